@@ -10,6 +10,7 @@ import (
 	"github.com/edulinq/autograder/internal/db"
 	"github.com/edulinq/autograder/internal/grader"
 	"github.com/edulinq/autograder/internal/log"
+	"github.com/edulinq/autograder/internal/model"
 	"github.com/edulinq/autograder/internal/util"
 )
 
@@ -17,12 +18,13 @@ var args struct {
 	config.ConfigArgs
 	Course         string `help:"ID of the course." arg:""`
 	Assignment     string `help:"ID of the assignment." arg:""`
-	Submission     string `help:"Path to submission directory." required:"" type:"existingdir"`
+	Submission     string `help:"Path to submission directory (required unless --ticket is used)." type:"existingdir"`
 	OutPath        string `help:"Option path to output a JSON grading result." type:"path"`
 	User           string `help:"User email for the submission." default:"testuser"`
 	Message        string `help:"Submission message." default:""`
 	AllowLate      bool   `help:"Allow this submission to be graded, even if it is late." default:"false"`
 	CheckRejection bool   `help:"Check if this submission should be rejected (bypassed by default)." default:"false"`
+	Ticket         string `help:"Instead of grading, report the status of the queued submission with this ticket ID."`
 }
 
 func main() {
@@ -40,6 +42,15 @@ func main() {
 
 	assignment := db.MustGetAssignment(args.Course, args.Assignment)
 
+	if args.Ticket != "" {
+		reportTicket(assignment, args.Ticket)
+		return
+	}
+
+	if args.Submission == "" {
+		log.Fatal("A submission directory (--submission) is required when not checking a ticket.", assignment)
+	}
+
 	gradeOptions := grader.GetDefaultGradeOptions()
 	gradeOptions.AllowLate = args.AllowLate
 	gradeOptions.CheckRejection = args.CheckRejection
@@ -70,3 +81,38 @@ func main() {
 
 	fmt.Println(result.Info.Report())
 }
+
+func reportTicket(assignment *model.Assignment, ticketID string) {
+	ticket, position, err := grader.GetQueuedTicket(ticketID)
+	if err != nil {
+		log.Fatal("Failed to get grading ticket.", assignment, log.NewAttr("ticket-id", ticketID), err)
+	}
+
+	if (ticket == nil) || (ticket.CourseID != assignment.GetCourse().GetID()) || (ticket.AssignmentID != assignment.GetID()) {
+		log.Fatal("Could not find grading ticket.", assignment, log.NewAttr("ticket-id", ticketID))
+	}
+
+	fmt.Printf("Ticket: %s\n", ticket.ID)
+	fmt.Printf("User: %s\n", ticket.User)
+	fmt.Printf("Status: %s\n", ticket.Status)
+	fmt.Printf("Queue Time: %s\n", ticket.QueueTime.SafeString())
+
+	if ticket.Status == model.GradingTicketStatusQueued {
+		fmt.Printf("Queue Position: %d\n", position)
+	}
+
+	if !ticket.IsDone() || (ticket.Result == nil) {
+		return
+	}
+
+	result := ticket.Result
+	if result.Rejected {
+		fmt.Printf("Result: Rejected (%s)\n", result.RejectReason)
+	} else if result.SoftError != "" {
+		fmt.Printf("Result: Soft Error (%s)\n", result.SoftError)
+	} else if result.GradingSuccess {
+		fmt.Printf("Result: Graded (submission %s)\n", result.SubmissionID)
+	} else {
+		fmt.Println("Result: Failed Internally")
+	}
+}
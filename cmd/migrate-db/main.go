@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/alecthomas/kong"
+
+	"github.com/edulinq/autograder/internal/config"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/log"
+	"github.com/edulinq/autograder/internal/procedures/migrate"
+)
+
+var args struct {
+	config.ConfigArgs
+
+	Source    string `help:"The type of database to migrate from." arg:"" enum:"disk,sqlite,postgres"`
+	Target    string `help:"The type of database to migrate to." arg:"" enum:"disk,sqlite,postgres"`
+	Overwrite bool   `help:"Clear the target database before migrating (otherwise it must be empty)." default:"false"`
+}
+
+func main() {
+	kong.Parse(&args,
+		kong.Description("Copy all data from one type of database into another, and verify the copy."+
+			" Both databases are configured with the normal config options (e.g., dirs.base and db.pg.uri)."+
+			" The server should not be running during a migration."),
+	)
+
+	err := config.HandleConfigArgs(args.ConfigArgs)
+	if err != nil {
+		log.Fatal("Could not load config options.", err)
+	}
+
+	if args.Source == args.Target {
+		log.Fatal("Source and target databases must be different types.", log.NewAttr("type", args.Source))
+	}
+
+	source, err := db.OpenBackend(args.Source)
+	if err != nil {
+		log.Fatal("Failed to open source database.", err, log.NewAttr("type", args.Source))
+	}
+	defer source.Close()
+
+	target, err := db.OpenBackend(args.Target)
+	if err != nil {
+		log.Fatal("Failed to open target database.", err, log.NewAttr("type", args.Target))
+	}
+	defer target.Close()
+
+	result, err := migrate.Migrate(source, target, migrate.MigrateOptions{Overwrite: args.Overwrite})
+
+	if result != nil {
+		fmt.Printf("%-20s %10s %10s  %s\n", "Type", "Source", "Target", "Checksum")
+		for _, dataType := range migrate.DATA_TYPES {
+			sourceSummary := result.Source[dataType]
+			targetSummary := result.Target[dataType]
+
+			checksum := "match"
+			if sourceSummary.Checksum != targetSummary.Checksum {
+				checksum = "MISMATCH"
+			}
+
+			fmt.Printf("%-20s %10d %10d  %s\n", dataType, sourceSummary.Count, targetSummary.Count, checksum)
+		}
+	}
+
+	if err != nil {
+		log.Fatal("Failed to migrate database.", err)
+	}
+
+	fmt.Printf("Successfully migrated '%s' database to '%s'.\n", args.Source, args.Target)
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/alecthomas/kong"
+
+	"github.com/edulinq/autograder/internal/config"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/log"
+	"github.com/edulinq/autograder/internal/procedures/users"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+var args struct {
+	config.ConfigArgs
+
+	Email string `help:"Email of the user to erase data for (the user does not need to still exist)." arg:""`
+}
+
+func main() {
+	kong.Parse(&args,
+		kong.Description("Erase all the data stored on the server that references a user."+
+			" Data used in course aggregates (submissions, groups, analysis, and metrics) is pseudonymized,"+
+			" and everything else (including the user) is removed. Be careful."),
+	)
+
+	err := config.HandleConfigArgs(args.ConfigArgs)
+	if err != nil {
+		log.Fatal("Could not load config options.", err)
+	}
+
+	db.MustOpen()
+	defer db.MustClose()
+
+	result, err := users.EraseUserData(args.Email)
+	if err != nil {
+		log.Fatal("Failed to erase user data.", err, log.NewUserAttr(args.Email))
+	}
+
+	fmt.Println(util.MustToJSONIndent(result))
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"os"
+
+	"github.com/alecthomas/kong"
+
+	"github.com/edulinq/autograder/internal/config"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/log"
+	"github.com/edulinq/autograder/internal/procedures/users"
+)
+
+var args struct {
+	config.ConfigArgs
+
+	Email string `help:"Email of the user to export data for (the user does not need to still exist)." arg:""`
+	Out   string `help:"Path to write the zip file to." short:"o" required:""`
+}
+
+func main() {
+	kong.Parse(&args,
+		kong.Description("Export all the data stored on the server that references a user as a zip file."),
+	)
+
+	err := config.HandleConfigArgs(args.ConfigArgs)
+	if err != nil {
+		log.Fatal("Could not load config options.", err)
+	}
+
+	db.MustOpen()
+	defer db.MustClose()
+
+	data, err := users.ExportUserData(args.Email)
+	if err != nil {
+		log.Fatal("Failed to export user data.", err, log.NewUserAttr(args.Email))
+	}
+
+	err = os.WriteFile(args.Out, data, 0644)
+	if err != nil {
+		log.Fatal("Failed to write user data.", err, log.NewAttr("path", args.Out))
+	}
+}
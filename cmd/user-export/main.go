@@ -0,0 +1,75 @@
+package main
+
+import (
+	"os"
+
+	"github.com/alecthomas/kong"
+
+	"github.com/edulinq/autograder/internal/config"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/log"
+	"github.com/edulinq/autograder/internal/procedures/users"
+)
+
+var args struct {
+	config.ConfigArgs
+
+	Course string `help:"Only export the users in this course (otherwise, export all server users)."`
+	Out    string `help:"Path to write the CSV to (defaults to stdout)." short:"o"`
+}
+
+func main() {
+	kong.Parse(&args,
+		kong.Description("Export the server (or a course's) roster as a CSV file."),
+	)
+
+	err := config.HandleConfigArgs(args.ConfigArgs)
+	if err != nil {
+		log.Fatal("Could not load config options.", err)
+	}
+
+	db.MustOpen()
+	defer db.MustClose()
+
+	writer := os.Stdout
+	if args.Out != "" {
+		writer, err = os.Create(args.Out)
+		if err != nil {
+			log.Fatal("Failed to create output file.", err, log.NewAttr("path", args.Out))
+		}
+		defer writer.Close()
+	}
+
+	if args.Course != "" {
+		course := db.MustGetCourse(args.Course)
+
+		courseUsers, err := db.GetCourseUsers(course)
+		if err != nil {
+			log.Fatal("Failed to get course users.", err, course)
+		}
+
+		err = users.WriteCourseUsersCSV(writer, course.GetID(), toSlice(courseUsers))
+		if err != nil {
+			log.Fatal("Failed to write course users.", err, course)
+		}
+	} else {
+		serverUsers, err := db.GetServerUsers()
+		if err != nil {
+			log.Fatal("Failed to get server users.", err)
+		}
+
+		err = users.WriteServerUsersCSV(writer, toSlice(serverUsers))
+		if err != nil {
+			log.Fatal("Failed to write server users.", err)
+		}
+	}
+}
+
+func toSlice[T any](usersMap map[string]*T) []*T {
+	values := make([]*T, 0, len(usersMap))
+	for _, value := range usersMap {
+		values = append(values, value)
+	}
+
+	return values
+}
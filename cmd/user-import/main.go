@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/alecthomas/kong"
+
+	"github.com/edulinq/autograder/internal/config"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/log"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/procedures/users"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+var args struct {
+	config.ConfigArgs
+
+	Path string `help:"Path to a CSV file of users (the first row must be a header)." arg:"" type:"existingfile"`
+
+	users.CSVImportOptions
+
+	SendEmail bool `help:"Send an email to users if important changes (like a new password) were made." default:"false"`
+	Commit    bool `help:"Actually write out the users and send emails. By default, only state what would happen (a dry run)." default:"false"`
+}
+
+func main() {
+	kong.Parse(&args,
+		kong.Description("Upsert (update or insert) users from a CSV file. Runs as a dry run unless --commit is specified."),
+	)
+
+	err := config.HandleConfigArgs(args.ConfigArgs)
+	if err != nil {
+		log.Fatal("Could not load config options.", err)
+	}
+
+	db.MustOpen()
+	defer db.MustClose()
+
+	file, err := os.Open(args.Path)
+	if err != nil {
+		log.Fatal("Failed to open CSV file.", err, log.NewAttr("path", args.Path))
+	}
+	defer file.Close()
+
+	rawUsers, err := users.ReadUsersCSV(file, args.CSVImportOptions)
+	if err != nil {
+		log.Fatal("Failed to read users from CSV file.", err, log.NewAttr("path", args.Path))
+	}
+
+	options := users.UpsertUsersOptions{
+		RawUsers:          rawUsers,
+		SendEmails:        args.SendEmail,
+		DryRun:            !args.Commit,
+		ContextServerRole: model.ServerRoleRoot,
+	}
+
+	results := users.UpsertUsers(options)
+
+	fmt.Println(util.MustToJSONIndent(results))
+
+	for _, result := range results {
+		if result.HasErrors() {
+			os.Exit(1)
+		}
+	}
+}
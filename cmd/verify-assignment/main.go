@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/alecthomas/kong"
+
+	"github.com/edulinq/autograder/internal/config"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/grader"
+	"github.com/edulinq/autograder/internal/log"
+	"github.com/edulinq/autograder/internal/model"
+)
+
+var args struct {
+	config.ConfigArgs
+	Course     string `help:"ID of the course." arg:""`
+	Assignment string `help:"ID of the assignment (defaults to all of the course's assignments)." arg:"" optional:""`
+
+	grader.VerifyOptions
+}
+
+func main() {
+	kong.Parse(&args,
+		kong.Description("Grade an assignment's (or all of a course's assignments') test submissions and compare them against their expected results."+
+			" Exits with a non-zero status if any test submission does not match its expected result."),
+	)
+
+	err := config.HandleConfigArgs(args.ConfigArgs)
+	if err != nil {
+		log.Fatal("Could not load config options.", err)
+	}
+
+	db.MustOpen()
+	defer db.MustClose()
+
+	course := db.MustGetCourse(args.Course)
+
+	assignments := course.GetSortedAssignments()
+	if args.Assignment != "" {
+		assignments = []*model.Assignment{db.MustGetAssignment(args.Course, args.Assignment)}
+	}
+
+	result, err := grader.VerifyAssignments(context.Background(), assignments, args.VerifyOptions)
+	if err != nil {
+		log.Fatal("Failed to verify assignments.", err, course)
+	}
+
+	fmt.Printf("%-20s %-40s %s\n", "Assignment", "Test Submission", "Result")
+	for _, testResult := range result.Results {
+		status := "pass"
+		if !testResult.Passed {
+			status = "FAIL"
+		}
+
+		fmt.Printf("%-20s %-40s %s\n", testResult.AssignmentID, testResult.ID, status)
+	}
+
+	for _, testResult := range result.Results {
+		if testResult.Passed {
+			continue
+		}
+
+		fmt.Printf("\n--- %s: %s ---\n", testResult.AssignmentID, testResult.ID)
+		fmt.Println(testResult.Message)
+
+		if testResult.Diff != "" {
+			fmt.Print(testResult.Diff)
+		}
+	}
+
+	fmt.Printf("\nPassed: %d, Failed: %d.\n", result.NumPassed, result.NumFailed)
+
+	if !result.Passed {
+		db.MustClose()
+		os.Exit(1)
+	}
+}
@@ -131,7 +131,7 @@ func (this *dolosEngine) ComputeFileSimilarity(paths [2]string, templatePath str
 		arguments = append(arguments, "--ignore", templateFilename)
 	}
 
-	stdout, stderr, _, _, err := docker.RunContainer(ctx, this, getImageName(), mounts, arguments, NAME, MAX_RUNTIME_SECS)
+	stdout, stderr, _, _, _, err := docker.RunContainer(ctx, this, getImageName(), mounts, arguments, NAME, MAX_RUNTIME_SECS, nil)
 	if err != nil {
 		log.Debug("Failed to run Dolos container.", err, log.NewAttr("stdout", stdout), log.NewAttr("stderr", stderr))
 		return nil, fmt.Errorf("Failed to run Dolos container: '%w'.", err)
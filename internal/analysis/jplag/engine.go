@@ -145,7 +145,7 @@ func (this *JPlagEngine) ComputeFileSimilarity(paths [2]string, templatePath str
 		arguments = append(arguments, "--base-code", "template")
 	}
 
-	stdout, stderr, _, _, err := docker.RunContainer(ctx, this, getImageName(), mounts, arguments, NAME, MAX_RUNTIME_SECS)
+	stdout, stderr, _, _, _, err := docker.RunContainer(ctx, this, getImageName(), mounts, arguments, NAME, MAX_RUNTIME_SECS, nil)
 	if err != nil {
 		log.Debug("Failed to run JPlag container.", err, log.NewAttr("stdout", stdout), log.NewAttr("stderr", stderr))
 		return nil, fmt.Errorf("Failed to run JPlag container: '%w'.", err)
@@ -0,0 +1,64 @@
+package audit
+
+import (
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/common"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+)
+
+type QueryRequest struct {
+	core.APIRequestUserContext
+	core.MinServerRoleUser
+
+	model.AuditQuery
+}
+
+type QueryResponse struct {
+	Records []*model.AuditRecord `json:"records"`
+}
+
+// Query the audit trail of state-changing actions.
+// Server admins can query all records, while course admins can query the records for their course.
+func HandleQuery(request *QueryRequest) (*QueryResponse, *core.APIError) {
+	apiErr := checkQueryPermissions(request)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	if request.Action != model.AuditActionUnknown {
+		err := request.Action.Validate()
+		if err != nil {
+			return nil, core.NewBadRequestError("-703", request, "Invalid audit action.").Err(err)
+		}
+	}
+
+	records, err := db.GetAuditRecords(request.AuditQuery)
+	if err != nil {
+		return nil, core.NewInternalError("-704", request, "Failed to query audit records.").Err(err)
+	}
+
+	return &QueryResponse{records}, nil
+}
+
+func checkQueryPermissions(request *QueryRequest) *core.APIError {
+	if request.ServerUser.Role >= model.ServerRoleAdmin {
+		return nil
+	}
+
+	if request.CourseID == "" {
+		return core.NewPermissionsError("-701", request, model.ServerRoleAdmin, request.ServerUser.Role,
+			"Only server admins can query the audit trail without a course.")
+	}
+
+	courseID, _ := common.ValidateID(request.CourseID)
+	request.CourseID = courseID
+
+	role := request.ServerUser.GetCourseRole(courseID)
+	if role < model.CourseRoleAdmin {
+		return core.NewPermissionsError("-702", request, model.CourseRoleAdmin, role,
+			"Only course admins can query a course's audit trail.").Course(courseID)
+	}
+
+	return nil
+}
@@ -0,0 +1,100 @@
+package audit
+
+import (
+	"testing"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestQuery(test *testing.T) {
+	defer db.ResetForTesting()
+
+	addTestAuditRecords(test)
+
+	testCases := []struct {
+		email         string
+		query         model.AuditQuery
+		expectedCount int
+		locator       string
+	}{
+		// Server admins can query anything.
+		{"server-admin@test.edulinq.org", model.AuditQuery{}, 3, ""},
+		{"server-admin@test.edulinq.org", model.AuditQuery{CourseID: "course101"}, 2, ""},
+		{"server-admin@test.edulinq.org", model.AuditQuery{Action: model.AuditActionCourseDrop}, 1, ""},
+		{"server-admin@test.edulinq.org", model.AuditQuery{Target: "course-student@test.edulinq.org"}, 2, ""},
+
+		// Course admins can query their own course.
+		{"course-admin@test.edulinq.org", model.AuditQuery{CourseID: "course101"}, 2, ""},
+		{"course-admin@test.edulinq.org", model.AuditQuery{CourseID: "course101", Actor: "course-grader@test.edulinq.org"}, 1, ""},
+		{"course-admin@test.edulinq.org", model.AuditQuery{}, 0, "-701"},
+		{"course-admin@test.edulinq.org", model.AuditQuery{CourseID: "ZZZ"}, 0, "-702"},
+
+		// Graders cannot query.
+		{"course-grader@test.edulinq.org", model.AuditQuery{CourseID: "course101"}, 0, "-702"},
+
+		// Bad action.
+		{"server-admin@test.edulinq.org", model.AuditQuery{Action: "ZZZ"}, 0, "-703"},
+	}
+
+	for i, testCase := range testCases {
+		var fields map[string]any
+		util.MustJSONFromString(util.MustToJSON(testCase.query), &fields)
+
+		response := core.SendTestAPIRequestFull(test, "audit/query", fields, nil, testCase.email)
+		if !response.Success {
+			if testCase.locator != response.Locator {
+				test.Errorf("Case %d: Incorrect error returned. Expected: '%s', Actual: '%s'.",
+					i, testCase.locator, response.Locator)
+			}
+
+			continue
+		}
+
+		if testCase.locator != "" {
+			test.Errorf("Case %d: Did not get an expected error. Expected: '%s'", i, testCase.locator)
+			continue
+		}
+
+		var responseContent QueryResponse
+		util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent)
+
+		if testCase.expectedCount != len(responseContent.Records) {
+			test.Errorf("Case %d: Unexpected number of records. Expected: %d, Actual: %d.",
+				i, testCase.expectedCount, len(responseContent.Records))
+			continue
+		}
+	}
+}
+
+func addTestAuditRecords(test *testing.T) {
+	records := []*model.AuditRecord{
+		&model.AuditRecord{
+			Action: model.AuditActionUserUpsert,
+			Actor:  "server-admin@test.edulinq.org",
+			Target: "course-student@test.edulinq.org",
+		},
+		&model.AuditRecord{
+			Action:       model.AuditActionSubmissionRemove,
+			Actor:        "course-grader@test.edulinq.org",
+			CourseID:     "course101",
+			AssignmentID: "hw0",
+			Target:       "course-student@test.edulinq.org",
+		},
+		&model.AuditRecord{
+			Action:   model.AuditActionCourseDrop,
+			Actor:    "course-admin@test.edulinq.org",
+			CourseID: "course101",
+			Target:   "course-other@test.edulinq.org",
+		},
+	}
+
+	for i, record := range records {
+		err := db.AppendAuditRecord(record)
+		if err != nil {
+			test.Fatalf("Failed to add audit record %d: '%v'.", i, err)
+		}
+	}
+}
@@ -0,0 +1,16 @@
+package audit
+
+// All the API endpoints handled by this package.
+
+import (
+	"github.com/edulinq/autograder/internal/api/core"
+)
+
+var routes []core.Route = []core.Route{
+	core.MustNewAPIRoute(`audit/query`, HandleQuery),
+	core.MustNewAPIRoute(`audit/verify`, HandleVerify),
+}
+
+func GetRoutes() *[]core.Route {
+	return &routes
+}
@@ -0,0 +1,46 @@
+package audit
+
+import (
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/log"
+	"github.com/edulinq/autograder/internal/model"
+)
+
+type VerifyRequest struct {
+	core.APIRequestUserContext
+	core.MinServerRoleAdmin
+}
+
+type VerifyResponse struct {
+	Valid bool `json:"valid"`
+
+	// The number of records checked.
+	Count int `json:"count"`
+
+	// A description of the first problem found (if the trail is not valid).
+	Message string `json:"message"`
+}
+
+// Check that the audit trail has not been tampered with.
+func HandleVerify(request *VerifyRequest) (*VerifyResponse, *core.APIError) {
+	records, err := db.GetAuditRecords(model.AuditQuery{})
+	if err != nil {
+		return nil, core.NewInternalError("-705", request, "Failed to get audit records.").Err(err)
+	}
+
+	response := VerifyResponse{
+		Valid: true,
+		Count: len(records),
+	}
+
+	err = model.VerifyAuditChain(records)
+	if err != nil {
+		log.Error("Audit trail failed verification.", err, log.NewUserAttr(request.UserEmail))
+
+		response.Valid = false
+		response.Message = err.Error()
+	}
+
+	return &response, nil
+}
@@ -0,0 +1,39 @@
+package audit
+
+import (
+	"testing"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestVerify(test *testing.T) {
+	defer db.ResetForTesting()
+
+	addTestAuditRecords(test)
+
+	response := core.SendTestAPIRequestFull(test, "audit/verify", nil, nil, "server-admin@test.edulinq.org")
+	if !response.Success {
+		test.Fatalf("Response is not a success when it should be: '%v'.", response)
+	}
+
+	var responseContent VerifyResponse
+	util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent)
+
+	expected := VerifyResponse{Valid: true, Count: 3}
+	if expected != responseContent {
+		test.Fatalf("Unexpected response. Expected: '%+v', Actual: '%+v'.", expected, responseContent)
+	}
+
+	// Only server admins can verify.
+	response = core.SendTestAPIRequestFull(test, "audit/verify", nil, nil, "course-owner@test.edulinq.org")
+	if response.Success {
+		test.Fatalf("Response is a success when it should not be.")
+	}
+
+	expectedLocator := "-041"
+	if response.Locator != expectedLocator {
+		test.Fatalf("Incorrect error returned. Expected: '%s', Actual: '%s'.", expectedLocator, response.Locator)
+	}
+}
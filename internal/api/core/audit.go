@@ -0,0 +1,74 @@
+package core
+
+import (
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/log"
+	"github.com/edulinq/autograder/internal/model"
+)
+
+// Add a record of a state-changing action to the audit trail.
+// The actor and request ID will be filled in from this request.
+// The action has already happened, so failures are logged instead of returned.
+func (this *APIRequestUserContext) Audit(record *model.AuditRecord) {
+	record.Actor = this.UserEmail
+	record.RequestID = this.RequestID
+
+	err := db.AppendAuditRecord(record)
+	if err != nil {
+		log.Error("Failed to add audit record.", err,
+			log.NewAttr("request-id", this.RequestID), log.NewAttr("action", record.Action),
+			log.NewCourseAttr(record.CourseID), log.NewUserAttr(this.UserEmail))
+	}
+}
+
+// See APIRequestUserContext.Audit().
+// The course will also be filled in (if not already set).
+func (this *APIRequestCourseUserContext) Audit(record *model.AuditRecord) {
+	if record.CourseID == "" {
+		record.CourseID = this.CourseID
+	}
+
+	this.APIRequestUserContext.Audit(record)
+}
+
+// See APIRequestCourseUserContext.Audit().
+// The assignment will also be filled in (if not already set).
+func (this *APIRequestAssignmentContext) Audit(record *model.AuditRecord) {
+	if record.AssignmentID == "" {
+		record.AssignmentID = this.AssignmentID
+	}
+
+	this.APIRequestCourseUserContext.Audit(record)
+}
+
+// Create audit records for the successful results of a user operation (e.g., an upsert or enrollment).
+// The given users should be all the server users from before the operation,
+// and the summaries will be limited to the given course (if not empty).
+func NewUserOpAuditRecords(action model.AuditAction, courseID string, before map[string]*model.ServerUser, results []*model.UserOpResult) []*model.AuditRecord {
+	records := make([]*model.AuditRecord, 0, len(results))
+
+	for _, result := range results {
+		if result.HasErrors() {
+			continue
+		}
+
+		changed := result.Added || result.Modified || result.Removed || (len(result.Enrolled) > 0) || (len(result.Dropped) > 0)
+		if !changed {
+			continue
+		}
+
+		after, err := db.GetServerUser(result.Email)
+		if err != nil {
+			log.Error("Failed to get user for audit record.", err, log.NewUserAttr(result.Email))
+		}
+
+		record := model.NewAuditRecord(action, result.Email)
+		record.CourseID = courseID
+		record.Before = before[result.Email].GetAuditSummary(courseID)
+		record.After = after.GetAuditSummary(courseID)
+
+		records = append(records, record)
+	}
+
+	return records
+}
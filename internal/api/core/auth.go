@@ -1,6 +1,8 @@
 package core
 
 import (
+	"strings"
+
 	"github.com/edulinq/autograder/internal/db"
 	"github.com/edulinq/autograder/internal/model"
 )
@@ -13,23 +15,43 @@ func (this *APIRequestUserContext) Auth() (*model.ServerUser, *APIError) {
 		return nil, NewAuthError("-051", this, "Root is not allowed to authenticate.")
 	}
 
+	throttles, apiErr := this.CheckLoginThrottles()
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
 	user, err := db.GetServerUser(this.UserEmail)
 	if err != nil {
 		return nil, NewAuthError("-012", this, "Cannot Get User").Err(err)
 	}
 
 	if user == nil {
+		this.RecordLoginFailure(false)
 		return nil, NewAuthError("-013", this, "Unknown User")
 	}
 
-	auth, err := user.Auth(this.UserPass)
+	token, err := user.AuthToken(this.UserPass)
 	if err != nil {
 		return nil, NewInternalError("-037", this.Endpoint, "User auth failed.").Err(err)
 	}
 
-	if !auth {
+	if token == nil {
+		this.RecordLoginFailure(true)
 		return nil, NewAuthError("-014", this, "Bad Password")
 	}
 
+	endpoint := strings.Trim(strings.TrimPrefix(this.Endpoint, CURRENT_PREFIX), "/")
+	if !token.AllowsEndpoint(endpoint) {
+		return nil, NewAuthError("-057", this, "Token is not allowed to access this endpoint.").
+			Add("token-id", token.ID)
+	}
+
+	apiErr = this.checkTwoFactor(user, token, endpoint)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	this.RecordLoginSuccess(throttles)
+
 	return user, nil
 }
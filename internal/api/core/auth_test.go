@@ -3,6 +3,9 @@ package core
 import (
 	"testing"
 
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/timestamp"
 	"github.com/edulinq/autograder/internal/util"
 )
 
@@ -56,3 +59,99 @@ func TestAuth(test *testing.T) {
 		}
 	}
 }
+
+func TestAuthTokenScopes(test *testing.T) {
+	defer db.ResetForTesting()
+
+	type baseAPIRequest struct {
+		APIRequestUserContext
+		MinCourseRoleOther
+	}
+
+	email := "course-student@test.edulinq.org"
+
+	testCases := []struct {
+		scopes   []string
+		endpoint string
+		locator  string
+	}{
+		{nil, "courses/assignments/submit", ""},
+		{[]string{"courses/assignments/submit"}, "courses/assignments/submit", ""},
+		{[]string{"courses/assignments/submissions/*"}, "courses/assignments/submissions/fetch/user/peek", ""},
+		{[]string{"read-only"}, "courses/assignments/get", ""},
+
+		{[]string{"courses/assignments/submit"}, "users/tokens/create", "-057"},
+		{[]string{"courses/assignments/submissions/*"}, "courses/assignments/submit", "-057"},
+		{[]string{"read-only"}, "courses/assignments/submit", "-057"},
+	}
+
+	for i, testCase := range testCases {
+		db.ResetForTesting()
+
+		user := db.MustGetServerUser(email)
+
+		token, cleartext, err := user.CreateRandomToken("scoped", model.TokenSourceUser)
+		if err != nil {
+			test.Errorf("Case %d: Failed to create token: '%v'.", i, err)
+			continue
+		}
+
+		token.Scopes = testCase.scopes
+		db.MustUpsertUser(user)
+
+		request := baseAPIRequest{
+			APIRequestUserContext: APIRequestUserContext{
+				UserEmail: email,
+				UserPass:  util.Sha256HexFromString(cleartext),
+			},
+		}
+
+		apiErr := ValidateAPIRequest(nil, &request, MakeFullAPIPath(testCase.endpoint))
+
+		if (apiErr == nil) && (testCase.locator != "") {
+			test.Errorf("Case %d: Expecting error '%s', but got no error.", i, testCase.locator)
+		} else if (apiErr != nil) && (testCase.locator == "") {
+			test.Errorf("Case %d: Expecting no error, but got '%s': '%v'.", i, apiErr.Locator, apiErr)
+		} else if (apiErr != nil) && (testCase.locator != "") && (apiErr.Locator != testCase.locator) {
+			test.Errorf("Case %d: Got a different error than expected. Expected: '%s', actual: '%s' -- '%v'.",
+				i, testCase.locator, apiErr.Locator, apiErr)
+		}
+	}
+}
+
+func TestAuthTokenExpired(test *testing.T) {
+	defer db.ResetForTesting()
+
+	type baseAPIRequest struct {
+		APIRequestUserContext
+		MinCourseRoleOther
+	}
+
+	email := "course-student@test.edulinq.org"
+	user := db.MustGetServerUser(email)
+
+	token, cleartext, err := user.CreateRandomToken("expired", model.TokenSourceUser)
+	if err != nil {
+		test.Fatalf("Failed to create token: '%v'.", err)
+	}
+
+	expiration := timestamp.Now() - 1
+	token.ExpirationTime = &expiration
+	db.MustUpsertUser(user)
+
+	request := baseAPIRequest{
+		APIRequestUserContext: APIRequestUserContext{
+			UserEmail: email,
+			UserPass:  util.Sha256HexFromString(cleartext),
+		},
+	}
+
+	apiErr := ValidateAPIRequest(nil, &request, "")
+	if apiErr == nil {
+		test.Fatalf("Expired token was accepted.")
+	}
+
+	if apiErr.Locator != "-014" {
+		test.Fatalf("Got a different error than expected. Expected: '-014', actual: '%s' -- '%v'.", apiErr.Locator, apiErr)
+	}
+}
@@ -11,6 +11,8 @@ type AssignmentInfo struct {
 	ID        string               `json:"id"`
 	Name      string               `json:"name"`
 	DueDate   *timestamp.Timestamp `json:"due-date,omitempty"`
+	OpenDate  *timestamp.Timestamp `json:"open-date,omitempty"`
+	CloseDate *timestamp.Timestamp `json:"close-date,omitempty"`
 	MaxPoints float64              `json:"max-points,omitempty"`
 }
 
@@ -25,6 +27,8 @@ func NewAssignmentInfo(assignment *model.Assignment) *AssignmentInfo {
 		ID:        assignment.ID,
 		Name:      assignment.Name,
 		DueDate:   assignment.DueDate,
+		OpenDate:  assignment.GetOpenDate(),
+		CloseDate: assignment.GetCloseDate(),
 		MaxPoints: assignment.MaxPoints,
 	}
 }
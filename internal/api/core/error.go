@@ -53,6 +53,10 @@ type APIError struct {
 	AssignmentID string
 	UserEmail    string
 
+	// Only set when the request was made while viewing as another user.
+	ViewAs       string
+	Impersonator string
+
 	AdditionalDetails map[string]any
 }
 
@@ -92,6 +96,10 @@ func (this *APIError) Log() {
 		args = append(args, log.NewUserAttr(this.UserEmail))
 	}
 
+	if this.Impersonator != "" {
+		args = append(args, log.NewAttr("view-as", this.ViewAs), log.NewAttr("impersonator", this.Impersonator))
+	}
+
 	log.LogToLevel(this.LogLevel, "API Error", args...)
 }
 
@@ -156,6 +164,8 @@ func (this *APIError) ToResponse() *APIResponse {
 		Success:        (this.HTTPStatus == HTTP_STATUS_GOOD),
 		Message:        this.ResponseText,
 		Content:        nil,
+		ViewAs:         this.ViewAs,
+		Impersonator:   this.Impersonator,
 	}
 }
 
@@ -314,6 +324,11 @@ func applyAPIRequestUserContext(apiError *APIError, context *APIRequestUserConte
 
 func applyAPIRequestCourseUserContext(apiError *APIError, context *APIRequestCourseUserContext) {
 	apiError.CourseID = context.CourseID
+
+	if context.IsImpersonating() {
+		apiError.ViewAs = context.UserEmail
+		apiError.Impersonator = context.Impersonator
+	}
 }
 
 func applyAPIRequestAssignmentContext(apiError *APIError, context *APIRequestAssignmentContext) {
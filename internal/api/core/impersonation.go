@@ -0,0 +1,66 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+)
+
+// Is this request being made as another user (see APIRequestCourseUserContext.ViewAs).
+func (this *APIRequestCourseUserContext) IsImpersonating() bool {
+	return this.Impersonator != ""
+}
+
+// Switch this request over to the user being viewed as.
+// This should only be called after the requesting user has been authenticated and loaded as a course user.
+// Impersonation is limited to course admins (or above), read-only endpoints, and users with a lower course role.
+func (this *APIRequestCourseUserContext) impersonate() *APIError {
+	if this.ViewAs == "" {
+		return nil
+	}
+
+	if !model.IsReadOnlyEndpoint(strings.TrimPrefix(this.Endpoint, CURRENT_PREFIX)) {
+		return NewBadRequestError("-064", this, "Viewing as another user is only allowed for read-only endpoints.").
+			Add("view-as", this.ViewAs)
+	}
+
+	if this.User.Role < model.CourseRoleAdmin {
+		return NewPermissionsError("-065", this, model.CourseRoleAdmin, this.User.Role,
+			"Only course admins can view as another user.").Add("view-as", this.ViewAs)
+	}
+
+	targetServerUser, err := db.GetServerUser(this.ViewAs)
+	if err != nil {
+		return NewInternalError("-066", this, "Failed to get the user to view as.").Err(err).Add("view-as", this.ViewAs)
+	}
+
+	var targetUser *model.CourseUser = nil
+	if targetServerUser != nil {
+		targetUser, err = targetServerUser.ToCourseUser(this.Course.ID, false)
+		if err != nil {
+			return NewInternalError("-067", this, "Unable to convert the user to view as into a course user.").
+				Err(err).Add("view-as", this.ViewAs)
+		}
+	}
+
+	if targetUser == nil {
+		return NewBadRequestError("-068", this,
+			fmt.Sprintf("Could not find the user to view as ('%s') in course '%s'.", this.ViewAs, this.CourseID)).
+			Add("view-as", this.ViewAs)
+	}
+
+	if targetUser.Role >= this.User.Role {
+		return NewPermissionsError("-069", this, this.User.Role, targetUser.Role,
+			"Cannot view as a user with an equal or higher course role.").Add("view-as", this.ViewAs)
+	}
+
+	this.Impersonator = this.UserEmail
+
+	this.UserEmail = targetServerUser.Email
+	this.ServerUser = targetServerUser
+	this.User = targetUser
+
+	return nil
+}
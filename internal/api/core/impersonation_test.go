@@ -0,0 +1,128 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+type impersonationTestRequest struct {
+	APIRequestCourseUserContext
+	MinCourseRoleStudent
+}
+
+func TestImpersonation(test *testing.T) {
+	defer db.ResetForTesting()
+
+	readEndpoint := MakeFullAPIPath("courses/assignments/submissions/fetch/user/peek")
+	writeEndpoint := MakeFullAPIPath("courses/assignments/submissions/submit")
+
+	testCases := []struct {
+		email        string
+		viewAs       string
+		endpoint     string
+		expectedRole model.CourseUserRole
+		locator      string
+	}{
+		// No impersonation.
+		{"course-student", "", writeEndpoint, model.CourseRoleStudent, ""},
+		{"course-admin", "", writeEndpoint, model.CourseRoleAdmin, ""},
+
+		// Valid impersonation.
+		{"course-admin", "course-student@test.edulinq.org", readEndpoint, model.CourseRoleStudent, ""},
+		{"course-admin", "course-grader@test.edulinq.org", readEndpoint, model.CourseRoleGrader, ""},
+		{"course-owner", "course-admin@test.edulinq.org", readEndpoint, model.CourseRoleAdmin, ""},
+		{"server-admin", "course-student@test.edulinq.org", readEndpoint, model.CourseRoleStudent, ""},
+
+		// Write endpoints.
+		{"course-admin", "course-student@test.edulinq.org", writeEndpoint, model.CourseRoleUnknown, "-064"},
+		{"course-admin", "course-student@test.edulinq.org", MakeFullAPIPath("courses/users/drop"), model.CourseRoleUnknown, "-064"},
+		{"course-admin", "course-student@test.edulinq.org", MakeFullAPIPath("courses/upsert/zip"), model.CourseRoleUnknown, "-064"},
+		{"course-admin", "course-student@test.edulinq.org", MakeFullAPIPath("lms/upload/scores"), model.CourseRoleUnknown, "-064"},
+		{"course-admin", "course-grader@test.edulinq.org", MakeFullAPIPath("lms/upload/scores"), model.CourseRoleUnknown, "-064"},
+		{"course-admin", "course-student@test.edulinq.org", MakeFullAPIPath("courses/lms/scores/upload"), model.CourseRoleUnknown, "-064"},
+
+		// Not a course admin.
+		{"course-grader", "course-student@test.edulinq.org", readEndpoint, model.CourseRoleUnknown, "-065"},
+		{"course-student", "course-other@test.edulinq.org", readEndpoint, model.CourseRoleUnknown, "-065"},
+
+		// Unknown users.
+		{"course-admin", "ZZZ", readEndpoint, model.CourseRoleUnknown, "-068"},
+		{"course-admin", "server-user@test.edulinq.org", readEndpoint, model.CourseRoleUnknown, "-068"},
+
+		// Equal or higher roles.
+		{"course-admin", "course-admin@test.edulinq.org", readEndpoint, model.CourseRoleUnknown, "-069"},
+		{"course-admin", "course-owner@test.edulinq.org", readEndpoint, model.CourseRoleUnknown, "-069"},
+		{"server-admin", "course-owner@test.edulinq.org", readEndpoint, model.CourseRoleUnknown, "-069"},
+	}
+
+	for i, testCase := range testCases {
+		email := testCase.email + "@test.edulinq.org"
+
+		request := impersonationTestRequest{
+			APIRequestCourseUserContext: APIRequestCourseUserContext{
+				APIRequestUserContext: APIRequestUserContext{
+					UserEmail: email,
+					UserPass:  util.Sha256HexFromString(testCase.email),
+				},
+				CourseID: "course101",
+				ViewAs:   testCase.viewAs,
+			},
+		}
+
+		apiErr := ValidateAPIRequest(nil, &request, testCase.endpoint)
+		if apiErr != nil {
+			if testCase.locator != apiErr.Locator {
+				test.Errorf("Case %d: Incorrect error returned. Expected: '%s', Actual: '%s' -- '%v'.",
+					i, testCase.locator, apiErr.Locator, apiErr)
+			}
+
+			continue
+		}
+
+		if testCase.locator != "" {
+			test.Errorf("Case %d: Did not get an expected error. Expected: '%s'.", i, testCase.locator)
+			continue
+		}
+
+		if testCase.expectedRole != request.User.Role {
+			test.Errorf("Case %d: Unexpected role. Expected: '%s', Actual: '%s'.", i, testCase.expectedRole, request.User.Role)
+			continue
+		}
+
+		if testCase.viewAs == "" {
+			if request.IsImpersonating() || (request.UserEmail != email) {
+				test.Errorf("Case %d: Request should not be impersonating: '%s' (%s).", i, request.UserEmail, request.Impersonator)
+			}
+
+			continue
+		}
+
+		if !request.IsImpersonating() || (request.Impersonator != email) {
+			test.Errorf("Case %d: Unexpected impersonator. Expected: '%s', Actual: '%s'.", i, email, request.Impersonator)
+			continue
+		}
+
+		if (request.UserEmail != testCase.viewAs) || (request.ServerUser.Email != testCase.viewAs) || (request.User.Email != testCase.viewAs) {
+			test.Errorf("Case %d: Request is not running as the impersonated user. Expected: '%s', Actual: '%s'.",
+				i, testCase.viewAs, request.UserEmail)
+			continue
+		}
+
+		viewAs, impersonator := getImpersonationInfo(&request)
+		if (viewAs != testCase.viewAs) || (impersonator != email) {
+			test.Errorf("Case %d: Unexpected impersonation info. Expected: ('%s', '%s'), Actual: ('%s', '%s').",
+				i, testCase.viewAs, email, viewAs, impersonator)
+			continue
+		}
+
+		response := NewAPIResponse(&request, nil)
+		if (response.ViewAs != testCase.viewAs) || (response.Impersonator != email) {
+			test.Errorf("Case %d: Response is not flagged. Expected: ('%s', '%s'), Actual: ('%s', '%s').",
+				i, testCase.viewAs, email, response.ViewAs, response.Impersonator)
+			continue
+		}
+	}
+}
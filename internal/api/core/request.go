@@ -53,6 +53,10 @@ type APIRequestUserContext struct {
 	UserPass      string `json:"user-pass" required:""`
 	RootUserNonce string `json:"root-user-nonce,omitempty"`
 
+	// The current two-factor (TOTP) code of the user making this request.
+	// Only required for users with two-factor auth enabled (unless they are using a token created with two-factor auth).
+	TwoFactorCode string `json:"two-factor-code,omitempty"`
+
 	ServerUser *model.ServerUser `json:"-"`
 }
 
@@ -63,6 +67,14 @@ type APIRequestCourseUserContext struct {
 	// The ID of the course to make this request to.
 	CourseID string `json:"course-id" required:""`
 
+	// The email of another user in this course to make this (read-only) request as.
+	// Only available to course admins (see impersonate()).
+	ViewAs string `json:"view-as,omitempty"`
+
+	// When viewing as another user, the email of the user that actually made this request.
+	// The rest of the context (e.g., UserEmail and User) will describe the user being viewed as.
+	Impersonator string `json:"-"`
+
 	Course *model.Course     `json:"-"`
 	User   *model.CourseUser `json:"-"`
 }
@@ -188,6 +200,11 @@ func (this *APIRequestCourseUserContext) Validate(httpRequest *http.Request, req
 		return NewBadRequestError("-040", this, fmt.Sprintf("User '%s' is not enolled in course '%s'.", this.UserEmail, this.CourseID))
 	}
 
+	apiErr = this.impersonate()
+	if apiErr != nil {
+		return apiErr
+	}
+
 	minRole, foundRole := getMaxCourseRole(request)
 	if !foundRole {
 		return NewInternalError("-019", this, "No role found for request. All course-based request structs require a minimum role.")
@@ -254,9 +271,16 @@ func getLogAttributesFromAPIRequest(apiRequest ValidAPIRequest) []any {
 	generalData["endpoint"] = typedAPIRequest.Endpoint
 	generalData["sender"] = typedAPIRequest.Sender
 
+	// Flag any impersonation.
+	_, impersonator := getImpersonationInfo(apiRequest)
+	if impersonator != "" {
+		generalData["impersonator"] = impersonator
+	}
+
 	// Remove passwords.
 	delete(generalData, "user-pass")
 	delete(generalData, "new-pass")
+	delete(generalData, "two-factor-code")
 	cleanRawUsers(generalData)
 
 	// Swap over standard loggable keys.
@@ -427,6 +451,23 @@ func getRequestIDAndTimestamp(request ValidAPIRequest) (string, timestamp.Timest
 	return id, startTime
 }
 
+// Reflexively get the user being viewed as and the impersonator from a request.
+// Both will be empty if the request is not impersonating another user.
+func getImpersonationInfo(request ValidAPIRequest) (string, string) {
+	if request == nil {
+		return "", ""
+	}
+
+	reflectValue := reflect.ValueOf(request).Elem()
+
+	impersonatorValue := reflectValue.FieldByName("Impersonator")
+	if !impersonatorValue.IsValid() || (impersonatorValue.String() == "") {
+		return "", ""
+	}
+
+	return reflectValue.FieldByName("UserEmail").String(), impersonatorValue.String()
+}
+
 // Get the endpoint, sender, userEmail, courseID, assignmentID, and locator
 // from a ValidAPIRequest and an APIError, both of which may be nil.
 func getRequestInfo(request ValidAPIRequest, apiError *APIError) (string, string, string, string, string, string) {
@@ -42,6 +42,7 @@ type TargetServerUser struct {
 // A user's email must be specified, but no error is generated if the user is not found.
 // The existence of this type in a struct also indicates that the request is at least a APIRequestCourseUserContext.
 // Server users (with adequate permissions) can also be returned.
+// Graders limited to sections can only target users in their sections.
 type TargetCourseUser struct {
 	Found bool
 	Email string
@@ -301,6 +302,15 @@ func checkRequestTargetCourseUser(endpoint string, apiRequest any, fieldIndex in
 		}
 	}
 
+	// Graders limited to their sections (see model.CourseUser.GetSectionScope()) can only target users in those sections.
+	// Students are not checked, since endpoints that let students target others (e.g., group invites) are not about grading.
+	scope := courseContext.User.GetSectionScope()
+	if (user != nil) && (field.Email != courseContext.User.Email) && (courseContext.User.Role >= model.CourseRoleGrader) &&
+		(scope != nil) && !user.InAnySection(scope) {
+		return NewPermissionsError("-073", courseContext, model.CourseRoleAdmin, courseContext.User.Role, "Target User Outside Of Sections").
+			Add("target-user", field.Email)
+	}
+
 	field.Found = (user != nil)
 	field.User = user
 
@@ -355,6 +365,13 @@ func checkRequestTargetCourseUserSelfOrRole(endpoint string, apiRequest any, fie
 		}
 	}
 
+	// Users limited to their sections (e.g., section graders) can only target users in those sections.
+	scope := courseContext.User.GetSectionScope()
+	if (user != nil) && (field.Email != courseContext.User.Email) && (scope != nil) && !user.InAnySection(scope) {
+		return NewPermissionsError("-070", courseContext, minRole, courseContext.User.Role, "Target User Outside Of Sections").
+			Add("target-user", field.Email)
+	}
+
 	field.Found = (user != nil)
 	field.User = user
 
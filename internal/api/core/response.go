@@ -19,6 +19,11 @@ type APIResponse struct {
 
 	Message string `json:"message"`
 	Content any    `json:"content"`
+
+	// When the request was made while viewing as another user,
+	// the user being viewed as and the user that actually made the request.
+	ViewAs       string `json:"view-as,omitempty"`
+	Impersonator string `json:"impersonator,omitempty"`
 }
 
 func (this *APIResponse) String() string {
@@ -27,6 +32,7 @@ func (this *APIResponse) String() string {
 
 func NewAPIResponse(request ValidAPIRequest, content any) *APIResponse {
 	id, startTime := getRequestIDAndTimestamp(request)
+	viewAs, impersonator := getImpersonationInfo(request)
 
 	version, err := util.GetFullCachedVersion()
 	if err != nil {
@@ -42,5 +48,7 @@ func NewAPIResponse(request ValidAPIRequest, content any) *APIResponse {
 		Success:        true,
 		Message:        "",
 		Content:        content,
+		ViewAs:         viewAs,
+		Impersonator:   impersonator,
 	}
 }
@@ -115,6 +115,14 @@ func handleAPIEndpoint(response http.ResponseWriter, request *http.Request, apiH
 	// Execute the handler.
 	apiResponse, apiErr := callHandler(apiHandler, apiRequest)
 
+	// Some responses are sent as a stream of events instead.
+	if apiErr == nil {
+		streamer, ok := apiResponse.(EventStreamer)
+		if ok && streamer.ShouldStream() {
+			return sendEventStream(request.Context(), apiRequest, response, streamer, startTime)
+		}
+	}
+
 	return sendAPIResponse(apiRequest, response, apiResponse, apiErr, false, startTime)
 }
 
@@ -151,11 +159,31 @@ func sendAPIResponse(apiRequest ValidAPIRequest, response http.ResponseWriter,
 		}
 	}
 
+	storeAPIRequestMetric(apiRequest, apiErr, startTime, apiResponse.EndTimestamp)
+
+	// When in testing mode, allow cross-origin requests.
+	if config.UNIT_TESTING_MODE.Get() {
+		response.Header().Set("Access-Control-Allow-Origin", "*")
+	}
+
+	response.WriteHeader(apiResponse.HTTPStatus)
+
+	_, err = fmt.Fprint(response, payload)
+	if err != nil {
+		http.Error(response, "Server Failed to Send Response - Contact Admins", http.StatusInternalServerError)
+		log.Error("Failed to write final payload to http writer.", err, log.NewAttr("payload", payload))
+		return fmt.Errorf("Could not write API response payload: '%w'.", err)
+	}
+
+	return nil
+}
+
+func storeAPIRequestMetric(apiRequest ValidAPIRequest, apiErr *APIError, startTime timestamp.Timestamp, endTime timestamp.Timestamp) {
 	endpoint, sender, userEmail, courseID, assignmentID, locator := getRequestInfo(apiRequest, apiErr)
 	metric := stats.Metric{
 		Timestamp: startTime,
 		Type:      stats.MetricTypeAPIRequest,
-		Value:     float64((apiResponse.EndTimestamp - startTime).ToMSecs()),
+		Value:     float64((endTime - startTime).ToMSecs()),
 		Attributes: map[stats.MetricAttribute]any{
 			stats.MetricAttributeEndpoint: endpoint,
 		},
@@ -169,22 +197,12 @@ func sendAPIResponse(apiRequest ValidAPIRequest, response http.ResponseWriter,
 	metric.SetUserEmail(userEmail)
 
 	stats.AsyncStoreMetric(&metric)
+}
 
-	// When in testing mode, allow cross-origin requests.
-	if config.UNIT_TESTING_MODE.Get() {
-		response.Header().Set("Access-Control-Allow-Origin", "*")
-	}
-
-	response.WriteHeader(apiResponse.HTTPStatus)
-
-	_, err = fmt.Fprint(response, payload)
-	if err != nil {
-		http.Error(response, "Server Failed to Send Response - Contact Admins", http.StatusInternalServerError)
-		log.Error("Failed to write final payload to http writer.", err, log.NewAttr("payload", payload))
-		return fmt.Errorf("Could not write API response payload: '%w'.", err)
-	}
-
-	return nil
+// Send a standard API response from a route that is not an API endpoint
+// (e.g., a route that users are redirected to by an external service).
+func SendAPIResponse(response http.ResponseWriter, content any, apiError *APIError) error {
+	return sendAPIResponse(nil, response, content, apiError, false, timestamp.Now())
 }
 
 // Reflexively create an API request for the handler from the content of the POST request.
@@ -0,0 +1,87 @@
+package core
+
+// Support for API endpoints that respond with a stream of server-sent events (SSE)
+// instead of a single JSON response.
+// Requests to these endpoints are made (and validated) just like any other API request.
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/edulinq/autograder/internal/config"
+	"github.com/edulinq/autograder/internal/log"
+	"github.com/edulinq/autograder/internal/timestamp"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+const EVENT_STREAM_CONTENT_TYPE = "text/event-stream"
+
+// API responses that implement this interface may be sent as an event stream.
+type EventStreamer interface {
+	// If false, then this response will be sent as a standard API response.
+	ShouldStream() bool
+
+	// Send events until there are no more or the context is done.
+	// Errors cannot be sent to the client (the stream has already started), they are only logged.
+	Stream(ctx context.Context, stream *EventStream) error
+}
+
+type EventStream struct {
+	writer     http.ResponseWriter
+	controller *http.ResponseController
+}
+
+// Send an event with JSON data.
+func (this *EventStream) Send(eventType string, data any) error {
+	payload, err := util.ToJSON(data)
+	if err != nil {
+		return fmt.Errorf("Failed to serialize event data: '%w'.", err)
+	}
+
+	return this.write(fmt.Sprintf("event: %s\ndata: %s\n\n", eventType, payload))
+}
+
+// Send a comment, which clients will ignore, to keep idle connections open.
+func (this *EventStream) KeepAlive() error {
+	return this.write(": keep-alive\n\n")
+}
+
+func (this *EventStream) write(text string) error {
+	_, err := fmt.Fprint(this.writer, text)
+	if err != nil {
+		return fmt.Errorf("Failed to write event: '%w'.", err)
+	}
+
+	err = this.controller.Flush()
+	if err != nil {
+		return fmt.Errorf("Failed to flush event: '%w'.", err)
+	}
+
+	return nil
+}
+
+func sendEventStream(ctx context.Context, apiRequest ValidAPIRequest, response http.ResponseWriter, streamer EventStreamer, startTime timestamp.Timestamp) error {
+	stream := &EventStream{
+		writer:     response,
+		controller: http.NewResponseController(response),
+	}
+
+	if config.UNIT_TESTING_MODE.Get() {
+		response.Header().Set("Access-Control-Allow-Origin", "*")
+	}
+
+	response.Header().Set("Content-Type", EVENT_STREAM_CONTENT_TYPE)
+	response.Header().Set("Cache-Control", "no-cache")
+	response.Header().Set("X-Accel-Buffering", "no")
+	response.WriteHeader(http.StatusOK)
+
+	err := streamer.Stream(ctx, stream)
+	if err != nil {
+		log.Warn("Event stream ended with an error.", err, log.NewAttr("request", apiRequest))
+	}
+
+	storeAPIRequestMetric(apiRequest, nil, startTime, timestamp.Now())
+
+	return nil
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/edulinq/autograder/internal/grader"
 	"github.com/edulinq/autograder/internal/log"
 	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/timestamp"
 )
 
 type BaseSubmitResponse struct {
@@ -68,11 +69,45 @@ func GradeRequestSubmission(request APIRequestAssignmentContext, submissionPath
 	}
 
 	response.GradingSuccess = true
-	response.GradingInfo = result.Info
+	response.GradingInfo = RedactGradingInfo(request.User, request.Assignment, result.Info)
 
 	return response
 }
 
+// Get a short summary of the result of a submission for the audit trail.
+func (this BaseSubmitResponse) GetAuditSummary() map[string]string {
+	summary := map[string]string{
+		"rejected":        fmt.Sprintf("%v", this.Rejected),
+		"grading-success": fmt.Sprintf("%v", this.GradingSuccess),
+	}
+
+	if this.GradingInfo != nil {
+		summary["submission"] = this.GradingInfo.ID
+		summary["score"] = fmt.Sprintf("%f", this.GradingInfo.Score)
+	}
+
+	return summary
+}
+
+// Users below grader only get the feedback that has been released to students.
+func RedactGradingInfo(user *model.CourseUser, assignment *model.Assignment, info *model.GradingInfo) *model.GradingInfo {
+	if user.Role >= model.CourseRoleGrader {
+		return info
+	}
+
+	info, _ = info.Redact(assignment, timestamp.Now())
+	return info
+}
+
+// Users below grader only get the feedback that has been released to students.
+func RedactGradingResult(user *model.CourseUser, assignment *model.Assignment, result *model.GradingResult) *model.GradingResult {
+	if user.Role >= model.CourseRoleGrader {
+		return result
+	}
+
+	return result.Redact(assignment, timestamp.Now())
+}
+
 // Concat stdout/stderr to the given message.
 func ConcatStdOutErr(message string, stdout string, stderr string) string {
 	message += fmt.Sprintf("\n--- stdout ---\n%s\n--------------\n", stdout)
@@ -10,6 +10,12 @@ import (
 	"github.com/edulinq/autograder/internal/util"
 )
 
+// A server-sent event from a test request.
+type TestEvent struct {
+	Type string
+	Data string
+}
+
 var server *httptest.Server
 var serverURL string
 
@@ -63,6 +69,57 @@ func SendTestAPIRequest(test *testing.T, endpoint string, fields map[string]any)
 // The base API path will be expanded to the full API path.
 // If an email is provided without an "@", we will suffix the email with the common test domain.
 func SendTestAPIRequestFull(test *testing.T, endpoint string, fields map[string]any, paths []string, email string) *APIResponse {
+	responseText := sendTestAPIRequest(test, endpoint, fields, paths, email)
+
+	var response APIResponse
+	err := util.JSONFromString(responseText, &response)
+	if err != nil {
+		test.Fatalf("Could not unmarshal JSON response '%s': '%v'.", responseText, err)
+	}
+
+	return &response
+}
+
+// Make a request (see SendTestAPIRequestFull()) to an endpoint that responds with an event stream,
+// and get all the events once the stream is done.
+// If the endpoint sent a standard API response instead, then the response will be returned (and the events will be nil).
+func SendTestAPIEventStreamRequest(test *testing.T, endpoint string, fields map[string]any, email string) ([]*TestEvent, *APIResponse) {
+	responseText := sendTestAPIRequest(test, endpoint, fields, nil, email)
+
+	if !strings.HasPrefix(responseText, "event: ") && !strings.HasPrefix(responseText, ": ") {
+		var response APIResponse
+		err := util.JSONFromString(responseText, &response)
+		if err != nil {
+			test.Fatalf("Could not unmarshal JSON response '%s': '%v'.", responseText, err)
+		}
+
+		return nil, &response
+	}
+
+	events := make([]*TestEvent, 0)
+	for _, rawEvent := range strings.Split(responseText, "\n\n") {
+		event := TestEvent{}
+
+		for _, line := range strings.Split(rawEvent, "\n") {
+			if strings.HasPrefix(line, "event: ") {
+				event.Type = strings.TrimPrefix(line, "event: ")
+			} else if strings.HasPrefix(line, "data: ") {
+				event.Data = strings.TrimPrefix(line, "data: ")
+			}
+		}
+
+		// Skip comments (keep-alives) and the empty text after the final event.
+		if event.Type == "" {
+			continue
+		}
+
+		events = append(events, &event)
+	}
+
+	return events, nil
+}
+
+func sendTestAPIRequest(test *testing.T, endpoint string, fields map[string]any, paths []string, email string) string {
 	url := serverURL + MakeFullAPIPath(endpoint)
 
 	if !strings.Contains(email, "@") {
@@ -104,11 +161,5 @@ func SendTestAPIRequestFull(test *testing.T, endpoint string, fields map[string]
 		test.Fatalf("API POST returned an error: '%v'.", err)
 	}
 
-	var response APIResponse
-	err = util.JSONFromString(responseText, &response)
-	if err != nil {
-		test.Fatalf("Could not unmarshal JSON response '%s': '%v'.", responseText, err)
-	}
-
-	return &response
+	return responseText
 }
@@ -0,0 +1,169 @@
+package core
+
+// Throttling for failed logins.
+// Failures are counted for both the user's email and the sender's IP address,
+// and logins for either will be refused while they are locked out.
+// Throttles are stored in the database, so they persist across server restarts.
+
+import (
+	"net"
+	"net/http"
+	"sync"
+
+	"github.com/edulinq/autograder/internal/config"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/log"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/timestamp"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+// Serialize updates to throttles so concurrent failures are all counted.
+var loginThrottleLock sync.Mutex
+
+type loginThrottleKey struct {
+	Key         string
+	MaxFailures int
+}
+
+// Get a user context for a login that is not made through a standard API request (e.g., an OIDC callback),
+// so that the login can be throttled and audited like any other.
+// The email may be empty if the user is not yet known.
+func NewExternalLoginContext(httpRequest *http.Request, email string) *APIRequestUserContext {
+	return &APIRequestUserContext{
+		APIRequest: APIRequest{
+			RequestID: util.UUID(),
+			Endpoint:  httpRequest.URL.Path,
+			Sender:    httpRequest.RemoteAddr,
+			Timestamp: timestamp.Now(),
+			Context:   httpRequest.Context(),
+		},
+		UserEmail: email,
+	}
+}
+
+// Get the IP address (without a port) of the sender.
+// Returns an empty string if the sender is unknown.
+func getSenderIP(sender string) string {
+	host, _, err := net.SplitHostPort(sender)
+	if err != nil {
+		return sender
+	}
+
+	return host
+}
+
+func (this *APIRequestUserContext) getLoginThrottleKeys(includeEmail bool) []loginThrottleKey {
+	keys := make([]loginThrottleKey, 0, 2)
+
+	maxEmailFailures := config.LOGIN_THROTTLE_EMAIL_FAILURES.Get()
+	if includeEmail && (maxEmailFailures > 0) {
+		keys = append(keys, loginThrottleKey{model.LoginThrottleKeyForEmail(this.UserEmail), maxEmailFailures})
+	}
+
+	ip := getSenderIP(this.Sender)
+	maxIPFailures := config.LOGIN_THROTTLE_IP_FAILURES.Get()
+	if (ip != "") && (maxIPFailures > 0) {
+		keys = append(keys, loginThrottleKey{model.LoginThrottleKeyForIP(ip), maxIPFailures})
+	}
+
+	return keys
+}
+
+// Return an error if this request's user or sender is currently locked out.
+// The current throttles (keyed by throttle key) are also returned.
+func (this *APIRequestUserContext) CheckLoginThrottles() (map[string]*model.LoginThrottle, *APIError) {
+	keys := this.getLoginThrottleKeys(true)
+	if len(keys) == 0 {
+		return nil, nil
+	}
+
+	rawKeys := make([]string, 0, len(keys))
+	for _, key := range keys {
+		rawKeys = append(rawKeys, key.Key)
+	}
+
+	throttles, err := db.GetLoginThrottles(rawKeys)
+	if err != nil {
+		return nil, NewInternalError("-058", this, "Failed to get login throttles.").Err(err)
+	}
+
+	now := timestamp.Now()
+
+	for _, throttle := range throttles {
+		if throttle.IsLocked(now) {
+			return nil, NewAuthError("-059", this, "Too many failed logins.").
+				Add("throttle", throttle.Key).Add("locked-until", throttle.LockedUntil)
+		}
+	}
+
+	return throttles, nil
+}
+
+// Record a failed login for this request.
+// Failures for unknown users are only counted against the sender
+// (so we do not store throttles for arbitrary emails).
+// Errors will be logged, since the login has already failed.
+func (this *APIRequestUserContext) RecordLoginFailure(knownUser bool) {
+	keys := this.getLoginThrottleKeys(knownUser)
+	if len(keys) == 0 {
+		return
+	}
+
+	loginThrottleLock.Lock()
+	defer loginThrottleLock.Unlock()
+
+	now := timestamp.Now()
+	baseSecs := config.LOGIN_THROTTLE_LOCKOUT_SECS.Get()
+	maxSecs := config.LOGIN_THROTTLE_MAX_SECS.Get()
+	windowSecs := config.LOGIN_THROTTLE_WINDOW_SECS.Get()
+
+	throttles := make(map[string]*model.LoginThrottle, len(keys))
+
+	for _, key := range keys {
+		throttle, err := db.GetLoginThrottle(key.Key)
+		if err != nil {
+			log.Error("Failed to get login throttle.", err, log.NewAttr("throttle", key.Key), log.NewUserAttr(this.UserEmail))
+			return
+		}
+
+		if (throttle == nil) || throttle.IsStale(now, windowSecs) {
+			throttle = &model.LoginThrottle{Key: key.Key}
+		}
+
+		locked := throttle.RecordFailure(now, key.MaxFailures, baseSecs, maxSecs)
+		if locked {
+			log.Warn("Login locked out after too many failures.",
+				log.NewUserAttr(this.UserEmail),
+				log.NewAttr("throttle", throttle.Key),
+				log.NewAttr("failures", throttle.Failures),
+				log.NewAttr("locked-until", throttle.LockedUntil))
+		}
+
+		throttles[key.Key] = throttle
+	}
+
+	err := db.UpsertLoginThrottles(throttles)
+	if err != nil {
+		log.Error("Failed to save login throttles.", err, log.NewUserAttr(this.UserEmail))
+	}
+}
+
+// A successful login clears any failures for the user (but not the sender).
+// The throttles should come from CheckLoginThrottles().
+func (this *APIRequestUserContext) RecordLoginSuccess(throttles map[string]*model.LoginThrottle) {
+	key := model.LoginThrottleKeyForEmail(this.UserEmail)
+
+	_, ok := throttles[key]
+	if !ok {
+		return
+	}
+
+	loginThrottleLock.Lock()
+	defer loginThrottleLock.Unlock()
+
+	err := db.RemoveLoginThrottles([]string{key})
+	if err != nil {
+		log.Error("Failed to clear login throttle.", err, log.NewAttr("throttle", key), log.NewUserAttr(this.UserEmail))
+	}
+}
@@ -0,0 +1,155 @@
+package core
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/edulinq/autograder/internal/config"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+type throttleTestRequest struct {
+	APIRequestUserContext
+	MinCourseRoleOther
+}
+
+func TestLoginThrottleEmail(test *testing.T) {
+	defer db.ResetForTesting()
+	defer resetLoginThrottleConfig()
+
+	config.LOGIN_THROTTLE_EMAIL_FAILURES.Set(3)
+
+	email := "course-student@test.edulinq.org"
+
+	testCases := []struct {
+		pass    string
+		locator string
+	}{
+		// Failures up to the threshold.
+		{"ZZZ", "-014"},
+		{"ZZZ", "-014"},
+
+		// A success clears the failures.
+		{"course-student", ""},
+
+		{"ZZZ", "-014"},
+		{"ZZZ", "-014"},
+		{"ZZZ", "-014"},
+
+		// Now locked out, even with the correct password.
+		{"course-student", "-059"},
+		{"ZZZ", "-059"},
+	}
+
+	for i, testCase := range testCases {
+		apiErr := sendThrottleTestRequest(email, testCase.pass, "")
+		checkThrottleTestResult(test, i, apiErr, testCase.locator)
+	}
+
+	// Other users are not locked out.
+	apiErr := sendThrottleTestRequest("course-grader@test.edulinq.org", "course-grader", "")
+	checkThrottleTestResult(test, len(testCases), apiErr, "")
+
+	throttle, err := db.GetLoginThrottle(model.LoginThrottleKeyForEmail(email))
+	if err != nil {
+		test.Fatalf("Failed to get throttle: '%v'.", err)
+	}
+
+	if throttle == nil {
+		test.Fatalf("Could not find throttle.")
+	}
+
+	if throttle.Failures != 3 {
+		test.Fatalf("Unexpected number of failures. Expected: 3, Actual: %d.", throttle.Failures)
+	}
+
+	// Clearing the throttle allows logins again.
+	err = db.RemoveLoginThrottles([]string{throttle.Key})
+	if err != nil {
+		test.Fatalf("Failed to remove throttle: '%v'.", err)
+	}
+
+	apiErr = sendThrottleTestRequest(email, "course-student", "")
+	checkThrottleTestResult(test, len(testCases)+1, apiErr, "")
+}
+
+func TestLoginThrottleIP(test *testing.T) {
+	defer db.ResetForTesting()
+	defer resetLoginThrottleConfig()
+
+	config.LOGIN_THROTTLE_IP_FAILURES.Set(3)
+
+	sender := "192.0.2.1:1234"
+
+	testCases := []struct {
+		email   string
+		pass    string
+		sender  string
+		locator string
+	}{
+		// Failures from different users (including unknown users) all count.
+		{"course-student@test.edulinq.org", "ZZZ", sender, "-014"},
+		{"course-grader@test.edulinq.org", "ZZZ", sender, "-014"},
+		{"ZZZ@test.edulinq.org", "ZZZ", sender, "-013"},
+
+		// Now locked out.
+		{"course-admin@test.edulinq.org", "course-admin", sender, "-059"},
+
+		// Different ports are the same sender.
+		{"course-admin@test.edulinq.org", "course-admin", "192.0.2.1:5678", "-059"},
+
+		// Other senders are fine.
+		{"course-admin@test.edulinq.org", "course-admin", "192.0.2.2:1234", ""},
+	}
+
+	for i, testCase := range testCases {
+		apiErr := sendThrottleTestRequest(testCase.email, testCase.pass, testCase.sender)
+		checkThrottleTestResult(test, i, apiErr, testCase.locator)
+	}
+
+	// Unknown users should not get their own throttle.
+	throttle, err := db.GetLoginThrottle(model.LoginThrottleKeyForEmail("ZZZ@test.edulinq.org"))
+	if err != nil {
+		test.Fatalf("Failed to get throttle: '%v'.", err)
+	}
+
+	if throttle != nil {
+		test.Fatalf("Found a throttle for an unknown user.")
+	}
+}
+
+func sendThrottleTestRequest(email string, pass string, sender string) *APIError {
+	request := throttleTestRequest{
+		APIRequestUserContext: APIRequestUserContext{
+			UserEmail: email,
+			UserPass:  util.Sha256HexFromString(pass),
+		},
+	}
+
+	if sender == "" {
+		return ValidateAPIRequest(nil, &request, "")
+	}
+
+	httpRequest := httptest.NewRequest("POST", "/", nil)
+	httpRequest.RemoteAddr = sender
+
+	return ValidateAPIRequest(httpRequest, &request, "")
+}
+
+func checkThrottleTestResult(test *testing.T, i int, apiErr *APIError, locator string) {
+	if (apiErr == nil) && (locator != "") {
+		test.Errorf("Case %d: Expecting error '%s', but got no error.", i, locator)
+	} else if (apiErr != nil) && (locator == "") {
+		test.Errorf("Case %d: Expecting no error, but got '%s': '%v'.", i, apiErr.Locator, apiErr)
+	} else if (apiErr != nil) && (locator != "") && (apiErr.Locator != locator) {
+		test.Errorf("Case %d: Got a different error than expected. Expected: '%s', actual: '%s' -- '%v'.",
+			i, locator, apiErr.Locator, apiErr)
+	}
+}
+
+func resetLoginThrottleConfig() {
+	config.LOGIN_THROTTLE_EMAIL_FAILURES.Set(0)
+	config.LOGIN_THROTTLE_IP_FAILURES.Set(0)
+}
@@ -0,0 +1,92 @@
+package core
+
+import (
+	"fmt"
+
+	"github.com/edulinq/autograder/internal/config"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/lockmanager"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/totp"
+)
+
+// Endpoints that users who are required to use two-factor auth can still access before enrolling.
+var twoFactorEnrollmentEndpoints = map[string]bool{
+	"users/2fa/enroll": true,
+	"users/2fa/verify": true,
+}
+
+// Endpoints that always require a fresh two-factor code (even when using a token created with two-factor auth).
+// A leaked token should not be able to turn off two-factor auth or create more tokens.
+var twoFactorFreshCodeEndpoints = map[string]bool{
+	"users/2fa/disable":   true,
+	"users/tokens/create": true,
+}
+
+// Check a request's two-factor code (if required).
+// This should only be called after the user's token has been successfully checked.
+func (this *APIRequestUserContext) checkTwoFactor(user *model.ServerUser, token *model.Token, endpoint string) *APIError {
+	if !user.HasTwoFactor() {
+		if config.TWO_FACTOR_REQUIRE_PRIVILEGED.Get() && user.IsTwoFactorPrivileged() && !twoFactorEnrollmentEndpoints[endpoint] {
+			return NewBadRequestError("-060", this,
+				"Two-factor authentication is required for this user. Enroll using the 'users/2fa/enroll' endpoint.")
+		}
+
+		return nil
+	}
+
+	// Tokens created with two-factor auth do not need a code (outside of sensitive endpoints).
+	if token.TwoFactor && !twoFactorFreshCodeEndpoints[endpoint] {
+		return nil
+	}
+
+	if this.TwoFactorCode == "" {
+		return NewBadRequestError("-061", this, "A two-factor code is required for this user.")
+	}
+
+	valid, timeStep, err := totp.ValidateCodeTimeStep(user.TwoFactor.Secret, this.TwoFactorCode, this.Timestamp)
+	if err != nil {
+		return NewInternalError("-062", this, "Failed to check two-factor code.").Err(err)
+	}
+
+	if !valid {
+		this.RecordLoginFailure(true)
+		return NewAuthError("-063", this, "Bad two-factor code.")
+	}
+
+	return this.useTwoFactorTimeStep(user, timeStep)
+}
+
+// Mark a two-factor code's time step as used so the same code cannot be accepted again.
+func (this *APIRequestUserContext) useTwoFactorTimeStep(user *model.ServerUser, timeStep uint64) *APIError {
+	lockKey := fmt.Sprintf("two-factor::%s", user.Email)
+	lockmanager.Lock(lockKey)
+	defer lockmanager.Unlock(lockKey)
+
+	// Check against the latest saved user, another request may have used a code since this user was fetched.
+	savedUser, err := db.GetServerUser(user.Email)
+	if err != nil {
+		return NewInternalError("-074", this, "Failed to get user to check two-factor code usage.").Err(err)
+	}
+
+	if (savedUser == nil) || !savedUser.HasTwoFactor() {
+		return NewInternalError("-075", this, "Could not find two-factor info to check code usage.")
+	}
+
+	if timeStep <= savedUser.TwoFactor.LastTimeStep {
+		this.RecordLoginFailure(true)
+		return NewAuthError("-071", this, "Two-factor code has already been used.")
+	}
+
+	savedUser.TwoFactor.LastTimeStep = timeStep
+
+	err = db.UpsertUser(savedUser)
+	if err != nil {
+		return NewInternalError("-072", this, "Failed to save two-factor code usage.").Err(err)
+	}
+
+	// Keep the request's user in sync so later saves of it do not revert the time step.
+	user.TwoFactor.LastTimeStep = timeStep
+
+	return nil
+}
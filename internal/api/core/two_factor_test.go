@@ -0,0 +1,239 @@
+package core
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/edulinq/autograder/internal/config"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/timestamp"
+	"github.com/edulinq/autograder/internal/totp"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+type twoFactorTestRequest struct {
+	APIRequestUserContext
+	MinCourseRoleOther
+}
+
+func TestAuthTwoFactor(test *testing.T) {
+	defer db.ResetForTesting()
+
+	email := "course-admin@test.edulinq.org"
+	secret := enableTwoFactorForTesting(test, email)
+
+	user := db.MustGetServerUser(email)
+
+	markedToken, markedCleartext, err := user.CreateRandomToken("marked", model.TokenSourceUser)
+	if err != nil {
+		test.Fatalf("Failed to create token: '%v'.", err)
+	}
+
+	markedToken.TwoFactor = true
+
+	_, unmarkedCleartext, err := user.CreateRandomToken("unmarked", model.TokenSourceUser)
+	if err != nil {
+		test.Fatalf("Failed to create token: '%v'.", err)
+	}
+
+	err = db.UpsertUser(user)
+	if err != nil {
+		test.Fatalf("Failed to save user: '%v'.", err)
+	}
+
+	code, err := totp.GenerateCode(secret, timestamp.Now())
+	if err != nil {
+		test.Fatalf("Failed to generate code: '%v'.", err)
+	}
+
+	testCases := []struct {
+		email    string
+		pass     string
+		code     string
+		endpoint string
+		locator  string
+	}{
+		// Passwords require a code.
+		{email, util.Sha256HexFromString("course-admin"), code, "", ""},
+		{email, util.Sha256HexFromString("course-admin"), "", "", "-061"},
+		{email, util.Sha256HexFromString("course-admin"), "000000", "", "-063"},
+		{email, util.Sha256HexFromString("course-admin"), "ZZZ", "", "-063"},
+
+		// Bad passwords fail before the code is checked.
+		{email, util.Sha256HexFromString("ZZZ"), code, "", "-014"},
+
+		// Marked tokens do not require a code.
+		{email, util.Sha256HexFromString(markedCleartext), "", "", ""},
+		{email, util.Sha256HexFromString(markedCleartext), "000000", "", ""},
+
+		// Except for sensitive endpoints.
+		{email, util.Sha256HexFromString(markedCleartext), "", MakeFullAPIPath("users/2fa/disable"), "-061"},
+		{email, util.Sha256HexFromString(markedCleartext), "", MakeFullAPIPath("users/tokens/create"), "-061"},
+		{email, util.Sha256HexFromString(markedCleartext), "000000", MakeFullAPIPath("users/tokens/create"), "-063"},
+		{email, util.Sha256HexFromString(markedCleartext), code, MakeFullAPIPath("users/2fa/disable"), ""},
+		{email, util.Sha256HexFromString(markedCleartext), code, MakeFullAPIPath("users/tokens/create"), ""},
+
+		// Unmarked tokens do.
+		{email, util.Sha256HexFromString(unmarkedCleartext), code, "", ""},
+		{email, util.Sha256HexFromString(unmarkedCleartext), "", "", "-061"},
+
+		// Other users are not affected.
+		{"course-student@test.edulinq.org", util.Sha256HexFromString("course-student"), "", "", ""},
+		{"course-student@test.edulinq.org", util.Sha256HexFromString("course-student"), "000000", "", ""},
+	}
+
+	for i, testCase := range testCases {
+		// Allow the same code to be used in each case.
+		clearTwoFactorTimeStepForTesting(test, email)
+
+		apiErr := sendTwoFactorTestRequest(testCase.email, testCase.pass, testCase.code, testCase.endpoint)
+		checkTwoFactorTestResult(test, i, apiErr, testCase.locator)
+	}
+}
+
+func TestAuthTwoFactorReusedCode(test *testing.T) {
+	defer db.ResetForTesting()
+
+	email := "course-admin@test.edulinq.org"
+	pass := util.Sha256HexFromString("course-admin")
+	secret := enableTwoFactorForTesting(test, email)
+
+	now := timestamp.Now()
+	periodMSecs := timestamp.FromMSecs(totp.PERIOD_SECS * 1000)
+
+	previousCode := mustGenerateTwoFactorCode(test, secret, now-periodMSecs)
+	currentCode := mustGenerateTwoFactorCode(test, secret, now)
+	nextCode := mustGenerateTwoFactorCode(test, secret, now+periodMSecs)
+
+	testCases := []struct {
+		code    string
+		locator string
+	}{
+		{currentCode, ""},
+
+		// The same code cannot be used again.
+		{currentCode, "-071"},
+
+		// Codes from earlier periods cannot be used after a later one.
+		{previousCode, "-071"},
+
+		{nextCode, ""},
+		{nextCode, "-071"},
+		{currentCode, "-071"},
+	}
+
+	for i, testCase := range testCases {
+		apiErr := sendTwoFactorTestRequest(email, pass, testCase.code, "")
+		checkTwoFactorTestResult(test, i, apiErr, testCase.locator)
+	}
+}
+
+func TestAuthTwoFactorRequired(test *testing.T) {
+	defer db.ResetForTesting()
+	defer config.TWO_FACTOR_REQUIRE_PRIVILEGED.Set(false)
+
+	config.TWO_FACTOR_REQUIRE_PRIVILEGED.Set(true)
+
+	testCases := []struct {
+		email    string
+		endpoint string
+		locator  string
+	}{
+		{"course-owner@test.edulinq.org", "", "-060"},
+		{"course-admin@test.edulinq.org", "", "-060"},
+		{"server-admin@test.edulinq.org", "", "-060"},
+		{"server-owner@test.edulinq.org", "", "-060"},
+
+		{"course-grader@test.edulinq.org", "", ""},
+		{"course-student@test.edulinq.org", "", ""},
+		{"server-user@test.edulinq.org", "", ""},
+		{"server-creator@test.edulinq.org", "", ""},
+
+		// Users can still enroll.
+		{"course-admin@test.edulinq.org", MakeFullAPIPath("users/2fa/enroll"), ""},
+		{"course-admin@test.edulinq.org", MakeFullAPIPath("users/2fa/verify"), ""},
+		{"course-admin@test.edulinq.org", MakeFullAPIPath("users/2fa/disable"), "-060"},
+	}
+
+	for i, testCase := range testCases {
+		pass := util.Sha256HexFromString(strings.Split(testCase.email, "@")[0])
+		apiErr := sendTwoFactorTestRequest(testCase.email, pass, "", testCase.endpoint)
+		checkTwoFactorTestResult(test, i, apiErr, testCase.locator)
+	}
+
+	// Once enrolled, the user can make requests.
+	email := "course-admin@test.edulinq.org"
+	secret := enableTwoFactorForTesting(test, email)
+
+	code, err := totp.GenerateCode(secret, timestamp.Now())
+	if err != nil {
+		test.Fatalf("Failed to generate code: '%v'.", err)
+	}
+
+	apiErr := sendTwoFactorTestRequest(email, util.Sha256HexFromString("course-admin"), code, "")
+	checkTwoFactorTestResult(test, len(testCases), apiErr, "")
+}
+
+func enableTwoFactorForTesting(test *testing.T, email string) string {
+	secret, err := totp.NewSecret()
+	if err != nil {
+		test.Fatalf("Failed to create secret: '%v'.", err)
+	}
+
+	user := db.MustGetServerUser(email)
+	user.TwoFactor = &model.TwoFactorInfo{
+		Secret:     secret,
+		Enabled:    true,
+		EnrollTime: timestamp.Now(),
+	}
+
+	err = db.UpsertUser(user)
+	if err != nil {
+		test.Fatalf("Failed to save user: '%v'.", err)
+	}
+
+	return secret
+}
+
+func clearTwoFactorTimeStepForTesting(test *testing.T, email string) {
+	user := db.MustGetServerUser(email)
+	user.TwoFactor.LastTimeStep = 0
+
+	err := db.UpsertUser(user)
+	if err != nil {
+		test.Fatalf("Failed to save user: '%v'.", err)
+	}
+}
+
+func mustGenerateTwoFactorCode(test *testing.T, secret string, now timestamp.Timestamp) string {
+	code, err := totp.GenerateCode(secret, now)
+	if err != nil {
+		test.Fatalf("Failed to generate code: '%v'.", err)
+	}
+
+	return code
+}
+
+func sendTwoFactorTestRequest(email string, pass string, code string, endpoint string) *APIError {
+	request := twoFactorTestRequest{
+		APIRequestUserContext: APIRequestUserContext{
+			UserEmail:     email,
+			UserPass:      pass,
+			TwoFactorCode: code,
+		},
+	}
+
+	return ValidateAPIRequest(nil, &request, endpoint)
+}
+
+func checkTwoFactorTestResult(test *testing.T, i int, apiErr *APIError, locator string) {
+	if (apiErr == nil) && (locator != "") {
+		test.Errorf("Case %d: Expecting error '%s', but got no error.", i, locator)
+	} else if (apiErr != nil) && (locator == "") {
+		test.Errorf("Case %d: Expecting no error, but got '%s': '%v'.", i, apiErr.Locator, apiErr)
+	} else if (apiErr != nil) && (locator != "") && (apiErr.Locator != locator) {
+		test.Errorf("Case %d: Got a different error than expected. Expected: '%s', actual: '%s' -- '%v'.",
+			i, locator, apiErr.Locator, apiErr)
+	}
+}
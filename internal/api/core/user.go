@@ -39,8 +39,9 @@ type EnrollmentInfo struct {
 // Embed the BaseUserInfo and use CourseUserInfoType as the type.
 type CourseUserInfo struct {
 	BaseUserInfo
-	Role  model.CourseUserRole `json:"role"`
-	LMSID string               `json:"lms-id"`
+	Role     model.CourseUserRole `json:"role"`
+	LMSID    string               `json:"lms-id"`
+	Sections []string             `json:"sections,omitempty"`
 }
 
 func NewServerUserInfo(user *model.ServerUser) *ServerUserInfo {
@@ -102,8 +103,9 @@ func NewCourseUserInfo(user *model.CourseUser) *CourseUserInfo {
 			Email: user.Email,
 			Name:  user.GetDisplayName(),
 		},
-		Role:  user.Role,
-		LMSID: user.GetLMSID(),
+		Role:     user.Role,
+		LMSID:    user.GetLMSID(),
+		Sections: user.Sections,
 	}
 
 	return info
@@ -9,6 +9,7 @@ import (
 var routes []core.Route = []core.Route{
 	core.MustNewAPIRoute(`courses/admin/email`, HandleEmail),
 	core.MustNewAPIRoute(`courses/admin/update`, HandleUpdate),
+	core.MustNewAPIRoute(`courses/admin/verify`, HandleVerify),
 }
 
 func GetRoutes() *[]core.Route {
@@ -1,7 +1,10 @@
 package admin
 
 import (
+	"fmt"
+
 	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/model"
 	"github.com/edulinq/autograder/internal/procedures/courses"
 )
 
@@ -17,6 +20,7 @@ type UpdateResponse struct {
 }
 
 // Update an existing course.
+// Updates (that are not dry runs) are recorded in the audit trail.
 func HandleUpdate(request *UpdateRequest) (*UpdateResponse, *core.APIError) {
 	options := request.CourseUpsertOptions
 	options.ContextUser = request.ServerUser
@@ -27,5 +31,14 @@ func HandleUpdate(request *UpdateRequest) (*UpdateResponse, *core.APIError) {
 			"Failed to update course.").Err(err)
 	}
 
+	if !options.DryRun && result.Success && (result.Created || result.Updated) {
+		record := model.NewAuditRecord(model.AuditActionCourseUpsert, result.CourseID)
+		record.After = map[string]string{
+			"created": fmt.Sprintf("%v", result.Created),
+			"updated": fmt.Sprintf("%v", result.Updated),
+		}
+		request.Audit(record)
+	}
+
 	return &UpdateResponse{result}, nil
 }
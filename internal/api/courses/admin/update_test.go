@@ -5,6 +5,8 @@ import (
 
 	"github.com/edulinq/autograder/internal/api/core"
 	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
 )
 
 // Change the course name and ensure it is back after an update.
@@ -37,3 +39,51 @@ func TestUpdateCourse(test *testing.T) {
 		test.Fatalf("Name change survived update.")
 	}
 }
+
+func TestUpdateCourseAudit(test *testing.T) {
+	defer db.ResetForTesting()
+
+	testCases := []struct {
+		dryRun          bool
+		expectedRecords int
+	}{
+		{true, 0},
+		{false, 1},
+	}
+
+	for i, testCase := range testCases {
+		db.ResetForTesting()
+
+		fields := map[string]any{
+			"dry-run":           testCase.dryRun,
+			"skip-build-images": true,
+		}
+
+		response := core.SendTestAPIRequestFull(test, `courses/admin/update`, fields, nil, "course-admin")
+		if !response.Success {
+			test.Errorf("Case %d: Response is not a success when it should be: '%v'.", i, response)
+			continue
+		}
+
+		records, err := db.GetAuditRecords(model.AuditQuery{Action: model.AuditActionCourseUpsert})
+		if err != nil {
+			test.Errorf("Case %d: Failed to get audit records: '%v'.", i, err)
+			continue
+		}
+
+		if testCase.expectedRecords != len(records) {
+			test.Errorf("Case %d: Unexpected number of audit records. Expected: %d, Actual: %d.", i, testCase.expectedRecords, len(records))
+			continue
+		}
+
+		if len(records) == 0 {
+			continue
+		}
+
+		record := records[0]
+		if (record.Actor != "course-admin@test.edulinq.org") || (record.CourseID != db.TEST_COURSE_ID) || (record.After["updated"] != "true") {
+			test.Errorf("Case %d: Unexpected audit record: '%s'.", i, util.MustToJSONIndent(record))
+			continue
+		}
+	}
+}
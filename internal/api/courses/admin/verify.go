@@ -0,0 +1,93 @@
+package admin
+
+import (
+	"errors"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/grader"
+	"github.com/edulinq/autograder/internal/model"
+)
+
+type VerifyRequest struct {
+	core.APIRequestCourseUserContext
+	core.MinCourseRoleAdmin
+
+	// Only verify this assignment (instead of all the course's assignments).
+	TargetAssignment string `json:"target-assignment"`
+
+	ForceBuild bool `json:"force-build"`
+
+	// Wait for the verification to complete and return the result.
+	// Otherwise, the verification runs in the background and its ID is returned.
+	WaitForCompletion bool `json:"wait-for-completion"`
+
+	// Check on an earlier background verification (instead of starting a new one).
+	VerifyID string `json:"verify-id"`
+}
+
+type VerifyResponse struct {
+	// The ID of a background verification, which can be passed back to check on it.
+	VerifyID string `json:"verify-id,omitempty"`
+
+	Complete bool                 `json:"complete"`
+	Result   *grader.VerifyResult `json:"result"`
+}
+
+// Grade the test submissions for a course's assignments and compare them against their expected results.
+// Verification gradings are not saved.
+// Unless waiting for completion, the verification is run in the background and its ID is returned
+// (finished background verifications are only kept for a limited time).
+func HandleVerify(request *VerifyRequest) (*VerifyResponse, *core.APIError) {
+	if request.VerifyID != "" {
+		return getVerifyStatus(request)
+	}
+
+	assignments := request.Course.GetSortedAssignments()
+
+	if request.TargetAssignment != "" {
+		assignment := request.Course.GetAssignment(request.TargetAssignment)
+		if assignment == nil {
+			return nil, core.NewBadRequestError("-706", request, "Unknown assignment.").
+				Add("assignment-id", request.TargetAssignment)
+		}
+
+		assignments = []*model.Assignment{assignment}
+	}
+
+	options := grader.VerifyOptions{
+		ForceBuild: request.ForceBuild,
+	}
+
+	if !request.WaitForCompletion {
+		status := grader.StartVerifyAssignments(request.Course, assignments, options)
+		return &VerifyResponse{VerifyID: status.ID}, nil
+	}
+
+	result, err := grader.VerifyAssignments(request.Context, assignments, options)
+	if err != nil {
+		return nil, core.NewInternalError("-707", request, "Failed to verify assignments.").Err(err)
+	}
+
+	return &VerifyResponse{Complete: true, Result: result}, nil
+}
+
+func getVerifyStatus(request *VerifyRequest) (*VerifyResponse, *core.APIError) {
+	status := grader.GetVerifyStatus(request.VerifyID)
+	if (status == nil) || (status.CourseID != request.Course.GetID()) {
+		return nil, core.NewBadRequestError("-711", request, "Unknown verification.").
+			Add("verify-id", request.VerifyID)
+	}
+
+	if status.Error != "" {
+		return nil, core.NewInternalError("-712", request, "Failed to verify assignments.").
+			Err(errors.New(status.Error)).Add("verify-id", request.VerifyID)
+	}
+
+	response := &VerifyResponse{
+		VerifyID: status.ID,
+		Complete: status.Complete,
+		Result:   status.Result,
+	}
+
+	return response, nil
+}
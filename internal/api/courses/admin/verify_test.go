@@ -0,0 +1,167 @@
+package admin
+
+import (
+	"testing"
+	"time"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/config"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestVerify(test *testing.T) {
+	db.ResetForTesting()
+	defer db.ResetForTesting()
+
+	defer config.DOCKER_DISABLE.Set(config.DOCKER_DISABLE.Get())
+	config.DOCKER_DISABLE.Set(true)
+
+	testCases := []struct {
+		email            string
+		targetAssignment string
+		expectedLocator  string
+	}{
+		// Only the bash assignment can be graded without installing any grader dependencies.
+		{"course-admin", "bash", ""},
+		{"server-admin", "bash", ""},
+
+		// Unknown assignment.
+		{"course-admin", "zzz", "-706"},
+
+		// Bad permissions.
+		{"course-grader", "bash", "-020"},
+		{"course-student", "bash", "-020"},
+	}
+
+	for i, testCase := range testCases {
+		fields := map[string]any{
+			"course-id":           "course-languages",
+			"target-assignment":   testCase.targetAssignment,
+			"wait-for-completion": true,
+		}
+
+		response := core.SendTestAPIRequestFull(test, `courses/admin/verify`, fields, nil, testCase.email)
+		if !response.Success {
+			if testCase.expectedLocator == "" {
+				test.Errorf("Case %d: Response is not a success when it should be: '%v'.", i, response)
+			} else if testCase.expectedLocator != response.Locator {
+				test.Errorf("Case %d: Incorrect error returned. Expected '%s', found '%s'.", i, testCase.expectedLocator, response.Locator)
+			}
+
+			continue
+		}
+
+		if testCase.expectedLocator != "" {
+			test.Errorf("Case %d: Did not get an expected error.", i)
+			continue
+		}
+
+		var responseContent VerifyResponse
+		util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent)
+
+		if !responseContent.Complete {
+			test.Errorf("Case %d: Verification is not complete after waiting.", i)
+			continue
+		}
+
+		result := responseContent.Result
+		if (result == nil) || !result.Passed || (result.NumPassed != 3) || (result.NumFailed != 0) {
+			test.Errorf("Case %d: Unexpected verify result: '%s'.", i, util.MustToJSONIndent(result))
+			continue
+		}
+
+		for _, testResult := range result.Results {
+			if testResult.AssignmentID != testCase.targetAssignment {
+				test.Errorf("Case %d: Verified an unexpected assignment: '%s'.", i, testResult.AssignmentID)
+			}
+		}
+	}
+}
+
+func TestVerifyBackground(test *testing.T) {
+	db.ResetForTesting()
+	defer db.ResetForTesting()
+
+	defer config.DOCKER_DISABLE.Set(config.DOCKER_DISABLE.Get())
+	config.DOCKER_DISABLE.Set(true)
+
+	fields := map[string]any{
+		"course-id":         "course-languages",
+		"target-assignment": "bash",
+	}
+
+	response := core.SendTestAPIRequestFull(test, `courses/admin/verify`, fields, nil, "course-admin")
+	if !response.Success {
+		test.Fatalf("Response is not a success when it should be: '%v'.", response)
+	}
+
+	var responseContent VerifyResponse
+	util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent)
+
+	if responseContent.VerifyID == "" {
+		test.Fatalf("Did not get a verification ID.")
+	}
+
+	if responseContent.Complete || (responseContent.Result != nil) {
+		test.Fatalf("Background verification started as complete: '%s'.", util.MustToJSONIndent(responseContent))
+	}
+
+	verifyID := responseContent.VerifyID
+
+	// Unknown verifications (and ones from other courses) cannot be checked.
+	testCases := []struct {
+		courseID string
+		verifyID string
+	}{
+		{"course-languages", "zzz"},
+		{"course101", verifyID},
+	}
+
+	for i, testCase := range testCases {
+		fields = map[string]any{
+			"course-id": testCase.courseID,
+			"verify-id": testCase.verifyID,
+		}
+
+		response = core.SendTestAPIRequestFull(test, `courses/admin/verify`, fields, nil, "server-admin")
+		if response.Success {
+			test.Errorf("Case %d: Response is a success when it should not be.", i)
+			continue
+		}
+
+		if response.Locator != "-711" {
+			test.Errorf("Case %d: Incorrect error returned. Expected '-711', found '%s'.", i, response.Locator)
+		}
+	}
+
+	fields = map[string]any{
+		"course-id": "course-languages",
+		"verify-id": verifyID,
+	}
+
+	for i := 0; i < 100; i++ {
+		response = core.SendTestAPIRequestFull(test, `courses/admin/verify`, fields, nil, "course-admin")
+		if !response.Success {
+			test.Fatalf("Status response is not a success when it should be: '%v'.", response)
+		}
+
+		responseContent = VerifyResponse{}
+		util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent)
+
+		if responseContent.Complete {
+			break
+		}
+
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	if !responseContent.Complete {
+		test.Fatalf("Background verification did not complete.")
+	}
+
+	result := responseContent.Result
+	if (result == nil) || !result.Passed || (result.NumPassed != 3) || (result.NumFailed != 0) {
+		test.Fatalf("Unexpected verify result: '%s'.", util.MustToJSONIndent(result))
+	}
+}
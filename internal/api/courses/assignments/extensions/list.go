@@ -0,0 +1,35 @@
+package extensions
+
+import (
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+type ListRequest struct {
+	core.APIRequestAssignmentContext
+	core.MinCourseRoleAdmin
+}
+
+type ListResponse struct {
+	Extensions []*model.AssignmentExtension `json:"extensions"`
+}
+
+// List all the user extensions for an assignment.
+func HandleList(request *ListRequest) (*ListResponse, *core.APIError) {
+	extensions, err := db.GetAssignmentExtensions(request.Assignment)
+	if err != nil {
+		return nil, core.NewInternalError("-660", request, "Failed to get assignment extensions.").Err(err)
+	}
+
+	response := ListResponse{
+		Extensions: make([]*model.AssignmentExtension, 0, len(extensions)),
+	}
+
+	for _, email := range util.GetSortedKeys(extensions) {
+		response.Extensions = append(response.Extensions, extensions[email])
+	}
+
+	return &response, nil
+}
@@ -0,0 +1,95 @@
+package extensions
+
+import (
+	"testing"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/timestamp"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestListBase(test *testing.T) {
+	defer db.ResetForTesting()
+	db.ResetForTesting()
+
+	expected := addTestExtensions(test)
+
+	testCases := []struct {
+		email   string
+		locator string
+	}{
+		{"course-admin", ""},
+		{"course-owner", ""},
+		{"server-admin", ""},
+
+		{"course-grader", "-020"},
+		{"course-student", "-020"},
+		{"server-user", "-040"},
+	}
+
+	for i, testCase := range testCases {
+		response := core.SendTestAPIRequestFull(test, `courses/assignments/extensions/list`, nil, nil, testCase.email)
+		if !response.Success {
+			if testCase.locator != response.Locator {
+				test.Errorf("Case %d: Incorrect error returned. Expected: '%s', Actual: '%s'.",
+					i, testCase.locator, response.Locator)
+			}
+
+			continue
+		}
+
+		if testCase.locator != "" {
+			test.Errorf("Case %d: Did not get an expected error. Expected: '%s'.", i, testCase.locator)
+			continue
+		}
+
+		var responseContent ListResponse
+		util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent)
+
+		expectedJSON := util.MustToJSONIndent(expected)
+		actualJSON := util.MustToJSONIndent(responseContent.Extensions)
+
+		if expectedJSON != actualJSON {
+			test.Errorf("Case %d: Unexpected extensions. Expected: '%s', Actual: '%s'.", i, expectedJSON, actualJSON)
+			continue
+		}
+	}
+}
+
+// Add extensions for the test student and other user (returned in email order).
+func addTestExtensions(test *testing.T) []*model.AssignmentExtension {
+	extensions := []*model.AssignmentExtension{
+		&model.AssignmentExtension{
+			CourseID:      db.TEST_COURSE_ID,
+			AssignmentID:  db.TEST_ASSIGNMENT_ID,
+			Email:         "course-other@test.edulinq.org",
+			ExtraAttempts: 1,
+			UpdatedBy:     "course-admin@test.edulinq.org",
+			UpdateTime:    timestamp.FromMSecs(100),
+		},
+		&model.AssignmentExtension{
+			CourseID:     db.TEST_COURSE_ID,
+			AssignmentID: db.TEST_ASSIGNMENT_ID,
+			Email:        "course-student@test.edulinq.org",
+			DueDate:      timestampPointer(1000),
+			UpdatedBy:    "course-admin@test.edulinq.org",
+			UpdateTime:   timestamp.FromMSecs(200),
+		},
+	}
+
+	for _, extension := range extensions {
+		err := db.UpsertAssignmentExtension(db.MustGetTestAssignment(), extension)
+		if err != nil {
+			test.Fatalf("Failed to add test extension: '%v'.", err)
+		}
+	}
+
+	return extensions
+}
+
+func timestampPointer(msecs int64) *timestamp.Timestamp {
+	value := timestamp.FromMSecs(msecs)
+	return &value
+}
@@ -0,0 +1,38 @@
+package extensions
+
+import (
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+)
+
+type RemoveRequest struct {
+	core.APIRequestAssignmentContext
+	core.MinCourseRoleAdmin
+
+	TargetCourseUser core.TargetCourseUser `json:"target-email" required:""`
+}
+
+type RemoveResponse struct {
+	FoundUser      bool `json:"found-user"`
+	FoundExtension bool `json:"found-extension"`
+}
+
+// Remove a user's extension for an assignment.
+func HandleRemove(request *RemoveRequest) (*RemoveResponse, *core.APIError) {
+	response := RemoveResponse{}
+
+	if !request.TargetCourseUser.Found {
+		return &response, nil
+	}
+
+	response.FoundUser = true
+
+	found, err := db.RemoveAssignmentExtension(request.Assignment, request.TargetCourseUser.Email)
+	if err != nil {
+		return nil, core.NewInternalError("-663", request, "Failed to remove extension.").Err(err).Add("target-user", request.TargetCourseUser.Email)
+	}
+
+	response.FoundExtension = found
+
+	return &response, nil
+}
@@ -0,0 +1,73 @@
+package extensions
+
+import (
+	"testing"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestRemoveBase(test *testing.T) {
+	defer db.ResetForTesting()
+
+	testCases := []struct {
+		email          string
+		targetEmail    string
+		foundUser      bool
+		foundExtension bool
+		locator        string
+	}{
+		{"course-admin", "course-student@test.edulinq.org", true, true, ""},
+		{"server-admin", "course-other@test.edulinq.org", true, true, ""},
+		{"course-admin", "course-grader@test.edulinq.org", true, false, ""},
+		{"course-admin", "zzz@test.edulinq.org", false, false, ""},
+
+		{"course-grader", "course-student@test.edulinq.org", false, false, "-020"},
+		{"server-user", "course-student@test.edulinq.org", false, false, "-040"},
+	}
+
+	for i, testCase := range testCases {
+		db.ResetForTesting()
+		addTestExtensions(test)
+
+		fields := map[string]any{
+			"target-email": testCase.targetEmail,
+		}
+
+		response := core.SendTestAPIRequestFull(test, `courses/assignments/extensions/remove`, fields, nil, testCase.email)
+		if !response.Success {
+			if testCase.locator != response.Locator {
+				test.Errorf("Case %d: Incorrect error returned. Expected: '%s', Actual: '%s'.",
+					i, testCase.locator, response.Locator)
+			}
+
+			continue
+		}
+
+		if testCase.locator != "" {
+			test.Errorf("Case %d: Did not get an expected error. Expected: '%s'.", i, testCase.locator)
+			continue
+		}
+
+		var responseContent RemoveResponse
+		util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent)
+
+		expected := RemoveResponse{testCase.foundUser, testCase.foundExtension}
+		if expected != responseContent {
+			test.Errorf("Case %d: Unexpected response. Expected: '%+v', Actual: '%+v'.", i, expected, responseContent)
+			continue
+		}
+
+		extension, err := db.GetAssignmentExtension(db.MustGetTestAssignment(), testCase.targetEmail)
+		if err != nil {
+			test.Errorf("Case %d: Failed to get extension: '%v'.", i, err)
+			continue
+		}
+
+		if extension != nil {
+			test.Errorf("Case %d: Extension was not removed.", i)
+			continue
+		}
+	}
+}
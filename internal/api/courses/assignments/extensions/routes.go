@@ -0,0 +1,21 @@
+package extensions
+
+// All the API endpoints handled by this package.
+
+import (
+	"github.com/edulinq/autograder/internal/api/core"
+)
+
+var baseRoutes []core.Route = []core.Route{
+	core.MustNewAPIRoute(`courses/assignments/extensions/list`, HandleList),
+	core.MustNewAPIRoute(`courses/assignments/extensions/remove`, HandleRemove),
+	core.MustNewAPIRoute(`courses/assignments/extensions/upsert`, HandleUpsert),
+}
+
+func GetRoutes() *[]core.Route {
+	routes := make([]core.Route, 0)
+
+	routes = append(routes, baseRoutes...)
+
+	return &routes
+}
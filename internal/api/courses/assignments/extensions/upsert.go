@@ -0,0 +1,63 @@
+package extensions
+
+import (
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/timestamp"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+type UpsertRequest struct {
+	core.APIRequestAssignmentContext
+	core.MinCourseRoleAdmin
+
+	TargetCourseUser core.TargetCourseUser `json:"target-email" required:""`
+
+	DueDate       *timestamp.Timestamp `json:"due-date"`
+	ExtraTime     *util.DurationSpec   `json:"extra-time"`
+	ExtraAttempts int                  `json:"extra-attempts"`
+	Reason        string               `json:"reason"`
+}
+
+type UpsertResponse struct {
+	FoundUser bool                       `json:"found-user"`
+	Extension *model.AssignmentExtension `json:"extension"`
+}
+
+// Create or replace a user's extension (a new due date or extra time, and/or extra submissions) for an assignment.
+func HandleUpsert(request *UpsertRequest) (*UpsertResponse, *core.APIError) {
+	response := UpsertResponse{}
+
+	if !request.TargetCourseUser.Found {
+		return &response, nil
+	}
+
+	response.FoundUser = true
+
+	extension := &model.AssignmentExtension{
+		CourseID:      request.Course.GetID(),
+		AssignmentID:  request.Assignment.GetID(),
+		Email:         request.TargetCourseUser.Email,
+		DueDate:       request.DueDate,
+		ExtraTime:     request.ExtraTime,
+		ExtraAttempts: request.ExtraAttempts,
+		Reason:        request.Reason,
+		UpdatedBy:     request.User.Email,
+		UpdateTime:    timestamp.Now(),
+	}
+
+	err := extension.Validate()
+	if err != nil {
+		return nil, core.NewBadRequestError("-661", request, "Invalid extension.").Err(err).Add("target-user", request.TargetCourseUser.Email)
+	}
+
+	err = db.UpsertAssignmentExtension(request.Assignment, extension)
+	if err != nil {
+		return nil, core.NewInternalError("-662", request, "Failed to save extension.").Err(err).Add("target-user", request.TargetCourseUser.Email)
+	}
+
+	response.Extension = extension
+
+	return &response, nil
+}
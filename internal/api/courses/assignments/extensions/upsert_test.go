@@ -0,0 +1,123 @@
+package extensions
+
+import (
+	"testing"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestUpsertBase(test *testing.T) {
+	defer db.ResetForTesting()
+
+	testCases := []struct {
+		email     string
+		fields    map[string]any
+		foundUser bool
+		expected  *model.AssignmentExtension
+		locator   string
+	}{
+		// Valid extensions.
+		{
+			"course-admin",
+			map[string]any{"target-email": "course-student@test.edulinq.org", "due-date": 1000},
+			true,
+			&model.AssignmentExtension{Email: "course-student@test.edulinq.org", DueDate: timestampPointer(1000), UpdatedBy: "course-admin@test.edulinq.org"},
+			"",
+		},
+		{
+			"course-owner",
+			map[string]any{"target-email": "course-student@test.edulinq.org", "extra-time": map[string]any{"days": 2}, "extra-attempts": 1, "reason": "Accommodation."},
+			true,
+			&model.AssignmentExtension{Email: "course-student@test.edulinq.org", ExtraTime: &util.DurationSpec{Days: 2}, ExtraAttempts: 1, Reason: "Accommodation.", UpdatedBy: "course-owner@test.edulinq.org"},
+			"",
+		},
+		{
+			"server-admin",
+			map[string]any{"target-email": "course-student@test.edulinq.org", "extra-attempts": 2},
+			true,
+			&model.AssignmentExtension{Email: "course-student@test.edulinq.org", ExtraAttempts: 2, UpdatedBy: "server-admin@test.edulinq.org"},
+			"",
+		},
+
+		// Missing user.
+		{"course-admin", map[string]any{"target-email": "zzz@test.edulinq.org", "extra-attempts": 1}, false, nil, ""},
+
+		// Invalid extensions.
+		{"course-admin", map[string]any{"target-email": "course-student@test.edulinq.org"}, true, nil, "-661"},
+		{"course-admin", map[string]any{"target-email": "course-student@test.edulinq.org", "due-date": 1000, "extra-time": map[string]any{"days": 2}}, true, nil, "-661"},
+		{"course-admin", map[string]any{"target-email": "course-student@test.edulinq.org", "extra-attempts": -1}, true, nil, "-661"},
+
+		// Permissions.
+		{"course-grader", map[string]any{"target-email": "course-student@test.edulinq.org", "extra-attempts": 1}, false, nil, "-020"},
+		{"course-student", map[string]any{"target-email": "course-student@test.edulinq.org", "extra-attempts": 1}, false, nil, "-020"},
+		{"server-user", map[string]any{"target-email": "course-student@test.edulinq.org", "extra-attempts": 1}, false, nil, "-040"},
+	}
+
+	assignment := db.MustGetTestAssignment()
+
+	for i, testCase := range testCases {
+		db.ResetForTesting()
+
+		response := core.SendTestAPIRequestFull(test, `courses/assignments/extensions/upsert`, testCase.fields, nil, testCase.email)
+		if !response.Success {
+			if testCase.locator != response.Locator {
+				test.Errorf("Case %d: Incorrect error returned. Expected: '%s', Actual: '%s'.",
+					i, testCase.locator, response.Locator)
+			}
+
+			continue
+		}
+
+		if testCase.locator != "" {
+			test.Errorf("Case %d: Did not get an expected error. Expected: '%s'.", i, testCase.locator)
+			continue
+		}
+
+		var responseContent UpsertResponse
+		util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent)
+
+		if testCase.foundUser != responseContent.FoundUser {
+			test.Errorf("Case %d: Unexpected found user. Expected: '%v', Actual: '%v'.", i, testCase.foundUser, responseContent.FoundUser)
+			continue
+		}
+
+		if testCase.expected == nil {
+			if responseContent.Extension != nil {
+				test.Errorf("Case %d: Got an unexpected extension: '%s'.", i, util.MustToJSONIndent(responseContent.Extension))
+			}
+
+			continue
+		}
+
+		extension, err := db.GetAssignmentExtension(assignment, testCase.expected.Email)
+		if err != nil {
+			test.Errorf("Case %d: Failed to get extension: '%v'.", i, err)
+			continue
+		}
+
+		if extension == nil {
+			test.Errorf("Case %d: Extension was not saved.", i)
+			continue
+		}
+
+		// Fill in fields that are not known ahead of time.
+		testCase.expected.CourseID = db.TEST_COURSE_ID
+		testCase.expected.AssignmentID = db.TEST_ASSIGNMENT_ID
+		testCase.expected.UpdateTime = extension.UpdateTime
+
+		expectedJSON := util.MustToJSONIndent(testCase.expected)
+
+		if expectedJSON != util.MustToJSONIndent(extension) {
+			test.Errorf("Case %d: Unexpected saved extension. Expected: '%s', Actual: '%s'.", i, expectedJSON, util.MustToJSONIndent(extension))
+			continue
+		}
+
+		if expectedJSON != util.MustToJSONIndent(responseContent.Extension) {
+			test.Errorf("Case %d: Unexpected response extension. Expected: '%s', Actual: '%s'.", i, expectedJSON, util.MustToJSONIndent(responseContent.Extension))
+			continue
+		}
+	}
+}
@@ -1,7 +1,11 @@
 package assignments
 
 import (
+	"fmt"
+
 	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/timestamp"
 )
 
 type GetRequest struct {
@@ -14,7 +18,12 @@ type GetResponse struct {
 }
 
 // Get the information for a course assignment.
+// Assignments that have not been opened yet are only visible to graders and above.
 func HandleGet(request *GetRequest) (*GetResponse, *core.APIError) {
+	if (request.User.Role < model.CourseRoleGrader) && !request.Assignment.IsOpen(timestamp.Now()) {
+		return nil, core.NewBadRequestError("-664", request, fmt.Sprintf("Could not find assignment: '%s'.", request.AssignmentID))
+	}
+
 	response := GetResponse{
 		Assignment: core.NewAssignmentInfo(request.Assignment),
 	}
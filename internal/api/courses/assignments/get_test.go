@@ -6,6 +6,7 @@ import (
 
 	"github.com/edulinq/autograder/internal/api/core"
 	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/timestamp"
 	"github.com/edulinq/autograder/internal/util"
 )
 
@@ -66,3 +67,54 @@ func TestGet(test *testing.T) {
 		}
 	}
 }
+
+func TestGetNotOpen(test *testing.T) {
+	db.ResetForTesting()
+	defer db.ResetForTesting()
+
+	assignment := db.MustGetTestAssignment()
+
+	openDate := timestamp.Now() + timestamp.FromMSecs(60*60*1000)
+	assignment.OpenDate = &openDate
+
+	err := db.SaveAssignment(assignment)
+	if err != nil {
+		test.Fatalf("Failed to save assignment: '%v'.", err)
+	}
+
+	testCases := []struct {
+		email   string
+		locator string
+	}{
+		{"course-other@test.edulinq.org", "-664"},
+		{"course-student@test.edulinq.org", "-664"},
+		{"course-grader@test.edulinq.org", ""},
+		{"course-admin@test.edulinq.org", ""},
+		{"server-admin@test.edulinq.org", ""},
+	}
+
+	for i, testCase := range testCases {
+		response := core.SendTestAPIRequestFull(test, `courses/assignments/get`, nil, nil, testCase.email)
+		if !response.Success {
+			if testCase.locator != response.Locator {
+				test.Errorf("Case %d: Incorrect error returned. Expected '%s', found '%s'.", i, testCase.locator, response.Locator)
+			}
+
+			continue
+		}
+
+		if testCase.locator != "" {
+			test.Errorf("Case %d: Did not get an expected error '%s'.", i, testCase.locator)
+			continue
+		}
+
+		var responseContent GetResponse
+		util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent)
+
+		if (responseContent.Assignment.OpenDate == nil) || (openDate != *responseContent.Assignment.OpenDate) {
+			test.Errorf("Case %d: Unexpected open date. Expected: '%s', Actual: '%s'.",
+				i, openDate.SafeString(), responseContent.Assignment.OpenDate.SafeString())
+			continue
+		}
+	}
+}
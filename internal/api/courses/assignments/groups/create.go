@@ -0,0 +1,100 @@
+package groups
+
+import (
+	"slices"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/lockmanager"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/timestamp"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+type CreateRequest struct {
+	core.APIRequestAssignmentContext
+	core.MinCourseRoleStudent
+
+	// The group's members. Students may only create a group with just themselves (the default for students).
+	Members []string `json:"members"`
+}
+
+type CreateResponse struct {
+	Group *model.AssignmentGroup `json:"group"`
+}
+
+// Create a new group for an assignment.
+func HandleCreate(request *CreateRequest) (*CreateResponse, *core.APIError) {
+	apiError := checkGroupPermissions(&request.APIRequestAssignmentContext)
+	if apiError != nil {
+		return nil, apiError
+	}
+
+	members := make([]string, 0, len(request.Members))
+	for _, member := range request.Members {
+		if !slices.Contains(members, member) {
+			members = append(members, member)
+		}
+	}
+
+	if request.User.Role < model.CourseRoleGrader {
+		if len(members) == 0 {
+			members = append(members, request.User.Email)
+		}
+
+		if (len(members) != 1) || (members[0] != request.User.Email) {
+			return nil, core.NewBadRequestError("-654", request, "Students may only create a group that contains just themselves.")
+		}
+	}
+
+	if len(members) == 0 {
+		return nil, core.NewBadRequestError("-655", request, "A group must have at least one member.")
+	}
+
+	maxSize := request.Assignment.GroupOptions.MaxSize
+	if len(members) > maxSize {
+		return nil, core.NewBadRequestError("-656", request, "Too many members for a group.").
+			Add("num-members", len(members)).Add("max-size", maxSize)
+	}
+
+	lockKey := getLockKey(request.Assignment)
+	lockmanager.Lock(lockKey)
+	defer lockmanager.Unlock(lockKey)
+
+	groups, apiError := getGroups(&request.APIRequestAssignmentContext)
+	if apiError != nil {
+		return nil, apiError
+	}
+
+	for _, member := range members {
+		user, err := db.GetCourseUser(request.Course, member)
+		if err != nil {
+			return nil, core.NewInternalError("-657", request, "Failed to get course user.").Err(err).Add("target-user", member)
+		}
+
+		apiError = checkNewMember(&request.APIRequestAssignmentContext, groups, user)
+		if apiError != nil {
+			return nil, apiError.Add("target-user", member)
+		}
+	}
+
+	group := &model.AssignmentGroup{
+		ID:           util.UUID(),
+		CourseID:     request.Course.GetID(),
+		AssignmentID: request.Assignment.GetID(),
+		Members:      members,
+		CreatedBy:    request.User.Email,
+		CreationTime: timestamp.Now(),
+	}
+
+	apiError = saveGroup(&request.APIRequestAssignmentContext, group)
+	if apiError != nil {
+		return nil, apiError
+	}
+
+	response := CreateResponse{
+		Group: group,
+	}
+
+	return &response, nil
+}
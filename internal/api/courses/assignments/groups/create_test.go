@@ -0,0 +1,137 @@
+package groups
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/timestamp"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestCreateBase(test *testing.T) {
+	defer db.ResetForTesting()
+
+	testCases := []struct {
+		email              string
+		groupAssignment    bool
+		allowStudentFormed bool
+		members            []string
+		expectedMembers    []string
+		locator            string
+	}{
+		// Students.
+		{"course-student", true, true, nil, []string{"course-student@test.edulinq.org"}, ""},
+		{"course-student", true, true, []string{"course-student@test.edulinq.org"}, []string{"course-student@test.edulinq.org"}, ""},
+		{"course-student", true, true, []string{"extra-course-student-1@test.edulinq.org"}, nil, "-654"},
+		{"course-student", true, true, []string{"course-student@test.edulinq.org", "extra-course-student-1@test.edulinq.org"}, nil, "-654"},
+		{"course-student", true, false, nil, nil, "-649"},
+
+		// Graders.
+		{"course-grader", true, false, []string{"course-student@test.edulinq.org", "extra-course-student-1@test.edulinq.org"}, []string{"course-student@test.edulinq.org", "extra-course-student-1@test.edulinq.org"}, ""},
+		{"course-grader", true, false, []string{"course-student@test.edulinq.org", "course-student@test.edulinq.org"}, []string{"course-student@test.edulinq.org"}, ""},
+		{"server-admin", true, false, []string{"course-student@test.edulinq.org"}, []string{"course-student@test.edulinq.org"}, ""},
+		{"course-grader", true, false, nil, nil, "-655"},
+		{"course-grader", true, false, []string{"course-student@test.edulinq.org", "extra-course-student-1@test.edulinq.org", "extra-course-student-3@test.edulinq.org"}, nil, "-656"},
+
+		// Bad members.
+		{"course-grader", true, false, []string{"course-grader@test.edulinq.org"}, nil, "-652"},
+		{"course-grader", true, false, []string{"course-other@test.edulinq.org"}, nil, "-652"},
+		{"course-grader", true, false, []string{"zzz@test.edulinq.org"}, nil, "-652"},
+		{"course-grader", true, false, []string{"extra-course-student-2@test.edulinq.org"}, nil, "-653"},
+
+		// Not a group assignment.
+		{"course-grader", false, false, []string{"course-student@test.edulinq.org"}, nil, "-648"},
+
+		// Permissions.
+		{"course-other", true, true, nil, nil, "-020"},
+		{"server-user", true, true, nil, nil, "-040"},
+	}
+
+	for i, testCase := range testCases {
+		prepGroupAssignment(test, testCase.groupAssignment, testCase.allowStudentFormed)
+
+		fields := map[string]any{
+			"members": testCase.members,
+		}
+
+		response := core.SendTestAPIRequestFull(test, `courses/assignments/groups/create`, fields, nil, testCase.email)
+		if !response.Success {
+			if testCase.locator != response.Locator {
+				test.Errorf("Case %d: Incorrect error returned. Expected: '%s', Actual: '%s'.",
+					i, testCase.locator, response.Locator)
+			}
+
+			continue
+		}
+
+		if testCase.locator != "" {
+			test.Errorf("Case %d: Did not get an expected error. Expected: '%s'.", i, testCase.locator)
+			continue
+		}
+
+		var responseContent CreateResponse
+		util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent)
+
+		if !slices.Equal(testCase.expectedMembers, responseContent.Group.Members) {
+			test.Errorf("Case %d: Unexpected members. Expected: '%v', Actual: '%v'.", i, testCase.expectedMembers, responseContent.Group.Members)
+			continue
+		}
+
+		group, err := db.GetAssignmentGroup(db.MustGetTestAssignment(), responseContent.Group.ID)
+		if err != nil {
+			test.Errorf("Case %d: Failed to get created group: '%v'.", i, err)
+			continue
+		}
+
+		if !responseContent.Group.Equals(group) {
+			test.Errorf("Case %d: Saved group does not match response. Expected: '%s', Actual: '%s'.",
+				i, util.MustToJSONIndent(responseContent.Group), util.MustToJSONIndent(group))
+			continue
+		}
+	}
+}
+
+// Reset the database and setup the test assignment to be a group assignment (with a max group size of two).
+// Extra students one and two will be enrolled in the course, and extra student two will be in group "existing".
+func prepGroupAssignment(test *testing.T, groupAssignment bool, allowStudentFormed bool) {
+	db.ResetForTesting()
+
+	for _, email := range []string{"extra-course-student-1@test.edulinq.org", "extra-course-student-2@test.edulinq.org"} {
+		user := db.MustGetServerUser(email)
+		user.CourseInfo[db.TEST_COURSE_ID] = &model.UserCourseInfo{Role: model.CourseRoleStudent}
+		db.MustUpsertUser(user)
+	}
+
+	if !groupAssignment {
+		return
+	}
+
+	assignment := db.MustGetTestAssignment()
+	assignment.GroupOptions = &model.GroupOptions{
+		MaxSize:            2,
+		AllowStudentFormed: allowStudentFormed,
+	}
+
+	err := db.SaveAssignment(assignment)
+	if err != nil {
+		test.Fatalf("Failed to save group assignment: '%v'.", err)
+	}
+
+	group := &model.AssignmentGroup{
+		ID:           "existing",
+		CourseID:     db.TEST_COURSE_ID,
+		AssignmentID: db.TEST_ASSIGNMENT_ID,
+		Members:      []string{"extra-course-student-2@test.edulinq.org"},
+		CreatedBy:    "course-admin@test.edulinq.org",
+		CreationTime: timestamp.Now(),
+		Invites:      []string{"course-student@test.edulinq.org"},
+	}
+
+	err = db.UpsertAssignmentGroup(assignment, group)
+	if err != nil {
+		test.Fatalf("Failed to save test group: '%v'.", err)
+	}
+}
@@ -0,0 +1,78 @@
+package groups
+
+import (
+	"fmt"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+)
+
+// Get the lock that must be held while changing an assignment's groups.
+func getLockKey(assignment *model.Assignment) string {
+	return fmt.Sprintf("groups::%s::%s", assignment.GetCourse().GetID(), assignment.GetID())
+}
+
+func checkGroupAssignment(request *core.APIRequestAssignmentContext) *core.APIError {
+	if !request.Assignment.IsGroupAssignment() {
+		return core.NewBadRequestError("-648", request, "Assignment is not submitted by groups.")
+	}
+
+	return nil
+}
+
+// Ensure that the request's assignment uses groups,
+// and that the context user is allowed to manage the assignment's groups.
+func checkGroupPermissions(request *core.APIRequestAssignmentContext) *core.APIError {
+	apiError := checkGroupAssignment(request)
+	if apiError != nil {
+		return apiError
+	}
+
+	if (request.User.Role < model.CourseRoleGrader) && !request.Assignment.GroupOptions.AllowStudentFormed {
+		return core.NewBadRequestError("-649", request, "Students cannot manage their own groups for this assignment.")
+	}
+
+	return nil
+}
+
+func getGroups(request *core.APIRequestAssignmentContext) (map[string]*model.AssignmentGroup, *core.APIError) {
+	groups, err := db.GetAssignmentGroups(request.Assignment)
+	if err != nil {
+		return nil, core.NewInternalError("-650", request, "Failed to get assignment groups.").Err(err)
+	}
+
+	return groups, nil
+}
+
+// Save a group, removing it if it no longer has any members.
+func saveGroup(request *core.APIRequestAssignmentContext, group *model.AssignmentGroup) *core.APIError {
+	var err error
+	if len(group.Members) == 0 {
+		err = db.RemoveAssignmentGroup(request.Assignment, group.ID)
+	} else {
+		err = db.UpsertAssignmentGroup(request.Assignment, group)
+	}
+
+	if err != nil {
+		return core.NewInternalError("-651", request, "Failed to save assignment group.").Err(err).Add("group-id", group.ID)
+	}
+
+	return nil
+}
+
+// Check that a user can be added to a group for this assignment.
+func checkNewMember(request *core.APIRequestAssignmentContext, groups map[string]*model.AssignmentGroup, user *model.CourseUser) *core.APIError {
+	if (user == nil) || (user.Role != model.CourseRoleStudent) {
+		return core.NewBadRequestError("-652", request, "Group members must be students in the course.")
+	}
+
+	for _, group := range groups {
+		if group.HasMember(user.Email) {
+			return core.NewBadRequestError("-653", request, "User is already in a group for this assignment.").
+				Add("target-user", user.Email).Add("group-id", group.ID)
+		}
+	}
+
+	return nil
+}
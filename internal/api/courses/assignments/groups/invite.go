@@ -0,0 +1,83 @@
+package groups
+
+import (
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/common"
+	"github.com/edulinq/autograder/internal/lockmanager"
+	"github.com/edulinq/autograder/internal/model"
+)
+
+type InviteRequest struct {
+	core.APIRequestAssignmentContext
+	core.MinCourseRoleStudent
+
+	GroupID    string                `json:"group-id" required:""`
+	TargetUser core.TargetCourseUser `json:"target-email" required:""`
+}
+
+type InviteResponse struct {
+	FoundUser  bool                   `json:"found-user"`
+	FoundGroup bool                   `json:"found-group"`
+	Group      *model.AssignmentGroup `json:"group"`
+}
+
+// Invite a student to join a group for an assignment.
+// Students can only invite others to their own group, and students can only join groups they have been invited to.
+func HandleInvite(request *InviteRequest) (*InviteResponse, *core.APIError) {
+	apiError := checkGroupPermissions(&request.APIRequestAssignmentContext)
+	if apiError != nil {
+		return nil, apiError
+	}
+
+	groupID, err := common.ValidateID(request.GroupID)
+	if err != nil {
+		return nil, core.NewBadRequestError("-708", request, "Invalid group ID.").Err(err).Add("group-id", request.GroupID)
+	}
+
+	response := InviteResponse{}
+
+	if !request.TargetUser.Found {
+		return &response, nil
+	}
+
+	response.FoundUser = true
+
+	lockKey := getLockKey(request.Assignment)
+	lockmanager.Lock(lockKey)
+	defer lockmanager.Unlock(lockKey)
+
+	groups, apiError := getGroups(&request.APIRequestAssignmentContext)
+	if apiError != nil {
+		return nil, apiError
+	}
+
+	group := groups[groupID]
+	if group == nil {
+		return &response, nil
+	}
+
+	response.FoundGroup = true
+
+	if (request.User.Role < model.CourseRoleGrader) && !group.HasMember(request.User.Email) {
+		return nil, core.NewBadRequestError("-709", request, "Students can only invite users to their own group.").
+			Add("group-id", group.ID)
+	}
+
+	apiError = checkNewMember(&request.APIRequestAssignmentContext, groups, request.TargetUser.User)
+	if apiError != nil {
+		return nil, apiError.Add("target-user", request.TargetUser.Email)
+	}
+
+	if !group.HasInvite(request.TargetUser.Email) {
+		group.Invites = append(group.Invites, request.TargetUser.Email)
+
+		apiError = saveGroup(&request.APIRequestAssignmentContext, group)
+		if apiError != nil {
+			return nil, apiError
+		}
+	}
+
+	response.Group = group
+
+	return &response, nil
+}
@@ -0,0 +1,146 @@
+package groups
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestInviteBase(test *testing.T) {
+	defer db.ResetForTesting()
+
+	testCases := []struct {
+		email              string
+		targetEmail        string
+		groupID            string
+		groupAssignment    bool
+		allowStudentFormed bool
+		foundUser          bool
+		foundGroup         bool
+		expectedInvites    []string
+		locator            string
+	}{
+		// Members.
+		{"extra-course-student-2", "extra-course-student-1@test.edulinq.org", "existing", true, true, true, true, []string{"course-student@test.edulinq.org", "extra-course-student-1@test.edulinq.org"}, ""},
+		{"extra-course-student-2", "extra-course-student-1@test.edulinq.org", "EXISTING", true, true, true, true, []string{"course-student@test.edulinq.org", "extra-course-student-1@test.edulinq.org"}, ""},
+		{"extra-course-student-2", "course-student@test.edulinq.org", "existing", true, true, true, true, []string{"course-student@test.edulinq.org"}, ""},
+		{"extra-course-student-2", "extra-course-student-1@test.edulinq.org", "zzz", true, true, true, false, nil, ""},
+		{"extra-course-student-2", "zzz@test.edulinq.org", "existing", true, true, false, false, nil, ""},
+		{"extra-course-student-2", "extra-course-student-1@test.edulinq.org", "existing", true, false, false, false, nil, "-649"},
+
+		// Graders.
+		{"course-grader", "extra-course-student-1@test.edulinq.org", "existing", true, false, true, true, []string{"course-student@test.edulinq.org", "extra-course-student-1@test.edulinq.org"}, ""},
+
+		// Not a member.
+		{"extra-course-student-1", "course-student@test.edulinq.org", "existing", true, true, true, true, nil, "-709"},
+
+		// Bad targets.
+		{"extra-course-student-2", "course-grader@test.edulinq.org", "existing", true, true, true, true, nil, "-652"},
+		{"extra-course-student-2", "extra-course-student-2@test.edulinq.org", "existing", true, true, true, true, nil, "-653"},
+
+		// Bad group ID.
+		{"extra-course-student-2", "extra-course-student-1@test.edulinq.org", "../existing", true, true, false, false, nil, "-708"},
+
+		// Not a group assignment.
+		{"extra-course-student-2", "extra-course-student-1@test.edulinq.org", "existing", false, true, false, false, nil, "-648"},
+	}
+
+	for i, testCase := range testCases {
+		prepGroupAssignment(test, testCase.groupAssignment, testCase.allowStudentFormed)
+
+		fields := map[string]any{
+			"target-email": testCase.targetEmail,
+			"group-id":     testCase.groupID,
+		}
+
+		response := core.SendTestAPIRequestFull(test, `courses/assignments/groups/invite`, fields, nil, testCase.email)
+		if !response.Success {
+			if testCase.locator != response.Locator {
+				test.Errorf("Case %d: Incorrect error returned. Expected: '%s', Actual: '%s'.",
+					i, testCase.locator, response.Locator)
+			}
+
+			continue
+		}
+
+		if testCase.locator != "" {
+			test.Errorf("Case %d: Did not get an expected error. Expected: '%s'.", i, testCase.locator)
+			continue
+		}
+
+		var responseContent InviteResponse
+		util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent)
+
+		if testCase.foundUser != responseContent.FoundUser {
+			test.Errorf("Case %d: Unexpected found user. Expected: '%v', Actual: '%v'.", i, testCase.foundUser, responseContent.FoundUser)
+			continue
+		}
+
+		if testCase.foundGroup != responseContent.FoundGroup {
+			test.Errorf("Case %d: Unexpected found group. Expected: '%v', Actual: '%v'.", i, testCase.foundGroup, responseContent.FoundGroup)
+			continue
+		}
+
+		if !testCase.foundGroup {
+			continue
+		}
+
+		if !slices.Equal(testCase.expectedInvites, responseContent.Group.Invites) {
+			test.Errorf("Case %d: Unexpected invites. Expected: '%v', Actual: '%v'.", i, testCase.expectedInvites, responseContent.Group.Invites)
+			continue
+		}
+	}
+}
+
+// A student can join a group after being invited by a member.
+func TestInviteJoin(test *testing.T) {
+	defer db.ResetForTesting()
+
+	prepGroupAssignment(test, true, true)
+
+	fields := map[string]any{
+		"group-id": "existing",
+	}
+
+	response := core.SendTestAPIRequestFull(test, `courses/assignments/groups/join`, fields, nil, "extra-course-student-1")
+	if response.Success {
+		test.Fatalf("Joined a group without an invite.")
+	}
+
+	fields = map[string]any{
+		"target-email": "extra-course-student-1@test.edulinq.org",
+		"group-id":     "existing",
+	}
+
+	response = core.SendTestAPIRequestFull(test, `courses/assignments/groups/invite`, fields, nil, "extra-course-student-2")
+	if !response.Success {
+		test.Fatalf("Failed to invite to group: '%s'.", response.Locator)
+	}
+
+	fields = map[string]any{
+		"group-id": "existing",
+	}
+
+	response = core.SendTestAPIRequestFull(test, `courses/assignments/groups/join`, fields, nil, "extra-course-student-1")
+	if !response.Success {
+		test.Fatalf("Failed to join group after an invite: '%s'.", response.Locator)
+	}
+
+	group, err := db.GetAssignmentGroup(db.MustGetTestAssignment(), "existing")
+	if err != nil {
+		test.Fatalf("Failed to get group: '%v'.", err)
+	}
+
+	expectedMembers := []string{"extra-course-student-2@test.edulinq.org", "extra-course-student-1@test.edulinq.org"}
+	if !slices.Equal(expectedMembers, group.Members) {
+		test.Fatalf("Unexpected members. Expected: '%v', Actual: '%v'.", expectedMembers, group.Members)
+	}
+
+	expectedInvites := []string{"course-student@test.edulinq.org"}
+	if !slices.Equal(expectedInvites, group.Invites) {
+		test.Fatalf("Unexpected invites. Expected: '%v', Actual: '%v'.", expectedInvites, group.Invites)
+	}
+}
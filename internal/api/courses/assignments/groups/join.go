@@ -0,0 +1,92 @@
+package groups
+
+import (
+	"slices"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/common"
+	"github.com/edulinq/autograder/internal/lockmanager"
+	"github.com/edulinq/autograder/internal/model"
+)
+
+type JoinRequest struct {
+	core.APIRequestAssignmentContext
+	core.MinCourseRoleStudent
+
+	GroupID    string                            `json:"group-id" required:""`
+	TargetUser core.TargetCourseUserSelfOrGrader `json:"target-email"`
+}
+
+type JoinResponse struct {
+	FoundUser  bool                   `json:"found-user"`
+	FoundGroup bool                   `json:"found-group"`
+	Group      *model.AssignmentGroup `json:"group"`
+}
+
+// Add a user to an existing group for an assignment.
+// Students can only join a group that they have been invited to (see HandleInvite).
+func HandleJoin(request *JoinRequest) (*JoinResponse, *core.APIError) {
+	apiError := checkGroupPermissions(&request.APIRequestAssignmentContext)
+	if apiError != nil {
+		return nil, apiError
+	}
+
+	groupID, err := common.ValidateID(request.GroupID)
+	if err != nil {
+		return nil, core.NewBadRequestError("-658", request, "Invalid group ID.").Err(err).Add("group-id", request.GroupID)
+	}
+
+	response := JoinResponse{}
+
+	if !request.TargetUser.Found {
+		return &response, nil
+	}
+
+	response.FoundUser = true
+
+	lockKey := getLockKey(request.Assignment)
+	lockmanager.Lock(lockKey)
+	defer lockmanager.Unlock(lockKey)
+
+	groups, apiError := getGroups(&request.APIRequestAssignmentContext)
+	if apiError != nil {
+		return nil, apiError
+	}
+
+	group := groups[groupID]
+	if group == nil {
+		return &response, nil
+	}
+
+	response.FoundGroup = true
+
+	apiError = checkNewMember(&request.APIRequestAssignmentContext, groups, request.TargetUser.User)
+	if apiError != nil {
+		return nil, apiError.Add("target-user", request.TargetUser.Email)
+	}
+
+	maxSize := request.Assignment.GroupOptions.MaxSize
+	if len(group.Members) >= maxSize {
+		return nil, core.NewBadRequestError("-659", request, "Group is full.").
+			Add("group-id", group.ID).Add("max-size", maxSize)
+	}
+
+	if (request.User.Role < model.CourseRoleGrader) && !group.HasInvite(request.TargetUser.Email) {
+		return nil, core.NewBadRequestError("-710", request, "Students can only join a group that they have been invited to.").
+			Add("group-id", group.ID)
+	}
+
+	group.Members = append(group.Members, request.TargetUser.Email)
+	group.Invites = slices.DeleteFunc(group.Invites, func(email string) bool {
+		return email == request.TargetUser.Email
+	})
+
+	apiError = saveGroup(&request.APIRequestAssignmentContext, group)
+	if apiError != nil {
+		return nil, apiError
+	}
+
+	response.Group = group
+
+	return &response, nil
+}
@@ -0,0 +1,131 @@
+package groups
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestJoinBase(test *testing.T) {
+	defer db.ResetForTesting()
+
+	testCases := []struct {
+		email              string
+		targetEmail        string
+		groupID            string
+		groupAssignment    bool
+		allowStudentFormed bool
+		foundUser          bool
+		foundGroup         bool
+		expectedMembers    []string
+		locator            string
+	}{
+		// Self.
+		{"course-student", "", "existing", true, true, true, true, []string{"extra-course-student-2@test.edulinq.org", "course-student@test.edulinq.org"}, ""},
+		{"course-student", "", "EXISTING", true, true, true, true, []string{"extra-course-student-2@test.edulinq.org", "course-student@test.edulinq.org"}, ""},
+		{"course-student", "", "zzz", true, true, true, false, nil, ""},
+		{"course-student", "", "existing", true, false, false, false, nil, "-649"},
+
+		// Not invited.
+		{"extra-course-student-1", "", "existing", true, true, true, true, nil, "-710"},
+
+		// Other.
+		{"course-grader", "course-student@test.edulinq.org", "existing", true, false, true, true, []string{"extra-course-student-2@test.edulinq.org", "course-student@test.edulinq.org"}, ""},
+		{"course-grader", "zzz@test.edulinq.org", "existing", true, false, false, false, nil, ""},
+		{"course-student", "extra-course-student-1@test.edulinq.org", "existing", true, true, false, false, nil, "-033"},
+
+		// Bad members.
+		{"course-grader", "course-grader@test.edulinq.org", "existing", true, false, true, true, nil, "-652"},
+		{"course-grader", "extra-course-student-2@test.edulinq.org", "existing", true, false, true, true, nil, "-653"},
+
+		// Bad group ID.
+		{"course-student", "", "../existing", true, true, false, false, nil, "-658"},
+
+		// Not a group assignment.
+		{"course-student", "", "existing", false, true, false, false, nil, "-648"},
+	}
+
+	for i, testCase := range testCases {
+		prepGroupAssignment(test, testCase.groupAssignment, testCase.allowStudentFormed)
+
+		fields := map[string]any{
+			"target-email": testCase.targetEmail,
+			"group-id":     testCase.groupID,
+		}
+
+		response := core.SendTestAPIRequestFull(test, `courses/assignments/groups/join`, fields, nil, testCase.email)
+		if !response.Success {
+			if testCase.locator != response.Locator {
+				test.Errorf("Case %d: Incorrect error returned. Expected: '%s', Actual: '%s'.",
+					i, testCase.locator, response.Locator)
+			}
+
+			continue
+		}
+
+		if testCase.locator != "" {
+			test.Errorf("Case %d: Did not get an expected error. Expected: '%s'.", i, testCase.locator)
+			continue
+		}
+
+		var responseContent JoinResponse
+		util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent)
+
+		if testCase.foundUser != responseContent.FoundUser {
+			test.Errorf("Case %d: Unexpected found user. Expected: '%v', Actual: '%v'.", i, testCase.foundUser, responseContent.FoundUser)
+			continue
+		}
+
+		if testCase.foundGroup != responseContent.FoundGroup {
+			test.Errorf("Case %d: Unexpected found group. Expected: '%v', Actual: '%v'.", i, testCase.foundGroup, responseContent.FoundGroup)
+			continue
+		}
+
+		if !testCase.foundGroup {
+			continue
+		}
+
+		if !slices.Equal(testCase.expectedMembers, responseContent.Group.Members) {
+			test.Errorf("Case %d: Unexpected members. Expected: '%v', Actual: '%v'.", i, testCase.expectedMembers, responseContent.Group.Members)
+			continue
+		}
+
+		if len(responseContent.Group.Invites) != 0 {
+			test.Errorf("Case %d: Invite was not removed after joining: '%v'.", i, responseContent.Group.Invites)
+			continue
+		}
+	}
+}
+
+func TestJoinFull(test *testing.T) {
+	defer db.ResetForTesting()
+
+	prepGroupAssignment(test, true, true)
+
+	fields := map[string]any{
+		"target-email": "extra-course-student-1@test.edulinq.org",
+		"group-id":     "existing",
+	}
+
+	response := core.SendTestAPIRequestFull(test, `courses/assignments/groups/join`, fields, nil, "course-grader")
+	if !response.Success {
+		test.Fatalf("Failed to join group: '%s'.", response.Locator)
+	}
+
+	fields = map[string]any{
+		"group-id": "existing",
+	}
+
+	response = core.SendTestAPIRequestFull(test, `courses/assignments/groups/join`, fields, nil, "course-student")
+	if response.Success {
+		test.Fatalf("Joined a full group.")
+	}
+
+	expectedLocator := "-659"
+	if expectedLocator != response.Locator {
+		test.Fatalf("Incorrect error returned. Expected: '%s', Actual: '%s'.", expectedLocator, response.Locator)
+	}
+}
@@ -0,0 +1,76 @@
+package groups
+
+import (
+	"slices"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/lockmanager"
+	"github.com/edulinq/autograder/internal/model"
+)
+
+type LeaveRequest struct {
+	core.APIRequestAssignmentContext
+	core.MinCourseRoleStudent
+
+	TargetUser core.TargetCourseUserSelfOrGrader `json:"target-email"`
+}
+
+type LeaveResponse struct {
+	FoundUser  bool                   `json:"found-user"`
+	FoundGroup bool                   `json:"found-group"`
+	Group      *model.AssignmentGroup `json:"group"`
+}
+
+// Remove a user from their group for an assignment. Groups without any remaining members are removed.
+func HandleLeave(request *LeaveRequest) (*LeaveResponse, *core.APIError) {
+	apiError := checkGroupPermissions(&request.APIRequestAssignmentContext)
+	if apiError != nil {
+		return nil, apiError
+	}
+
+	response := LeaveResponse{}
+
+	if !request.TargetUser.Found {
+		return &response, nil
+	}
+
+	response.FoundUser = true
+
+	lockKey := getLockKey(request.Assignment)
+	lockmanager.Lock(lockKey)
+	defer lockmanager.Unlock(lockKey)
+
+	groups, apiError := getGroups(&request.APIRequestAssignmentContext)
+	if apiError != nil {
+		return nil, apiError
+	}
+
+	var group *model.AssignmentGroup
+	for _, candidate := range groups {
+		if candidate.HasMember(request.TargetUser.Email) {
+			group = candidate
+			break
+		}
+	}
+
+	if group == nil {
+		return &response, nil
+	}
+
+	response.FoundGroup = true
+
+	group.Members = slices.DeleteFunc(group.Members, func(member string) bool {
+		return member == request.TargetUser.Email
+	})
+
+	apiError = saveGroup(&request.APIRequestAssignmentContext, group)
+	if apiError != nil {
+		return nil, apiError
+	}
+
+	if len(group.Members) > 0 {
+		response.Group = group
+	}
+
+	return &response, nil
+}
@@ -0,0 +1,113 @@
+package groups
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestLeaveBase(test *testing.T) {
+	defer db.ResetForTesting()
+
+	testCases := []struct {
+		email              string
+		targetEmail        string
+		groupAssignment    bool
+		allowStudentFormed bool
+		joinFirst          bool
+		foundUser          bool
+		foundGroup         bool
+		expectedMembers    []string
+		locator            string
+	}{
+		// Self.
+		{"course-student", "", true, true, true, true, true, []string{"extra-course-student-2@test.edulinq.org"}, ""},
+		{"course-student", "", true, true, false, true, false, nil, ""},
+		{"course-student", "", true, false, true, false, false, nil, "-649"},
+
+		// Other.
+		{"course-grader", "course-student@test.edulinq.org", true, false, true, true, true, []string{"extra-course-student-2@test.edulinq.org"}, ""},
+		{"course-grader", "zzz@test.edulinq.org", true, false, false, false, false, nil, ""},
+		{"course-student", "extra-course-student-2@test.edulinq.org", true, true, false, false, false, nil, "-033"},
+
+		// Last member, group is removed.
+		{"course-grader", "extra-course-student-2@test.edulinq.org", true, false, false, true, true, nil, ""},
+
+		// Not a group assignment.
+		{"course-student", "", false, true, false, false, false, nil, "-648"},
+	}
+
+	for i, testCase := range testCases {
+		prepGroupAssignment(test, testCase.groupAssignment, testCase.allowStudentFormed)
+
+		if testCase.joinFirst {
+			fields := map[string]any{
+				"target-email": "course-student@test.edulinq.org",
+				"group-id":     "existing",
+			}
+
+			response := core.SendTestAPIRequestFull(test, `courses/assignments/groups/join`, fields, nil, "course-grader")
+			if !response.Success {
+				test.Fatalf("Case %d: Failed to join group: '%s'.", i, response.Locator)
+			}
+		}
+
+		fields := map[string]any{
+			"target-email": testCase.targetEmail,
+		}
+
+		response := core.SendTestAPIRequestFull(test, `courses/assignments/groups/leave`, fields, nil, testCase.email)
+		if !response.Success {
+			if testCase.locator != response.Locator {
+				test.Errorf("Case %d: Incorrect error returned. Expected: '%s', Actual: '%s'.",
+					i, testCase.locator, response.Locator)
+			}
+
+			continue
+		}
+
+		if testCase.locator != "" {
+			test.Errorf("Case %d: Did not get an expected error. Expected: '%s'.", i, testCase.locator)
+			continue
+		}
+
+		var responseContent LeaveResponse
+		util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent)
+
+		if testCase.foundUser != responseContent.FoundUser {
+			test.Errorf("Case %d: Unexpected found user. Expected: '%v', Actual: '%v'.", i, testCase.foundUser, responseContent.FoundUser)
+			continue
+		}
+
+		if testCase.foundGroup != responseContent.FoundGroup {
+			test.Errorf("Case %d: Unexpected found group. Expected: '%v', Actual: '%v'.", i, testCase.foundGroup, responseContent.FoundGroup)
+			continue
+		}
+
+		if !testCase.foundGroup {
+			continue
+		}
+
+		group, err := db.GetAssignmentGroup(db.MustGetTestAssignment(), "existing")
+		if err != nil {
+			test.Errorf("Case %d: Failed to get group: '%v'.", i, err)
+			continue
+		}
+
+		if testCase.expectedMembers == nil {
+			if (group != nil) || (responseContent.Group != nil) {
+				test.Errorf("Case %d: Empty group was not removed.", i)
+			}
+
+			continue
+		}
+
+		if !slices.Equal(testCase.expectedMembers, group.Members) {
+			test.Errorf("Case %d: Unexpected members. Expected: '%v', Actual: '%v'.", i, testCase.expectedMembers, group.Members)
+			continue
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package groups
+
+import (
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+type ListRequest struct {
+	core.APIRequestAssignmentContext
+	core.MinCourseRoleStudent
+}
+
+type ListResponse struct {
+	Groups []*model.AssignmentGroup `json:"groups"`
+}
+
+// List the groups for an assignment. Students will only see their own group.
+func HandleList(request *ListRequest) (*ListResponse, *core.APIError) {
+	apiError := checkGroupAssignment(&request.APIRequestAssignmentContext)
+	if apiError != nil {
+		return nil, apiError
+	}
+
+	groups, apiError := getGroups(&request.APIRequestAssignmentContext)
+	if apiError != nil {
+		return nil, apiError
+	}
+
+	response := ListResponse{
+		Groups: make([]*model.AssignmentGroup, 0, len(groups)),
+	}
+
+	for _, id := range util.GetSortedKeys(groups) {
+		group := groups[id]
+
+		if (request.User.Role < model.CourseRoleGrader) && !group.HasMember(request.User.Email) {
+			continue
+		}
+
+		response.Groups = append(response.Groups, group)
+	}
+
+	return &response, nil
+}
@@ -0,0 +1,81 @@
+package groups
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestListBase(test *testing.T) {
+	defer db.ResetForTesting()
+
+	testCases := []struct {
+		email           string
+		groupAssignment bool
+		expectedIDs     []string
+		locator         string
+	}{
+		{"course-grader", true, []string{"existing", "other"}, ""},
+		{"course-admin", true, []string{"existing", "other"}, ""},
+		{"extra-course-student-2", true, []string{"existing"}, ""},
+		{"course-student", true, []string{"other"}, ""},
+		{"extra-course-student-1", true, []string{}, ""},
+
+		{"course-grader", false, nil, "-648"},
+		{"course-other", true, nil, "-020"},
+	}
+
+	for i, testCase := range testCases {
+		prepGroupAssignment(test, testCase.groupAssignment, false)
+
+		if testCase.groupAssignment {
+			fields := map[string]any{
+				"members": []string{"course-student@test.edulinq.org"},
+			}
+
+			response := core.SendTestAPIRequestFull(test, `courses/assignments/groups/create`, fields, nil, "course-grader")
+			if !response.Success {
+				test.Fatalf("Case %d: Failed to create group: '%s'.", i, response.Locator)
+			}
+		}
+
+		response := core.SendTestAPIRequestFull(test, `courses/assignments/groups/list`, nil, nil, testCase.email)
+		if !response.Success {
+			if testCase.locator != response.Locator {
+				test.Errorf("Case %d: Incorrect error returned. Expected: '%s', Actual: '%s'.",
+					i, testCase.locator, response.Locator)
+			}
+
+			continue
+		}
+
+		if testCase.locator != "" {
+			test.Errorf("Case %d: Did not get an expected error. Expected: '%s'.", i, testCase.locator)
+			continue
+		}
+
+		var responseContent ListResponse
+		util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent)
+
+		// The created group has a random ID.
+		actualIDs := make([]string, 0, len(responseContent.Groups))
+		for _, group := range responseContent.Groups {
+			id := group.ID
+			if id != "existing" {
+				id = "other"
+			}
+
+			actualIDs = append(actualIDs, id)
+		}
+
+		slices.Sort(actualIDs)
+
+		if !slices.Equal(testCase.expectedIDs, actualIDs) {
+			test.Errorf("Case %d: Unexpected groups. Expected: '%v', Actual: '%v'.", i, testCase.expectedIDs, actualIDs)
+			continue
+		}
+	}
+}
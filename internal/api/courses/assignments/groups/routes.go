@@ -0,0 +1,23 @@
+package groups
+
+// All the API endpoints handled by this package.
+
+import (
+	"github.com/edulinq/autograder/internal/api/core"
+)
+
+var baseRoutes []core.Route = []core.Route{
+	core.MustNewAPIRoute(`courses/assignments/groups/create`, HandleCreate),
+	core.MustNewAPIRoute(`courses/assignments/groups/invite`, HandleInvite),
+	core.MustNewAPIRoute(`courses/assignments/groups/join`, HandleJoin),
+	core.MustNewAPIRoute(`courses/assignments/groups/leave`, HandleLeave),
+	core.MustNewAPIRoute(`courses/assignments/groups/list`, HandleList),
+}
+
+func GetRoutes() *[]core.Route {
+	routes := make([]core.Route, 0)
+
+	routes = append(routes, baseRoutes...)
+
+	return &routes
+}
@@ -2,6 +2,8 @@ package assignments
 
 import (
 	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/timestamp"
 )
 
 type ListRequest struct {
@@ -14,9 +16,25 @@ type ListResponse struct {
 }
 
 // List the assignments in the course.
+// Assignments that have not been opened yet are only listed for graders and above.
 func HandleList(request *ListRequest) (*ListResponse, *core.APIError) {
+	assignments := request.Course.GetSortedAssignments()
+
+	if request.User.Role < model.CourseRoleGrader {
+		now := timestamp.Now()
+		visibleAssignments := make([]*model.Assignment, 0, len(assignments))
+
+		for _, assignment := range assignments {
+			if assignment.IsOpen(now) {
+				visibleAssignments = append(visibleAssignments, assignment)
+			}
+		}
+
+		assignments = visibleAssignments
+	}
+
 	response := ListResponse{
-		Assignments: core.NewAssignmentInfos(request.Course.GetSortedAssignments()),
+		Assignments: core.NewAssignmentInfos(assignments),
 	}
 
 	return &response, nil
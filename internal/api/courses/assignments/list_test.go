@@ -7,6 +7,7 @@ import (
 	"github.com/edulinq/autograder/internal/api/core"
 	"github.com/edulinq/autograder/internal/db"
 	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/timestamp"
 	"github.com/edulinq/autograder/internal/util"
 )
 
@@ -87,3 +88,61 @@ func TestList(test *testing.T) {
 		}
 	}
 }
+
+func TestListNotOpen(test *testing.T) {
+	db.ResetForTesting()
+	defer db.ResetForTesting()
+
+	course := db.MustGetCourse("course-languages")
+
+	openDate := timestamp.Now() + timestamp.FromMSecs(60*60*1000)
+	course.OpenDate = &openDate
+
+	// An assignment that overrides the course's open date.
+	assignment := course.GetAssignment("bash")
+	pastOpenDate := timestamp.Zero()
+	assignment.OpenDate = &pastOpenDate
+
+	db.MustSaveCourse(course)
+
+	allIDs := make([]string, 0)
+	for _, assignment := range course.GetSortedAssignments() {
+		allIDs = append(allIDs, assignment.GetID())
+	}
+
+	testCases := []struct {
+		email    string
+		expected []string
+	}{
+		{"course-other@test.edulinq.org", []string{"bash"}},
+		{"course-student@test.edulinq.org", []string{"bash"}},
+		{"course-grader@test.edulinq.org", allIDs},
+		{"course-admin@test.edulinq.org", allIDs},
+		{"server-admin@test.edulinq.org", allIDs},
+	}
+
+	for i, testCase := range testCases {
+		fields := map[string]any{
+			"course-id": "course-languages",
+		}
+
+		response := core.SendTestAPIRequestFull(test, `courses/assignments/list`, fields, nil, testCase.email)
+		if !response.Success {
+			test.Errorf("Case %d: Response is not a success when it should be: '%v'.", i, response)
+			continue
+		}
+
+		var responseContent ListResponse
+		util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent)
+
+		actual := make([]string, 0, len(responseContent.Assignments))
+		for _, info := range responseContent.Assignments {
+			actual = append(actual, info.ID)
+		}
+
+		if !reflect.DeepEqual(testCase.expected, actual) {
+			test.Errorf("Case %d: Unexpected assignments. Expected: '%v', Actual: '%v'.", i, testCase.expected, actual)
+			continue
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package regrades
+
+import (
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/timestamp"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+type FileRequest struct {
+	core.APIRequestAssignmentContext
+	core.MinCourseRoleStudent
+
+	TargetCourseUser core.TargetCourseUserSelfOrGrader `json:"target-email"`
+
+	// Defaults to the most recent submission.
+	TargetSubmission string `json:"target-submission"`
+
+	// The name of the disputed question.
+	Question      core.NonEmptyString `json:"question" required:""`
+	Justification core.NonEmptyString `json:"justification" required:""`
+}
+
+type FileResponse struct {
+	FoundUser       bool `json:"found-user"`
+	FoundSubmission bool `json:"found-submission"`
+
+	Request *model.RegradeRequest `json:"request"`
+}
+
+// File a request to have the score for a question on a submission reviewed by a grader.
+// Only one open request is allowed per question on a submission.
+func HandleFile(request *FileRequest) (*FileResponse, *core.APIError) {
+	response := FileResponse{}
+
+	if !request.TargetCourseUser.Found {
+		return &response, nil
+	}
+
+	response.FoundUser = true
+
+	gradingInfo, err := db.GetSubmissionResult(request.Assignment, request.TargetCourseUser.Email, request.TargetSubmission)
+	if err != nil {
+		return nil, core.NewInternalError("-686", request, "Failed to get submission result.").
+			Err(err).Add("target-user", request.TargetCourseUser.Email).Add("submission", request.TargetSubmission)
+	}
+
+	if gradingInfo == nil {
+		return &response, nil
+	}
+
+	response.FoundSubmission = true
+
+	question := string(request.Question)
+	if gradingInfo.GetQuestion(question) == nil {
+		return nil, core.NewBadRequestError("-687", request, "Submission does not have the given question.").
+			Add("submission", gradingInfo.ID).Add("question", question)
+	}
+
+	requests, err := db.GetRegradeRequests(request.Assignment)
+	if err != nil {
+		return nil, core.NewInternalError("-688", request, "Failed to get regrade requests.").Err(err)
+	}
+
+	for _, regradeRequest := range requests {
+		if regradeRequest.IsOpen() && (regradeRequest.SubmissionID == gradingInfo.ID) && (regradeRequest.Question == question) {
+			return nil, core.NewBadRequestError("-689", request, "There is already an open regrade request for this question.").
+				Add("submission", gradingInfo.ID).Add("question", question).Add("regrade-id", regradeRequest.ID)
+		}
+	}
+
+	regradeRequest := &model.RegradeRequest{
+		ID:            util.UUID(),
+		CourseID:      request.Course.GetID(),
+		AssignmentID:  request.Assignment.GetID(),
+		SubmissionID:  gradingInfo.ID,
+		User:          request.TargetCourseUser.Email,
+		Question:      question,
+		Justification: string(request.Justification),
+		CreateTime:    timestamp.Now(),
+		Status:        model.REGRADE_STATUS_OPEN,
+	}
+
+	err = db.UpsertRegradeRequest(request.Assignment, regradeRequest)
+	if err != nil {
+		return nil, core.NewInternalError("-690", request, "Failed to save regrade request.").
+			Err(err).Add("submission", gradingInfo.ID).Add("question", question)
+	}
+
+	response.Request = regradeRequest
+
+	return &response, nil
+}
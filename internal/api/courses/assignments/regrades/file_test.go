@@ -0,0 +1,147 @@
+package regrades
+
+import (
+	"testing"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+const TEST_SUBMISSION_ID = "course101::hw0::course-student@test.edulinq.org::1697406272"
+
+func TestFileBase(test *testing.T) {
+	defer db.ResetForTesting()
+
+	testCases := []struct {
+		email            string
+		targetEmail      string
+		targetSubmission string
+		question         string
+		foundUser        bool
+		foundSubmission  bool
+		expectedID       string
+		locator          string
+	}{
+		{"course-student", "", "", "Q1", true, true, TEST_SUBMISSION_ID, ""},
+		{"course-student", "", "1697406256", "Q2", true, true, "course101::hw0::course-student@test.edulinq.org::1697406256", ""},
+		{"course-grader", "course-student@test.edulinq.org", "", "Q1", true, true, TEST_SUBMISSION_ID, ""},
+		{"course-grader", "", "", "Q1", true, false, "", ""},
+		{"course-grader", "zzz@test.edulinq.org", "", "Q1", false, false, "", ""},
+
+		// Unknown question.
+		{"course-student", "", "", "zzz", false, false, "", "-687"},
+
+		// Permissions.
+		{"course-student", "course-other@test.edulinq.org", "", "Q1", false, false, "", "-033"},
+		{"server-user", "", "", "Q1", false, false, "", "-040"},
+	}
+
+	for i, testCase := range testCases {
+		db.ResetForTesting()
+
+		fields := map[string]any{
+			"target-email":      testCase.targetEmail,
+			"target-submission": testCase.targetSubmission,
+			"question":          testCase.question,
+			"justification":     "My answer was right.",
+		}
+
+		response := core.SendTestAPIRequestFull(test, `courses/assignments/regrades/file`, fields, nil, testCase.email)
+		if !response.Success {
+			if testCase.locator != response.Locator {
+				test.Errorf("Case %d: Incorrect error returned. Expected: '%s', Actual: '%s'.",
+					i, testCase.locator, response.Locator)
+			}
+
+			continue
+		}
+
+		if testCase.locator != "" {
+			test.Errorf("Case %d: Did not get an expected error. Expected: '%s'.", i, testCase.locator)
+			continue
+		}
+
+		var responseContent FileResponse
+		util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent)
+
+		if (testCase.foundUser != responseContent.FoundUser) || (testCase.foundSubmission != responseContent.FoundSubmission) {
+			test.Errorf("Case %d: Unexpected found flags. Expected: (%v, %v), Actual: (%v, %v).",
+				i, testCase.foundUser, testCase.foundSubmission, responseContent.FoundUser, responseContent.FoundSubmission)
+			continue
+		}
+
+		if !testCase.foundSubmission {
+			if responseContent.Request != nil {
+				test.Errorf("Case %d: Got a regrade request without a submission: '%s'.", i, util.MustToJSONIndent(responseContent.Request))
+			}
+
+			continue
+		}
+
+		request := responseContent.Request
+		if (request == nil) || (request.SubmissionID != testCase.expectedID) || (request.Question != testCase.question) || !request.IsOpen() {
+			test.Errorf("Case %d: Unexpected regrade request: '%s'.", i, util.MustToJSONIndent(request))
+			continue
+		}
+
+		requests, err := db.GetRegradeRequests(db.MustGetTestAssignment())
+		if err != nil {
+			test.Errorf("Case %d: Failed to get regrade requests: '%v'.", i, err)
+			continue
+		}
+
+		if (len(requests) != 1) || (requests[request.ID] == nil) {
+			test.Errorf("Case %d: Regrade request was not saved: '%s'.", i, util.MustToJSONIndent(requests))
+			continue
+		}
+	}
+}
+
+func TestFileDuplicate(test *testing.T) {
+	defer db.ResetForTesting()
+	db.ResetForTesting()
+
+	fields := map[string]any{
+		"question":      "Q1",
+		"justification": "My answer was right.",
+	}
+
+	response := core.SendTestAPIRequestFull(test, `courses/assignments/regrades/file`, fields, nil, "course-student")
+	if !response.Success {
+		test.Fatalf("Failed to file the first regrade request: '%v'.", response)
+	}
+
+	// A second open request for the same question is not allowed.
+	response = core.SendTestAPIRequestFull(test, `courses/assignments/regrades/file`, fields, nil, "course-student")
+	if response.Success {
+		test.Fatalf("Did not get an error when filing a duplicate regrade request.")
+	}
+
+	if response.Locator != "-689" {
+		test.Fatalf("Incorrect error returned. Expected: '-689', Actual: '%s'.", response.Locator)
+	}
+
+	// Once the first request is resolved, the question can be disputed again.
+	assignment := db.MustGetTestAssignment()
+	requests, err := db.GetSortedRegradeRequests(assignment, "")
+	if err != nil {
+		test.Fatalf("Failed to get regrade requests: '%v'.", err)
+	}
+
+	request := requests[0]
+	request.Status = model.REGRADE_STATUS_RESOLVED
+	request.ResolvedBy = "course-grader@test.edulinq.org"
+	request.ResolveTime = &request.CreateTime
+
+	err = db.UpsertRegradeRequest(assignment, request)
+	if err != nil {
+		test.Fatalf("Failed to resolve regrade request: '%v'.", err)
+	}
+
+	response = core.SendTestAPIRequestFull(test, `courses/assignments/regrades/file`, fields, nil, "course-student")
+	if !response.Success {
+		test.Fatalf("Failed to file a regrade request after resolution: '%v'.", response)
+	}
+}
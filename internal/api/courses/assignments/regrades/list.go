@@ -0,0 +1,63 @@
+package regrades
+
+import (
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+)
+
+type ListRequest struct {
+	core.APIRequestAssignmentContext
+	core.MinCourseRoleStudent
+
+	// Include resolved requests in a grader's queue.
+	// Students always see all of their own requests.
+	IncludeResolved bool `json:"include-resolved"`
+}
+
+type ListResponse struct {
+	Requests []*model.RegradeRequest `json:"requests"`
+}
+
+// List the regrade requests for an assignment (oldest first).
+// Graders will see the queue of open requests (for the users in their sections),
+// while students will only see their own requests.
+func HandleList(request *ListRequest) (*ListResponse, *core.APIError) {
+	email := ""
+	if request.User.Role < model.CourseRoleGrader {
+		email = request.User.Email
+	}
+
+	requests, err := db.GetSortedRegradeRequests(request.Assignment, email)
+	if err != nil {
+		return nil, core.NewInternalError("-691", request, "Failed to get regrade requests.").Err(err)
+	}
+
+	var users map[string]*model.CourseUser = nil
+
+	scope := request.User.GetSectionScope()
+	if (email == "") && (scope != nil) {
+		users, err = db.GetCourseUsers(request.Course)
+		if err != nil {
+			return nil, core.NewInternalError("-692", request, "Failed to get course users.").Err(err)
+		}
+	}
+
+	response := ListResponse{
+		Requests: make([]*model.RegradeRequest, 0, len(requests)),
+	}
+
+	for _, regradeRequest := range requests {
+		if (email == "") && !request.IncludeResolved && !regradeRequest.IsOpen() {
+			continue
+		}
+
+		if (users != nil) && !users[regradeRequest.User].InAnySection(scope) {
+			continue
+		}
+
+		response.Requests = append(response.Requests, regradeRequest)
+	}
+
+	return &response, nil
+}
@@ -0,0 +1,154 @@
+package regrades
+
+import (
+	"testing"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/timestamp"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestListBase(test *testing.T) {
+	defer db.ResetForTesting()
+
+	db.ResetForTesting()
+	addTestRequests(test)
+
+	testCases := []struct {
+		email           string
+		includeResolved bool
+		expectedIDs     []string
+		locator         string
+	}{
+		// Graders see the queue of open requests.
+		{"course-grader", false, []string{"aaa", "ccc"}, ""},
+		{"course-admin", true, []string{"aaa", "bbb", "ccc"}, ""},
+
+		// Students only see their own requests.
+		{"course-student", false, []string{"aaa", "bbb"}, ""},
+		{"course-student", true, []string{"aaa", "bbb"}, ""},
+		{"course-other", false, nil, "-020"},
+
+		// Permissions.
+		{"server-user", false, nil, "-040"},
+	}
+
+	for i, testCase := range testCases {
+		fields := map[string]any{
+			"include-resolved": testCase.includeResolved,
+		}
+
+		response := core.SendTestAPIRequestFull(test, `courses/assignments/regrades/list`, fields, nil, testCase.email)
+		if !response.Success {
+			if testCase.locator != response.Locator {
+				test.Errorf("Case %d: Incorrect error returned. Expected: '%s', Actual: '%s'.",
+					i, testCase.locator, response.Locator)
+			}
+
+			continue
+		}
+
+		if testCase.locator != "" {
+			test.Errorf("Case %d: Did not get an expected error. Expected: '%s'.", i, testCase.locator)
+			continue
+		}
+
+		var responseContent ListResponse
+		util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent)
+
+		checkIDs(test, i, testCase.expectedIDs, responseContent.Requests)
+	}
+}
+
+func TestListSections(test *testing.T) {
+	defer db.ResetForTesting()
+
+	db.ResetForTesting()
+	addTestRequests(test)
+
+	db.MustSetTestSections(db.TEST_COURSE_ID, map[string][]string{
+		"course-grader@test.edulinq.org":  []string{"lab-a"},
+		"course-student@test.edulinq.org": []string{"lab-a"},
+		"course-other@test.edulinq.org":   []string{"lab-b"},
+	})
+
+	response := core.SendTestAPIRequestFull(test, `courses/assignments/regrades/list`, nil, nil, "course-grader")
+	if !response.Success {
+		test.Fatalf("Response is not a success when it should be: '%v'.", response)
+	}
+
+	var responseContent ListResponse
+	util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent)
+
+	checkIDs(test, 0, []string{"aaa"}, responseContent.Requests)
+}
+
+func checkIDs(test *testing.T, i int, expectedIDs []string, requests []*model.RegradeRequest) {
+	actualIDs := make([]string, 0, len(requests))
+	for _, request := range requests {
+		actualIDs = append(actualIDs, request.ID)
+	}
+
+	if len(expectedIDs) != len(actualIDs) {
+		test.Errorf("Case %d: Unexpected requests. Expected: '%v', Actual: '%v'.", i, expectedIDs, actualIDs)
+		return
+	}
+
+	for j := range expectedIDs {
+		if expectedIDs[j] != actualIDs[j] {
+			test.Errorf("Case %d: Unexpected requests. Expected: '%v', Actual: '%v'.", i, expectedIDs, actualIDs)
+			return
+		}
+	}
+}
+
+// Add three requests (in creation order):
+// "aaa" (open, course-student, Q1), "bbb" (resolved, course-student, Q2), and "ccc" (open, course-other, Q1).
+func addTestRequests(test *testing.T) {
+	resolveTime := timestamp.FromMSecs(400)
+
+	requests := []*model.RegradeRequest{
+		&model.RegradeRequest{
+			ID:            "aaa",
+			SubmissionID:  TEST_SUBMISSION_ID,
+			User:          "course-student@test.edulinq.org",
+			Question:      "Q1",
+			Justification: "My answer was right.",
+			CreateTime:    timestamp.FromMSecs(100),
+			Status:        model.REGRADE_STATUS_OPEN,
+		},
+		&model.RegradeRequest{
+			ID:              "bbb",
+			SubmissionID:    TEST_SUBMISSION_ID,
+			User:            "course-student@test.edulinq.org",
+			Question:        "Q2",
+			Justification:   "Please check again.",
+			CreateTime:      timestamp.FromMSecs(200),
+			Status:          model.REGRADE_STATUS_RESOLVED,
+			ScoreAdjustment: -0.5,
+			ResolvedBy:      "course-grader@test.edulinq.org",
+			ResolveTime:     &resolveTime,
+		},
+		&model.RegradeRequest{
+			ID:            "ccc",
+			SubmissionID:  "course101::hw0::course-other@test.edulinq.org::1697406300",
+			User:          "course-other@test.edulinq.org",
+			Question:      "Q1",
+			Justification: "Please.",
+			CreateTime:    timestamp.FromMSecs(300),
+			Status:        model.REGRADE_STATUS_OPEN,
+		},
+	}
+
+	for _, request := range requests {
+		request.CourseID = db.TEST_COURSE_ID
+		request.AssignmentID = db.TEST_ASSIGNMENT_ID
+
+		err := db.UpsertRegradeRequest(db.MustGetTestAssignment(), request)
+		if err != nil {
+			test.Fatalf("Failed to add test regrade request '%s': '%v'.", request.ID, err)
+		}
+	}
+}
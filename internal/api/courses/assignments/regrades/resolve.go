@@ -0,0 +1,130 @@
+package regrades
+
+import (
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/email"
+	"github.com/edulinq/autograder/internal/log"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/timestamp"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+type ResolveRequest struct {
+	core.APIRequestAssignmentContext
+	core.MinCourseRoleGrader
+
+	RegradeID core.NonEmptyString `json:"regrade-id" required:""`
+
+	// Points to add to (or remove from) the disputed question's score.
+	// The adjusted score must be between zero and the question's max points.
+	ScoreAdjustment float64 `json:"score-adjustment"`
+
+	Reply string `json:"reply"`
+}
+
+type ResolveResponse struct {
+	FoundRequest bool `json:"found-request"`
+
+	Request *model.RegradeRequest `json:"request"`
+
+	// True if the student was notified about the resolution.
+	Emailed bool `json:"emailed"`
+}
+
+// Resolve an open regrade request with a score adjustment (which may be zero) and a reply.
+// The student that filed the request will be emailed the resolution.
+func HandleResolve(request *ResolveRequest) (*ResolveResponse, *core.APIError) {
+	response := ResolveResponse{}
+
+	regradeRequest, err := db.GetRegradeRequest(request.Assignment, string(request.RegradeID))
+	if err != nil {
+		return nil, core.NewInternalError("-693", request, "Failed to get regrade request.").
+			Err(err).Add("regrade-id", request.RegradeID)
+	}
+
+	if regradeRequest == nil {
+		return &response, nil
+	}
+
+	response.FoundRequest = true
+
+	if !regradeRequest.IsOpen() {
+		return nil, core.NewBadRequestError("-694", request, "Regrade request has already been resolved.").
+			Add("regrade-id", request.RegradeID)
+	}
+
+	// Users limited to their sections can only resolve requests from users in those sections.
+	scope := request.User.GetSectionScope()
+	if scope != nil {
+		targetUser, err := db.GetCourseUser(request.Course, regradeRequest.User)
+		if err != nil {
+			return nil, core.NewInternalError("-695", request, "Failed to get regrade request user.").
+				Err(err).Add("regrade-id", request.RegradeID).Add("target-user", regradeRequest.User)
+		}
+
+		if !targetUser.InAnySection(scope) {
+			return nil, core.NewPermissionsError("-696", request, model.CourseRoleGrader, request.User.Role, "Regrade Request User Outside Of Sections").
+				Add("regrade-id", request.RegradeID).Add("target-user", regradeRequest.User)
+		}
+	}
+
+	gradingInfo, err := db.GetSubmissionResult(request.Assignment, regradeRequest.User, regradeRequest.SubmissionID)
+	if err != nil {
+		return nil, core.NewInternalError("-697", request, "Failed to get submission result.").
+			Err(err).Add("regrade-id", request.RegradeID).Add("submission", regradeRequest.SubmissionID)
+	}
+
+	question := gradingInfo.GetQuestion(regradeRequest.Question)
+	if question == nil {
+		return nil, core.NewBadRequestError("-698", request, "Could not find the disputed question in the submission.").
+			Add("regrade-id", request.RegradeID).Add("submission", regradeRequest.SubmissionID).Add("question", regradeRequest.Question)
+	}
+
+	oldScore := question.Score
+	newScore := oldScore + request.ScoreAdjustment
+
+	if (newScore < 0) || (newScore > question.MaxPoints) {
+		return nil, core.NewBadRequestError("-699", request, "Adjusted question score must be between zero and the question's max points.").
+			Add("regrade-id", request.RegradeID).Add("score", oldScore).Add("score-adjustment", request.ScoreAdjustment).Add("max-points", question.MaxPoints)
+	}
+
+	now := timestamp.Now()
+
+	regradeRequest.Status = model.REGRADE_STATUS_RESOLVED
+	regradeRequest.ScoreAdjustment = request.ScoreAdjustment
+	regradeRequest.Reply = request.Reply
+	regradeRequest.ResolvedBy = request.User.Email
+	regradeRequest.ResolveTime = &now
+
+	err = db.UpsertRegradeRequest(request.Assignment, regradeRequest)
+	if err != nil {
+		return nil, core.NewInternalError("-700", request, "Failed to save regrade request.").
+			Err(err).Add("regrade-id", request.RegradeID)
+	}
+
+	response.Request = regradeRequest
+
+	record := model.NewAuditRecord(model.AuditActionRegradeResolve, regradeRequest.SubmissionID)
+	record.Before = map[string]string{
+		"question": regradeRequest.Question,
+		"score":    util.FloatToStr(oldScore),
+	}
+	record.After = map[string]string{
+		"question":   regradeRequest.Question,
+		"score":      util.FloatToStr(newScore),
+		"regrade-id": regradeRequest.ID,
+	}
+	request.Audit(record)
+
+	// The resolution is already saved, so a failed notification is not an error.
+	err = email.SendMessage(regradeRequest.GetResolutionEmail(request.Assignment.GetDisplayName()))
+	if err != nil {
+		log.Warn("Failed to email regrade request resolution.", err, request.Assignment, log.NewUserAttr(regradeRequest.User),
+			log.NewAttr("regrade-id", regradeRequest.ID))
+	} else {
+		response.Emailed = true
+	}
+
+	return &response, nil
+}
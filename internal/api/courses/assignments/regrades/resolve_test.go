@@ -0,0 +1,138 @@
+package regrades
+
+import (
+	"testing"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/email"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestResolveBase(test *testing.T) {
+	defer db.ResetForTesting()
+
+	testCases := []struct {
+		email         string
+		requestID     string
+		adjustment    float64
+		foundRequest  bool
+		expectedScore float64
+		locator       string
+	}{
+		{"course-grader", "aaa", -0.5, true, 1.5, ""},
+		{"course-admin", "aaa", 0, true, 2, ""},
+		{"course-grader", "aaa", -1, true, 1, ""},
+		{"course-grader", "zzz", 0, false, 0, ""},
+
+		// Already resolved.
+		{"course-grader", "bbb", 0, false, 0, "-694"},
+
+		// Out of bounds.
+		{"course-grader", "aaa", 1, false, 0, "-699"},
+		{"course-grader", "aaa", -1.5, false, 0, "-699"},
+
+		// Permissions.
+		{"course-student", "aaa", 0, false, 0, "-020"},
+	}
+
+	for i, testCase := range testCases {
+		db.ResetForTesting()
+		email.ClearTestMessages()
+		addTestRequests(test)
+
+		fields := map[string]any{
+			"regrade-id":       testCase.requestID,
+			"score-adjustment": testCase.adjustment,
+			"reply":            "Thanks.",
+		}
+
+		response := core.SendTestAPIRequestFull(test, `courses/assignments/regrades/resolve`, fields, nil, testCase.email)
+		if !response.Success {
+			if testCase.locator != response.Locator {
+				test.Errorf("Case %d: Incorrect error returned. Expected: '%s', Actual: '%s'.",
+					i, testCase.locator, response.Locator)
+			}
+
+			continue
+		}
+
+		if testCase.locator != "" {
+			test.Errorf("Case %d: Did not get an expected error. Expected: '%s'.", i, testCase.locator)
+			continue
+		}
+
+		var responseContent ResolveResponse
+		util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent)
+
+		if testCase.foundRequest != responseContent.FoundRequest {
+			test.Errorf("Case %d: Unexpected found flag. Expected: %v, Actual: %v.", i, testCase.foundRequest, responseContent.FoundRequest)
+			continue
+		}
+
+		if !testCase.foundRequest {
+			continue
+		}
+
+		request := responseContent.Request
+		if (request == nil) || request.IsOpen() || (request.ScoreAdjustment != testCase.adjustment) || (request.Reply != "Thanks.") {
+			test.Errorf("Case %d: Unexpected regrade request: '%s'.", i, util.MustToJSONIndent(request))
+			continue
+		}
+
+		gradingInfo, err := db.GetSubmissionResult(db.MustGetTestAssignment(), "course-student@test.edulinq.org", "")
+		if err != nil {
+			test.Errorf("Case %d: Failed to get submission result: '%v'.", i, err)
+			continue
+		}
+
+		// The already resolved request ("bbb") takes half a point from Q2.
+		if !util.IsClose(testCase.expectedScore-0.5, gradingInfo.Score) {
+			test.Errorf("Case %d: Unexpected score. Expected: %v, Actual: %v.", i, testCase.expectedScore-0.5, gradingInfo.Score)
+			continue
+		}
+
+		messages := email.GetTestMessages()
+		if !responseContent.Emailed || (len(messages) != 1) || (messages[0].To[0] != "course-student@test.edulinq.org") {
+			test.Errorf("Case %d: Student was not emailed. Emailed: %v, Messages: '%s'.", i, responseContent.Emailed, util.MustToJSONIndent(messages))
+			continue
+		}
+
+		records, err := db.GetAuditRecords(model.AuditQuery{Action: model.AuditActionRegradeResolve})
+		if err != nil {
+			test.Errorf("Case %d: Failed to get audit records: '%v'.", i, err)
+			continue
+		}
+
+		if (len(records) != 1) || (records[0].Target != TEST_SUBMISSION_ID) || (records[0].Before["score"] != "1") {
+			test.Errorf("Case %d: Unexpected audit records: '%s'.", i, util.MustToJSONIndent(records))
+			continue
+		}
+	}
+}
+
+func TestResolveSections(test *testing.T) {
+	defer db.ResetForTesting()
+
+	db.ResetForTesting()
+	addTestRequests(test)
+
+	db.MustSetTestSections(db.TEST_COURSE_ID, map[string][]string{
+		"course-grader@test.edulinq.org":  []string{"lab-a"},
+		"course-student@test.edulinq.org": []string{"lab-b"},
+	})
+
+	fields := map[string]any{
+		"regrade-id": "aaa",
+	}
+
+	response := core.SendTestAPIRequestFull(test, `courses/assignments/regrades/resolve`, fields, nil, "course-grader")
+	if response.Success {
+		test.Fatalf("Did not get an error when resolving a request outside of the grader's sections.")
+	}
+
+	if response.Locator != "-696" {
+		test.Fatalf("Incorrect error returned. Expected: '-696', Actual: '%s'.", response.Locator)
+	}
+}
@@ -0,0 +1,21 @@
+package regrades
+
+// All the API endpoints handled by this package.
+
+import (
+	"github.com/edulinq/autograder/internal/api/core"
+)
+
+var baseRoutes []core.Route = []core.Route{
+	core.MustNewAPIRoute(`courses/assignments/regrades/file`, HandleFile),
+	core.MustNewAPIRoute(`courses/assignments/regrades/list`, HandleList),
+	core.MustNewAPIRoute(`courses/assignments/regrades/resolve`, HandleResolve),
+}
+
+func GetRoutes() *[]core.Route {
+	routes := make([]core.Route, 0)
+
+	routes = append(routes, baseRoutes...)
+
+	return &routes
+}
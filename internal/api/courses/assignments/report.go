@@ -15,8 +15,9 @@ type CourseReportResponse struct {
 }
 
 // Fetch an assignment grading report for a course.
+// Users limited to their sections (e.g., section graders) will only see a report for students in their sections.
 func HandleCourseReport(request *CourseReportRequest) (*CourseReportResponse, *core.APIError) {
-	courseReport, err := report.GetCourseScoringReport(request.Course)
+	courseReport, err := report.GetSectionCourseScoringReport(request.Course, request.User.GetSectionScope())
 	if err != nil {
 		return nil, core.NewInternalError("-639", request, "Unable to fetch course report.").Err(err)
 	}
@@ -73,3 +73,42 @@ func TestCourseReport(test *testing.T) {
 		}
 	}
 }
+
+func TestCourseReportSections(test *testing.T) {
+	defer db.ResetForTesting()
+
+	db.MustSetTestSections(db.TEST_COURSE_ID, map[string][]string{
+		"course-grader@test.edulinq.org":  []string{"lab-a"},
+		"course-admin@test.edulinq.org":   []string{"lab-a"},
+		"course-student@test.edulinq.org": []string{"lab-b"},
+	})
+
+	testCases := []struct {
+		email               string
+		numberOfSubmissions int
+	}{
+		// The only student is outside of the grader's section.
+		{"course-grader@test.edulinq.org", 0},
+
+		// Admins are never limited to their sections.
+		{"course-admin@test.edulinq.org", 1},
+	}
+
+	for i, testCase := range testCases {
+		response := core.SendTestAPIRequestFull(test, `courses/assignments/report`, nil, nil, testCase.email)
+		if !response.Success {
+			test.Errorf("Case %d: Response is not a success when it should be: '%v'.", i, response)
+			continue
+		}
+
+		var responseContent CourseReportResponse
+		util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent)
+
+		actual := responseContent.CourseReport.Assignments[0].NumberOfSubmissions
+		if testCase.numberOfSubmissions != actual {
+			test.Errorf("Case %d: Unexpected number of submissions. Expected: %d, Actual: %d.",
+				i, testCase.numberOfSubmissions, actual)
+			continue
+		}
+	}
+}
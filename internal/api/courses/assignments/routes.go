@@ -4,7 +4,11 @@ package assignments
 
 import (
 	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/api/courses/assignments/extensions"
+	"github.com/edulinq/autograder/internal/api/courses/assignments/groups"
 	"github.com/edulinq/autograder/internal/api/courses/assignments/images"
+	"github.com/edulinq/autograder/internal/api/courses/assignments/regrades"
+	"github.com/edulinq/autograder/internal/api/courses/assignments/rubric"
 	"github.com/edulinq/autograder/internal/api/courses/assignments/submissions"
 )
 
@@ -18,7 +22,11 @@ func GetRoutes() *[]core.Route {
 	routes := make([]core.Route, 0)
 
 	routes = append(routes, baseRoutes...)
+	routes = append(routes, *(extensions.GetRoutes())...)
+	routes = append(routes, *(groups.GetRoutes())...)
 	routes = append(routes, *(images.GetRoutes())...)
+	routes = append(routes, *(regrades.GetRoutes())...)
+	routes = append(routes, *(rubric.GetRoutes())...)
 	routes = append(routes, *(submissions.GetRoutes())...)
 
 	return &routes
@@ -0,0 +1,71 @@
+package rubric
+
+import (
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+)
+
+type GetRequest struct {
+	core.APIRequestAssignmentContext
+	core.MinCourseRoleGrader
+
+	TargetCourseUser core.TargetCourseUser `json:"target-email" required:""`
+
+	// Defaults to the most recent submission.
+	TargetSubmission string `json:"target-submission"`
+}
+
+type GetResponse struct {
+	FoundUser       bool `json:"found-user"`
+	FoundSubmission bool `json:"found-submission"`
+
+	Rubric       *model.Rubric `json:"rubric"`
+	SubmissionID string        `json:"submission-id"`
+
+	// Nil if the submission has not been graded yet.
+	Grade *model.RubricGrade `json:"grade"`
+
+	// True if every rubric criterion has been scored for the submission.
+	Complete bool `json:"complete"`
+}
+
+// Get an assignment's rubric and a submission's grade against it.
+func HandleGet(request *GetRequest) (*GetResponse, *core.APIError) {
+	if request.Assignment.Rubric == nil {
+		return nil, core.NewBadRequestError("-675", request, "Assignment does not have a rubric.")
+	}
+
+	response := GetResponse{
+		Rubric: request.Assignment.Rubric,
+	}
+
+	if !request.TargetCourseUser.Found {
+		return &response, nil
+	}
+
+	response.FoundUser = true
+
+	gradingInfo, err := db.GetSubmissionResult(request.Assignment, request.TargetCourseUser.Email, request.TargetSubmission)
+	if err != nil {
+		return nil, core.NewInternalError("-676", request, "Failed to get submission result.").
+			Err(err).Add("target-user", request.TargetCourseUser.Email).Add("submission", request.TargetSubmission)
+	}
+
+	if gradingInfo == nil {
+		return &response, nil
+	}
+
+	response.FoundSubmission = true
+	response.SubmissionID = gradingInfo.ID
+
+	response.Grade, err = db.GetRubricGrade(request.Assignment, gradingInfo.ID)
+	if err != nil {
+		return nil, core.NewInternalError("-677", request, "Failed to get rubric grade.").
+			Err(err).Add("target-user", request.TargetCourseUser.Email).Add("submission", gradingInfo.ID)
+	}
+
+	response.Complete = response.Grade.IsComplete(request.Assignment.Rubric)
+
+	return &response, nil
+}
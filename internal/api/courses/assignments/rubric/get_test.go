@@ -0,0 +1,179 @@
+package rubric
+
+import (
+	"testing"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestGetBase(test *testing.T) {
+	defer db.ResetForTesting()
+
+	db.ResetForTesting()
+	assignment := db.MustSetTestRubric(db.TEST_COURSE_ID, db.TEST_ASSIGNMENT_ID)
+	addTestGrade(test, assignment)
+
+	testCases := []struct {
+		email            string
+		targetEmail      string
+		targetSubmission string
+		foundUser        bool
+		foundSubmission  bool
+		expectedID       string
+		hasGrade         bool
+		locator          string
+	}{
+		{"course-grader", "course-student@test.edulinq.org", "", true, true, TEST_SUBMISSION_ID, true, ""},
+		{"course-admin", "course-student@test.edulinq.org", "1697406256", true, true, "course101::hw0::course-student@test.edulinq.org::1697406256", false, ""},
+		{"course-grader", "course-grader@test.edulinq.org", "", true, false, "", false, ""},
+		{"course-grader", "zzz@test.edulinq.org", "", false, false, "", false, ""},
+
+		// Permissions.
+		{"course-student", "course-student@test.edulinq.org", "", false, false, "", false, "-020"},
+		{"server-user", "course-student@test.edulinq.org", "", false, false, "", false, "-040"},
+	}
+
+	for i, testCase := range testCases {
+		fields := map[string]any{
+			"target-email":      testCase.targetEmail,
+			"target-submission": testCase.targetSubmission,
+		}
+
+		response := core.SendTestAPIRequestFull(test, `courses/assignments/rubric/get`, fields, nil, testCase.email)
+		if !response.Success {
+			if testCase.locator != response.Locator {
+				test.Errorf("Case %d: Incorrect error returned. Expected: '%s', Actual: '%s'.",
+					i, testCase.locator, response.Locator)
+			}
+
+			continue
+		}
+
+		if testCase.locator != "" {
+			test.Errorf("Case %d: Did not get an expected error. Expected: '%s'.", i, testCase.locator)
+			continue
+		}
+
+		var responseContent GetResponse
+		util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent)
+
+		if (testCase.foundUser != responseContent.FoundUser) || (testCase.foundSubmission != responseContent.FoundSubmission) {
+			test.Errorf("Case %d: Unexpected found flags. Expected: (%v, %v), Actual: (%v, %v).",
+				i, testCase.foundUser, testCase.foundSubmission, responseContent.FoundUser, responseContent.FoundSubmission)
+			continue
+		}
+
+		if (responseContent.Rubric == nil) || (len(responseContent.Rubric.Criteria) != 2) {
+			test.Errorf("Case %d: Unexpected rubric: '%s'.", i, util.MustToJSONIndent(responseContent.Rubric))
+			continue
+		}
+
+		if testCase.expectedID != responseContent.SubmissionID {
+			test.Errorf("Case %d: Unexpected submission ID. Expected: '%s', Actual: '%s'.", i, testCase.expectedID, responseContent.SubmissionID)
+			continue
+		}
+
+		if testCase.hasGrade != (responseContent.Grade != nil) {
+			test.Errorf("Case %d: Unexpected grade presence. Expected: %v, Actual: '%s'.", i, testCase.hasGrade, util.MustToJSONIndent(responseContent.Grade))
+			continue
+		}
+	}
+}
+
+func TestGetNoRubric(test *testing.T) {
+	fields := map[string]any{
+		"target-email": "course-student@test.edulinq.org",
+	}
+
+	response := core.SendTestAPIRequestFull(test, `courses/assignments/rubric/get`, fields, nil, "course-grader")
+	if response.Success {
+		test.Fatalf("Did not get an error for an assignment without a rubric.")
+	}
+
+	if response.Locator != "-675" {
+		test.Fatalf("Incorrect error returned. Expected: '-675', Actual: '%s'.", response.Locator)
+	}
+}
+
+func addTestGrade(test *testing.T, assignment *model.Assignment) {
+	grade := &model.RubricGrade{
+		CourseID:     db.TEST_COURSE_ID,
+		AssignmentID: db.TEST_ASSIGNMENT_ID,
+		SubmissionID: TEST_SUBMISSION_ID,
+		User:         "course-student@test.edulinq.org",
+		Scores: map[string]*model.RubricCriterionScore{
+			"Style": &model.RubricCriterionScore{Score: 1},
+		},
+		GradedBy: "course-grader@test.edulinq.org",
+	}
+
+	err := db.UpsertRubricGrade(assignment, grade)
+	if err != nil {
+		test.Fatalf("Failed to add test rubric grade: '%v'.", err)
+	}
+}
+
+func TestRubricSections(test *testing.T) {
+	defer db.ResetForTesting()
+
+	db.ResetForTesting()
+	assignment := db.MustSetTestRubric(db.TEST_COURSE_ID, db.TEST_ASSIGNMENT_ID)
+	addTestGrade(test, assignment)
+
+	db.MustSetTestSections(db.TEST_COURSE_ID, map[string][]string{
+		"course-grader@test.edulinq.org":  []string{"lab-a"},
+		"course-student@test.edulinq.org": []string{"lab-b"},
+	})
+
+	testCases := []struct {
+		email    string
+		endpoint string
+		fields   map[string]any
+		locator  string
+	}{
+		// Graders cannot target users outside their sections.
+		{"course-grader", `courses/assignments/rubric/get`, map[string]any{"target-email": "course-student@test.edulinq.org"}, "-073"},
+		{
+			"course-grader", `courses/assignments/rubric/grade`,
+			map[string]any{"target-email": "course-student@test.edulinq.org", "scores": map[string]any{"Style": map[string]any{"score": 2}}},
+			"-073",
+		},
+		{"course-grader", `courses/assignments/rubric/remove`, map[string]any{"target-email": "course-student@test.edulinq.org"}, "-073"},
+
+		// Graders can still target themselves.
+		{"course-grader", `courses/assignments/rubric/get`, map[string]any{"target-email": "course-grader@test.edulinq.org"}, ""},
+
+		// Admins are not limited to sections.
+		{"course-admin", `courses/assignments/rubric/get`, map[string]any{"target-email": "course-student@test.edulinq.org"}, ""},
+	}
+
+	for i, testCase := range testCases {
+		response := core.SendTestAPIRequestFull(test, testCase.endpoint, testCase.fields, nil, testCase.email)
+		if !response.Success {
+			if testCase.locator != response.Locator {
+				test.Errorf("Case %d: Incorrect error returned. Expected: '%s', Actual: '%s'.",
+					i, testCase.locator, response.Locator)
+			}
+
+			continue
+		}
+
+		if testCase.locator != "" {
+			test.Errorf("Case %d: Did not get an expected error. Expected: '%s'.", i, testCase.locator)
+			continue
+		}
+	}
+
+	// The grade from outside the section should not have been changed or removed.
+	grade, err := db.GetRubricGrade(assignment, TEST_SUBMISSION_ID)
+	if err != nil {
+		test.Fatalf("Failed to get rubric grade: '%v'.", err)
+	}
+
+	if (grade == nil) || (grade.Scores["Style"].Score != 1) {
+		test.Fatalf("Rubric grade was changed by a grader outside of the section: '%s'.", util.MustToJSONIndent(grade))
+	}
+}
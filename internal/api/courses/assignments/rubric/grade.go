@@ -0,0 +1,109 @@
+package rubric
+
+import (
+	"fmt"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/timestamp"
+)
+
+type GradeRequest struct {
+	core.APIRequestAssignmentContext
+	core.MinCourseRoleGrader
+
+	TargetCourseUser core.TargetCourseUser `json:"target-email" required:""`
+
+	// Defaults to the most recent submission.
+	TargetSubmission string `json:"target-submission"`
+
+	// Scores (keyed by criterion name) to set for the submission.
+	// Criteria that are not included keep any existing score.
+	Scores map[string]*model.RubricCriterionScore `json:"scores" required:""`
+}
+
+type GradeResponse struct {
+	FoundUser       bool `json:"found-user"`
+	FoundSubmission bool `json:"found-submission"`
+
+	Grade *model.RubricGrade `json:"grade"`
+
+	// True if every rubric criterion has been scored for the submission.
+	Complete bool `json:"complete"`
+}
+
+// Score a submission against the assignment's rubric.
+// Grading can be done over multiple requests, each setting the scores for some criteria.
+func HandleGrade(request *GradeRequest) (*GradeResponse, *core.APIError) {
+	if request.Assignment.Rubric == nil {
+		return nil, core.NewBadRequestError("-678", request, "Assignment does not have a rubric.")
+	}
+
+	response := GradeResponse{}
+
+	if !request.TargetCourseUser.Found {
+		return &response, nil
+	}
+
+	response.FoundUser = true
+
+	gradingInfo, err := db.GetSubmissionResult(request.Assignment, request.TargetCourseUser.Email, request.TargetSubmission)
+	if err != nil {
+		return nil, core.NewInternalError("-679", request, "Failed to get submission result.").
+			Err(err).Add("target-user", request.TargetCourseUser.Email).Add("submission", request.TargetSubmission)
+	}
+
+	if gradingInfo == nil {
+		return &response, nil
+	}
+
+	response.FoundSubmission = true
+
+	grade, err := db.GetRubricGrade(request.Assignment, gradingInfo.ID)
+	if err != nil {
+		return nil, core.NewInternalError("-680", request, "Failed to get rubric grade.").
+			Err(err).Add("target-user", request.TargetCourseUser.Email).Add("submission", gradingInfo.ID)
+	}
+
+	if grade == nil {
+		grade = &model.RubricGrade{
+			CourseID:     request.Course.GetID(),
+			AssignmentID: request.Assignment.GetID(),
+			SubmissionID: gradingInfo.ID,
+			User:         gradingInfo.User,
+			Scores:       make(map[string]*model.RubricCriterionScore, len(request.Scores)),
+		}
+	}
+
+	for name, score := range request.Scores {
+		grade.Scores[name] = score
+	}
+
+	grade.GradedBy = request.User.Email
+	grade.UpdateTime = timestamp.Now()
+
+	err = grade.Validate(request.Assignment.Rubric)
+	if err != nil {
+		return nil, core.NewBadRequestError("-681", request, "Invalid rubric grade.").
+			Err(err).Add("target-user", request.TargetCourseUser.Email).Add("submission", gradingInfo.ID)
+	}
+
+	err = db.UpsertRubricGrade(request.Assignment, grade)
+	if err != nil {
+		return nil, core.NewInternalError("-682", request, "Failed to save rubric grade.").
+			Err(err).Add("target-user", request.TargetCourseUser.Email).Add("submission", gradingInfo.ID)
+	}
+
+	response.Grade = grade
+	response.Complete = grade.IsComplete(request.Assignment.Rubric)
+
+	record := model.NewAuditRecord(model.AuditActionRubricGrade, request.TargetCourseUser.Email)
+	record.After = map[string]string{
+		"submission": gradingInfo.ID,
+		"complete":   fmt.Sprintf("%v", response.Complete),
+	}
+	request.Audit(record)
+
+	return &response, nil
+}
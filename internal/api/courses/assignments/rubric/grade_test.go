@@ -0,0 +1,170 @@
+package rubric
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+const TEST_SUBMISSION_ID = "course101::hw0::course-student@test.edulinq.org::1697406272"
+
+func TestGradeBase(test *testing.T) {
+	defer db.ResetForTesting()
+
+	testCases := []struct {
+		email            string
+		setRubric        bool
+		fields           map[string]any
+		foundUser        bool
+		foundSubmission  bool
+		expectedScores   map[string]float64
+		expectedComplete bool
+		locator          string
+	}{
+		// Partial and full grades.
+		{
+			"course-grader", true,
+			map[string]any{"target-email": "course-student@test.edulinq.org", "scores": map[string]any{"Style": map[string]any{"score": 1}}},
+			true, true, map[string]float64{"Style": 1}, false, "",
+		},
+		{
+			"course-admin", true,
+			map[string]any{"target-email": "course-student@test.edulinq.org", "scores": map[string]any{"Style": map[string]any{"score": 2}, "Design": map[string]any{"score": 3, "comment": "Nice."}}},
+			true, true, map[string]float64{"Style": 2, "Design": 3}, true, "",
+		},
+		{
+			"course-grader", true,
+			map[string]any{"target-email": "course-student@test.edulinq.org", "target-submission": "1697406256", "scores": map[string]any{"Design": map[string]any{"score": 0}}},
+			true, true, map[string]float64{"Design": 0}, false, "",
+		},
+
+		// Missing user and submission.
+		{"course-grader", true, map[string]any{"target-email": "zzz@test.edulinq.org", "scores": map[string]any{}}, false, false, nil, false, ""},
+		{"course-grader", true, map[string]any{"target-email": "course-grader@test.edulinq.org", "scores": map[string]any{}}, true, false, nil, false, ""},
+
+		// Invalid grades.
+		{"course-grader", true, map[string]any{"target-email": "course-student@test.edulinq.org", "scores": map[string]any{"ZZZ": map[string]any{"score": 1}}}, true, true, nil, false, "-681"},
+		{"course-grader", true, map[string]any{"target-email": "course-student@test.edulinq.org", "scores": map[string]any{"Style": map[string]any{"score": 3}}}, true, true, nil, false, "-681"},
+
+		// No rubric.
+		{"course-grader", false, map[string]any{"target-email": "course-student@test.edulinq.org", "scores": map[string]any{"Style": map[string]any{"score": 1}}}, false, false, nil, false, "-678"},
+
+		// Permissions.
+		{"course-student", true, map[string]any{"target-email": "course-student@test.edulinq.org", "scores": map[string]any{}}, false, false, nil, false, "-020"},
+		{"server-user", true, map[string]any{"target-email": "course-student@test.edulinq.org", "scores": map[string]any{}}, false, false, nil, false, "-040"},
+	}
+
+	for i, testCase := range testCases {
+		db.ResetForTesting()
+
+		if testCase.setRubric {
+			db.MustSetTestRubric(db.TEST_COURSE_ID, db.TEST_ASSIGNMENT_ID)
+		}
+
+		response := core.SendTestAPIRequestFull(test, `courses/assignments/rubric/grade`, testCase.fields, nil, testCase.email)
+		if !response.Success {
+			if testCase.locator != response.Locator {
+				test.Errorf("Case %d: Incorrect error returned. Expected: '%s', Actual: '%s'.",
+					i, testCase.locator, response.Locator)
+			}
+
+			continue
+		}
+
+		if testCase.locator != "" {
+			test.Errorf("Case %d: Did not get an expected error. Expected: '%s'.", i, testCase.locator)
+			continue
+		}
+
+		var responseContent GradeResponse
+		util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent)
+
+		if (testCase.foundUser != responseContent.FoundUser) || (testCase.foundSubmission != responseContent.FoundSubmission) {
+			test.Errorf("Case %d: Unexpected found flags. Expected: (%v, %v), Actual: (%v, %v).",
+				i, testCase.foundUser, testCase.foundSubmission, responseContent.FoundUser, responseContent.FoundSubmission)
+			continue
+		}
+
+		if !testCase.foundSubmission {
+			continue
+		}
+
+		if testCase.expectedComplete != responseContent.Complete {
+			test.Errorf("Case %d: Unexpected complete. Expected: %v, Actual: %v.", i, testCase.expectedComplete, responseContent.Complete)
+			continue
+		}
+
+		grade, err := db.GetRubricGrade(db.MustGetTestAssignment(), responseContent.Grade.SubmissionID)
+		if err != nil {
+			test.Errorf("Case %d: Failed to get rubric grade: '%v'.", i, err)
+			continue
+		}
+
+		if grade == nil {
+			test.Errorf("Case %d: Rubric grade was not saved.", i)
+			continue
+		}
+
+		scores := make(map[string]float64, len(grade.Scores))
+		for name, score := range grade.Scores {
+			scores[name] = score.Score
+		}
+
+		if !reflect.DeepEqual(testCase.expectedScores, scores) {
+			test.Errorf("Case %d: Unexpected scores. Expected: '%v', Actual: '%v'.", i, testCase.expectedScores, scores)
+			continue
+		}
+
+		if grade.GradedBy != (testCase.email + "@test.edulinq.org") {
+			test.Errorf("Case %d: Unexpected grader. Expected: '%s', Actual: '%s'.", i, testCase.email, grade.GradedBy)
+			continue
+		}
+	}
+}
+
+// Grading over multiple requests keeps the scores of earlier requests.
+func TestGradeMerge(test *testing.T) {
+	defer db.ResetForTesting()
+
+	db.ResetForTesting()
+	assignment := db.MustSetTestRubric(db.TEST_COURSE_ID, db.TEST_ASSIGNMENT_ID)
+
+	requests := []map[string]any{
+		map[string]any{"Style": map[string]any{"score": 1.5}},
+		map[string]any{"Design": map[string]any{"score": 2, "comment": "Good."}},
+	}
+
+	for _, scores := range requests {
+		fields := map[string]any{
+			"target-email": "course-student@test.edulinq.org",
+			"scores":       scores,
+		}
+
+		response := core.SendTestAPIRequestFull(test, `courses/assignments/rubric/grade`, fields, nil, "course-grader")
+		if !response.Success {
+			test.Fatalf("Failed to grade: '%s'.", util.MustToJSONIndent(response))
+		}
+	}
+
+	expected := map[string]*model.RubricCriterionScore{
+		"Style":  &model.RubricCriterionScore{Score: 1.5},
+		"Design": &model.RubricCriterionScore{Score: 2, Comment: "Good."},
+	}
+
+	grade, err := db.GetRubricGrade(assignment, TEST_SUBMISSION_ID)
+	if err != nil {
+		test.Fatalf("Failed to get rubric grade: '%v'.", err)
+	}
+
+	if util.MustToJSON(expected) != util.MustToJSON(grade.Scores) {
+		test.Fatalf("Unexpected scores. Expected: '%s', Actual: '%s'.", util.MustToJSONIndent(expected), util.MustToJSONIndent(grade.Scores))
+	}
+
+	if !grade.IsComplete(assignment.Rubric) {
+		test.Fatalf("Grade is not complete.")
+	}
+}
@@ -0,0 +1,66 @@
+package rubric
+
+import (
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+)
+
+type RemoveRequest struct {
+	core.APIRequestAssignmentContext
+	core.MinCourseRoleGrader
+
+	TargetCourseUser core.TargetCourseUser `json:"target-email" required:""`
+
+	// Defaults to the most recent submission.
+	TargetSubmission string `json:"target-submission"`
+}
+
+type RemoveResponse struct {
+	FoundUser       bool `json:"found-user"`
+	FoundSubmission bool `json:"found-submission"`
+	FoundGrade      bool `json:"found-grade"`
+}
+
+// Remove a submission's rubric grade (marking the submission as ungraded).
+func HandleRemove(request *RemoveRequest) (*RemoveResponse, *core.APIError) {
+	if request.Assignment.Rubric == nil {
+		return nil, core.NewBadRequestError("-683", request, "Assignment does not have a rubric.")
+	}
+
+	response := RemoveResponse{}
+
+	if !request.TargetCourseUser.Found {
+		return &response, nil
+	}
+
+	response.FoundUser = true
+
+	gradingInfo, err := db.GetSubmissionResult(request.Assignment, request.TargetCourseUser.Email, request.TargetSubmission)
+	if err != nil {
+		return nil, core.NewInternalError("-684", request, "Failed to get submission result.").
+			Err(err).Add("target-user", request.TargetCourseUser.Email).Add("submission", request.TargetSubmission)
+	}
+
+	if gradingInfo == nil {
+		return &response, nil
+	}
+
+	response.FoundSubmission = true
+
+	response.FoundGrade, err = db.RemoveRubricGrade(request.Assignment, gradingInfo.ID)
+	if err != nil {
+		return nil, core.NewInternalError("-685", request, "Failed to remove rubric grade.").
+			Err(err).Add("target-user", request.TargetCourseUser.Email).Add("submission", gradingInfo.ID)
+	}
+
+	if response.FoundGrade {
+		record := model.NewAuditRecord(model.AuditActionRubricRemove, request.TargetCourseUser.Email)
+		record.Before = map[string]string{
+			"submission": gradingInfo.ID,
+		}
+		request.Audit(record)
+	}
+
+	return &response, nil
+}
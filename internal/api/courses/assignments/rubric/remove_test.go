@@ -0,0 +1,91 @@
+package rubric
+
+import (
+	"testing"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestRemoveBase(test *testing.T) {
+	defer db.ResetForTesting()
+
+	testCases := []struct {
+		email            string
+		targetEmail      string
+		targetSubmission string
+		expected         RemoveResponse
+		locator          string
+	}{
+		{"course-grader", "course-student@test.edulinq.org", "", RemoveResponse{true, true, true}, ""},
+		{"course-admin", "course-student@test.edulinq.org", "1697406256", RemoveResponse{true, true, false}, ""},
+		{"course-grader", "course-grader@test.edulinq.org", "", RemoveResponse{true, false, false}, ""},
+		{"course-grader", "zzz@test.edulinq.org", "", RemoveResponse{false, false, false}, ""},
+
+		// Permissions.
+		{"course-student", "course-student@test.edulinq.org", "", RemoveResponse{}, "-020"},
+		{"server-user", "course-student@test.edulinq.org", "", RemoveResponse{}, "-040"},
+	}
+
+	for i, testCase := range testCases {
+		db.ResetForTesting()
+		assignment := db.MustSetTestRubric(db.TEST_COURSE_ID, db.TEST_ASSIGNMENT_ID)
+		addTestGrade(test, assignment)
+
+		fields := map[string]any{
+			"target-email":      testCase.targetEmail,
+			"target-submission": testCase.targetSubmission,
+		}
+
+		response := core.SendTestAPIRequestFull(test, `courses/assignments/rubric/remove`, fields, nil, testCase.email)
+		if !response.Success {
+			if testCase.locator != response.Locator {
+				test.Errorf("Case %d: Incorrect error returned. Expected: '%s', Actual: '%s'.",
+					i, testCase.locator, response.Locator)
+			}
+
+			continue
+		}
+
+		if testCase.locator != "" {
+			test.Errorf("Case %d: Did not get an expected error. Expected: '%s'.", i, testCase.locator)
+			continue
+		}
+
+		var responseContent RemoveResponse
+		util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent)
+
+		if testCase.expected != responseContent {
+			test.Errorf("Case %d: Unexpected response. Expected: '%+v', Actual: '%+v'.", i, testCase.expected, responseContent)
+			continue
+		}
+
+		grade, err := db.GetRubricGrade(assignment, TEST_SUBMISSION_ID)
+		if err != nil {
+			test.Errorf("Case %d: Failed to get rubric grade: '%v'.", i, err)
+			continue
+		}
+
+		if testCase.expected.FoundGrade != (grade == nil) {
+			test.Errorf("Case %d: Unexpected rubric grade state after removal: '%s'.", i, util.MustToJSONIndent(grade))
+			continue
+		}
+
+		if !testCase.expected.FoundGrade {
+			continue
+		}
+
+		records, err := db.GetAuditRecords(model.AuditQuery{Action: model.AuditActionRubricRemove})
+		if err != nil {
+			test.Errorf("Case %d: Failed to get audit records: '%v'.", i, err)
+			continue
+		}
+
+		if len(records) != 1 {
+			test.Errorf("Case %d: Unexpected number of audit records. Expected: 1, Actual: %d.", i, len(records))
+			continue
+		}
+	}
+}
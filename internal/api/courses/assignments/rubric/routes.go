@@ -0,0 +1,21 @@
+package rubric
+
+// All the API endpoints handled by this package.
+
+import (
+	"github.com/edulinq/autograder/internal/api/core"
+)
+
+var baseRoutes []core.Route = []core.Route{
+	core.MustNewAPIRoute(`courses/assignments/rubric/get`, HandleGet),
+	core.MustNewAPIRoute(`courses/assignments/rubric/grade`, HandleGrade),
+	core.MustNewAPIRoute(`courses/assignments/rubric/remove`, HandleRemove),
+}
+
+func GetRoutes() *[]core.Route {
+	routes := make([]core.Route, 0)
+
+	routes = append(routes, baseRoutes...)
+
+	return &routes
+}
@@ -1,6 +1,10 @@
 package analysis
 
 import (
+	"fmt"
+
+	"github.com/edulinq/autograder/internal/common"
+	"github.com/edulinq/autograder/internal/db"
 	"github.com/edulinq/autograder/internal/model"
 )
 
@@ -19,3 +23,47 @@ func checkPermissions(user *model.ServerUser, courses []string) bool {
 
 	return true
 }
+
+// Check that every submission belongs to a user in the requester's sections (for any course where the requester is limited to their sections).
+// Returns the first submission the user cannot see (or an empty string if all submissions are allowed).
+func checkSectionPermissions(user *model.ServerUser, fullSubmissionIDs []string) (string, error) {
+	if user.Role >= model.ServerRoleAdmin {
+		return "", nil
+	}
+
+	for _, fullSubmissionID := range fullSubmissionIDs {
+		courseID, _, email, _, err := common.SplitFullSubmissionID(fullSubmissionID)
+		if err != nil {
+			return "", err
+		}
+
+		courseUser, err := user.ToCourseUser(courseID, false)
+		if err != nil {
+			return "", fmt.Errorf("Failed to convert user '%s' into a course user: '%w'.", user.Email, err)
+		}
+
+		scope := courseUser.GetSectionScope()
+		if scope == nil {
+			continue
+		}
+
+		targetUser, err := db.GetServerUser(email)
+		if err != nil {
+			return "", fmt.Errorf("Failed to get submission user '%s': '%w'.", email, err)
+		}
+
+		var targetCourseUser *model.CourseUser = nil
+		if targetUser != nil {
+			targetCourseUser, err = targetUser.ToCourseUser(courseID, false)
+			if err != nil {
+				return "", fmt.Errorf("Failed to convert user '%s' into a course user: '%w'.", email, err)
+			}
+		}
+
+		if !targetCourseUser.InAnySection(scope) {
+			return fullSubmissionID, nil
+		}
+	}
+
+	return "", nil
+}
@@ -43,6 +43,16 @@ func HandleIndividual(request *IndividualRequest) (*IndividualResponse, *core.AP
 			"User does not have permissions (server admin or course admin in all present courses.")
 	}
 
+	deniedSubmissionID, err := checkSectionPermissions(request.ServerUser, fullSubmissionIDs)
+	if err != nil {
+		return nil, core.NewInternalError("-667", request, "Failed to check section permissions.").Err(err)
+	}
+
+	if deniedSubmissionID != "" {
+		return nil, core.NewBadRequestError("-668", request,
+			fmt.Sprintf("User does not have permissions for a submission outside of their sections: '%s'.", deniedSubmissionID))
+	}
+
 	request.ResolvedSubmissionIDs = fullSubmissionIDs
 	request.InitiatorEmail = request.ServerUser.Email
 	request.AnalysisOptions.Context = request.APIRequestUserContext.Context
@@ -274,3 +274,44 @@ func TestIndividualCheckPermissions(test *testing.T) {
 		}
 	}
 }
+
+func TestIndividualCheckSectionPermissions(test *testing.T) {
+	defer db.ResetForTesting()
+
+	db.MustSetTestSections(db.TEST_COURSE_ID, map[string][]string{
+		"course-grader@test.edulinq.org":  []string{"lab-a"},
+		"course-admin@test.edulinq.org":   []string{"lab-a"},
+		"course-student@test.edulinq.org": []string{"lab-b"},
+		"course-other@test.edulinq.org":   []string{"lab-a"},
+	})
+
+	studentSubmission := "course101::hw0::course-student@test.edulinq.org::1697406256"
+	otherSubmission := "course101::hw0::course-other@test.edulinq.org::1697406256"
+	languagesSubmission := "course-languages::bash::course-student@test.edulinq.org::1697406256"
+
+	testCases := []struct {
+		email         string
+		submissionIDs []string
+		expected      string
+	}{
+		{"server-admin@test.edulinq.org", []string{studentSubmission, otherSubmission}, ""},
+		{"course-admin@test.edulinq.org", []string{studentSubmission, otherSubmission}, ""},
+		{"course-grader@test.edulinq.org", []string{otherSubmission}, ""},
+		{"course-grader@test.edulinq.org", []string{otherSubmission, studentSubmission}, studentSubmission},
+
+		// The grader has no sections in this course.
+		{"course-grader@test.edulinq.org", []string{languagesSubmission}, ""},
+	}
+
+	for i, testCase := range testCases {
+		actual, err := checkSectionPermissions(db.MustGetServerUser(testCase.email), testCase.submissionIDs)
+		if err != nil {
+			test.Errorf("Case %d: Failed to check permissions: '%v'.", i, err)
+			continue
+		}
+
+		if testCase.expected != actual {
+			test.Errorf("Case %d: Incorrect. Expected: '%s', Actual: '%s'.", i, testCase.expected, actual)
+		}
+	}
+}
@@ -30,6 +30,9 @@ func HandleFetchCourseAttempts(request *FetchCourseAttemptsRequest) (*FetchCours
 		return nil, core.NewBadRequestError("-637", request, "Failed to parse target users.").Err(err)
 	}
 
+	// Users limited to their sections (e.g., section graders) can only see users in those sections.
+	reference.RestrictToSections(request.User.GetSectionScope())
+
 	results, err := db.GetRecentSubmissionContents(request.Assignment, reference)
 	if err != nil {
 		return nil, core.NewInternalError("-605", request, "Failed to get submissions.").Err(err)
@@ -30,6 +30,9 @@ func HandleFetchCourseScores(request *FetchCourseScoresRequest) (*FetchCourseSco
 		return nil, core.NewBadRequestError("-636", request, "Failed to parse target users.").Err(err)
 	}
 
+	// Users limited to their sections (e.g., section graders) can only see users in those sections.
+	reference.RestrictToSections(request.User.GetSectionScope())
+
 	submissionInfos, err := db.GetRecentSubmissionSurvey(request.Assignment, reference)
 	if err != nil {
 		return nil, core.NewInternalError("-602", request, "Failed to get submission summaries.").Err(err)
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
 	"github.com/edulinq/autograder/internal/model"
 	"github.com/edulinq/autograder/internal/util"
 )
@@ -126,3 +127,71 @@ func TestFetchCourseScores(test *testing.T) {
 		}
 	}
 }
+
+func TestFetchCourseScoresSections(test *testing.T) {
+	defer db.ResetForTesting()
+
+	db.MustSetTestSections(db.TEST_COURSE_ID, map[string][]string{
+		"course-grader@test.edulinq.org":  []string{"lab-a"},
+		"course-student@test.edulinq.org": []string{"lab-a"},
+		"course-other@test.edulinq.org":   []string{"lab-b"},
+	})
+
+	testCases := []struct {
+		email       string
+		targetUsers []model.CourseUserReference
+		ids         map[string]string
+	}{
+		// Section graders only see their sections.
+		{"course-grader", nil, map[string]string{
+			"course-student@test.edulinq.org": "course101::hw0::course-student@test.edulinq.org::1697406272",
+			"course-grader@test.edulinq.org":  "",
+		}},
+		{"course-grader", []model.CourseUserReference{"section:lab-a"}, map[string]string{
+			"course-student@test.edulinq.org": "course101::hw0::course-student@test.edulinq.org::1697406272",
+			"course-grader@test.edulinq.org":  "",
+		}},
+		{"course-grader", []model.CourseUserReference{"section:lab-b"}, map[string]string{}},
+		{"course-grader", []model.CourseUserReference{"course-other@test.edulinq.org"}, map[string]string{}},
+
+		// Admins see everyone.
+		{"course-admin", []model.CourseUserReference{"section:lab-b"}, map[string]string{
+			"course-other@test.edulinq.org": "",
+		}},
+		{"course-admin", []model.CourseUserReference{"*", "-section:lab-a"}, map[string]string{
+			"course-other@test.edulinq.org": "",
+			"course-admin@test.edulinq.org": "",
+			"course-owner@test.edulinq.org": "",
+		}},
+	}
+
+	for i, testCase := range testCases {
+		fields := map[string]any{
+			"target-users": testCase.targetUsers,
+		}
+
+		response := core.SendTestAPIRequestFull(test, `courses/assignments/submissions/fetch/course/scores`, fields, nil, testCase.email)
+		if !response.Success {
+			test.Errorf("Case %d: Response is not a success when it should be: '%v'.", i, response)
+			continue
+		}
+
+		var responseContent FetchCourseScoresResponse
+		util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent)
+
+		actualIDs := make(map[string]string, len(testCase.ids))
+		for email, info := range responseContent.SubmissionInfos {
+			id := ""
+			if info != nil {
+				id = info.ID
+			}
+
+			actualIDs[email] = id
+		}
+
+		if !maps.Equal(testCase.ids, actualIDs) {
+			test.Errorf("Case %d: Submission IDs do not match. Expected: '%+v', actual: '%+v'.", i, testCase.ids, actualIDs)
+			continue
+		}
+	}
+}
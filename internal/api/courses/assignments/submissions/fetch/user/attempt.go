@@ -41,7 +41,7 @@ func HandleFetchUserAttempt(request *FetchUserAttemptRequest) (*FetchUserAttempt
 	}
 
 	response.FoundSubmission = true
-	response.GradingResult = gradingResult
+	response.GradingResult = core.RedactGradingResult(request.User, request.Assignment, gradingResult)
 
 	return &response, nil
 }
@@ -37,6 +37,23 @@ func HandleFetchUserHistory(request *FetchUserHistoryRequest) (*FetchUserHistory
 			Err(err).Add("target-user", request.TargetUser.Email)
 	}
 
+	// The scores students see should not include feedback that has not been released.
+	if request.User.Role < model.CourseRoleGrader {
+		for _, item := range history {
+			gradingInfo, err := db.GetSubmissionResult(request.Assignment, request.TargetUser.Email, item.ShortID)
+			if err != nil {
+				return nil, core.NewInternalError("-665", request, "Failed to get submission result.").
+					Err(err).Add("target-user", request.TargetUser.Email).Add("submission", item.ShortID)
+			}
+
+			if gradingInfo == nil {
+				continue
+			}
+
+			item.Score = core.RedactGradingInfo(request.User, request.Assignment, gradingInfo).Score
+		}
+	}
+
 	response.History = history
 
 	return &response, nil
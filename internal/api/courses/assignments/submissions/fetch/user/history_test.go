@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
 	"github.com/edulinq/autograder/internal/model"
 	"github.com/edulinq/autograder/internal/timestamp"
 	"github.com/edulinq/autograder/internal/util"
@@ -115,3 +116,53 @@ var studentHist []*model.SubmissionHistoryItem = []*model.SubmissionHistoryItem{
 		GradingStartTime: timestamp.Timestamp(1697406273000),
 	},
 }
+
+func TestHistoryHiddenFeedback(test *testing.T) {
+	db.ResetForTesting()
+	defer db.ResetForTesting()
+
+	assignment := db.MustGetTestAssignment()
+	assignment.FeedbackRelease = &model.FeedbackReleaseInfo{DefaultVisibility: model.FEEDBACK_VISIBILITY_AFTER_RELEASE}
+
+	err := db.SaveAssignment(assignment)
+	if err != nil {
+		test.Fatalf("Failed to save assignment: '%v'.", err)
+	}
+
+	hiddenHist := make([]*model.SubmissionHistoryItem, 0, len(studentHist))
+	for _, item := range studentHist {
+		hiddenItem := *item
+		hiddenItem.Score = 0
+		hiddenHist = append(hiddenHist, &hiddenItem)
+	}
+
+	testCases := []struct {
+		email    string
+		expected []*model.SubmissionHistoryItem
+	}{
+		{"course-student", hiddenHist},
+		{"course-grader", studentHist},
+		{"server-admin", studentHist},
+	}
+
+	for i, testCase := range testCases {
+		fields := map[string]any{
+			"target-email": "course-student@test.edulinq.org",
+		}
+
+		response := core.SendTestAPIRequestFull(test, `courses/assignments/submissions/fetch/user/history`, fields, nil, testCase.email)
+		if !response.Success {
+			test.Errorf("Case %d: Response is not a success when it should be: '%v'.", i, response)
+			continue
+		}
+
+		var responseContent FetchUserHistoryResponse
+		util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent)
+
+		if !reflect.DeepEqual(testCase.expected, responseContent.History) {
+			test.Errorf("Case %d: History does not match. Expected: '%s', actual: '%s'.", i,
+				util.MustToJSONIndent(testCase.expected), util.MustToJSONIndent(responseContent.History))
+			continue
+		}
+	}
+}
@@ -41,7 +41,7 @@ func HandleFetchUserPeek(request *FetchUserPeekRequest) (*FetchUserPeekResponse,
 	}
 
 	response.FoundSubmission = true
-	response.GradingInfo = submissionResult
+	response.GradingInfo = core.RedactGradingInfo(request.User, request.Assignment, submissionResult)
 
 	return &response, nil
 }
@@ -4,6 +4,9 @@ import (
 	"testing"
 
 	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/timestamp"
 	"github.com/edulinq/autograder/internal/util"
 )
 
@@ -123,3 +126,135 @@ func TestFetchUserPeek(test *testing.T) {
 		}
 	}
 }
+
+func TestFetchUserPeekHiddenFeedback(test *testing.T) {
+	db.ResetForTesting()
+	defer db.ResetForTesting()
+
+	assignment := db.MustGetTestAssignment()
+	assignment.FeedbackRelease = &model.FeedbackReleaseInfo{DefaultVisibility: model.FEEDBACK_VISIBILITY_AFTER_RELEASE}
+
+	err := db.SaveAssignment(assignment)
+	if err != nil {
+		test.Fatalf("Failed to save assignment: '%v'.", err)
+	}
+
+	releaseDate := timestamp.Zero()
+
+	testCases := []struct {
+		email       string
+		releaseDate *timestamp.Timestamp
+		score       float64
+		hidden      bool
+	}{
+		{"course-student", nil, 0.0, true},
+		{"course-grader", nil, 2.0, false},
+		{"server-admin", nil, 2.0, false},
+
+		{"course-student", &releaseDate, 2.0, false},
+	}
+
+	for i, testCase := range testCases {
+		assignment.FeedbackRelease.ReleaseDate = testCase.releaseDate
+
+		err = db.SaveAssignment(assignment)
+		if err != nil {
+			test.Fatalf("Case %d: Failed to save assignment: '%v'.", i, err)
+		}
+
+		fields := map[string]any{
+			"target-email": "course-student@test.edulinq.org",
+		}
+
+		response := core.SendTestAPIRequestFull(test, `courses/assignments/submissions/fetch/user/peek`, fields, nil, testCase.email)
+		if !response.Success {
+			test.Errorf("Case %d: Response is not a success when it should be: '%v'.", i, response)
+			continue
+		}
+
+		var responseContent FetchUserPeekResponse
+		util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent)
+
+		if !responseContent.FoundSubmission {
+			test.Errorf("Case %d: Did not find submission.", i)
+			continue
+		}
+
+		actualScore := responseContent.GradingInfo.Score
+		if !util.IsClose(testCase.score, actualScore) {
+			test.Errorf("Case %d: Unexpected submission score. Expected: '%+v', actual: '%+v'.", i, testCase.score, actualScore)
+			continue
+		}
+
+		for _, question := range responseContent.GradingInfo.Questions {
+			if testCase.hidden != question.Hidden {
+				test.Errorf("Case %d: Unexpected hidden value for question '%s'. Expected: '%v', actual: '%v'.", i, question.Name, testCase.hidden, question.Hidden)
+			}
+		}
+	}
+}
+
+func TestFetchUserPeekSections(test *testing.T) {
+	defer db.ResetForTesting()
+
+	db.MustSetTestSections(db.TEST_COURSE_ID, map[string][]string{
+		"course-grader@test.edulinq.org":  []string{"lab-a"},
+		"course-admin@test.edulinq.org":   []string{"lab-a"},
+		"course-student@test.edulinq.org": []string{"lab-b"},
+	})
+
+	testCases := []struct {
+		email       string
+		targetEmail string
+		locator     string
+	}{
+		// Self.
+		{"course-grader", "", ""},
+		{"course-student", "", ""},
+
+		// Outside of the grader's section.
+		{"course-grader", "course-student@test.edulinq.org", "-070"},
+
+		// Missing users are still just not found.
+		{"course-grader", "ZZZ@test.edulinq.org", ""},
+
+		// Admins and server admins are not limited.
+		{"course-admin", "course-student@test.edulinq.org", ""},
+		{"server-admin", "course-student@test.edulinq.org", ""},
+	}
+
+	for i, testCase := range testCases {
+		fields := map[string]any{
+			"target-email": testCase.targetEmail,
+		}
+
+		response := core.SendTestAPIRequestFull(test, `courses/assignments/submissions/fetch/user/peek`, fields, nil, testCase.email)
+		if !response.Success {
+			if testCase.locator != response.Locator {
+				test.Errorf("Case %d: Incorrect error returned. Expected '%s', found '%s'.",
+					i, testCase.locator, response.Locator)
+			}
+
+			continue
+		}
+
+		if testCase.locator != "" {
+			test.Errorf("Case %d: Did not get an expected error: '%s'.", i, testCase.locator)
+			continue
+		}
+	}
+
+	// Once in a shared section, the grader can see the student.
+	db.MustSetTestSections(db.TEST_COURSE_ID, map[string][]string{
+		"course-student@test.edulinq.org": []string{"lab-a", "lab-b"},
+	})
+
+	fields := map[string]any{
+		"target-email": "course-student@test.edulinq.org",
+	}
+
+	response := core.SendTestAPIRequestFull(test, `courses/assignments/submissions/fetch/user/peek`, fields, nil, "course-grader")
+	if !response.Success {
+		test.Fatalf("Response is not a success when it should be: '%v'.", response)
+	}
+}
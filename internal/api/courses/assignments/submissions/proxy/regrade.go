@@ -1,6 +1,9 @@
 package proxy
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/edulinq/autograder/internal/api/core"
 	"github.com/edulinq/autograder/internal/grader"
 	"github.com/edulinq/autograder/internal/model"
@@ -43,6 +46,13 @@ func HandleRegrade(request *RegradeRequest) (*RegradeResponse, *core.APIError) {
 		return nil, core.NewBadRequestError("-640", request, "Failed to regrade the assignment for the target users.").Err(userErr)
 	}
 
+	record := model.NewAuditRecord(model.AuditActionProxyRegrade, "")
+	record.After = map[string]string{
+		"users":    strings.Join(result.Options.ResolvedUsers, ","),
+		"complete": fmt.Sprintf("%v", (numRemaining == 0)),
+	}
+	request.Audit(record)
+
 	response := RegradeResponse{
 		RegradeResult: *result,
 		Complete:      (numRemaining == 0),
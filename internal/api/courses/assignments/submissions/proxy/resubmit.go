@@ -4,6 +4,7 @@ import (
 	"github.com/edulinq/autograder/internal/api/core"
 	"github.com/edulinq/autograder/internal/db"
 	"github.com/edulinq/autograder/internal/grader"
+	"github.com/edulinq/autograder/internal/model"
 	"github.com/edulinq/autograder/internal/timestamp"
 	"github.com/edulinq/autograder/internal/util"
 )
@@ -73,5 +74,14 @@ func HandleResubmit(request *ResubmitRequest) (*ResubmitResponse, *core.APIError
 
 	response.BaseSubmitResponse = core.GradeRequestSubmission(request.APIRequestAssignmentContext, tempDir, request.ProxyUser.Email, message, gradeOptions)
 
+	record := model.NewAuditRecord(model.AuditActionProxyResubmit, request.ProxyUser.Email)
+	if gradingResult.Info != nil {
+		record.Before = map[string]string{
+			"submission": gradingResult.Info.ID,
+		}
+	}
+	record.After = response.BaseSubmitResponse.GetAuditSummary()
+	request.Audit(record)
+
 	return &response, nil
 }
@@ -3,6 +3,7 @@ package proxy
 import (
 	"github.com/edulinq/autograder/internal/api/core"
 	"github.com/edulinq/autograder/internal/grader"
+	"github.com/edulinq/autograder/internal/model"
 	"github.com/edulinq/autograder/internal/timestamp"
 )
 
@@ -41,5 +42,9 @@ func HandleSubmit(request *SubmitRequest) (*SubmitResponse, *core.APIError) {
 
 	response.BaseSubmitResponse = core.GradeRequestSubmission(request.APIRequestAssignmentContext, request.Files.TempDir, request.ProxyUser.Email, request.Message, gradeOptions)
 
+	record := model.NewAuditRecord(model.AuditActionProxySubmit, request.ProxyUser.Email)
+	record.After = response.BaseSubmitResponse.GetAuditSummary()
+	request.Audit(record)
+
 	return &response, nil
 }
@@ -3,6 +3,7 @@ package submissions
 import (
 	"github.com/edulinq/autograder/internal/api/core"
 	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
 )
 
 type RemoveRequest struct {
@@ -36,5 +37,18 @@ func HandleRemove(request *RemoveRequest) (*RemoveResponse, *core.APIError) {
 
 	response.FoundSubmission = doesExist
 
+	if doesExist {
+		submission := request.TargetSubmission
+		if submission == "" {
+			submission = "<most recent>"
+		}
+
+		record := model.NewAuditRecord(model.AuditActionSubmissionRemove, request.TargetUser.Email)
+		record.Before = map[string]string{
+			"submission": submission,
+		}
+		request.Audit(record)
+	}
+
 	return &response, nil
 }
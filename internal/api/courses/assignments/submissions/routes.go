@@ -11,6 +11,8 @@ import (
 
 var baseRoutes []core.Route = []core.Route{
 	core.MustNewAPIRoute(`courses/assignments/submissions/remove`, HandleRemove),
+	core.MustNewAPIRoute(`courses/assignments/submissions/status`, HandleStatus),
+	core.MustNewAPIRoute(`courses/assignments/submissions/stream`, HandleStream),
 	core.MustNewAPIRoute(`courses/assignments/submissions/submit`, HandleSubmit),
 }
 
@@ -0,0 +1,101 @@
+package submissions
+
+import (
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/common"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/grader"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/timestamp"
+)
+
+type StatusRequest struct {
+	core.APIRequestAssignmentContext
+	core.MinCourseRoleStudent
+
+	TicketID string `json:"ticket-id" required:""`
+}
+
+type StatusResponse struct {
+	FoundTicket   bool                      `json:"found-ticket"`
+	Status        model.GradingTicketStatus `json:"status"`
+	QueuePosition int                       `json:"queue-position"`
+	QueueTime     timestamp.Timestamp       `json:"queue-time"`
+	StartTime     *timestamp.Timestamp      `json:"start-time"`
+	EndTime       *timestamp.Timestamp      `json:"end-time"`
+
+	// Only filled in once grading is done.
+	core.BaseSubmitResponse
+}
+
+// Get the status of a queued submission. Once grading is done, the grading result is included.
+func HandleStatus(request *StatusRequest) (*StatusResponse, *core.APIError) {
+	response := StatusResponse{}
+
+	_, err := common.ValidateID(request.TicketID)
+	if err != nil {
+		return nil, core.NewBadRequestError("-645", request, "Invalid ticket ID.").Err(err).Add("ticket-id", request.TicketID)
+	}
+
+	ticket, position, err := grader.GetQueuedTicket(request.TicketID)
+	if err != nil {
+		return nil, core.NewInternalError("-646", request, "Failed to get grading ticket.").Err(err).Add("ticket-id", request.TicketID)
+	}
+
+	if ticket == nil {
+		return &response, nil
+	}
+
+	// Tickets from other assignments or users (unless the requester is a grader) are treated as missing.
+	if (ticket.CourseID != request.Course.GetID()) || (ticket.AssignmentID != request.Assignment.GetID()) {
+		return &response, nil
+	}
+
+	if (ticket.User != request.User.Email) && (request.User.Role < model.CourseRoleGrader) {
+		return &response, nil
+	}
+
+	response.FoundTicket = true
+	response.Status = ticket.Status
+	response.QueuePosition = position
+	response.QueueTime = ticket.QueueTime
+	response.StartTime = ticket.StartTime
+	response.EndTime = ticket.EndTime
+
+	if !ticket.IsDone() || (ticket.Result == nil) {
+		return &response, nil
+	}
+
+	result := ticket.Result
+
+	if result.Rejected {
+		response.Rejected = true
+		response.Message = result.RejectReason
+		return &response, nil
+	}
+
+	if result.SoftError != "" {
+		response.Message = core.ConcatStdOutErr(result.SoftError, result.Stdout, result.Stderr)
+		return &response, nil
+	}
+
+	if !result.GradingSuccess {
+		return &response, nil
+	}
+
+	gradingInfo, err := db.GetSubmissionResult(request.Assignment, ticket.User, result.SubmissionID)
+	if err != nil {
+		return nil, core.NewInternalError("-647", request, "Failed to get submission result.").
+			Err(err).Add("ticket-id", ticket.ID).Add("submission", result.SubmissionID)
+	}
+
+	// The submission may have been removed since it was graded.
+	if gradingInfo == nil {
+		return &response, nil
+	}
+
+	response.GradingSuccess = true
+	response.GradingInfo = core.RedactGradingInfo(request.User, request.Assignment, gradingInfo)
+
+	return &response, nil
+}
@@ -0,0 +1,227 @@
+package submissions
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/grader"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/timestamp"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestStatusQueuedSubmit(test *testing.T) {
+	db.ResetForTesting()
+	defer db.ResetForTesting()
+	defer grader.StopQueue()
+
+	assignment := db.MustGetTestSubmissionAssignment()
+	paths := []string{filepath.Join(assignment.GetSourceDir(), SUBMISSION_RELPATH)}
+
+	fields := map[string]any{
+		"course-id":     assignment.GetCourse().GetID(),
+		"assignment-id": assignment.GetID(),
+		"allow-late":    true,
+		"queue":         true,
+	}
+
+	response := core.SendTestAPIRequestFull(test, `courses/assignments/submissions/submit`, fields, paths, "course-student")
+	if !response.Success {
+		test.Fatalf("Response is not a success when it should be: '%v'.", response)
+	}
+
+	var submitContent SubmitResponse
+	util.MustJSONFromString(util.MustToJSON(response.Content), &submitContent)
+
+	if submitContent.TicketID == "" {
+		test.Fatalf("Queued submission did not get a ticket ID: '%s'.", util.MustToJSONIndent(submitContent))
+	}
+
+	if submitContent.GradingSuccess || (submitContent.GradingInfo != nil) {
+		test.Fatalf("Queued submission returned a grading result: '%s'.", util.MustToJSONIndent(submitContent))
+	}
+
+	fields = map[string]any{
+		"course-id":     assignment.GetCourse().GetID(),
+		"assignment-id": assignment.GetID(),
+		"ticket-id":     submitContent.TicketID,
+	}
+
+	var statusContent StatusResponse
+	for i := 0; i < 600; i++ {
+		response = core.SendTestAPIRequestFull(test, `courses/assignments/submissions/status`, fields, nil, "course-student")
+		if !response.Success {
+			test.Fatalf("Status response is not a success when it should be: '%v'.", response)
+		}
+
+		statusContent = StatusResponse{}
+		util.MustJSONFromString(util.MustToJSON(response.Content), &statusContent)
+
+		if statusContent.Status == model.GradingTicketStatusDone {
+			break
+		}
+
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	if !statusContent.FoundTicket {
+		test.Fatalf("Did not find ticket.")
+	}
+
+	if statusContent.Status != model.GradingTicketStatusDone {
+		test.Fatalf("Ticket was never finished: '%s'.", util.MustToJSONIndent(statusContent))
+	}
+
+	if !statusContent.GradingSuccess || (statusContent.GradingInfo == nil) {
+		test.Fatalf("Ticket is not a grading success when it should be: '%s'.", util.MustToJSONIndent(statusContent))
+	}
+
+	submission, err := db.GetSubmissionResult(assignment, "course-student@test.edulinq.org", "")
+	if err != nil {
+		test.Fatalf("Failed to get submission: '%v'.", err)
+	}
+
+	if !statusContent.GradingInfo.Equals(*submission, true) {
+		test.Fatalf("Status result does not match database value. Expected: '%s', Actual: '%s'.",
+			util.MustToJSONIndent(submission), util.MustToJSONIndent(statusContent.GradingInfo))
+	}
+}
+
+func TestStatusBase(test *testing.T) {
+	db.ResetForTesting()
+	defer db.ResetForTesting()
+
+	queueTime := timestamp.FromMSecs(100)
+	startTime := timestamp.FromMSecs(200)
+	endTime := timestamp.FromMSecs(300)
+
+	newTicket := func(id string, assignmentID string, result *model.GradingTicketResult) *model.GradingTicket {
+		return &model.GradingTicket{
+			ID:           id,
+			CourseID:     "course101",
+			AssignmentID: assignmentID,
+			User:         "course-student@test.edulinq.org",
+			Status:       model.GradingTicketStatusDone,
+			QueueTime:    queueTime,
+			StartTime:    &startTime,
+			EndTime:      &endTime,
+			Result:       result,
+		}
+	}
+
+	// Only use finished tickets so a running queue will not pick them up.
+	tickets := map[string]*model.GradingTicket{
+		"graded":   newTicket("graded", "hw0", &model.GradingTicketResult{GradingSuccess: true, SubmissionID: "1697406272"}),
+		"rejected": newTicket("rejected", "hw0", &model.GradingTicketResult{Rejected: true, RejectReason: "Some Reason"}),
+		"soft":     newTicket("soft", "hw0", &model.GradingTicketResult{SoftError: "Some Error", Stdout: "out", Stderr: "err"}),
+		"failed":   newTicket("failed", "hw0", &model.GradingTicketResult{}),
+		"other":    newTicket("other", "other-assignment", &model.GradingTicketResult{}),
+		"not-mine": newTicket("not-mine", "hw0", &model.GradingTicketResult{}),
+	}
+
+	tickets["not-mine"].User = "course-grader@test.edulinq.org"
+
+	err := db.UpsertGradingTickets(tickets)
+	if err != nil {
+		test.Fatalf("Failed to save tickets: '%v'.", err)
+	}
+
+	testCases := []struct {
+		email          string
+		ticketID       string
+		found          bool
+		gradingSuccess bool
+		rejected       bool
+		message        string
+		locator        string
+	}{
+		// Owner.
+		{"course-student", "graded", true, true, false, "", ""},
+		{"course-student", "rejected", true, false, true, "Some Reason", ""},
+		{"course-student", "soft", true, false, false, core.ConcatStdOutErr("Some Error", "out", "err"), ""},
+		{"course-student", "failed", true, false, false, "", ""},
+
+		// Graders can see other users' tickets.
+		{"course-grader", "graded", true, true, false, "", ""},
+		{"course-admin", "rejected", true, false, true, "Some Reason", ""},
+
+		// Students cannot see other users' tickets.
+		{"course-student", "not-mine", false, false, false, "", ""},
+
+		// Ticket from another assignment.
+		{"course-student", "other", false, false, false, "", ""},
+
+		// Missing ticket.
+		{"course-student", "zzz", false, false, false, "", ""},
+
+		// Bad ticket ID.
+		{"course-student", "../graded", false, false, false, "", "-645"},
+	}
+
+	for i, testCase := range testCases {
+		fields := map[string]any{
+			"course-id":     "course101",
+			"assignment-id": "hw0",
+			"ticket-id":     testCase.ticketID,
+		}
+
+		response := core.SendTestAPIRequestFull(test, `courses/assignments/submissions/status`, fields, nil, testCase.email)
+		if !response.Success {
+			if testCase.locator != response.Locator {
+				test.Errorf("Case %d: Incorrect locator. Expected: '%s', Actual: '%s'.", i, testCase.locator, response.Locator)
+			}
+
+			continue
+		}
+
+		if testCase.locator != "" {
+			test.Errorf("Case %d: Response is a success when it should not be: '%v'.", i, response)
+			continue
+		}
+
+		var responseContent StatusResponse
+		util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent)
+
+		if testCase.found != responseContent.FoundTicket {
+			test.Errorf("Case %d: Unexpected found ticket. Expected: '%v', Actual: '%v'.", i, testCase.found, responseContent.FoundTicket)
+			continue
+		}
+
+		if !testCase.found {
+			continue
+		}
+
+		if responseContent.Status != model.GradingTicketStatusDone {
+			test.Errorf("Case %d: Unexpected status. Expected: '%s', Actual: '%s'.", i, model.GradingTicketStatusDone, responseContent.Status)
+			continue
+		}
+
+		if (responseContent.QueueTime != queueTime) || (*responseContent.StartTime != startTime) || (*responseContent.EndTime != endTime) {
+			test.Errorf("Case %d: Unexpected times: '%s'.", i, util.MustToJSONIndent(responseContent))
+			continue
+		}
+
+		if testCase.gradingSuccess != responseContent.GradingSuccess {
+			test.Errorf("Case %d: Unexpected grading success. Expected: '%v', Actual: '%v'.", i, testCase.gradingSuccess, responseContent.GradingSuccess)
+			continue
+		}
+
+		if testCase.gradingSuccess && (responseContent.GradingInfo == nil) {
+			test.Errorf("Case %d: Missing grading result.", i)
+			continue
+		}
+
+		if testCase.rejected != responseContent.Rejected {
+			test.Errorf("Case %d: Unexpected rejected. Expected: '%v', Actual: '%v'.", i, testCase.rejected, responseContent.Rejected)
+			continue
+		}
+
+		if testCase.message != responseContent.Message {
+			test.Errorf("Case %d: Unexpected message. Expected: '%s', Actual: '%s'.", i, testCase.message, responseContent.Message)
+			continue
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package submissions
+
+import (
+	"context"
+	"time"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/grader"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/timestamp"
+)
+
+// How often to send a keep-alive on a stream with no new events.
+const streamKeepAliveMS = 15 * 1000
+
+type StreamRequest struct {
+	core.APIRequestAssignmentContext
+	core.MinCourseRoleStudent
+
+	TargetUser       core.TargetCourseUserSelfOrGrader `json:"target-email"`
+	TargetSubmission string                            `json:"target-submission"`
+}
+
+type StreamResponse struct {
+	FoundUser       bool `json:"found-user"`
+	FoundSubmission bool `json:"found-submission"`
+
+	stream     *grader.GradingStream
+	user       *model.CourseUser
+	assignment *model.Assignment
+
+	// Output events that are being held back from a student (see redactEvents()).
+	heldOutput []*grader.GradingStreamEvent
+	hasHidden  bool
+}
+
+// Follow a submission while it is being graded.
+// If the submission is found, the response is a stream of server-sent events (instead of a JSON response).
+// Each event's data is a grading stream event, and the stream ends after the "done" event.
+// Students only get the grader's output once grading is done (and only if no questions were hidden).
+// If no submission is specified, the submission currently being graded for the user is used.
+// Recently graded submissions can still be streamed (for a short time).
+func HandleStream(request *StreamRequest) (*StreamResponse, *core.APIError) {
+	response := StreamResponse{
+		user:       request.User,
+		assignment: request.Assignment,
+	}
+
+	if !request.TargetUser.Found {
+		return &response, nil
+	}
+
+	response.FoundUser = true
+
+	response.stream = grader.GetGradingStream(request.Assignment, request.TargetUser.Email, request.TargetSubmission)
+	if response.stream == nil {
+		return &response, nil
+	}
+
+	response.FoundSubmission = true
+
+	return &response, nil
+}
+
+func (this *StreamResponse) ShouldStream() bool {
+	return (this != nil) && (this.stream != nil)
+}
+
+func (this *StreamResponse) Stream(ctx context.Context, eventStream *core.EventStream) error {
+	keepAlive := time.NewTicker(time.Duration(streamKeepAliveMS) * time.Millisecond)
+	defer keepAlive.Stop()
+
+	nextEvent := 0
+
+	for {
+		events, done, changed := this.stream.GetEvents(nextEvent)
+		nextEvent += len(events)
+
+		for _, event := range this.redactEvents(events) {
+			err := eventStream.Send(string(event.Type), event)
+			if err != nil {
+				return err
+			}
+		}
+
+		if done {
+			return nil
+		}
+
+		select {
+		case <-changed:
+		case <-keepAlive.C:
+			err := eventStream.KeepAlive()
+			if err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// Users below grader only get the feedback that has been released to students.
+// The grader's output may describe questions that are hidden (either by the assignment or by the grader itself),
+// and which questions are hidden is not known until grading is done.
+// So output is held back from students until the done event,
+// and is then only sent if no questions were hidden (see model.GradingResult.Redact()).
+// Returns the events that should be sent now.
+func (this *StreamResponse) redactEvents(events []*grader.GradingStreamEvent) []*grader.GradingStreamEvent {
+	if this.user.Role >= model.CourseRoleGrader {
+		return events
+	}
+
+	results := make([]*grader.GradingStreamEvent, 0, len(events))
+
+	for _, event := range events {
+		switch event.Type {
+		case grader.GRADING_STREAM_EVENT_STDOUT, grader.GRADING_STREAM_EVENT_STDERR, grader.GRADING_STREAM_EVENT_TRUNCATED:
+			this.heldOutput = append(this.heldOutput, event)
+		case grader.GRADING_STREAM_EVENT_QUESTION:
+			question, hidden := event.Question.Redact(this.assignment, timestamp.Now())
+			if hidden {
+				this.hasHidden = true
+
+				eventCopy := *event
+				eventCopy.Question = question
+				event = &eventCopy
+			}
+
+			results = append(results, event)
+		case grader.GRADING_STREAM_EVENT_DONE:
+			if !this.hasHidden {
+				results = append(results, this.heldOutput...)
+			}
+
+			this.heldOutput = nil
+			results = append(results, event)
+		default:
+			results = append(results, event)
+		}
+	}
+
+	return results
+}
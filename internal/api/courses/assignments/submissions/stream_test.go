@@ -0,0 +1,226 @@
+package submissions
+
+import (
+	"context"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/config"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/grader"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestStream(test *testing.T) {
+	db.ResetForTesting()
+	defer db.ResetForTesting()
+
+	defer config.DOCKER_DISABLE.Set(config.DOCKER_DISABLE.Get())
+	config.DOCKER_DISABLE.Set(true)
+
+	// Only the bash assignment can be graded without installing any grader dependencies.
+	assignment := db.MustGetAssignment("course-languages", "bash")
+	submissionDir := filepath.Join(assignment.GetSourceDir(), "test-submissions", "solution")
+
+	options := grader.GetDefaultGradeOptions()
+	options.CheckRejection = false
+
+	result, _, softError, err := grader.Grade(context.Background(), assignment, submissionDir, "course-student@test.edulinq.org", "", options)
+	if err != nil {
+		test.Fatalf("Failed to grade submission: '%v'.", err)
+	}
+
+	if softError != "" {
+		test.Fatalf("Submission got a soft error: '%s'.", softError)
+	}
+
+	testCases := []struct {
+		email            string
+		targetEmail      string
+		targetSubmission string
+		feedbackRelease  *model.FeedbackReleaseInfo
+		expectedLocator  string
+		expectedFound    bool
+		expectedOutput   bool
+		expectedHidden   bool
+	}{
+		// Valid streams.
+		{"course-student", "", result.Info.ShortID, nil, "", true, true, false},
+		{"course-student", "course-student@test.edulinq.org", result.Info.ID, nil, "", true, true, false},
+		{"course-grader", "course-student@test.edulinq.org", result.Info.ShortID, nil, "", true, true, false},
+
+		// Held back feedback.
+		{"course-student", "", result.Info.ShortID, &model.FeedbackReleaseInfo{DefaultVisibility: model.FEEDBACK_VISIBILITY_AFTER_RELEASE}, "", true, false, true},
+		{"course-student", "", result.Info.ShortID, &model.FeedbackReleaseInfo{}, "", true, true, false},
+		{"course-grader", "course-student@test.edulinq.org", result.Info.ShortID, &model.FeedbackReleaseInfo{DefaultVisibility: model.FEEDBACK_VISIBILITY_AFTER_RELEASE}, "", true, true, false},
+
+		// Missing streams.
+		{"course-student", "", "", nil, "", false, false, false},
+		{"course-student", "", "999", nil, "", false, false, false},
+		{"course-grader", "course-other@test.edulinq.org", result.Info.ShortID, nil, "", false, false, false},
+
+		// Other users.
+		{"course-student", "course-grader@test.edulinq.org", result.Info.ShortID, nil, "-033", false, false, false},
+		{"course-other", "course-student@test.edulinq.org", result.Info.ShortID, nil, "-020", false, false, false},
+	}
+
+	for i, testCase := range testCases {
+		assignment.FeedbackRelease = testCase.feedbackRelease
+		db.MustSaveAssignment(assignment)
+
+		fields := map[string]any{
+			"course-id":         assignment.GetCourse().GetID(),
+			"assignment-id":     assignment.GetID(),
+			"target-email":      testCase.targetEmail,
+			"target-submission": testCase.targetSubmission,
+		}
+
+		events, response := core.SendTestAPIEventStreamRequest(test, `courses/assignments/submissions/stream`, fields, testCase.email)
+
+		if testCase.expectedLocator != "" {
+			if (response == nil) || response.Success {
+				test.Errorf("Case %d: Response is not an error when it should be.", i)
+				continue
+			}
+
+			if testCase.expectedLocator != response.Locator {
+				test.Errorf("Case %d: Incorrect error returned. Expected '%s', found '%s'.", i, testCase.expectedLocator, response.Locator)
+			}
+
+			continue
+		}
+
+		if !testCase.expectedFound {
+			if response == nil {
+				test.Errorf("Case %d: Got a stream when none was expected.", i)
+				continue
+			}
+
+			if !response.Success {
+				test.Errorf("Case %d: Response is not a success when it should be: '%v'.", i, response)
+				continue
+			}
+
+			var responseContent StreamResponse
+			util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent)
+
+			if responseContent.FoundSubmission {
+				test.Errorf("Case %d: Found a submission when none was expected.", i)
+			}
+
+			continue
+		}
+
+		if events == nil {
+			test.Errorf("Case %d: Did not get a stream: '%v'.", i, response)
+			continue
+		}
+
+		counts := make(map[string]int)
+		for _, event := range events {
+			counts[event.Type]++
+
+			var streamEvent grader.GradingStreamEvent
+			util.MustJSONFromString(event.Data, &streamEvent)
+
+			if event.Type != string(streamEvent.Type) {
+				test.Errorf("Case %d: Event type does not match its data. Event: '%s', Data: '%s'.", i, event.Type, streamEvent.Type)
+			}
+
+			if (streamEvent.Question != nil) && (testCase.expectedHidden != streamEvent.Question.Hidden) {
+				test.Errorf("Case %d: Unexpected hidden value for question '%s'. Expected: '%v', actual: '%v'.",
+					i, streamEvent.Question.Name, testCase.expectedHidden, streamEvent.Question.Hidden)
+			}
+		}
+
+		if (counts[string(grader.GRADING_STREAM_EVENT_START)] != 1) || (counts[string(grader.GRADING_STREAM_EVENT_DONE)] != 1) {
+			test.Errorf("Case %d: Stream does not have a single start and done event: '%s'.", i, util.MustToJSONIndent(counts))
+			continue
+		}
+
+		if counts[string(grader.GRADING_STREAM_EVENT_QUESTION)] != len(result.Info.Questions) {
+			test.Errorf("Case %d: Unexpected number of question events. Expected: %d, Actual: %d.",
+				i, len(result.Info.Questions), counts[string(grader.GRADING_STREAM_EVENT_QUESTION)])
+			continue
+		}
+
+		hasOutput := (counts[string(grader.GRADING_STREAM_EVENT_STDOUT)] > 0)
+		if testCase.expectedOutput != hasOutput {
+			test.Errorf("Case %d: Unexpected output. Expected: %v, Actual: '%s'.", i, testCase.expectedOutput, util.MustToJSONIndent(counts))
+			continue
+		}
+	}
+}
+
+// Output is held back from students until the stream is done,
+// and is then only sent if no questions were hidden (including questions hidden by the grader itself).
+func TestStreamRedactEvents(test *testing.T) {
+	assignment := db.MustGetAssignment("course-languages", "bash")
+	assignment.FeedbackRelease = nil
+
+	start := &grader.GradingStreamEvent{Type: grader.GRADING_STREAM_EVENT_START}
+	stdout := &grader.GradingStreamEvent{Type: grader.GRADING_STREAM_EVENT_STDOUT, Text: "out"}
+	stderr := &grader.GradingStreamEvent{Type: grader.GRADING_STREAM_EVENT_STDERR, Text: "err"}
+	visible := &grader.GradingStreamEvent{Type: grader.GRADING_STREAM_EVENT_QUESTION, Question: &model.GradedQuestion{Name: "visible", Score: 1}}
+	hidden := &grader.GradingStreamEvent{Type: grader.GRADING_STREAM_EVENT_QUESTION, Question: &model.GradedQuestion{Name: "hidden", Score: 1, Visibility: model.FEEDBACK_VISIBILITY_AFTER_RELEASE}}
+	done := &grader.GradingStreamEvent{Type: grader.GRADING_STREAM_EVENT_DONE}
+
+	testCases := []struct {
+		role          model.CourseUserRole
+		batches       [][]*grader.GradingStreamEvent
+		expectedTypes []grader.GradingStreamEventType
+	}{
+		// All questions visible.
+		{
+			model.CourseRoleStudent,
+			[][]*grader.GradingStreamEvent{{start, stdout}, {stderr}, {visible, done}},
+			[]grader.GradingStreamEventType{"start", "question", "stdout", "stderr", "done"},
+		},
+
+		// Hidden by the grader.
+		{
+			model.CourseRoleStudent,
+			[][]*grader.GradingStreamEvent{{start, stdout}, {stderr}, {visible, hidden, done}},
+			[]grader.GradingStreamEventType{"start", "question", "question", "done"},
+		},
+
+		// Graders get everything as it happens.
+		{
+			model.CourseRoleGrader,
+			[][]*grader.GradingStreamEvent{{start, stdout}, {stderr}, {visible, hidden, done}},
+			[]grader.GradingStreamEventType{"start", "stdout", "stderr", "question", "question", "done"},
+		},
+	}
+
+	for i, testCase := range testCases {
+		response := StreamResponse{
+			user:       &model.CourseUser{Role: testCase.role},
+			assignment: assignment,
+		}
+
+		types := make([]grader.GradingStreamEventType, 0)
+		for j, batch := range testCase.batches {
+			events := response.redactEvents(batch)
+
+			// Nothing should be sent to students after the start event until the questions are graded.
+			if (testCase.role < model.CourseRoleGrader) && (j == 1) && (len(events) != 0) {
+				test.Errorf("Case %d: Student got output before grading was done: '%s'.", i, util.MustToJSONIndent(events))
+			}
+
+			for _, event := range events {
+				types = append(types, event.Type)
+
+				if (event.Question != nil) && (event.Question.Name == "hidden") && (testCase.role < model.CourseRoleGrader) && !event.Question.Hidden {
+					test.Errorf("Case %d: Hidden question was not redacted.", i)
+				}
+			}
+		}
+
+		if !slices.Equal(testCase.expectedTypes, types) {
+			test.Errorf("Case %d: Unexpected events. Expected: '%v', Actual: '%v'.", i, testCase.expectedTypes, types)
+		}
+	}
+}
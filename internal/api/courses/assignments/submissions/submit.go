@@ -12,16 +12,30 @@ type SubmitRequest struct {
 
 	Message   string `json:"message"`
 	AllowLate bool   `json:"allow-late"`
+	Queue     bool   `json:"queue"`
 }
 
 type SubmitResponse struct {
 	core.BaseSubmitResponse
+
+	TicketID string `json:"ticket-id,omitempty"`
 }
 
-// Submit an assignment submission to the autograder.
+// Submit an assignment submission to the autograder. If queued, a ticket ID (for checking the status) is returned instead of the grading result.
 func HandleSubmit(request *SubmitRequest) (*SubmitResponse, *core.APIError) {
 	response := SubmitResponse{}
 
+	if request.Queue {
+		ticket, err := grader.QueueSubmission(request.Assignment, request.Files.TempDir, request.User.Email, request.Message, request.AllowLate)
+		if err != nil {
+			return nil, core.NewInternalError("-644", request, "Failed to queue the submission.").Err(err)
+		}
+
+		response.TicketID = ticket.ID
+
+		return &response, nil
+	}
+
 	gradeOptions := grader.GetDefaultGradeOptions()
 	gradeOptions.AllowLate = request.AllowLate
 
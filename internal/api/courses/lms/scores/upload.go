@@ -1,6 +1,7 @@
 package scores
 
 import (
+	"fmt"
 	"slices"
 	"strings"
 
@@ -22,6 +23,7 @@ type UploadResponse struct {
 }
 
 // Perform a full scoring and upload scores to the course's LMS.
+// Uploads (that are not dry runs) are recorded in the audit trail.
 func HandleUpload(request *UploadRequest) (*UploadResponse, *core.APIError) {
 	scores, err := scoring.FullCourseScoringAndUpload(request.Course, request.DryRun)
 	if err != nil {
@@ -45,6 +47,15 @@ func HandleUpload(request *UploadRequest) (*UploadResponse, *core.APIError) {
 		return strings.Compare(a.UserEmail, b.UserEmail)
 	})
 
+	if !request.DryRun {
+		record := model.NewAuditRecord(model.AuditActionLMSUploadScores, request.Course.GetID())
+		record.After = map[string]string{
+			"assignments": fmt.Sprintf("%d", len(scores)),
+			"count":       fmt.Sprintf("%d", len(results)),
+		}
+		request.Audit(record)
+	}
+
 	response := &UploadResponse{
 		DryRun:  request.DryRun,
 		Results: results,
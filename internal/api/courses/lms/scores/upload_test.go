@@ -59,3 +59,50 @@ func TestLMSScoresUpload(test *testing.T) {
 			util.MustToJSONIndent(expectedResponse), util.MustToJSONIndent(responseContent))
 	}
 }
+
+func TestLMSScoresUploadAudit(test *testing.T) {
+	defer db.ResetForTesting()
+
+	testCases := []struct {
+		dryRun          bool
+		expectedRecords int
+	}{
+		{true, 0},
+		{false, 1},
+	}
+
+	for i, testCase := range testCases {
+		db.ResetForTesting()
+
+		fields := map[string]any{
+			"dry-run": testCase.dryRun,
+		}
+
+		response := core.SendTestAPIRequestFull(test, `courses/lms/scores/upload`, fields, nil, "course-admin")
+		if !response.Success {
+			test.Errorf("Case %d: Response is not a success when it should be: '%v'.", i, response)
+			continue
+		}
+
+		records, err := db.GetAuditRecords(model.AuditQuery{Action: model.AuditActionLMSUploadScores})
+		if err != nil {
+			test.Errorf("Case %d: Failed to get audit records: '%v'.", i, err)
+			continue
+		}
+
+		if testCase.expectedRecords != len(records) {
+			test.Errorf("Case %d: Unexpected number of audit records. Expected: %d, Actual: %d.", i, testCase.expectedRecords, len(records))
+			continue
+		}
+
+		if len(records) == 0 {
+			continue
+		}
+
+		record := records[0]
+		if (record.Actor != "course-admin@test.edulinq.org") || (record.CourseID != db.TEST_COURSE_ID) || (record.Target != db.TEST_COURSE_ID) {
+			test.Errorf("Case %d: Unexpected audit record: '%s'.", i, util.MustToJSONIndent(record))
+			continue
+		}
+	}
+}
@@ -1,9 +1,35 @@
 package upsert
 
 import (
+	"fmt"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/model"
 	"github.com/edulinq/autograder/internal/procedures/courses"
 )
 
 type UpsertResponse struct {
 	Results []courses.CourseUpsertResult `json:"results"`
 }
+
+// Add an audit record for each course that was created or updated.
+func auditResults(request *core.APIRequestUserContext, options courses.CourseUpsertOptions, results []courses.CourseUpsertResult) {
+	if options.DryRun {
+		return
+	}
+
+	for _, result := range results {
+		if !result.Success || (!result.Created && !result.Updated) {
+			continue
+		}
+
+		record := model.NewAuditRecord(model.AuditActionCourseUpsert, result.CourseID)
+		record.CourseID = result.CourseID
+		record.After = map[string]string{
+			"created": fmt.Sprintf("%v", result.Created),
+			"updated": fmt.Sprintf("%v", result.Updated),
+		}
+
+		request.Audit(record)
+	}
+}
@@ -25,5 +25,7 @@ func HandleFileSpec(request *FileSpecRequest) (*UpsertResponse, *core.APIError)
 			"Failed to upsert course from FileSpec.").Err(err)
 	}
 
+	auditResults(&request.APIRequestUserContext, options, results)
+
 	return &UpsertResponse{results}, nil
 }
@@ -34,5 +34,7 @@ func HandleZipFile(request *ZipFileRequest) (*UpsertResponse, *core.APIError) {
 			"Failed to upsert course from zip file.").Err(err)
 	}
 
+	auditResults(&request.APIRequestUserContext, options, results)
+
 	return &UpsertResponse{results}, nil
 }
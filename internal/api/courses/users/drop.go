@@ -38,6 +38,12 @@ func HandleDrop(request *DropRequest) (*DropResponse, *core.APIError) {
 			"Failed to drop user.").Err(err).Add("target-course-user", request.TargetCourseUser.User.Email)
 	}
 
+	record := model.NewAuditRecord(model.AuditActionCourseDrop, request.TargetCourseUser.User.Email)
+	record.Before = map[string]string{
+		"course-role": request.TargetCourseUser.User.Role.String(),
+	}
+	request.Audit(record)
+
 	return &response, nil
 }
 
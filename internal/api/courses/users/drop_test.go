@@ -3,6 +3,7 @@ package users
 import (
 	"github.com/edulinq/autograder/internal/api/core"
 	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
 	"github.com/edulinq/autograder/internal/util"
 	"reflect"
 	"testing"
@@ -110,5 +111,33 @@ func TestUserDrop(test *testing.T) {
 				continue
 			}
 		}
+
+		// Ensure that only actual drops are audited.
+		records, err := db.GetAuditRecords(model.AuditQuery{Action: model.AuditActionCourseDrop})
+		if err != nil {
+			test.Errorf("Case %d: Failed to get audit records: '%v'.", i, err)
+			continue
+		}
+
+		expectedRecords := 0
+		if testCase.expected.FoundUser && !testCase.permError {
+			expectedRecords = 1
+		}
+
+		if expectedRecords != len(records) {
+			test.Errorf("Case %d: Unexpected number of audit records. Expected: %d, Actual: %d.",
+				i, expectedRecords, len(records))
+			continue
+		}
+
+		if expectedRecords == 0 {
+			continue
+		}
+
+		record := records[0]
+		if (record.Actor != (testCase.email + "@test.edulinq.org")) || (record.Target != testCase.target) || (record.CourseID != course.GetID()) {
+			test.Errorf("Case %d: Unexpected audit record: '%s'.", i, util.MustToJSONIndent(record))
+			continue
+		}
 	}
 }
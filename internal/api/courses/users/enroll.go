@@ -4,6 +4,7 @@ import (
 	"slices"
 
 	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
 	"github.com/edulinq/autograder/internal/model"
 	"github.com/edulinq/autograder/internal/procedures/users"
 )
@@ -45,8 +46,19 @@ func HandleEnroll(request *EnrollRequest) (*EnrollResponse, *core.APIError) {
 		ContextCourseRole: request.User.Role,
 	}
 
+	before, err := db.GetServerUsers()
+	if err != nil {
+		return nil, core.NewInternalError("-666", request, "Failed to get server users.").Err(err)
+	}
+
 	results := users.UpsertUsers(options)
 
+	if !request.DryRun {
+		for _, record := range core.NewUserOpAuditRecords(model.AuditActionCourseEnroll, request.Course.GetID(), before, results) {
+			request.Audit(record)
+		}
+	}
+
 	var response EnrollResponse
 
 	// Convert UserOpResults to user friendly ExternalUserOpResults.
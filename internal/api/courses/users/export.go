@@ -0,0 +1,44 @@
+package users
+
+import (
+	"strings"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/procedures/users"
+)
+
+type ExportRequest struct {
+	core.APIRequestCourseUserContext
+	core.MinCourseRoleAdmin
+	Users core.CourseUsers `json:"-"`
+
+	TargetUsers []model.CourseUserReference `json:"target-users"`
+}
+
+type ExportResponse struct {
+	// The exported users as a CSV (see courses/users/import).
+	CSV string `json:"csv"`
+}
+
+// Export the users in the course as a CSV.
+func HandleExport(request *ExportRequest) (*ExportResponse, *core.APIError) {
+	// Default to exporting all users in the course.
+	if len(request.TargetUsers) == 0 {
+		request.TargetUsers = model.NewAllCourseUserReference()
+	}
+
+	reference, err := model.ParseCourseUserReferences(request.TargetUsers)
+	if err != nil {
+		return nil, core.NewBadRequestError("-673", request, "Failed to parse target users.").Err(err)
+	}
+
+	var builder strings.Builder
+
+	err = users.WriteCourseUsersCSV(&builder, request.Course.GetID(), model.ResolveCourseUsers(request.Users, reference))
+	if err != nil {
+		return nil, core.NewInternalError("-674", request, "Failed to write users as a CSV.").Err(err)
+	}
+
+	return &ExportResponse{builder.String()}, nil
+}
@@ -0,0 +1,70 @@
+package users
+
+import (
+	"testing"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestExport(test *testing.T) {
+	testCases := []struct {
+		email    string
+		input    []string
+		expected string
+		locator  string
+	}{
+		// Invalid Permissions
+		{"course-student", nil, "", "-020"},
+		{"course-grader", nil, "", "-020"},
+
+		// No Users
+		{"course-admin", []string{"-*"}, "email,name,course,course-role,course-lms-id,course-sections\n", ""},
+
+		// Some Users
+		{
+			"course-admin",
+			[]string{"student", "grader"},
+			"email,name,course,course-role,course-lms-id,course-sections\n" +
+				"course-grader@test.edulinq.org,course-grader,course101,grader,lms-course-grader@test.edulinq.org,\n" +
+				"course-student@test.edulinq.org,course-student,course101,student,lms-course-student@test.edulinq.org,\n",
+			"",
+		},
+
+		// Input Errors
+		{"course-admin", []string{"ZZZ"}, "", "-673"},
+	}
+
+	for i, testCase := range testCases {
+		fields := map[string]any{
+			"target-users": testCase.input,
+		}
+
+		response := core.SendTestAPIRequestFull(test, `courses/users/export`, fields, nil, testCase.email)
+		if !response.Success {
+			if testCase.locator != "" {
+				if response.Locator != testCase.locator {
+					test.Errorf("Case %d: Incorrect error returned. Expected '%s', found '%s'.",
+						i, testCase.locator, response.Locator)
+				}
+			} else {
+				test.Errorf("Case %d: Response is not a success when it should be: '%v'.", i, response)
+			}
+
+			continue
+		}
+
+		if testCase.locator != "" {
+			test.Errorf("Case %d: Did not get an expected error: '%s'.", i, testCase.locator)
+			continue
+		}
+
+		var responseContent ExportResponse
+		util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent)
+
+		if testCase.expected != responseContent.CSV {
+			test.Errorf("Case %d: Unexpected CSV. Expected: '%s', Actual: '%s'.", i, testCase.expected, responseContent.CSV)
+			continue
+		}
+	}
+}
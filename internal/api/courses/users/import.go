@@ -0,0 +1,91 @@
+package users
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/procedures/users"
+)
+
+type ImportRequest struct {
+	core.APIRequestCourseUserContext
+	core.MinCourseRoleAdmin
+
+	// The contents of a CSV file (with a header row).
+	CSV string `json:"csv" required:""`
+
+	users.CSVImportOptions
+
+	SkipInserts bool `json:"skip-inserts"`
+	SkipUpdates bool `json:"skip-updates"`
+
+	// Send any relevant email (usually about creation or password changing).
+	SendEmails bool `json:"send-emails"`
+
+	// Imports are dry runs by default,
+	// changes are only committed (and emails only sent) when this is true.
+	Commit bool `json:"commit"`
+}
+
+type ImportResponse struct {
+	Results []*model.ExternalUserOpResult `json:"results"`
+}
+
+// Enroll users into the course from a CSV file.
+// Any course or server role columns are ignored (all users are enrolled in this course).
+// Unless the import is committed, this only reports what would happen.
+func HandleImport(request *ImportRequest) (*ImportResponse, *core.APIError) {
+	rawUsers, err := users.ReadUsersCSV(strings.NewReader(request.CSV), request.CSVImportOptions)
+	if err != nil {
+		return nil, core.NewBadRequestError("-671", request, "Failed to read users from CSV.").Err(err)
+	}
+
+	for _, rawUser := range rawUsers {
+		rawUser.Role = ""
+		rawUser.Course = request.Course.GetID()
+
+		if rawUser.CourseRole == "" {
+			rawUser.CourseRole = request.DefaultCourseRole
+		}
+	}
+
+	options := users.UpsertUsersOptions{
+		RawUsers: rawUsers,
+
+		SkipInserts: request.SkipInserts,
+		SkipUpdates: request.SkipUpdates,
+		SendEmails:  request.SendEmails,
+		DryRun:      !request.Commit,
+
+		ContextEmail:      request.ServerUser.Email,
+		ContextServerRole: request.ServerUser.Role,
+		ContextCourseRole: request.User.Role,
+	}
+
+	before, err := db.GetServerUsers()
+	if err != nil {
+		return nil, core.NewInternalError("-672", request, "Failed to get server users.").Err(err)
+	}
+
+	results := users.UpsertUsers(options)
+
+	if !options.DryRun {
+		for _, record := range core.NewUserOpAuditRecords(model.AuditActionCourseEnroll, request.Course.GetID(), before, results) {
+			request.Audit(record)
+		}
+	}
+
+	var response ImportResponse
+
+	// Convert UserOpResults to user friendly ExternalUserOpResults.
+	for _, result := range results {
+		response.Results = append(response.Results, result.ToExternalResult())
+	}
+
+	slices.SortFunc(response.Results, model.CompareExternalUserOpResultPointer)
+
+	return &response, nil
+}
@@ -0,0 +1,163 @@
+package users
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestImport(test *testing.T) {
+	defer db.ResetForTesting()
+
+	testCases := []struct {
+		email             string
+		csv               string
+		defaultCourseRole string
+		commit            bool
+		expected          []*model.ExternalUserOpResult
+		expectedRole      model.CourseUserRole
+		locator           string
+	}{
+		// Dry run (default).
+		{
+			email:             "course-admin",
+			csv:               "email,name\nnew@test.edulinq.org,new\n",
+			defaultCourseRole: "student",
+			expected: []*model.ExternalUserOpResult{
+				&model.ExternalUserOpResult{
+					BaseUserOpResult: model.BaseUserOpResult{
+						Email:    "new@test.edulinq.org",
+						Added:    true,
+						Enrolled: []string{"course101"},
+					},
+				},
+			},
+		},
+
+		// Commit, course and server roles are ignored.
+		{
+			email:             "course-admin",
+			csv:               "email,name,role,course,course-role\nnew@test.edulinq.org,new,owner,course-languages,\n",
+			defaultCourseRole: "grader",
+			commit:            true,
+			expected: []*model.ExternalUserOpResult{
+				&model.ExternalUserOpResult{
+					BaseUserOpResult: model.BaseUserOpResult{
+						Email:    "new@test.edulinq.org",
+						Added:    true,
+						Enrolled: []string{"course101"},
+					},
+				},
+			},
+			expectedRole: model.CourseRoleGrader,
+		},
+
+		// Permissions are still checked per-row.
+		{
+			email:  "course-admin",
+			csv:    "email,course-role\nnew@test.edulinq.org,owner\n",
+			commit: true,
+			expected: []*model.ExternalUserOpResult{
+				&model.ExternalUserOpResult{
+					BaseUserOpResult: model.BaseUserOpResult{
+						Email: "new@test.edulinq.org",
+					},
+					ValidationError: &model.ExternalLocatableError{
+						Locator: "",
+						Message: VALIDATION_ERROR_EXTERNAL_MESSAGE,
+					},
+				},
+			},
+		},
+
+		// Invalid CSV.
+		{
+			email:   "course-admin",
+			csv:     "",
+			locator: "-671",
+		},
+
+		// Invalid permissions.
+		{
+			email:   "course-grader",
+			csv:     "email\nnew@test.edulinq.org\n",
+			locator: "-020",
+		},
+	}
+
+	for i, testCase := range testCases {
+		db.ResetForTesting()
+
+		fields := map[string]any{
+			"csv":                 testCase.csv,
+			"default-course-role": testCase.defaultCourseRole,
+			"commit":              testCase.commit,
+		}
+
+		response := core.SendTestAPIRequestFull(test, `courses/users/import`, fields, nil, testCase.email)
+		if !response.Success {
+			if testCase.locator != "" {
+				if response.Locator != testCase.locator {
+					test.Errorf("Case %d: Incorrect error returned. Expected '%s', found '%s'.",
+						i, testCase.locator, response.Locator)
+				}
+			} else {
+				test.Errorf("Case %d: Response is not a success when it should be: '%v'.", i, response)
+			}
+
+			continue
+		}
+
+		if testCase.locator != "" {
+			test.Errorf("Case %d: Did not get an expected error: '%s'.", i, testCase.locator)
+			continue
+		}
+
+		var responseContent ImportResponse
+		util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent)
+
+		if !reflect.DeepEqual(testCase.expected, responseContent.Results) {
+			test.Errorf("Case %d: Unexpected user op response. Expected: '%s', actual: '%s'.",
+				i, util.MustToJSONIndent(testCase.expected), util.MustToJSONIndent(responseContent.Results))
+			continue
+		}
+
+		user, err := db.GetServerUser("new@test.edulinq.org")
+		if err != nil {
+			test.Errorf("Case %d: Failed to get user: '%v'.", i, err)
+			continue
+		}
+
+		if testCase.expectedRole == model.CourseRoleUnknown {
+			if user != nil {
+				test.Errorf("Case %d: User was unexpectedly created.", i)
+			}
+
+			continue
+		}
+
+		if user == nil {
+			test.Errorf("Case %d: User was not created.", i)
+			continue
+		}
+
+		if user.Role != model.ServerRoleUser {
+			test.Errorf("Case %d: Unexpected server role. Expected: 'user', Actual: '%s'.", i, user.Role.String())
+			continue
+		}
+
+		if !reflect.DeepEqual([]string{"course101"}, user.GetCourses()) {
+			test.Errorf("Case %d: Unexpected courses. Expected: '%v', Actual: '%v'.", i, []string{"course101"}, user.GetCourses())
+			continue
+		}
+
+		if testCase.expectedRole != user.GetCourseRole("course101") {
+			test.Errorf("Case %d: Unexpected course role. Expected: '%s', Actual: '%s'.", i, testCase.expectedRole.String(), user.GetCourseRole("course101").String())
+			continue
+		}
+	}
+}
@@ -18,6 +18,7 @@ type ListResponse struct {
 }
 
 // List the users in the course.
+// Users limited to sections (e.g., section graders) will only see users in their sections.
 func HandleList(request *ListRequest) (*ListResponse, *core.APIError) {
 	// Default to listing all users in the course.
 	if len(request.TargetUsers) == 0 {
@@ -29,6 +30,9 @@ func HandleList(request *ListRequest) (*ListResponse, *core.APIError) {
 		return nil, core.NewBadRequestError("-635", request, "Failed to parse target users.").Err(err)
 	}
 
+	// Users limited to their sections (e.g., section graders) can only see users in those sections.
+	reference.RestrictToSections(request.User.GetSectionScope())
+
 	users := model.ResolveCourseUsers(request.Users, reference)
 
 	response := ListResponse{core.NewCourseUserInfos(users)}
@@ -2,6 +2,7 @@ package users
 
 import (
 	"reflect"
+	"slices"
 	"testing"
 
 	"github.com/edulinq/autograder/internal/api/core"
@@ -155,3 +156,66 @@ func TestListEmptyCourse(test *testing.T) {
 			util.MustToJSONIndent(expectedInfos), util.MustToJSONIndent(responseContent.Users))
 	}
 }
+
+func TestListSections(test *testing.T) {
+	defer db.ResetForTesting()
+
+	db.MustSetTestSections(db.TEST_COURSE_ID, map[string][]string{
+		"course-grader@test.edulinq.org":  []string{"lab-a"},
+		"course-student@test.edulinq.org": []string{"lab-a"},
+		"course-other@test.edulinq.org":   []string{"lab-b"},
+	})
+
+	testCases := []struct {
+		email          string
+		input          []string
+		expectedEmails []string
+	}{
+		// Section graders only see their sections.
+		{"course-grader", nil, []string{"course-grader@test.edulinq.org", "course-student@test.edulinq.org"}},
+		{"course-grader", []string{"section:lab-a"}, []string{"course-grader@test.edulinq.org", "course-student@test.edulinq.org"}},
+		{"course-grader", []string{"section:lab-b"}, []string{}},
+		{"course-grader", []string{"course-other@test.edulinq.org"}, []string{}},
+
+		// Admins see everyone.
+		{"course-admin", []string{"section:lab-b"}, []string{"course-other@test.edulinq.org"}},
+		{
+			"course-admin",
+			nil,
+			[]string{
+				"course-admin@test.edulinq.org",
+				"course-grader@test.edulinq.org",
+				"course-other@test.edulinq.org",
+				"course-owner@test.edulinq.org",
+				"course-student@test.edulinq.org",
+			},
+		},
+	}
+
+	for i, testCase := range testCases {
+		fields := map[string]any{
+			"target-users": testCase.input,
+		}
+
+		response := core.SendTestAPIRequestFull(test, `courses/users/list`, fields, nil, testCase.email)
+		if !response.Success {
+			test.Errorf("Case %d: Response is not a success when it should be: '%v'.", i, response)
+			continue
+		}
+
+		var responseContent ListResponse
+		util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent)
+
+		actualEmails := make([]string, 0, len(responseContent.Users))
+		for _, info := range responseContent.Users {
+			actualEmails = append(actualEmails, info.Email)
+		}
+
+		slices.Sort(actualEmails)
+
+		if !reflect.DeepEqual(testCase.expectedEmails, actualEmails) {
+			test.Errorf("Case %d: Unexpected users. Expected: '%v', Actual: '%v'.", i, testCase.expectedEmails, actualEmails)
+			continue
+		}
+	}
+}
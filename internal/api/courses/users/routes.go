@@ -9,7 +9,9 @@ import (
 var routes []core.Route = []core.Route{
 	core.MustNewAPIRoute(`courses/users/drop`, HandleDrop),
 	core.MustNewAPIRoute(`courses/users/enroll`, HandleEnroll),
+	core.MustNewAPIRoute(`courses/users/export`, HandleExport),
 	core.MustNewAPIRoute(`courses/users/get`, HandleGet),
+	core.MustNewAPIRoute(`courses/users/import`, HandleImport),
 	core.MustNewAPIRoute(`courses/users/list`, HandleList),
 }
 
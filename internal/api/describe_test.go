@@ -8,6 +8,7 @@ import (
 	"github.com/edulinq/autograder/internal/api/core"
 	courseUsers "github.com/edulinq/autograder/internal/api/courses/users"
 	"github.com/edulinq/autograder/internal/api/users"
+	"github.com/edulinq/autograder/internal/model"
 	"github.com/edulinq/autograder/internal/util"
 )
 
@@ -57,6 +58,22 @@ func TestDescribeRoutesEmptyDescription(test *testing.T) {
 	}
 }
 
+// Read-only endpoints are listed in full, so make sure each one is a real endpoint.
+func TestReadOnlyEndpointsExist(test *testing.T) {
+	descriptions, err := core.DescribeRoutes(*GetRoutes())
+	if err != nil {
+		test.Fatalf("Failed to describe endpoints: '%v'.", err)
+	}
+
+	for _, endpoint := range model.GetReadOnlyEndpoints() {
+		_, ok := descriptions.Endpoints[endpoint]
+		if !ok {
+			test.Errorf("Read-only endpoint does not exist: '%s'.", endpoint)
+			continue
+		}
+	}
+}
+
 // Test types with conflicting names in `internal/api` to avoid cycles when importing `users.ListRequest` and `courses/users.ListRequest`.
 func TestDescribeTypeConflictingNames(test *testing.T) {
 	info := core.TypeInfoCache{
@@ -1,9 +1,12 @@
 package upload
 
 import (
+	"fmt"
+
 	"github.com/edulinq/autograder/internal/api/core"
 	"github.com/edulinq/autograder/internal/lms"
 	"github.com/edulinq/autograder/internal/lms/lmstypes"
+	"github.com/edulinq/autograder/internal/model"
 )
 
 type UploadScoresRequest struct {
@@ -57,6 +60,12 @@ func HandleUploadScores(request *UploadScoresRequest) (*UploadScoresResponse, *c
 			"Failed to upload LMS scores.").Err(err)
 	}
 
+	record := model.NewAuditRecord(model.AuditActionLMSUploadScores, string(request.AssignmentLMSID))
+	record.After = map[string]string{
+		"count": fmt.Sprintf("%d", response.Count),
+	}
+	request.Audit(record)
+
 	return &response, nil
 }
 
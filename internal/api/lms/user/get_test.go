@@ -26,6 +26,7 @@ func TestUserGet(test *testing.T) {
 				},
 				model.CourseRoleOther,
 				"lms-course-other@test.edulinq.org",
+				nil,
 			},
 		},
 		{
@@ -38,6 +39,7 @@ func TestUserGet(test *testing.T) {
 				},
 				model.CourseRoleStudent,
 				"lms-course-student@test.edulinq.org",
+				nil,
 			},
 		},
 		{
@@ -50,6 +52,7 @@ func TestUserGet(test *testing.T) {
 				},
 				model.CourseRoleGrader,
 				"lms-course-grader@test.edulinq.org",
+				nil,
 			},
 		},
 		{
@@ -62,6 +65,7 @@ func TestUserGet(test *testing.T) {
 				},
 				model.CourseRoleAdmin,
 				"lms-course-admin@test.edulinq.org",
+				nil,
 			},
 		},
 		{
@@ -74,6 +78,7 @@ func TestUserGet(test *testing.T) {
 				},
 				model.CourseRoleOwner,
 				"lms-course-owner@test.edulinq.org",
+				nil,
 			},
 		},
 
@@ -3,6 +3,7 @@ package api
 // All routes handled by the server.
 
 import (
+	"github.com/edulinq/autograder/internal/api/audit"
 	"github.com/edulinq/autograder/internal/api/core"
 	"github.com/edulinq/autograder/internal/api/courses"
 	"github.com/edulinq/autograder/internal/api/lms"
@@ -27,6 +28,7 @@ func GetRoutes() *[]core.Route {
 	routes := make([]core.Route, 0)
 
 	routes = append(routes, baseRoutes...)
+	routes = append(routes, *(audit.GetRoutes())...)
 	routes = append(routes, *(courses.GetRoutes())...)
 	routes = append(routes, *(lms.GetRoutes())...)
 	routes = append(routes, *(logs.GetRoutes())...)
@@ -0,0 +1,51 @@
+package twofactor
+
+import (
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+)
+
+type DisableRequest struct {
+	core.APIRequestUserContext
+	core.MinServerRoleUser
+
+	TargetUser core.TargetServerUserSelfOrAdmin `json:"target-user"`
+}
+
+type DisableResponse struct {
+	FoundUser bool `json:"found-user"`
+
+	// True if the user had two-factor auth enabled (or a pending enrollment).
+	Disabled bool `json:"disabled"`
+}
+
+// Disable two-factor authentication (or cancel a pending enrollment).
+// Server admins can disable two-factor auth for other users (e.g., when a user loses their device).
+func HandleDisable(request *DisableRequest) (*DisableResponse, *core.APIError) {
+	response := DisableResponse{}
+
+	if !request.TargetUser.Found {
+		return &response, nil
+	}
+
+	response.FoundUser = true
+
+	user := request.TargetUser.User
+	if (user.TwoFactor == nil) || (user.TwoFactor.Secret == "") {
+		return &response, nil
+	}
+
+	// An empty (but not nil) struct will clear the existing settings.
+	user.TwoFactor = &model.TwoFactorInfo{}
+
+	err := db.UpsertUser(user)
+	if err != nil {
+		return nil, core.NewInternalError("-840", request,
+			"Failed to save user.").Err(err)
+	}
+
+	response.Disabled = true
+
+	return &response, nil
+}
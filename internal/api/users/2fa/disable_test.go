@@ -0,0 +1,97 @@
+package twofactor
+
+import (
+	"testing"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestDisableBase(test *testing.T) {
+	testCases := []struct {
+		email       string
+		targetUser  string
+		enabled     bool
+		useCode     bool
+		missingUser bool
+		disabled    bool
+		locator     string
+	}{
+		// Self
+		{email: "course-admin@test.edulinq.org", enabled: true, useCode: true, disabled: true},
+		{email: "course-admin@test.edulinq.org", enabled: false, disabled: false},
+
+		// Self - No Code
+		{email: "course-admin@test.edulinq.org", enabled: true, useCode: false, locator: "-061"},
+
+		// Other
+		{email: "server-admin@test.edulinq.org", targetUser: "course-admin@test.edulinq.org", enabled: true, disabled: true},
+		{email: "server-admin@test.edulinq.org", targetUser: "course-admin@test.edulinq.org", enabled: false, disabled: false},
+
+		// Other - Bad Permissions
+		{email: "course-owner@test.edulinq.org", targetUser: "course-admin@test.edulinq.org", enabled: true, locator: "-046"},
+
+		// Other - User Missing
+		{email: "server-admin@test.edulinq.org", targetUser: "ZZZ@test.edulinq.org", missingUser: true},
+	}
+
+	for i, testCase := range testCases {
+		db.ResetForTesting()
+
+		targetEmail := testCase.targetUser
+		if targetEmail == "" {
+			targetEmail = testCase.email
+		}
+
+		fields := map[string]any{
+			"target-user": testCase.targetUser,
+		}
+
+		if testCase.enabled {
+			secret := enableTwoFactorForTesting(test, targetEmail)
+			if testCase.useCode {
+				fields["two-factor-code"] = mustGenerateCode(test, secret)
+			}
+		}
+
+		response := core.SendTestAPIRequestFull(test, "users/2fa/disable", fields, nil, testCase.email)
+		if !response.Success {
+			if testCase.locator != response.Locator {
+				test.Errorf("Case %d: Incorrect error returned. Expected: '%s', Actual: '%s'.",
+					i, testCase.locator, response.Locator)
+			}
+
+			continue
+		}
+
+		if testCase.locator != "" {
+			test.Errorf("Case %d: Did not get an expected error. Expected: '%s'", i, testCase.locator)
+			continue
+		}
+
+		var responseContent DisableResponse
+		util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent)
+
+		if testCase.missingUser == responseContent.FoundUser {
+			test.Errorf("Case %d: Found user not as expected. Expected: '%v', Actual: '%v'.", i, !testCase.missingUser, responseContent.FoundUser)
+			continue
+		}
+
+		if testCase.disabled != responseContent.Disabled {
+			test.Errorf("Case %d: Disabled not as expected. Expected: '%v', Actual: '%v'.", i, testCase.disabled, responseContent.Disabled)
+			continue
+		}
+
+		if testCase.missingUser {
+			continue
+		}
+
+		if db.MustGetServerUser(targetEmail).HasTwoFactor() {
+			test.Errorf("Case %d: User still has two-factor auth enabled.", i)
+			continue
+		}
+	}
+
+	db.ResetForTesting()
+}
@@ -0,0 +1,58 @@
+package twofactor
+
+import (
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/config"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/totp"
+)
+
+type EnrollRequest struct {
+	core.APIRequestUserContext
+	core.MinServerRoleUser
+}
+
+type EnrollResponse struct {
+	// The base32-encoded secret to add to an authenticator app.
+	Secret string `json:"secret"`
+
+	// An otpauth URI for the secret (usually shown as a QR code).
+	URI string `json:"uri"`
+}
+
+// Start enrolling in two-factor authentication.
+// Two-factor auth will not be enabled until a code is confirmed with users/2fa/verify.
+func HandleEnroll(request *EnrollRequest) (*EnrollResponse, *core.APIError) {
+	user := request.ServerUser
+
+	if user.HasTwoFactor() {
+		return nil, core.NewBadRequestError("-832", request,
+			"Two-factor authentication is already enabled. Disable it before enrolling again.")
+	}
+
+	secret, err := totp.NewSecret()
+	if err != nil {
+		return nil, core.NewInternalError("-833", request,
+			"Failed to create two-factor secret.").Err(err)
+	}
+
+	user.TwoFactor = &model.TwoFactorInfo{
+		Secret:     secret,
+		Enabled:    false,
+		EnrollTime: request.Timestamp,
+	}
+
+	err = db.UpsertUser(user)
+	if err != nil {
+		return nil, core.NewInternalError("-834", request,
+			"Failed to save user.").Err(err)
+	}
+
+	response := EnrollResponse{
+		Secret: secret,
+		URI:    totp.GetURI(secret, config.TWO_FACTOR_ISSUER.Get(), user.Email),
+	}
+
+	return &response, nil
+}
@@ -0,0 +1,80 @@
+package twofactor
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestEnrollBase(test *testing.T) {
+	defer db.ResetForTesting()
+
+	email := "course-admin@test.edulinq.org"
+
+	response := core.SendTestAPIRequestFull(test, "users/2fa/enroll", nil, nil, email)
+	if !response.Success {
+		test.Fatalf("Response is not a success when it should be: '%v'.", response)
+	}
+
+	var responseContent EnrollResponse
+	util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent)
+
+	if responseContent.Secret == "" {
+		test.Fatalf("Got an empty secret.")
+	}
+
+	if !strings.HasPrefix(responseContent.URI, "otpauth://totp/") || !strings.Contains(responseContent.URI, responseContent.Secret) {
+		test.Fatalf("Unexpected URI: '%s'.", responseContent.URI)
+	}
+
+	user := db.MustGetServerUser(email)
+	if user.TwoFactor == nil {
+		test.Fatalf("User does not have two-factor info.")
+	}
+
+	if user.TwoFactor.Secret != responseContent.Secret {
+		test.Fatalf("Unexpected secret. Expected: '%s', Actual: '%s'.", responseContent.Secret, user.TwoFactor.Secret)
+	}
+
+	// Two-factor auth is not enabled until verified.
+	if user.HasTwoFactor() {
+		test.Fatalf("Two-factor auth was enabled before verification.")
+	}
+
+	// Enrolling again replaces the pending secret.
+	response = core.SendTestAPIRequestFull(test, "users/2fa/enroll", nil, nil, email)
+	if !response.Success {
+		test.Fatalf("Second response is not a success when it should be: '%v'.", response)
+	}
+
+	var secondResponseContent EnrollResponse
+	util.MustJSONFromString(util.MustToJSON(response.Content), &secondResponseContent)
+
+	if secondResponseContent.Secret == responseContent.Secret {
+		test.Fatalf("Second enrollment did not get a new secret.")
+	}
+}
+
+func TestEnrollAlreadyEnabled(test *testing.T) {
+	defer db.ResetForTesting()
+
+	email := "course-admin@test.edulinq.org"
+	secret := enableTwoFactorForTesting(test, email)
+
+	fields := map[string]any{
+		"two-factor-code": mustGenerateCode(test, secret),
+	}
+
+	response := core.SendTestAPIRequestFull(test, "users/2fa/enroll", fields, nil, email)
+	if response.Success {
+		test.Fatalf("Response is a success when it should not be.")
+	}
+
+	expectedLocator := "-832"
+	if response.Locator != expectedLocator {
+		test.Fatalf("Incorrect error returned. Expected: '%s', Actual: '%s'.", expectedLocator, response.Locator)
+	}
+}
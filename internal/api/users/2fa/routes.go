@@ -0,0 +1,17 @@
+package twofactor
+
+// All the API endpoints handled by this package.
+
+import (
+	"github.com/edulinq/autograder/internal/api/core"
+)
+
+var routes []core.Route = []core.Route{
+	core.MustNewAPIRoute(`users/2fa/disable`, HandleDisable),
+	core.MustNewAPIRoute(`users/2fa/enroll`, HandleEnroll),
+	core.MustNewAPIRoute(`users/2fa/verify`, HandleVerify),
+}
+
+func GetRoutes() *[]core.Route {
+	return &routes
+}
@@ -0,0 +1,56 @@
+package twofactor
+
+import (
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/totp"
+)
+
+type VerifyRequest struct {
+	core.APIRequestUserContext
+	core.MinServerRoleUser
+
+	// A code generated from the secret given by users/2fa/enroll.
+	Code core.NonEmptyString `json:"code" required:""`
+}
+
+type VerifyResponse struct {
+	Enabled bool `json:"enabled"`
+}
+
+// Finish enrolling in two-factor authentication by confirming a code.
+func HandleVerify(request *VerifyRequest) (*VerifyResponse, *core.APIError) {
+	user := request.ServerUser
+
+	if user.HasTwoFactor() {
+		return nil, core.NewBadRequestError("-835", request,
+			"Two-factor authentication is already enabled.")
+	}
+
+	if (user.TwoFactor == nil) || (user.TwoFactor.Secret == "") {
+		return nil, core.NewBadRequestError("-836", request,
+			"No pending two-factor enrollment. Enroll using the 'users/2fa/enroll' endpoint.")
+	}
+
+	valid, timeStep, err := totp.ValidateCodeTimeStep(user.TwoFactor.Secret, string(request.Code), request.Timestamp)
+	if err != nil {
+		return nil, core.NewInternalError("-837", request,
+			"Failed to check two-factor code.").Err(err)
+	}
+
+	if !valid {
+		return nil, core.NewBadRequestError("-838", request,
+			"Incorrect two-factor code.")
+	}
+
+	user.TwoFactor.Enabled = true
+	user.TwoFactor.LastTimeStep = timeStep
+
+	err = db.UpsertUser(user)
+	if err != nil {
+		return nil, core.NewInternalError("-839", request,
+			"Failed to save user.").Err(err)
+	}
+
+	return &VerifyResponse{Enabled: true}, nil
+}
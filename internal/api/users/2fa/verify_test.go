@@ -0,0 +1,125 @@
+package twofactor
+
+import (
+	"testing"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/timestamp"
+	"github.com/edulinq/autograder/internal/totp"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestVerifyBase(test *testing.T) {
+	defer db.ResetForTesting()
+
+	email := "course-admin@test.edulinq.org"
+
+	// No pending enrollment.
+	fields := map[string]any{
+		"code": "123456",
+	}
+
+	response := core.SendTestAPIRequestFull(test, "users/2fa/verify", fields, nil, email)
+	checkErrorResponse(test, "No Enrollment", response, "-836")
+
+	response = core.SendTestAPIRequestFull(test, "users/2fa/enroll", nil, nil, email)
+	if !response.Success {
+		test.Fatalf("Enroll response is not a success when it should be: '%v'.", response)
+	}
+
+	var enrollContent EnrollResponse
+	util.MustJSONFromString(util.MustToJSON(response.Content), &enrollContent)
+
+	// Bad code.
+	fields["code"] = "ZZZ"
+	response = core.SendTestAPIRequestFull(test, "users/2fa/verify", fields, nil, email)
+	checkErrorResponse(test, "Bad Code", response, "-838")
+
+	// No code.
+	fields["code"] = ""
+	response = core.SendTestAPIRequestFull(test, "users/2fa/verify", fields, nil, email)
+	checkErrorResponse(test, "No Code", response, "-038")
+
+	if db.MustGetServerUser(email).HasTwoFactor() {
+		test.Fatalf("Two-factor auth was enabled after failed verifications.")
+	}
+
+	fields["code"] = mustGenerateCode(test, enrollContent.Secret)
+	response = core.SendTestAPIRequestFull(test, "users/2fa/verify", fields, nil, email)
+	if !response.Success {
+		test.Fatalf("Verify response is not a success when it should be: '%v'.", response)
+	}
+
+	var responseContent VerifyResponse
+	util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent)
+
+	if !responseContent.Enabled {
+		test.Fatalf("Response does not indicate that two-factor auth is enabled.")
+	}
+
+	if !db.MustGetServerUser(email).HasTwoFactor() {
+		test.Fatalf("Two-factor auth was not enabled after verification.")
+	}
+
+	// Requests now require a code.
+	response = core.SendTestAPIRequestFull(test, "users/2fa/verify", fields, nil, email)
+	checkErrorResponse(test, "Already Enabled (No Auth Code)", response, "-061")
+
+	// The code used to verify cannot be reused.
+	// (Auth errors do not expose their locator.)
+	fields["two-factor-code"] = fields["code"]
+	response = core.SendTestAPIRequestFull(test, "users/2fa/verify", fields, nil, email)
+	checkErrorResponse(test, "Already Enabled (Reused Code)", response, "")
+
+	// Use the code for the next period (which is still accepted).
+	nextCode, err := totp.GenerateCode(enrollContent.Secret, timestamp.Now()+timestamp.FromMSecs(totp.PERIOD_SECS*1000))
+	if err != nil {
+		test.Fatalf("Failed to generate code: '%v'.", err)
+	}
+
+	fields["two-factor-code"] = nextCode
+	response = core.SendTestAPIRequestFull(test, "users/2fa/verify", fields, nil, email)
+	checkErrorResponse(test, "Already Enabled", response, "-835")
+}
+
+func enableTwoFactorForTesting(test *testing.T, email string) string {
+	secret, err := totp.NewSecret()
+	if err != nil {
+		test.Fatalf("Failed to create secret: '%v'.", err)
+	}
+
+	user := db.MustGetServerUser(email)
+	user.TwoFactor = &model.TwoFactorInfo{
+		Secret:     secret,
+		Enabled:    true,
+		EnrollTime: timestamp.Now(),
+	}
+
+	err = db.UpsertUser(user)
+	if err != nil {
+		test.Fatalf("Failed to save user: '%v'.", err)
+	}
+
+	return secret
+}
+
+func mustGenerateCode(test *testing.T, secret string) string {
+	code, err := totp.GenerateCode(secret, timestamp.Now())
+	if err != nil {
+		test.Fatalf("Failed to generate code: '%v'.", err)
+	}
+
+	return code
+}
+
+func checkErrorResponse(test *testing.T, label string, response *core.APIResponse, expectedLocator string) {
+	if response.Success {
+		test.Fatalf("%s: Response is a success when it should not be.", label)
+	}
+
+	if response.Locator != expectedLocator {
+		test.Fatalf("%s: Incorrect error returned. Expected: '%s', Actual: '%s'.", label, expectedLocator, response.Locator)
+	}
+}
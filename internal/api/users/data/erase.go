@@ -0,0 +1,53 @@
+package data
+
+import (
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/procedures/users"
+)
+
+type EraseDataRequest struct {
+	core.APIRequestUserContext
+	core.MinServerRoleOwner
+
+	// The target user does not need to exist on the server (e.g., their submissions may remain after removal).
+	TargetUser core.TargetServerUser `json:"target-email" required:""`
+}
+
+type EraseDataResponse struct {
+	FoundUser bool                       `json:"found-user"`
+	Result    *users.EraseUserDataResult `json:"result"`
+}
+
+// Erase all the data stored on the server that references a user.
+// Data used in course aggregates is pseudonymized, everything else (including the user) is removed.
+func HandleEraseData(request *EraseDataRequest) (*EraseDataResponse, *core.APIError) {
+	response := EraseDataResponse{
+		FoundUser: request.TargetUser.Found,
+	}
+
+	var before map[string]string = nil
+
+	if request.TargetUser.Found {
+		if request.TargetUser.User.Role >= request.ServerUser.Role {
+			return nil, core.NewPermissionsError("-849", request, request.TargetUser.User.Role, request.ServerUser.Role,
+				"Cannot erase a user with an equal or higher role.").Add("target-user", request.TargetUser.Email)
+		}
+
+		before = request.TargetUser.User.GetAuditSummary("")
+	}
+
+	result, err := users.EraseUserData(request.TargetUser.Email)
+	if err != nil {
+		return nil, core.NewInternalError("-850", request,
+			"Failed to erase user data.").Err(err).Add("target-user", request.TargetUser.Email)
+	}
+
+	response.Result = result
+
+	record := model.NewAuditRecord(model.AuditActionUserErase, request.TargetUser.Email)
+	record.Before = before
+	request.Audit(record)
+
+	return &response, nil
+}
@@ -0,0 +1,92 @@
+package data
+
+import (
+	"testing"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestErase(test *testing.T) {
+	defer db.ResetForTesting()
+
+	testCases := []struct {
+		email               string
+		targetEmail         string
+		locator             string
+		foundUser           bool
+		expectedSubmissions int
+	}{
+		{"server-owner", "course-student@test.edulinq.org", "", true, 5},
+		{"server-owner", "server-admin@test.edulinq.org", "", true, 0},
+		{"server-owner", "zzz@test.edulinq.org", "", false, 0},
+
+		// Equal role.
+		{"server-owner", "server-owner@test.edulinq.org", "-849", true, 0},
+
+		// Bad permissions.
+		{"server-admin", "course-student@test.edulinq.org", "-041", false, 0},
+		{"course-owner", "course-student@test.edulinq.org", "-041", false, 0},
+	}
+
+	for i, testCase := range testCases {
+		db.ResetForTesting()
+
+		fields := map[string]any{
+			"target-email": testCase.targetEmail,
+		}
+
+		response := core.SendTestAPIRequestFull(test, "users/data/erase", fields, nil, testCase.email)
+		if !response.Success {
+			if testCase.locator != response.Locator {
+				test.Errorf("Case %d: Incorrect error returned. Expected: '%s', Actual: '%s'.",
+					i, testCase.locator, response.Locator)
+			}
+
+			continue
+		}
+
+		if testCase.locator != "" {
+			test.Errorf("Case %d: Did not get an expected error. Expected: '%s'.", i, testCase.locator)
+			continue
+		}
+
+		var responseContent EraseDataResponse
+		util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent)
+
+		if testCase.foundUser != responseContent.FoundUser {
+			test.Errorf("Case %d: Unexpected found user. Expected: %v, Actual: %v.", i, testCase.foundUser, responseContent.FoundUser)
+			continue
+		}
+
+		if testCase.expectedSubmissions != responseContent.Result.Submissions {
+			test.Errorf("Case %d: Unexpected number of erased submissions. Expected: %d, Actual: %d.",
+				i, testCase.expectedSubmissions, responseContent.Result.Submissions)
+			continue
+		}
+
+		user, err := db.GetServerUser(testCase.targetEmail)
+		if err != nil {
+			test.Errorf("Case %d: Failed to get user: '%v'.", i, err)
+			continue
+		}
+
+		if user != nil {
+			test.Errorf("Case %d: User was not removed.", i)
+			continue
+		}
+
+		records, err := db.GetAuditRecords(model.AuditQuery{Action: model.AuditActionUserErase, Target: testCase.targetEmail})
+		if err != nil {
+			test.Errorf("Case %d: Failed to get audit records: '%v'.", i, err)
+			continue
+		}
+
+		if len(records) != 1 {
+			test.Errorf("Case %d: Unexpected number of audit records. Expected: 1, Actual: %d.", i, len(records))
+			continue
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package data
+
+import (
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/procedures/users"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+type ExportDataRequest struct {
+	core.APIRequestUserContext
+	core.MinServerRoleOwner
+
+	// The target user does not need to exist on the server (e.g., their submissions may remain after removal).
+	TargetUser core.TargetServerUser `json:"target-email" required:""`
+}
+
+type ExportDataResponse struct {
+	FoundUser bool `json:"found-user"`
+
+	// A base64-encoded zip file containing all the user's data.
+	Bytes string `json:"bytes"`
+}
+
+// Export all the data stored on the server that references a user (as a zip file).
+func HandleExportData(request *ExportDataRequest) (*ExportDataResponse, *core.APIError) {
+	data, err := users.ExportUserData(request.TargetUser.Email)
+	if err != nil {
+		return nil, core.NewInternalError("-848", request,
+			"Failed to export user data.").Err(err).Add("target-user", request.TargetUser.Email)
+	}
+
+	response := ExportDataResponse{
+		FoundUser: request.TargetUser.Found,
+		Bytes:     util.Base64Encode(data),
+	}
+
+	return &response, nil
+}
@@ -0,0 +1,69 @@
+package data
+
+import (
+	"testing"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestExport(test *testing.T) {
+	testCases := []struct {
+		email       string
+		targetEmail string
+		locator     string
+		foundUser   bool
+	}{
+		{"server-owner", "course-student@test.edulinq.org", "", true},
+		{"server-owner", "server-admin@test.edulinq.org", "", true},
+		{"server-owner", "zzz@test.edulinq.org", "", false},
+
+		// Bad permissions.
+		{"server-admin", "course-student@test.edulinq.org", "-041", false},
+		{"course-owner", "course-student@test.edulinq.org", "-041", false},
+	}
+
+	for i, testCase := range testCases {
+		fields := map[string]any{
+			"target-email": testCase.targetEmail,
+		}
+
+		response := core.SendTestAPIRequestFull(test, "users/data/export", fields, nil, testCase.email)
+		if !response.Success {
+			if testCase.locator != response.Locator {
+				test.Errorf("Case %d: Incorrect error returned. Expected: '%s', Actual: '%s'.",
+					i, testCase.locator, response.Locator)
+			}
+
+			continue
+		}
+
+		if testCase.locator != "" {
+			test.Errorf("Case %d: Did not get an expected error. Expected: '%s'.", i, testCase.locator)
+			continue
+		}
+
+		var responseContent ExportDataResponse
+		util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent)
+
+		if testCase.foundUser != responseContent.FoundUser {
+			test.Errorf("Case %d: Unexpected found user. Expected: %v, Actual: %v.", i, testCase.foundUser, responseContent.FoundUser)
+			continue
+		}
+
+		data, err := util.Base64Decode(responseContent.Bytes)
+		if err != nil {
+			test.Errorf("Case %d: Failed to decode bytes: '%v'.", i, err)
+			continue
+		}
+
+		tempDir := util.MustMkDirTemp("test-users-data-export-")
+		defer util.RemoveDirent(tempDir)
+
+		err = util.UnzipFromBytes(data, tempDir)
+		if err != nil {
+			test.Errorf("Case %d: Failed to unzip data: '%v'.", i, err)
+			continue
+		}
+	}
+}
@@ -0,0 +1,16 @@
+package data
+
+// All the API endpoints handled by this package.
+
+import (
+	"github.com/edulinq/autograder/internal/api/core"
+)
+
+var routes []core.Route = []core.Route{
+	core.MustNewAPIRoute(`users/data/erase`, HandleEraseData),
+	core.MustNewAPIRoute(`users/data/export`, HandleExportData),
+}
+
+func GetRoutes() *[]core.Route {
+	return &routes
+}
@@ -0,0 +1,54 @@
+package users
+
+import (
+	"strings"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/procedures/users"
+)
+
+type ExportRequest struct {
+	core.APIRequestUserContext
+	core.MinServerRoleAdmin
+
+	TargetUsers []model.ServerUserReference `json:"target-users"`
+}
+
+type ExportResponse struct {
+	// The exported users as a CSV (see users/import).
+	CSV string `json:"csv"`
+}
+
+// Export the users on the server as a CSV.
+func HandleExport(request *ExportRequest) (*ExportResponse, *core.APIError) {
+	// Default to exporting all users in the server.
+	if len(request.TargetUsers) == 0 {
+		request.TargetUsers = model.NewAllServerUserReference()
+	}
+
+	courses, err := db.GetCourses()
+	if err != nil {
+		return nil, core.NewInternalError("-844", request, "Failed to get courses from database.").Err(err)
+	}
+
+	reference, err := model.ParseServerUserReferences(request.TargetUsers, courses)
+	if err != nil {
+		return nil, core.NewBadRequestError("-845", request, "Failed to parse target users.").Err(err)
+	}
+
+	usersMap, err := db.GetServerUsers()
+	if err != nil {
+		return nil, core.NewInternalError("-846", request, "Failed to get server users from database.").Err(err)
+	}
+
+	var builder strings.Builder
+
+	err = users.WriteServerUsersCSV(&builder, model.ResolveServerUsers(usersMap, reference))
+	if err != nil {
+		return nil, core.NewInternalError("-847", request, "Failed to write users as a CSV.").Err(err)
+	}
+
+	return &ExportResponse{builder.String()}, nil
+}
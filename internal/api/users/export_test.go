@@ -0,0 +1,71 @@
+package users
+
+import (
+	"testing"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestExport(test *testing.T) {
+	testCases := []struct {
+		email    string
+		input    []string
+		expected string
+		locator  string
+	}{
+		// Invalid Permissions
+		{"server-user", nil, "", "-041"},
+		{"server-creator", nil, "", "-041"},
+
+		// No Users
+		{"server-admin", []string{"-*"}, "email,name,role,course,course-role,course-lms-id,course-sections\n", ""},
+
+		// Some Users
+		{
+			"server-admin",
+			[]string{"owner", "course-languages::admin"},
+			"email,name,role,course,course-role,course-lms-id,course-sections\n" +
+				"course-admin@test.edulinq.org,course-admin,user,course-languages,admin,lms-course-admin@test.edulinq.org,\n" +
+				"course-admin@test.edulinq.org,course-admin,user,course101,admin,lms-course-admin@test.edulinq.org,\n" +
+				"server-owner@test.edulinq.org,server-owner,owner,,,,\n",
+			"",
+		},
+
+		// Input Errors
+		{"server-admin", []string{"ZZZ"}, "", "-845"},
+	}
+
+	for i, testCase := range testCases {
+		fields := map[string]any{
+			"target-users": testCase.input,
+		}
+
+		response := core.SendTestAPIRequestFull(test, `users/export`, fields, nil, testCase.email)
+		if !response.Success {
+			if testCase.locator != "" {
+				if response.Locator != testCase.locator {
+					test.Errorf("Case %d: Incorrect error returned. Expected '%s', found '%s'.",
+						i, testCase.locator, response.Locator)
+				}
+			} else {
+				test.Errorf("Case %d: Response is not a success when it should be: '%v'.", i, response)
+			}
+
+			continue
+		}
+
+		if testCase.locator != "" {
+			test.Errorf("Case %d: Did not get an expected error: '%s'.", i, testCase.locator)
+			continue
+		}
+
+		var responseContent ExportResponse
+		util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent)
+
+		if testCase.expected != responseContent.CSV {
+			test.Errorf("Case %d: Unexpected CSV. Expected: '%s', Actual: '%s'.", i, testCase.expected, responseContent.CSV)
+			continue
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package users
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/procedures/users"
+)
+
+type ImportRequest struct {
+	core.APIRequestUserContext
+	core.MinServerRoleAdmin
+
+	// The contents of a CSV file (with a header row).
+	CSV string `json:"csv" required:""`
+
+	users.CSVImportOptions
+
+	SkipInserts bool `json:"skip-inserts"`
+	SkipUpdates bool `json:"skip-updates"`
+
+	// Send any relevant email (usually about creation or password changing).
+	SendEmails bool `json:"send-emails"`
+
+	// Imports are dry runs by default,
+	// changes are only committed (and emails only sent) when this is true.
+	Commit bool `json:"commit"`
+}
+
+type ImportResponse struct {
+	Results []*model.ExternalUserOpResult `json:"results"`
+}
+
+// Upsert users to the server from a CSV file.
+// Unless the import is committed, this only reports what would happen.
+func HandleImport(request *ImportRequest) (*ImportResponse, *core.APIError) {
+	rawUsers, err := users.ReadUsersCSV(strings.NewReader(request.CSV), request.CSVImportOptions)
+	if err != nil {
+		return nil, core.NewBadRequestError("-842", request, "Failed to read users from CSV.").Err(err)
+	}
+
+	options := users.UpsertUsersOptions{
+		RawUsers: rawUsers,
+
+		SkipInserts: request.SkipInserts,
+		SkipUpdates: request.SkipUpdates,
+		SendEmails:  request.SendEmails,
+		DryRun:      !request.Commit,
+
+		ContextEmail:      request.ServerUser.Email,
+		ContextServerRole: request.ServerUser.Role,
+	}
+
+	before, err := db.GetServerUsers()
+	if err != nil {
+		return nil, core.NewInternalError("-843", request, "Failed to get server users.").Err(err)
+	}
+
+	results := users.UpsertUsers(options)
+
+	if !options.DryRun {
+		for _, record := range core.NewUserOpAuditRecords(model.AuditActionUserUpsert, "", before, results) {
+			request.Audit(record)
+		}
+	}
+
+	var response ImportResponse
+
+	// Convert UserOpResults to user friendly ExternalUserOpResults.
+	for _, result := range results {
+		response.Results = append(response.Results, result.ToExternalResult())
+	}
+
+	slices.SortFunc(response.Results, model.CompareExternalUserOpResultPointer)
+
+	return &response, nil
+}
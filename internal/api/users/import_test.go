@@ -0,0 +1,177 @@
+package users
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestImport(test *testing.T) {
+	defer db.ResetForTesting()
+
+	testCases := []struct {
+		email            string
+		csv              string
+		columns          map[string]any
+		defaultCourse    string
+		commit           bool
+		expected         []*model.ExternalUserOpResult
+		expectedSections []string
+		locator          string
+	}{
+		// Dry run (default).
+		{
+			email: "server-admin",
+			csv:   "email,name,course,course-role\nnew@test.edulinq.org,new,course101,student\n",
+			expected: []*model.ExternalUserOpResult{
+				&model.ExternalUserOpResult{
+					BaseUserOpResult: model.BaseUserOpResult{
+						Email:    "new@test.edulinq.org",
+						Added:    true,
+						Enrolled: []string{"course101"},
+					},
+				},
+			},
+		},
+
+		// Commit.
+		{
+			email:  "server-admin",
+			csv:    "email,name,course,course-role,course-sections\nnew@test.edulinq.org,new,course101,student,Lab-01;lab-02\n",
+			commit: true,
+			expected: []*model.ExternalUserOpResult{
+				&model.ExternalUserOpResult{
+					BaseUserOpResult: model.BaseUserOpResult{
+						Email:    "new@test.edulinq.org",
+						Added:    true,
+						Enrolled: []string{"course101"},
+					},
+				},
+			},
+			expectedSections: []string{"lab-01", "lab-02"},
+		},
+
+		// Custom columns, multiple rows, per-row errors.
+		{
+			email:         "server-owner",
+			csv:           "Student,Lab\nnew@test.edulinq.org,lab-01\ncourse-student@test.edulinq.org,lab-03\n,lab-04\n",
+			columns:       map[string]any{"email": "Student", "course-sections": "Lab"},
+			defaultCourse: "course101",
+			commit:        true,
+			expected: []*model.ExternalUserOpResult{
+				&model.ExternalUserOpResult{
+					BaseUserOpResult: model.BaseUserOpResult{
+						Email: "",
+					},
+					ValidationError: &model.ExternalLocatableError{
+						Locator: "",
+						Message: "User email is empty.",
+					},
+				},
+				&model.ExternalUserOpResult{
+					BaseUserOpResult: model.BaseUserOpResult{
+						Email:    "course-student@test.edulinq.org",
+						Modified: true,
+					},
+				},
+				&model.ExternalUserOpResult{
+					BaseUserOpResult: model.BaseUserOpResult{
+						Email:    "new@test.edulinq.org",
+						Added:    true,
+						Enrolled: []string{"course101"},
+					},
+				},
+			},
+			expectedSections: []string{"lab-01"},
+		},
+
+		// Invalid CSV.
+		{
+			email:   "server-admin",
+			csv:     "name\nnew\n",
+			locator: "-842",
+		},
+
+		// Invalid permissions.
+		{
+			email:   "server-user",
+			csv:     "email\nnew@test.edulinq.org\n",
+			locator: "-041",
+		},
+		{
+			email:   "server-creator",
+			csv:     "email\nnew@test.edulinq.org\n",
+			locator: "-041",
+		},
+	}
+
+	for i, testCase := range testCases {
+		db.ResetForTesting()
+
+		fields := map[string]any{
+			"csv":    testCase.csv,
+			"commit": testCase.commit,
+		}
+
+		if testCase.columns != nil {
+			fields["columns"] = testCase.columns
+		}
+
+		if testCase.defaultCourse != "" {
+			fields["default-course"] = testCase.defaultCourse
+			fields["default-course-role"] = model.GetCourseUserRoleString(model.CourseRoleStudent)
+		}
+
+		response := core.SendTestAPIRequestFull(test, `users/import`, fields, nil, testCase.email)
+		if !response.Success {
+			if testCase.locator != "" {
+				if response.Locator != testCase.locator {
+					test.Errorf("Case %d: Incorrect error returned. Expected '%s', found '%s'.",
+						i, testCase.locator, response.Locator)
+				}
+			} else {
+				test.Errorf("Case %d: Response is not a success when it should be: '%v'.", i, response)
+			}
+
+			continue
+		}
+
+		if testCase.locator != "" {
+			test.Errorf("Case %d: Did not get an expected error: '%s'.", i, testCase.locator)
+			continue
+		}
+
+		var responseContent ImportResponse
+		util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent)
+
+		if !reflect.DeepEqual(testCase.expected, responseContent.Results) {
+			test.Errorf("Case %d: Unexpected user op response. Expected: '%s', actual: '%s'.",
+				i, util.MustToJSONIndent(testCase.expected), util.MustToJSONIndent(responseContent.Results))
+			continue
+		}
+
+		user, err := db.GetServerUser("new@test.edulinq.org")
+		if err != nil {
+			test.Errorf("Case %d: Failed to get user: '%v'.", i, err)
+			continue
+		}
+
+		if testCase.commit != (user != nil) {
+			test.Errorf("Case %d: Unexpected user existence. Expected: '%v', Actual: '%v'.", i, testCase.commit, (user != nil))
+			continue
+		}
+
+		if (user != nil) && (testCase.expectedSections != nil) {
+			sections := user.CourseInfo["course101"].Sections
+			if !slices.Equal(testCase.expectedSections, sections) {
+				test.Errorf("Case %d: Unexpected sections. Expected: '%v', Actual: '%v'.", i, testCase.expectedSections, sections)
+				continue
+			}
+		}
+	}
+}
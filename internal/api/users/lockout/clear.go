@@ -0,0 +1,66 @@
+package lockout
+
+import (
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+)
+
+type ClearRequest struct {
+	core.APIRequestUserContext
+	core.MinServerRoleAdmin
+
+	// The email of a user to clear failed logins for.
+	TargetEmail string `json:"target-email"`
+
+	// The IP address to clear failed logins for.
+	TargetIP string `json:"target-ip"`
+}
+
+type ClearResponse struct {
+	// True if the target email had failed logins (or a lockout) that were cleared.
+	ClearedEmail bool `json:"cleared-email"`
+
+	// True if the target IP address had failed logins (or a lockout) that were cleared.
+	ClearedIP bool `json:"cleared-ip"`
+}
+
+// Clear the failed logins (and any lockout) for a user and/or IP address.
+func HandleClear(request *ClearRequest) (*ClearResponse, *core.APIError) {
+	if (request.TargetEmail == "") && (request.TargetIP == "") {
+		return nil, core.NewBadRequestError("-829", request,
+			"At least one of a target email or target IP must be specified.")
+	}
+
+	keys := make([]string, 0, 2)
+
+	emailKey := ""
+	if request.TargetEmail != "" {
+		emailKey = model.LoginThrottleKeyForEmail(request.TargetEmail)
+		keys = append(keys, emailKey)
+	}
+
+	ipKey := ""
+	if request.TargetIP != "" {
+		ipKey = model.LoginThrottleKeyForIP(request.TargetIP)
+		keys = append(keys, ipKey)
+	}
+
+	throttles, err := db.GetLoginThrottles(keys)
+	if err != nil {
+		return nil, core.NewInternalError("-830", request,
+			"Failed to get login throttles.").Err(err)
+	}
+
+	err = db.RemoveLoginThrottles(keys)
+	if err != nil {
+		return nil, core.NewInternalError("-831", request,
+			"Failed to remove login throttles.").Err(err)
+	}
+
+	response := ClearResponse{}
+	_, response.ClearedEmail = throttles[emailKey]
+	_, response.ClearedIP = throttles[ipKey]
+
+	return &response, nil
+}
@@ -0,0 +1,101 @@
+package lockout
+
+import (
+	"testing"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/timestamp"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestClear(test *testing.T) {
+	defer db.ResetForTesting()
+
+	emailKey := model.LoginThrottleKeyForEmail("course-student@test.edulinq.org")
+	ipKey := model.LoginThrottleKeyForIP("192.0.2.1")
+
+	testCases := []struct {
+		email       string
+		targetEmail string
+		targetIP    string
+		locator     string
+		expected    ClearResponse
+		remaining   []string
+	}{
+		{"server-admin", "course-student@test.edulinq.org", "", "", ClearResponse{true, false}, []string{ipKey}},
+		{"server-admin", "", "192.0.2.1", "", ClearResponse{false, true}, []string{emailKey}},
+		{"server-owner", "course-student@test.edulinq.org", "192.0.2.1", "", ClearResponse{true, true}, []string{}},
+
+		// Nothing to clear.
+		{"server-admin", "course-grader@test.edulinq.org", "192.0.2.2", "", ClearResponse{false, false}, []string{emailKey, ipKey}},
+
+		// No target.
+		{"server-admin", "", "", "-829", ClearResponse{}, []string{emailKey, ipKey}},
+
+		// Bad permissions.
+		{"server-creator", "course-student@test.edulinq.org", "", "-041", ClearResponse{}, []string{emailKey, ipKey}},
+		{"course-owner", "course-student@test.edulinq.org", "", "-041", ClearResponse{}, []string{emailKey, ipKey}},
+	}
+
+	for i, testCase := range testCases {
+		db.ResetForTesting()
+
+		now := timestamp.Now()
+		throttles := map[string]*model.LoginThrottle{
+			emailKey: &model.LoginThrottle{Key: emailKey, Failures: 5, LastFailure: now, LockedUntil: now + 100000},
+			ipKey:    &model.LoginThrottle{Key: ipKey, Failures: 50, LastFailure: now, LockedUntil: now + 100000},
+		}
+
+		err := db.UpsertLoginThrottles(throttles)
+		if err != nil {
+			test.Errorf("Case %d: Failed to upsert throttles: '%v'.", i, err)
+			continue
+		}
+
+		fields := map[string]any{
+			"target-email": testCase.targetEmail,
+			"target-ip":    testCase.targetIP,
+		}
+
+		response := core.SendTestAPIRequestFull(test, "users/lockout/clear", fields, nil, testCase.email)
+		if !response.Success {
+			if testCase.locator != response.Locator {
+				test.Errorf("Case %d: Incorrect error returned. Expected: '%s', Actual: '%s'.",
+					i, testCase.locator, response.Locator)
+			}
+		} else if testCase.locator != "" {
+			test.Errorf("Case %d: Did not get an expected error. Expected: '%s'.", i, testCase.locator)
+			continue
+		} else {
+			var responseContent ClearResponse
+			util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent)
+
+			if testCase.expected != responseContent {
+				test.Errorf("Case %d: Unexpected response. Expected: '%s', Actual: '%s'.",
+					i, util.MustToJSONIndent(testCase.expected), util.MustToJSONIndent(responseContent))
+				continue
+			}
+		}
+
+		remaining, err := db.GetLoginThrottles([]string{emailKey, ipKey})
+		if err != nil {
+			test.Errorf("Case %d: Failed to get remaining throttles: '%v'.", i, err)
+			continue
+		}
+
+		if len(testCase.remaining) != len(remaining) {
+			test.Errorf("Case %d: Unexpected number of remaining throttles. Expected: %d, Actual: %d.",
+				i, len(testCase.remaining), len(remaining))
+			continue
+		}
+
+		for _, key := range testCase.remaining {
+			_, ok := remaining[key]
+			if !ok {
+				test.Errorf("Case %d: Throttle '%s' was unexpectedly removed.", i, key)
+			}
+		}
+	}
+}
@@ -0,0 +1,15 @@
+package lockout
+
+// All the API endpoints handled by this package.
+
+import (
+	"github.com/edulinq/autograder/internal/api/core"
+)
+
+var routes []core.Route = []core.Route{
+	core.MustNewAPIRoute(`users/lockout/clear`, HandleClear),
+}
+
+func GetRoutes() *[]core.Route {
+	return &routes
+}
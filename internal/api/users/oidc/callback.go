@@ -0,0 +1,189 @@
+package oidc
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/config"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/oidc"
+	"github.com/edulinq/autograder/internal/procedures/users"
+	"github.com/edulinq/autograder/internal/timestamp"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+const TOKEN_NAME = "oidc"
+
+type CallbackResponse struct {
+	Email          string           `json:"email"`
+	CreatedUser    bool             `json:"created-user"`
+	TokenInfo      *model.TokenInfo `json:"token-info"`
+	TokenCleartext string           `json:"token-cleartext"`
+}
+
+// Finish logging in through the configured OIDC provider.
+// The login must be finished in the same browser that started it (see HandleLogin()).
+// Failed logins count towards the same login throttles as password logins.
+// On success, a new token for the user is returned (which can then be used like a password).
+// The new token replaces any token from the user's previous OIDC login, and expires after a configured time.
+func HandleCallback(response http.ResponseWriter, request *http.Request) error {
+	content, apiError := handleCallback(request)
+
+	// The state can only be used once, so always clear the cookie.
+	http.SetCookie(response, newStateCookie(request, "", -1))
+
+	return core.SendAPIResponse(response, content, apiError)
+}
+
+func handleCallback(request *http.Request) (*CallbackResponse, *core.APIError) {
+	endpoint := request.URL.Path
+	query := request.URL.Query()
+
+	if !oidc.IsEnabled() {
+		return nil, core.NewBadRequestError("-818", endpoint, "OIDC login is not enabled.")
+	}
+
+	providerError := query.Get("error")
+	if providerError != "" {
+		return nil, core.NewBadRequestError("-819", endpoint, fmt.Sprintf("OIDC provider returned an error: '%s'.", providerError)).
+			Add("error-description", query.Get("error_description"))
+	}
+
+	// The user is not known until the login is finished, so early failures only count against the sender.
+	loginContext := core.NewExternalLoginContext(request, "")
+
+	state := query.Get("state")
+
+	cookie, err := request.Cookie(STATE_COOKIE_NAME)
+	if (err != nil) || (subtle.ConstantTimeCompare([]byte(cookie.Value), []byte(state)) != 1) {
+		loginContext.RecordLoginFailure(false)
+		return nil, core.NewAuthError("-852", endpoint, "OIDC login state does not match the browser that started the login.")
+	}
+
+	identity, err := oidc.FinishLogin(state, query.Get("code"))
+	if err != nil {
+		loginContext.RecordLoginFailure(false)
+		return nil, core.NewAuthError("-820", endpoint, "Failed to finish OIDC login.").Err(err)
+	}
+
+	if identity.Email == model.RootUserEmail {
+		return nil, core.NewAuthError("-821", endpoint, "Root is not allowed to authenticate.")
+	}
+
+	loginContext.UserEmail = identity.Email
+
+	throttles, apiErr := loginContext.CheckLoginThrottles()
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	user, err := db.GetServerUser(identity.Email)
+	if err != nil {
+		return nil, core.NewInternalError("-822", endpoint, "Failed to get user.").Err(err).Add("email", identity.Email)
+	}
+
+	createdUser := false
+
+	if user == nil {
+		if !config.OIDC_CREATE_USERS.Get() {
+			loginContext.RecordLoginFailure(false)
+			return nil, core.NewAuthError("-823", endpoint, "Unknown User").Add("email", identity.Email)
+		}
+
+		user, err = createUser(identity)
+		if err != nil {
+			return nil, core.NewInternalError("-824", endpoint, "Failed to create user.").Err(err).Add("email", identity.Email)
+		}
+
+		createdUser = true
+	}
+
+	oldTokenIDs := getOIDCTokenIDs(user)
+
+	token, cleartext, err := user.CreateRandomToken(TOKEN_NAME, model.TokenSourceServer)
+	if err != nil {
+		return nil, core.NewInternalError("-825", endpoint, "Failed to create random user token.").Err(err).Add("email", user.Email)
+	}
+
+	ttlHours := config.OIDC_TOKEN_TTL_HOURS.Get()
+	if ttlHours > 0 {
+		expirationTime := token.CreationTime + timestamp.FromGoTimeDuration(time.Duration(ttlHours)*time.Hour)
+		token.ExpirationTime = &expirationTime
+	}
+
+	err = db.UpsertUser(user)
+	if err != nil {
+		return nil, core.NewInternalError("-826", endpoint, "Failed to save user.").Err(err).Add("email", user.Email)
+	}
+
+	for _, tokenID := range oldTokenIDs {
+		_, err = db.DeleteUserToken(user.Email, tokenID)
+		if err != nil {
+			return nil, core.NewInternalError("-851", endpoint, "Failed to remove old OIDC token.").Err(err).Add("email", user.Email).Add("token-id", tokenID)
+		}
+	}
+
+	loginContext.RecordLoginSuccess(throttles)
+
+	record := model.NewAuditRecord(model.AuditActionTokenCreate, user.Email)
+	record.After = map[string]string{
+		"token-id": token.ID,
+		"name":     token.Name,
+		"scopes":   strings.Join(token.Scopes, ","),
+	}
+	loginContext.Audit(record)
+
+	return &CallbackResponse{
+		Email:          user.Email,
+		CreatedUser:    createdUser,
+		TokenInfo:      &token.TokenInfo,
+		TokenCleartext: cleartext,
+	}, nil
+}
+
+// Get the IDs of the tokens created by previous OIDC logins.
+func getOIDCTokenIDs(user *model.ServerUser) []string {
+	ids := make([]string, 0)
+	for _, token := range user.Tokens {
+		if (token.Source == model.TokenSourceServer) && (token.Name == TOKEN_NAME) {
+			ids = append(ids, token.ID)
+		}
+	}
+
+	return ids
+}
+
+func createUser(identity *oidc.Identity) (*model.ServerUser, error) {
+	options := users.UpsertUsersOptions{
+		RawUsers: []*model.RawServerUserData{
+			&model.RawServerUserData{
+				Email: identity.Email,
+				Name:  identity.Name,
+				Role:  model.GetServerUserRoleString(model.ServerRoleUser),
+			},
+		},
+		SkipUpdates:       true,
+		ContextServerRole: model.ServerRoleRoot,
+	}
+
+	result := users.UpsertUser(options)
+	if result.HasErrors() {
+		return nil, fmt.Errorf("Failed to insert user: '%s'.", util.MustToJSON(result))
+	}
+
+	user, err := db.GetServerUser(identity.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	if user == nil {
+		return nil, fmt.Errorf("Could not find user after inserting.")
+	}
+
+	return user, nil
+}
@@ -0,0 +1,386 @@
+package oidc
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/config"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/oidc"
+	"github.com/edulinq/autograder/internal/timestamp"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestCallback(test *testing.T) {
+	db.ResetForTesting()
+	defer db.ResetForTesting()
+
+	issuer := oidc.NewTestIssuer()
+	defer issuer.Close()
+
+	testCases := []struct {
+		email       string
+		createUsers bool
+		created     bool
+		authError   bool
+	}{
+		{"course-student@test.edulinq.org", false, false, false},
+		{"server-admin@test.edulinq.org", false, false, false},
+		{"server-admin@test.edulinq.org", true, false, false},
+
+		// Unknown users.
+		{"zzz@test.edulinq.org", false, false, true},
+		{"zzz@test.edulinq.org", true, true, false},
+
+		// Root.
+		{model.RootUserEmail, true, false, true},
+	}
+
+	for i, testCase := range testCases {
+		config.OIDC_CREATE_USERS.Set(testCase.createUsers)
+
+		authURL, cookies := sendLoginRequest(test)
+
+		state, code, err := issuer.Login(authURL, testCase.email, "Some Name")
+		if err != nil {
+			test.Errorf("Case %d: Failed to log in with the issuer: '%v'.", i, err)
+			continue
+		}
+
+		response := sendCallbackRequest(test, url.Values{"state": []string{state}, "code": []string{code}}, cookies)
+		if !response.Success {
+			if !testCase.authError {
+				test.Errorf("Case %d: Response is not a success when it should be: '%v'.", i, response)
+			} else if response.HTTPStatus != core.HTTP_STATUS_AUTH_ERROR {
+				test.Errorf("Case %d: Unexpected HTTP status. Expected: %d, Actual: %d.", i, core.HTTP_STATUS_AUTH_ERROR, response.HTTPStatus)
+			}
+
+			continue
+		}
+
+		if testCase.authError {
+			test.Errorf("Case %d: Did not get an expected auth error.", i)
+			continue
+		}
+
+		var responseContent CallbackResponse
+		util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent)
+
+		if testCase.email != responseContent.Email {
+			test.Errorf("Case %d: Unexpected email. Expected: '%s', Actual: '%s'.", i, testCase.email, responseContent.Email)
+			continue
+		}
+
+		if testCase.created != responseContent.CreatedUser {
+			test.Errorf("Case %d: Unexpected created value. Expected: '%v', Actual: '%v'.", i, testCase.created, responseContent.CreatedUser)
+			continue
+		}
+
+		user := db.MustGetServerUser(testCase.email)
+		if user == nil {
+			test.Errorf("Case %d: Could not find user.", i)
+			continue
+		}
+
+		if testCase.created && (user.Role != model.ServerRoleUser) {
+			test.Errorf("Case %d: Unexpected role for created user. Expected: 'user', Actual: '%s'.", i, user.Role.String())
+			continue
+		}
+
+		auth, err := user.Auth(util.Sha256HexFromString(responseContent.TokenCleartext))
+		if err != nil {
+			test.Errorf("Case %d: Failed to auth with token: '%v'.", i, err)
+			continue
+		}
+
+		if !auth {
+			test.Errorf("Case %d: Returned token does not authenticate the user.", i)
+			continue
+		}
+
+		// Only the token from the latest login should remain.
+		tokenIDs := getOIDCTokenIDs(user)
+		if (len(tokenIDs) != 1) || (tokenIDs[0] != responseContent.TokenInfo.ID) {
+			test.Errorf("Case %d: Unexpected OIDC tokens. Expected: '%v', Actual: '%v'.", i, []string{responseContent.TokenInfo.ID}, tokenIDs)
+			continue
+		}
+
+		expectedExpiration := responseContent.TokenInfo.CreationTime + timestamp.FromGoTimeDuration(time.Duration(config.OIDC_TOKEN_TTL_HOURS.Get())*time.Hour)
+		if (responseContent.TokenInfo.ExpirationTime == nil) || (*responseContent.TokenInfo.ExpirationTime != expectedExpiration) {
+			test.Errorf("Case %d: Unexpected token expiration. Expected: '%s', Actual: '%s'.",
+				i, expectedExpiration.SafeString(), responseContent.TokenInfo.ExpirationTime.SafeString())
+			continue
+		}
+
+		records, err := db.GetAuditRecords(model.AuditQuery{Action: model.AuditActionTokenCreate, Target: testCase.email})
+		if err != nil {
+			test.Errorf("Case %d: Failed to get audit records: '%v'.", i, err)
+			continue
+		}
+
+		found := false
+		for _, record := range records {
+			if record.After["token-id"] == responseContent.TokenInfo.ID {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			test.Errorf("Case %d: Could not find an audit record for the new token '%s'.", i, responseContent.TokenInfo.ID)
+			continue
+		}
+	}
+}
+
+func TestCallbackNoTokenExpiration(test *testing.T) {
+	db.ResetForTesting()
+	defer db.ResetForTesting()
+	defer config.OIDC_TOKEN_TTL_HOURS.Set(config.OIDC_TOKEN_TTL_HOURS.Get())
+
+	issuer := oidc.NewTestIssuer()
+	defer issuer.Close()
+
+	config.OIDC_TOKEN_TTL_HOURS.Set(0)
+
+	authURL, cookies := sendLoginRequest(test)
+
+	state, code, err := issuer.Login(authURL, "course-student@test.edulinq.org", "Some Name")
+	if err != nil {
+		test.Fatalf("Failed to log in with the issuer: '%v'.", err)
+	}
+
+	response := sendCallbackRequest(test, url.Values{"state": []string{state}, "code": []string{code}}, cookies)
+	if !response.Success {
+		test.Fatalf("Response is not a success when it should be: '%v'.", response)
+	}
+
+	var responseContent CallbackResponse
+	util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent)
+
+	if responseContent.TokenInfo.ExpirationTime != nil {
+		test.Fatalf("Token has an expiration when it should not: '%s'.", responseContent.TokenInfo.ExpirationTime.SafeString())
+	}
+}
+
+func TestCallbackErrors(test *testing.T) {
+	issuer := oidc.NewTestIssuer()
+	defer issuer.Close()
+
+	authURL, cookies := sendLoginRequest(test)
+
+	state, code, err := issuer.Login(authURL, "course-student@test.edulinq.org", "")
+	if err != nil {
+		test.Fatalf("Failed to log in with the issuer: '%v'.", err)
+	}
+
+	otherCookies := []*http.Cookie{&http.Cookie{Name: STATE_COOKIE_NAME, Value: "zzz"}}
+
+	testCases := []struct {
+		query      url.Values
+		cookies    []*http.Cookie
+		locator    string
+		httpStatus int
+	}{
+		{url.Values{"error": []string{"access_denied"}}, cookies, "-819", core.HTTP_STATUS_BAD_REQUEST},
+		{url.Values{"state": []string{"zzz"}, "code": []string{code}}, cookies, "", core.HTTP_STATUS_AUTH_ERROR},
+		{url.Values{"state": []string{state}, "code": []string{"zzz"}}, cookies, "", core.HTTP_STATUS_AUTH_ERROR},
+
+		// The state is not bound to this browser.
+		{url.Values{"state": []string{state}, "code": []string{code}}, nil, "", core.HTTP_STATUS_AUTH_ERROR},
+		{url.Values{"state": []string{state}, "code": []string{code}}, otherCookies, "", core.HTTP_STATUS_AUTH_ERROR},
+	}
+
+	for i, testCase := range testCases {
+		response := sendCallbackRequest(test, testCase.query, testCase.cookies)
+		if response.Success {
+			test.Errorf("Case %d: Response is a success when it should not be: '%v'.", i, response)
+			continue
+		}
+
+		if testCase.locator != response.Locator {
+			test.Errorf("Case %d: Incorrect error returned. Expected '%s', found '%s'.", i, testCase.locator, response.Locator)
+			continue
+		}
+
+		if testCase.httpStatus != response.HTTPStatus {
+			test.Errorf("Case %d: Unexpected HTTP status. Expected: %d, Actual: %d.", i, testCase.httpStatus, response.HTTPStatus)
+			continue
+		}
+	}
+}
+
+func TestOIDCDisabled(test *testing.T) {
+	recorder := httptest.NewRecorder()
+	HandleLogin(recorder, httptest.NewRequest("GET", core.MakeFullAPIPath(`users/oidc/login`), nil))
+
+	response := parseResponse(test, recorder)
+	if response.Locator != "-816" {
+		test.Fatalf("Incorrect login error returned. Expected '-816', found '%s'.", response.Locator)
+	}
+
+	response = sendCallbackRequest(test, url.Values{}, nil)
+	if response.Locator != "-818" {
+		test.Fatalf("Incorrect callback error returned. Expected '-818', found '%s'.", response.Locator)
+	}
+}
+
+func TestCallbackStateCookie(test *testing.T) {
+	db.ResetForTesting()
+	defer db.ResetForTesting()
+
+	issuer := oidc.NewTestIssuer()
+	defer issuer.Close()
+
+	authURL, cookies := sendLoginRequest(test)
+	if (len(cookies) != 1) || (cookies[0].Name != STATE_COOKIE_NAME) || !cookies[0].HttpOnly {
+		test.Fatalf("Unexpected login cookies: '%s'.", util.MustToJSONIndent(cookies))
+	}
+
+	state, code, err := issuer.Login(authURL, "course-student@test.edulinq.org", "")
+	if err != nil {
+		test.Fatalf("Failed to log in with the issuer: '%v'.", err)
+	}
+
+	if cookies[0].Value != state {
+		test.Fatalf("Cookie does not hold the login state. Expected: '%s', Actual: '%s'.", state, cookies[0].Value)
+	}
+
+	// A cookie from another login is not accepted.
+	_, otherCookies := sendLoginRequest(test)
+
+	_, apiErr := handleCallback(newCallbackRequest(url.Values{"state": []string{state}, "code": []string{code}}, otherCookies))
+	if (apiErr == nil) || (apiErr.Locator != "-852") {
+		test.Fatalf("Did not get the expected state error. Expected: '-852', Actual: '%v'.", apiErr)
+	}
+
+	// The callback always clears the cookie.
+	recorder := httptest.NewRecorder()
+
+	err = HandleCallback(recorder, newCallbackRequest(url.Values{"state": []string{state}, "code": []string{code}}, cookies))
+	if err != nil {
+		test.Fatalf("Failed to handle callback: '%v'.", err)
+	}
+
+	response := parseResponse(test, recorder)
+	if !response.Success {
+		test.Fatalf("Response is not a success when it should be: '%v'.", response)
+	}
+
+	responseCookies := recorder.Result().Cookies()
+	if (len(responseCookies) != 1) || (responseCookies[0].Name != STATE_COOKIE_NAME) || (responseCookies[0].MaxAge >= 0) {
+		test.Fatalf("State cookie was not cleared: '%s'.", util.MustToJSONIndent(responseCookies))
+	}
+}
+
+func TestCallbackThrottle(test *testing.T) {
+	db.ResetForTesting()
+	defer db.ResetForTesting()
+	defer config.LOGIN_THROTTLE_EMAIL_FAILURES.Set(config.LOGIN_THROTTLE_EMAIL_FAILURES.Get())
+	defer config.LOGIN_THROTTLE_IP_FAILURES.Set(config.LOGIN_THROTTLE_IP_FAILURES.Get())
+
+	issuer := oidc.NewTestIssuer()
+	defer issuer.Close()
+
+	email := "course-student@test.edulinq.org"
+
+	config.LOGIN_THROTTLE_EMAIL_FAILURES.Set(1)
+	config.LOGIN_THROTTLE_IP_FAILURES.Set(2)
+
+	// A locked out user cannot log in.
+	throttle := &model.LoginThrottle{Key: model.LoginThrottleKeyForEmail(email)}
+	throttle.RecordFailure(timestamp.Now(), 1, 60, 60)
+
+	err := db.UpsertLoginThrottles(map[string]*model.LoginThrottle{throttle.Key: throttle})
+	if err != nil {
+		test.Fatalf("Failed to save throttle: '%v'.", err)
+	}
+
+	apiErr := sendThrottledLogin(test, issuer, email)
+	if (apiErr == nil) || (apiErr.Locator != "-059") {
+		test.Fatalf("Locked out user did not get a throttle error: '%v'.", apiErr)
+	}
+
+	err = db.RemoveLoginThrottles([]string{throttle.Key})
+	if err != nil {
+		test.Fatalf("Failed to remove throttle: '%v'.", err)
+	}
+
+	// Failed callbacks count against the sender.
+	for i := 0; i < 2; i++ {
+		_, cookies := sendLoginRequest(test)
+
+		_, apiErr = handleCallback(newCallbackRequest(url.Values{"state": []string{"zzz"}, "code": []string{"zzz"}}, cookies))
+		if (apiErr == nil) || (apiErr.Locator != "-852") {
+			test.Fatalf("Case %d: Did not get the expected state error. Expected: '-852', Actual: '%v'.", i, apiErr)
+		}
+	}
+
+	apiErr = sendThrottledLogin(test, issuer, email)
+	if (apiErr == nil) || (apiErr.Locator != "-059") {
+		test.Fatalf("Locked out sender did not get a throttle error: '%v'.", apiErr)
+	}
+}
+
+func sendThrottledLogin(test *testing.T, issuer *oidc.TestIssuer, email string) *core.APIError {
+	authURL, cookies := sendLoginRequest(test)
+
+	state, code, err := issuer.Login(authURL, email, "")
+	if err != nil {
+		test.Fatalf("Failed to log in with the issuer: '%v'.", err)
+	}
+
+	_, apiErr := handleCallback(newCallbackRequest(url.Values{"state": []string{state}, "code": []string{code}}, cookies))
+	return apiErr
+}
+
+// Returns the provider's URL that the user was redirected to and the cookies that were set.
+func sendLoginRequest(test *testing.T) (string, []*http.Cookie) {
+	recorder := httptest.NewRecorder()
+
+	err := HandleLogin(recorder, httptest.NewRequest("GET", core.MakeFullAPIPath(`users/oidc/login`), nil))
+	if err != nil {
+		test.Fatalf("Failed to handle login: '%v'.", err)
+	}
+
+	if recorder.Code != http.StatusFound {
+		test.Fatalf("Unexpected login status. Expected: %d, Actual: %d. Body: '%s'.", http.StatusFound, recorder.Code, recorder.Body.String())
+	}
+
+	return recorder.Header().Get("Location"), recorder.Result().Cookies()
+}
+
+func newCallbackRequest(query url.Values, cookies []*http.Cookie) *http.Request {
+	request := httptest.NewRequest("GET", core.MakeFullAPIPath(`users/oidc/callback`)+"?"+query.Encode(), nil)
+	for _, cookie := range cookies {
+		request.AddCookie(cookie)
+	}
+
+	return request
+}
+
+func sendCallbackRequest(test *testing.T, query url.Values, cookies []*http.Cookie) *core.APIResponse {
+	recorder := httptest.NewRecorder()
+
+	err := HandleCallback(recorder, newCallbackRequest(query, cookies))
+	if err != nil {
+		test.Fatalf("Failed to handle callback: '%v'.", err)
+	}
+
+	return parseResponse(test, recorder)
+}
+
+func parseResponse(test *testing.T, recorder *httptest.ResponseRecorder) *core.APIResponse {
+	var response core.APIResponse
+	err := util.JSONFromString(recorder.Body.String(), &response)
+	if err != nil {
+		test.Fatalf("Could not unmarshal JSON response '%s': '%v'.", recorder.Body.String(), err)
+	}
+
+	return &response
+}
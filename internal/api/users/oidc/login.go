@@ -0,0 +1,46 @@
+package oidc
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/config"
+	"github.com/edulinq/autograder/internal/oidc"
+)
+
+// The cookie that binds a login's state to the browser that started it.
+const STATE_COOKIE_NAME = "autograder-oidc-state"
+
+// Start logging in through the configured OIDC provider.
+// The user will be redirected to the provider, which will then send them to the callback.
+// The login's state is also stored in a cookie, so the login can only be finished in the same browser.
+func HandleLogin(response http.ResponseWriter, request *http.Request) error {
+	if !oidc.IsEnabled() {
+		return core.SendAPIResponse(response, nil, core.NewBadRequestError("-816", request.URL.Path, "OIDC login is not enabled."))
+	}
+
+	authURL, state, err := oidc.StartLogin()
+	if err != nil {
+		return core.SendAPIResponse(response, nil, core.NewInternalError("-817", request.URL.Path, "Failed to start OIDC login.").Err(err))
+	}
+
+	http.SetCookie(response, newStateCookie(request, state, (oidc.LOGIN_TIMEOUT_MS/1000)))
+
+	http.Redirect(response, request, authURL, http.StatusFound)
+	return nil
+}
+
+// A negative max age will remove the cookie.
+func newStateCookie(request *http.Request, state string, maxAgeSecs int) *http.Cookie {
+	// Lax (instead of strict) is required, since the provider sends the user to the callback from another site.
+	return &http.Cookie{
+		Name:     STATE_COOKIE_NAME,
+		Value:    state,
+		Path:     core.MakeFullAPIPath(`users/oidc/callback`),
+		MaxAge:   maxAgeSecs,
+		HttpOnly: true,
+		Secure:   (request.TLS != nil) || strings.HasPrefix(config.OIDC_REDIRECT_URI.Get(), "https://"),
+		SameSite: http.SameSiteLaxMode,
+	}
+}
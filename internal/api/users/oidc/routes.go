@@ -0,0 +1,18 @@
+package oidc
+
+// All the routes handled by this package.
+// These are not standard API endpoints,
+// since users are sent to them by their browser (with GET requests).
+
+import (
+	"github.com/edulinq/autograder/internal/api/core"
+)
+
+var routes []core.Route = []core.Route{
+	core.NewBaseRoute("GET", core.MakeFullAPIPath(`users/oidc/login`), HandleLogin),
+	core.NewBaseRoute("GET", core.MakeFullAPIPath(`users/oidc/callback`), HandleCallback),
+}
+
+func GetRoutes() *[]core.Route {
+	return &routes
+}
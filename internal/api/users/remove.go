@@ -3,6 +3,7 @@ package users
 import (
 	"github.com/edulinq/autograder/internal/api/core"
 	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
 )
 
 type RemoveRequest struct {
@@ -37,5 +38,9 @@ func HandleRemove(request *RemoveRequest) (*RemoveResponse, *core.APIError) {
 			"Failed to remove user.").Err(err).Add("target-user", request.TargetUser.User.Email)
 	}
 
+	record := model.NewAuditRecord(model.AuditActionUserRemove, request.TargetUser.User.Email)
+	record.Before = request.TargetUser.User.GetAuditSummary("")
+	request.Audit(record)
+
 	return &response, nil
 }
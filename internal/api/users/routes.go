@@ -4,13 +4,19 @@ package users
 
 import (
 	"github.com/edulinq/autograder/internal/api/core"
+	twofactor "github.com/edulinq/autograder/internal/api/users/2fa"
+	"github.com/edulinq/autograder/internal/api/users/data"
+	"github.com/edulinq/autograder/internal/api/users/lockout"
+	"github.com/edulinq/autograder/internal/api/users/oidc"
 	"github.com/edulinq/autograder/internal/api/users/password"
 	"github.com/edulinq/autograder/internal/api/users/tokens"
 )
 
 var baseRoutes []core.Route = []core.Route{
 	core.MustNewAPIRoute(`users/auth`, HandleAuth),
+	core.MustNewAPIRoute(`users/export`, HandleExport),
 	core.MustNewAPIRoute(`users/get`, HandleGet),
+	core.MustNewAPIRoute(`users/import`, HandleImport),
 	core.MustNewAPIRoute(`users/list`, HandleList),
 	core.MustNewAPIRoute(`users/remove`, HandleRemove),
 	core.MustNewAPIRoute(`users/upsert`, HandleUpsert),
@@ -20,8 +26,12 @@ func GetRoutes() *[]core.Route {
 	routes := make([]core.Route, 0)
 
 	routes = append(routes, baseRoutes...)
+	routes = append(routes, *(data.GetRoutes())...)
+	routes = append(routes, *(lockout.GetRoutes())...)
+	routes = append(routes, *(oidc.GetRoutes())...)
 	routes = append(routes, *(password.GetRoutes())...)
 	routes = append(routes, *(tokens.GetRoutes())...)
+	routes = append(routes, *(twofactor.GetRoutes())...)
 
 	return &routes
 }
@@ -1,9 +1,12 @@
 package tokens
 
 import (
+	"strings"
+
 	"github.com/edulinq/autograder/internal/api/core"
 	"github.com/edulinq/autograder/internal/db"
 	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/timestamp"
 )
 
 type CreateRequest struct {
@@ -13,6 +16,14 @@ type CreateRequest struct {
 	TargetUser core.TargetServerUserSelfOrAdmin `json:"target-user"`
 
 	Name string `json:"name"`
+
+	// When this token should expire.
+	// If not set, the token will not expire.
+	ExpirationTime *timestamp.Timestamp `json:"expiration-time"`
+
+	// The endpoints this token may be used for, e.g., "courses/assignments/submissions/*" or "read-only".
+	// If not set, the token may be used for all endpoints.
+	Scopes []string `json:"scopes"`
 }
 
 type CreateResponse struct {
@@ -31,18 +42,45 @@ func HandleCreate(request *CreateRequest) (*CreateResponse, *core.APIError) {
 
 	response.FoundUser = true
 
+	if (request.ExpirationTime != nil) && (*request.ExpirationTime <= request.Timestamp) {
+		return nil, core.NewBadRequestError("-827", request,
+			"Token expiration time must be in the future.").
+			Add("expiration-time", *request.ExpirationTime)
+	}
+
+	scopes, err := model.ValidateTokenScopes(request.Scopes)
+	if err != nil {
+		return nil, core.NewBadRequestError("-828", request,
+			"Invalid token scopes.").Err(err)
+	}
+
 	token, cleartext, err := request.TargetUser.User.CreateRandomToken(request.Name, model.TokenSourceUser)
 	if err != nil {
 		return nil, core.NewInternalError("-801", request,
 			"Failed to create random user token.").Err(err)
 	}
 
+	token.ExpirationTime = request.ExpirationTime
+	token.Scopes = scopes
+
+	// Tokens owned by users with two-factor auth can be used without a code.
+	// The owner's (not the requester's) status is used, since the token authenticates as the owner.
+	token.TwoFactor = request.TargetUser.User.HasTwoFactor()
+
 	err = db.UpsertUser(request.TargetUser.User)
 	if err != nil {
 		return nil, core.NewInternalError("-802", request,
 			"Failed to save user.").Err(err)
 	}
 
+	record := model.NewAuditRecord(model.AuditActionTokenCreate, request.TargetUser.User.Email)
+	record.After = map[string]string{
+		"token-id": token.ID,
+		"name":     token.Name,
+		"scopes":   strings.Join(token.Scopes, ","),
+	}
+	request.Audit(record)
+
 	response.TokenInfo = &token.TokenInfo
 	response.TokenCleartext = cleartext
 
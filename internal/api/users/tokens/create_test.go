@@ -7,18 +7,26 @@ import (
 	"github.com/edulinq/autograder/internal/api/core"
 	"github.com/edulinq/autograder/internal/db"
 	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/timestamp"
+	"github.com/edulinq/autograder/internal/totp"
 	"github.com/edulinq/autograder/internal/util"
 )
 
 func TestCreateBase(test *testing.T) {
 	defer db.ResetForTesting()
 
+	future := timestamp.Now() + timestamp.FromMSecs(60*60*1000)
+	past := timestamp.Now() - 1
+
 	testCases := []struct {
-		email       string
-		targetUser  string
-		name        string
-		missingUser bool
-		locator     string
+		email          string
+		targetUser     string
+		name           string
+		expirationTime *timestamp.Timestamp
+		scopes         []string
+		expectedScopes []string
+		missingUser    bool
+		locator        string
 	}{
 		// Self
 		{
@@ -31,6 +39,40 @@ func TestCreateBase(test *testing.T) {
 			name:  "test",
 		},
 
+		// Expiration
+		{
+			email:          "course-student@test.edulinq.org",
+			expirationTime: &future,
+		},
+
+		// Expiration - Past
+		{
+			email:          "course-student@test.edulinq.org",
+			expirationTime: &past,
+			locator:        "-827",
+		},
+
+		// Scopes
+		{
+			email:          "course-student@test.edulinq.org",
+			scopes:         []string{"read-only", "/courses/assignments/submissions/*"},
+			expectedScopes: []string{"courses/assignments/submissions/*", "read-only"},
+		},
+
+		// Scopes - Invalid
+		{
+			email:   "course-student@test.edulinq.org",
+			scopes:  []string{"courses/*/submit"},
+			locator: "-828",
+		},
+
+		// Scopes - Empty
+		{
+			email:   "course-student@test.edulinq.org",
+			scopes:  []string{""},
+			locator: "-828",
+		},
+
 		// Other
 		{
 			email:      "server-admin@test.edulinq.org",
@@ -72,8 +114,10 @@ func TestCreateBase(test *testing.T) {
 		}
 
 		fields := map[string]any{
-			"target-user": testCase.targetUser,
-			"name":        testCase.name,
+			"target-user":     testCase.targetUser,
+			"name":            testCase.name,
+			"expiration-time": testCase.expirationTime,
+			"scopes":          testCase.scopes,
 		}
 
 		response := core.SendTestAPIRequestFull(test, "users/tokens/create", fields, nil, testCase.email)
@@ -150,5 +194,147 @@ func TestCreateBase(test *testing.T) {
 				i, testCase.name, newToken.Name)
 			continue
 		}
+
+		if !reflect.DeepEqual(testCase.expirationTime, newToken.ExpirationTime) {
+			test.Errorf("Case %d: New token expiration time not as expected. Expected: '%v', Actual: '%v'.",
+				i, testCase.expirationTime, newToken.ExpirationTime)
+			continue
+		}
+
+		if !reflect.DeepEqual(testCase.expectedScopes, newToken.Scopes) {
+			test.Errorf("Case %d: New token scopes not as expected. Expected: '%v', Actual: '%v'.",
+				i, testCase.expectedScopes, newToken.Scopes)
+			continue
+		}
+	}
+}
+
+func TestCreateTwoFactor(test *testing.T) {
+	defer db.ResetForTesting()
+
+	email := "course-admin@test.edulinq.org"
+
+	secret := mustEnableTwoFactor(test, email)
+
+	code, err := totp.GenerateCode(secret, timestamp.Now())
+	if err != nil {
+		test.Fatalf("Failed to generate code: '%v'.", err)
+	}
+
+	fields := map[string]any{
+		"name":            "script",
+		"two-factor-code": code,
+	}
+
+	response := core.SendTestAPIRequestFull(test, "users/tokens/create", fields, nil, email)
+	if !response.Success {
+		test.Fatalf("Response is not a success when it should be: '%v'.", response)
+	}
+
+	var responseContent CreateResponse
+	util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent)
+
+	if !responseContent.TokenInfo.TwoFactor {
+		test.Fatalf("Token created by a two-factor user was not marked.")
+	}
+
+	// The new token can be used without a code.
+	fields = map[string]any{
+		"user-pass": util.Sha256HexFromString(responseContent.TokenCleartext),
+	}
+
+	response = core.SendTestAPIRequestFull(test, "users/tokens/list", fields, nil, email)
+	if !response.Success {
+		test.Fatalf("Request with two-factor token is not a success when it should be: '%v'.", response)
+	}
+
+	// But it cannot create more tokens without a code.
+	fields["name"] = "another-script"
+
+	response = core.SendTestAPIRequestFull(test, "users/tokens/create", fields, nil, email)
+	if response.Success {
+		test.Fatalf("Token creation with a two-factor token and no code is a success when it should not be.")
 	}
+
+	if response.Locator != "-061" {
+		test.Fatalf("Incorrect error returned. Expected: '-061', Actual: '%s'.", response.Locator)
+	}
+}
+
+// The two-factor mark comes from the user that owns the token, not the user that requested it.
+func TestCreateTwoFactorOtherUser(test *testing.T) {
+	defer db.ResetForTesting()
+
+	testCases := []struct {
+		requesterTwoFactor bool
+		targetTwoFactor    bool
+	}{
+		{false, false},
+		{true, false},
+		{false, true},
+		{true, true},
+	}
+
+	requesterEmail := "server-admin@test.edulinq.org"
+	targetEmail := "course-student@test.edulinq.org"
+
+	for i, testCase := range testCases {
+		db.ResetForTesting()
+
+		fields := map[string]any{
+			"target-user": targetEmail,
+			"name":        "other",
+		}
+
+		if testCase.requesterTwoFactor {
+			secret := mustEnableTwoFactor(test, requesterEmail)
+
+			code, err := totp.GenerateCode(secret, timestamp.Now())
+			if err != nil {
+				test.Fatalf("Case %d: Failed to generate code: '%v'.", i, err)
+			}
+
+			fields["two-factor-code"] = code
+		}
+
+		if testCase.targetTwoFactor {
+			mustEnableTwoFactor(test, targetEmail)
+		}
+
+		response := core.SendTestAPIRequestFull(test, "users/tokens/create", fields, nil, requesterEmail)
+		if !response.Success {
+			test.Errorf("Case %d: Response is not a success when it should be: '%v'.", i, response)
+			continue
+		}
+
+		var responseContent CreateResponse
+		util.MustJSONFromString(util.MustToJSON(response.Content), &responseContent)
+
+		if testCase.targetTwoFactor != responseContent.TokenInfo.TwoFactor {
+			test.Errorf("Case %d: Unexpected two-factor mark. Expected: %v, Actual: %v.",
+				i, testCase.targetTwoFactor, responseContent.TokenInfo.TwoFactor)
+			continue
+		}
+	}
+}
+
+// Returns the user's new secret.
+func mustEnableTwoFactor(test *testing.T, email string) string {
+	secret, err := totp.NewSecret()
+	if err != nil {
+		test.Fatalf("Failed to create secret: '%v'.", err)
+	}
+
+	user := db.MustGetServerUser(email)
+	user.TwoFactor = &model.TwoFactorInfo{
+		Secret:  secret,
+		Enabled: true,
+	}
+
+	err = db.UpsertUser(user)
+	if err != nil {
+		test.Fatalf("Failed to save user: '%v'.", err)
+	}
+
+	return secret
 }
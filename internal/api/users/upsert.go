@@ -4,6 +4,7 @@ import (
 	"slices"
 
 	"github.com/edulinq/autograder/internal/api/core"
+	"github.com/edulinq/autograder/internal/db"
 	"github.com/edulinq/autograder/internal/model"
 	"github.com/edulinq/autograder/internal/procedures/users"
 )
@@ -24,8 +25,19 @@ func HandleUpsert(request *UpsertRequest) (*UpsertResponse, *core.APIError) {
 	request.ContextEmail = request.ServerUser.Email
 	request.ContextServerRole = request.ServerUser.Role
 
+	before, err := db.GetServerUsers()
+	if err != nil {
+		return nil, core.NewInternalError("-841", request, "Failed to get server users.").Err(err)
+	}
+
 	results := users.UpsertUsers(request.UpsertUsersOptions)
 
+	if !request.DryRun {
+		for _, record := range core.NewUserOpAuditRecords(model.AuditActionUserUpsert, "", before, results) {
+			request.Audit(record)
+		}
+	}
+
 	var response UpsertResponse
 	// Convert UserOpResults to user friendly ExternalUserOpResults.
 	for _, result := range results {
@@ -55,6 +55,10 @@ func EnableUnitTestingModeFull(loadEnv bool) error {
 	NO_TASKS.Set(true)
 	LOAD_TEST_DATA.Set(true)
 
+	// Many tests intentionally fail to log in, tests for throttling will enable it.
+	LOGIN_THROTTLE_EMAIL_FAILURES.Set(0)
+	LOGIN_THROTTLE_IP_FAILURES.Set(0)
+
 	tempWorkDir, err := util.MkDirTemp("autograder-unit-testing-")
 	if err != nil {
 		return fmt.Errorf("Failed to make temp unit testing work dir: '%w'.", err)
@@ -36,16 +36,31 @@ var (
 	EMAIL_MIN_PERIOD           = MustNewIntOption("email.smtp.minperiod", 250, "The minimum time (in MS) between sending emails.")
 
 	// Docker
-	DOCKER_DISABLE            = MustNewBoolOption("docker.disable", false, "Disable the use of docker (usually for testing).")
-	DOCKER_MAX_OUTPUT_SIZE_KB = MustNewIntOption("docker.output.maxsize", 4*1024, "The maximum allowed size (in KB) for stdout and stderr combined. The default is 4096 KB (4 MB).")
+	DOCKER_DISABLE                = MustNewBoolOption("docker.disable", false, "Disable the use of docker (usually for testing).")
+	DOCKER_NODOCKER_SANDBOX       = MustNewBoolOption("docker.nodocker.sandbox", false, "When docker is disabled, run graders inside a lightweight (Linux namespace) sandbox that only exposes the grading directories.")
+	DOCKER_NODOCKER_SANDBOX_PATHS = MustNewStringOption("docker.nodocker.sandbox.paths", "", "A comma-separated list of additional host paths (e.g., a Python virtual environment) to expose (read-only) inside the no-docker sandbox.")
+	DOCKER_RUNTIME                = MustNewStringOption("docker.runtime", "docker", "The container runtime used to build images and run graders: 'docker' (the default) or 'podman' (which can run rootless).")
+	DOCKER_PODMAN_PATH            = MustNewStringOption("docker.podman.path", "podman", "The podman executable to use when the container runtime is 'podman'.")
+	DOCKER_MAX_OUTPUT_SIZE_KB     = MustNewIntOption("docker.output.maxsize", 4*1024, "The maximum allowed size (in KB) for stdout and stderr combined. The default is 4096 KB (4 MB).")
+	DOCKER_MAX_MEMORY_MB          = MustNewIntOption("docker.limits.memory", 2*1024, "The maximum memory (in MB) a container can use. Assignments may ask for less. Zero means no limit.")
+	DOCKER_MAX_CPUS               = MustNewFloatOption("docker.limits.cpus", 2.0, "The maximum number of CPUs a container can use. Assignments may ask for less. Zero means no limit.")
+	DOCKER_MAX_PIDS               = MustNewIntOption("docker.limits.pids", 512, "The maximum number of processes/threads a container can have running at once. Assignments may ask for less. Zero means no limit.")
+	DOCKER_MAX_TMPFS_MB           = MustNewIntOption("docker.limits.tmpfs", 256, "The maximum size (in MB) of the tmpfs mounted at /tmp inside a container. Zero means no limit.")
+	DOCKER_ALLOW_NETWORK          = MustNewBoolOption("docker.network.allow", false, "Allow assignments to enable networking inside their containers. Without this, containers never have network access.")
+	DOCKER_POOL_MAX_CONTAINERS    = MustNewIntOption("docker.pool.max", 8, "The maximum number of warm (pre-created and paused) grading containers kept across all assignments. Zero disables warm container pools.")
+	DOCKER_POOL_MAX_IDLE_SECS     = MustNewIntOption("docker.pool.idle.max", 10*60, "The maximum number of seconds a warm grading container can sit unused before it is removed. Assignments may ask for less. Zero means no maximum.")
 
 	// Grading
-	GRADING_RUNTIME_MAX_SECS = MustNewIntOption("grading.runtime.max", 60*5, "The maximum number of seconds a Docker container can be running for.")
+	GRADING_RUNTIME_MAX_SECS      = MustNewIntOption("grading.runtime.max", 60*5, "The maximum number of seconds a Docker container can be running for.")
+	GRADING_QUEUE_WORKERS         = MustNewIntOption("grading.queue.workers", 2, "The number of queued submissions that can be graded at the same time.")
+	GRADING_QUEUE_RETENTION_HOURS = MustNewIntOption("grading.queue.retention", 7*24, "The number of hours that finished grading tickets (and their results) are kept before being removed. Set to zero to keep finished tickets forever.")
 
 	// Tasks
-	NO_TASKS             = MustNewBoolOption("tasks.disable", false, "Disable all scheduled tasks.")
-	TASK_MAX_WAIT_SECS   = MustNewIntOption("tasks.maxwait", 2*60, "The maximum wait between checking for the next task to run.")
-	TASK_MIN_PERIOD_SECS = MustNewIntOption("tasks.minperiod", 10*60, "The minimum period between the runs of the same task.")
+	NO_TASKS                         = MustNewBoolOption("tasks.disable", false, "Disable all scheduled tasks.")
+	TASK_MAX_WAIT_SECS               = MustNewIntOption("tasks.maxwait", 2*60, "The maximum wait between checking for the next task to run.")
+	TASK_MIN_PERIOD_SECS             = MustNewIntOption("tasks.minperiod", 10*60, "The minimum period between the runs of the same task.")
+	TASK_TOKEN_CLEANUP_MINS          = MustNewIntOption("tasks.tokens.cleanup", 60, "The period (in minutes) between removing expired user tokens. Set to zero to never remove expired tokens.")
+	TASK_GRADING_TICKET_CLEANUP_MINS = MustNewIntOption("tasks.gradingtickets.cleanup", 60, "The period (in minutes) between removing finished grading tickets that are past their retention (see grading.queue.retention). Set to zero to never remove finished grading tickets.")
 
 	// Server
 	WEB_HTTP_PORT        = MustNewIntOption("web.http.port", 8080, "The port to serve HTTP traffic on. Standard is 80 (but requires root to use).")
@@ -58,8 +73,27 @@ var (
 	WEB_STATIC_ROOT      = MustNewStringOption("web.static.root", "", "The root directory to serve as part of the static portion of the API. Defaults to empty string, which indicates the embedded static directory.")
 	WEB_STATIC_FALLBACK  = MustNewBoolOption("web.static.fallback", false, "For any unmatched route (potential 404) that does not have an API prefix, try to match it in the static root before giving the final 404.")
 
+	// Login Throttling
+	LOGIN_THROTTLE_EMAIL_FAILURES = MustNewIntOption("login.throttle.email.failures", 5, "The number of failed logins for a user before that user is temporarily locked out. Zero disables throttling by user.")
+	LOGIN_THROTTLE_IP_FAILURES    = MustNewIntOption("login.throttle.ip.failures", 50, "The number of failed logins from an IP address before that address is temporarily locked out. Zero disables throttling by IP address.")
+	LOGIN_THROTTLE_LOCKOUT_SECS   = MustNewIntOption("login.throttle.lockout.base", 30, "The number of seconds of the first lockout. Each additional failed login doubles the lockout.")
+	LOGIN_THROTTLE_MAX_SECS       = MustNewIntOption("login.throttle.lockout.max", 60*60, "The maximum number of seconds of a lockout.")
+	LOGIN_THROTTLE_WINDOW_SECS    = MustNewIntOption("login.throttle.window", 60*60, "Failed logins are forgotten after this many seconds without another failure (and no active lockout).")
+
+	// Two-Factor Authentication
+	TWO_FACTOR_ISSUER             = MustNewStringOption("login.2fa.issuer", "autograder", "The name of this server shown in users' authenticator apps.")
+	TWO_FACTOR_REQUIRE_PRIVILEGED = MustNewBoolOption("login.2fa.require-privileged", false, "Require two-factor authentication for server admins (and above) and course admins (and above).")
+
+	// OpenID Connect
+	OIDC_ISSUER          = MustNewStringOption("oidc.issuer", "", "The issuer URL of an OpenID Connect provider that users can log in through. Empty disables OIDC login.")
+	OIDC_CLIENT_ID       = MustNewStringOption("oidc.client.id", "", "The client ID for this server registered with the OIDC provider.")
+	OIDC_CLIENT_SECRET   = MustNewStringOption("oidc.client.secret", "", "The client secret for this server registered with the OIDC provider.")
+	OIDC_REDIRECT_URI    = MustNewStringOption("oidc.redirect", "", "The full URI of this server's OIDC callback route (e.g., 'https://autograder.example.com/api/v03/users/oidc/callback'). Must be registered with the OIDC provider.")
+	OIDC_CREATE_USERS    = MustNewBoolOption("oidc.users.create", false, "Create a new server user (with the 'user' role) when an unknown email logs in through OIDC.")
+	OIDC_TOKEN_TTL_HOURS = MustNewIntOption("oidc.token.ttl", 24, "The number of hours that a token created by an OIDC login is valid for. Set to zero to never expire these tokens.")
+
 	// Database
-	DB_TYPE   = MustNewStringOption("db.type", "disk", "The type of database to use.")
+	DB_TYPE   = MustNewStringOption("db.type", "disk", "The type of database to use (\"disk\", \"sqlite\", or \"postgres\").")
 	DB_PG_URI = MustNewStringOption("db.pg.uri", "", "Connection string to connect to a Postgres Database. Empty if not using Postgres.")
 
 	// Job Management
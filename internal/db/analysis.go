@@ -53,3 +53,19 @@ func StorePairwiseAnalysis(records []*model.PairwiseAnalysis) error {
 
 	return backend.StorePairwiseAnalysis(records)
 }
+
+func GetCourseIndividualAnalysis(courseID string) ([]*model.IndividualAnalysis, error) {
+	if backend == nil {
+		return nil, fmt.Errorf("Database has not been opened.")
+	}
+
+	return backend.GetCourseIndividualAnalysis(courseID)
+}
+
+func GetCoursePairwiseAnalysis(courseID string) ([]*model.PairwiseAnalysis, error) {
+	if backend == nil {
+		return nil, fmt.Errorf("Database has not been opened.")
+	}
+
+	return backend.GetCoursePairwiseAnalysis(courseID)
+}
@@ -379,6 +379,91 @@ func (this *DBTests) DBTestRemovePairwiseAnalysisBase(test *testing.T) {
 	}
 }
 
+func (this *DBTests) DBTestGetCourseIndividualAnalysisBase(test *testing.T) {
+	ResetForTesting()
+	defer ResetForTesting()
+
+	// Store records out of order and with an override, only the last record for an ID should be kept.
+	override := *testIndividualRecords[0]
+	override.SubmissionStartTime = timestamp.FromMSecs(100)
+
+	records := []*model.IndividualAnalysis{
+		testIndividualRecords[2],
+		testIndividualRecords[0],
+		testIndividualRecords[1],
+	}
+
+	err := StoreIndividualAnalysis(records)
+	if err != nil {
+		test.Fatalf("Failed to store initial records: '%v'.", err)
+	}
+
+	err = StoreIndividualAnalysis([]*model.IndividualAnalysis{&override})
+	if err != nil {
+		test.Fatalf("Failed to store override record: '%v'.", err)
+	}
+
+	results, err := GetCourseIndividualAnalysis("course101")
+	if err != nil {
+		test.Fatalf("Failed to get records: '%v'.", err)
+	}
+
+	expected := []*model.IndividualAnalysis{
+		&override,
+		testIndividualRecords[1],
+		testIndividualRecords[2],
+	}
+
+	if !reflect.DeepEqual(expected, results) {
+		test.Fatalf("Results not as expected. Expected: '%s', Actual: '%s'.",
+			util.MustToJSONIndent(expected), util.MustToJSONIndent(results))
+	}
+
+	results, err = GetCourseIndividualAnalysis("course-languages")
+	if err != nil {
+		test.Fatalf("Failed to get empty records: '%v'.", err)
+	}
+
+	if len(results) != 0 {
+		test.Fatalf("Unexpected empty results. Actual: '%s'.", util.MustToJSONIndent(results))
+	}
+}
+
+func (this *DBTests) DBTestGetCoursePairwiseAnalysisBase(test *testing.T) {
+	ResetForTesting()
+	defer ResetForTesting()
+
+	records := []*model.PairwiseAnalysis{
+		testPairwiseRecords[2],
+		testPairwiseRecords[0],
+		testPairwiseRecords[1],
+	}
+
+	err := StorePairwiseAnalysis(records)
+	if err != nil {
+		test.Fatalf("Failed to store initial records: '%v'.", err)
+	}
+
+	results, err := GetCoursePairwiseAnalysis("course101")
+	if err != nil {
+		test.Fatalf("Failed to get records: '%v'.", err)
+	}
+
+	if !reflect.DeepEqual(testPairwiseRecords, results) {
+		test.Fatalf("Results not as expected. Expected: '%s', Actual: '%s'.",
+			util.MustToJSONIndent(testPairwiseRecords), util.MustToJSONIndent(results))
+	}
+
+	results, err = GetCoursePairwiseAnalysis("course-languages")
+	if err != nil {
+		test.Fatalf("Failed to get empty records: '%v'.", err)
+	}
+
+	if len(results) != 0 {
+		test.Fatalf("Unexpected empty results. Actual: '%s'.", util.MustToJSONIndent(results))
+	}
+}
+
 var testIndividualIDs []string = []string{
 	"course101::hw0::course-student@test.edulinq.org::1697406256",
 	"course101::hw0::course-student@test.edulinq.org::1697406265",
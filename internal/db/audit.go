@@ -0,0 +1,48 @@
+package db
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/edulinq/autograder/internal/model"
+)
+
+// Serialize appends so that each record is chained to the one before it.
+var auditLock sync.Mutex
+
+// Add a record to the end of the audit trail.
+// The record's index and hashes will be set here.
+func AppendAuditRecord(record *model.AuditRecord) error {
+	if backend == nil {
+		return fmt.Errorf("Database has not been opened.")
+	}
+
+	err := record.Validate()
+	if err != nil {
+		return fmt.Errorf("Failed to validate audit record: '%w'.", err)
+	}
+
+	auditLock.Lock()
+	defer auditLock.Unlock()
+
+	previous, err := backend.GetLastAuditRecord()
+	if err != nil {
+		return fmt.Errorf("Failed to get the last audit record: '%w'.", err)
+	}
+
+	err = record.Chain(previous)
+	if err != nil {
+		return fmt.Errorf("Failed to chain audit record: '%w'.", err)
+	}
+
+	return backend.AppendAuditRecord(record)
+}
+
+// Get all the audit records that match the query, sorted by index.
+func GetAuditRecords(query model.AuditQuery) ([]*model.AuditRecord, error) {
+	if backend == nil {
+		return nil, fmt.Errorf("Database has not been opened.")
+	}
+
+	return backend.GetAuditRecords(query)
+}
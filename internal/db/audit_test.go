@@ -0,0 +1,119 @@
+package db
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/timestamp"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func (this *DBTests) DBTestAuditRecordsBase(test *testing.T) {
+	ResetForTesting()
+	defer ResetForTesting()
+
+	records, err := GetAuditRecords(model.AuditQuery{})
+	if err != nil {
+		test.Fatalf("Failed to fetch empty audit records: '%v'.", err)
+	}
+
+	if len(records) != 0 {
+		test.Fatalf("Initial audit records are not empty, found %d records.", len(records))
+	}
+
+	input := []*model.AuditRecord{
+		&model.AuditRecord{
+			Timestamp: timestamp.FromMSecs(100),
+			RequestID: "request-1",
+			Action:    model.AuditActionUserUpsert,
+			Actor:     "server-admin@test.edulinq.org",
+			Target:    "course-student@test.edulinq.org",
+			After:     map[string]string{"server-role": "user"},
+		},
+		&model.AuditRecord{
+			Timestamp:    timestamp.FromMSecs(200),
+			RequestID:    "request-2",
+			Action:       model.AuditActionSubmissionRemove,
+			Actor:        "course-grader@test.edulinq.org",
+			CourseID:     "course101",
+			AssignmentID: "hw0",
+			Target:       "course-student@test.edulinq.org",
+			Before:       map[string]string{"submission": "course101::hw0::course-student@test.edulinq.org::1697406256"},
+		},
+		&model.AuditRecord{
+			Timestamp: timestamp.FromMSecs(300),
+			RequestID: "request-3",
+			Action:    model.AuditActionCourseDrop,
+			Actor:     "course-admin@test.edulinq.org",
+			CourseID:  "course101",
+			Target:    "course-other@test.edulinq.org",
+			Before:    map[string]string{"course-role": "other"},
+		},
+	}
+
+	for i, record := range input {
+		err = AppendAuditRecord(record)
+		if err != nil {
+			test.Fatalf("Failed to append audit record %d: '%v'.", i, err)
+		}
+
+		if record.Index != int64(i+1) {
+			test.Fatalf("Unexpected index for record %d. Expected: %d, Actual: %d.", i, i+1, record.Index)
+		}
+	}
+
+	records, err = GetAuditRecords(model.AuditQuery{})
+	if err != nil {
+		test.Fatalf("Failed to fetch audit records: '%v'.", err)
+	}
+
+	err = model.VerifyAuditChain(records)
+	if err != nil {
+		test.Fatalf("Failed to verify audit trail: '%v'.", err)
+	}
+
+	testCases := []struct {
+		query    model.AuditQuery
+		expected []*model.AuditRecord
+	}{
+		{model.AuditQuery{}, input},
+		{model.AuditQuery{CourseID: "course101"}, input[1:]},
+		{model.AuditQuery{CourseID: "course101", Action: model.AuditActionCourseDrop}, input[2:]},
+		{model.AuditQuery{Target: "course-student@test.edulinq.org"}, input[0:2]},
+		{model.AuditQuery{Actor: "server-admin@test.edulinq.org"}, input[0:1]},
+		{model.AuditQuery{After: timestamp.FromMSecs(150)}, input[1:]},
+		{model.AuditQuery{CourseID: "ZZZ"}, []*model.AuditRecord{}},
+	}
+
+	for i, testCase := range testCases {
+		records, err := GetAuditRecords(testCase.query)
+		if err != nil {
+			test.Errorf("Case %d: Failed to get audit records: '%v'.", i, err)
+			continue
+		}
+
+		if !reflect.DeepEqual(testCase.expected, records) {
+			test.Errorf("Case %d: Unexpected records. Expected: '%s', Actual: '%s'.",
+				i, util.MustToJSONIndent(testCase.expected), util.MustToJSONIndent(records))
+			continue
+		}
+	}
+}
+
+func (this *DBTests) DBTestAuditRecordsInvalid(test *testing.T) {
+	ResetForTesting()
+	defer ResetForTesting()
+
+	records := []*model.AuditRecord{
+		&model.AuditRecord{Action: model.AuditActionUserRemove},
+		&model.AuditRecord{Action: "ZZZ", Actor: "server-admin@test.edulinq.org"},
+	}
+
+	for i, record := range records {
+		err := AppendAuditRecord(record)
+		if err == nil {
+			test.Errorf("Case %d: Did not get an error on an invalid audit record.", i)
+		}
+	}
+}
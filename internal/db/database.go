@@ -12,6 +12,8 @@ import (
 
 	"github.com/edulinq/autograder/internal/config"
 	"github.com/edulinq/autograder/internal/db/disk"
+	"github.com/edulinq/autograder/internal/db/pg"
+	"github.com/edulinq/autograder/internal/db/sqlite"
 	"github.com/edulinq/autograder/internal/log"
 	"github.com/edulinq/autograder/internal/model"
 	"github.com/edulinq/autograder/internal/stats"
@@ -66,6 +68,46 @@ type Backend interface {
 	// Explicitly save an assignment.
 	SaveAssignment(assignment *model.Assignment) error
 
+	// Assignment Group Operations
+
+	// Get all the groups for an assignment, keyed by the group's ID.
+	GetAssignmentGroups(assignment *model.Assignment) (map[string]*model.AssignmentGroup, error)
+
+	// Upsert the given groups for an assignment.
+	// The map of groups is keyed by the group's ID,
+	// and a nil value indicates that the given group should be removed.
+	UpsertAssignmentGroups(assignment *model.Assignment, groups map[string]*model.AssignmentGroup) error
+
+	// Assignment Extension Operations
+
+	// Get all the extensions for an assignment, keyed by the user's email.
+	GetAssignmentExtensions(assignment *model.Assignment) (map[string]*model.AssignmentExtension, error)
+
+	// Upsert the given extensions for an assignment.
+	// The map of extensions is keyed by the user's email,
+	// and a nil value indicates that the user's extension should be removed.
+	UpsertAssignmentExtensions(assignment *model.Assignment, extensions map[string]*model.AssignmentExtension) error
+
+	// Rubric Grade Operations
+
+	// Get all the rubric grades for an assignment, keyed by the full submission ID.
+	GetRubricGrades(assignment *model.Assignment) (map[string]*model.RubricGrade, error)
+
+	// Upsert the given rubric grades for an assignment.
+	// The map of grades is keyed by the full submission ID,
+	// and a nil value indicates that the submission's grade should be removed.
+	UpsertRubricGrades(assignment *model.Assignment, grades map[string]*model.RubricGrade) error
+
+	// Regrade Request Operations
+
+	// Get all the regrade requests for an assignment, keyed by the request ID.
+	GetRegradeRequests(assignment *model.Assignment) (map[string]*model.RegradeRequest, error)
+
+	// Upsert the given regrade requests for an assignment.
+	// The map of requests is keyed by the request ID,
+	// and a nil value indicates that the request should be removed.
+	UpsertRegradeRequests(assignment *model.Assignment, requests map[string]*model.RegradeRequest) error
+
 	// User Operations
 	// User maps always map the user's ID to an actual user pointer.
 
@@ -124,6 +166,11 @@ type Backend interface {
 	// Get all attempts for a specific user.
 	GetSubmissionAttempts(assignment *model.Assignment, email string) ([]*model.GradingResult, error)
 
+	// Get the emails of all users that have at least one submission for this assignment,
+	// including users that are no longer enrolled in the course.
+	// The emails will be sorted.
+	GetSubmissionUsers(assignment *model.Assignment) ([]string, error)
+
 	// Get the scoring infos for an assignment for all users that match the given course user reference.
 	// Users without a submission (but matching the reference) will be represented with a nil map value.
 	// A nil map should only be returned on error.
@@ -165,6 +212,54 @@ type Backend interface {
 	// and a nil value indicates that the given task should be removed.
 	UpsertActiveTasks(tasks map[string]*model.FullScheduledTask) error
 
+	// Grading Queue Operations
+
+	// Get a specific grading ticket.
+	// Returns (nil, nil) if the ticket does not exist.
+	GetGradingTicket(ticketID string) (*model.GradingTicket, error)
+
+	// Get all the grading tickets that are not done (queued or running).
+	// The tickets will be sorted by queue time (ties broken by ID), i.e., the order they should be graded in.
+	GetActiveGradingTickets() ([]*model.GradingTicket, error)
+
+	// Get all the grading tickets that are done.
+	// The tickets will be sorted by queue time (ties broken by ID).
+	GetDoneGradingTickets() ([]*model.GradingTicket, error)
+
+	// Upsert the given grading tickets.
+	// The map of tickets is keyed by the ticket's ID,
+	// and a nil value indicates that the given ticket should be removed.
+	UpsertGradingTickets(tickets map[string]*model.GradingTicket) error
+
+	// Get all the grading tickets (including done tickets) submitted by a user.
+	// The tickets will be sorted by queue time (ties broken by ID).
+	GetUserGradingTickets(email string) ([]*model.GradingTicket, error)
+
+	// Login Throttle Operations
+
+	// Get the login throttles for the given keys.
+	// Keys without a throttle will not be represented in the output.
+	GetLoginThrottles(keys []string) (map[string]*model.LoginThrottle, error)
+
+	// Upsert the given login throttles.
+	// The map of throttles is keyed by the throttle's key,
+	// and a nil value indicates that the given throttle should be removed.
+	UpsertLoginThrottles(throttles map[string]*model.LoginThrottle) error
+
+	// Audit Operations
+
+	// Add a record to the end of the audit trail.
+	// The record will already be chained to the current last record (see model.AuditRecord.Chain()).
+	// Audit records are never modified or removed (except when the entire database is cleared).
+	AppendAuditRecord(record *model.AuditRecord) error
+
+	// Get the last record in the audit trail.
+	// Returns (nil, nil) if the trail is empty.
+	GetLastAuditRecord() (*model.AuditRecord, error)
+
+	// Get all the audit records that match the query, sorted by index.
+	GetAuditRecords(query model.AuditQuery) ([]*model.AuditRecord, error)
+
 	// Logging Operations
 
 	// DB backends will also be used as logging storage backends.
@@ -174,11 +269,18 @@ type Backend interface {
 	// Each parameter (except for the log level) can be passed with a zero value, in which case it will not be used for filtering.
 	GetLogRecords(query log.ParsedLogQuery) ([]*log.Record, error)
 
+	// Remove all the log records attributed to a user (via the record's user field).
+	RemoveUserLogRecords(email string) error
+
 	// Stats Operations
 
 	// DB backends will also be used as stats storage backends.
 	stats.StorageBackend
 
+	// Replace the user of every metric attributed to a user (stats.MetricAttributeUserEmail) with the given pseudonym.
+	// The metrics themselves are kept, so aggregate statistics do not change.
+	PseudonymizeUserMetrics(email string, pseudonym string) error
+
 	// Analysis Operations
 
 	// Fetch any matching individual analysis results.
@@ -202,6 +304,12 @@ type Backend interface {
 
 	// Store the results of a pairwise analysis.
 	StorePairwiseAnalysis(records []*model.PairwiseAnalysis) error
+
+	// Get all the individual analysis results stored for a course (sorted by full submission ID).
+	GetCourseIndividualAnalysis(courseID string) ([]*model.IndividualAnalysis, error)
+
+	// Get all the pairwise analysis results stored for a course (sorted by key).
+	GetCoursePairwiseAnalysis(courseID string) ([]*model.PairwiseAnalysis, error)
 }
 
 func Open() error {
@@ -213,15 +321,7 @@ func Open() error {
 	}
 
 	var err error
-	dbType := config.DB_TYPE.Get()
-
-	switch dbType {
-	case DB_TYPE_DISK:
-		backend, err = disk.Open()
-	default:
-		err = fmt.Errorf("Unknown database type: '%s'.", dbType)
-	}
-
+	backend, err = OpenBackend(config.DB_TYPE.Get())
 	if err != nil {
 		return fmt.Errorf("Failed to open database: '%w'.", err)
 	}
@@ -258,6 +358,33 @@ func Open() error {
 	return nil
 }
 
+// Open a specific type of backend without making it the active database.
+// This is meant for tools that work with multiple databases at once (e.g., migrations),
+// normal code should just use Open().
+// The caller is responsible for closing the returned backend.
+func OpenBackend(dbType string) (Backend, error) {
+	var newBackend Backend
+	var err error
+
+	// Backends return typed pointers, so make sure we do not return a non-nil interface to a nil backend.
+	switch dbType {
+	case DB_TYPE_DISK:
+		newBackend, err = disk.Open()
+	case DB_TYPE_SQLITE:
+		newBackend, err = sqlite.Open()
+	case DB_TYPE_POSTGRES:
+		newBackend, err = pg.Open()
+	default:
+		err = fmt.Errorf("Unknown database type: '%s'.", dbType)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return newBackend, nil
+}
+
 func Close() error {
 	dbLock.Lock()
 	defer dbLock.Unlock()
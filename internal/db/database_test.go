@@ -12,6 +12,14 @@ import (
 // Backends to put through the standard tests.
 var testBackends []string = []string{
 	DB_TYPE_DISK,
+	DB_TYPE_SQLITE,
+}
+
+func init() {
+	// Postgres needs an external server, so only test it when one has been provided.
+	if config.DB_PG_URI.Get() != "" {
+		testBackends = append(testBackends, DB_TYPE_POSTGRES)
+	}
 }
 
 // Methods attatched to this struct will be called for each backend in testBackends.
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"path/filepath"
 	"slices"
+	"strings"
 
 	"github.com/edulinq/autograder/internal/common"
 	"github.com/edulinq/autograder/internal/log"
@@ -116,6 +117,34 @@ func (this *backend) removeCourseIndividualAnalysis(courseID string, fullSubmiss
 	return util.RemoveEntriesJSONLFile(path, model.IndividualAnalysis{}, shouldRemoveFunc)
 }
 
+func (this *backend) GetCourseIndividualAnalysis(courseID string) ([]*model.IndividualAnalysis, error) {
+	this.analysisIndividualLock.RLock()
+	defer this.analysisIndividualLock.RUnlock()
+
+	// Later records override earlier ones.
+	records := make(map[string]*model.IndividualAnalysis)
+	applyFunc := func(index int, record *model.IndividualAnalysis, line string) {
+		records[record.FullID] = record
+	}
+
+	path := this.getAnalysisIndividualPath(courseID)
+	err := util.ApplyJSONLFile(path, model.IndividualAnalysis{}, applyFunc)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read individual analysis for course '%s': '%w'.", courseID, err)
+	}
+
+	results := make([]*model.IndividualAnalysis, 0, len(records))
+	for _, record := range records {
+		results = append(results, record)
+	}
+
+	slices.SortFunc(results, func(a *model.IndividualAnalysis, b *model.IndividualAnalysis) int {
+		return strings.Compare(a.FullID, b.FullID)
+	})
+
+	return results, nil
+}
+
 func splitSubmissionIDsByCourse(fullSubmissionIDs []string) map[string][]string {
 	courseQueries := make(map[string][]string)
 	for _, fullSubmissionID := range fullSubmissionIDs {
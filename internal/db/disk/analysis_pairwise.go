@@ -116,6 +116,34 @@ func (this *backend) removeCoursePairwiseAnalysis(courseID string, keys []model.
 	return util.RemoveEntriesJSONLFile(path, model.PairwiseAnalysis{}, shouldRemoveFunc)
 }
 
+func (this *backend) GetCoursePairwiseAnalysis(courseID string) ([]*model.PairwiseAnalysis, error) {
+	this.analysisPairwiseLock.RLock()
+	defer this.analysisPairwiseLock.RUnlock()
+
+	// Later records override earlier ones.
+	records := make(map[model.PairwiseKey]*model.PairwiseAnalysis)
+	applyFunc := func(index int, record *model.PairwiseAnalysis, line string) {
+		records[record.SubmissionIDs] = record
+	}
+
+	path := this.getAnalysisPairwisePath(courseID)
+	err := util.ApplyJSONLFile(path, model.PairwiseAnalysis{}, applyFunc)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read pairwise analysis for course '%s': '%w'.", courseID, err)
+	}
+
+	results := make([]*model.PairwiseAnalysis, 0, len(records))
+	for _, record := range records {
+		results = append(results, record)
+	}
+
+	slices.SortFunc(results, func(a *model.PairwiseAnalysis, b *model.PairwiseAnalysis) int {
+		return model.ComparePairwiseKey(a.SubmissionIDs, b.SubmissionIDs)
+	})
+
+	return results, nil
+}
+
 func splitPairwiseKeysByCourse(keys []model.PairwiseKey) map[string][]model.PairwiseKey {
 	courseQueries := make(map[string][]model.PairwiseKey)
 	for _, key := range keys {
@@ -0,0 +1,43 @@
+package disk
+
+import (
+	"path/filepath"
+
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+const DISK_DB_AUDIT_FILENAME = "audit.jsonl"
+
+func (this *backend) AppendAuditRecord(record *model.AuditRecord) error {
+	this.auditLock.Lock()
+	defer this.auditLock.Unlock()
+
+	return util.AppendJSONLFile(this.getAuditPath(), record)
+}
+
+// Only the end of the audit file is read (since this is called on every append).
+func (this *backend) GetLastAuditRecord() (*model.AuditRecord, error) {
+	this.auditLock.RLock()
+	defer this.auditLock.RUnlock()
+
+	return util.ReadLastJSONLRecord(this.getAuditPath(), model.AuditRecord{})
+}
+
+func (this *backend) GetAuditRecords(query model.AuditQuery) ([]*model.AuditRecord, error) {
+	this.auditLock.RLock()
+	defer this.auditLock.RUnlock()
+
+	path := this.getAuditPath()
+	if !util.PathExists(path) {
+		return make([]*model.AuditRecord, 0), nil
+	}
+
+	return util.FilterJSONLFile(path, model.AuditRecord{}, func(record *model.AuditRecord) bool {
+		return query.Match(record)
+	})
+}
+
+func (this *backend) getAuditPath() string {
+	return filepath.Join(this.baseDir, DISK_DB_AUDIT_FILENAME)
+}
@@ -27,6 +27,9 @@ type backend struct {
 	logLock                sync.RWMutex
 	userLock               sync.RWMutex
 	tasksLock              sync.RWMutex
+	gradingQueueLock       sync.RWMutex
+	loginThrottleLock      sync.RWMutex
+	auditLock              sync.RWMutex
 	analysisIndividualLock sync.RWMutex
 	analysisPairwiseLock   sync.RWMutex
 }
@@ -91,6 +94,15 @@ func (this *backend) Clear() error {
 	this.tasksLock.Lock()
 	defer this.tasksLock.Unlock()
 
+	this.gradingQueueLock.Lock()
+	defer this.gradingQueueLock.Unlock()
+
+	this.loginThrottleLock.Lock()
+	defer this.loginThrottleLock.Unlock()
+
+	this.auditLock.Lock()
+	defer this.auditLock.Unlock()
+
 	this.analysisIndividualLock.Lock()
 	defer this.analysisIndividualLock.Unlock()
 
@@ -0,0 +1,73 @@
+package disk
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+const DISK_DB_EXTENSIONS_FILENAME = "extensions.json"
+
+// All the extensions for an assignment are stored together in a single file.
+
+func (this *backend) GetAssignmentExtensions(assignment *model.Assignment) (map[string]*model.AssignmentExtension, error) {
+	path := this.getAssignmentExtensionsPath(assignment)
+
+	this.contextReadLock(path)
+	defer this.contextReadUnlock(path)
+
+	return this.getAssignmentExtensions(path)
+}
+
+func (this *backend) UpsertAssignmentExtensions(assignment *model.Assignment, upsertExtensions map[string]*model.AssignmentExtension) error {
+	path := this.getAssignmentExtensionsPath(assignment)
+
+	this.contextLock(path)
+	defer this.contextUnlock(path)
+
+	extensions, err := this.getAssignmentExtensions(path)
+	if err != nil {
+		return err
+	}
+
+	for email, upsertExtension := range upsertExtensions {
+		if upsertExtension == nil {
+			delete(extensions, email)
+		} else {
+			extensions[email] = upsertExtension
+		}
+	}
+
+	err = util.MkDir(filepath.Dir(path))
+	if err != nil {
+		return fmt.Errorf("Failed to create assignment dir for extensions '%s': '%w'.", filepath.Dir(path), err)
+	}
+
+	err = util.ToJSONFileIndent(extensions, path)
+	if err != nil {
+		return fmt.Errorf("Failed to write assignment extensions file '%s': '%w'.", path, err)
+	}
+
+	return nil
+}
+
+func (this *backend) getAssignmentExtensionsPath(assignment *model.Assignment) string {
+	return filepath.Join(this.getAssignmentDir(assignment), DISK_DB_EXTENSIONS_FILENAME)
+}
+
+func (this *backend) getAssignmentExtensions(path string) (map[string]*model.AssignmentExtension, error) {
+	extensions := make(map[string]*model.AssignmentExtension)
+
+	if !util.PathExists(path) {
+		return extensions, nil
+	}
+
+	err := util.JSONFromFile(path, &extensions)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read assignment extensions file '%s': '%w'.", path, err)
+	}
+
+	return extensions, nil
+}
@@ -0,0 +1,144 @@
+package disk
+
+import (
+	"cmp"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+const DISK_DB_GRADING_QUEUE_DIRNAME = "grading-queue"
+
+// Each ticket is stored in its own file (since tickets can hold submission files).
+
+func (this *backend) GetGradingTicket(ticketID string) (*model.GradingTicket, error) {
+	this.gradingQueueLock.RLock()
+	defer this.gradingQueueLock.RUnlock()
+
+	return this.getGradingTicket(this.getGradingTicketPath(ticketID))
+}
+
+func (this *backend) GetActiveGradingTickets() ([]*model.GradingTicket, error) {
+	this.gradingQueueLock.RLock()
+	defer this.gradingQueueLock.RUnlock()
+
+	return this.getMatchingGradingTickets(func(ticket *model.GradingTicket) bool {
+		return !ticket.IsDone()
+	})
+}
+
+func (this *backend) GetDoneGradingTickets() ([]*model.GradingTicket, error) {
+	this.gradingQueueLock.RLock()
+	defer this.gradingQueueLock.RUnlock()
+
+	return this.getMatchingGradingTickets(func(ticket *model.GradingTicket) bool {
+		return ticket.IsDone()
+	})
+}
+
+func (this *backend) GetUserGradingTickets(email string) ([]*model.GradingTicket, error) {
+	this.gradingQueueLock.RLock()
+	defer this.gradingQueueLock.RUnlock()
+
+	return this.getMatchingGradingTickets(func(ticket *model.GradingTicket) bool {
+		return ticket.User == email
+	})
+}
+
+// Get all the tickets that match the given function (sorted by queue time).
+// The caller should already hold the grading queue lock.
+func (this *backend) getMatchingGradingTickets(matchFunc func(ticket *model.GradingTicket) bool) ([]*model.GradingTicket, error) {
+	tickets := make([]*model.GradingTicket, 0)
+
+	baseDir := this.getGradingQueueDir()
+	if !util.PathExists(baseDir) {
+		return tickets, nil
+	}
+
+	dirents, err := os.ReadDir(baseDir)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read grading queue dir '%s': '%w'.", baseDir, err)
+	}
+
+	for _, dirent := range dirents {
+		if dirent.IsDir() || !strings.HasSuffix(dirent.Name(), ".json") {
+			continue
+		}
+
+		ticket, err := this.getGradingTicket(filepath.Join(baseDir, dirent.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		if (ticket == nil) || !matchFunc(ticket) {
+			continue
+		}
+
+		tickets = append(tickets, ticket)
+	}
+
+	slices.SortFunc(tickets, compareGradingTickets)
+
+	return tickets, nil
+}
+
+func (this *backend) UpsertGradingTickets(upsertTickets map[string]*model.GradingTicket) error {
+	this.gradingQueueLock.Lock()
+	defer this.gradingQueueLock.Unlock()
+
+	err := util.MkDir(this.getGradingQueueDir())
+	if err != nil {
+		return fmt.Errorf("Failed to create grading queue dir '%s': '%w'.", this.getGradingQueueDir(), err)
+	}
+
+	for ticketID, upsertTicket := range upsertTickets {
+		path := this.getGradingTicketPath(ticketID)
+
+		if upsertTicket == nil {
+			err = util.RemoveDirent(path)
+			if err != nil {
+				return fmt.Errorf("Failed to remove grading ticket '%s': '%w'.", ticketID, err)
+			}
+
+			continue
+		}
+
+		err = util.ToJSONFile(upsertTicket, path)
+		if err != nil {
+			return fmt.Errorf("Failed to write grading ticket '%s': '%w'.", ticketID, err)
+		}
+	}
+
+	return nil
+}
+
+func (this *backend) getGradingQueueDir() string {
+	return filepath.Join(this.baseDir, DISK_DB_GRADING_QUEUE_DIRNAME)
+}
+
+func (this *backend) getGradingTicketPath(ticketID string) string {
+	return filepath.Join(this.getGradingQueueDir(), ticketID+".json")
+}
+
+func (this *backend) getGradingTicket(path string) (*model.GradingTicket, error) {
+	if !util.PathExists(path) {
+		return nil, nil
+	}
+
+	var ticket model.GradingTicket
+	err := util.JSONFromFile(path, &ticket)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read grading ticket file '%s': '%w'.", path, err)
+	}
+
+	return &ticket, nil
+}
+
+func compareGradingTickets(a *model.GradingTicket, b *model.GradingTicket) int {
+	return cmp.Or(cmp.Compare(a.QueueTime, b.QueueTime), strings.Compare(a.ID, b.ID))
+}
@@ -0,0 +1,73 @@
+package disk
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+const DISK_DB_GROUPS_FILENAME = "groups.json"
+
+// All the groups for an assignment are stored together in a single file.
+
+func (this *backend) GetAssignmentGroups(assignment *model.Assignment) (map[string]*model.AssignmentGroup, error) {
+	path := this.getAssignmentGroupsPath(assignment)
+
+	this.contextReadLock(path)
+	defer this.contextReadUnlock(path)
+
+	return this.getAssignmentGroups(path)
+}
+
+func (this *backend) UpsertAssignmentGroups(assignment *model.Assignment, upsertGroups map[string]*model.AssignmentGroup) error {
+	path := this.getAssignmentGroupsPath(assignment)
+
+	this.contextLock(path)
+	defer this.contextUnlock(path)
+
+	groups, err := this.getAssignmentGroups(path)
+	if err != nil {
+		return err
+	}
+
+	for groupID, upsertGroup := range upsertGroups {
+		if upsertGroup == nil {
+			delete(groups, groupID)
+		} else {
+			groups[groupID] = upsertGroup
+		}
+	}
+
+	err = util.MkDir(filepath.Dir(path))
+	if err != nil {
+		return fmt.Errorf("Failed to create assignment dir for groups '%s': '%w'.", filepath.Dir(path), err)
+	}
+
+	err = util.ToJSONFileIndent(groups, path)
+	if err != nil {
+		return fmt.Errorf("Failed to write assignment groups file '%s': '%w'.", path, err)
+	}
+
+	return nil
+}
+
+func (this *backend) getAssignmentGroupsPath(assignment *model.Assignment) string {
+	return filepath.Join(this.getAssignmentDir(assignment), DISK_DB_GROUPS_FILENAME)
+}
+
+func (this *backend) getAssignmentGroups(path string) (map[string]*model.AssignmentGroup, error) {
+	groups := make(map[string]*model.AssignmentGroup)
+
+	if !util.PathExists(path) {
+		return groups, nil
+	}
+
+	err := util.JSONFromFile(path, &groups)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read assignment groups file '%s': '%w'.", path, err)
+	}
+
+	return groups, nil
+}
@@ -32,6 +32,15 @@ func (this *backend) GetLogRecords(query log.ParsedLogQuery) ([]*log.Record, err
 	return records, err
 }
 
+func (this *backend) RemoveUserLogRecords(email string) error {
+	this.logLock.Lock()
+	defer this.logLock.Unlock()
+
+	return util.RemoveEntriesJSONLFile(this.getLogPath(), log.Record{}, func(record *log.Record) bool {
+		return record.User == email
+	})
+}
+
 func (this *backend) getLogPath() string {
 	return filepath.Join(this.baseDir, LOG_FILENAME)
 }
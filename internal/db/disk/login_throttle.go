@@ -0,0 +1,83 @@
+package disk
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+const DISK_DB_LOGIN_THROTTLES_FILENAME = "login-throttles.json"
+
+func (this *backend) GetLoginThrottles(keys []string) (map[string]*model.LoginThrottle, error) {
+	this.loginThrottleLock.RLock()
+	defer this.loginThrottleLock.RUnlock()
+
+	allThrottles, err := this.getLoginThrottles()
+	if err != nil {
+		return nil, err
+	}
+
+	throttles := make(map[string]*model.LoginThrottle, len(keys))
+	for _, key := range keys {
+		throttle, ok := allThrottles[key]
+		if ok {
+			throttles[key] = throttle
+		}
+	}
+
+	return throttles, nil
+}
+
+func (this *backend) UpsertLoginThrottles(upsertThrottles map[string]*model.LoginThrottle) error {
+	this.loginThrottleLock.Lock()
+	defer this.loginThrottleLock.Unlock()
+
+	allThrottles, err := this.getLoginThrottles()
+	if err != nil {
+		return err
+	}
+
+	for key, upsertThrottle := range upsertThrottles {
+		if upsertThrottle == nil {
+			delete(allThrottles, key)
+		} else {
+			allThrottles[key] = upsertThrottle
+		}
+	}
+
+	path := this.getLoginThrottlesPath()
+
+	err = util.MkDir(filepath.Dir(path))
+	if err != nil {
+		return fmt.Errorf("Failed to create directory for login throttles file '%s': '%w'.", path, err)
+	}
+
+	err = util.ToJSONFileIndent(allThrottles, path)
+	if err != nil {
+		return fmt.Errorf("Failed to write login throttles file '%s': '%w'.", path, err)
+	}
+
+	return nil
+}
+
+func (this *backend) getLoginThrottlesPath() string {
+	return filepath.Join(this.baseDir, DISK_DB_LOGIN_THROTTLES_FILENAME)
+}
+
+func (this *backend) getLoginThrottles() (map[string]*model.LoginThrottle, error) {
+	throttles := make(map[string]*model.LoginThrottle, 0)
+
+	path := this.getLoginThrottlesPath()
+	if !util.PathExists(path) {
+		return throttles, nil
+	}
+
+	err := util.JSONFromFile(path, &throttles)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read login throttles file '%s': '%w'.", path, err)
+	}
+
+	return throttles, nil
+}
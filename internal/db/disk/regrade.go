@@ -0,0 +1,73 @@
+package disk
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+const DISK_DB_REGRADE_REQUESTS_FILENAME = "regrade-requests.json"
+
+// All the regrade requests for an assignment are stored together in a single file.
+
+func (this *backend) GetRegradeRequests(assignment *model.Assignment) (map[string]*model.RegradeRequest, error) {
+	path := this.getRegradeRequestsPath(assignment)
+
+	this.contextReadLock(path)
+	defer this.contextReadUnlock(path)
+
+	return this.getRegradeRequests(path)
+}
+
+func (this *backend) UpsertRegradeRequests(assignment *model.Assignment, upsertRequests map[string]*model.RegradeRequest) error {
+	path := this.getRegradeRequestsPath(assignment)
+
+	this.contextLock(path)
+	defer this.contextUnlock(path)
+
+	requests, err := this.getRegradeRequests(path)
+	if err != nil {
+		return err
+	}
+
+	for requestID, upsertRequest := range upsertRequests {
+		if upsertRequest == nil {
+			delete(requests, requestID)
+		} else {
+			requests[requestID] = upsertRequest
+		}
+	}
+
+	err = util.MkDir(filepath.Dir(path))
+	if err != nil {
+		return fmt.Errorf("Failed to create assignment dir for regrade requests '%s': '%w'.", filepath.Dir(path), err)
+	}
+
+	err = util.ToJSONFileIndent(requests, path)
+	if err != nil {
+		return fmt.Errorf("Failed to write regrade requests file '%s': '%w'.", path, err)
+	}
+
+	return nil
+}
+
+func (this *backend) getRegradeRequestsPath(assignment *model.Assignment) string {
+	return filepath.Join(this.getAssignmentDir(assignment), DISK_DB_REGRADE_REQUESTS_FILENAME)
+}
+
+func (this *backend) getRegradeRequests(path string) (map[string]*model.RegradeRequest, error) {
+	requests := make(map[string]*model.RegradeRequest)
+
+	if !util.PathExists(path) {
+		return requests, nil
+	}
+
+	err := util.JSONFromFile(path, &requests)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read regrade requests file '%s': '%w'.", path, err)
+	}
+
+	return requests, nil
+}
@@ -0,0 +1,73 @@
+package disk
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+const DISK_DB_RUBRIC_GRADES_FILENAME = "rubric-grades.json"
+
+// All the rubric grades for an assignment are stored together in a single file.
+
+func (this *backend) GetRubricGrades(assignment *model.Assignment) (map[string]*model.RubricGrade, error) {
+	path := this.getRubricGradesPath(assignment)
+
+	this.contextReadLock(path)
+	defer this.contextReadUnlock(path)
+
+	return this.getRubricGrades(path)
+}
+
+func (this *backend) UpsertRubricGrades(assignment *model.Assignment, upsertGrades map[string]*model.RubricGrade) error {
+	path := this.getRubricGradesPath(assignment)
+
+	this.contextLock(path)
+	defer this.contextUnlock(path)
+
+	grades, err := this.getRubricGrades(path)
+	if err != nil {
+		return err
+	}
+
+	for submissionID, upsertGrade := range upsertGrades {
+		if upsertGrade == nil {
+			delete(grades, submissionID)
+		} else {
+			grades[submissionID] = upsertGrade
+		}
+	}
+
+	err = util.MkDir(filepath.Dir(path))
+	if err != nil {
+		return fmt.Errorf("Failed to create assignment dir for rubric grades '%s': '%w'.", filepath.Dir(path), err)
+	}
+
+	err = util.ToJSONFileIndent(grades, path)
+	if err != nil {
+		return fmt.Errorf("Failed to write rubric grades file '%s': '%w'.", path, err)
+	}
+
+	return nil
+}
+
+func (this *backend) getRubricGradesPath(assignment *model.Assignment) string {
+	return filepath.Join(this.getAssignmentDir(assignment), DISK_DB_RUBRIC_GRADES_FILENAME)
+}
+
+func (this *backend) getRubricGrades(path string) (map[string]*model.RubricGrade, error) {
+	grades := make(map[string]*model.RubricGrade)
+
+	if !util.PathExists(path) {
+		return grades, nil
+	}
+
+	err := util.JSONFromFile(path, &grades)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read rubric grades file '%s': '%w'.", path, err)
+	}
+
+	return grades, nil
+}
@@ -38,6 +38,36 @@ func (this *backend) StoreMetric(record *stats.Metric) error {
 	return util.AppendJSONLFile(path, record)
 }
 
+func (this *backend) PseudonymizeUserMetrics(email string, pseudonym string) error {
+	for _, metricType := range stats.GetMetricTypes() {
+		path, err := this.getStatsPath(metricType)
+		if err != nil {
+			return err
+		}
+
+		err = this.pseudonymizeUserMetricsFile(path, email, pseudonym)
+		if err != nil {
+			return fmt.Errorf("Failed to pseudonymize '%s' metrics: '%w'.", metricType, err)
+		}
+	}
+
+	return nil
+}
+
+func (this *backend) pseudonymizeUserMetricsFile(path string, email string, pseudonym string) error {
+	this.contextLock(path)
+	defer this.contextUnlock(path)
+
+	return util.UpdateEntriesJSONLFile(path, stats.Metric{}, func(record *stats.Metric) bool {
+		if !record.IsUser(email) {
+			return false
+		}
+
+		record.Attributes[stats.MetricAttributeUserEmail] = pseudonym
+		return true
+	})
+}
+
 func (this *backend) getStatsPath(metricType stats.MetricType) (string, error) {
 	if metricType == "" {
 		return "", fmt.Errorf("No metric type was given.")
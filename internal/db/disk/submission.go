@@ -8,7 +8,6 @@ import (
 	"slices"
 	"time"
 
-	"github.com/edulinq/autograder/internal/common"
 	"github.com/edulinq/autograder/internal/model"
 	"github.com/edulinq/autograder/internal/util"
 )
@@ -31,38 +30,7 @@ func (this *backend) saveSubmissionLock(course *model.Course, submission *model.
 		defer this.contextUnlock(baseDir)
 	}
 
-	err := util.MkDir(baseDir)
-	if err != nil {
-		return fmt.Errorf("Failed to make submission dir '%s': '%w'.", baseDir, err)
-	}
-
-	resultPath := filepath.Join(baseDir, model.SUBMISSION_RESULT_FILENAME)
-	err = util.ToJSONFileIndent(submission.Info, resultPath)
-	if err != nil {
-		return fmt.Errorf("Failed to write submission result '%s': '%w'.", resultPath, err)
-	}
-
-	err = util.GzipBytesToDirectory(filepath.Join(baseDir, common.GRADING_INPUT_DIRNAME), submission.InputFilesGZip)
-	if err != nil {
-		return fmt.Errorf("Failed to write submission input files: '%w'.", err)
-	}
-
-	err = util.GzipBytesToDirectory(filepath.Join(baseDir, common.GRADING_OUTPUT_DIRNAME), submission.OutputFilesGZip)
-	if err != nil {
-		return fmt.Errorf("Failed to write submission input files: '%w'.", err)
-	}
-
-	err = util.WriteFile(submission.Stdout, filepath.Join(baseDir, common.SUBMISSION_STDOUT_FILENAME))
-	if err != nil {
-		return fmt.Errorf("Failed to write submission stdout file: '%w'.", err)
-	}
-
-	err = util.WriteFile(submission.Stderr, filepath.Join(baseDir, common.SUBMISSION_STDERR_FILENAME))
-	if err != nil {
-		return fmt.Errorf("Failed to write submission stderr file: '%w'.", err)
-	}
-
-	return nil
+	return model.WriteGradingResult(submission, baseDir)
 }
 
 func (this *backend) SaveSubmissions(course *model.Course, submissions []*model.GradingResult) error {
@@ -189,7 +157,7 @@ func (this *backend) GetRecentSubmissions(assignment *model.Assignment, referenc
 	}
 
 	for email, user := range users {
-		if !reference.RefersTo(email, user.Role) {
+		if !reference.RefersTo(email, user.Role, user.Sections) {
 			continue
 		}
 
@@ -288,7 +256,7 @@ func (this *backend) GetRecentSubmissionContents(assignment *model.Assignment, r
 	}
 
 	for email, user := range users {
-		if !reference.RefersTo(email, user.Role) {
+		if !reference.RefersTo(email, user.Role, user.Sections) {
 			continue
 		}
 
@@ -303,6 +271,30 @@ func (this *backend) GetRecentSubmissionContents(assignment *model.Assignment, r
 	return results, nil
 }
 
+func (this *backend) GetSubmissionUsers(assignment *model.Assignment) ([]string, error) {
+	emails := make([]string, 0)
+
+	submissionsDir := this.getAssignmentSubmissionsDir(assignment.GetCourse().GetID(), assignment.GetID())
+	if !util.PathExists(submissionsDir) {
+		return emails, nil
+	}
+
+	dirents, err := os.ReadDir(submissionsDir)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to read assignment submissions dir '%s': '%w'.", submissionsDir, err)
+	}
+
+	for _, dirent := range dirents {
+		if dirent.IsDir() {
+			emails = append(emails, dirent.Name())
+		}
+	}
+
+	slices.Sort(emails)
+
+	return emails, nil
+}
+
 func (this *backend) getSubmissionDir(courseID string, assignmentID string, user string, shortSubmissionID string) string {
 	return filepath.Join(this.getUserSubmissionDir(courseID, assignmentID, user), shortSubmissionID)
 }
@@ -316,7 +308,11 @@ func (this *backend) getSubmissionDirFromResult(gradingInfo *model.GradingInfo)
 }
 
 func (this *backend) getUserSubmissionDir(courseID string, assignmentID string, user string) string {
-	return filepath.Join(this.getCourseDirFromID(courseID), model.SUBMISSIONS_DIRNAME, assignmentID, user)
+	return filepath.Join(this.getAssignmentSubmissionsDir(courseID, assignmentID), user)
+}
+
+func (this *backend) getAssignmentSubmissionsDir(courseID string, assignmentID string) string {
+	return filepath.Join(this.getCourseDirFromID(courseID), model.SUBMISSIONS_DIRNAME, assignmentID)
 }
 
 // Get the short ID of the most recent submission (or empty string if there are no submissions).
@@ -365,6 +361,17 @@ func (this *backend) RemoveSubmission(assignment *model.Assignment, email string
 		return false, wrappedErr
 	}
 
+	// Remove the user's submissions dir once it is empty,
+	// so the user no longer shows up as having submissions.
+	userDir := filepath.Dir(submissionDir)
+	dirents, err := os.ReadDir(userDir)
+	if (err == nil) && (len(dirents) == 0) {
+		err = util.RemoveDirent(userDir)
+		if err != nil {
+			return true, fmt.Errorf("Failed to remove empty user submissions dir '%s': '%w'.", userDir, err)
+		}
+	}
+
 	return true, nil
 }
 
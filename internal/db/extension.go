@@ -0,0 +1,71 @@
+package db
+
+import (
+	"fmt"
+
+	"github.com/edulinq/autograder/internal/model"
+)
+
+func GetAssignmentExtensions(assignment *model.Assignment) (map[string]*model.AssignmentExtension, error) {
+	if backend == nil {
+		return nil, fmt.Errorf("Database has not been opened.")
+	}
+
+	return backend.GetAssignmentExtensions(assignment)
+}
+
+// Get a user's extension for an assignment.
+// Returns (nil, nil) if the user does not have an extension.
+func GetAssignmentExtension(assignment *model.Assignment, email string) (*model.AssignmentExtension, error) {
+	extensions, err := GetAssignmentExtensions(assignment)
+	if err != nil {
+		return nil, err
+	}
+
+	return extensions[email], nil
+}
+
+func UpsertAssignmentExtension(assignment *model.Assignment, extension *model.AssignmentExtension) error {
+	err := extension.Validate()
+	if err != nil {
+		return fmt.Errorf("Invalid extension for user '%s': '%w'.", extension.Email, err)
+	}
+
+	extensions := map[string]*model.AssignmentExtension{
+		extension.Email: extension,
+	}
+
+	return UpsertAssignmentExtensions(assignment, extensions)
+}
+
+// Remove a user's extension for an assignment.
+// Returns true if the extension existed.
+func RemoveAssignmentExtension(assignment *model.Assignment, email string) (bool, error) {
+	extension, err := GetAssignmentExtension(assignment, email)
+	if err != nil {
+		return false, err
+	}
+
+	if extension == nil {
+		return false, nil
+	}
+
+	extensions := map[string]*model.AssignmentExtension{
+		email: nil,
+	}
+
+	err = UpsertAssignmentExtensions(assignment, extensions)
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func UpsertAssignmentExtensions(assignment *model.Assignment, extensions map[string]*model.AssignmentExtension) error {
+	if backend == nil {
+		return fmt.Errorf("Database has not been opened.")
+	}
+
+	return backend.UpsertAssignmentExtensions(assignment, extensions)
+}
@@ -0,0 +1,118 @@
+package db
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/timestamp"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func (this *DBTests) DBTestAssignmentExtensionsBase(test *testing.T) {
+	ResetForTesting()
+	defer ResetForTesting()
+
+	assignment := MustGetTestAssignment()
+
+	extensions, err := GetAssignmentExtensions(assignment)
+	if err != nil {
+		test.Fatalf("Failed to fetch empty extensions: '%v'.", err)
+	}
+
+	if len(extensions) != 0 {
+		test.Fatalf("Initial extension fetch is not empty, found %d extensions.", len(extensions))
+	}
+
+	dueDate := timestamp.FromMSecs(100)
+	expected := map[string]*model.AssignmentExtension{
+		"course-student@test.edulinq.org": &model.AssignmentExtension{
+			CourseID:     TEST_COURSE_ID,
+			AssignmentID: TEST_ASSIGNMENT_ID,
+			Email:        "course-student@test.edulinq.org",
+			DueDate:      &dueDate,
+			UpdatedBy:    "course-admin@test.edulinq.org",
+			UpdateTime:   timestamp.FromMSecs(200),
+		},
+		"course-other@test.edulinq.org": &model.AssignmentExtension{
+			CourseID:      TEST_COURSE_ID,
+			AssignmentID:  TEST_ASSIGNMENT_ID,
+			Email:         "course-other@test.edulinq.org",
+			ExtraTime:     &util.DurationSpec{Days: 2},
+			ExtraAttempts: 3,
+			Reason:        "Accommodation.",
+			UpdatedBy:     "course-admin@test.edulinq.org",
+			UpdateTime:    timestamp.FromMSecs(300),
+		},
+	}
+
+	for _, extension := range expected {
+		err = UpsertAssignmentExtension(assignment, extension)
+		if err != nil {
+			test.Fatalf("Failed to upsert extension: '%v'.", err)
+		}
+	}
+
+	extensions, err = GetAssignmentExtensions(assignment)
+	if err != nil {
+		test.Fatalf("Failed to fetch extensions: '%v'.", err)
+	}
+
+	if !reflect.DeepEqual(expected, extensions) {
+		test.Fatalf("Unexpected extensions. Expected: '%s', Actual: '%s'.", util.MustToJSONIndent(expected), util.MustToJSONIndent(extensions))
+	}
+
+	extension, err := GetAssignmentExtension(assignment, "course-grader@test.edulinq.org")
+	if err != nil {
+		test.Fatalf("Failed to fetch missing extension: '%v'.", err)
+	}
+
+	if extension != nil {
+		test.Fatalf("Found an extension for a user without one: '%s'.", util.MustToJSONIndent(extension))
+	}
+
+	// Extensions from other assignments are not visible.
+	otherExtensions, err := GetAssignmentExtensions(MustGetTestSubmissionAssignment())
+	if err != nil {
+		test.Fatalf("Failed to fetch other extensions: '%v'.", err)
+	}
+
+	if len(otherExtensions) != 0 {
+		test.Fatalf("Found extensions for another assignment: '%s'.", util.MustToJSONIndent(otherExtensions))
+	}
+
+	// Invalid extensions cannot be saved.
+	err = UpsertAssignmentExtension(assignment, &model.AssignmentExtension{Email: "course-grader@test.edulinq.org"})
+	if err == nil {
+		test.Fatalf("Did not get an error when saving an invalid extension.")
+	}
+
+	found, err := RemoveAssignmentExtension(assignment, "course-other@test.edulinq.org")
+	if err != nil {
+		test.Fatalf("Failed to remove extension: '%v'.", err)
+	}
+
+	if !found {
+		test.Fatalf("Did not find extension to remove.")
+	}
+
+	found, err = RemoveAssignmentExtension(assignment, "course-other@test.edulinq.org")
+	if err != nil {
+		test.Fatalf("Failed to remove missing extension: '%v'.", err)
+	}
+
+	if found {
+		test.Fatalf("Found extension that was already removed.")
+	}
+
+	extensions, err = GetAssignmentExtensions(assignment)
+	if err != nil {
+		test.Fatalf("Failed to fetch extensions after removal: '%v'.", err)
+	}
+
+	delete(expected, "course-other@test.edulinq.org")
+
+	if !reflect.DeepEqual(expected, extensions) {
+		test.Fatalf("Unexpected extensions after removal. Expected: '%s', Actual: '%s'.", util.MustToJSONIndent(expected), util.MustToJSONIndent(extensions))
+	}
+}
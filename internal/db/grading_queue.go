@@ -0,0 +1,96 @@
+package db
+
+import (
+	"fmt"
+
+	"github.com/edulinq/autograder/internal/common"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/timestamp"
+)
+
+// Get a grading ticket.
+// Returns (nil, nil) if the ticket does not exist.
+func GetGradingTicket(rawTicketID string) (*model.GradingTicket, error) {
+	if backend == nil {
+		return nil, fmt.Errorf("Database has not been opened.")
+	}
+
+	ticketID, err := common.ValidateID(rawTicketID)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to validate grading ticket id '%s': '%w'.", rawTicketID, err)
+	}
+
+	return backend.GetGradingTicket(ticketID)
+}
+
+func GetActiveGradingTickets() ([]*model.GradingTicket, error) {
+	if backend == nil {
+		return nil, fmt.Errorf("Database has not been opened.")
+	}
+
+	return backend.GetActiveGradingTickets()
+}
+
+// Remove all the done grading tickets that finished before the given time.
+// Done tickets without an end time are judged by their queue time.
+// Returns the number of removed tickets.
+func RemoveDoneGradingTickets(before timestamp.Timestamp) (int, error) {
+	if backend == nil {
+		return 0, fmt.Errorf("Database has not been opened.")
+	}
+
+	tickets, err := backend.GetDoneGradingTickets()
+	if err != nil {
+		return 0, fmt.Errorf("Failed to get done grading tickets: '%w'.", err)
+	}
+
+	removeTickets := make(map[string]*model.GradingTicket)
+	for _, ticket := range tickets {
+		endTime := ticket.QueueTime
+		if ticket.EndTime != nil {
+			endTime = *ticket.EndTime
+		}
+
+		if endTime < before {
+			removeTickets[ticket.ID] = nil
+		}
+	}
+
+	if len(removeTickets) == 0 {
+		return 0, nil
+	}
+
+	err = backend.UpsertGradingTickets(removeTickets)
+	if err != nil {
+		return 0, fmt.Errorf("Failed to remove done grading tickets: '%w'.", err)
+	}
+
+	return len(removeTickets), nil
+}
+
+// Get all the grading tickets (including done tickets) for a user.
+func GetUserGradingTickets(email string) ([]*model.GradingTicket, error) {
+	if backend == nil {
+		return nil, fmt.Errorf("Database has not been opened.")
+	}
+
+	return backend.GetUserGradingTickets(email)
+}
+
+// Upsert a grading ticket.
+// This is also how a ticket's progress (status and result) is recorded.
+func UpsertGradingTicket(ticket *model.GradingTicket) error {
+	tickets := map[string]*model.GradingTicket{
+		ticket.ID: ticket,
+	}
+
+	return UpsertGradingTickets(tickets)
+}
+
+func UpsertGradingTickets(tickets map[string]*model.GradingTicket) error {
+	if backend == nil {
+		return fmt.Errorf("Database has not been opened.")
+	}
+
+	return backend.UpsertGradingTickets(tickets)
+}
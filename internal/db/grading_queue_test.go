@@ -0,0 +1,266 @@
+package db
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/timestamp"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func (this *DBTests) DBTestUpsertGradingTicketsBase(test *testing.T) {
+	ResetForTesting()
+	defer ResetForTesting()
+
+	tickets, err := GetActiveGradingTickets()
+	if err != nil {
+		test.Fatalf("Failed to fetch empty tickets: '%v'.", err)
+	}
+
+	if len(tickets) != 0 {
+		test.Fatalf("Initial ticket fetch is not empty, found %d tickets.", len(tickets))
+	}
+
+	err = UpsertGradingTickets(getTestGradingTickets())
+	if err != nil {
+		test.Fatalf("Failed to upsert initial tickets: '%v'.", err)
+	}
+
+	tickets, err = GetActiveGradingTickets()
+	if err != nil {
+		test.Fatalf("Failed to fetch initial tickets: '%v'.", err)
+	}
+
+	// Done tickets are not active, and active tickets are ordered by queue time then ID.
+	expectedIDs := []string{"b", "a", "c"}
+	actualIDs := getGradingTicketIDs(tickets)
+	if !reflect.DeepEqual(expectedIDs, actualIDs) {
+		test.Fatalf("Unexpected active tickets. Expected: '%v', Actual: '%v'.", expectedIDs, actualIDs)
+	}
+
+	upsertTickets := map[string]*model.GradingTicket{
+		// Remove.
+		"a": nil,
+		// Finish.
+		"b": tickets[0],
+	}
+
+	upsertTickets["b"].Status = model.GradingTicketStatusDone
+	upsertTickets["b"].InputFilesGZip = nil
+	upsertTickets["b"].Result = &model.GradingTicketResult{
+		GradingSuccess: true,
+		SubmissionID:   "1",
+	}
+
+	err = UpsertGradingTickets(upsertTickets)
+	if err != nil {
+		test.Fatalf("Failed to upsert new tickets: '%v'.", err)
+	}
+
+	tickets, err = GetActiveGradingTickets()
+	if err != nil {
+		test.Fatalf("Failed to fetch new tickets: '%v'.", err)
+	}
+
+	expectedIDs = []string{"c"}
+	actualIDs = getGradingTicketIDs(tickets)
+	if !reflect.DeepEqual(expectedIDs, actualIDs) {
+		test.Fatalf("Unexpected new active tickets. Expected: '%v', Actual: '%v'.", expectedIDs, actualIDs)
+	}
+
+	ticket, err := GetGradingTicket("a")
+	if err != nil {
+		test.Fatalf("Failed to fetch removed ticket: '%v'.", err)
+	}
+
+	if ticket != nil {
+		test.Fatalf("Found ticket that should have been removed: '%s'.", util.MustToJSONIndent(ticket))
+	}
+
+	ticket, err = GetGradingTicket("b")
+	if err != nil {
+		test.Fatalf("Failed to fetch finished ticket: '%v'.", err)
+	}
+
+	if !reflect.DeepEqual(upsertTickets["b"], ticket) {
+		test.Fatalf("Finished ticket is not as expected. Expected: '%s', Actual: '%s'.",
+			util.MustToJSONIndent(upsertTickets["b"]), util.MustToJSONIndent(ticket))
+	}
+}
+
+func (this *DBTests) DBTestGetGradingTicketBase(test *testing.T) {
+	ResetForTesting()
+	defer ResetForTesting()
+
+	testTickets := getTestGradingTickets()
+
+	err := UpsertGradingTickets(testTickets)
+	if err != nil {
+		test.Fatalf("Failed to upsert tickets: '%v'.", err)
+	}
+
+	testCases := []struct {
+		ticketID string
+		expected *model.GradingTicket
+		hasError bool
+	}{
+		{"a", testTickets["a"], false},
+		{"d", testTickets["d"], false},
+		{"  A ", testTickets["a"], false},
+		{"zzz", nil, false},
+		{"", nil, true},
+		{"../a", nil, true},
+	}
+
+	for i, testCase := range testCases {
+		ticket, err := GetGradingTicket(testCase.ticketID)
+		if err != nil {
+			if !testCase.hasError {
+				test.Errorf("Case %d: Failed to get ticket: '%v'.", i, err)
+			}
+
+			continue
+		}
+
+		if testCase.hasError {
+			test.Errorf("Case %d: Did not get an expected error.", i)
+			continue
+		}
+
+		if !reflect.DeepEqual(testCase.expected, ticket) {
+			test.Errorf("Case %d: Unexpected ticket. Expected: '%s', Actual: '%s'.",
+				i, util.MustToJSONIndent(testCase.expected), util.MustToJSONIndent(ticket))
+			continue
+		}
+	}
+}
+
+func (this *DBTests) DBTestRemoveDoneGradingTicketsBase(test *testing.T) {
+	ResetForTesting()
+	defer ResetForTesting()
+
+	testTickets := getTestGradingTickets()
+
+	// A done ticket without an end time.
+	testTickets["e"] = &model.GradingTicket{
+		ID:           "e",
+		CourseID:     "course101",
+		AssignmentID: "hw0",
+		User:         "course-student@test.edulinq.org",
+		Status:       model.GradingTicketStatusDone,
+		QueueTime:    timestamp.FromMSecs(500),
+	}
+
+	err := UpsertGradingTickets(testTickets)
+	if err != nil {
+		test.Fatalf("Failed to upsert tickets: '%v'.", err)
+	}
+
+	testCases := []struct {
+		before        int64
+		expectedCount int
+		expectedIDs   []string
+	}{
+		// Nothing finished this early.
+		{70, 0, []string{"a", "b", "c", "d", "e"}},
+		// Only the ticket that ended (not the one that was queued) in time.
+		{100, 1, []string{"a", "b", "c", "e"}},
+		// Unfinished tickets are never removed.
+		{1000, 1, []string{"a", "b", "c"}},
+	}
+
+	for i, testCase := range testCases {
+		count, err := RemoveDoneGradingTickets(timestamp.FromMSecs(testCase.before))
+		if err != nil {
+			test.Errorf("Case %d: Failed to remove done tickets: '%v'.", i, err)
+			continue
+		}
+
+		if testCase.expectedCount != count {
+			test.Errorf("Case %d: Unexpected number of removed tickets. Expected: %d, Actual: %d.", i, testCase.expectedCount, count)
+			continue
+		}
+
+		actualIDs := make([]string, 0)
+		for _, id := range []string{"a", "b", "c", "d", "e"} {
+			ticket, err := GetGradingTicket(id)
+			if err != nil {
+				test.Errorf("Case %d: Failed to get ticket '%s': '%v'.", i, id, err)
+				continue
+			}
+
+			if ticket != nil {
+				actualIDs = append(actualIDs, id)
+			}
+		}
+
+		if !reflect.DeepEqual(testCase.expectedIDs, actualIDs) {
+			test.Errorf("Case %d: Unexpected remaining tickets. Expected: '%v', Actual: '%v'.", i, testCase.expectedIDs, actualIDs)
+			continue
+		}
+	}
+}
+
+func getTestGradingTickets() map[string]*model.GradingTicket {
+	return map[string]*model.GradingTicket{
+		"a": &model.GradingTicket{
+			ID:             "a",
+			CourseID:       "course101",
+			AssignmentID:   "hw0",
+			User:           "course-student@test.edulinq.org",
+			Status:         model.GradingTicketStatusRunning,
+			QueueTime:      timestamp.FromMSecs(200),
+			StartTime:      newGradingTicketTimestamp(300),
+			InputFilesGZip: map[string][]byte{"submission.py": []byte{1, 2, 3}},
+		},
+		"b": &model.GradingTicket{
+			ID:             "b",
+			CourseID:       "course101",
+			AssignmentID:   "hw0",
+			User:           "course-other@test.edulinq.org",
+			Message:        "Some Message",
+			AllowLate:      true,
+			Status:         model.GradingTicketStatusQueued,
+			QueueTime:      timestamp.FromMSecs(100),
+			InputFilesGZip: map[string][]byte{"submission.py": []byte{4, 5, 6}},
+		},
+		"c": &model.GradingTicket{
+			ID:             "c",
+			CourseID:       "course101",
+			AssignmentID:   "hw0",
+			User:           "course-student@test.edulinq.org",
+			Status:         model.GradingTicketStatusQueued,
+			QueueTime:      timestamp.FromMSecs(200),
+			InputFilesGZip: map[string][]byte{"submission.py": []byte{7, 8, 9}},
+		},
+		"d": &model.GradingTicket{
+			ID:           "d",
+			CourseID:     "course101",
+			AssignmentID: "hw0",
+			User:         "course-student@test.edulinq.org",
+			Status:       model.GradingTicketStatusDone,
+			QueueTime:    timestamp.FromMSecs(50),
+			StartTime:    newGradingTicketTimestamp(60),
+			EndTime:      newGradingTicketTimestamp(70),
+			Result: &model.GradingTicketResult{
+				Rejected:     true,
+				RejectReason: "Some Reason",
+			},
+		},
+	}
+}
+
+func getGradingTicketIDs(tickets []*model.GradingTicket) []string {
+	ids := make([]string, 0, len(tickets))
+	for _, ticket := range tickets {
+		ids = append(ids, ticket.ID)
+	}
+
+	return ids
+}
+
+func newGradingTicketTimestamp(msecs int64) *timestamp.Timestamp {
+	value := timestamp.FromMSecs(msecs)
+	return &value
+}
@@ -0,0 +1,137 @@
+package db
+
+import (
+	"fmt"
+
+	"github.com/edulinq/autograder/internal/common"
+	"github.com/edulinq/autograder/internal/model"
+)
+
+func GetAssignmentGroups(assignment *model.Assignment) (map[string]*model.AssignmentGroup, error) {
+	if backend == nil {
+		return nil, fmt.Errorf("Database has not been opened.")
+	}
+
+	return backend.GetAssignmentGroups(assignment)
+}
+
+// Get a specific group for an assignment.
+// Returns (nil, nil) if the group does not exist.
+func GetAssignmentGroup(assignment *model.Assignment, rawGroupID string) (*model.AssignmentGroup, error) {
+	groupID, err := common.ValidateID(rawGroupID)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to validate group id '%s': '%w'.", rawGroupID, err)
+	}
+
+	groups, err := GetAssignmentGroups(assignment)
+	if err != nil {
+		return nil, err
+	}
+
+	return groups[groupID], nil
+}
+
+// Get the group that a user belongs to for an assignment.
+// Returns (nil, nil) if the user is not in a group.
+func GetUserAssignmentGroup(assignment *model.Assignment, email string) (*model.AssignmentGroup, error) {
+	groups, err := GetAssignmentGroups(assignment)
+	if err != nil {
+		return nil, err
+	}
+
+	return findUserGroup(groups, email), nil
+}
+
+// Get all the users that share submissions with the given user for an assignment (including the user).
+// Users that are not in a group (or assignments without groups) will just return the user.
+// Returns: (members, group (may be nil), error).
+func GetAssignmentGroupMembers(assignment *model.Assignment, email string) ([]string, *model.AssignmentGroup, error) {
+	if !assignment.IsGroupAssignment() {
+		return []string{email}, nil, nil
+	}
+
+	group, err := GetUserAssignmentGroup(assignment, email)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if group == nil {
+		return []string{email}, nil, nil
+	}
+
+	return group.Members, group, nil
+}
+
+func UpsertAssignmentGroup(assignment *model.Assignment, group *model.AssignmentGroup) error {
+	groups := map[string]*model.AssignmentGroup{
+		group.ID: group,
+	}
+
+	return UpsertAssignmentGroups(assignment, groups)
+}
+
+func RemoveAssignmentGroup(assignment *model.Assignment, groupID string) error {
+	groups := map[string]*model.AssignmentGroup{
+		groupID: nil,
+	}
+
+	return UpsertAssignmentGroups(assignment, groups)
+}
+
+func UpsertAssignmentGroups(assignment *model.Assignment, groups map[string]*model.AssignmentGroup) error {
+	if backend == nil {
+		return fmt.Errorf("Database has not been opened.")
+	}
+
+	return backend.UpsertAssignmentGroups(assignment, groups)
+}
+
+// Give every member of a group the group's most recent scoring info.
+// Only submissions made by the group (not ones made by a member before joining or in another group) are shared.
+// Only users that already appear in the scoring infos are modified.
+func shareGroupScoringInfos(assignment *model.Assignment, scoringInfos map[string]*model.ScoringInfo) error {
+	groups, err := GetAssignmentGroups(assignment)
+	if err != nil {
+		return fmt.Errorf("Failed to get assignment groups: '%w'.", err)
+	}
+
+	for _, group := range groups {
+		var latest *model.ScoringInfo = nil
+		for _, member := range group.Members {
+			scoringInfo := scoringInfos[member]
+			if (scoringInfo == nil) || (scoringInfo.GroupID != group.ID) {
+				continue
+			}
+
+			if (latest == nil) || (scoringInfo.SubmissionTime > latest.SubmissionTime) {
+				latest = scoringInfo
+			}
+		}
+
+		if latest == nil {
+			continue
+		}
+
+		for _, member := range group.Members {
+			_, ok := scoringInfos[member]
+			if !ok {
+				continue
+			}
+
+			sharedInfo := *latest
+			scoringInfos[member] = &sharedInfo
+		}
+	}
+
+	return nil
+}
+
+func findUserGroup(groups map[string]*model.AssignmentGroup, email string) *model.AssignmentGroup {
+	for _, group := range groups {
+		if group.HasMember(email) {
+			return group
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,274 @@
+package db
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/edulinq/autograder/internal/common"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/timestamp"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func (this *DBTests) DBTestAssignmentGroupsBase(test *testing.T) {
+	ResetForTesting()
+	defer ResetForTesting()
+
+	assignment := MustGetTestAssignment()
+
+	groups, err := GetAssignmentGroups(assignment)
+	if err != nil {
+		test.Fatalf("Failed to fetch empty groups: '%v'.", err)
+	}
+
+	if len(groups) != 0 {
+		test.Fatalf("Initial group fetch is not empty, found %d groups.", len(groups))
+	}
+
+	expected := getTestAssignmentGroups()
+
+	err = UpsertAssignmentGroups(assignment, expected)
+	if err != nil {
+		test.Fatalf("Failed to upsert groups: '%v'.", err)
+	}
+
+	groups, err = GetAssignmentGroups(assignment)
+	if err != nil {
+		test.Fatalf("Failed to fetch groups: '%v'.", err)
+	}
+
+	if !reflect.DeepEqual(expected, groups) {
+		test.Fatalf("Unexpected groups. Expected: '%s', Actual: '%s'.", util.MustToJSONIndent(expected), util.MustToJSONIndent(groups))
+	}
+
+	// Groups from other assignments are not visible.
+	otherGroups, err := GetAssignmentGroups(MustGetTestSubmissionAssignment())
+	if err != nil {
+		test.Fatalf("Failed to fetch other groups: '%v'.", err)
+	}
+
+	if len(otherGroups) != 0 {
+		test.Fatalf("Found groups for another assignment: '%s'.", util.MustToJSONIndent(otherGroups))
+	}
+
+	err = RemoveAssignmentGroup(assignment, "b")
+	if err != nil {
+		test.Fatalf("Failed to remove group: '%v'.", err)
+	}
+
+	groups, err = GetAssignmentGroups(assignment)
+	if err != nil {
+		test.Fatalf("Failed to fetch groups after removal: '%v'.", err)
+	}
+
+	delete(expected, "b")
+
+	if !reflect.DeepEqual(expected, groups) {
+		test.Fatalf("Unexpected groups after removal. Expected: '%s', Actual: '%s'.", util.MustToJSONIndent(expected), util.MustToJSONIndent(groups))
+	}
+}
+
+func (this *DBTests) DBTestGetAssignmentGroupBase(test *testing.T) {
+	ResetForTesting()
+	defer ResetForTesting()
+
+	assignment := MustGetTestAssignment()
+	groups := getTestAssignmentGroups()
+
+	err := UpsertAssignmentGroups(assignment, groups)
+	if err != nil {
+		test.Fatalf("Failed to upsert groups: '%v'.", err)
+	}
+
+	testCases := []struct {
+		groupID  string
+		expected *model.AssignmentGroup
+		hasError bool
+	}{
+		{"a", groups["a"], false},
+		{"b", groups["b"], false},
+		{"zzz", nil, false},
+		{"../a", nil, true},
+	}
+
+	for i, testCase := range testCases {
+		group, err := GetAssignmentGroup(assignment, testCase.groupID)
+		if err != nil {
+			if !testCase.hasError {
+				test.Errorf("Case %d: Failed to get group: '%v'.", i, err)
+			}
+
+			continue
+		}
+
+		if testCase.hasError {
+			test.Errorf("Case %d: Did not get an expected error.", i)
+			continue
+		}
+
+		if !testCase.expected.Equals(group) {
+			test.Errorf("Case %d: Unexpected group. Expected: '%s', Actual: '%s'.",
+				i, util.MustToJSONIndent(testCase.expected), util.MustToJSONIndent(group))
+			continue
+		}
+	}
+}
+
+func (this *DBTests) DBTestGetAssignmentGroupMembersBase(test *testing.T) {
+	ResetForTesting()
+	defer ResetForTesting()
+
+	assignment := MustGetTestAssignment()
+
+	err := UpsertAssignmentGroups(assignment, getTestAssignmentGroups())
+	if err != nil {
+		test.Fatalf("Failed to upsert groups: '%v'.", err)
+	}
+
+	testCases := []struct {
+		isGroupAssignment bool
+		email             string
+		expectedMembers   []string
+		expectedGroupID   string
+	}{
+		{true, "course-student@test.edulinq.org", []string{"course-student@test.edulinq.org", "course-other@test.edulinq.org"}, "a"},
+		{true, "course-other@test.edulinq.org", []string{"course-student@test.edulinq.org", "course-other@test.edulinq.org"}, "a"},
+		{true, "course-grader@test.edulinq.org", []string{"course-grader@test.edulinq.org"}, "b"},
+		{true, "course-admin@test.edulinq.org", []string{"course-admin@test.edulinq.org"}, ""},
+
+		// Groups are ignored for assignments that do not use them.
+		{false, "course-student@test.edulinq.org", []string{"course-student@test.edulinq.org"}, ""},
+	}
+
+	for i, testCase := range testCases {
+		assignment.GroupOptions = nil
+		if testCase.isGroupAssignment {
+			assignment.GroupOptions = &model.GroupOptions{MaxSize: 2}
+		}
+
+		members, group, err := GetAssignmentGroupMembers(assignment, testCase.email)
+		if err != nil {
+			test.Errorf("Case %d: Failed to get group members: '%v'.", i, err)
+			continue
+		}
+
+		if !reflect.DeepEqual(testCase.expectedMembers, members) {
+			test.Errorf("Case %d: Unexpected members. Expected: '%v', Actual: '%v'.", i, testCase.expectedMembers, members)
+			continue
+		}
+
+		groupID := ""
+		if group != nil {
+			groupID = group.ID
+		}
+
+		if testCase.expectedGroupID != groupID {
+			test.Errorf("Case %d: Unexpected group. Expected: '%s', Actual: '%s'.", i, testCase.expectedGroupID, groupID)
+			continue
+		}
+	}
+}
+
+// All members of a group should get the group's most recent score.
+func (this *DBTests) DBTestGetScoringInfosGroup(test *testing.T) {
+	ResetForTesting()
+	defer ResetForTesting()
+
+	assignment := MustGetTestAssignment()
+	reference, err := model.ParseCourseUserReferences(model.NewAllCourseUserReference())
+	if err != nil {
+		test.Fatalf("Failed to parse course user reference: '%v'.", err)
+	}
+
+	individualInfos, err := GetScoringInfos(assignment, reference)
+	if err != nil {
+		test.Fatalf("Failed to get individual scoring infos: '%v'.", err)
+	}
+
+	if individualInfos["course-student@test.edulinq.org"] == nil {
+		test.Fatalf("Could not find test student's scoring info.")
+	}
+
+	if individualInfos["course-other@test.edulinq.org"] != nil {
+		test.Fatalf("Found unexpected scoring info for a user without submissions.")
+	}
+
+	err = UpsertAssignmentGroups(assignment, getTestAssignmentGroups())
+	if err != nil {
+		test.Fatalf("Failed to upsert groups: '%v'.", err)
+	}
+
+	assignment.GroupOptions = &model.GroupOptions{MaxSize: 2}
+
+	// Submissions made before joining the group are not shared.
+	groupInfos, err := GetScoringInfos(assignment, reference)
+	if err != nil {
+		test.Fatalf("Failed to get group scoring infos (before group submission): '%v'.", err)
+	}
+
+	if !reflect.DeepEqual(individualInfos, groupInfos) {
+		test.Fatalf("Non-group submissions were shared. Expected: '%s', Actual: '%s'.",
+			util.MustToJSONIndent(individualInfos), util.MustToJSONIndent(groupInfos))
+	}
+
+	// Make a group submission.
+	submission, err := GetSubmissionContents(assignment, "course-student@test.edulinq.org", "")
+	if err != nil {
+		test.Fatalf("Failed to get test student's submission: '%v'.", err)
+	}
+
+	submission.Info.ShortID = "group"
+	submission.Info.ID = common.CreateFullSubmissionID(TEST_COURSE_ID, TEST_ASSIGNMENT_ID, "course-student@test.edulinq.org", "group")
+	submission.Info.GroupID = "a"
+	submission.Info.GradingStartTime = submission.Info.GradingStartTime + 1000
+	submission.Info.GradingEndTime = submission.Info.GradingEndTime + 1000
+
+	err = SaveSubmission(assignment, submission)
+	if err != nil {
+		test.Fatalf("Failed to save group submission: '%v'.", err)
+	}
+
+	groupInfos, err = GetScoringInfos(assignment, reference)
+	if err != nil {
+		test.Fatalf("Failed to get group scoring infos: '%v'.", err)
+	}
+
+	if len(individualInfos) != len(groupInfos) {
+		test.Fatalf("Unexpected number of scoring infos. Expected: %d, Actual: %d.", len(individualInfos), len(groupInfos))
+	}
+
+	expected := submission.Info.ToScoringInfo()
+
+	for _, email := range []string{"course-student@test.edulinq.org", "course-other@test.edulinq.org"} {
+		if !reflect.DeepEqual(expected, groupInfos[email]) {
+			test.Errorf("User '%s' does not have the group's scoring info. Expected: '%s', Actual: '%s'.",
+				email, util.MustToJSONIndent(expected), util.MustToJSONIndent(groupInfos[email]))
+		}
+	}
+
+	// A group without any submissions.
+	if groupInfos["course-grader@test.edulinq.org"] != nil {
+		test.Errorf("Found unexpected scoring info for a group without submissions.")
+	}
+}
+
+func getTestAssignmentGroups() map[string]*model.AssignmentGroup {
+	return map[string]*model.AssignmentGroup{
+		"a": &model.AssignmentGroup{
+			ID:           "a",
+			CourseID:     TEST_COURSE_ID,
+			AssignmentID: TEST_ASSIGNMENT_ID,
+			Members:      []string{"course-student@test.edulinq.org", "course-other@test.edulinq.org"},
+			CreatedBy:    "course-student@test.edulinq.org",
+			CreationTime: timestamp.FromMSecs(100),
+		},
+		"b": &model.AssignmentGroup{
+			ID:           "b",
+			CourseID:     TEST_COURSE_ID,
+			AssignmentID: TEST_ASSIGNMENT_ID,
+			Members:      []string{"course-grader@test.edulinq.org"},
+			CreatedBy:    "course-admin@test.edulinq.org",
+			CreationTime: timestamp.FromMSecs(200),
+		},
+	}
+}
@@ -30,6 +30,15 @@ func GetLogRecordsFull(query log.ParsedLogQuery, useTestingData bool) ([]*log.Re
 	return records, nil
 }
 
+// Remove all the log records attributed to a user.
+func RemoveUserLogRecords(email string) error {
+	if backend == nil {
+		return fmt.Errorf("Database has not been opened.")
+	}
+
+	return backend.RemoveUserLogRecords(email)
+}
+
 var TESTING_LOG_RECORDS []*log.Record = []*log.Record{
 	&log.Record{
 		Level:      log.LevelTrace,
@@ -0,0 +1,73 @@
+package db
+
+import (
+	"fmt"
+
+	"github.com/edulinq/autograder/internal/model"
+)
+
+// Get the login throttles for the given keys (see model.LoginThrottleKeyFor*()).
+// Keys without a throttle will not be represented in the output.
+func GetLoginThrottles(keys []string) (map[string]*model.LoginThrottle, error) {
+	if backend == nil {
+		return nil, fmt.Errorf("Database has not been opened.")
+	}
+
+	if len(keys) == 0 {
+		return make(map[string]*model.LoginThrottle), nil
+	}
+
+	return backend.GetLoginThrottles(keys)
+}
+
+// Get a single login throttle.
+// Returns (nil, nil) if the throttle does not exist.
+func GetLoginThrottle(key string) (*model.LoginThrottle, error) {
+	throttles, err := GetLoginThrottles([]string{key})
+	if err != nil {
+		return nil, err
+	}
+
+	return throttles[key], nil
+}
+
+func UpsertLoginThrottle(throttle *model.LoginThrottle) error {
+	throttles := map[string]*model.LoginThrottle{
+		throttle.Key: throttle,
+	}
+
+	return UpsertLoginThrottles(throttles)
+}
+
+// Remove the throttles for the given keys.
+func RemoveLoginThrottles(keys []string) error {
+	throttles := make(map[string]*model.LoginThrottle, len(keys))
+	for _, key := range keys {
+		throttles[key] = nil
+	}
+
+	return UpsertLoginThrottles(throttles)
+}
+
+func UpsertLoginThrottles(throttles map[string]*model.LoginThrottle) error {
+	if backend == nil {
+		return fmt.Errorf("Database has not been opened.")
+	}
+
+	if len(throttles) == 0 {
+		return nil
+	}
+
+	for key, throttle := range throttles {
+		if throttle == nil {
+			continue
+		}
+
+		err := throttle.Validate()
+		if err != nil {
+			return fmt.Errorf("Failed to validate login throttle '%s': '%w'.", key, err)
+		}
+	}
+
+	return backend.UpsertLoginThrottles(throttles)
+}
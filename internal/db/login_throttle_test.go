@@ -0,0 +1,79 @@
+package db
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/timestamp"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func (this *DBTests) DBTestUpsertLoginThrottlesBase(test *testing.T) {
+	ResetForTesting()
+	defer ResetForTesting()
+
+	emailKey := model.LoginThrottleKeyForEmail("course-student@test.edulinq.org")
+	ipKey := model.LoginThrottleKeyForIP("192.0.2.1")
+	keys := []string{emailKey, ipKey}
+
+	throttles, err := GetLoginThrottles(keys)
+	if err != nil {
+		test.Fatalf("Failed to fetch empty throttles: '%v'.", err)
+	}
+
+	if len(throttles) != 0 {
+		test.Fatalf("Initial throttle fetch is not empty, found %d throttles.", len(throttles))
+	}
+
+	expected := map[string]*model.LoginThrottle{
+		emailKey: &model.LoginThrottle{
+			Key:         emailKey,
+			Failures:    5,
+			LastFailure: timestamp.FromMSecs(100),
+			LockedUntil: timestamp.FromMSecs(200),
+		},
+		ipKey: &model.LoginThrottle{
+			Key:         ipKey,
+			Failures:    1,
+			LastFailure: timestamp.FromMSecs(300),
+		},
+	}
+
+	err = UpsertLoginThrottles(expected)
+	if err != nil {
+		test.Fatalf("Failed to upsert throttles: '%v'.", err)
+	}
+
+	throttles, err = GetLoginThrottles(append(keys, model.LoginThrottleKeyForIP("ZZZ")))
+	if err != nil {
+		test.Fatalf("Failed to fetch throttles: '%v'.", err)
+	}
+
+	if !reflect.DeepEqual(expected, throttles) {
+		test.Fatalf("Unexpected throttles. Expected: '%s', Actual: '%s'.", util.MustToJSONIndent(expected), util.MustToJSONIndent(throttles))
+	}
+
+	err = RemoveLoginThrottles([]string{emailKey})
+	if err != nil {
+		test.Fatalf("Failed to remove throttle: '%v'.", err)
+	}
+
+	throttle, err := GetLoginThrottle(emailKey)
+	if err != nil {
+		test.Fatalf("Failed to fetch removed throttle: '%v'.", err)
+	}
+
+	if throttle != nil {
+		test.Fatalf("Removed throttle still exists: '%s'.", util.MustToJSONIndent(throttle))
+	}
+
+	throttle, err = GetLoginThrottle(ipKey)
+	if err != nil {
+		test.Fatalf("Failed to fetch remaining throttle: '%v'.", err)
+	}
+
+	if !reflect.DeepEqual(expected[ipKey], throttle) {
+		test.Fatalf("Unexpected remaining throttle. Expected: '%s', Actual: '%s'.", util.MustToJSONIndent(expected[ipKey]), util.MustToJSONIndent(throttle))
+	}
+}
@@ -0,0 +1,69 @@
+package db
+
+import (
+	"fmt"
+
+	"github.com/edulinq/autograder/internal/model"
+)
+
+// Manual grades (rubric grades and regrade adjustments) are stored separately from submissions,
+// and are merged into submission results when they are fetched.
+type manualGrades struct {
+	// Keyed by full submission ID.
+	rubricGrades map[string]*model.RubricGrade
+	adjustments  map[string][]*model.RegradeRequest
+}
+
+func getManualGrades(assignment *model.Assignment) (*manualGrades, error) {
+	grades := &manualGrades{}
+
+	var err error
+
+	if assignment.Rubric != nil {
+		grades.rubricGrades, err = GetRubricGrades(assignment)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to get rubric grades: '%w'.", err)
+		}
+	}
+
+	grades.adjustments, err = getRegradeAdjustments(assignment)
+	if err != nil {
+		return nil, err
+	}
+
+	return grades, nil
+}
+
+// An assignment has no manual grades if it has no rubric and no regrade adjustments.
+// Results for these assignments can be used as-is.
+func (this *manualGrades) isEmpty() bool {
+	return (this.rubricGrades == nil) && (len(this.adjustments) == 0)
+}
+
+// Rubric grades are applied first, so regrade requests can adjust rubric criteria.
+func (this *manualGrades) apply(assignment *model.Assignment, gradingInfo *model.GradingInfo) {
+	if gradingInfo == nil {
+		return
+	}
+
+	gradingInfo.ApplyRubricGrade(assignment.Rubric, this.rubricGrades[gradingInfo.ID])
+	gradingInfo.ApplyRegradeAdjustments(this.adjustments[gradingInfo.ID])
+}
+
+func (this *manualGrades) applyToResult(assignment *model.Assignment, gradingResult *model.GradingResult) {
+	if gradingResult == nil {
+		return
+	}
+
+	this.apply(assignment, gradingResult.Info)
+}
+
+// Only rubric grades can be applied to history items (since they do not have questions),
+// items with regrade adjustments need to be made from a full result (see apply()).
+func (this *manualGrades) applyToHistoryItem(assignment *model.Assignment, item *model.SubmissionHistoryItem) {
+	if item == nil {
+		return
+	}
+
+	item.ApplyRubricGrade(assignment.Rubric, this.rubricGrades[item.ID])
+}
@@ -0,0 +1,103 @@
+package pg
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/edulinq/autograder/internal/log"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func (this *backend) GetIndividualAnalysis(fullSubmissionIDs []string) (map[string]*model.IndividualAnalysis, error) {
+	records, err := this.getIndividualAnalysis(`WHERE full_id = ANY($1)`, fullSubmissionIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make(map[string]*model.IndividualAnalysis, len(records))
+	for _, record := range records {
+		results[record.FullID] = record
+	}
+
+	return results, nil
+}
+
+func (this *backend) GetCourseIndividualAnalysis(courseID string) ([]*model.IndividualAnalysis, error) {
+	records, err := this.getIndividualAnalysis(`WHERE course_id = $1`, courseID)
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(records, func(a *model.IndividualAnalysis, b *model.IndividualAnalysis) int {
+		return strings.Compare(a.FullID, b.FullID)
+	})
+
+	return records, nil
+}
+
+func (this *backend) StoreIndividualAnalysis(allRecords []*model.IndividualAnalysis) error {
+	return this.withTransaction(func(tx pgx.Tx) error {
+		for _, record := range allRecords {
+			if record.CourseID == "" {
+				// This would be a bit strange, just log and skip it.
+				log.Warn("Found empty course ID in individual analysis.", log.NewAttr("record", record))
+				continue
+			}
+
+			data, err := util.ToJSON(record)
+			if err != nil {
+				return fmt.Errorf("Failed to serialize individual analysis for '%s': '%w'.", record.FullID, err)
+			}
+
+			_, err = tx.Exec(context.Background(),
+				`INSERT INTO analysis_individual (full_id, course_id, data) VALUES ($1, $2, $3)
+					ON CONFLICT (full_id) DO UPDATE SET course_id = EXCLUDED.course_id, data = EXCLUDED.data`,
+				record.FullID, record.CourseID, data)
+			if err != nil {
+				return fmt.Errorf("Failed to store individual analysis for '%s': '%w'.", record.FullID, err)
+			}
+		}
+
+		return nil
+	})
+}
+
+func (this *backend) RemoveIndividualAnalysis(fullSubmissionIDs []string) error {
+	_, err := this.pool.Exec(context.Background(), `DELETE FROM analysis_individual WHERE full_id = ANY($1)`, fullSubmissionIDs)
+	if err != nil {
+		return fmt.Errorf("Failed to remove individual analysis: '%w'.", err)
+	}
+
+	return nil
+}
+
+func (this *backend) getIndividualAnalysis(whereClause string, args ...any) ([]*model.IndividualAnalysis, error) {
+	rows, err := this.pool.Query(context.Background(), `SELECT data FROM analysis_individual `+whereClause, args...)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to query individual analysis: '%w'.", err)
+	}
+
+	records := make([]*model.IndividualAnalysis, 0)
+
+	var data string
+	_, err = pgx.ForEachRow(rows, []any{&data}, func() error {
+		var record model.IndividualAnalysis
+		err := util.JSONFromString(data, &record)
+		if err != nil {
+			return fmt.Errorf("Failed to deserialize individual analysis: '%w'.", err)
+		}
+
+		records = append(records, &record)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read individual analysis: '%w'.", err)
+	}
+
+	return records, nil
+}
@@ -0,0 +1,112 @@
+package pg
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/edulinq/autograder/internal/log"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func (this *backend) GetPairwiseAnalysis(keys []model.PairwiseKey) (map[model.PairwiseKey]*model.PairwiseAnalysis, error) {
+	records, err := this.getPairwiseAnalysis(`WHERE key = ANY($1)`, pairwiseKeyStrings(keys))
+	if err != nil {
+		return nil, err
+	}
+
+	results := make(map[model.PairwiseKey]*model.PairwiseAnalysis, len(records))
+	for _, record := range records {
+		results[record.SubmissionIDs] = record
+	}
+
+	return results, nil
+}
+
+func (this *backend) GetCoursePairwiseAnalysis(courseID string) ([]*model.PairwiseAnalysis, error) {
+	records, err := this.getPairwiseAnalysis(`WHERE course_id = $1`, courseID)
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(records, func(a *model.PairwiseAnalysis, b *model.PairwiseAnalysis) int {
+		return model.ComparePairwiseKey(a.SubmissionIDs, b.SubmissionIDs)
+	})
+
+	return records, nil
+}
+
+func (this *backend) StorePairwiseAnalysis(allRecords []*model.PairwiseAnalysis) error {
+	return this.withTransaction(func(tx pgx.Tx) error {
+		for _, record := range allRecords {
+			courseID := record.SubmissionIDs.Course()
+			if courseID == "" {
+				// This would be a bit strange, just log and skip it.
+				log.Warn("Found empty course ID in pairwise analysis.", log.NewAttr("record", record))
+				continue
+			}
+
+			data, err := util.ToJSON(record)
+			if err != nil {
+				return fmt.Errorf("Failed to serialize pairwise analysis for '%s': '%w'.", record.SubmissionIDs.String(), err)
+			}
+
+			_, err = tx.Exec(context.Background(),
+				`INSERT INTO analysis_pairwise (key, course_id, data) VALUES ($1, $2, $3)
+					ON CONFLICT (key) DO UPDATE SET course_id = EXCLUDED.course_id, data = EXCLUDED.data`,
+				record.SubmissionIDs.String(), courseID, data)
+			if err != nil {
+				return fmt.Errorf("Failed to store pairwise analysis for '%s': '%w'.", record.SubmissionIDs.String(), err)
+			}
+		}
+
+		return nil
+	})
+}
+
+func (this *backend) RemovePairwiseAnalysis(keys []model.PairwiseKey) error {
+	_, err := this.pool.Exec(context.Background(), `DELETE FROM analysis_pairwise WHERE key = ANY($1)`, pairwiseKeyStrings(keys))
+	if err != nil {
+		return fmt.Errorf("Failed to remove pairwise analysis: '%w'.", err)
+	}
+
+	return nil
+}
+
+func (this *backend) getPairwiseAnalysis(whereClause string, args ...any) ([]*model.PairwiseAnalysis, error) {
+	rows, err := this.pool.Query(context.Background(), `SELECT data FROM analysis_pairwise `+whereClause, args...)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to query pairwise analysis: '%w'.", err)
+	}
+
+	records := make([]*model.PairwiseAnalysis, 0)
+
+	var data string
+	_, err = pgx.ForEachRow(rows, []any{&data}, func() error {
+		var record model.PairwiseAnalysis
+		err := util.JSONFromString(data, &record)
+		if err != nil {
+			return fmt.Errorf("Failed to deserialize pairwise analysis: '%w'.", err)
+		}
+
+		records = append(records, &record)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read pairwise analysis: '%w'.", err)
+	}
+
+	return records, nil
+}
+
+func pairwiseKeyStrings(keys []model.PairwiseKey) []string {
+	keyStrings := make([]string, 0, len(keys))
+	for _, key := range keys {
+		keyStrings = append(keyStrings, key.String())
+	}
+
+	return keyStrings
+}
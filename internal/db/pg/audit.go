@@ -0,0 +1,81 @@
+package pg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func (this *backend) AppendAuditRecord(record *model.AuditRecord) error {
+	data, err := util.ToJSON(record)
+	if err != nil {
+		return fmt.Errorf("Failed to serialize audit record: '%w'.", err)
+	}
+
+	_, err = this.pool.Exec(context.Background(),
+		`INSERT INTO audit_records (record_index, timestamp, course_id, data) VALUES ($1, $2, $3, $4)`,
+		record.Index, int64(record.Timestamp), record.CourseID, data)
+	if err != nil {
+		return fmt.Errorf("Failed to store audit record %d: '%w'.", record.Index, err)
+	}
+
+	return nil
+}
+
+func (this *backend) GetLastAuditRecord() (*model.AuditRecord, error) {
+	var data string
+	err := this.pool.QueryRow(context.Background(),
+		`SELECT data FROM audit_records ORDER BY record_index DESC LIMIT 1`).Scan(&data)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get last audit record: '%w'.", err)
+	}
+
+	var record model.AuditRecord
+	err = util.JSONFromString(data, &record)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to deserialize audit record: '%w'.", err)
+	}
+
+	return &record, nil
+}
+
+func (this *backend) GetAuditRecords(query model.AuditQuery) ([]*model.AuditRecord, error) {
+	// Do a coarse filter in the database, and then use the query's own matching for the exact semantics.
+	rows, err := this.pool.Query(context.Background(),
+		`SELECT data FROM audit_records WHERE timestamp >= $1 ORDER BY record_index`,
+		int64(query.After))
+	if err != nil {
+		return nil, fmt.Errorf("Failed to query audit records: '%w'.", err)
+	}
+
+	records := make([]*model.AuditRecord, 0)
+
+	var data string
+	_, err = pgx.ForEachRow(rows, []any{&data}, func() error {
+		var record model.AuditRecord
+		err := util.JSONFromString(data, &record)
+		if err != nil {
+			return fmt.Errorf("Failed to deserialize audit record: '%w'.", err)
+		}
+
+		if query.Match(&record) {
+			records = append(records, &record)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read audit records: '%w'.", err)
+	}
+
+	return records, nil
+}
@@ -0,0 +1,176 @@
+package pg
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+const DUMP_ASSIGNMENTS_DIRNAME = "assignments"
+
+func (this *backend) ClearCourse(course *model.Course) error {
+	return this.withTransaction(func(tx pgx.Tx) error {
+		statements := []string{
+			`DELETE FROM courses WHERE id = $1`,
+			`DELETE FROM submissions WHERE course_id = $1`,
+			`DELETE FROM assignment_groups WHERE course_id = $1`,
+			`DELETE FROM assignment_extensions WHERE course_id = $1`,
+			`DELETE FROM rubric_grades WHERE course_id = $1`,
+			`DELETE FROM regrade_requests WHERE course_id = $1`,
+			`DELETE FROM analysis_individual WHERE course_id = $1`,
+			`DELETE FROM analysis_pairwise WHERE course_id = $1`,
+			`UPDATE users SET data = jsonb_set(data, '{course-info}', (data -> 'course-info') - $1::TEXT) WHERE (data -> 'course-info') ? $1::TEXT`,
+		}
+
+		for _, statement := range statements {
+			_, err := tx.Exec(context.Background(), statement, course.GetID())
+			if err != nil {
+				return fmt.Errorf("Failed to clear course '%s': '%w'.", course.GetID(), err)
+			}
+		}
+
+		return nil
+	})
+}
+
+func (this *backend) AddTestCourse(path string) (*model.Course, error) {
+	course, submissions, err := model.FullLoadCourseFromPath(util.ShouldAbs(path), true)
+	if err != nil {
+		return nil, err
+	}
+
+	err = this.withTransaction(func(tx pgx.Tx) error {
+		err := saveCourse(tx, course)
+		if err != nil {
+			return err
+		}
+
+		return saveSubmissions(tx, submissions)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return course, nil
+}
+
+func (this *backend) SaveCourse(course *model.Course) error {
+	return this.withTransaction(func(tx pgx.Tx) error {
+		return saveCourse(tx, course)
+	})
+}
+
+func saveCourse(tx pgx.Tx, course *model.Course) error {
+	data, err := util.ToJSON(course)
+	if err != nil {
+		return fmt.Errorf("Failed to serialize course '%s': '%w'.", course.GetID(), err)
+	}
+
+	_, err = tx.Exec(context.Background(),
+		`INSERT INTO courses (id, data) VALUES ($1, $2) ON CONFLICT (id) DO UPDATE SET data = EXCLUDED.data`,
+		course.GetID(), data)
+	if err != nil {
+		return fmt.Errorf("Failed to save course '%s': '%w'.", course.GetID(), err)
+	}
+
+	for _, assignment := range course.Assignments {
+		err = saveAssignment(tx, assignment)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (this *backend) DumpCourse(course *model.Course, targetDir string) error {
+	err := util.ToJSONFileIndent(course, filepath.Join(targetDir, model.COURSE_CONFIG_FILENAME))
+	if err != nil {
+		return fmt.Errorf("Failed to dump course config for '%s': '%w'.", course.GetID(), err)
+	}
+
+	for _, assignment := range course.Assignments {
+		path := filepath.Join(targetDir, DUMP_ASSIGNMENTS_DIRNAME, assignment.GetID(), model.ASSIGNMENT_CONFIG_FILENAME)
+
+		err = util.MkDir(filepath.Dir(path))
+		if err != nil {
+			return fmt.Errorf("Failed to make assignment dump dir '%s': '%w'.", filepath.Dir(path), err)
+		}
+
+		err = util.ToJSONFileIndent(assignment, path)
+		if err != nil {
+			return fmt.Errorf("Failed to dump assignment config for '%s': '%w'.", assignment.FullID(), err)
+		}
+
+		submissions, err := this.getSubmissions(`WHERE course_id = $1 AND assignment_id = $2`, true, course.GetID(), assignment.GetID())
+		if err != nil {
+			return fmt.Errorf("Failed to get submissions to dump for '%s': '%w'.", assignment.FullID(), err)
+		}
+
+		for _, submission := range submissions {
+			submissionDir := filepath.Join(targetDir, model.SUBMISSIONS_DIRNAME, assignment.GetID(), submission.Info.User, submission.Info.ShortID)
+
+			err = model.WriteGradingResult(submission, submissionDir)
+			if err != nil {
+				return fmt.Errorf("Failed to dump submission '%s': '%w'.", submission.Info.ID, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (this *backend) GetCourse(courseID string) (*model.Course, error) {
+	courses, err := this.getCourses(`WHERE id = $1`, courseID)
+	if err != nil {
+		return nil, err
+	}
+
+	return courses[courseID], nil
+}
+
+func (this *backend) GetCourses() (map[string]*model.Course, error) {
+	return this.getCourses("")
+}
+
+func (this *backend) getCourses(whereClause string, args ...any) (map[string]*model.Course, error) {
+	courses := make(map[string]*model.Course)
+
+	courseJSONs := make(map[string]string)
+
+	rows, err := this.pool.Query(context.Background(), `SELECT id, data FROM courses `+whereClause, args...)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to query courses: '%w'.", err)
+	}
+
+	var id string
+	var data string
+	_, err = pgx.ForEachRow(rows, []any{&id, &data}, func() error {
+		courseJSONs[id] = data
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read courses: '%w'.", err)
+	}
+
+	for courseID, courseJSON := range courseJSONs {
+		assignmentJSONs, err := this.getAssignmentJSONs(courseID)
+		if err != nil {
+			return nil, err
+		}
+
+		course, err := model.LoadCourseFromJSON(courseJSON, assignmentJSONs)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to load course '%s': '%w'.", courseID, err)
+		}
+
+		courses[course.GetID()] = course
+	}
+
+	return courses, nil
+}
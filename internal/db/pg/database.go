@@ -1,12 +1,18 @@
+// A database backend that stores all data in a Postgres database.
+// Meant for larger deployments where the disk database cannot keep up.
+// Transactions (instead of in-process locks) are used to keep operations consistent.
 package pg
 
 import (
 	"context"
 	"fmt"
+	"strings"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 
 	"github.com/edulinq/autograder/internal/config"
+	"github.com/edulinq/autograder/internal/log"
 )
 
 type backend struct {
@@ -24,6 +30,14 @@ func Open() (*backend, error) {
 		return nil, fmt.Errorf("Failed to open connection pool to Postgres database at '%s': %w.", uri, err)
 	}
 
+	err = pool.Ping(context.Background())
+	if err != nil {
+		pool.Close()
+		return nil, fmt.Errorf("Failed to connect to Postgres database at '%s': %w.", uri, err)
+	}
+
+	log.Debug("Opened Postgres database.")
+
 	return &backend{pool}, nil
 }
 
@@ -31,3 +45,33 @@ func (this *backend) Close() error {
 	this.pool.Close()
 	return nil
 }
+
+func (this *backend) EnsureTables() error {
+	return this.withTransaction(func(tx pgx.Tx) error {
+		for i, statement := range SCHEMA_STATEMENTS {
+			_, err := tx.Exec(context.Background(), statement)
+			if err != nil {
+				return fmt.Errorf("Failed to execute schema statement %d: '%w'.", i, err)
+			}
+		}
+
+		return nil
+	})
+}
+
+func (this *backend) Clear() error {
+	statement := fmt.Sprintf("TRUNCATE %s RESTART IDENTITY CASCADE", strings.Join(TABLE_NAMES, ", "))
+
+	_, err := this.pool.Exec(context.Background(), statement)
+	if err != nil {
+		return fmt.Errorf("Failed to truncate tables: '%w'.", err)
+	}
+
+	return nil
+}
+
+// Run the given function inside a transaction.
+// The transaction will be committed if the function returns nil and rolled back otherwise.
+func (this *backend) withTransaction(function func(tx pgx.Tx) error) error {
+	return pgx.BeginFunc(context.Background(), this.pool, function)
+}
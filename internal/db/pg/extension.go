@@ -0,0 +1,76 @@
+package pg
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func (this *backend) GetAssignmentExtensions(assignment *model.Assignment) (map[string]*model.AssignmentExtension, error) {
+	rows, err := this.pool.Query(context.Background(),
+		`SELECT email, data FROM assignment_extensions WHERE course_id = $1 AND assignment_id = $2`,
+		assignment.GetCourse().GetID(), assignment.GetID())
+	if err != nil {
+		return nil, fmt.Errorf("Failed to query assignment extensions: '%w'.", err)
+	}
+
+	extensions := make(map[string]*model.AssignmentExtension)
+
+	var email string
+	var data string
+
+	_, err = pgx.ForEachRow(rows, []any{&email, &data}, func() error {
+		var extension model.AssignmentExtension
+		err := util.JSONFromString(data, &extension)
+		if err != nil {
+			return fmt.Errorf("Failed to deserialize assignment extension '%s': '%w'.", email, err)
+		}
+
+		extensions[email] = &extension
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read assignment extensions: '%w'.", err)
+	}
+
+	return extensions, nil
+}
+
+func (this *backend) UpsertAssignmentExtensions(assignment *model.Assignment, upsertExtensions map[string]*model.AssignmentExtension) error {
+	courseID := assignment.GetCourse().GetID()
+	assignmentID := assignment.GetID()
+
+	return this.withTransaction(func(tx pgx.Tx) error {
+		for email, upsertExtension := range upsertExtensions {
+			if upsertExtension == nil {
+				_, err := tx.Exec(context.Background(),
+					`DELETE FROM assignment_extensions WHERE course_id = $1 AND assignment_id = $2 AND email = $3`,
+					courseID, assignmentID, email)
+				if err != nil {
+					return fmt.Errorf("Failed to remove assignment extension '%s': '%w'.", email, err)
+				}
+
+				continue
+			}
+
+			data, err := util.ToJSON(upsertExtension)
+			if err != nil {
+				return fmt.Errorf("Failed to serialize assignment extension '%s': '%w'.", email, err)
+			}
+
+			_, err = tx.Exec(context.Background(),
+				`INSERT INTO assignment_extensions (course_id, assignment_id, email, data) VALUES ($1, $2, $3, $4)
+					ON CONFLICT (course_id, assignment_id, email) DO UPDATE SET data = EXCLUDED.data`,
+				courseID, assignmentID, email, data)
+			if err != nil {
+				return fmt.Errorf("Failed to upsert assignment extension '%s': '%w'.", email, err)
+			}
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,76 @@
+package pg
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func (this *backend) GetAssignmentGroups(assignment *model.Assignment) (map[string]*model.AssignmentGroup, error) {
+	rows, err := this.pool.Query(context.Background(),
+		`SELECT id, data FROM assignment_groups WHERE course_id = $1 AND assignment_id = $2`,
+		assignment.GetCourse().GetID(), assignment.GetID())
+	if err != nil {
+		return nil, fmt.Errorf("Failed to query assignment groups: '%w'.", err)
+	}
+
+	groups := make(map[string]*model.AssignmentGroup)
+
+	var id string
+	var data string
+
+	_, err = pgx.ForEachRow(rows, []any{&id, &data}, func() error {
+		var group model.AssignmentGroup
+		err := util.JSONFromString(data, &group)
+		if err != nil {
+			return fmt.Errorf("Failed to deserialize assignment group '%s': '%w'.", id, err)
+		}
+
+		groups[id] = &group
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read assignment groups: '%w'.", err)
+	}
+
+	return groups, nil
+}
+
+func (this *backend) UpsertAssignmentGroups(assignment *model.Assignment, upsertGroups map[string]*model.AssignmentGroup) error {
+	courseID := assignment.GetCourse().GetID()
+	assignmentID := assignment.GetID()
+
+	return this.withTransaction(func(tx pgx.Tx) error {
+		for groupID, upsertGroup := range upsertGroups {
+			if upsertGroup == nil {
+				_, err := tx.Exec(context.Background(),
+					`DELETE FROM assignment_groups WHERE course_id = $1 AND assignment_id = $2 AND id = $3`,
+					courseID, assignmentID, groupID)
+				if err != nil {
+					return fmt.Errorf("Failed to remove assignment group '%s': '%w'.", groupID, err)
+				}
+
+				continue
+			}
+
+			data, err := util.ToJSON(upsertGroup)
+			if err != nil {
+				return fmt.Errorf("Failed to serialize assignment group '%s': '%w'.", groupID, err)
+			}
+
+			_, err = tx.Exec(context.Background(),
+				`INSERT INTO assignment_groups (course_id, assignment_id, id, data) VALUES ($1, $2, $3, $4)
+					ON CONFLICT (course_id, assignment_id, id) DO UPDATE SET data = EXCLUDED.data`,
+				courseID, assignmentID, groupID, data)
+			if err != nil {
+				return fmt.Errorf("Failed to upsert assignment group '%s': '%w'.", groupID, err)
+			}
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,68 @@
+package pg
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/edulinq/autograder/internal/log"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func (this *backend) LogDirect(record *log.Record) error {
+	data, err := util.ToJSON(record)
+	if err != nil {
+		return fmt.Errorf("Failed to serialize log record: '%w'.", err)
+	}
+
+	_, err = this.pool.Exec(context.Background(),
+		`INSERT INTO logs (level, timestamp, course_id, assignment_id, user_email, data) VALUES ($1, $2, $3, $4, $5, $6)`,
+		int32(record.Level), int64(record.Timestamp), record.Course, record.Assignment, record.User, data)
+	if err != nil {
+		return fmt.Errorf("Failed to store log record: '%w'.", err)
+	}
+
+	return nil
+}
+
+func (this *backend) GetLogRecords(query log.ParsedLogQuery) ([]*log.Record, error) {
+	// Do a coarse filter in the database, and then use the query's own matching for the exact semantics.
+	rows, err := this.pool.Query(context.Background(),
+		`SELECT data FROM logs WHERE level >= $1 AND timestamp >= $2 ORDER BY id`,
+		int32(query.Level), int64(query.After))
+	if err != nil {
+		return nil, fmt.Errorf("Failed to query log records: '%w'.", err)
+	}
+
+	records := make([]*log.Record, 0)
+
+	var data string
+	_, err = pgx.ForEachRow(rows, []any{&data}, func() error {
+		var record log.Record
+		err := util.JSONFromString(data, &record)
+		if err != nil {
+			return fmt.Errorf("Failed to deserialize log record: '%w'.", err)
+		}
+
+		if query.Match(&record) {
+			records = append(records, &record)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read log records: '%w'.", err)
+	}
+
+	return records, nil
+}
+
+func (this *backend) RemoveUserLogRecords(email string) error {
+	_, err := this.pool.Exec(context.Background(), `DELETE FROM logs WHERE user_email = $1`, email)
+	if err != nil {
+		return fmt.Errorf("Failed to remove log records for user '%s': '%w'.", email, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,70 @@
+package pg
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func (this *backend) GetLoginThrottles(keys []string) (map[string]*model.LoginThrottle, error) {
+	rows, err := this.pool.Query(context.Background(), `SELECT key, data FROM login_throttles WHERE key = ANY($1)`, keys)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to query login throttles: '%w'.", err)
+	}
+
+	throttles := make(map[string]*model.LoginThrottle, len(keys))
+
+	var key string
+	var data string
+
+	_, err = pgx.ForEachRow(rows, []any{&key, &data}, func() error {
+		var throttle model.LoginThrottle
+		err := util.JSONFromString(data, &throttle)
+		if err != nil {
+			return fmt.Errorf("Failed to deserialize login throttle '%s': '%w'.", key, err)
+		}
+
+		throttles[key] = &throttle
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read login throttles: '%w'.", err)
+	}
+
+	return throttles, nil
+}
+
+func (this *backend) UpsertLoginThrottles(upsertThrottles map[string]*model.LoginThrottle) error {
+	return this.withTransaction(func(tx pgx.Tx) error {
+		for key, upsertThrottle := range upsertThrottles {
+			if upsertThrottle == nil {
+				_, err := tx.Exec(context.Background(), `DELETE FROM login_throttles WHERE key = $1`, key)
+				if err != nil {
+					return fmt.Errorf("Failed to remove login throttle '%s': '%w'.", key, err)
+				}
+
+				continue
+			}
+
+			data, err := util.ToJSON(upsertThrottle)
+			if err != nil {
+				return fmt.Errorf("Failed to serialize login throttle '%s': '%w'.", key, err)
+			}
+
+			_, err = tx.Exec(context.Background(),
+				`INSERT INTO login_throttles (key, data) VALUES ($1, $2)
+					ON CONFLICT (key) DO UPDATE SET
+						data = EXCLUDED.data`,
+				key, data)
+			if err != nil {
+				return fmt.Errorf("Failed to upsert login throttle '%s': '%w'.", key, err)
+			}
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,76 @@
+package pg
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func (this *backend) GetRegradeRequests(assignment *model.Assignment) (map[string]*model.RegradeRequest, error) {
+	rows, err := this.pool.Query(context.Background(),
+		`SELECT id, data FROM regrade_requests WHERE course_id = $1 AND assignment_id = $2`,
+		assignment.GetCourse().GetID(), assignment.GetID())
+	if err != nil {
+		return nil, fmt.Errorf("Failed to query regrade requests: '%w'.", err)
+	}
+
+	requests := make(map[string]*model.RegradeRequest)
+
+	var requestID string
+	var data string
+
+	_, err = pgx.ForEachRow(rows, []any{&requestID, &data}, func() error {
+		var request model.RegradeRequest
+		err := util.JSONFromString(data, &request)
+		if err != nil {
+			return fmt.Errorf("Failed to deserialize regrade request '%s': '%w'.", requestID, err)
+		}
+
+		requests[requestID] = &request
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read regrade requests: '%w'.", err)
+	}
+
+	return requests, nil
+}
+
+func (this *backend) UpsertRegradeRequests(assignment *model.Assignment, upsertRequests map[string]*model.RegradeRequest) error {
+	courseID := assignment.GetCourse().GetID()
+	assignmentID := assignment.GetID()
+
+	return this.withTransaction(func(tx pgx.Tx) error {
+		for requestID, upsertRequest := range upsertRequests {
+			if upsertRequest == nil {
+				_, err := tx.Exec(context.Background(),
+					`DELETE FROM regrade_requests WHERE course_id = $1 AND assignment_id = $2 AND id = $3`,
+					courseID, assignmentID, requestID)
+				if err != nil {
+					return fmt.Errorf("Failed to remove regrade request '%s': '%w'.", requestID, err)
+				}
+
+				continue
+			}
+
+			data, err := util.ToJSON(upsertRequest)
+			if err != nil {
+				return fmt.Errorf("Failed to serialize regrade request '%s': '%w'.", requestID, err)
+			}
+
+			_, err = tx.Exec(context.Background(),
+				`INSERT INTO regrade_requests (course_id, assignment_id, id, data) VALUES ($1, $2, $3, $4)
+					ON CONFLICT (course_id, assignment_id, id) DO UPDATE SET data = EXCLUDED.data`,
+				courseID, assignmentID, requestID, data)
+			if err != nil {
+				return fmt.Errorf("Failed to upsert regrade request '%s': '%w'.", requestID, err)
+			}
+		}
+
+		return nil
+	})
+}
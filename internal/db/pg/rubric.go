@@ -0,0 +1,76 @@
+package pg
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func (this *backend) GetRubricGrades(assignment *model.Assignment) (map[string]*model.RubricGrade, error) {
+	rows, err := this.pool.Query(context.Background(),
+		`SELECT submission_id, data FROM rubric_grades WHERE course_id = $1 AND assignment_id = $2`,
+		assignment.GetCourse().GetID(), assignment.GetID())
+	if err != nil {
+		return nil, fmt.Errorf("Failed to query rubric grades: '%w'.", err)
+	}
+
+	grades := make(map[string]*model.RubricGrade)
+
+	var submissionID string
+	var data string
+
+	_, err = pgx.ForEachRow(rows, []any{&submissionID, &data}, func() error {
+		var grade model.RubricGrade
+		err := util.JSONFromString(data, &grade)
+		if err != nil {
+			return fmt.Errorf("Failed to deserialize rubric grade '%s': '%w'.", submissionID, err)
+		}
+
+		grades[submissionID] = &grade
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read rubric grades: '%w'.", err)
+	}
+
+	return grades, nil
+}
+
+func (this *backend) UpsertRubricGrades(assignment *model.Assignment, upsertGrades map[string]*model.RubricGrade) error {
+	courseID := assignment.GetCourse().GetID()
+	assignmentID := assignment.GetID()
+
+	return this.withTransaction(func(tx pgx.Tx) error {
+		for submissionID, upsertGrade := range upsertGrades {
+			if upsertGrade == nil {
+				_, err := tx.Exec(context.Background(),
+					`DELETE FROM rubric_grades WHERE course_id = $1 AND assignment_id = $2 AND submission_id = $3`,
+					courseID, assignmentID, submissionID)
+				if err != nil {
+					return fmt.Errorf("Failed to remove rubric grade '%s': '%w'.", submissionID, err)
+				}
+
+				continue
+			}
+
+			data, err := util.ToJSON(upsertGrade)
+			if err != nil {
+				return fmt.Errorf("Failed to serialize rubric grade '%s': '%w'.", submissionID, err)
+			}
+
+			_, err = tx.Exec(context.Background(),
+				`INSERT INTO rubric_grades (course_id, assignment_id, submission_id, data) VALUES ($1, $2, $3, $4)
+					ON CONFLICT (course_id, assignment_id, submission_id) DO UPDATE SET data = EXCLUDED.data`,
+				courseID, assignmentID, submissionID, data)
+			if err != nil {
+				return fmt.Errorf("Failed to upsert rubric grade '%s': '%w'.", submissionID, err)
+			}
+		}
+
+		return nil
+	})
+}
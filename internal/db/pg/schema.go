@@ -0,0 +1,161 @@
+package pg
+
+// Most complex objects are stored as JSONB documents,
+// with the fields we need to filter or sort on pulled out into their own columns.
+
+// All tables managed by this backend (used when clearing the database).
+var TABLE_NAMES []string = []string{
+	"courses",
+	"assignments",
+	"users",
+	"submissions",
+	"submission_files",
+	"assignment_groups",
+	"assignment_extensions",
+	"rubric_grades",
+	"regrade_requests",
+	"tasks",
+	"grading_queue",
+	"login_throttles",
+	"audit_records",
+	"logs",
+	"metrics",
+	"analysis_individual",
+	"analysis_pairwise",
+}
+
+var SCHEMA_STATEMENTS []string = []string{
+	`CREATE TABLE IF NOT EXISTS courses (
+		id TEXT PRIMARY KEY,
+		data JSONB NOT NULL
+	)`,
+
+	`CREATE TABLE IF NOT EXISTS assignments (
+		course_id TEXT NOT NULL REFERENCES courses (id) ON DELETE CASCADE,
+		id TEXT NOT NULL,
+		data JSONB NOT NULL,
+		PRIMARY KEY (course_id, id)
+	)`,
+
+	`CREATE TABLE IF NOT EXISTS users (
+		email TEXT PRIMARY KEY,
+		data JSONB NOT NULL
+	)`,
+	`CREATE INDEX IF NOT EXISTS users_course_info_index ON users USING GIN ((data -> 'course-info'))`,
+
+	`CREATE TABLE IF NOT EXISTS submissions (
+		course_id TEXT NOT NULL,
+		assignment_id TEXT NOT NULL,
+		user_email TEXT NOT NULL,
+		short_id TEXT NOT NULL,
+		grading_start_time BIGINT NOT NULL,
+		info JSONB NOT NULL,
+		stdout TEXT NOT NULL,
+		stderr TEXT NOT NULL,
+		PRIMARY KEY (course_id, assignment_id, user_email, short_id)
+	)`,
+
+	`CREATE TABLE IF NOT EXISTS submission_files (
+		course_id TEXT NOT NULL,
+		assignment_id TEXT NOT NULL,
+		user_email TEXT NOT NULL,
+		short_id TEXT NOT NULL,
+		kind TEXT NOT NULL,
+		relpath TEXT NOT NULL,
+		contents BYTEA NOT NULL,
+		PRIMARY KEY (course_id, assignment_id, user_email, short_id, kind, relpath),
+		FOREIGN KEY (course_id, assignment_id, user_email, short_id)
+			REFERENCES submissions (course_id, assignment_id, user_email, short_id) ON DELETE CASCADE
+	)`,
+
+	`CREATE TABLE IF NOT EXISTS assignment_groups (
+		course_id TEXT NOT NULL,
+		assignment_id TEXT NOT NULL,
+		id TEXT NOT NULL,
+		data JSONB NOT NULL,
+		PRIMARY KEY (course_id, assignment_id, id)
+	)`,
+
+	`CREATE TABLE IF NOT EXISTS assignment_extensions (
+		course_id TEXT NOT NULL,
+		assignment_id TEXT NOT NULL,
+		email TEXT NOT NULL,
+		data JSONB NOT NULL,
+		PRIMARY KEY (course_id, assignment_id, email)
+	)`,
+
+	`CREATE TABLE IF NOT EXISTS rubric_grades (
+		course_id TEXT NOT NULL,
+		assignment_id TEXT NOT NULL,
+		submission_id TEXT NOT NULL,
+		data JSONB NOT NULL,
+		PRIMARY KEY (course_id, assignment_id, submission_id)
+	)`,
+
+	`CREATE TABLE IF NOT EXISTS regrade_requests (
+		course_id TEXT NOT NULL,
+		assignment_id TEXT NOT NULL,
+		id TEXT NOT NULL,
+		data JSONB NOT NULL,
+		PRIMARY KEY (course_id, assignment_id, id)
+	)`,
+
+	`CREATE TABLE IF NOT EXISTS tasks (
+		hash TEXT PRIMARY KEY,
+		source TEXT NOT NULL,
+		course_id TEXT NOT NULL,
+		next_run_time BIGINT NOT NULL,
+		data JSONB NOT NULL
+	)`,
+
+	`CREATE TABLE IF NOT EXISTS grading_queue (
+		id TEXT PRIMARY KEY,
+		status TEXT NOT NULL,
+		queue_time BIGINT NOT NULL,
+		data JSONB NOT NULL
+	)`,
+	`CREATE INDEX IF NOT EXISTS grading_queue_status_index ON grading_queue (status, queue_time)`,
+
+	`CREATE TABLE IF NOT EXISTS login_throttles (
+		key TEXT PRIMARY KEY,
+		data JSONB NOT NULL
+	)`,
+
+	`CREATE TABLE IF NOT EXISTS audit_records (
+		record_index BIGINT PRIMARY KEY,
+		timestamp BIGINT NOT NULL,
+		course_id TEXT NOT NULL,
+		data JSONB NOT NULL
+	)`,
+
+	`CREATE TABLE IF NOT EXISTS logs (
+		id BIGSERIAL PRIMARY KEY,
+		level INTEGER NOT NULL,
+		timestamp BIGINT NOT NULL,
+		course_id TEXT NOT NULL,
+		assignment_id TEXT NOT NULL,
+		user_email TEXT NOT NULL,
+		data JSONB NOT NULL
+	)`,
+	`CREATE INDEX IF NOT EXISTS logs_timestamp_index ON logs (timestamp)`,
+
+	`CREATE TABLE IF NOT EXISTS metrics (
+		id BIGSERIAL PRIMARY KEY,
+		type TEXT NOT NULL,
+		timestamp BIGINT NOT NULL,
+		data JSONB NOT NULL
+	)`,
+	`CREATE INDEX IF NOT EXISTS metrics_type_index ON metrics (type, timestamp)`,
+
+	`CREATE TABLE IF NOT EXISTS analysis_individual (
+		full_id TEXT PRIMARY KEY,
+		course_id TEXT NOT NULL,
+		data JSONB NOT NULL
+	)`,
+
+	`CREATE TABLE IF NOT EXISTS analysis_pairwise (
+		key TEXT PRIMARY KEY,
+		course_id TEXT NOT NULL,
+		data JSONB NOT NULL
+	)`,
+}
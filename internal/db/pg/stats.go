@@ -0,0 +1,111 @@
+package pg
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/edulinq/autograder/internal/stats"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func (this *backend) GetMetrics(query stats.Query) ([]*stats.Metric, error) {
+	if query.Type == "" {
+		return nil, fmt.Errorf("No metric type was given.")
+	}
+
+	rows, err := this.pool.Query(context.Background(), `SELECT data FROM metrics WHERE type = $1 ORDER BY id`, string(query.Type))
+	if err != nil {
+		return nil, fmt.Errorf("Failed to query metrics: '%w'.", err)
+	}
+
+	records := make([]*stats.Metric, 0)
+
+	var data string
+	_, err = pgx.ForEachRow(rows, []any{&data}, func() error {
+		var record stats.Metric
+		err := util.JSONFromString(data, &record)
+		if err != nil {
+			return fmt.Errorf("Failed to deserialize metric: '%w'.", err)
+		}
+
+		if query.Match(&record) {
+			records = append(records, &record)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read metrics: '%w'.", err)
+	}
+
+	return records, nil
+}
+
+func (this *backend) StoreMetric(record *stats.Metric) error {
+	if record.Type == "" {
+		return fmt.Errorf("No metric type was given.")
+	}
+
+	data, err := util.ToJSON(record)
+	if err != nil {
+		return fmt.Errorf("Failed to serialize metric: '%w'.", err)
+	}
+
+	_, err = this.pool.Exec(context.Background(),
+		`INSERT INTO metrics (type, timestamp, data) VALUES ($1, $2, $3)`,
+		string(record.Type), int64(record.Timestamp), data)
+	if err != nil {
+		return fmt.Errorf("Failed to store metric: '%w'.", err)
+	}
+
+	return nil
+}
+
+func (this *backend) PseudonymizeUserMetrics(email string, pseudonym string) error {
+	return this.withTransaction(func(tx pgx.Tx) error {
+		rows, err := tx.Query(context.Background(), `SELECT id, data FROM metrics`)
+		if err != nil {
+			return fmt.Errorf("Failed to query metrics: '%w'.", err)
+		}
+
+		updates := make(map[int64]string)
+
+		var id int64
+		var data string
+
+		_, err = pgx.ForEachRow(rows, []any{&id, &data}, func() error {
+			var record stats.Metric
+			err := util.JSONFromString(data, &record)
+			if err != nil {
+				return fmt.Errorf("Failed to deserialize metric: '%w'.", err)
+			}
+
+			if !record.IsUser(email) {
+				return nil
+			}
+
+			record.Attributes[stats.MetricAttributeUserEmail] = pseudonym
+
+			updates[id], err = util.ToJSON(record)
+			if err != nil {
+				return fmt.Errorf("Failed to serialize metric: '%w'.", err)
+			}
+
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("Failed to read metrics: '%w'.", err)
+		}
+
+		for id, data := range updates {
+			_, err = tx.Exec(context.Background(), `UPDATE metrics SET data = $1 WHERE id = $2`, data, id)
+			if err != nil {
+				return fmt.Errorf("Failed to update metric: '%w'.", err)
+			}
+		}
+
+		return nil
+	})
+}
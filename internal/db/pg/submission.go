@@ -0,0 +1,474 @@
+package pg
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+const (
+	SUBMISSION_FILE_KIND_INPUT  = "input"
+	SUBMISSION_FILE_KIND_OUTPUT = "output"
+)
+
+func (this *backend) SaveSubmissions(course *model.Course, submissions []*model.GradingResult) error {
+	return this.withTransaction(func(tx pgx.Tx) error {
+		return saveSubmissions(tx, submissions)
+	})
+}
+
+func saveSubmissions(tx pgx.Tx, submissions []*model.GradingResult) error {
+	for _, submission := range submissions {
+		err := saveSubmission(tx, submission)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func saveSubmission(tx pgx.Tx, submission *model.GradingResult) error {
+	info := submission.Info
+	key := []any{info.CourseID, info.AssignmentID, info.User, info.ShortID}
+
+	data, err := util.ToJSON(info)
+	if err != nil {
+		return fmt.Errorf("Failed to serialize submission result '%s': '%w'.", info.ID, err)
+	}
+
+	// Replace any existing submission (and its files).
+	_, err = tx.Exec(context.Background(),
+		`DELETE FROM submissions WHERE course_id = $1 AND assignment_id = $2 AND user_email = $3 AND short_id = $4`,
+		key...)
+	if err != nil {
+		return fmt.Errorf("Failed to remove old submission '%s': '%w'.", info.ID, err)
+	}
+
+	_, err = tx.Exec(context.Background(),
+		`INSERT INTO submissions (course_id, assignment_id, user_email, short_id, grading_start_time, info, stdout, stderr)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+		append(key, int64(info.GradingStartTime), data, submission.Stdout, submission.Stderr)...)
+	if err != nil {
+		return fmt.Errorf("Failed to save submission '%s': '%w'.", info.ID, err)
+	}
+
+	files := map[string]map[string][]byte{
+		SUBMISSION_FILE_KIND_INPUT:  submission.InputFilesGZip,
+		SUBMISSION_FILE_KIND_OUTPUT: submission.OutputFilesGZip,
+	}
+
+	for kind, contents := range files {
+		for relpath, content := range contents {
+			_, err = tx.Exec(context.Background(),
+				`INSERT INTO submission_files (course_id, assignment_id, user_email, short_id, kind, relpath, contents)
+					VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+				append(key, kind, relpath, content)...)
+			if err != nil {
+				return fmt.Errorf("Failed to save submission %s file '%s' for '%s': '%w'.", kind, relpath, info.ID, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Compute the next submission ID based on the current time.
+// If a submission ID exists for the current time, increment the ID until it is unique.
+func (this *backend) GetNextSubmissionID(assignment *model.Assignment, email string) (string, error) {
+	submissionID := time.Now().Unix()
+
+	for {
+		var exists bool
+		err := this.pool.QueryRow(context.Background(),
+			`SELECT EXISTS (SELECT 1 FROM submissions WHERE course_id = $1 AND assignment_id = $2 AND user_email = $3 AND short_id = $4)`,
+			assignment.GetCourse().GetID(), assignment.GetID(), email, fmt.Sprintf("%d", submissionID)).Scan(&exists)
+		if err != nil {
+			return "", fmt.Errorf("Failed to check for existing submission ID: '%w'.", err)
+		}
+
+		if !exists {
+			break
+		}
+
+		// This ID has been used.
+		submissionID++
+	}
+
+	return fmt.Sprintf("%d", submissionID), nil
+}
+
+func (this *backend) GetPreviousSubmissionID(assignment *model.Assignment, email string, shortSubmissionID string) (string, error) {
+	history, err := this.GetSubmissionHistory(assignment, email)
+	if err != nil {
+		return "", err
+	}
+
+	index := slices.IndexFunc(history, func(item *model.SubmissionHistoryItem) bool {
+		return item.ShortID == shortSubmissionID
+	})
+
+	if index <= 0 {
+		return "", nil
+	}
+
+	return history[index-1].ID, nil
+}
+
+func (this *backend) GetSubmissionResult(assignment *model.Assignment, email string, shortSubmissionID string) (*model.GradingInfo, error) {
+	results, err := this.getSubmissions(submissionWhereClause(shortSubmissionID), false, submissionArgs(assignment, email, shortSubmissionID)...)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(results) == 0 {
+		return nil, nil
+	}
+
+	return results[0].Info, nil
+}
+
+func (this *backend) GetSubmissionHistory(assignment *model.Assignment, email string) ([]*model.SubmissionHistoryItem, error) {
+	results, err := this.getSubmissions(`WHERE course_id = $1 AND assignment_id = $2 AND user_email = $3 ORDER BY grading_start_time, short_id`,
+		false, assignment.GetCourse().GetID(), assignment.GetID(), email)
+	if err != nil {
+		return nil, err
+	}
+
+	history := make([]*model.SubmissionHistoryItem, 0, len(results))
+	for _, result := range results {
+		history = append(history, result.Info.ToHistoryItem())
+	}
+
+	return history, nil
+}
+
+func (this *backend) GetRecentSubmissions(assignment *model.Assignment, reference *model.ParsedCourseUserReference) (map[string]*model.GradingInfo, error) {
+	results, err := this.getRecentSubmissions(assignment, reference, false)
+	if err != nil {
+		return nil, err
+	}
+
+	gradingInfos := make(map[string]*model.GradingInfo, len(results))
+	for email, result := range results {
+		if result == nil {
+			gradingInfos[email] = nil
+		} else {
+			gradingInfos[email] = result.Info
+		}
+	}
+
+	return gradingInfos, nil
+}
+
+func (this *backend) GetScoringInfos(assignment *model.Assignment, reference *model.ParsedCourseUserReference) (map[string]*model.ScoringInfo, error) {
+	gradingInfos, err := this.GetRecentSubmissions(assignment, reference)
+	if err != nil {
+		return nil, err
+	}
+
+	scoringInfos := make(map[string]*model.ScoringInfo, len(gradingInfos))
+	for email, gradingInfo := range gradingInfos {
+		if gradingInfo == nil {
+			scoringInfos[email] = nil
+		} else {
+			scoringInfos[email] = gradingInfo.ToScoringInfo()
+		}
+	}
+
+	return scoringInfos, nil
+}
+
+func (this *backend) GetRecentSubmissionSurvey(assignment *model.Assignment, reference *model.ParsedCourseUserReference) (map[string]*model.SubmissionHistoryItem, error) {
+	gradingInfos, err := this.GetRecentSubmissions(assignment, reference)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make(map[string]*model.SubmissionHistoryItem, len(gradingInfos))
+	for email, gradingInfo := range gradingInfos {
+		if gradingInfo == nil {
+			results[email] = nil
+		} else {
+			results[email] = gradingInfo.ToHistoryItem()
+		}
+	}
+
+	return results, nil
+}
+
+func (this *backend) GetSubmissionContents(assignment *model.Assignment, email string, shortSubmissionID string) (*model.GradingResult, error) {
+	results, err := this.getSubmissions(submissionWhereClause(shortSubmissionID), true, submissionArgs(assignment, email, shortSubmissionID)...)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(results) == 0 {
+		return nil, nil
+	}
+
+	return results[0], nil
+}
+
+func (this *backend) GetRecentSubmissionContents(assignment *model.Assignment, reference *model.ParsedCourseUserReference) (map[string]*model.GradingResult, error) {
+	return this.getRecentSubmissions(assignment, reference, true)
+}
+
+func (this *backend) RemoveSubmission(assignment *model.Assignment, email string, shortSubmissionID string) (bool, error) {
+	var err error
+
+	if shortSubmissionID == "" {
+		shortSubmissionID, err = this.getMostRecentSubmissionID(assignment, email)
+		if err != nil {
+			return false, fmt.Errorf("Failed to get most recent submission id: '%w'.", err)
+		}
+	}
+
+	if shortSubmissionID == "" {
+		return false, nil
+	}
+
+	tag, err := this.pool.Exec(context.Background(),
+		`DELETE FROM submissions WHERE course_id = $1 AND assignment_id = $2 AND user_email = $3 AND short_id = $4`,
+		assignment.GetCourse().GetID(), assignment.GetID(), email, shortSubmissionID)
+	if err != nil {
+		return false, fmt.Errorf("Failed to remove submission '%s': '%w'.", shortSubmissionID, err)
+	}
+
+	return (tag.RowsAffected() > 0), nil
+}
+
+func (this *backend) GetSubmissionAttempts(assignment *model.Assignment, email string) ([]*model.GradingResult, error) {
+	return this.getSubmissions(`WHERE course_id = $1 AND assignment_id = $2 AND user_email = $3 ORDER BY short_id`,
+		true, assignment.GetCourse().GetID(), assignment.GetID(), email)
+}
+
+func (this *backend) GetSubmissionUsers(assignment *model.Assignment) ([]string, error) {
+	rows, err := this.pool.Query(context.Background(), `SELECT DISTINCT user_email FROM submissions WHERE course_id = $1 AND assignment_id = $2`,
+		assignment.GetCourse().GetID(), assignment.GetID())
+	if err != nil {
+		return nil, fmt.Errorf("Failed to query submission users: '%w'.", err)
+	}
+
+	emails, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read submission users: '%w'.", err)
+	}
+
+	// Sort outside of the database to avoid any collation differences.
+	slices.Sort(emails)
+
+	return emails, nil
+}
+
+// Get the short ID of the most recent submission (or empty string if there are no submissions).
+// The most recent submission has the largest ID for the user.
+func (this *backend) getMostRecentSubmissionID(assignment *model.Assignment, email string) (string, error) {
+	rows, err := this.pool.Query(context.Background(),
+		`SELECT short_id FROM submissions WHERE course_id = $1 AND assignment_id = $2 AND user_email = $3 ORDER BY short_id DESC LIMIT 1`,
+		assignment.GetCourse().GetID(), assignment.GetID(), email)
+	if err != nil {
+		return "", fmt.Errorf("Failed to query most recent submission: '%w'.", err)
+	}
+
+	ids, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return "", fmt.Errorf("Failed to read most recent submission: '%w'.", err)
+	}
+
+	if len(ids) == 0 {
+		return "", nil
+	}
+
+	return ids[0], nil
+}
+
+// Get the most recent submission for every user in the course that matches the reference.
+// Users without a submission (but matching the reference) will be represented with a nil map value.
+func (this *backend) getRecentSubmissions(assignment *model.Assignment, reference *model.ParsedCourseUserReference, withContents bool) (map[string]*model.GradingResult, error) {
+	users, err := this.GetCourseUsers(assignment.GetCourse())
+	if err != nil {
+		return nil, err
+	}
+
+	results := make(map[string]*model.GradingResult)
+	emails := make([]string, 0, len(users))
+
+	for email, user := range users {
+		if !reference.RefersTo(email, user.Role, user.Sections) {
+			continue
+		}
+
+		results[email] = nil
+		emails = append(emails, email)
+	}
+
+	if len(emails) == 0 {
+		return results, nil
+	}
+
+	submissions, err := this.getSubmissions(
+		`WHERE (course_id, assignment_id, user_email, short_id) IN (
+			SELECT DISTINCT ON (user_email) course_id, assignment_id, user_email, short_id
+			FROM submissions
+			WHERE course_id = $1 AND assignment_id = $2 AND user_email = ANY($3)
+			ORDER BY user_email, short_id DESC
+		)`,
+		withContents, assignment.GetCourse().GetID(), assignment.GetID(), emails)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, submission := range submissions {
+		results[submission.Info.User] = submission
+	}
+
+	return results, nil
+}
+
+// Fetch the submissions matching a where clause (which may also contain ordering/limits).
+// Results will only contain the grading info, unless file contents were requested.
+func (this *backend) getSubmissions(whereClause string, withContents bool, args ...any) ([]*model.GradingResult, error) {
+	rows, err := this.pool.Query(context.Background(),
+		`SELECT course_id, assignment_id, user_email, short_id, info, stdout, stderr FROM submissions `+whereClause, args...)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to query submissions: '%w'.", err)
+	}
+
+	results := make([]*model.GradingResult, 0)
+	keys := make([]submissionKey, 0)
+
+	var key submissionKey
+	var data string
+	var stdout string
+	var stderr string
+
+	_, err = pgx.ForEachRow(rows, []any{&key.CourseID, &key.AssignmentID, &key.User, &key.ShortID, &data, &stdout, &stderr}, func() error {
+		var info model.GradingInfo
+		err := util.JSONFromString(data, &info)
+		if err != nil {
+			return fmt.Errorf("Unable to deserialize grading info for '%s': '%w'.", key.String(), err)
+		}
+
+		result := &model.GradingResult{
+			Info: &info,
+		}
+
+		if withContents {
+			result.Stdout = stdout
+			result.Stderr = stderr
+			result.InputFilesGZip = make(map[string][]byte)
+			result.OutputFilesGZip = make(map[string][]byte)
+		}
+
+		results = append(results, result)
+		keys = append(keys, key)
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read submissions: '%w'.", err)
+	}
+
+	if withContents && (len(results) > 0) {
+		err = this.fillSubmissionFiles(keys, results)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return results, nil
+}
+
+type submissionKey struct {
+	CourseID     string
+	AssignmentID string
+	User         string
+	ShortID      string
+}
+
+func (this submissionKey) String() string {
+	return strings.Join([]string{this.CourseID, this.AssignmentID, this.User, this.ShortID}, "::")
+}
+
+// Fill in the input/output files for each result (results and keys must be parallel).
+func (this *backend) fillSubmissionFiles(keys []submissionKey, results []*model.GradingResult) error {
+	lookup := make(map[submissionKey]*model.GradingResult, len(keys))
+
+	courseIDs := make([]string, 0, len(keys))
+	assignmentIDs := make([]string, 0, len(keys))
+	users := make([]string, 0, len(keys))
+	shortIDs := make([]string, 0, len(keys))
+
+	for i, key := range keys {
+		lookup[key] = results[i]
+
+		courseIDs = append(courseIDs, key.CourseID)
+		assignmentIDs = append(assignmentIDs, key.AssignmentID)
+		users = append(users, key.User)
+		shortIDs = append(shortIDs, key.ShortID)
+	}
+
+	rows, err := this.pool.Query(context.Background(),
+		`SELECT f.course_id, f.assignment_id, f.user_email, f.short_id, f.kind, f.relpath, f.contents
+			FROM submission_files f
+			JOIN UNNEST($1::TEXT[], $2::TEXT[], $3::TEXT[], $4::TEXT[]) AS k (course_id, assignment_id, user_email, short_id)
+				ON (f.course_id = k.course_id AND f.assignment_id = k.assignment_id AND f.user_email = k.user_email AND f.short_id = k.short_id)`,
+		courseIDs, assignmentIDs, users, shortIDs)
+	if err != nil {
+		return fmt.Errorf("Failed to query submission files: '%w'.", err)
+	}
+
+	var key submissionKey
+	var kind string
+	var relpath string
+	var contents []byte
+
+	_, err = pgx.ForEachRow(rows, []any{&key.CourseID, &key.AssignmentID, &key.User, &key.ShortID, &kind, &relpath, &contents}, func() error {
+		result, ok := lookup[key]
+		if !ok {
+			return nil
+		}
+
+		// Make sure each file gets its own copy of the contents.
+		content := slices.Clone(contents)
+
+		if kind == SUBMISSION_FILE_KIND_INPUT {
+			result.InputFilesGZip[relpath] = content
+		} else {
+			result.OutputFilesGZip[relpath] = content
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("Failed to read submission files: '%w'.", err)
+	}
+
+	return nil
+}
+
+// Get a where clause that will select either a specific submission or the most recent one.
+func submissionWhereClause(shortSubmissionID string) string {
+	if shortSubmissionID == "" {
+		return `WHERE course_id = $1 AND assignment_id = $2 AND user_email = $3 ORDER BY short_id DESC LIMIT 1`
+	}
+
+	return `WHERE course_id = $1 AND assignment_id = $2 AND user_email = $3 AND short_id = $4`
+}
+
+func submissionArgs(assignment *model.Assignment, email string, shortSubmissionID string) []any {
+	args := []any{assignment.GetCourse().GetID(), assignment.GetID(), email}
+	if shortSubmissionID != "" {
+		args = append(args, shortSubmissionID)
+	}
+
+	return args
+}
@@ -0,0 +1,94 @@
+package pg
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func (this *backend) GetActiveCourseTasks(course *model.Course) (map[string]*model.FullScheduledTask, error) {
+	return this.getTasks(`WHERE source = $1 AND course_id = $2`, model.TaskSourceCourse, course.GetID())
+}
+
+func (this *backend) GetActiveTasks() (map[string]*model.FullScheduledTask, error) {
+	return this.getTasks("")
+}
+
+func (this *backend) GetNextActiveTask() (*model.FullScheduledTask, error) {
+	tasks, err := this.getTasks(`ORDER BY next_run_time LIMIT 1`)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, task := range tasks {
+		return task, nil
+	}
+
+	return nil, nil
+}
+
+func (this *backend) UpsertActiveTasks(upsertTasks map[string]*model.FullScheduledTask) error {
+	return this.withTransaction(func(tx pgx.Tx) error {
+		for hash, upsertTask := range upsertTasks {
+			if upsertTask == nil {
+				_, err := tx.Exec(context.Background(), `DELETE FROM tasks WHERE hash = $1`, hash)
+				if err != nil {
+					return fmt.Errorf("Failed to remove task '%s': '%w'.", hash, err)
+				}
+
+				continue
+			}
+
+			data, err := util.ToJSON(upsertTask)
+			if err != nil {
+				return fmt.Errorf("Failed to serialize task '%s': '%w'.", hash, err)
+			}
+
+			_, err = tx.Exec(context.Background(),
+				`INSERT INTO tasks (hash, source, course_id, next_run_time, data) VALUES ($1, $2, $3, $4, $5)
+					ON CONFLICT (hash) DO UPDATE SET
+						source = EXCLUDED.source,
+						course_id = EXCLUDED.course_id,
+						next_run_time = EXCLUDED.next_run_time,
+						data = EXCLUDED.data`,
+				hash, string(upsertTask.Source), upsertTask.CourseID, int64(upsertTask.NextRunTime), data)
+			if err != nil {
+				return fmt.Errorf("Failed to upsert task '%s': '%w'.", hash, err)
+			}
+		}
+
+		return nil
+	})
+}
+
+func (this *backend) getTasks(clause string, args ...any) (map[string]*model.FullScheduledTask, error) {
+	rows, err := this.pool.Query(context.Background(), `SELECT hash, data FROM tasks `+clause, args...)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to query tasks: '%w'.", err)
+	}
+
+	tasks := make(map[string]*model.FullScheduledTask)
+
+	var hash string
+	var data string
+
+	_, err = pgx.ForEachRow(rows, []any{&hash, &data}, func() error {
+		var task model.FullScheduledTask
+		err := util.JSONFromString(data, &task)
+		if err != nil {
+			return fmt.Errorf("Failed to deserialize task '%s': '%w'.", hash, err)
+		}
+
+		tasks[hash] = &task
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read tasks: '%w'.", err)
+	}
+
+	return tasks, nil
+}
@@ -0,0 +1,187 @@
+package pg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func (this *backend) GetServerUsers() (map[string]*model.ServerUser, error) {
+	return getServerUsers(this.pool, "")
+}
+
+func (this *backend) GetCourseUsers(course *model.Course) (map[string]*model.CourseUser, error) {
+	users, err := getServerUsers(this.pool, `WHERE (data -> 'course-info') ? $1::TEXT`, course.GetID())
+	if err != nil {
+		return nil, err
+	}
+
+	courseUsers := make(map[string]*model.CourseUser)
+	for email, user := range users {
+		// Don't include root as a course user.
+		if email == model.RootUserEmail {
+			continue
+		}
+
+		courseUser, err := user.ToCourseUser(course.ID, false)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid user '%s': '%w'.", email, err)
+		}
+
+		if courseUser != nil {
+			courseUsers[courseUser.Email] = courseUser
+		}
+	}
+
+	return courseUsers, nil
+}
+
+func (this *backend) GetServerUser(email string) (*model.ServerUser, error) {
+	users, err := getServerUsers(this.pool, `WHERE email = $1`, email)
+	if err != nil {
+		return nil, err
+	}
+
+	return users[email], nil
+}
+
+func (this *backend) UpsertUsers(upsertUsers map[string]*model.ServerUser) error {
+	return this.withTransaction(func(tx pgx.Tx) error {
+		for email, upsertUser := range upsertUsers {
+			if upsertUser == nil {
+				continue
+			}
+
+			users, err := getServerUsers(tx, `WHERE email = $1 FOR UPDATE`, email)
+			if err != nil {
+				return fmt.Errorf("Failed to get user to merge before saving: '%w'.", err)
+			}
+
+			user := upsertUser
+
+			oldUser, exists := users[email]
+			if exists {
+				_, err = oldUser.Merge(upsertUser)
+				if err != nil {
+					return fmt.Errorf("User '%s' could not be merged with existing user: '%w'.", email, err)
+				}
+
+				user = oldUser
+			}
+
+			err = saveServerUser(tx, user)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func (this *backend) DeleteUser(email string) error {
+	_, err := this.pool.Exec(context.Background(), `DELETE FROM users WHERE email = $1`, email)
+	if err != nil {
+		return fmt.Errorf("Failed to delete user '%s': '%w'.", email, err)
+	}
+
+	return nil
+}
+
+func (this *backend) RemoveUserFromCourse(course *model.Course, email string) error {
+	_, err := this.pool.Exec(context.Background(),
+		`UPDATE users SET data = jsonb_set(data, '{course-info}', (data -> 'course-info') - $1::TEXT) WHERE email = $2`,
+		course.GetID(), email)
+	if err != nil {
+		return fmt.Errorf("Failed to remove user '%s' from course '%s': '%w'.", email, course.GetID(), err)
+	}
+
+	return nil
+}
+
+func (this *backend) DeleteUserToken(email string, tokenID string) (bool, error) {
+	removed := false
+
+	err := this.withTransaction(func(tx pgx.Tx) error {
+		users, err := getServerUsers(tx, `WHERE email = $1 FOR UPDATE`, email)
+		if err != nil {
+			return fmt.Errorf("Failed to get user when deleting user token '%s': '%w'.", email, err)
+		}
+
+		user, ok := users[email]
+		if !ok {
+			return nil
+		}
+
+		for i, token := range user.Tokens {
+			if tokenID == token.ID {
+				user.Tokens = slices.Delete(user.Tokens, i, i+1)
+				removed = true
+				break
+			}
+		}
+
+		if !removed {
+			return nil
+		}
+
+		return saveServerUser(tx, user)
+	})
+
+	return removed, err
+}
+
+func saveServerUser(tx pgx.Tx, user *model.ServerUser) error {
+	data, err := util.ToJSON(user)
+	if err != nil {
+		return fmt.Errorf("Failed to serialize user '%s': '%w'.", user.Email, err)
+	}
+
+	_, err = tx.Exec(context.Background(),
+		`INSERT INTO users (email, data) VALUES ($1, $2) ON CONFLICT (email) DO UPDATE SET data = EXCLUDED.data`,
+		user.Email, data)
+	if err != nil {
+		return fmt.Errorf("Failed to save user '%s': '%w'.", user.Email, err)
+	}
+
+	return nil
+}
+
+// The common interface between a pool and transaction used for reads.
+type querier interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+}
+
+func getServerUsers(source querier, whereClause string, args ...any) (map[string]*model.ServerUser, error) {
+	users := make(map[string]*model.ServerUser)
+
+	rows, err := source.Query(context.Background(), `SELECT data FROM users `+whereClause, args...)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to query users: '%w'.", err)
+	}
+
+	userJSONs, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read users: '%w'.", err)
+	}
+
+	var errs error = nil
+	for _, userJSON := range userJSONs {
+		var user model.ServerUser
+		err = util.JSONFromString(userJSON, &user)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to deserialize user: '%w'.", err)
+		}
+
+		errs = errors.Join(errs, user.Validate())
+		users[user.Email] = &user
+	}
+
+	return users, errs
+}
@@ -0,0 +1,95 @@
+package db
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func GetRegradeRequests(assignment *model.Assignment) (map[string]*model.RegradeRequest, error) {
+	if backend == nil {
+		return nil, fmt.Errorf("Database has not been opened.")
+	}
+
+	return backend.GetRegradeRequests(assignment)
+}
+
+// Get a regrade request by ID.
+// Returns (nil, nil) if the request does not exist.
+func GetRegradeRequest(assignment *model.Assignment, id string) (*model.RegradeRequest, error) {
+	requests, err := GetRegradeRequests(assignment)
+	if err != nil {
+		return nil, err
+	}
+
+	return requests[id], nil
+}
+
+// Get all the regrade requests for an assignment (ordered by creation time).
+// If email is not empty, only requests from that user will be returned.
+func GetSortedRegradeRequests(assignment *model.Assignment, email string) ([]*model.RegradeRequest, error) {
+	requests, err := GetRegradeRequests(assignment)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*model.RegradeRequest, 0, len(requests))
+	for _, id := range util.GetSortedKeys(requests) {
+		request := requests[id]
+
+		if (email != "") && (request.User != email) {
+			continue
+		}
+
+		results = append(results, request)
+	}
+
+	slices.SortStableFunc(results, func(a *model.RegradeRequest, b *model.RegradeRequest) int {
+		return cmp.Compare(a.CreateTime, b.CreateTime)
+	})
+
+	return results, nil
+}
+
+func UpsertRegradeRequest(assignment *model.Assignment, request *model.RegradeRequest) error {
+	err := request.Validate()
+	if err != nil {
+		return fmt.Errorf("Invalid regrade request: '%w'.", err)
+	}
+
+	requests := map[string]*model.RegradeRequest{
+		request.ID: request,
+	}
+
+	return UpsertRegradeRequests(assignment, requests)
+}
+
+func UpsertRegradeRequests(assignment *model.Assignment, requests map[string]*model.RegradeRequest) error {
+	if backend == nil {
+		return fmt.Errorf("Database has not been opened.")
+	}
+
+	return backend.UpsertRegradeRequests(assignment, requests)
+}
+
+// Get the resolved regrade requests that change a score, keyed by full submission ID.
+func getRegradeAdjustments(assignment *model.Assignment) (map[string][]*model.RegradeRequest, error) {
+	requests, err := GetRegradeRequests(assignment)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get regrade requests: '%w'.", err)
+	}
+
+	adjustments := make(map[string][]*model.RegradeRequest)
+	for _, request := range requests {
+		if request.IsOpen() || (request.ScoreAdjustment == 0) {
+			continue
+		}
+
+		adjustments[request.SubmissionID] = append(adjustments[request.SubmissionID], request)
+	}
+
+	return adjustments, nil
+}
@@ -0,0 +1,243 @@
+package db
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/timestamp"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func (this *DBTests) DBTestRegradeRequestsBase(test *testing.T) {
+	ResetForTesting()
+	defer ResetForTesting()
+
+	assignment := MustGetTestAssignment()
+
+	requests, err := GetSortedRegradeRequests(assignment, "")
+	if err != nil {
+		test.Fatalf("Failed to fetch empty regrade requests: '%v'.", err)
+	}
+
+	if len(requests) != 0 {
+		test.Fatalf("Initial regrade request fetch is not empty, found %d requests.", len(requests))
+	}
+
+	resolveTime := timestamp.FromMSecs(300)
+
+	expected := []*model.RegradeRequest{
+		&model.RegradeRequest{
+			ID:            "bbb",
+			CourseID:      TEST_COURSE_ID,
+			AssignmentID:  TEST_ASSIGNMENT_ID,
+			SubmissionID:  TEST_RUBRIC_SUBMISSION_ID,
+			User:          "course-student@test.edulinq.org",
+			Question:      "Q1",
+			Justification: "My answer was right.",
+			CreateTime:    timestamp.FromMSecs(100),
+			Status:        model.REGRADE_STATUS_OPEN,
+		},
+		&model.RegradeRequest{
+			ID:              "aaa",
+			CourseID:        TEST_COURSE_ID,
+			AssignmentID:    TEST_ASSIGNMENT_ID,
+			SubmissionID:    "course101::hw0::course-other@test.edulinq.org::1697406256",
+			User:            "course-other@test.edulinq.org",
+			Question:        "Q2",
+			Justification:   "Please check again.",
+			CreateTime:      timestamp.FromMSecs(200),
+			Status:          model.REGRADE_STATUS_RESOLVED,
+			ScoreAdjustment: -0.5,
+			Reply:           "Done.",
+			ResolvedBy:      "course-grader@test.edulinq.org",
+			ResolveTime:     &resolveTime,
+		},
+	}
+
+	for _, request := range expected {
+		err = UpsertRegradeRequest(assignment, request)
+		if err != nil {
+			test.Fatalf("Failed to upsert regrade request '%s': '%v'.", request.ID, err)
+		}
+	}
+
+	requests, err = GetSortedRegradeRequests(assignment, "")
+	if err != nil {
+		test.Fatalf("Failed to fetch regrade requests: '%v'.", err)
+	}
+
+	if !reflect.DeepEqual(expected, requests) {
+		test.Fatalf("Unexpected regrade requests. Expected: '%s', Actual: '%s'.", util.MustToJSONIndent(expected), util.MustToJSONIndent(requests))
+	}
+
+	requests, err = GetSortedRegradeRequests(assignment, "course-student@test.edulinq.org")
+	if err != nil {
+		test.Fatalf("Failed to fetch user regrade requests: '%v'.", err)
+	}
+
+	if !reflect.DeepEqual(expected[0:1], requests) {
+		test.Fatalf("Unexpected user regrade requests. Expected: '%s', Actual: '%s'.", util.MustToJSONIndent(expected[0:1]), util.MustToJSONIndent(requests))
+	}
+
+	// Invalid requests cannot be saved.
+	err = UpsertRegradeRequest(assignment, &model.RegradeRequest{ID: "ccc", Status: model.REGRADE_STATUS_OPEN})
+	if err == nil {
+		test.Fatalf("Did not get an error when saving an invalid regrade request.")
+	}
+}
+
+func (this *DBTests) DBTestRegradeRequestsScoring(test *testing.T) {
+	ResetForTesting()
+	defer ResetForTesting()
+
+	email := "course-student@test.edulinq.org"
+	reference := &model.ParsedCourseUserReference{
+		Emails: map[string]any{email: nil},
+	}
+
+	assignment := MustGetTestAssignment()
+	resolveTime := timestamp.FromMSecs(300)
+
+	testCases := []struct {
+		status        model.RegradeStatus
+		question      string
+		adjustment    float64
+		expectedScore float64
+	}{
+		// Open requests do not change the score.
+		{model.REGRADE_STATUS_OPEN, "Q1", 0, 2},
+
+		{model.REGRADE_STATUS_RESOLVED, "Q1", -0.5, 1.5},
+		{model.REGRADE_STATUS_RESOLVED, "Q2", -1, 1},
+		{model.REGRADE_STATUS_RESOLVED, "Q1", 0, 2},
+
+		// Adjustments for questions that the submission does not have are skipped everywhere.
+		{model.REGRADE_STATUS_RESOLVED, "ZZZ", -1, 2},
+	}
+
+	for i, testCase := range testCases {
+		request := &model.RegradeRequest{
+			ID:            "aaa",
+			SubmissionID:  TEST_RUBRIC_SUBMISSION_ID,
+			User:          email,
+			Question:      testCase.question,
+			Justification: "Please.",
+			Status:        testCase.status,
+		}
+
+		if testCase.status == model.REGRADE_STATUS_RESOLVED {
+			request.ScoreAdjustment = testCase.adjustment
+			request.ResolvedBy = "course-grader@test.edulinq.org"
+			request.ResolveTime = &resolveTime
+		}
+
+		err := UpsertRegradeRequest(assignment, request)
+		if err != nil {
+			test.Errorf("Case %d: Failed to upsert regrade request: '%v'.", i, err)
+			continue
+		}
+
+		gradingInfo, err := GetSubmissionResult(assignment, email, "")
+		if err != nil {
+			test.Errorf("Case %d: Failed to get submission result: '%v'.", i, err)
+			continue
+		}
+
+		if gradingInfo.Score != testCase.expectedScore {
+			test.Errorf("Case %d: Unexpected submission result. Expected: %v, Actual: %v.", i, testCase.expectedScore, gradingInfo.Score)
+			continue
+		}
+
+		question := gradingInfo.GetQuestion(testCase.question)
+		if (question != nil) && (question.Score != (1 + testCase.adjustment)) {
+			test.Errorf("Case %d: Unexpected question score. Expected: %v, Actual: %v.", i, (1 + testCase.adjustment), question.Score)
+			continue
+		}
+
+		scoringInfos, err := GetScoringInfos(assignment, reference)
+		if err != nil {
+			test.Errorf("Case %d: Failed to get scoring infos: '%v'.", i, err)
+			continue
+		}
+
+		if scoringInfos[email].RawScore != testCase.expectedScore {
+			test.Errorf("Case %d: Unexpected scoring info. Expected: %v, Actual: '%s'.",
+				i, testCase.expectedScore, util.MustToJSONIndent(scoringInfos[email]))
+			continue
+		}
+
+		survey, err := GetRecentSubmissionSurvey(assignment, reference)
+		if err != nil {
+			test.Errorf("Case %d: Failed to get submission survey: '%v'.", i, err)
+			continue
+		}
+
+		if survey[email].Score != testCase.expectedScore {
+			test.Errorf("Case %d: Unexpected survey. Expected: %v, Actual: '%s'.",
+				i, testCase.expectedScore, util.MustToJSONIndent(survey[email]))
+			continue
+		}
+
+		history, err := GetSubmissionHistory(assignment, email)
+		if err != nil {
+			test.Errorf("Case %d: Failed to get submission history: '%v'.", i, err)
+			continue
+		}
+
+		lastItem := history[len(history)-1]
+		if lastItem.Score != testCase.expectedScore {
+			test.Errorf("Case %d: Unexpected history item. Expected: %v, Actual: '%s'.",
+				i, testCase.expectedScore, util.MustToJSONIndent(lastItem))
+			continue
+		}
+
+		contents, err := GetSubmissionContents(assignment, email, TEST_RUBRIC_SUBMISSION_ID)
+		if err != nil {
+			test.Errorf("Case %d: Failed to get submission contents: '%v'.", i, err)
+			continue
+		}
+
+		if contents.Info.Score != testCase.expectedScore {
+			test.Errorf("Case %d: Unexpected submission contents. Expected: %v, Actual: %v.", i, testCase.expectedScore, contents.Info.Score)
+			continue
+		}
+
+		recentContents, err := GetRecentSubmissionContents(assignment, reference)
+		if err != nil {
+			test.Errorf("Case %d: Failed to get recent submission contents: '%v'.", i, err)
+			continue
+		}
+
+		if recentContents[email].Info.Score != testCase.expectedScore {
+			test.Errorf("Case %d: Unexpected recent submission contents. Expected: %v, Actual: %v.",
+				i, testCase.expectedScore, recentContents[email].Info.Score)
+			continue
+		}
+
+		attempts, err := GetSubmissionAttempts(assignment, email)
+		if err != nil {
+			test.Errorf("Case %d: Failed to get submission attempts: '%v'.", i, err)
+			continue
+		}
+
+		lastAttempt := attempts[len(attempts)-1]
+		if lastAttempt.Info.Score != testCase.expectedScore {
+			test.Errorf("Case %d: Unexpected submission attempt. Expected: %v, Actual: %v.", i, testCase.expectedScore, lastAttempt.Info.Score)
+			continue
+		}
+
+		// Raw attempts never include manual grades.
+		rawAttempts, err := GetRawSubmissionAttempts(assignment, email)
+		if err != nil {
+			test.Errorf("Case %d: Failed to get raw submission attempts: '%v'.", i, err)
+			continue
+		}
+
+		lastRawAttempt := rawAttempts[len(rawAttempts)-1]
+		if lastRawAttempt.Info.Score != 2 {
+			test.Errorf("Case %d: Unexpected raw submission attempt. Expected: %v, Actual: %v.", i, 2, lastRawAttempt.Info.Score)
+			continue
+		}
+	}
+}
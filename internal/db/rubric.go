@@ -0,0 +1,71 @@
+package db
+
+import (
+	"fmt"
+
+	"github.com/edulinq/autograder/internal/model"
+)
+
+func GetRubricGrades(assignment *model.Assignment) (map[string]*model.RubricGrade, error) {
+	if backend == nil {
+		return nil, fmt.Errorf("Database has not been opened.")
+	}
+
+	return backend.GetRubricGrades(assignment)
+}
+
+// Get the rubric grade for a submission (by full submission ID).
+// Returns (nil, nil) if the submission has not been graded.
+func GetRubricGrade(assignment *model.Assignment, submissionID string) (*model.RubricGrade, error) {
+	grades, err := GetRubricGrades(assignment)
+	if err != nil {
+		return nil, err
+	}
+
+	return grades[submissionID], nil
+}
+
+func UpsertRubricGrade(assignment *model.Assignment, grade *model.RubricGrade) error {
+	err := grade.Validate(assignment.Rubric)
+	if err != nil {
+		return fmt.Errorf("Invalid rubric grade for submission '%s': '%w'.", grade.SubmissionID, err)
+	}
+
+	grades := map[string]*model.RubricGrade{
+		grade.SubmissionID: grade,
+	}
+
+	return UpsertRubricGrades(assignment, grades)
+}
+
+// Remove the rubric grade for a submission (by full submission ID).
+// Returns true if the grade existed.
+func RemoveRubricGrade(assignment *model.Assignment, submissionID string) (bool, error) {
+	grade, err := GetRubricGrade(assignment, submissionID)
+	if err != nil {
+		return false, err
+	}
+
+	if grade == nil {
+		return false, nil
+	}
+
+	grades := map[string]*model.RubricGrade{
+		submissionID: nil,
+	}
+
+	err = UpsertRubricGrades(assignment, grades)
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func UpsertRubricGrades(assignment *model.Assignment, grades map[string]*model.RubricGrade) error {
+	if backend == nil {
+		return fmt.Errorf("Database has not been opened.")
+	}
+
+	return backend.UpsertRubricGrades(assignment, grades)
+}
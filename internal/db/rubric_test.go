@@ -0,0 +1,197 @@
+package db
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/timestamp"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+const TEST_RUBRIC_SUBMISSION_ID = "course101::hw0::course-student@test.edulinq.org::1697406272"
+
+func (this *DBTests) DBTestRubricGradesBase(test *testing.T) {
+	ResetForTesting()
+	defer ResetForTesting()
+
+	assignment := MustSetTestRubric(TEST_COURSE_ID, TEST_ASSIGNMENT_ID)
+
+	grades, err := GetRubricGrades(assignment)
+	if err != nil {
+		test.Fatalf("Failed to fetch empty rubric grades: '%v'.", err)
+	}
+
+	if len(grades) != 0 {
+		test.Fatalf("Initial rubric grade fetch is not empty, found %d grades.", len(grades))
+	}
+
+	expected := map[string]*model.RubricGrade{
+		TEST_RUBRIC_SUBMISSION_ID: &model.RubricGrade{
+			CourseID:     TEST_COURSE_ID,
+			AssignmentID: TEST_ASSIGNMENT_ID,
+			SubmissionID: TEST_RUBRIC_SUBMISSION_ID,
+			User:         "course-student@test.edulinq.org",
+			Scores: map[string]*model.RubricCriterionScore{
+				"Style": &model.RubricCriterionScore{Score: 1.5, Comment: "Some long lines."},
+			},
+			GradedBy:   "course-grader@test.edulinq.org",
+			UpdateTime: timestamp.FromMSecs(100),
+		},
+	}
+
+	err = UpsertRubricGrade(assignment, expected[TEST_RUBRIC_SUBMISSION_ID])
+	if err != nil {
+		test.Fatalf("Failed to upsert rubric grade: '%v'.", err)
+	}
+
+	grades, err = GetRubricGrades(assignment)
+	if err != nil {
+		test.Fatalf("Failed to fetch rubric grades: '%v'.", err)
+	}
+
+	if !reflect.DeepEqual(expected, grades) {
+		test.Fatalf("Unexpected rubric grades. Expected: '%s', Actual: '%s'.", util.MustToJSONIndent(expected), util.MustToJSONIndent(grades))
+	}
+
+	// Grades that do not match the rubric cannot be saved.
+	invalidGrades := []*model.RubricGrade{
+		&model.RubricGrade{
+			SubmissionID: TEST_RUBRIC_SUBMISSION_ID,
+			Scores:       map[string]*model.RubricCriterionScore{"ZZZ": &model.RubricCriterionScore{Score: 1}},
+		},
+		&model.RubricGrade{
+			SubmissionID: TEST_RUBRIC_SUBMISSION_ID,
+			Scores:       map[string]*model.RubricCriterionScore{"Style": &model.RubricCriterionScore{Score: 3}},
+		},
+	}
+
+	for i, invalidGrade := range invalidGrades {
+		err = UpsertRubricGrade(assignment, invalidGrade)
+		if err == nil {
+			test.Fatalf("Case %d: Did not get an error when saving an invalid rubric grade.", i)
+		}
+	}
+
+	found, err := RemoveRubricGrade(assignment, TEST_RUBRIC_SUBMISSION_ID)
+	if err != nil {
+		test.Fatalf("Failed to remove rubric grade: '%v'.", err)
+	}
+
+	if !found {
+		test.Fatalf("Did not find rubric grade to remove.")
+	}
+
+	found, err = RemoveRubricGrade(assignment, TEST_RUBRIC_SUBMISSION_ID)
+	if err != nil {
+		test.Fatalf("Failed to remove missing rubric grade: '%v'.", err)
+	}
+
+	if found {
+		test.Fatalf("Found rubric grade that was already removed.")
+	}
+}
+
+func (this *DBTests) DBTestRubricGradesScoring(test *testing.T) {
+	ResetForTesting()
+	defer ResetForTesting()
+
+	email := "course-student@test.edulinq.org"
+	reference := &model.ParsedCourseUserReference{
+		Emails: map[string]any{email: nil},
+	}
+
+	assignment := MustSetTestRubric(TEST_COURSE_ID, TEST_ASSIGNMENT_ID)
+
+	testCases := []struct {
+		scores             map[string]*model.RubricCriterionScore
+		expectedScore      float64
+		expectedIncomplete bool
+	}{
+		// Not graded at all.
+		{nil, 2, true},
+
+		// Partially graded.
+		{map[string]*model.RubricCriterionScore{"Style": &model.RubricCriterionScore{Score: 1}}, 3, true},
+
+		// Fully graded.
+		{
+			map[string]*model.RubricCriterionScore{
+				"Style":  &model.RubricCriterionScore{Score: 1},
+				"Design": &model.RubricCriterionScore{Score: 2.5},
+			},
+			5.5,
+			false,
+		},
+	}
+
+	for i, testCase := range testCases {
+		if testCase.scores != nil {
+			grade := &model.RubricGrade{
+				SubmissionID: TEST_RUBRIC_SUBMISSION_ID,
+				User:         email,
+				Scores:       testCase.scores,
+			}
+
+			err := UpsertRubricGrade(assignment, grade)
+			if err != nil {
+				test.Errorf("Case %d: Failed to upsert rubric grade: '%v'.", i, err)
+				continue
+			}
+		}
+
+		gradingInfo, err := GetSubmissionResult(assignment, email, "")
+		if err != nil {
+			test.Errorf("Case %d: Failed to get submission result: '%v'.", i, err)
+			continue
+		}
+
+		if (gradingInfo.Score != testCase.expectedScore) || (gradingInfo.MaxPoints != 7) || (gradingInfo.RubricIncomplete != testCase.expectedIncomplete) {
+			test.Errorf("Case %d: Unexpected submission result. Expected: %v / 7 (incomplete: %v), Actual: %v / %v (incomplete: %v).",
+				i, testCase.expectedScore, testCase.expectedIncomplete, gradingInfo.Score, gradingInfo.MaxPoints, gradingInfo.RubricIncomplete)
+			continue
+		}
+
+		if len(gradingInfo.Questions) != 4 {
+			test.Errorf("Case %d: Unexpected number of questions. Expected: 4, Actual: %d.", i, len(gradingInfo.Questions))
+			continue
+		}
+
+		survey, err := GetRecentSubmissionSurvey(assignment, reference)
+		if err != nil {
+			test.Errorf("Case %d: Failed to get submission survey: '%v'.", i, err)
+			continue
+		}
+
+		if (survey[email].Score != testCase.expectedScore) || (survey[email].RubricIncomplete != testCase.expectedIncomplete) {
+			test.Errorf("Case %d: Unexpected survey. Expected: %v (incomplete: %v), Actual: '%s'.",
+				i, testCase.expectedScore, testCase.expectedIncomplete, util.MustToJSONIndent(survey[email]))
+			continue
+		}
+
+		scoringInfos, err := GetScoringInfos(assignment, reference)
+		if err != nil {
+			test.Errorf("Case %d: Failed to get scoring infos: '%v'.", i, err)
+			continue
+		}
+
+		if (scoringInfos[email].RawScore != testCase.expectedScore) || (scoringInfos[email].RubricIncomplete != testCase.expectedIncomplete) {
+			test.Errorf("Case %d: Unexpected scoring info. Expected: %v (incomplete: %v), Actual: '%s'.",
+				i, testCase.expectedScore, testCase.expectedIncomplete, util.MustToJSONIndent(scoringInfos[email]))
+			continue
+		}
+
+		history, err := GetSubmissionHistory(assignment, email)
+		if err != nil {
+			test.Errorf("Case %d: Failed to get submission history: '%v'.", i, err)
+			continue
+		}
+
+		lastItem := history[len(history)-1]
+		if (lastItem.Score != testCase.expectedScore) || (lastItem.MaxPoints != 7) || (lastItem.RubricIncomplete != testCase.expectedIncomplete) {
+			test.Errorf("Case %d: Unexpected history item. Expected: %v / 7 (incomplete: %v), Actual: '%s'.",
+				i, testCase.expectedScore, testCase.expectedIncomplete, util.MustToJSONIndent(lastItem))
+			continue
+		}
+	}
+}
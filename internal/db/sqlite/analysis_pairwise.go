@@ -0,0 +1,128 @@
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+	"slices"
+
+	"github.com/edulinq/autograder/internal/log"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func (this *backend) GetPairwiseAnalysis(keys []model.PairwiseKey) (map[model.PairwiseKey]*model.PairwiseAnalysis, error) {
+	keysJSON, err := pairwiseKeysJSON(keys)
+	if err != nil {
+		return nil, err
+	}
+
+	records, err := this.getPairwiseAnalysis(`WHERE key IN (SELECT value FROM json_each(?))`, keysJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make(map[model.PairwiseKey]*model.PairwiseAnalysis, len(records))
+	for _, record := range records {
+		results[record.SubmissionIDs] = record
+	}
+
+	return results, nil
+}
+
+func (this *backend) GetCoursePairwiseAnalysis(courseID string) ([]*model.PairwiseAnalysis, error) {
+	records, err := this.getPairwiseAnalysis(`WHERE course_id = ?`, courseID)
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(records, func(a *model.PairwiseAnalysis, b *model.PairwiseAnalysis) int {
+		return model.ComparePairwiseKey(a.SubmissionIDs, b.SubmissionIDs)
+	})
+
+	return records, nil
+}
+
+func (this *backend) StorePairwiseAnalysis(allRecords []*model.PairwiseAnalysis) error {
+	records := make([]*model.PairwiseAnalysis, 0, len(allRecords))
+	for _, record := range allRecords {
+		if record.SubmissionIDs.Course() == "" {
+			// This would be a bit strange, just log and skip it.
+			log.Warn("Found empty course ID in pairwise analysis.", log.NewAttr("record", record))
+			continue
+		}
+
+		records = append(records, record)
+	}
+
+	return this.withTransaction(func(tx *sql.Tx) error {
+		for _, record := range records {
+			data, err := util.ToJSON(record)
+			if err != nil {
+				return fmt.Errorf("Failed to serialize pairwise analysis for '%s': '%w'.", record.SubmissionIDs.String(), err)
+			}
+
+			_, err = tx.Exec(`INSERT INTO analysis_pairwise (key, course_id, data) VALUES (?, ?, ?)
+					ON CONFLICT (key) DO UPDATE SET course_id = excluded.course_id, data = excluded.data`,
+				record.SubmissionIDs.String(), record.SubmissionIDs.Course(), data)
+			if err != nil {
+				return fmt.Errorf("Failed to store pairwise analysis for '%s': '%w'.", record.SubmissionIDs.String(), err)
+			}
+		}
+
+		return nil
+	})
+}
+
+func (this *backend) RemovePairwiseAnalysis(keys []model.PairwiseKey) error {
+	keysJSON, err := pairwiseKeysJSON(keys)
+	if err != nil {
+		return err
+	}
+
+	_, err = this.db.Exec(`DELETE FROM analysis_pairwise WHERE key IN (SELECT value FROM json_each(?))`, keysJSON)
+	if err != nil {
+		return fmt.Errorf("Failed to remove pairwise analysis: '%w'.", err)
+	}
+
+	return nil
+}
+
+func (this *backend) getPairwiseAnalysis(whereClause string, args ...any) ([]*model.PairwiseAnalysis, error) {
+	rows, err := this.db.Query(`SELECT data FROM analysis_pairwise `+whereClause, args...)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to query pairwise analysis: '%w'.", err)
+	}
+
+	dataJSONs, err := collectRows[string](rows)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read pairwise analysis: '%w'.", err)
+	}
+
+	records := make([]*model.PairwiseAnalysis, 0, len(dataJSONs))
+	for _, data := range dataJSONs {
+		var record model.PairwiseAnalysis
+		err = util.JSONFromString(data, &record)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to deserialize pairwise analysis: '%w'.", err)
+		}
+
+		records = append(records, &record)
+	}
+
+	return records, nil
+}
+
+// Get a JSON array of the string form of each key (for use with json_each()).
+func pairwiseKeysJSON(keys []model.PairwiseKey) (string, error) {
+	keyStrings := make([]string, 0, len(keys))
+	for _, key := range keys {
+		keyStrings = append(keyStrings, key.String())
+	}
+
+	keysJSON, err := util.ToJSON(keyStrings)
+	if err != nil {
+		return "", fmt.Errorf("Failed to serialize pairwise keys: '%w'.", err)
+	}
+
+	return keysJSON, nil
+}
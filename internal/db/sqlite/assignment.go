@@ -0,0 +1,45 @@
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func (this *backend) SaveAssignment(assignment *model.Assignment) error {
+	return this.withTransaction(func(tx *sql.Tx) error {
+		return saveAssignment(tx, assignment)
+	})
+}
+
+func saveAssignment(tx *sql.Tx, assignment *model.Assignment) error {
+	data, err := util.ToJSON(assignment)
+	if err != nil {
+		return fmt.Errorf("Failed to serialize assignment '%s': '%w'.", assignment.FullID(), err)
+	}
+
+	_, err = tx.Exec(`INSERT INTO assignments (course_id, id, data) VALUES (?, ?, ?)
+			ON CONFLICT (course_id, id) DO UPDATE SET data = excluded.data`,
+		assignment.GetCourse().GetID(), assignment.GetID(), data)
+	if err != nil {
+		return fmt.Errorf("Failed to save assignment '%s': '%w'.", assignment.FullID(), err)
+	}
+
+	return nil
+}
+
+func (this *backend) getAssignmentJSONs(courseID string) ([]string, error) {
+	rows, err := this.db.Query(`SELECT data FROM assignments WHERE course_id = ? ORDER BY id`, courseID)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to query assignments for course '%s': '%w'.", courseID, err)
+	}
+
+	assignmentJSONs, err := collectRows[string](rows)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read assignments for course '%s': '%w'.", courseID, err)
+	}
+
+	return assignmentJSONs, nil
+}
@@ -0,0 +1,203 @@
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+	"path/filepath"
+
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+const DUMP_ASSIGNMENTS_DIRNAME = "assignments"
+
+func (this *backend) ClearCourse(course *model.Course) error {
+	return this.withTransaction(func(tx *sql.Tx) error {
+		statements := []string{
+			`DELETE FROM courses WHERE id = ?`,
+			`DELETE FROM submissions WHERE course_id = ?`,
+			`DELETE FROM assignment_groups WHERE course_id = ?`,
+			`DELETE FROM assignment_extensions WHERE course_id = ?`,
+			`DELETE FROM rubric_grades WHERE course_id = ?`,
+			`DELETE FROM regrade_requests WHERE course_id = ?`,
+			`DELETE FROM analysis_individual WHERE course_id = ?`,
+			`DELETE FROM analysis_pairwise WHERE course_id = ?`,
+		}
+
+		for _, statement := range statements {
+			_, err := tx.Exec(statement, course.GetID())
+			if err != nil {
+				return fmt.Errorf("Failed to clear course '%s': '%w'.", course.GetID(), err)
+			}
+		}
+
+		users, err := getServerUsers(tx, courseUsersWhereClause, course.GetID())
+		if err != nil {
+			return fmt.Errorf("Failed to get users to drop from removed course: '%w'.", err)
+		}
+
+		for _, user := range users {
+			delete(user.CourseInfo, course.GetID())
+
+			err = saveServerUser(tx, user)
+			if err != nil {
+				return fmt.Errorf("Failed to drop users from removed course: '%w'.", err)
+			}
+		}
+
+		return nil
+	})
+}
+
+func (this *backend) AddTestCourse(path string) (*model.Course, error) {
+	course, submissions, err := model.FullLoadCourseFromPath(util.ShouldAbs(path), true)
+	if err != nil {
+		return nil, err
+	}
+
+	err = this.withTransaction(func(tx *sql.Tx) error {
+		err := saveCourse(tx, course)
+		if err != nil {
+			return err
+		}
+
+		return saveSubmissions(tx, submissions)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return course, nil
+}
+
+func (this *backend) SaveCourse(course *model.Course) error {
+	return this.withTransaction(func(tx *sql.Tx) error {
+		return saveCourse(tx, course)
+	})
+}
+
+func saveCourse(tx *sql.Tx, course *model.Course) error {
+	data, err := util.ToJSON(course)
+	if err != nil {
+		return fmt.Errorf("Failed to serialize course '%s': '%w'.", course.GetID(), err)
+	}
+
+	_, err = tx.Exec(`INSERT INTO courses (id, data) VALUES (?, ?) ON CONFLICT (id) DO UPDATE SET data = excluded.data`,
+		course.GetID(), data)
+	if err != nil {
+		return fmt.Errorf("Failed to save course '%s': '%w'.", course.GetID(), err)
+	}
+
+	for _, assignment := range course.Assignments {
+		err = saveAssignment(tx, assignment)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (this *backend) DumpCourse(course *model.Course, targetDir string) error {
+	err := util.ToJSONFileIndent(course, filepath.Join(targetDir, model.COURSE_CONFIG_FILENAME))
+	if err != nil {
+		return fmt.Errorf("Failed to dump course config for '%s': '%w'.", course.GetID(), err)
+	}
+
+	for _, assignment := range course.Assignments {
+		path := filepath.Join(targetDir, DUMP_ASSIGNMENTS_DIRNAME, assignment.GetID(), model.ASSIGNMENT_CONFIG_FILENAME)
+
+		err = util.MkDir(filepath.Dir(path))
+		if err != nil {
+			return fmt.Errorf("Failed to make assignment dump dir '%s': '%w'.", filepath.Dir(path), err)
+		}
+
+		err = util.ToJSONFileIndent(assignment, path)
+		if err != nil {
+			return fmt.Errorf("Failed to dump assignment config for '%s': '%w'.", assignment.FullID(), err)
+		}
+
+		submissions, err := this.getSubmissions(`WHERE course_id = ? AND assignment_id = ?`, true, course.GetID(), assignment.GetID())
+		if err != nil {
+			return fmt.Errorf("Failed to get submissions to dump for '%s': '%w'.", assignment.FullID(), err)
+		}
+
+		for _, submission := range submissions {
+			submissionDir := filepath.Join(targetDir, model.SUBMISSIONS_DIRNAME, assignment.GetID(), submission.Info.User, submission.Info.ShortID)
+
+			err = model.WriteGradingResult(submission, submissionDir)
+			if err != nil {
+				return fmt.Errorf("Failed to dump submission '%s': '%w'.", submission.Info.ID, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (this *backend) GetCourse(courseID string) (*model.Course, error) {
+	courses, err := this.getCourses(`WHERE id = ?`, courseID)
+	if err != nil {
+		return nil, err
+	}
+
+	return courses[courseID], nil
+}
+
+func (this *backend) GetCourses() (map[string]*model.Course, error) {
+	return this.getCourses("")
+}
+
+func (this *backend) getCourses(whereClause string, args ...any) (map[string]*model.Course, error) {
+	courses := make(map[string]*model.Course)
+
+	courseJSONs, err := this.getCourseJSONs(whereClause, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	for courseID, courseJSON := range courseJSONs {
+		assignmentJSONs, err := this.getAssignmentJSONs(courseID)
+		if err != nil {
+			return nil, err
+		}
+
+		course, err := model.LoadCourseFromJSON(courseJSON, assignmentJSONs)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to load course '%s': '%w'.", courseID, err)
+		}
+
+		courses[course.GetID()] = course
+	}
+
+	return courses, nil
+}
+
+// Get the raw JSON for each matching course, keyed by course ID.
+func (this *backend) getCourseJSONs(whereClause string, args ...any) (map[string]string, error) {
+	rows, err := this.db.Query(`SELECT id, data FROM courses `+whereClause, args...)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to query courses: '%w'.", err)
+	}
+	defer rows.Close()
+
+	courseJSONs := make(map[string]string)
+	for rows.Next() {
+		var id string
+		var data string
+
+		err = rows.Scan(&id, &data)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to read course: '%w'.", err)
+		}
+
+		courseJSONs[id] = data
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read courses: '%w'.", err)
+	}
+
+	return courseJSONs, nil
+}
@@ -0,0 +1,133 @@
+// A database backend that stores all data in a single SQLite file.
+// Meant for small deployments that want transactional storage without running a database server.
+// Transactions (instead of in-process locks) are used to keep operations consistent.
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/url"
+	"path/filepath"
+
+	_ "modernc.org/sqlite"
+
+	"github.com/edulinq/autograder/internal/config"
+	"github.com/edulinq/autograder/internal/log"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+const DB_FILENAME = "sqlite-database.db"
+
+// Options passed to every connection.
+// Transactions immediately take the write lock so that concurrent writers wait (instead of failing) when upgrading locks.
+const DB_CONNECTION_OPTIONS = "_pragma=foreign_keys(1)&_pragma=busy_timeout(10000)&_pragma=journal_mode(WAL)&_txlock=immediate"
+
+type backend struct {
+	db   *sql.DB
+	path string
+}
+
+func Open() (*backend, error) {
+	path := util.ShouldAbs(filepath.Join(config.GetDatabaseDir(), DB_FILENAME))
+
+	err := util.MkDir(filepath.Dir(path))
+	if err != nil {
+		return nil, fmt.Errorf("Failed to make db dir '%s': '%w'.", filepath.Dir(path), err)
+	}
+
+	uri := (&url.URL{Scheme: "file", Path: path, RawQuery: DB_CONNECTION_OPTIONS}).String()
+
+	db, err := sql.Open("sqlite", uri)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to open SQLite database at '%s': '%w'.", path, err)
+	}
+
+	err = db.Ping()
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("Failed to connect to SQLite database at '%s': '%w'.", path, err)
+	}
+
+	log.Debug("Opened SQLite database.", log.NewAttr("path", path))
+
+	return &backend{db: db, path: path}, nil
+}
+
+func (this *backend) Close() error {
+	return this.db.Close()
+}
+
+func (this *backend) EnsureTables() error {
+	return this.withTransaction(func(tx *sql.Tx) error {
+		for i, statement := range SCHEMA_STATEMENTS {
+			_, err := tx.Exec(statement)
+			if err != nil {
+				return fmt.Errorf("Failed to execute schema statement %d: '%w'.", i, err)
+			}
+		}
+
+		return nil
+	})
+}
+
+func (this *backend) Clear() error {
+	return this.withTransaction(func(tx *sql.Tx) error {
+		for _, tableName := range TABLE_NAMES {
+			_, err := tx.Exec(fmt.Sprintf("DELETE FROM %s", tableName))
+			if err != nil {
+				return fmt.Errorf("Failed to clear table '%s': '%w'.", tableName, err)
+			}
+		}
+
+		return nil
+	})
+}
+
+// Run the given function inside a transaction.
+// The transaction will be committed if the function returns nil and rolled back otherwise.
+// Nothing inside the function should use the database outside of the transaction (including logging),
+// since that would wait on the transaction's own write lock.
+func (this *backend) withTransaction(function func(tx *sql.Tx) error) error {
+	tx, err := this.db.BeginTx(context.Background(), nil)
+	if err != nil {
+		return fmt.Errorf("Failed to begin transaction: '%w'.", err)
+	}
+
+	err = function(tx)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("Failed to commit transaction: '%w'.", err)
+	}
+
+	return nil
+}
+
+// The common interface between a database and transaction used for reads.
+type querier interface {
+	Query(query string, args ...any) (*sql.Rows, error)
+}
+
+// Collect all the values from single-column rows.
+// The rows will always be closed.
+func collectRows[T any](rows *sql.Rows) ([]T, error) {
+	defer rows.Close()
+
+	results := make([]T, 0)
+	for rows.Next() {
+		var value T
+		err := rows.Scan(&value)
+		if err != nil {
+			return nil, err
+		}
+
+		results = append(results, value)
+	}
+
+	return results, rows.Err()
+}
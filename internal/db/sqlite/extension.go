@@ -0,0 +1,78 @@
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func (this *backend) GetAssignmentExtensions(assignment *model.Assignment) (map[string]*model.AssignmentExtension, error) {
+	rows, err := this.db.Query(`SELECT email, data FROM assignment_extensions WHERE course_id = ? AND assignment_id = ?`,
+		assignment.GetCourse().GetID(), assignment.GetID())
+	if err != nil {
+		return nil, fmt.Errorf("Failed to query assignment extensions: '%w'.", err)
+	}
+	defer rows.Close()
+
+	extensions := make(map[string]*model.AssignmentExtension)
+
+	for rows.Next() {
+		var email string
+		var data string
+
+		err = rows.Scan(&email, &data)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to read assignment extension: '%w'.", err)
+		}
+
+		var extension model.AssignmentExtension
+		err = util.JSONFromString(data, &extension)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to deserialize assignment extension '%s': '%w'.", email, err)
+		}
+
+		extensions[email] = &extension
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read assignment extensions: '%w'.", err)
+	}
+
+	return extensions, nil
+}
+
+func (this *backend) UpsertAssignmentExtensions(assignment *model.Assignment, upsertExtensions map[string]*model.AssignmentExtension) error {
+	courseID := assignment.GetCourse().GetID()
+	assignmentID := assignment.GetID()
+
+	return this.withTransaction(func(tx *sql.Tx) error {
+		for email, upsertExtension := range upsertExtensions {
+			if upsertExtension == nil {
+				_, err := tx.Exec(`DELETE FROM assignment_extensions WHERE course_id = ? AND assignment_id = ? AND email = ?`,
+					courseID, assignmentID, email)
+				if err != nil {
+					return fmt.Errorf("Failed to remove assignment extension '%s': '%w'.", email, err)
+				}
+
+				continue
+			}
+
+			data, err := util.ToJSON(upsertExtension)
+			if err != nil {
+				return fmt.Errorf("Failed to serialize assignment extension '%s': '%w'.", email, err)
+			}
+
+			_, err = tx.Exec(`INSERT INTO assignment_extensions (course_id, assignment_id, email, data) VALUES (?, ?, ?, ?)
+					ON CONFLICT (course_id, assignment_id, email) DO UPDATE SET data = excluded.data`,
+				courseID, assignmentID, email, data)
+			if err != nil {
+				return fmt.Errorf("Failed to upsert assignment extension '%s': '%w'.", email, err)
+			}
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,118 @@
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func (this *backend) GetGradingTicket(ticketID string) (*model.GradingTicket, error) {
+	tickets, err := this.getGradingTickets(`WHERE id = ?`, ticketID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(tickets) == 0 {
+		return nil, nil
+	}
+
+	return tickets[0], nil
+}
+
+func (this *backend) GetActiveGradingTickets() ([]*model.GradingTicket, error) {
+	return this.getGradingTickets(`WHERE status != ? ORDER BY queue_time, id`, string(model.GradingTicketStatusDone))
+}
+
+func (this *backend) GetDoneGradingTickets() ([]*model.GradingTicket, error) {
+	return this.getGradingTickets(`WHERE status = ? ORDER BY queue_time, id`, string(model.GradingTicketStatusDone))
+}
+
+func (this *backend) GetUserGradingTickets(email string) ([]*model.GradingTicket, error) {
+	tickets, err := this.getGradingTickets(`ORDER BY queue_time, id`)
+	if err != nil {
+		return nil, err
+	}
+
+	return filterUserGradingTickets(tickets, email), nil
+}
+
+func (this *backend) UpsertGradingTickets(upsertTickets map[string]*model.GradingTicket) error {
+	return this.withTransaction(func(tx *sql.Tx) error {
+		for ticketID, upsertTicket := range upsertTickets {
+			if upsertTicket == nil {
+				_, err := tx.Exec(`DELETE FROM grading_queue WHERE id = ?`, ticketID)
+				if err != nil {
+					return fmt.Errorf("Failed to remove grading ticket '%s': '%w'.", ticketID, err)
+				}
+
+				continue
+			}
+
+			data, err := util.ToJSON(upsertTicket)
+			if err != nil {
+				return fmt.Errorf("Failed to serialize grading ticket '%s': '%w'.", ticketID, err)
+			}
+
+			_, err = tx.Exec(`INSERT INTO grading_queue (id, status, queue_time, data) VALUES (?, ?, ?, ?)
+					ON CONFLICT (id) DO UPDATE SET
+						status = excluded.status,
+						queue_time = excluded.queue_time,
+						data = excluded.data`,
+				ticketID, string(upsertTicket.Status), int64(upsertTicket.QueueTime), data)
+			if err != nil {
+				return fmt.Errorf("Failed to upsert grading ticket '%s': '%w'.", ticketID, err)
+			}
+		}
+
+		return nil
+	})
+}
+
+func (this *backend) getGradingTickets(clause string, args ...any) ([]*model.GradingTicket, error) {
+	rows, err := this.db.Query(`SELECT id, data FROM grading_queue `+clause, args...)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to query grading tickets: '%w'.", err)
+	}
+	defer rows.Close()
+
+	tickets := make([]*model.GradingTicket, 0)
+
+	for rows.Next() {
+		var id string
+		var data string
+
+		err = rows.Scan(&id, &data)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to read grading ticket: '%w'.", err)
+		}
+
+		var ticket model.GradingTicket
+		err = util.JSONFromString(data, &ticket)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to deserialize grading ticket '%s': '%w'.", id, err)
+		}
+
+		tickets = append(tickets, &ticket)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read grading tickets: '%w'.", err)
+	}
+
+	return tickets, nil
+}
+
+// Ticket data is only stored as JSON, so users must be matched after deserialization.
+func filterUserGradingTickets(tickets []*model.GradingTicket, email string) []*model.GradingTicket {
+	userTickets := make([]*model.GradingTicket, 0)
+	for _, ticket := range tickets {
+		if ticket.User == email {
+			userTickets = append(userTickets, ticket)
+		}
+	}
+
+	return userTickets
+}
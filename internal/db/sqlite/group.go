@@ -0,0 +1,78 @@
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func (this *backend) GetAssignmentGroups(assignment *model.Assignment) (map[string]*model.AssignmentGroup, error) {
+	rows, err := this.db.Query(`SELECT id, data FROM assignment_groups WHERE course_id = ? AND assignment_id = ?`,
+		assignment.GetCourse().GetID(), assignment.GetID())
+	if err != nil {
+		return nil, fmt.Errorf("Failed to query assignment groups: '%w'.", err)
+	}
+	defer rows.Close()
+
+	groups := make(map[string]*model.AssignmentGroup)
+
+	for rows.Next() {
+		var id string
+		var data string
+
+		err = rows.Scan(&id, &data)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to read assignment group: '%w'.", err)
+		}
+
+		var group model.AssignmentGroup
+		err = util.JSONFromString(data, &group)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to deserialize assignment group '%s': '%w'.", id, err)
+		}
+
+		groups[id] = &group
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read assignment groups: '%w'.", err)
+	}
+
+	return groups, nil
+}
+
+func (this *backend) UpsertAssignmentGroups(assignment *model.Assignment, upsertGroups map[string]*model.AssignmentGroup) error {
+	courseID := assignment.GetCourse().GetID()
+	assignmentID := assignment.GetID()
+
+	return this.withTransaction(func(tx *sql.Tx) error {
+		for groupID, upsertGroup := range upsertGroups {
+			if upsertGroup == nil {
+				_, err := tx.Exec(`DELETE FROM assignment_groups WHERE course_id = ? AND assignment_id = ? AND id = ?`,
+					courseID, assignmentID, groupID)
+				if err != nil {
+					return fmt.Errorf("Failed to remove assignment group '%s': '%w'.", groupID, err)
+				}
+
+				continue
+			}
+
+			data, err := util.ToJSON(upsertGroup)
+			if err != nil {
+				return fmt.Errorf("Failed to serialize assignment group '%s': '%w'.", groupID, err)
+			}
+
+			_, err = tx.Exec(`INSERT INTO assignment_groups (course_id, assignment_id, id, data) VALUES (?, ?, ?, ?)
+					ON CONFLICT (course_id, assignment_id, id) DO UPDATE SET data = excluded.data`,
+				courseID, assignmentID, groupID, data)
+			if err != nil {
+				return fmt.Errorf("Failed to upsert assignment group '%s': '%w'.", groupID, err)
+			}
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,61 @@
+package sqlite
+
+import (
+	"fmt"
+
+	"github.com/edulinq/autograder/internal/log"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func (this *backend) LogDirect(record *log.Record) error {
+	data, err := util.ToJSON(record)
+	if err != nil {
+		return fmt.Errorf("Failed to serialize log record: '%w'.", err)
+	}
+
+	_, err = this.db.Exec(`INSERT INTO logs (level, timestamp, course_id, assignment_id, user_email, data) VALUES (?, ?, ?, ?, ?, ?)`,
+		int64(record.Level), int64(record.Timestamp), record.Course, record.Assignment, record.User, data)
+	if err != nil {
+		return fmt.Errorf("Failed to store log record: '%w'.", err)
+	}
+
+	return nil
+}
+
+func (this *backend) GetLogRecords(query log.ParsedLogQuery) ([]*log.Record, error) {
+	// Do a coarse filter in the database, and then use the query's own matching for the exact semantics.
+	rows, err := this.db.Query(`SELECT data FROM logs WHERE level >= ? AND timestamp >= ? ORDER BY id`,
+		int64(query.Level), int64(query.After))
+	if err != nil {
+		return nil, fmt.Errorf("Failed to query log records: '%w'.", err)
+	}
+
+	dataJSONs, err := collectRows[string](rows)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read log records: '%w'.", err)
+	}
+
+	records := make([]*log.Record, 0)
+	for _, data := range dataJSONs {
+		var record log.Record
+		err = util.JSONFromString(data, &record)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to deserialize log record: '%w'.", err)
+		}
+
+		if query.Match(&record) {
+			records = append(records, &record)
+		}
+	}
+
+	return records, nil
+}
+
+func (this *backend) RemoveUserLogRecords(email string) error {
+	_, err := this.db.Exec(`DELETE FROM logs WHERE user_email = ?`, email)
+	if err != nil {
+		return fmt.Errorf("Failed to remove log records for user '%s': '%w'.", email, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,79 @@
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func (this *backend) GetLoginThrottles(keys []string) (map[string]*model.LoginThrottle, error) {
+	keysJSON, err := util.ToJSON(keys)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to serialize login throttle keys: '%w'.", err)
+	}
+
+	rows, err := this.db.Query(`SELECT key, data FROM login_throttles WHERE key IN (SELECT value FROM json_each(?))`, keysJSON)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to query login throttles: '%w'.", err)
+	}
+	defer rows.Close()
+
+	throttles := make(map[string]*model.LoginThrottle, len(keys))
+
+	for rows.Next() {
+		var key string
+		var data string
+
+		err = rows.Scan(&key, &data)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to read login throttle: '%w'.", err)
+		}
+
+		var throttle model.LoginThrottle
+		err = util.JSONFromString(data, &throttle)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to deserialize login throttle '%s': '%w'.", key, err)
+		}
+
+		throttles[key] = &throttle
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read login throttles: '%w'.", err)
+	}
+
+	return throttles, nil
+}
+
+func (this *backend) UpsertLoginThrottles(upsertThrottles map[string]*model.LoginThrottle) error {
+	return this.withTransaction(func(tx *sql.Tx) error {
+		for key, upsertThrottle := range upsertThrottles {
+			if upsertThrottle == nil {
+				_, err := tx.Exec(`DELETE FROM login_throttles WHERE key = ?`, key)
+				if err != nil {
+					return fmt.Errorf("Failed to remove login throttle '%s': '%w'.", key, err)
+				}
+
+				continue
+			}
+
+			data, err := util.ToJSON(upsertThrottle)
+			if err != nil {
+				return fmt.Errorf("Failed to serialize login throttle '%s': '%w'.", key, err)
+			}
+
+			_, err = tx.Exec(`INSERT INTO login_throttles (key, data) VALUES (?, ?)
+					ON CONFLICT (key) DO UPDATE SET
+						data = excluded.data`,
+				key, data)
+			if err != nil {
+				return fmt.Errorf("Failed to upsert login throttle '%s': '%w'.", key, err)
+			}
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,78 @@
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func (this *backend) GetRegradeRequests(assignment *model.Assignment) (map[string]*model.RegradeRequest, error) {
+	rows, err := this.db.Query(`SELECT id, data FROM regrade_requests WHERE course_id = ? AND assignment_id = ?`,
+		assignment.GetCourse().GetID(), assignment.GetID())
+	if err != nil {
+		return nil, fmt.Errorf("Failed to query regrade requests: '%w'.", err)
+	}
+	defer rows.Close()
+
+	requests := make(map[string]*model.RegradeRequest)
+
+	for rows.Next() {
+		var requestID string
+		var data string
+
+		err = rows.Scan(&requestID, &data)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to read regrade request: '%w'.", err)
+		}
+
+		var request model.RegradeRequest
+		err = util.JSONFromString(data, &request)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to deserialize regrade request '%s': '%w'.", requestID, err)
+		}
+
+		requests[requestID] = &request
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read regrade requests: '%w'.", err)
+	}
+
+	return requests, nil
+}
+
+func (this *backend) UpsertRegradeRequests(assignment *model.Assignment, upsertRequests map[string]*model.RegradeRequest) error {
+	courseID := assignment.GetCourse().GetID()
+	assignmentID := assignment.GetID()
+
+	return this.withTransaction(func(tx *sql.Tx) error {
+		for requestID, upsertRequest := range upsertRequests {
+			if upsertRequest == nil {
+				_, err := tx.Exec(`DELETE FROM regrade_requests WHERE course_id = ? AND assignment_id = ? AND id = ?`,
+					courseID, assignmentID, requestID)
+				if err != nil {
+					return fmt.Errorf("Failed to remove regrade request '%s': '%w'.", requestID, err)
+				}
+
+				continue
+			}
+
+			data, err := util.ToJSON(upsertRequest)
+			if err != nil {
+				return fmt.Errorf("Failed to serialize regrade request '%s': '%w'.", requestID, err)
+			}
+
+			_, err = tx.Exec(`INSERT INTO regrade_requests (course_id, assignment_id, id, data) VALUES (?, ?, ?, ?)
+					ON CONFLICT (course_id, assignment_id, id) DO UPDATE SET data = excluded.data`,
+				courseID, assignmentID, requestID, data)
+			if err != nil {
+				return fmt.Errorf("Failed to upsert regrade request '%s': '%w'.", requestID, err)
+			}
+		}
+
+		return nil
+	})
+}
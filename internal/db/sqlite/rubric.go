@@ -0,0 +1,78 @@
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func (this *backend) GetRubricGrades(assignment *model.Assignment) (map[string]*model.RubricGrade, error) {
+	rows, err := this.db.Query(`SELECT submission_id, data FROM rubric_grades WHERE course_id = ? AND assignment_id = ?`,
+		assignment.GetCourse().GetID(), assignment.GetID())
+	if err != nil {
+		return nil, fmt.Errorf("Failed to query rubric grades: '%w'.", err)
+	}
+	defer rows.Close()
+
+	grades := make(map[string]*model.RubricGrade)
+
+	for rows.Next() {
+		var submissionID string
+		var data string
+
+		err = rows.Scan(&submissionID, &data)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to read rubric grade: '%w'.", err)
+		}
+
+		var grade model.RubricGrade
+		err = util.JSONFromString(data, &grade)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to deserialize rubric grade '%s': '%w'.", submissionID, err)
+		}
+
+		grades[submissionID] = &grade
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read rubric grades: '%w'.", err)
+	}
+
+	return grades, nil
+}
+
+func (this *backend) UpsertRubricGrades(assignment *model.Assignment, upsertGrades map[string]*model.RubricGrade) error {
+	courseID := assignment.GetCourse().GetID()
+	assignmentID := assignment.GetID()
+
+	return this.withTransaction(func(tx *sql.Tx) error {
+		for submissionID, upsertGrade := range upsertGrades {
+			if upsertGrade == nil {
+				_, err := tx.Exec(`DELETE FROM rubric_grades WHERE course_id = ? AND assignment_id = ? AND submission_id = ?`,
+					courseID, assignmentID, submissionID)
+				if err != nil {
+					return fmt.Errorf("Failed to remove rubric grade '%s': '%w'.", submissionID, err)
+				}
+
+				continue
+			}
+
+			data, err := util.ToJSON(upsertGrade)
+			if err != nil {
+				return fmt.Errorf("Failed to serialize rubric grade '%s': '%w'.", submissionID, err)
+			}
+
+			_, err = tx.Exec(`INSERT INTO rubric_grades (course_id, assignment_id, submission_id, data) VALUES (?, ?, ?, ?)
+					ON CONFLICT (course_id, assignment_id, submission_id) DO UPDATE SET data = excluded.data`,
+				courseID, assignmentID, submissionID, data)
+			if err != nil {
+				return fmt.Errorf("Failed to upsert rubric grade '%s': '%w'.", submissionID, err)
+			}
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,114 @@
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/edulinq/autograder/internal/stats"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func (this *backend) GetMetrics(query stats.Query) ([]*stats.Metric, error) {
+	if query.Type == "" {
+		return nil, fmt.Errorf("No metric type was given.")
+	}
+
+	rows, err := this.db.Query(`SELECT data FROM metrics WHERE type = ? ORDER BY id`, string(query.Type))
+	if err != nil {
+		return nil, fmt.Errorf("Failed to query metrics: '%w'.", err)
+	}
+
+	dataJSONs, err := collectRows[string](rows)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read metrics: '%w'.", err)
+	}
+
+	records := make([]*stats.Metric, 0)
+	for _, data := range dataJSONs {
+		var record stats.Metric
+		err = util.JSONFromString(data, &record)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to deserialize metric: '%w'.", err)
+		}
+
+		if query.Match(&record) {
+			records = append(records, &record)
+		}
+	}
+
+	return records, nil
+}
+
+func (this *backend) StoreMetric(record *stats.Metric) error {
+	if record.Type == "" {
+		return fmt.Errorf("No metric type was given.")
+	}
+
+	data, err := util.ToJSON(record)
+	if err != nil {
+		return fmt.Errorf("Failed to serialize metric: '%w'.", err)
+	}
+
+	_, err = this.db.Exec(`INSERT INTO metrics (type, timestamp, data) VALUES (?, ?, ?)`,
+		string(record.Type), int64(record.Timestamp), data)
+	if err != nil {
+		return fmt.Errorf("Failed to store metric: '%w'.", err)
+	}
+
+	return nil
+}
+
+func (this *backend) PseudonymizeUserMetrics(email string, pseudonym string) error {
+	return this.withTransaction(func(tx *sql.Tx) error {
+		rows, err := tx.Query(`SELECT id, data FROM metrics`)
+		if err != nil {
+			return fmt.Errorf("Failed to query metrics: '%w'.", err)
+		}
+		defer rows.Close()
+
+		updates := make(map[int64]string)
+
+		for rows.Next() {
+			var id int64
+			var data string
+
+			err = rows.Scan(&id, &data)
+			if err != nil {
+				return fmt.Errorf("Failed to read metric: '%w'.", err)
+			}
+
+			var record stats.Metric
+			err = util.JSONFromString(data, &record)
+			if err != nil {
+				return fmt.Errorf("Failed to deserialize metric: '%w'.", err)
+			}
+
+			if !record.IsUser(email) {
+				continue
+			}
+
+			record.Attributes[stats.MetricAttributeUserEmail] = pseudonym
+
+			updates[id], err = util.ToJSON(record)
+			if err != nil {
+				return fmt.Errorf("Failed to serialize metric: '%w'.", err)
+			}
+		}
+
+		err = rows.Err()
+		if err != nil {
+			return fmt.Errorf("Failed to read metrics: '%w'.", err)
+		}
+
+		rows.Close()
+
+		for id, data := range updates {
+			_, err = tx.Exec(`UPDATE metrics SET data = ? WHERE id = ?`, data, id)
+			if err != nil {
+				return fmt.Errorf("Failed to update metric: '%w'.", err)
+			}
+		}
+
+		return nil
+	})
+}
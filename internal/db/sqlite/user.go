@@ -0,0 +1,194 @@
+package sqlite
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+// Select users that are enrolled in the course (given as the only argument).
+const courseUsersWhereClause = `WHERE EXISTS (SELECT 1 FROM json_each(users.data, '$."course-info"') WHERE json_each.key = ?)`
+
+func (this *backend) GetServerUsers() (map[string]*model.ServerUser, error) {
+	return getServerUsers(this.db, "")
+}
+
+func (this *backend) GetCourseUsers(course *model.Course) (map[string]*model.CourseUser, error) {
+	users, err := getServerUsers(this.db, courseUsersWhereClause, course.GetID())
+	if err != nil {
+		return nil, err
+	}
+
+	courseUsers := make(map[string]*model.CourseUser)
+	for email, user := range users {
+		// Don't include root as a course user.
+		if email == model.RootUserEmail {
+			continue
+		}
+
+		courseUser, err := user.ToCourseUser(course.ID, false)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid user '%s': '%w'.", email, err)
+		}
+
+		if courseUser != nil {
+			courseUsers[courseUser.Email] = courseUser
+		}
+	}
+
+	return courseUsers, nil
+}
+
+func (this *backend) GetServerUser(email string) (*model.ServerUser, error) {
+	users, err := getServerUsers(this.db, `WHERE email = ?`, email)
+	if err != nil {
+		return nil, err
+	}
+
+	return users[email], nil
+}
+
+func (this *backend) UpsertUsers(upsertUsers map[string]*model.ServerUser) error {
+	return this.withTransaction(func(tx *sql.Tx) error {
+		for email, upsertUser := range upsertUsers {
+			if upsertUser == nil {
+				continue
+			}
+
+			users, err := getServerUsers(tx, `WHERE email = ?`, email)
+			if err != nil {
+				return fmt.Errorf("Failed to get user to merge before saving: '%w'.", err)
+			}
+
+			user := upsertUser
+
+			oldUser, exists := users[email]
+			if exists {
+				_, err = oldUser.Merge(upsertUser)
+				if err != nil {
+					return fmt.Errorf("User '%s' could not be merged with existing user: '%w'.", email, err)
+				}
+
+				user = oldUser
+			}
+
+			err = saveServerUser(tx, user)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func (this *backend) DeleteUser(email string) error {
+	_, err := this.db.Exec(`DELETE FROM users WHERE email = ?`, email)
+	if err != nil {
+		return fmt.Errorf("Failed to delete user '%s': '%w'.", email, err)
+	}
+
+	return nil
+}
+
+func (this *backend) RemoveUserFromCourse(course *model.Course, email string) error {
+	return this.withTransaction(func(tx *sql.Tx) error {
+		users, err := getServerUsers(tx, `WHERE email = ?`, email)
+		if err != nil {
+			return fmt.Errorf("Failed to get user when removing user '%s' from course: '%w'.", email, err)
+		}
+
+		user, ok := users[email]
+		if !ok {
+			return nil
+		}
+
+		_, enrolled := user.CourseInfo[course.ID]
+		if !enrolled {
+			return nil
+		}
+
+		delete(user.CourseInfo, course.ID)
+
+		return saveServerUser(tx, user)
+	})
+}
+
+func (this *backend) DeleteUserToken(email string, tokenID string) (bool, error) {
+	removed := false
+
+	err := this.withTransaction(func(tx *sql.Tx) error {
+		users, err := getServerUsers(tx, `WHERE email = ?`, email)
+		if err != nil {
+			return fmt.Errorf("Failed to get user when deleting user token '%s': '%w'.", email, err)
+		}
+
+		user, ok := users[email]
+		if !ok {
+			return nil
+		}
+
+		for i, token := range user.Tokens {
+			if tokenID == token.ID {
+				user.Tokens = slices.Delete(user.Tokens, i, i+1)
+				removed = true
+				break
+			}
+		}
+
+		if !removed {
+			return nil
+		}
+
+		return saveServerUser(tx, user)
+	})
+
+	return removed, err
+}
+
+func saveServerUser(tx *sql.Tx, user *model.ServerUser) error {
+	data, err := util.ToJSON(user)
+	if err != nil {
+		return fmt.Errorf("Failed to serialize user '%s': '%w'.", user.Email, err)
+	}
+
+	_, err = tx.Exec(`INSERT INTO users (email, data) VALUES (?, ?) ON CONFLICT (email) DO UPDATE SET data = excluded.data`,
+		user.Email, data)
+	if err != nil {
+		return fmt.Errorf("Failed to save user '%s': '%w'.", user.Email, err)
+	}
+
+	return nil
+}
+
+func getServerUsers(source querier, whereClause string, args ...any) (map[string]*model.ServerUser, error) {
+	users := make(map[string]*model.ServerUser)
+
+	rows, err := source.Query(`SELECT data FROM users `+whereClause, args...)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to query users: '%w'.", err)
+	}
+
+	userJSONs, err := collectRows[string](rows)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read users: '%w'.", err)
+	}
+
+	var errs error = nil
+	for _, userJSON := range userJSONs {
+		var user model.ServerUser
+		err = util.JSONFromString(userJSON, &user)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to deserialize user: '%w'.", err)
+		}
+
+		errs = errors.Join(errs, user.Validate())
+		users[user.Email] = &user
+	}
+
+	return users, errs
+}
@@ -40,6 +40,15 @@ func StoreMetric(record *stats.Metric) error {
 	return backend.StoreMetric(record)
 }
 
+// Replace the user of all of a user's metrics with a pseudonym.
+func PseudonymizeUserMetrics(email string, pseudonym string) error {
+	if backend == nil {
+		return fmt.Errorf("Database has not been opened.")
+	}
+
+	return backend.PseudonymizeUserMetrics(email, pseudonym)
+}
+
 var TESTING_STATS_METRICS []*stats.Metric = []*stats.Metric{
 	&stats.Metric{
 		Timestamp: timestamp.Timestamp(1100),
@@ -45,7 +45,38 @@ func GetSubmissionHistory(assignment *model.Assignment, email string) ([]*model.
 		return nil, fmt.Errorf("Database has not been opened.")
 	}
 
-	return backend.GetSubmissionHistory(assignment, email)
+	history, err := backend.GetSubmissionHistory(assignment, email)
+	if err != nil {
+		return nil, err
+	}
+
+	grades, err := getManualGrades(assignment)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, item := range history {
+		if len(grades.adjustments[item.ID]) == 0 {
+			grades.applyToHistoryItem(assignment, item)
+			continue
+		}
+
+		// History items do not have questions, so regrade adjustments are applied to the full result
+		// (which skips adjustments for questions the submission does not have).
+		gradingInfo, err := backend.GetSubmissionResult(assignment, email, item.ShortID)
+		if err != nil {
+			return nil, err
+		}
+
+		if gradingInfo == nil {
+			return nil, fmt.Errorf("Could not find result for submission '%s'.", item.ID)
+		}
+
+		grades.apply(assignment, gradingInfo)
+		history[i] = gradingInfo.ToHistoryItem()
+	}
+
+	return history, nil
 }
 
 func GetSubmissionResult(assignment *model.Assignment, email string, submissionID string) (*model.GradingInfo, error) {
@@ -54,7 +85,20 @@ func GetSubmissionResult(assignment *model.Assignment, email string, submissionI
 	}
 
 	shortSubmissionID := common.GetShortSubmissionID(submissionID)
-	return backend.GetSubmissionResult(assignment, email, shortSubmissionID)
+
+	gradingInfo, err := backend.GetSubmissionResult(assignment, email, shortSubmissionID)
+	if err != nil {
+		return nil, err
+	}
+
+	grades, err := getManualGrades(assignment)
+	if err != nil {
+		return nil, err
+	}
+
+	grades.apply(assignment, gradingInfo)
+
+	return gradingInfo, nil
 }
 
 // Get only non-nil scoring infos.
@@ -74,45 +118,160 @@ func GetExistingScoringInfos(assignment *model.Assignment, reference *model.Pars
 	return info, nil
 }
 
+// For group assignments, every member of a group will get the group's most recent scoring info.
+// Scores will include any manual grades (rubric grades and regrade adjustments).
 func GetScoringInfos(assignment *model.Assignment, reference *model.ParsedCourseUserReference) (map[string]*model.ScoringInfo, error) {
 	if backend == nil {
 		return nil, fmt.Errorf("Database has not been opened.")
 	}
 
-	return backend.GetScoringInfos(assignment, reference)
+	grades, err := getManualGrades(assignment)
+	if err != nil {
+		return nil, err
+	}
+
+	var scoringInfos map[string]*model.ScoringInfo
+
+	if grades.isEmpty() {
+		scoringInfos, err = backend.GetScoringInfos(assignment, reference)
+	} else {
+		scoringInfos, err = getMergedScoringInfos(assignment, reference)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	// All members of a group share the same score.
+	if assignment.IsGroupAssignment() {
+		err = shareGroupScoringInfos(assignment, scoringInfos)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return scoringInfos, nil
 }
 
+// Results will include any manual grades (rubric grades and regrade adjustments).
 func GetRecentSubmissions(assignment *model.Assignment, reference *model.ParsedCourseUserReference) (map[string]*model.GradingInfo, error) {
 	if backend == nil {
 		return nil, fmt.Errorf("Database has not been opened.")
 	}
 
-	return backend.GetRecentSubmissions(assignment, reference)
+	gradingInfos, err := backend.GetRecentSubmissions(assignment, reference)
+	if err != nil {
+		return nil, err
+	}
+
+	grades, err := getManualGrades(assignment)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, gradingInfo := range gradingInfos {
+		grades.apply(assignment, gradingInfo)
+	}
+
+	return gradingInfos, nil
 }
 
+// Scores will include any manual grades (rubric grades and regrade adjustments).
 func GetRecentSubmissionSurvey(assignment *model.Assignment, reference *model.ParsedCourseUserReference) (map[string]*model.SubmissionHistoryItem, error) {
 	if backend == nil {
 		return nil, fmt.Errorf("Database has not been opened.")
 	}
 
-	return backend.GetRecentSubmissionSurvey(assignment, reference)
+	grades, err := getManualGrades(assignment)
+	if err != nil {
+		return nil, err
+	}
+
+	if grades.isEmpty() {
+		return backend.GetRecentSubmissionSurvey(assignment, reference)
+	}
+
+	gradingInfos, err := GetRecentSubmissions(assignment, reference)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make(map[string]*model.SubmissionHistoryItem, len(gradingInfos))
+	for email, gradingInfo := range gradingInfos {
+		if gradingInfo == nil {
+			results[email] = nil
+		} else {
+			results[email] = gradingInfo.ToHistoryItem()
+		}
+	}
+
+	return results, nil
+}
+
+// Backends compute scoring infos from the raw grading infos,
+// so assignments with manual grades need to compute them from the merged results.
+func getMergedScoringInfos(assignment *model.Assignment, reference *model.ParsedCourseUserReference) (map[string]*model.ScoringInfo, error) {
+	gradingInfos, err := GetRecentSubmissions(assignment, reference)
+	if err != nil {
+		return nil, err
+	}
+
+	scoringInfos := make(map[string]*model.ScoringInfo, len(gradingInfos))
+	for email, gradingInfo := range gradingInfos {
+		if gradingInfo == nil {
+			scoringInfos[email] = nil
+		} else {
+			scoringInfos[email] = gradingInfo.ToScoringInfo()
+		}
+	}
+
+	return scoringInfos, nil
 }
 
+// Results will include any manual grades (rubric grades and regrade adjustments).
 func GetSubmissionContents(assignment *model.Assignment, email string, submissionID string) (*model.GradingResult, error) {
 	if backend == nil {
 		return nil, fmt.Errorf("Database has not been opened.")
 	}
 
 	shortSubmissionID := common.GetShortSubmissionID(submissionID)
-	return backend.GetSubmissionContents(assignment, email, shortSubmissionID)
+
+	gradingResult, err := backend.GetSubmissionContents(assignment, email, shortSubmissionID)
+	if err != nil {
+		return nil, err
+	}
+
+	grades, err := getManualGrades(assignment)
+	if err != nil {
+		return nil, err
+	}
+
+	grades.applyToResult(assignment, gradingResult)
+
+	return gradingResult, nil
 }
 
+// Results will include any manual grades (rubric grades and regrade adjustments).
 func GetRecentSubmissionContents(assignment *model.Assignment, reference *model.ParsedCourseUserReference) (map[string]*model.GradingResult, error) {
 	if backend == nil {
 		return nil, fmt.Errorf("Database has not been opened.")
 	}
 
-	return backend.GetRecentSubmissionContents(assignment, reference)
+	gradingResults, err := backend.GetRecentSubmissionContents(assignment, reference)
+	if err != nil {
+		return nil, err
+	}
+
+	grades, err := getManualGrades(assignment)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, gradingResult := range gradingResults {
+		grades.applyToResult(assignment, gradingResult)
+	}
+
+	return gradingResults, nil
 }
 
 func RemoveSubmission(assignment *model.Assignment, email string, submissionID string) (bool, error) {
@@ -124,10 +283,40 @@ func RemoveSubmission(assignment *model.Assignment, email string, submissionID s
 	return backend.RemoveSubmission(assignment, email, shortSubmissionID)
 }
 
+// Results will include any manual grades (rubric grades and regrade adjustments).
 func GetSubmissionAttempts(assignment *model.Assignment, email string) ([]*model.GradingResult, error) {
+	gradingResults, err := GetRawSubmissionAttempts(assignment, email)
+	if err != nil {
+		return nil, err
+	}
+
+	grades, err := getManualGrades(assignment)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, gradingResult := range gradingResults {
+		grades.applyToResult(assignment, gradingResult)
+	}
+
+	return gradingResults, nil
+}
+
+// Get a user's submissions as they were graded (without any manual grades).
+// Use this (instead of GetSubmissionAttempts()) when the submissions will be saved again,
+// since manual grades are stored separately and would otherwise be applied twice.
+func GetRawSubmissionAttempts(assignment *model.Assignment, email string) ([]*model.GradingResult, error) {
 	if backend == nil {
 		return nil, fmt.Errorf("Database has not been opened.")
 	}
 
 	return backend.GetSubmissionAttempts(assignment, email)
 }
+
+func GetSubmissionUsers(assignment *model.Assignment) ([]string, error) {
+	if backend == nil {
+		return nil, fmt.Errorf("Database has not been opened.")
+	}
+
+	return backend.GetSubmissionUsers(assignment)
+}
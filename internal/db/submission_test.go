@@ -220,6 +220,47 @@ func (this *DBTests) DBTestGetSubmissionHistoryBase(test *testing.T) {
 	}
 }
 
+func (this *DBTests) DBTestGetSubmissionUsersBase(test *testing.T) {
+	defer ResetForTesting()
+
+	assignment := MustGetTestAssignment()
+
+	results, err := GetSubmissionUsers(assignment)
+	if err != nil {
+		test.Fatalf("Failed to get submission users: '%v'.", err)
+	}
+
+	expected := []string{"course-student@test.edulinq.org"}
+	if !reflect.DeepEqual(expected, results) {
+		test.Fatalf("Unexpected results. Expected: '%v', Actual: '%v'.", expected, results)
+	}
+
+	// Users that are removed from the course should still show up.
+	_, _, err = RemoveUserFromCourse(assignment.GetCourse(), "course-student@test.edulinq.org")
+	if err != nil {
+		test.Fatalf("Failed to remove user from course: '%v'.", err)
+	}
+
+	results, err = GetSubmissionUsers(assignment)
+	if err != nil {
+		test.Fatalf("Failed to get submission users after removal: '%v'.", err)
+	}
+
+	if !reflect.DeepEqual(expected, results) {
+		test.Fatalf("Unexpected results after removal. Expected: '%v', Actual: '%v'.", expected, results)
+	}
+
+	// No submissions.
+	results, err = GetSubmissionUsers(MustGetAssignment("course-languages", "java"))
+	if err != nil {
+		test.Fatalf("Failed to get empty submission users: '%v'.", err)
+	}
+
+	if len(results) != 0 {
+		test.Fatalf("Unexpected empty results. Actual: '%v'.", results)
+	}
+}
+
 func (this *DBTests) DBTestGetNextSubmissionIDBase(test *testing.T) {
 	previousSubmissionID := ""
 
@@ -55,3 +55,46 @@ func UpsertActiveTasks(tasks map[string]*model.FullScheduledTask) error {
 
 	return backend.UpsertActiveTasks(tasks)
 }
+
+// Replace all the active server tasks with the given tasks.
+// Existing server tasks that are not in the given tasks will be removed,
+// and tasks that already exist will keep their run times.
+func UpsertActiveServerTasks(tasks []*model.UserTaskInfo) error {
+	if backend == nil {
+		return fmt.Errorf("Database has not been opened.")
+	}
+
+	allTasks, err := backend.GetActiveTasks()
+	if err != nil {
+		return err
+	}
+
+	// Start with the assumption that we will remove all active server tasks.
+	newTasks := make(map[string]*model.FullScheduledTask)
+	for hash, task := range allTasks {
+		if task.Source == model.TaskSourceServer {
+			newTasks[hash] = nil
+		}
+	}
+
+	// Add in any new tasks and merge with any exiting tasks.
+	for i, task := range tasks {
+		newTask, err := task.ToFullServerTask()
+		if err != nil {
+			return fmt.Errorf("Unable to upsert server task at index %d: '%w'.", i, err)
+		}
+
+		if newTask == nil {
+			continue
+		}
+
+		newTask.MergeTimes(allTasks[newTask.Hash])
+		newTasks[newTask.Hash] = newTask
+	}
+
+	if len(newTasks) == 0 {
+		return nil
+	}
+
+	return backend.UpsertActiveTasks(newTasks)
+}
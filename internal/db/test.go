@@ -31,6 +31,45 @@ func MustGetTestSubmissionAssignment() *model.Assignment {
 	return MustGetAssignment(TEST_SUBMISSION_COURSE_ID, TEST_SUBMISSION_ASSIGNMENT_ID)
 }
 
+// Put test users into sections (keyed by email) within a course.
+// Users not in the map are not changed.
+func MustSetTestSections(courseID string, sections map[string][]string) {
+	for email, userSections := range sections {
+		user := MustGetServerUser(email)
+		if (user == nil) || !user.IsEnrolled(courseID) {
+			log.Fatal("Test user is not enrolled in course.", log.NewCourseAttr(courseID), log.NewUserAttr(email))
+		}
+
+		user.CourseInfo[courseID].Sections = userSections
+
+		err := UpsertUser(user)
+		if err != nil {
+			log.Fatal("Failed to set test user sections.", err, log.NewCourseAttr(courseID), log.NewUserAttr(email))
+		}
+	}
+}
+
+// A rubric for test assignments: two criteria worth 2 and 3 points.
+func GetTestRubric() *model.Rubric {
+	return &model.Rubric{
+		Criteria: []*model.RubricCriterion{
+			&model.RubricCriterion{Name: "Style", MaxPoints: 2, Description: "Code follows the style guide."},
+			&model.RubricCriterion{Name: "Design", MaxPoints: 3},
+		},
+	}
+}
+
+// Give a test assignment the test rubric (and save its course).
+// Returns the updated assignment.
+func MustSetTestRubric(courseID string, assignmentID string) *model.Assignment {
+	assignment := MustGetAssignment(courseID, assignmentID)
+	assignment.Rubric = GetTestRubric()
+
+	MustSaveCourse(assignment.GetCourse())
+
+	return MustGetAssignment(courseID, assignmentID)
+}
+
 // Perform the standard actions that prep for a package's testing main.
 // Callers should make sure to cleanup after testing:
 // `defer db.CleanupTestingMain();`.
@@ -45,6 +84,9 @@ func PrepForTestingMain() {
 	ResetForTesting()
 }
 
+// Functions called by ResetForTesting() (see AddResetForTestingHook()).
+var resetForTestingHooks []func() = make([]func(), 0)
+
 // A reset function than can be called between tests.
 func ResetForTesting() {
 	MustClear()
@@ -52,6 +94,16 @@ func ResetForTesting() {
 
 	// Open will add test data when in testing mode.
 	MustOpen()
+
+	for _, hook := range resetForTestingHooks {
+		hook()
+	}
+}
+
+// Add a function that will be called every time the database is reset for testing.
+// This allows other packages to reset any state that is tied to the database (e.g., caches).
+func AddResetForTestingHook(hook func()) {
+	resetForTestingHooks = append(resetForTestingHooks, hook)
 }
 
 func CleanupTestingMain() {
@@ -3,18 +3,12 @@ package docker
 // Handle building docker images for grading.
 
 import (
-	"bufio"
 	"context"
-	"errors"
 	"fmt"
-	"io"
 	"os"
 	"path/filepath"
 	"strings"
 
-	"github.com/docker/docker/api/types"
-	"github.com/docker/docker/pkg/archive"
-
 	"github.com/edulinq/autograder/internal/common"
 	"github.com/edulinq/autograder/internal/config"
 	"github.com/edulinq/autograder/internal/log"
@@ -62,106 +56,20 @@ func BuildImageWithOptions(imageSource ImageSource, options *BuildOptions) error
 	// Don't remove build artifacts when testing (it slows down tests).
 	removeBuildArtifacts := !config.UNIT_TESTING_MODE.Get()
 
-	buildOptions := types.ImageBuildOptions{
-		Tags:        []string{imageInfo.Name},
-		Dockerfile:  "Dockerfile",
-		Remove:      removeBuildArtifacts,
-		ForceRemove: removeBuildArtifacts,
-	}
-
-	if options.Rebuild {
-		buildOptions.NoCache = true
-	}
-
-	// Create the build context by adding all the relevant files.
-	tar, err := archive.TarWithOptions(tempDir, &archive.TarOptions{})
-	if err != nil {
-		return fmt.Errorf("Failed to create tar build context for image '%s': '%w'.", imageInfo.Name, err)
-	}
-
-	return buildImage(imageSource, buildOptions, tar)
-}
-
-func buildImage(imageSource ImageSource, buildOptions types.ImageBuildOptions, tar io.ReadCloser) error {
-	docker, err := getDockerClient()
+	runtime, err := GetRuntime()
 	if err != nil {
 		return err
 	}
-	defer docker.Close()
-
-	response, err := docker.ImageBuild(context.Background(), tar, buildOptions)
-	if err != nil {
-		return fmt.Errorf("Failed to run docker image build command: '%w'.", err)
-	}
 
-	output, err := collectBuildOutput(imageSource, response)
+	output, err := runtime.BuildImage(context.Background(), imageInfo.Name, tempDir, options.Rebuild, removeBuildArtifacts)
 	log.Trace("Image Build Output", imageSource, log.NewAttr("image-build-output", output), err)
 	if err != nil {
-		return fmt.Errorf("Found error(s) in Docker build output: '%w'.", err)
+		return err
 	}
 
 	return nil
 }
 
-// Try to get the build output from a build response.
-// Note that the response may be from a failure.
-func collectBuildOutput(imageSource ImageSource, response types.ImageBuildResponse) (string, error) {
-	if response.Body == nil {
-		return "", nil
-	}
-
-	defer response.Body.Close()
-
-	output := strings.Builder{}
-	var errs error = nil
-
-	responseScanner := bufio.NewScanner(response.Body)
-	for responseScanner.Scan() {
-		line := responseScanner.Text()
-
-		line = strings.TrimSpace(line)
-		if line == "" {
-			continue
-		}
-
-		jsonData, err := util.JSONMapFromString(line)
-		if err != nil {
-			output.WriteString("<WARNING: The following output line was not JSON.>")
-			output.WriteString(line)
-		}
-
-		rawText, ok := jsonData["error"]
-		if ok {
-			text, ok := rawText.(string)
-			if !ok {
-				text = "<ERROR: Docker output JSON value is not a string.>"
-			}
-
-			text = strings.TrimSpace(text)
-			if text != "" {
-				errs = errors.Join(errs, fmt.Errorf("%s", text))
-			}
-		}
-
-		rawText, ok = jsonData["stream"]
-		if ok {
-			text, ok := rawText.(string)
-			if !ok {
-				text = "<ERROR: Docker output JSON value is not a string.>"
-			}
-
-			output.WriteString(text)
-		}
-	}
-
-	err := responseScanner.Err()
-	if err != nil {
-		errs = errors.Join(errs, fmt.Errorf("Failed to scan docker image build response: '%w'.", err))
-	}
-
-	return output.String(), errs
-}
-
 // Write a full docker build context (Dockerfile and static files) to the given directory.
 func writeDockerContext(imageInfo *ImageInfo, outDir string) error {
 	_, _, workDir, err := common.CreateStandardGradingDirs(outDir)
@@ -1,19 +1,14 @@
 package docker
 
 import (
-	"bytes"
 	"context"
 	"errors"
 	"fmt"
-	"io"
 	"path/filepath"
 	"sync"
 
-	"github.com/docker/docker/api/types/image"
-
 	"github.com/edulinq/autograder/internal/config"
 	"github.com/edulinq/autograder/internal/log"
-	"github.com/edulinq/autograder/internal/timestamp"
 	"github.com/edulinq/autograder/internal/util"
 )
 
@@ -60,6 +55,13 @@ func BuildImageFromSource(imageSource ImageSource, force bool, quick bool, optio
 
 	buildErr := BuildImageWithOptions(imageSource, options)
 
+	// Any warm containers are from the old image.
+	if buildErr == nil {
+		refreshContainerPool(imageSource)
+	} else {
+		DrainContainerPool(imageSource.GetImageName())
+	}
+
 	// Always try to store the result of cache building.
 	_, _, cacheErr := util.CachePut(imageSource.GetCachePath(), CACHE_KEY_BUILD_SUCCESS, (buildErr == nil))
 
@@ -179,62 +181,42 @@ func EnsureImage(name string) error {
 	return PullImage(name)
 }
 
-func GetImageSummary(name string) (*image.Summary, error) {
-	docker, err := getDockerClient()
+// Get a summary of a local image (or nil if the image does not exist locally).
+func GetImageSummary(name string) (*ImageSummary, error) {
+	runtime, err := GetRuntime()
 	if err != nil {
 		return nil, err
 	}
-	defer docker.Close()
-
-	images, err := docker.ImageList(context.Background(), image.ListOptions{})
-	if err != nil {
-		return nil, fmt.Errorf("Failed to list docker images: '%w'.", err)
-	}
-
-	for _, image := range images {
-		for _, tag := range image.RepoTags {
-			if tag == name {
-				return &image, nil
-			}
-		}
-	}
 
-	return nil, nil
+	return runtime.InspectImage(context.Background(), name)
 }
 
 // Pull the image into the local repo.
 // The image name should include the version.
 func PullImage(name string) error {
-	docker, err := getDockerClient()
+	runtime, err := GetRuntime()
 	if err != nil {
 		return err
 	}
-	defer docker.Close()
 
-	output, err := docker.ImagePull(context.Background(), name, image.PullOptions{})
+	output, err := runtime.PullImage(context.Background(), name)
+	log.Debug("Image Pull", log.NewAttr("name", name), log.NewAttr("output", output), err)
 	if err != nil {
-		return fmt.Errorf("Failed to run docker image pull command: '%w'.", err)
+		return err
 	}
-	defer output.Close()
-
-	var buffer bytes.Buffer
-	io.Copy(&buffer, output)
-
-	log.Debug("Image Pull", log.NewAttr("name", name), log.NewAttr("output", buffer.String()))
 
 	return nil
 }
 
 func GetImageGzipBytes(name string) ([]byte, error) {
-	docker, err := getDockerClient()
+	runtime, err := GetRuntime()
 	if err != nil {
 		return nil, err
 	}
-	defer docker.Close()
 
-	reader, err := docker.ImageSave(context.Background(), []string{name})
+	reader, err := runtime.SaveImage(context.Background(), name)
 	if err != nil {
-		return nil, fmt.Errorf("Failed to get reader for image '%s': '%w'.", name, err)
+		return nil, err
 	}
 	defer reader.Close()
 
@@ -264,7 +246,7 @@ func GetBuiltImageInfo(imageSource ImageSource, appendLatest bool) (*BuiltImageI
 	}
 
 	info.Built = true
-	info.CreatedTimestamp = timestamp.Timestamp(image.Created * 1000)
+	info.CreatedTimestamp = image.Created
 	info.Size = image.Size
 
 	return &info, nil
@@ -0,0 +1,210 @@
+package docker
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/docker/docker/api/types/container"
+
+	"github.com/edulinq/autograder/internal/config"
+)
+
+const TMPFS_TARGET = "/tmp"
+
+// The resource limit that a container ran into (if any).
+type ResourceLimit string
+
+const (
+	RESOURCE_LIMIT_NONE   ResourceLimit = ""
+	RESOURCE_LIMIT_MEMORY ResourceLimit = "memory"
+	RESOURCE_LIMIT_PIDS   ResourceLimit = "pids"
+)
+
+// Messages (from common shells/languages) that indicate a process could not fork/spawn because of the PID limit.
+var pidLimitPattern *regexp.Regexp = regexp.MustCompile(`(?i)(fork: (retry: )?resource temporarily unavailable)|(can't fork)|(cannot fork)|(can't start new thread)|(unable to create (new )?native thread)|(BlockingIOError: \[Errno 11\])`)
+
+// The resource limits for a single container run (after server limits have been applied).
+// A zero (numeric) value means that there is no limit.
+type ContainerLimits struct {
+	MaxMemoryMB    int
+	MaxCPUs        float64
+	MaxPIDs        int
+	AllowNetwork   bool
+	ReadOnlyRootFS bool
+	TmpfsSizeMB    int
+}
+
+// Get the limits for running this image's containers.
+// Assignment values are lowered to the server's limits,
+// and server limits are used for any values the assignment did not set.
+// Networking is only allowed if both the assignment and server allow it.
+func (this *ImageInfo) GetContainerLimits() *ContainerLimits {
+	limits := &ContainerLimits{
+		MaxMemoryMB:    resolveIntLimit(this.MaxMemoryMB, config.DOCKER_MAX_MEMORY_MB.Get()),
+		MaxCPUs:        resolveFloatLimit(this.MaxCPUs, config.DOCKER_MAX_CPUS.Get()),
+		MaxPIDs:        resolveIntLimit(this.MaxPIDs, config.DOCKER_MAX_PIDS.Get()),
+		AllowNetwork:   (this.AllowNetwork && config.DOCKER_ALLOW_NETWORK.Get()),
+		ReadOnlyRootFS: this.ReadOnlyRootFS,
+	}
+
+	// Only mount a tmpfs when asked for one, or when there would otherwise be no writable scratch space.
+	if (this.TmpfsSizeMB > 0) || this.ReadOnlyRootFS {
+		limits.TmpfsSizeMB = resolveIntLimit(this.TmpfsSizeMB, config.DOCKER_MAX_TMPFS_MB.Get())
+	}
+
+	return limits
+}
+
+func (this *ImageInfo) validateLimits() error {
+	if this.MaxMemoryMB < 0 {
+		return fmt.Errorf("Max memory must be non-negative, found: %d.", this.MaxMemoryMB)
+	}
+
+	if this.MaxCPUs < 0 {
+		return fmt.Errorf("Max CPUs must be non-negative, found: %f.", this.MaxCPUs)
+	}
+
+	if this.MaxPIDs < 0 {
+		return fmt.Errorf("Max PIDs must be non-negative, found: %d.", this.MaxPIDs)
+	}
+
+	if this.TmpfsSizeMB < 0 {
+		return fmt.Errorf("Tmpfs size must be non-negative, found: %d.", this.TmpfsSizeMB)
+	}
+
+	return nil
+}
+
+// Apply these limits to the config for a container that is about to be created.
+// Nil limits will not limit any resources, but networking will still be disabled.
+func (this *ContainerLimits) apply(containerConfig *container.Config, hostConfig *container.HostConfig) {
+	if (this == nil) || !this.AllowNetwork {
+		containerConfig.NetworkDisabled = true
+		hostConfig.NetworkMode = "none"
+	}
+
+	if this == nil {
+		return
+	}
+
+	if this.MaxMemoryMB > 0 {
+		hostConfig.Resources.Memory = int64(this.MaxMemoryMB) * 1024 * 1024
+
+		// Do not allow swap, otherwise the memory limit will not be hit (just slowly approached).
+		hostConfig.Resources.MemorySwap = hostConfig.Resources.Memory
+	}
+
+	if this.MaxCPUs > 0 {
+		hostConfig.Resources.NanoCPUs = int64(this.MaxCPUs * 1e9)
+	}
+
+	if this.MaxPIDs > 0 {
+		pidsLimit := int64(this.MaxPIDs)
+		hostConfig.Resources.PidsLimit = &pidsLimit
+	}
+
+	hostConfig.ReadonlyRootfs = this.ReadOnlyRootFS
+
+	if this.hasTmpfs() {
+		hostConfig.Tmpfs = map[string]string{
+			TMPFS_TARGET: this.tmpfsOptions(),
+		}
+	}
+}
+
+// Get the "podman run" arguments for these limits (see apply()).
+// Nil limits will not limit any resources, but networking will still be disabled.
+func (this *ContainerLimits) podmanArgs() []string {
+	args := make([]string, 0)
+
+	if (this == nil) || !this.AllowNetwork {
+		args = append(args, "--network=none")
+	}
+
+	if this == nil {
+		return args
+	}
+
+	if this.MaxMemoryMB > 0 {
+		// Do not allow swap, otherwise the memory limit will not be hit (just slowly approached).
+		args = append(args, fmt.Sprintf("--memory=%dm", this.MaxMemoryMB), fmt.Sprintf("--memory-swap=%dm", this.MaxMemoryMB))
+	}
+
+	if this.MaxCPUs > 0 {
+		args = append(args, fmt.Sprintf("--cpus=%s", strconv.FormatFloat(this.MaxCPUs, 'f', -1, 64)))
+	}
+
+	if this.MaxPIDs > 0 {
+		args = append(args, fmt.Sprintf("--pids-limit=%d", this.MaxPIDs))
+	}
+
+	if this.ReadOnlyRootFS {
+		// Podman would otherwise add its own tmpfs mounts to a read-only root.
+		args = append(args, "--read-only", "--read-only-tmpfs=false")
+	}
+
+	if this.hasTmpfs() {
+		args = append(args, fmt.Sprintf("--tmpfs=%s:%s", TMPFS_TARGET, this.tmpfsOptions()))
+	}
+
+	return args
+}
+
+func (this *ContainerLimits) hasTmpfs() bool {
+	return (this.TmpfsSizeMB > 0) || this.ReadOnlyRootFS
+}
+
+func (this *ContainerLimits) tmpfsOptions() string {
+	options := "rw,exec,nosuid"
+	if this.TmpfsSizeMB > 0 {
+		options = fmt.Sprintf("%s,size=%dm", options, this.TmpfsSizeMB)
+	}
+
+	return options
+}
+
+// Guess which (if any) limit a finished container ran into.
+// Container runtimes directly report when a container runs out of memory,
+// but hitting the PID limit can only be seen in the output of the processes that failed to start.
+func (this *ContainerLimits) checkExceeded(oomKilled bool, stdout string, stderr string) ResourceLimit {
+	if this == nil {
+		return RESOURCE_LIMIT_NONE
+	}
+
+	if oomKilled && (this.MaxMemoryMB > 0) {
+		return RESOURCE_LIMIT_MEMORY
+	}
+
+	if (this.MaxPIDs > 0) && (pidLimitPattern.MatchString(stdout) || pidLimitPattern.MatchString(stderr)) {
+		return RESOURCE_LIMIT_PIDS
+	}
+
+	return RESOURCE_LIMIT_NONE
+}
+
+// A zero server limit means there is no server limit,
+// a zero assignment limit means to use the server limit.
+func resolveIntLimit(assignmentLimit int, serverLimit int) int {
+	if serverLimit <= 0 {
+		return max(0, assignmentLimit)
+	}
+
+	if (assignmentLimit <= 0) || (assignmentLimit > serverLimit) {
+		return serverLimit
+	}
+
+	return assignmentLimit
+}
+
+func resolveFloatLimit(assignmentLimit float64, serverLimit float64) float64 {
+	if serverLimit <= 0 {
+		return max(0, assignmentLimit)
+	}
+
+	if (assignmentLimit <= 0) || (assignmentLimit > serverLimit) {
+		return serverLimit
+	}
+
+	return assignmentLimit
+}
@@ -0,0 +1,215 @@
+package docker
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/docker/docker/api/types/container"
+
+	"github.com/edulinq/autograder/internal/config"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestGetContainerLimits(test *testing.T) {
+	oldMemory := config.DOCKER_MAX_MEMORY_MB.Get()
+	oldCPUs := config.DOCKER_MAX_CPUS.Get()
+	oldPIDs := config.DOCKER_MAX_PIDS.Get()
+	oldTmpfs := config.DOCKER_MAX_TMPFS_MB.Get()
+	oldNetwork := config.DOCKER_ALLOW_NETWORK.Get()
+
+	defer func() {
+		config.DOCKER_MAX_MEMORY_MB.Set(oldMemory)
+		config.DOCKER_MAX_CPUS.Set(oldCPUs)
+		config.DOCKER_MAX_PIDS.Set(oldPIDs)
+		config.DOCKER_MAX_TMPFS_MB.Set(oldTmpfs)
+		config.DOCKER_ALLOW_NETWORK.Set(oldNetwork)
+	}()
+
+	testCases := []struct {
+		serverMemory  int
+		serverCPUs    float64
+		serverPIDs    int
+		serverTmpfs   int
+		serverNetwork bool
+		imageInfo     ImageInfo
+		expected      ContainerLimits
+	}{
+		// Server defaults.
+		{1024, 2.0, 100, 64, false, ImageInfo{}, ContainerLimits{1024, 2.0, 100, false, false, 0}},
+
+		// Lower than the server limits.
+		{1024, 2.0, 100, 64, false, ImageInfo{MaxMemoryMB: 512, MaxCPUs: 0.5, MaxPIDs: 10, TmpfsSizeMB: 32}, ContainerLimits{512, 0.5, 10, false, false, 32}},
+
+		// Higher than the server limits.
+		{1024, 2.0, 100, 64, false, ImageInfo{MaxMemoryMB: 4096, MaxCPUs: 8, MaxPIDs: 1000, TmpfsSizeMB: 128}, ContainerLimits{1024, 2.0, 100, false, false, 64}},
+
+		// No server limits.
+		{0, 0, 0, 0, false, ImageInfo{}, ContainerLimits{0, 0, 0, false, false, 0}},
+		{0, 0, 0, 0, false, ImageInfo{MaxMemoryMB: 4096, MaxCPUs: 8, MaxPIDs: 1000, TmpfsSizeMB: 128}, ContainerLimits{4096, 8, 1000, false, false, 128}},
+
+		// Network.
+		{1024, 2.0, 100, 64, false, ImageInfo{AllowNetwork: true}, ContainerLimits{1024, 2.0, 100, false, false, 0}},
+		{1024, 2.0, 100, 64, true, ImageInfo{AllowNetwork: true}, ContainerLimits{1024, 2.0, 100, true, false, 0}},
+		{1024, 2.0, 100, 64, true, ImageInfo{}, ContainerLimits{1024, 2.0, 100, false, false, 0}},
+
+		// A read-only root always gets a tmpfs.
+		{1024, 2.0, 100, 64, false, ImageInfo{ReadOnlyRootFS: true}, ContainerLimits{1024, 2.0, 100, false, true, 64}},
+		{1024, 2.0, 100, 0, false, ImageInfo{ReadOnlyRootFS: true}, ContainerLimits{1024, 2.0, 100, false, true, 0}},
+	}
+
+	for i, testCase := range testCases {
+		config.DOCKER_MAX_MEMORY_MB.Set(testCase.serverMemory)
+		config.DOCKER_MAX_CPUS.Set(testCase.serverCPUs)
+		config.DOCKER_MAX_PIDS.Set(testCase.serverPIDs)
+		config.DOCKER_MAX_TMPFS_MB.Set(testCase.serverTmpfs)
+		config.DOCKER_ALLOW_NETWORK.Set(testCase.serverNetwork)
+
+		actual := testCase.imageInfo.GetContainerLimits()
+		if !reflect.DeepEqual(testCase.expected, *actual) {
+			test.Errorf("Case %d: Unexpected limits. Expected: '%s', Actual: '%s'.",
+				i, util.MustToJSONIndent(testCase.expected), util.MustToJSONIndent(actual))
+			continue
+		}
+	}
+}
+
+func TestContainerLimitsApply(test *testing.T) {
+	pids := int64(10)
+
+	testCases := []struct {
+		limits          *ContainerLimits
+		networkDisabled bool
+		expected        container.HostConfig
+	}{
+		{
+			nil,
+			true,
+			container.HostConfig{NetworkMode: "none"},
+		},
+		{
+			&ContainerLimits{},
+			true,
+			container.HostConfig{NetworkMode: "none"},
+		},
+		{
+			&ContainerLimits{AllowNetwork: true},
+			false,
+			container.HostConfig{},
+		},
+		{
+			&ContainerLimits{MaxMemoryMB: 2, MaxCPUs: 1.5, MaxPIDs: 10},
+			true,
+			container.HostConfig{
+				NetworkMode: "none",
+				Resources: container.Resources{
+					Memory:     2 * 1024 * 1024,
+					MemorySwap: 2 * 1024 * 1024,
+					NanoCPUs:   1500000000,
+					PidsLimit:  &pids,
+				},
+			},
+		},
+		{
+			&ContainerLimits{ReadOnlyRootFS: true},
+			true,
+			container.HostConfig{
+				NetworkMode:    "none",
+				ReadonlyRootfs: true,
+				Tmpfs:          map[string]string{"/tmp": "rw,exec,nosuid"},
+			},
+		},
+		{
+			&ContainerLimits{TmpfsSizeMB: 64},
+			true,
+			container.HostConfig{
+				NetworkMode: "none",
+				Tmpfs:       map[string]string{"/tmp": "rw,exec,nosuid,size=64m"},
+			},
+		},
+	}
+
+	for i, testCase := range testCases {
+		containerConfig := container.Config{}
+		hostConfig := container.HostConfig{}
+
+		testCase.limits.apply(&containerConfig, &hostConfig)
+
+		if testCase.networkDisabled != containerConfig.NetworkDisabled {
+			test.Errorf("Case %d: Unexpected network disabled. Expected: '%v', Actual: '%v'.", i, testCase.networkDisabled, containerConfig.NetworkDisabled)
+			continue
+		}
+
+		if !reflect.DeepEqual(testCase.expected, hostConfig) {
+			test.Errorf("Case %d: Unexpected host config. Expected: '%s', Actual: '%s'.",
+				i, util.MustToJSONIndent(testCase.expected), util.MustToJSONIndent(hostConfig))
+			continue
+		}
+	}
+}
+
+func TestContainerLimitsPodmanArgs(test *testing.T) {
+	testCases := []struct {
+		limits   *ContainerLimits
+		expected []string
+	}{
+		{nil, []string{"--network=none"}},
+		{&ContainerLimits{}, []string{"--network=none"}},
+		{&ContainerLimits{AllowNetwork: true}, []string{}},
+		{
+			&ContainerLimits{MaxMemoryMB: 2, MaxCPUs: 1.5, MaxPIDs: 10},
+			[]string{"--network=none", "--memory=2m", "--memory-swap=2m", "--cpus=1.5", "--pids-limit=10"},
+		},
+		{
+			&ContainerLimits{ReadOnlyRootFS: true},
+			[]string{"--network=none", "--read-only", "--read-only-tmpfs=false", "--tmpfs=/tmp:rw,exec,nosuid"},
+		},
+		{
+			&ContainerLimits{TmpfsSizeMB: 64},
+			[]string{"--network=none", "--tmpfs=/tmp:rw,exec,nosuid,size=64m"},
+		},
+	}
+
+	for i, testCase := range testCases {
+		actual := testCase.limits.podmanArgs()
+		if !reflect.DeepEqual(testCase.expected, actual) {
+			test.Errorf("Case %d: Unexpected args. Expected: '%s', Actual: '%s'.",
+				i, util.MustToJSONIndent(testCase.expected), util.MustToJSONIndent(actual))
+			continue
+		}
+	}
+}
+
+func TestContainerLimitsCheckExceeded(test *testing.T) {
+	limits := &ContainerLimits{MaxMemoryMB: 10, MaxPIDs: 10}
+
+	testCases := []struct {
+		limits    *ContainerLimits
+		oomKilled bool
+		stdout    string
+		stderr    string
+		expected  ResourceLimit
+	}{
+		{limits, false, "", "", RESOURCE_LIMIT_NONE},
+		{limits, false, "Some output.", "Some error.", RESOURCE_LIMIT_NONE},
+		{limits, true, "", "", RESOURCE_LIMIT_MEMORY},
+		{limits, false, "", "bash: fork: retry: Resource temporarily unavailable", RESOURCE_LIMIT_PIDS},
+		{limits, false, "", "sh: can't fork: Resource temporarily unavailable", RESOURCE_LIMIT_PIDS},
+		{limits, false, "BlockingIOError: [Errno 11] Resource temporarily unavailable", "", RESOURCE_LIMIT_PIDS},
+		{limits, false, "", "RuntimeError: can't start new thread", RESOURCE_LIMIT_PIDS},
+
+		// Memory takes priority.
+		{limits, true, "", "bash: fork: Resource temporarily unavailable", RESOURCE_LIMIT_MEMORY},
+
+		// No limits.
+		{nil, true, "", "bash: fork: Resource temporarily unavailable", RESOURCE_LIMIT_NONE},
+		{&ContainerLimits{}, true, "", "bash: fork: Resource temporarily unavailable", RESOURCE_LIMIT_NONE},
+	}
+
+	for i, testCase := range testCases {
+		actual := testCase.limits.checkExceeded(testCase.oomKilled, testCase.stdout, testCase.stderr)
+		if testCase.expected != actual {
+			test.Errorf("Case %d: Unexpected limit. Expected: '%s', Actual: '%s'.", i, testCase.expected, actual)
+			continue
+		}
+	}
+}
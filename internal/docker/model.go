@@ -27,6 +27,17 @@ type ImageInfo struct {
 
 	MaxRuntimeSecs int `json:"max-runtime-secs,omitempty"`
 
+	// Resource limits for grading containers (zero values will use the server's limits).
+	MaxMemoryMB    int     `json:"max-memory-mb,omitempty"`
+	MaxCPUs        float64 `json:"max-cpus,omitempty"`
+	MaxPIDs        int     `json:"max-pids,omitempty"`
+	AllowNetwork   bool    `json:"allow-network,omitempty"`
+	ReadOnlyRootFS bool    `json:"read-only-root-fs,omitempty"`
+	TmpfsSizeMB    int     `json:"tmpfs-size-mb,omitempty"`
+
+	// Keep warm containers ready for grading (see ContainerPoolOptions).
+	ContainerPool *ContainerPoolOptions `json:"container-pool,omitempty"`
+
 	// Fields that are not part of the JSON and are set after deserialization.
 
 	Name string `json:"-"`
@@ -148,5 +159,15 @@ func (this *ImageInfo) Validate() error {
 		return fmt.Errorf("Max runtime seconds must be non-negative, found: %d.", this.MaxRuntimeSecs)
 	}
 
+	err = this.validateLimits()
+	if err != nil {
+		return err
+	}
+
+	err = this.ContainerPool.validate()
+	if err != nil {
+		return fmt.Errorf("Failed to validate container pool: '%w'.", err)
+	}
+
 	return nil
 }
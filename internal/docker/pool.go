@@ -0,0 +1,448 @@
+package docker
+
+// Pools of warm grading containers.
+// A warm container is created and started ahead of time,
+// but it waits (paused) for a start file before running the image's command (see warmContainerRuntime).
+// Grading with a warm container skips the time it takes to create and start a container.
+// Each warm container has its own input/output dirs that grading input/output is copied into/out of.
+// Runtimes that do not support warm containers will always use fresh containers.
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/edulinq/autograder/internal/common"
+	"github.com/edulinq/autograder/internal/config"
+	"github.com/edulinq/autograder/internal/log"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+const (
+	WARM_CONTROL_DIRNAME = "control"
+	WARM_START_FILENAME  = "start"
+
+	warmControlTarget = "/autograder/control"
+)
+
+// Returned (wrapped) when a warm container cannot be used (but was not run).
+var errWarmContainerUnavailable = errors.New("Warm container is unavailable.")
+
+// Returned (wrapped) when an image cannot run warm containers (e.g., it does not have /bin/sh).
+// Pools are turned off for these images.
+var errWarmContainerUnsupported = errors.New("Image does not support warm containers.")
+
+// Options for keeping warm containers ready for an image.
+type ContainerPoolOptions struct {
+	// The number of warm containers to keep ready.
+	// Zero disables the pool, and the server may lower this (see config.DOCKER_POOL_MAX_CONTAINERS).
+	Size int `json:"size"`
+
+	// The number of seconds a warm container can be unused before it is removed.
+	// Zero uses the server's max (see config.DOCKER_POOL_MAX_IDLE_SECS).
+	// If both are zero, then warm containers are never removed for being idle.
+	MaxIdleSecs int `json:"max-idle-secs,omitempty"`
+}
+
+// Runtimes that can run warm containers.
+type warmContainerRuntime interface {
+	ContainerRuntime
+
+	// Create and start a container that will wait for the file at startPath (inside the container)
+	// before running the image's command, and then pause the container.
+	// Returns the container's ID.
+	createWarmContainer(ctx context.Context, spec *ContainerSpec, startPath string) (string, error)
+
+	// Like RunContainer(), but with a container made by createWarmContainer().
+	// The start function will be called (to create the start file) once the runtime is ready for output.
+	// Errors wrapping errWarmContainerUnavailable mean that the container was not run.
+	runWarmContainer(ctx context.Context, spec *ContainerSpec, id string, start func() error) (*ContainerResult, error)
+
+	// Kill and remove a container.
+	removeContainer(name string, id string)
+}
+
+type warmContainer struct {
+	name       string
+	id         string
+	tempDir    string
+	inputDir   string
+	outputDir  string
+	controlDir string
+	idleTimer  *time.Timer
+	runtime    warmContainerRuntime
+}
+
+type containerPool struct {
+	imageName string
+	options   *ContainerPoolOptions
+	limits    *ContainerLimits
+	idle      []*warmContainer
+	pending   int
+}
+
+var (
+	poolLock       sync.Mutex
+	containerPools map[string]*containerPool = make(map[string]*containerPool)
+
+	// The number of idle and pending warm containers across all pools.
+	poolContainerCount int = 0
+
+	// Tracks containers that are being created or removed in the background.
+	poolWaitGroup sync.WaitGroup
+
+	// Images that cannot run warm containers (see errWarmContainerUnsupported).
+	// An image gets another chance once it is rebuilt.
+	unsupportedImages map[string]bool = make(map[string]bool)
+)
+
+// Get the pool options for this image (after server limits have been applied).
+// Returns nil if this image should not have a pool.
+func (this *ImageInfo) GetContainerPoolOptions() *ContainerPoolOptions {
+	if (this.ContainerPool == nil) || (this.ContainerPool.Size <= 0) {
+		return nil
+	}
+
+	maxContainers := config.DOCKER_POOL_MAX_CONTAINERS.Get()
+	if maxContainers <= 0 {
+		return nil
+	}
+
+	return &ContainerPoolOptions{
+		Size:        min(this.ContainerPool.Size, maxContainers),
+		MaxIdleSecs: resolveIntLimit(this.ContainerPool.MaxIdleSecs, config.DOCKER_POOL_MAX_IDLE_SECS.Get()),
+	}
+}
+
+func (this *ContainerPoolOptions) validate() error {
+	if this == nil {
+		return nil
+	}
+
+	if this.Size < 0 {
+		return fmt.Errorf("Pool size must be non-negative, found: %d.", this.Size)
+	}
+
+	if this.MaxIdleSecs < 0 {
+		return fmt.Errorf("Max idle seconds must be non-negative, found: %d.", this.MaxIdleSecs)
+	}
+
+	return nil
+}
+
+// Remove all the warm containers for an image.
+// Containers that are currently being created will be removed once they are ready.
+func DrainContainerPool(imageName string) {
+	poolLock.Lock()
+	containers := drainContainerPoolLocked(imageName)
+	poolLock.Unlock()
+
+	for _, container := range containers {
+		container.remove()
+	}
+}
+
+// Replace any warm containers for an image that was just rebuilt.
+// Images without an existing pool (e.g., ones that have not been graded yet) do not get a new pool.
+func refreshContainerPool(imageSource ImageSource) {
+	runtime, err := GetRuntime()
+	if err != nil {
+		return
+	}
+
+	imageName := imageSource.GetImageName()
+	imageInfo := imageSource.GetImageInfo()
+
+	poolLock.Lock()
+
+	_, exists := containerPools[imageName]
+	containers := drainContainerPoolLocked(imageName)
+	delete(unsupportedImages, imageName)
+
+	poolLock.Unlock()
+
+	for _, container := range containers {
+		container.remove()
+	}
+
+	// The new pool is empty, so this will only start filling it.
+	if exists {
+		claimWarmContainer(runtime, imageName, imageInfo.GetContainerLimits(), imageInfo.GetContainerPoolOptions())
+	}
+}
+
+// Remove all warm containers (including ones that are currently being created).
+func StopContainerPools() {
+	poolLock.Lock()
+
+	containers := make([]*warmContainer, 0)
+	for imageName := range containerPools {
+		containers = append(containers, drainContainerPoolLocked(imageName)...)
+	}
+
+	poolLock.Unlock()
+
+	for _, container := range containers {
+		container.remove()
+	}
+
+	poolWaitGroup.Wait()
+}
+
+// Take an idle warm container for this image (if one is available), and start refilling the pool.
+// Returns nil if there is no warm container to use.
+func claimWarmContainer(runtime ContainerRuntime, imageName string, limits *ContainerLimits, options *ContainerPoolOptions) *warmContainer {
+	if options == nil {
+		return nil
+	}
+
+	warmRuntime, ok := runtime.(warmContainerRuntime)
+	if !ok {
+		return nil
+	}
+
+	poolLock.Lock()
+	defer poolLock.Unlock()
+
+	if unsupportedImages[imageName] {
+		return nil
+	}
+
+	pool := containerPools[imageName]
+
+	// Containers made with different options/limits cannot be used.
+	if (pool != nil) && !pool.matches(limits, options) {
+		containers := drainContainerPoolLocked(imageName)
+
+		poolWaitGroup.Add(1)
+		go func() {
+			defer poolWaitGroup.Done()
+
+			for _, container := range containers {
+				container.remove()
+			}
+		}()
+
+		pool = nil
+	}
+
+	if pool == nil {
+		pool = &containerPool{
+			imageName: imageName,
+			options:   options,
+			limits:    limits,
+			idle:      make([]*warmContainer, 0, options.Size),
+		}
+
+		containerPools[imageName] = pool
+	}
+
+	var container *warmContainer = nil
+	if len(pool.idle) > 0 {
+		container = pool.idle[0]
+		pool.idle = pool.idle[1:]
+		poolContainerCount--
+
+		container.stopIdleTimer()
+	}
+
+	pool.fillLocked(warmRuntime)
+
+	return container
+}
+
+func drainContainerPoolLocked(imageName string) []*warmContainer {
+	pool := containerPools[imageName]
+	if pool == nil {
+		return nil
+	}
+
+	delete(containerPools, imageName)
+
+	for _, container := range pool.idle {
+		container.stopIdleTimer()
+	}
+
+	poolContainerCount -= len(pool.idle)
+
+	return pool.idle
+}
+
+func (this *containerPool) matches(limits *ContainerLimits, options *ContainerPoolOptions) bool {
+	return (util.MustToJSON(this.limits) == util.MustToJSON(limits)) && (*this.options == *options)
+}
+
+// Start creating containers until the pool (or server) is full.
+func (this *containerPool) fillLocked(runtime warmContainerRuntime) {
+	maxContainers := config.DOCKER_POOL_MAX_CONTAINERS.Get()
+
+	for ((len(this.idle) + this.pending) < this.options.Size) && (poolContainerCount < maxContainers) {
+		this.pending++
+		poolContainerCount++
+		poolWaitGroup.Add(1)
+
+		go this.createContainer(runtime)
+	}
+}
+
+func (this *containerPool) createContainer(runtime warmContainerRuntime) {
+	defer poolWaitGroup.Done()
+
+	container, err := newWarmContainer(runtime, this.imageName, this.limits)
+
+	poolLock.Lock()
+
+	this.pending--
+
+	// The pool was drained while this container was being created.
+	stale := (containerPools[this.imageName] != this)
+
+	if (err != nil) || stale {
+		poolContainerCount--
+
+		// Turn off the pool (once) instead of failing to create containers for every submission.
+		var drainedContainers []*warmContainer = nil
+		turnedOff := false
+		if !stale && errors.Is(err, errWarmContainerUnsupported) {
+			unsupportedImages[this.imageName] = true
+			drainedContainers = drainContainerPoolLocked(this.imageName)
+			turnedOff = true
+		}
+
+		poolLock.Unlock()
+
+		if turnedOff {
+			log.Warn("Image cannot run warm containers, turning off its container pool.", err, log.NewAttr("image", this.imageName))
+		} else if err != nil {
+			log.Warn("Failed to create warm container.", err, log.NewAttr("image", this.imageName))
+		} else {
+			container.remove()
+		}
+
+		for _, drainedContainer := range drainedContainers {
+			drainedContainer.remove()
+		}
+
+		return
+	}
+
+	// Without a max idle time, containers wait until they are used (or the pool is drained).
+	if this.options.MaxIdleSecs > 0 {
+		container.idleTimer = time.AfterFunc(time.Duration(this.options.MaxIdleSecs)*time.Second, func() {
+			this.expire(container)
+		})
+	}
+
+	this.idle = append(this.idle, container)
+
+	poolLock.Unlock()
+
+	log.Debug("Created warm container.", log.NewAttr("image", this.imageName), log.NewAttr("name", container.name))
+}
+
+// Remove a container that has been idle for too long.
+// The pool is not refilled until it is used again.
+func (this *containerPool) expire(container *warmContainer) {
+	poolLock.Lock()
+
+	found := false
+	for i, idleContainer := range this.idle {
+		if idleContainer == container {
+			this.idle = append(this.idle[:i], this.idle[i+1:]...)
+			poolContainerCount--
+			found = true
+			break
+		}
+	}
+
+	poolLock.Unlock()
+
+	if found {
+		log.Debug("Removing idle warm container.", log.NewAttr("image", this.imageName), log.NewAttr("name", container.name))
+		container.remove()
+	}
+}
+
+func newWarmContainer(runtime warmContainerRuntime, imageName string, limits *ContainerLimits) (*warmContainer, error) {
+	tempDir, inputDir, outputDir, _, err := common.PrepTempGradingDir("warm")
+	if err != nil {
+		return nil, err
+	}
+
+	container := &warmContainer{
+		name:       cleanContainerName(fmt.Sprintf("%s-warm-%s", imageName, util.UUID())),
+		tempDir:    tempDir,
+		inputDir:   inputDir,
+		outputDir:  outputDir,
+		controlDir: filepath.Join(tempDir, WARM_CONTROL_DIRNAME),
+		runtime:    runtime,
+	}
+
+	err = util.MkDir(container.controlDir)
+	if err != nil {
+		util.RemoveDirent(tempDir)
+		return nil, fmt.Errorf("Failed to create warm container control dir: '%w'.", err)
+	}
+
+	spec := &ContainerSpec{
+		Name:   container.name,
+		Image:  imageName,
+		Mounts: container.getMounts(),
+		Limits: limits,
+	}
+
+	container.id, err = runtime.createWarmContainer(context.Background(), spec, filepath.Join(warmControlTarget, WARM_START_FILENAME))
+	if err != nil {
+		util.RemoveDirent(tempDir)
+		return nil, err
+	}
+
+	return container, nil
+}
+
+func (this *warmContainer) getMounts() []MountInfo {
+	return []MountInfo{
+		MountInfo{
+			Source:   util.ShouldAbs(this.inputDir),
+			Target:   "/autograder/input",
+			ReadOnly: true,
+		},
+		MountInfo{
+			Source:   util.ShouldAbs(this.outputDir),
+			Target:   "/autograder/output",
+			ReadOnly: false,
+		},
+		MountInfo{
+			Source:   util.ShouldAbs(this.controlDir),
+			Target:   warmControlTarget,
+			ReadOnly: true,
+		},
+	}
+}
+
+// Signal the container to run the image's command.
+func (this *warmContainer) start() error {
+	return os.WriteFile(filepath.Join(this.controlDir, WARM_START_FILENAME), []byte{}, 0644)
+}
+
+func (this *warmContainer) stopIdleTimer() {
+	if this.idleTimer != nil {
+		this.idleTimer.Stop()
+	}
+}
+
+func (this *warmContainer) remove() {
+	this.runtime.removeContainer(this.name, this.id)
+	util.RemoveDirent(this.tempDir)
+}
+
+// The script run (by /bin/sh) inside a warm container.
+// Images without /bin/sh cannot use warm containers.
+// The image's command is passed in as arguments.
+func getWarmContainerScript(startPath string) string {
+	return fmt.Sprintf(`while [ ! -e '%s' ]; do sleep 0.1; done; exec "$@"`, startPath)
+}
@@ -0,0 +1,287 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/edulinq/autograder/internal/config"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+type fakeWarmRuntime struct {
+	ContainerRuntime
+
+	lock    sync.Mutex
+	created map[string]bool
+	removed map[string]bool
+
+	// If set, creating a container will fail with this error.
+	createErr error
+	failures  int
+}
+
+func (this *fakeWarmRuntime) createWarmContainer(ctx context.Context, spec *ContainerSpec, startPath string) (string, error) {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	if this.createErr != nil {
+		this.failures++
+		return "", this.createErr
+	}
+
+	this.created[spec.Name] = true
+	return spec.Name, nil
+}
+
+func (this *fakeWarmRuntime) runWarmContainer(ctx context.Context, spec *ContainerSpec, id string, start func() error) (*ContainerResult, error) {
+	return &ContainerResult{ID: id}, start()
+}
+
+func (this *fakeWarmRuntime) removeContainer(name string, id string) {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	this.removed[id] = true
+}
+
+func TestGetContainerPoolOptions(test *testing.T) {
+	defer config.DOCKER_POOL_MAX_CONTAINERS.Set(config.DOCKER_POOL_MAX_CONTAINERS.Get())
+	defer config.DOCKER_POOL_MAX_IDLE_SECS.Set(config.DOCKER_POOL_MAX_IDLE_SECS.Get())
+
+	testCases := []struct {
+		serverMax     int
+		serverIdle    int
+		imageInfo     ImageInfo
+		expected      *ContainerPoolOptions
+		expectedError bool
+	}{
+		// No pool.
+		{8, 600, ImageInfo{}, nil, false},
+		{8, 600, ImageInfo{ContainerPool: &ContainerPoolOptions{}}, nil, false},
+		{0, 600, ImageInfo{ContainerPool: &ContainerPoolOptions{Size: 2}}, nil, false},
+
+		// Server defaults.
+		{8, 600, ImageInfo{ContainerPool: &ContainerPoolOptions{Size: 2}}, &ContainerPoolOptions{2, 600}, false},
+
+		// Lower than the server limits.
+		{8, 600, ImageInfo{ContainerPool: &ContainerPoolOptions{Size: 2, MaxIdleSecs: 60}}, &ContainerPoolOptions{2, 60}, false},
+
+		// Higher than the server limits.
+		{8, 600, ImageInfo{ContainerPool: &ContainerPoolOptions{Size: 10, MaxIdleSecs: 6000}}, &ContainerPoolOptions{8, 600}, false},
+
+		// No server idle limit.
+		{8, 0, ImageInfo{ContainerPool: &ContainerPoolOptions{Size: 2}}, &ContainerPoolOptions{2, 0}, false},
+		{8, 0, ImageInfo{ContainerPool: &ContainerPoolOptions{Size: 2, MaxIdleSecs: 60}}, &ContainerPoolOptions{2, 60}, false},
+
+		// Invalid.
+		{8, 600, ImageInfo{ContainerPool: &ContainerPoolOptions{Size: -1}}, nil, true},
+		{8, 600, ImageInfo{ContainerPool: &ContainerPoolOptions{Size: 2, MaxIdleSecs: -1}}, nil, true},
+	}
+
+	for i, testCase := range testCases {
+		config.DOCKER_POOL_MAX_CONTAINERS.Set(testCase.serverMax)
+		config.DOCKER_POOL_MAX_IDLE_SECS.Set(testCase.serverIdle)
+
+		err := testCase.imageInfo.ContainerPool.validate()
+		if testCase.expectedError {
+			if err == nil {
+				test.Errorf("Case %d: Did not get an expected error.", i)
+			}
+
+			continue
+		}
+
+		if err != nil {
+			test.Errorf("Case %d: Failed to validate: '%v'.", i, err)
+			continue
+		}
+
+		actual := testCase.imageInfo.GetContainerPoolOptions()
+		if util.MustToJSON(testCase.expected) != util.MustToJSON(actual) {
+			test.Errorf("Case %d: Unexpected pool options. Expected: '%s', Actual: '%s'.",
+				i, util.MustToJSON(testCase.expected), util.MustToJSON(actual))
+		}
+	}
+}
+
+func TestContainerPoolAccounting(test *testing.T) {
+	defer config.DOCKER_POOL_MAX_CONTAINERS.Set(config.DOCKER_POOL_MAX_CONTAINERS.Get())
+	config.DOCKER_POOL_MAX_CONTAINERS.Set(3)
+
+	defer StopContainerPools()
+
+	runtime := &fakeWarmRuntime{
+		created: make(map[string]bool),
+		removed: make(map[string]bool),
+	}
+
+	options := &ContainerPoolOptions{Size: 2, MaxIdleSecs: 600}
+	limits := &ContainerLimits{MaxMemoryMB: 128}
+
+	// The first claim has nothing to use, but fills the pool.
+	container := claimWarmContainer(runtime, "image-a", limits, options)
+	if container != nil {
+		test.Fatalf("Claimed a container from an empty pool.")
+	}
+
+	poolWaitGroup.Wait()
+	checkPoolCounts(test, "fill", map[string]int{"image-a": 2}, 2)
+
+	// Another pool only gets what is left under the server max.
+	claimWarmContainer(runtime, "image-b", limits, options)
+	poolWaitGroup.Wait()
+	checkPoolCounts(test, "server max", map[string]int{"image-a": 2, "image-b": 1}, 3)
+
+	// A claim gets a container and refills the pool.
+	container = claimWarmContainer(runtime, "image-a", limits, options)
+	if container == nil {
+		test.Fatalf("Did not claim a container from a full pool.")
+	}
+
+	poolWaitGroup.Wait()
+	checkPoolCounts(test, "claim", map[string]int{"image-a": 2, "image-b": 1}, 3)
+
+	// The claimed container can be started.
+	_, err := container.runtime.runWarmContainer(context.Background(), nil, container.id, container.start)
+	if err != nil {
+		test.Fatalf("Failed to start claimed container: '%v'.", err)
+	}
+
+	if !util.PathExists(filepath.Join(container.controlDir, WARM_START_FILENAME)) {
+		test.Fatalf("Start file was not created.")
+	}
+
+	container.remove()
+
+	// Different limits drain the pool.
+	newLimits := &ContainerLimits{MaxMemoryMB: 256}
+	claimWarmContainer(runtime, "image-b", newLimits, options)
+	poolWaitGroup.Wait()
+	checkPoolCounts(test, "new limits", map[string]int{"image-a": 2, "image-b": 1}, 3)
+
+	if containerPools["image-b"].limits != newLimits {
+		test.Fatalf("Pool was not recreated with the new limits.")
+	}
+
+	// Idle containers expire.
+	pool := containerPools["image-a"]
+	pool.expire(pool.idle[0])
+	checkPoolCounts(test, "expire", map[string]int{"image-a": 1, "image-b": 1}, 2)
+
+	DrainContainerPool("image-a")
+	checkPoolCounts(test, "drain", map[string]int{"image-b": 1}, 1)
+
+	StopContainerPools()
+	checkPoolCounts(test, "stop", map[string]int{}, 0)
+
+	if len(runtime.created) != len(runtime.removed) {
+		test.Fatalf("Not all created containers were removed. Created: %d, Removed: %d.", len(runtime.created), len(runtime.removed))
+	}
+}
+
+func TestContainerPoolNoIdleLimit(test *testing.T) {
+	defer StopContainerPools()
+
+	runtime := &fakeWarmRuntime{
+		created: make(map[string]bool),
+		removed: make(map[string]bool),
+	}
+
+	options := &ContainerPoolOptions{Size: 2, MaxIdleSecs: 0}
+
+	claimWarmContainer(runtime, "image-a", nil, options)
+	poolWaitGroup.Wait()
+
+	// Give any (incorrect) idle timers a chance to fire.
+	time.Sleep(50 * time.Millisecond)
+
+	checkPoolCounts(test, "no idle limit", map[string]int{"image-a": 2}, 2)
+
+	container := claimWarmContainer(runtime, "image-a", nil, options)
+	if container == nil {
+		test.Fatalf("Did not claim a container from a full pool.")
+	}
+
+	container.remove()
+}
+
+func TestContainerPoolUnsupportedImage(test *testing.T) {
+	defer StopContainerPools()
+	defer delete(unsupportedImages, "image-a")
+
+	runtime := &fakeWarmRuntime{
+		created:   make(map[string]bool),
+		removed:   make(map[string]bool),
+		createErr: fmt.Errorf("No shell: '%w'.", errWarmContainerUnsupported),
+	}
+
+	options := &ContainerPoolOptions{Size: 2, MaxIdleSecs: 600}
+
+	claimWarmContainer(runtime, "image-a", nil, options)
+	poolWaitGroup.Wait()
+
+	// The failure turns off the pool.
+	checkPoolCounts(test, "unsupported", map[string]int{}, 0)
+
+	if !unsupportedImages["image-a"] {
+		test.Fatalf("Image was not marked as unsupported.")
+	}
+
+	failures := runtime.failures
+
+	// No more containers are attempted.
+	for i := 0; i < 3; i++ {
+		container := claimWarmContainer(runtime, "image-a", nil, options)
+		if container != nil {
+			test.Fatalf("Claimed a container for an unsupported image.")
+		}
+	}
+
+	poolWaitGroup.Wait()
+	checkPoolCounts(test, "unsupported claims", map[string]int{}, 0)
+
+	if failures != runtime.failures {
+		test.Fatalf("Containers were created for an unsupported image. Expected Failures: %d, Actual Failures: %d.", failures, runtime.failures)
+	}
+
+	// Other errors do not turn off the pool.
+	runtime.createErr = fmt.Errorf("Some other error.")
+
+	claimWarmContainer(runtime, "image-b", nil, options)
+	poolWaitGroup.Wait()
+
+	checkPoolCounts(test, "other error", map[string]int{"image-b": 0}, 0)
+
+	if unsupportedImages["image-b"] {
+		test.Fatalf("Image was marked as unsupported from an unrelated error.")
+	}
+}
+
+// Check the number of idle containers in each pool, and the total number of pooled containers.
+func checkPoolCounts(test *testing.T, label string, expectedIdle map[string]int, expectedTotal int) {
+	poolLock.Lock()
+	defer poolLock.Unlock()
+
+	if len(expectedIdle) != len(containerPools) {
+		test.Fatalf("%s: Unexpected number of pools. Expected: %d, Actual: %d.", label, len(expectedIdle), len(containerPools))
+	}
+
+	for imageName, expected := range expectedIdle {
+		pool := containerPools[imageName]
+		if pool == nil {
+			test.Fatalf("%s: Missing pool for '%s'.", label, imageName)
+		}
+
+		if expected != len(pool.idle) {
+			test.Fatalf("%s: Unexpected number of idle containers for '%s'. Expected: %d, Actual: %d.", label, imageName, expected, len(pool.idle))
+		}
+	}
+
+	if expectedTotal != poolContainerCount {
+		test.Fatalf("%s: Unexpected number of pooled containers. Expected: %d, Actual: %d.", label, expectedTotal, poolContainerCount)
+	}
+}
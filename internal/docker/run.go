@@ -1,7 +1,6 @@
 package docker
 
 import (
-	"bytes"
 	"context"
 	"errors"
 	"fmt"
@@ -10,12 +9,6 @@ import (
 	"strings"
 	"sync"
 
-	"github.com/docker/docker/api/types"
-	"github.com/docker/docker/api/types/container"
-	"github.com/docker/docker/api/types/mount"
-	"github.com/docker/docker/client"
-	"github.com/docker/docker/pkg/stdcopy"
-
 	"github.com/edulinq/autograder/internal/config"
 	"github.com/edulinq/autograder/internal/log"
 	"github.com/edulinq/autograder/internal/util"
@@ -37,8 +30,10 @@ type MountInfo struct {
 }
 
 // Run a grading container.
-// Returns: (stdout, stderr, timeout?, canceled?, error)
-func RunGradingContainer(ctx context.Context, logId log.Loggable, imageName string, inputDir string, outputDir string, baseID string, maxRuntimeSecs int) (string, string, bool, bool, error) {
+// If pool is not nil, then a warm container from the image's pool will be used (if one is ready).
+// If live is not nil, output will also be sent to it while the container runs.
+// Returns: (stdout, stderr, timeout?, canceled?, exceeded resource limit, error)
+func RunGradingContainer(ctx context.Context, logId log.Loggable, imageName string, inputDir string, outputDir string, baseID string, maxRuntimeSecs int, limits *ContainerLimits, pool *ContainerPoolOptions, live *LiveOutput) (string, string, bool, bool, ResourceLimit, error) {
 	mounts := []MountInfo{
 		MountInfo{
 			Source:   util.ShouldAbs(inputDir),
@@ -52,21 +47,53 @@ func RunGradingContainer(ctx context.Context, logId log.Loggable, imageName stri
 		},
 	}
 
-	return RunContainer(ctx, logId, imageName, mounts, nil, baseID, maxRuntimeSecs)
+	runtime, err := GetRuntime()
+	if err != nil {
+		return "", "", false, false, RESOURCE_LIMIT_NONE, err
+	}
+
+	warm := claimWarmContainer(runtime, imageName, limits, pool)
+	if warm == nil {
+		return runContainer(ctx, logId, imageName, mounts, nil, baseID, maxRuntimeSecs, limits, nil, live)
+	}
+
+	// The container itself is cleaned up by the run.
+	defer util.RemoveDirent(warm.tempDir)
+
+	err = util.CopyDirContents(inputDir, warm.inputDir)
+	if err != nil {
+		go warm.remove()
+		return "", "", false, false, RESOURCE_LIMIT_NONE, fmt.Errorf("Failed to copy grading input into warm container: '%w'.", err)
+	}
+
+	stdout, stderr, timeout, canceled, exceededLimit, err := runContainer(ctx, logId, imageName, mounts, nil, baseID, maxRuntimeSecs, limits, warm, live)
+
+	copyErr := util.CopyDirContents(warm.outputDir, outputDir)
+	if copyErr != nil {
+		err = errors.Join(err, fmt.Errorf("Failed to copy grading output out of warm container: '%w'.", copyErr))
+	}
+
+	return stdout, stderr, timeout, canceled, exceededLimit, err
 }
 
 // Run a container.
-// Returns: (stdout, stderr, timeout?, canceled?, error)
-func RunContainer(ctx context.Context, logId log.Loggable, imageName string, mounts []MountInfo, cmd []string, baseID string, maxRuntimeSecs int) (string, string, bool, bool, error) {
+// Nil limits will not limit the container's resources (but networking will still be disabled).
+// Returns: (stdout, stderr, timeout?, canceled?, exceeded resource limit, error)
+func RunContainer(ctx context.Context, logId log.Loggable, imageName string, mounts []MountInfo, cmd []string, baseID string, maxRuntimeSecs int, limits *ContainerLimits) (string, string, bool, bool, ResourceLimit, error) {
+	return runContainer(ctx, logId, imageName, mounts, cmd, baseID, maxRuntimeSecs, limits, nil, nil)
+}
+
+func runContainer(ctx context.Context, logId log.Loggable, imageName string, mounts []MountInfo, cmd []string, baseID string, maxRuntimeSecs int, limits *ContainerLimits, warm *warmContainer, live *LiveOutput) (string, string, bool, bool, ResourceLimit, error) {
 	var stdout string
 	var stderr string
 	var tempTimeout bool
 	var timeout bool
 	var canceled bool
+	var exceededLimit ResourceLimit
 	var err error
 
 	runFunc := func(softTimeoutCtx context.Context) {
-		stdout, stderr, tempTimeout, err = runContainerInternal(softTimeoutCtx, logId, imageName, mounts, cmd, baseID)
+		stdout, stderr, tempTimeout, exceededLimit, err = runContainerInternal(softTimeoutCtx, logId, imageName, mounts, cmd, baseID, limits, warm, live)
 		timeout = timeout || tempTimeout
 	}
 
@@ -87,124 +114,70 @@ func RunContainer(ctx context.Context, logId log.Loggable, imageName string, mou
 		err = nil
 	}
 
-	return stdout, stderr, timeout, canceled, err
+	return stdout, stderr, timeout, canceled, exceededLimit, err
 }
 
 // An inner run container helper.
 // We split these up to allow for better timeout guarantees
-// (we can't fully trust Docker to timeout properly).
+// (we can't fully trust container runtimes to timeout properly).
 // This function does not try to enforce any timeouts (aside from passing along the context), that is left to callers.
 // If a timeout is detected, it will be returned (but it is only one of many ways a timeout could happen).
-// Returns: (stdout, stderr, timeout (only one of many types), error)
-func runContainerInternal(ctx context.Context, logId log.Loggable, imageName string, mounts []MountInfo, cmd []string, baseID string) (string, string, bool, error) {
-	// Get a docker client.
-	// Note that cleaning this up needs to wait until after we are sure the container is dead.
-	// This means we won't be defering the close right away (see cleanupRun()).
-	docker, err := getDockerClient()
+// If a warm container is given but cannot be used, then a fresh container will be run (with the given mounts).
+// Returns: (stdout, stderr, timeout (only one of many types), exceeded resource limit, error)
+func runContainerInternal(ctx context.Context, logId log.Loggable, imageName string, mounts []MountInfo, cmd []string, baseID string, limits *ContainerLimits, warm *warmContainer, live *LiveOutput) (string, string, bool, ResourceLimit, error) {
+	runtime, err := GetRuntime()
 	if err != nil {
-		return "", "", false, err
+		return "", "", false, RESOURCE_LIMIT_NONE, err
 	}
 
-	name := cleanContainerName(fmt.Sprintf("%s-%s", baseID, util.UUID()))
-
-	dockerMounts := make([]mount.Mount, 0, len(mounts))
-	for _, mount := range mounts {
-		dockerMounts = append(dockerMounts, mount.ToDocker())
-	}
-
-	log.Debug("Creating container.", log.NewAttr("name", name))
-	containerInstance, err := docker.ContainerCreate(
-		ctx,
-		&container.Config{
-			Image:           imageName,
-			NetworkDisabled: true,
-			Cmd:             cmd,
-		},
-		&container.HostConfig{
-			Mounts: dockerMounts,
-			LogConfig: container.LogConfig{
-				// Don't store any logs, we will copy stdout/stderr directly.
-				Type: "none",
-			},
-		},
-		nil,
-		nil,
-		name)
-
-	if err != nil {
-		docker.Close()
-		return "", "", false, fmt.Errorf("Failed to create container '%s': '%w'.", name, err)
+	spec := &ContainerSpec{
+		Name:   cleanContainerName(fmt.Sprintf("%s-%s", baseID, util.UUID())),
+		Image:  imageName,
+		Mounts: mounts,
+		Cmd:    cmd,
+		Limits: limits,
+		Live:   live,
 	}
 
-	// Now that we have the container, we can schedule cleanup in the background.
-	defer func() {
-		go cleanupRun(docker, name, containerInstance.ID)
-	}()
-
-	// Attach to the container so we can get stdout and stderr.
-	log.Trace("Attaching container.", log.NewAttr("name", name))
-	connection, err := docker.ContainerAttach(ctx, containerInstance.ID, container.AttachOptions{
-		Stream: true,
-		Stdout: true,
-		Stderr: true,
-	})
-	if err != nil {
-		return "", "", false, fmt.Errorf("Failed to attach to container '%s' (%s): '%w'.", name, containerInstance.ID, err)
+	var result *ContainerResult
+	if warm != nil {
+		warmSpec := *spec
+		warmSpec.Name = warm.name
+		warmSpec.Mounts = warm.getMounts()
+
+		result, err = warm.runtime.runWarmContainer(ctx, &warmSpec, warm.id, warm.start)
+		if errors.Is(err, errWarmContainerUnavailable) {
+			log.Warn("Could not use warm container, using a new container.", err, logId, log.NewAttr("name", warm.name))
+			result, err = runtime.RunContainer(ctx, spec)
+		} else {
+			spec = &warmSpec
+		}
+	} else {
+		result, err = runtime.RunContainer(ctx, spec)
 	}
-	defer connection.Close()
 
-	// Handle copying (and possibly truncating) stdout/stderr.
-	outputWaitGroup := &sync.WaitGroup{}
-	outputWaitGroup.Add(1)
-	output := &containerOutput{}
-	go handleContainerOutput(ctx, output, outputWaitGroup, connection)
-
-	log.Trace("Starting container.", log.NewAttr("name", name))
-	err = docker.ContainerStart(ctx, containerInstance.ID, container.StartOptions{})
 	if err != nil {
-		return "", "", false, fmt.Errorf("Failed to start container '%s' (%s): '%w'.", name, containerInstance.ID, err)
+		return "", "", false, RESOURCE_LIMIT_NONE, err
 	}
 
-	// Wait for the container to finish.
-	log.Trace("Waiting for container.", log.NewAttr("name", name))
-	statusChan, errorChan := docker.ContainerWait(ctx, containerInstance.ID, container.WaitConditionNotRunning)
-	select {
-	case err := <-errorChan:
-		if err != nil {
-			// On a timeout or cancel exit this select, and try to recover the output.
-			timeout := errors.Is(err, context.DeadlineExceeded)
-			canceled := errors.Is(err, context.Canceled)
-			if timeout || canceled {
-				break
-			}
-
-			return "", "", false, fmt.Errorf("Got an error when running container '%s' (%s): '%w'.", name, containerInstance.ID, err)
-		}
-	case <-statusChan:
-		// Waiting is complete.
-	case <-ctx.Done():
-		// The context finished but the result has not shown on the error chan (yet).
-	}
-
-	// Wait for output to get copied.
-	log.Trace("Waiting for container output.", log.NewAttr("name", name))
-	outputWaitGroup.Wait()
+	log.Debug("Done with container.", log.NewAttr("name", spec.Name))
 
-	log.Debug("Done with container.", log.NewAttr("name", name))
+	exceededLimit := limits.checkExceeded(result.OOMKilled, result.Stdout, result.Stderr)
 
 	log.Trace("Container output.",
 		logId,
-		log.NewAttr("container-name", name),
-		log.NewAttr("container-id", containerInstance.ID),
-		log.NewAttr("stdout", output.Stdout),
-		log.NewAttr("stderr", output.Stderr),
+		log.NewAttr("container-name", spec.Name),
+		log.NewAttr("container-id", result.ID),
+		log.NewAttr("stdout", result.Stdout),
+		log.NewAttr("stderr", result.Stderr),
 		log.NewAttr("timeout", errors.Is(ctx.Err(), context.DeadlineExceeded)),
 		log.NewAttr("canceled", errors.Is(ctx.Err(), context.Canceled)),
-		log.NewAttr("output-truncated", output.Truncated),
-		output.Err,
+		log.NewAttr("output-truncated", result.Truncated),
+		log.NewAttr("exceeded-limit", exceededLimit),
+		result.Err,
 	)
 
-	return output.Stdout, output.Stderr, errors.Is(ctx.Err(), context.DeadlineExceeded), nil
+	return result.Stdout, result.Stderr, errors.Is(ctx.Err(), context.DeadlineExceeded), exceededLimit, nil
 }
 
 func cleanContainerName(text string) string {
@@ -219,83 +192,85 @@ func cleanContainerName(text string) string {
 	return text
 }
 
-// Read a maximum amount from the container's stdout/stderr, parse the two from the common stream, and signal completion.
-func handleContainerOutput(ctx context.Context, output *containerOutput, outputWaitGroup *sync.WaitGroup, connection types.HijackedResponse) {
-	defer outputWaitGroup.Done()
-
-	// Closing the connection should also close the reader and stop any waiting read operations.
-	defer connection.Close()
-
-	successChan := make(chan bool, 1)
-
-	// Start trying to read in another thread.
-	go func() {
-		handleContainerOutputInternal(output, connection.Reader)
-		successChan <- true
-	}()
-
-	// Wait for either the context or read to complete.
-	select {
-	case <-successChan:
-		return
-	case <-ctx.Done():
-		// Close the connection to interrupt any blocking reads in handleContainerOutputInternal,
-		// then wait for it to finish writing to the output struct before signaling the WaitGroup.
-		connection.Close()
-		<-successChan
-		return
+// Set the final output, denoting any truncated streams.
+func (this *containerOutput) set(stdout string, stderr string, truncated bool) {
+	this.Truncated = truncated
+
+	if truncated {
+		message := fmt.Sprintf("\n\nCombined output (stdout + stderr) exceeds maximum size (%d KB), output has been truncated.", config.DOCKER_MAX_OUTPUT_SIZE_KB.Get())
+
+		if len(stdout) > 0 {
+			stdout += message
+		}
+
+		if len(stderr) > 0 {
+			stderr += message
+		}
 	}
+
+	this.Stdout = stdout
+	this.Stderr = stderr
 }
 
-func handleContainerOutputInternal(output *containerOutput, containerStream io.Reader) {
-	maxSizeKB := config.DOCKER_MAX_OUTPUT_SIZE_KB.Get()
-	bufferLen := maxSizeKB * 1024
+func getMaxOutputSize() int {
+	return config.DOCKER_MAX_OUTPUT_SIZE_KB.Get() * 1024
+}
 
-	// Make the first full (or short) read.
-	buffer := make([]byte, bufferLen)
-	_, err := io.ReadFull(containerStream, buffer)
-	if (err != nil) && (err != io.EOF) && (err != io.ErrUnexpectedEOF) {
-		output.Err = fmt.Errorf("Failed to read container output into temporary buffer: '%w'.", err)
-		return
-	}
+// A pair of writers (for stdout and stderr) that share a maximum combined size.
+// Output past the maximum is dropped (but still reported as written).
+// Kept output is also passed along to the stream's live writer (if set).
+type limitedOutput struct {
+	lock      sync.Mutex
+	remaining int
+	truncated bool
+}
 
-	// Check for too much output.
-	overflowBuffer := make([]byte, 1)
-	readCount, _ := containerStream.Read(overflowBuffer)
-	if readCount == 1 {
-		output.Truncated = true
+type limitedStream struct {
+	limit  *limitedOutput
+	buffer strings.Builder
+	live   io.Writer
+}
+
+func newLimitedOutput(maxSize int) (*limitedStream, *limitedStream) {
+	limit := &limitedOutput{
+		remaining: maxSize,
 	}
 
-	// Parse stdout and stderr out of the output stream.
-	outBuffer := new(strings.Builder)
-	errBuffer := new(strings.Builder)
+	return &limitedStream{limit: limit}, &limitedStream{limit: limit}
+}
 
-	stdcopy.StdCopy(outBuffer, errBuffer, bytes.NewReader(buffer))
+func (this *limitedStream) Write(data []byte) (int, error) {
+	this.limit.lock.Lock()
+	defer this.limit.lock.Unlock()
 
-	// Denote truncated streams.
-	if output.Truncated {
-		message := fmt.Sprintf("\n\nCombined output (stdout + stderr) exceeds maximum size (%d KB), output has been truncated.", maxSizeKB)
+	size := len(data)
+	if size > this.limit.remaining {
+		data = data[:this.limit.remaining]
+		this.limit.truncated = true
+	}
 
-		if outBuffer.Len() > 0 {
-			outBuffer.WriteString(message)
-		}
+	this.limit.remaining -= len(data)
+	this.buffer.Write(data)
 
-		if errBuffer.Len() > 0 {
-			errBuffer.WriteString(message)
-		}
+	if (this.live != nil) && (len(data) > 0) {
+		this.live.Write(data)
 	}
 
-	output.Stdout = outBuffer.String()
-	output.Stderr = errBuffer.String()
+	return size, nil
 }
 
-func (this MountInfo) ToDocker() mount.Mount {
-	return mount.Mount{
-		Type:     "bind",
-		Source:   this.Source,
-		Target:   this.Target,
-		ReadOnly: this.ReadOnly,
-	}
+func (this *limitedStream) String() string {
+	this.limit.lock.Lock()
+	defer this.limit.lock.Unlock()
+
+	return this.buffer.String()
+}
+
+func (this *limitedStream) Truncated() bool {
+	this.limit.lock.Lock()
+	defer this.limit.lock.Unlock()
+
+	return this.limit.truncated
 }
 
 // Set the value and return a function to reset it back to its original state.
@@ -307,28 +282,3 @@ func SetExtraInitTimeSecsForTesting(newValue int) func() {
 		extraInitTimeSecs = oldValue
 	}
 }
-
-func killContainer(docker *client.Client, name string, id string) {
-	// The container should have already gracefully exited.
-	// If not, kill it without any grace.
-	// Ignore any errors.
-	docker.ContainerKill(context.Background(), id, "KILL")
-
-	err := docker.ContainerRemove(context.Background(), id, container.RemoveOptions{
-		Force: true,
-	})
-	if err != nil {
-		log.Warn("Failed to remove container.", err, log.NewAttr("name", name), log.NewAttr("id", id))
-	}
-}
-
-// Cleanup any leftovers from the running the container.
-// This should generally be called in another go routine to prevent blocking.
-func cleanupRun(docker *client.Client, containerName string, containerID string) {
-	killContainer(docker, containerName, containerID)
-
-	err := docker.Close()
-	if err != nil {
-		log.Warn("Failed to close docker client connection.", err)
-	}
-}
@@ -0,0 +1,110 @@
+package docker
+
+// The container runtimes (engines) that can be used to build images and run containers.
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/edulinq/autograder/internal/config"
+	"github.com/edulinq/autograder/internal/timestamp"
+)
+
+const (
+	RUNTIME_DOCKER = "docker"
+	RUNTIME_PODMAN = "podman"
+)
+
+// The operations that the autograder needs from a container runtime.
+// All runtimes build images from the same build context (see writeDockerContext())
+// and should treat container limits the same way (see ContainerLimits).
+type ContainerRuntime interface {
+	// Check that the runtime is available.
+	Ping(ctx context.Context) error
+
+	// Build an image from a prepared build context (a directory with a Dockerfile).
+	// Returns the build output.
+	BuildImage(ctx context.Context, name string, contextDir string, noCache bool, removeArtifacts bool) (string, error)
+
+	// Create, run, wait on, and (eventually) remove a container.
+	// Errors from the container's own processes are not errors for the run (they are just output).
+	RunContainer(ctx context.Context, spec *ContainerSpec) (*ContainerResult, error)
+
+	// Get a summary of a local image (or nil if the image does not exist locally).
+	InspectImage(ctx context.Context, name string) (*ImageSummary, error)
+
+	// Pull an image into the local store.
+	// Returns the pull output.
+	PullImage(ctx context.Context, name string) (string, error)
+
+	// Get a reader for a (tar) archive of an image.
+	SaveImage(ctx context.Context, name string) (io.ReadCloser, error)
+}
+
+// Everything needed to run a single container.
+type ContainerSpec struct {
+	Name   string
+	Image  string
+	Mounts []MountInfo
+	Cmd    []string
+	Limits *ContainerLimits
+
+	// Where to send output while the container is running (may be nil).
+	Live *LiveOutput
+}
+
+type ContainerResult struct {
+	ID        string
+	OOMKilled bool
+
+	containerOutput
+}
+
+// Writers that receive a container's output while it is running
+// (in addition to the output being collected for the result).
+// Output past the maximum output size (see config.DOCKER_MAX_OUTPUT_SIZE_KB) is not sent.
+// Writes come from the runtime's own goroutines, so writers should be safe for concurrent use and should not block.
+type LiveOutput struct {
+	Stdout io.Writer
+	Stderr io.Writer
+}
+
+type ImageSummary struct {
+	Name    string
+	Created timestamp.Timestamp
+	Size    int64
+}
+
+// Get the container runtime selected in the config (see config.DOCKER_RUNTIME).
+func GetRuntime() (ContainerRuntime, error) {
+	name := strings.ToLower(strings.TrimSpace(config.DOCKER_RUNTIME.Get()))
+
+	switch name {
+	case "", RUNTIME_DOCKER:
+		return &dockerRuntime{}, nil
+	case RUNTIME_PODMAN:
+		return &podmanRuntime{
+			path: config.DOCKER_PODMAN_PATH.Get(),
+		}, nil
+	default:
+		return nil, fmt.Errorf("Unknown container runtime: '%s'.", name)
+	}
+}
+
+func (this *LiveOutput) getStdout() io.Writer {
+	if (this == nil) || (this.Stdout == nil) {
+		return io.Discard
+	}
+
+	return this.Stdout
+}
+
+func (this *LiveOutput) getStderr() io.Writer {
+	if (this == nil) || (this.Stderr == nil) {
+		return io.Discard
+	}
+
+	return this.Stderr
+}
@@ -0,0 +1,527 @@
+package docker
+
+// A container runtime that talks to the Docker daemon.
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/archive"
+	"github.com/docker/docker/pkg/stdcopy"
+
+	"github.com/edulinq/autograder/internal/config"
+	"github.com/edulinq/autograder/internal/log"
+	"github.com/edulinq/autograder/internal/timestamp"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+type dockerRuntime struct{}
+
+func (this *dockerRuntime) Ping(ctx context.Context) error {
+	docker, err := getDockerClient()
+	if err != nil {
+		return err
+	}
+	defer docker.Close()
+
+	_, err = docker.Info(ctx)
+	if err != nil {
+		return fmt.Errorf("Failed to get Docker info: '%w'.", err)
+	}
+
+	return nil
+}
+
+func (this *dockerRuntime) BuildImage(ctx context.Context, name string, contextDir string, noCache bool, removeArtifacts bool) (string, error) {
+	docker, err := getDockerClient()
+	if err != nil {
+		return "", err
+	}
+	defer docker.Close()
+
+	buildOptions := types.ImageBuildOptions{
+		Tags:        []string{name},
+		Dockerfile:  "Dockerfile",
+		Remove:      removeArtifacts,
+		ForceRemove: removeArtifacts,
+		NoCache:     noCache,
+	}
+
+	// Create the build context by adding all the relevant files.
+	tar, err := archive.TarWithOptions(contextDir, &archive.TarOptions{})
+	if err != nil {
+		return "", fmt.Errorf("Failed to create tar build context for image '%s': '%w'.", name, err)
+	}
+
+	response, err := docker.ImageBuild(ctx, tar, buildOptions)
+	if err != nil {
+		return "", fmt.Errorf("Failed to run docker image build command: '%w'.", err)
+	}
+
+	output, err := collectBuildOutput(response)
+	if err != nil {
+		return output, fmt.Errorf("Found error(s) in Docker build output: '%w'.", err)
+	}
+
+	return output, nil
+}
+
+func (this *dockerRuntime) RunContainer(ctx context.Context, spec *ContainerSpec) (*ContainerResult, error) {
+	// Get a docker client.
+	// Note that cleaning this up needs to wait until after we are sure the container is dead.
+	// This means we won't be defering the close right away (see cleanupRun()).
+	docker, err := getDockerClient()
+	if err != nil {
+		return nil, err
+	}
+
+	containerConfig, hostConfig := getContainerConfigs(spec)
+
+	log.Debug("Creating container.", log.NewAttr("name", spec.Name))
+	containerInstance, err := docker.ContainerCreate(
+		ctx,
+		containerConfig,
+		hostConfig,
+		nil,
+		nil,
+		spec.Name)
+
+	if err != nil {
+		docker.Close()
+		return nil, fmt.Errorf("Failed to create container '%s': '%w'.", spec.Name, err)
+	}
+
+	// Now that we have the container, we can schedule cleanup in the background.
+	defer func() {
+		go cleanupRun(docker, spec.Name, containerInstance.ID)
+	}()
+
+	startFunc := func() error {
+		return docker.ContainerStart(ctx, containerInstance.ID, container.StartOptions{})
+	}
+
+	return runCreatedContainer(ctx, docker, spec, containerInstance.ID, startFunc)
+}
+
+// Create a container that runs a (/bin/sh) wrapper around the image's command,
+// where the wrapper waits for the start file to exist before executing the image's command.
+// The container is started (so the wrapper is waiting) and then paused.
+func (this *dockerRuntime) createWarmContainer(ctx context.Context, spec *ContainerSpec, startPath string) (string, error) {
+	docker, err := getDockerClient()
+	if err != nil {
+		return "", err
+	}
+	defer docker.Close()
+
+	imageInfo, _, err := docker.ImageInspectWithRaw(ctx, spec.Image)
+	if err != nil {
+		return "", fmt.Errorf("Failed to inspect image '%s': '%w'.", spec.Image, err)
+	}
+
+	if imageInfo.Config == nil {
+		return "", fmt.Errorf("Image '%s' does not have a config.", spec.Image)
+	}
+
+	containerConfig, hostConfig := getContainerConfigs(spec)
+
+	cmd := []string(imageInfo.Config.Cmd)
+	if len(spec.Cmd) > 0 {
+		cmd = spec.Cmd
+	}
+
+	containerConfig.Entrypoint = []string{"/bin/sh", "-c", getWarmContainerScript(startPath), "autograder-warm"}
+	containerConfig.Cmd = append(append([]string{}, imageInfo.Config.Entrypoint...), cmd...)
+
+	log.Debug("Creating warm container.", log.NewAttr("name", spec.Name))
+	containerInstance, err := docker.ContainerCreate(ctx, containerConfig, hostConfig, nil, nil, spec.Name)
+	if err != nil {
+		return "", fmt.Errorf("Failed to create warm container '%s': '%w'.", spec.Name, err)
+	}
+
+	err = docker.ContainerStart(ctx, containerInstance.ID, container.StartOptions{})
+	if err == nil {
+		err = docker.ContainerPause(ctx, containerInstance.ID)
+	}
+
+	// Start failures usually mean that the wrapper could not be run (e.g., the image does not have /bin/sh).
+	if err != nil {
+		killContainer(docker, spec.Name, containerInstance.ID)
+		return "", fmt.Errorf("Failed to start and pause warm container '%s': '%w': '%w'.", spec.Name, errWarmContainerUnsupported, err)
+	}
+
+	return containerInstance.ID, nil
+}
+
+// Unpause a warm container (see createWarmContainer()), and run it (like RunContainer()).
+// The start function should create the container's start file.
+func (this *dockerRuntime) runWarmContainer(ctx context.Context, spec *ContainerSpec, id string, start func() error) (*ContainerResult, error) {
+	docker, err := getDockerClient()
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		go cleanupRun(docker, spec.Name, id)
+	}()
+
+	err = docker.ContainerUnpause(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to unpause warm container '%s' (%s): '%w': '%w'.", spec.Name, id, errWarmContainerUnavailable, err)
+	}
+
+	return runCreatedContainer(ctx, docker, spec, id, start)
+}
+
+func (this *dockerRuntime) removeContainer(name string, id string) {
+	docker, err := getDockerClient()
+	if err != nil {
+		log.Warn("Failed to get docker client to remove container.", err, log.NewAttr("name", name), log.NewAttr("id", id))
+		return
+	}
+
+	cleanupRun(docker, name, id)
+}
+
+func getContainerConfigs(spec *ContainerSpec) (*container.Config, *container.HostConfig) {
+	dockerMounts := make([]mount.Mount, 0, len(spec.Mounts))
+	for _, mount := range spec.Mounts {
+		dockerMounts = append(dockerMounts, mount.ToDocker())
+	}
+
+	containerConfig := &container.Config{
+		Image: spec.Image,
+		Cmd:   spec.Cmd,
+	}
+
+	hostConfig := &container.HostConfig{
+		Mounts: dockerMounts,
+		LogConfig: container.LogConfig{
+			// Don't store any logs, we will copy stdout/stderr directly.
+			Type: "none",
+		},
+	}
+
+	spec.Limits.apply(containerConfig, hostConfig)
+
+	return containerConfig, hostConfig
+}
+
+// Attach to a created container, start it (with the given function), and wait for it to finish.
+// The caller is responsible for cleaning up the container.
+func runCreatedContainer(ctx context.Context, docker *client.Client, spec *ContainerSpec, id string, start func() error) (*ContainerResult, error) {
+	// Attach to the container so we can get stdout and stderr.
+	log.Trace("Attaching container.", log.NewAttr("name", spec.Name))
+	connection, err := docker.ContainerAttach(ctx, id, container.AttachOptions{
+		Stream: true,
+		Stdout: true,
+		Stderr: true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to attach to container '%s' (%s): '%w'.", spec.Name, id, err)
+	}
+	defer connection.Close()
+
+	// Handle copying (and possibly truncating) stdout/stderr.
+	result := &ContainerResult{
+		ID: id,
+	}
+
+	outputWaitGroup := &sync.WaitGroup{}
+	outputWaitGroup.Add(1)
+	go handleContainerOutput(ctx, &result.containerOutput, outputWaitGroup, connection, spec.Live)
+
+	log.Trace("Starting container.", log.NewAttr("name", spec.Name))
+	err = start()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to start container '%s' (%s): '%w'.", spec.Name, id, err)
+	}
+
+	// Wait for the container to finish.
+	log.Trace("Waiting for container.", log.NewAttr("name", spec.Name))
+	statusChan, errorChan := docker.ContainerWait(ctx, id, container.WaitConditionNotRunning)
+	select {
+	case err := <-errorChan:
+		if err != nil {
+			// On a timeout or cancel exit this select, and try to recover the output.
+			timeout := errors.Is(err, context.DeadlineExceeded)
+			canceled := errors.Is(err, context.Canceled)
+			if timeout || canceled {
+				break
+			}
+
+			return nil, fmt.Errorf("Got an error when running container '%s' (%s): '%w'.", spec.Name, id, err)
+		}
+	case <-statusChan:
+		// Waiting is complete.
+	case <-ctx.Done():
+		// The context finished but the result has not shown on the error chan (yet).
+	}
+
+	// Wait for output to get copied.
+	log.Trace("Waiting for container output.", log.NewAttr("name", spec.Name))
+	outputWaitGroup.Wait()
+
+	// Check if the container was stopped by any limits.
+	// The run context may already be done, so use a fresh one.
+	containerInfo, err := docker.ContainerInspect(context.Background(), id)
+	if err != nil {
+		log.Warn("Failed to inspect finished container.", err, log.NewAttr("name", spec.Name))
+	} else if containerInfo.State != nil {
+		result.OOMKilled = containerInfo.State.OOMKilled
+	}
+
+	return result, nil
+}
+
+// Docker lists images by their tags, so the image's name should include a tag (e.g., ':latest').
+func (this *dockerRuntime) InspectImage(ctx context.Context, name string) (*ImageSummary, error) {
+	docker, err := getDockerClient()
+	if err != nil {
+		return nil, err
+	}
+	defer docker.Close()
+
+	images, err := docker.ImageList(ctx, image.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to list docker images: '%w'.", err)
+	}
+
+	for _, image := range images {
+		for _, tag := range image.RepoTags {
+			if tag == name {
+				return &ImageSummary{
+					Name:    name,
+					Created: timestamp.Timestamp(image.Created * 1000),
+					Size:    image.Size,
+				}, nil
+			}
+		}
+	}
+
+	return nil, nil
+}
+
+func (this *dockerRuntime) PullImage(ctx context.Context, name string) (string, error) {
+	docker, err := getDockerClient()
+	if err != nil {
+		return "", err
+	}
+	defer docker.Close()
+
+	output, err := docker.ImagePull(ctx, name, image.PullOptions{})
+	if err != nil {
+		return "", fmt.Errorf("Failed to run docker image pull command: '%w'.", err)
+	}
+	defer output.Close()
+
+	var buffer bytes.Buffer
+	io.Copy(&buffer, output)
+
+	return buffer.String(), nil
+}
+
+// The returned reader will hold a client connection until it is closed.
+func (this *dockerRuntime) SaveImage(ctx context.Context, name string) (io.ReadCloser, error) {
+	docker, err := getDockerClient()
+	if err != nil {
+		return nil, err
+	}
+
+	reader, err := docker.ImageSave(ctx, []string{name})
+	if err != nil {
+		docker.Close()
+		return nil, fmt.Errorf("Failed to get reader for image '%s': '%w'.", name, err)
+	}
+
+	return &closeAllReader{reader, []io.Closer{reader, docker}}, nil
+}
+
+func getDockerClient() (*client.Client, error) {
+	docker, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+	if err != nil {
+		return nil, fmt.Errorf("Cannot create Docker client: '%w'.", err)
+	}
+
+	return docker, nil
+}
+
+// Try to get the build output from a build response.
+// Note that the response may be from a failure.
+func collectBuildOutput(response types.ImageBuildResponse) (string, error) {
+	if response.Body == nil {
+		return "", nil
+	}
+
+	defer response.Body.Close()
+
+	output := strings.Builder{}
+	var errs error = nil
+
+	responseScanner := bufio.NewScanner(response.Body)
+	for responseScanner.Scan() {
+		line := responseScanner.Text()
+
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		jsonData, err := util.JSONMapFromString(line)
+		if err != nil {
+			output.WriteString("<WARNING: The following output line was not JSON.>")
+			output.WriteString(line)
+		}
+
+		rawText, ok := jsonData["error"]
+		if ok {
+			text, ok := rawText.(string)
+			if !ok {
+				text = "<ERROR: Docker output JSON value is not a string.>"
+			}
+
+			text = strings.TrimSpace(text)
+			if text != "" {
+				errs = errors.Join(errs, fmt.Errorf("%s", text))
+			}
+		}
+
+		rawText, ok = jsonData["stream"]
+		if ok {
+			text, ok := rawText.(string)
+			if !ok {
+				text = "<ERROR: Docker output JSON value is not a string.>"
+			}
+
+			output.WriteString(text)
+		}
+	}
+
+	err := responseScanner.Err()
+	if err != nil {
+		errs = errors.Join(errs, fmt.Errorf("Failed to scan docker image build response: '%w'.", err))
+	}
+
+	return output.String(), errs
+}
+
+// Read a maximum amount from the container's stdout/stderr, parse the two from the common stream, and signal completion.
+func handleContainerOutput(ctx context.Context, output *containerOutput, outputWaitGroup *sync.WaitGroup, connection types.HijackedResponse, live *LiveOutput) {
+	defer outputWaitGroup.Done()
+
+	// Closing the connection should also close the reader and stop any waiting read operations.
+	defer connection.Close()
+
+	successChan := make(chan bool, 1)
+
+	// Start trying to read in another thread.
+	go func() {
+		handleContainerOutputInternal(output, connection.Reader, live)
+		successChan <- true
+	}()
+
+	// Wait for either the context or read to complete.
+	select {
+	case <-successChan:
+		return
+	case <-ctx.Done():
+		// Close the connection to interrupt any blocking reads in handleContainerOutputInternal,
+		// then wait for it to finish writing to the output struct before signaling the WaitGroup.
+		connection.Close()
+		<-successChan
+		return
+	}
+}
+
+func handleContainerOutputInternal(output *containerOutput, containerStream io.Reader, live *LiveOutput) {
+	// Send a copy of everything we read to the live output (as it is read).
+	if live != nil {
+		liveReader, liveWriter := io.Pipe()
+		liveDone := make(chan bool, 1)
+
+		go func() {
+			stdcopy.StdCopy(live.getStdout(), live.getStderr(), liveReader)
+
+			// If the stream could not be parsed, keep reading so the copy below does not block.
+			io.Copy(io.Discard, liveReader)
+			liveDone <- true
+		}()
+
+		defer func() {
+			liveWriter.Close()
+			<-liveDone
+		}()
+
+		containerStream = io.TeeReader(containerStream, liveWriter)
+	}
+
+	bufferLen := config.DOCKER_MAX_OUTPUT_SIZE_KB.Get() * 1024
+
+	// Make the first full (or short) read.
+	buffer := make([]byte, bufferLen)
+	_, err := io.ReadFull(containerStream, buffer)
+	if (err != nil) && (err != io.EOF) && (err != io.ErrUnexpectedEOF) {
+		output.Err = fmt.Errorf("Failed to read container output into temporary buffer: '%w'.", err)
+		return
+	}
+
+	// Check for too much output.
+	truncated := false
+	overflowBuffer := make([]byte, 1)
+	readCount, _ := containerStream.Read(overflowBuffer)
+	if readCount == 1 {
+		truncated = true
+	}
+
+	// Parse stdout and stderr out of the output stream.
+	outBuffer := new(strings.Builder)
+	errBuffer := new(strings.Builder)
+
+	stdcopy.StdCopy(outBuffer, errBuffer, bytes.NewReader(buffer))
+
+	output.set(outBuffer.String(), errBuffer.String(), truncated)
+}
+
+func (this MountInfo) ToDocker() mount.Mount {
+	return mount.Mount{
+		Type:     "bind",
+		Source:   this.Source,
+		Target:   this.Target,
+		ReadOnly: this.ReadOnly,
+	}
+}
+
+func killContainer(docker *client.Client, name string, id string) {
+	// The container should have already gracefully exited.
+	// If not, kill it without any grace.
+	// Ignore any errors.
+	docker.ContainerKill(context.Background(), id, "KILL")
+
+	err := docker.ContainerRemove(context.Background(), id, container.RemoveOptions{
+		Force: true,
+	})
+	if err != nil {
+		log.Warn("Failed to remove container.", err, log.NewAttr("name", name), log.NewAttr("id", id))
+	}
+}
+
+// Cleanup any leftovers from the running the container.
+// This should generally be called in another go routine to prevent blocking.
+func cleanupRun(docker *client.Client, containerName string, containerID string) {
+	killContainer(docker, containerName, containerID)
+
+	err := docker.Close()
+	if err != nil {
+		log.Warn("Failed to close docker client connection.", err)
+	}
+}
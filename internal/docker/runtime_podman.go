@@ -0,0 +1,238 @@
+package docker
+
+// A container runtime that uses the Podman CLI.
+// Podman does not need a daemon, and can be run rootless by the same user as the server.
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/edulinq/autograder/internal/log"
+	"github.com/edulinq/autograder/internal/timestamp"
+)
+
+// Podman uses this exit code when the error is from Podman itself (and not from the container).
+const PODMAN_ERROR_EXIT_CODE = 125
+
+// How long to wait for output to be copied after a podman command has been killed.
+const podmanWaitDelay = 2 * time.Second
+
+type podmanRuntime struct {
+	path string
+}
+
+type podmanImageInspect struct {
+	Created time.Time `json:"Created"`
+	Size    int64     `json:"Size"`
+}
+
+func (this *podmanRuntime) Ping(ctx context.Context) error {
+	_, err := this.runCommand(ctx, "version")
+	return err
+}
+
+func (this *podmanRuntime) BuildImage(ctx context.Context, name string, contextDir string, noCache bool, removeArtifacts bool) (string, error) {
+	args := []string{
+		"build",
+		"--tag", name,
+		"--file", "Dockerfile",
+		fmt.Sprintf("--rm=%v", removeArtifacts),
+		fmt.Sprintf("--force-rm=%v", removeArtifacts),
+	}
+
+	if noCache {
+		args = append(args, "--no-cache")
+	}
+
+	args = append(args, ".")
+
+	command := this.newCommand(ctx, args...)
+	command.Dir = contextDir
+
+	output, err := command.CombinedOutput()
+	if err != nil {
+		return string(output), fmt.Errorf("Failed to build image '%s' with podman: '%w'.", name, err)
+	}
+
+	return string(output), nil
+}
+
+// Run the container attached (so we can get stdout and stderr) and check its state after it exits.
+// The container is not automatically removed (so it can be inspected), instead it is removed in the background.
+func (this *podmanRuntime) RunContainer(ctx context.Context, spec *ContainerSpec) (*ContainerResult, error) {
+	result := &ContainerResult{
+		ID: spec.Name,
+	}
+
+	stdout, stderr := newLimitedOutput(getMaxOutputSize())
+	stdout.live = spec.Live.getStdout()
+	stderr.live = spec.Live.getStderr()
+
+	command := this.newCommand(ctx, getPodmanRunArgs(spec)...)
+	command.Stdout = stdout
+	command.Stderr = stderr
+
+	// Podman will only exit once the container has been created and the container's output has been copied.
+	// So, any container that exists after this point needs to be removed.
+	defer func() {
+		go this.cleanupRun(spec.Name)
+	}()
+
+	log.Debug("Running container.", log.NewAttr("name", spec.Name))
+	err := command.Run()
+
+	// The context finishing (timeout or cancel) is not an error, we just use whatever output we got.
+	if (err != nil) && (ctx.Err() == nil) {
+		exitError := &exec.ExitError{}
+		if !errors.As(err, &exitError) || (exitError.ExitCode() == PODMAN_ERROR_EXIT_CODE) {
+			return nil, fmt.Errorf("Failed to run container '%s' with podman: '%w'. Stderr: '%s'.", spec.Name, err, stderr.String())
+		}
+
+		// Any other exit code is from the container.
+	}
+
+	result.set(stdout.String(), stderr.String(), stdout.Truncated())
+
+	// Check if the container was stopped by any limits.
+	// The run context may already be done, so use a fresh one.
+	output, err := this.runCommand(context.Background(), "container", "inspect", "--format", "{{.State.OOMKilled}}", spec.Name)
+	if err != nil {
+		log.Warn("Failed to inspect finished container.", err, log.NewAttr("name", spec.Name))
+	} else {
+		result.OOMKilled = (strings.TrimSpace(output) == "true")
+	}
+
+	return result, nil
+}
+
+func (this *podmanRuntime) InspectImage(ctx context.Context, name string) (*ImageSummary, error) {
+	// Podman uses a non-zero exit code to signal that the image does not exist.
+	err := this.newCommand(ctx, "image", "exists", name).Run()
+	if err != nil {
+		exitError := &exec.ExitError{}
+		if errors.As(err, &exitError) && (exitError.ExitCode() == 1) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("Failed to check if image '%s' exists with podman: '%w'.", name, err)
+	}
+
+	output, err := this.runCommand(ctx, "image", "inspect", name)
+	if err != nil {
+		return nil, err
+	}
+
+	return parsePodmanImageInspect(name, output)
+}
+
+func (this *podmanRuntime) PullImage(ctx context.Context, name string) (string, error) {
+	output, err := this.newCommand(ctx, "pull", name).CombinedOutput()
+	if err != nil {
+		return string(output), fmt.Errorf("Failed to pull image '%s' with podman: '%w'.", name, err)
+	}
+
+	return string(output), nil
+}
+
+// Images are saved in the same archive format as Docker.
+// Closing the returned reader will wait for the podman process to exit.
+func (this *podmanRuntime) SaveImage(ctx context.Context, name string) (io.ReadCloser, error) {
+	command := this.newCommand(ctx, "save", "--format", "docker-archive", name)
+
+	reader, err := command.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get output pipe for podman save of image '%s': '%w'.", name, err)
+	}
+
+	err = command.Start()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to start podman save of image '%s': '%w'.", name, err)
+	}
+
+	return &closeAllReader{reader, []io.Closer{reader, closerFunc(command.Wait)}}, nil
+}
+
+// Remove the container (killing it if it is still running).
+// This should generally be called in another go routine to prevent blocking.
+func (this *podmanRuntime) cleanupRun(name string) {
+	_, err := this.runCommand(context.Background(), "rm", "--force", "--ignore", name)
+	if err != nil {
+		log.Warn("Failed to remove container.", err, log.NewAttr("name", name))
+	}
+}
+
+func (this *podmanRuntime) newCommand(ctx context.Context, args ...string) *exec.Cmd {
+	command := exec.CommandContext(ctx, this.path, args...)
+	command.WaitDelay = podmanWaitDelay
+
+	return command
+}
+
+// Run a podman command and return its stdout.
+func (this *podmanRuntime) runCommand(ctx context.Context, args ...string) (string, error) {
+	stderr := &strings.Builder{}
+
+	command := this.newCommand(ctx, args...)
+	command.Stderr = stderr
+
+	output, err := command.Output()
+	if err != nil {
+		return "", fmt.Errorf("Failed to run podman command '%s': '%w'. Stderr: '%s'.", strings.Join(args, " "), err, stderr.String())
+	}
+
+	return string(output), nil
+}
+
+// Get the arguments for a "podman run" (including the "run").
+func getPodmanRunArgs(spec *ContainerSpec) []string {
+	args := []string{
+		"run",
+		"--name", spec.Name,
+		// Don't store any logs, we will copy stdout/stderr directly.
+		"--log-driver", "none",
+	}
+
+	for _, mount := range spec.Mounts {
+		args = append(args, "--mount", mount.toPodman())
+	}
+
+	args = append(args, spec.Limits.podmanArgs()...)
+
+	args = append(args, spec.Image)
+	args = append(args, spec.Cmd...)
+
+	return args
+}
+
+func parsePodmanImageInspect(name string, text string) (*ImageSummary, error) {
+	var images []podmanImageInspect
+	err := json.Unmarshal([]byte(text), &images)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse podman image inspect output for image '%s': '%w'.", name, err)
+	}
+
+	if len(images) == 0 {
+		return nil, nil
+	}
+
+	return &ImageSummary{
+		Name:    name,
+		Created: timestamp.FromGoTime(images[0].Created),
+		Size:    images[0].Size,
+	}, nil
+}
+
+func (this MountInfo) toPodman() string {
+	value := fmt.Sprintf("type=bind,src=%s,dst=%s", this.Source, this.Target)
+	if this.ReadOnly {
+		value += ",ro=true"
+	}
+
+	return value
+}
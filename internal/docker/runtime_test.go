@@ -0,0 +1,190 @@
+package docker
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/edulinq/autograder/internal/config"
+	"github.com/edulinq/autograder/internal/timestamp"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestGetRuntime(test *testing.T) {
+	oldRuntime := config.DOCKER_RUNTIME.Get()
+	oldPodmanPath := config.DOCKER_PODMAN_PATH.Get()
+
+	defer func() {
+		config.DOCKER_RUNTIME.Set(oldRuntime)
+		config.DOCKER_PODMAN_PATH.Set(oldPodmanPath)
+	}()
+
+	config.DOCKER_PODMAN_PATH.Set("/usr/local/bin/podman")
+
+	testCases := []struct {
+		name     string
+		expected ContainerRuntime
+	}{
+		{"", &dockerRuntime{}},
+		{"docker", &dockerRuntime{}},
+		{" Docker ", &dockerRuntime{}},
+		{"podman", &podmanRuntime{path: "/usr/local/bin/podman"}},
+		{"PODMAN", &podmanRuntime{path: "/usr/local/bin/podman"}},
+		{"containerd", nil},
+	}
+
+	for i, testCase := range testCases {
+		config.DOCKER_RUNTIME.Set(testCase.name)
+
+		actual, err := GetRuntime()
+		if testCase.expected == nil {
+			if err == nil {
+				test.Errorf("Case %d: Did not get an expected error.", i)
+			}
+
+			continue
+		}
+
+		if err != nil {
+			test.Errorf("Case %d: Failed to get runtime: '%v'.", i, err)
+			continue
+		}
+
+		if !reflect.DeepEqual(testCase.expected, actual) {
+			test.Errorf("Case %d: Unexpected runtime. Expected: '%#v', Actual: '%#v'.", i, testCase.expected, actual)
+			continue
+		}
+	}
+}
+
+func TestGetPodmanRunArgs(test *testing.T) {
+	testCases := []struct {
+		spec     *ContainerSpec
+		expected []string
+	}{
+		{
+			&ContainerSpec{Name: "a", Image: "image"},
+			[]string{"run", "--name", "a", "--log-driver", "none", "--network=none", "image"},
+		},
+		{
+			&ContainerSpec{
+				Name:  "a",
+				Image: "image",
+				Mounts: []MountInfo{
+					MountInfo{Source: "/in", Target: "/autograder/input", ReadOnly: true},
+					MountInfo{Source: "/out", Target: "/autograder/output"},
+				},
+				Cmd:    []string{"echo", "hello world"},
+				Limits: &ContainerLimits{MaxMemoryMB: 2, AllowNetwork: true},
+			},
+			[]string{
+				"run", "--name", "a", "--log-driver", "none",
+				"--mount", "type=bind,src=/in,dst=/autograder/input,ro=true",
+				"--mount", "type=bind,src=/out,dst=/autograder/output",
+				"--memory=2m", "--memory-swap=2m",
+				"image", "echo", "hello world",
+			},
+		},
+	}
+
+	for i, testCase := range testCases {
+		actual := getPodmanRunArgs(testCase.spec)
+		if !reflect.DeepEqual(testCase.expected, actual) {
+			test.Errorf("Case %d: Unexpected args. Expected: '%s', Actual: '%s'.",
+				i, util.MustToJSONIndent(testCase.expected), util.MustToJSONIndent(actual))
+			continue
+		}
+	}
+}
+
+func TestLimitedOutput(test *testing.T) {
+	testCases := []struct {
+		maxSize           int
+		stdoutWrites      []string
+		stderrWrites      []string
+		expectedStdout    string
+		expectedStderr    string
+		expectedTruncated bool
+	}{
+		{10, nil, nil, "", "", false},
+		{10, []string{"abc"}, []string{"def"}, "abc", "def", false},
+		{6, []string{"abc"}, []string{"def"}, "abc", "def", false},
+		{5, []string{"abc"}, []string{"def"}, "abc", "de", true},
+		{5, []string{"abc", "def"}, []string{"ghi"}, "abcde", "", true},
+		{0, []string{"abc"}, nil, "", "", true},
+	}
+
+	for i, testCase := range testCases {
+		stdout, stderr := newLimitedOutput(testCase.maxSize)
+
+		var liveStdout strings.Builder
+		var liveStderr strings.Builder
+		stdout.live = &liveStdout
+		stderr.live = &liveStderr
+
+		for _, text := range testCase.stdoutWrites {
+			count, err := stdout.Write([]byte(text))
+			if (err != nil) || (count != len(text)) {
+				test.Errorf("Case %d: Bad stdout write. Count: %d, Error: '%v'.", i, count, err)
+			}
+		}
+
+		for _, text := range testCase.stderrWrites {
+			count, err := stderr.Write([]byte(text))
+			if (err != nil) || (count != len(text)) {
+				test.Errorf("Case %d: Bad stderr write. Count: %d, Error: '%v'.", i, count, err)
+			}
+		}
+
+		if (testCase.expectedStdout != stdout.String()) || (testCase.expectedStderr != stderr.String()) {
+			test.Errorf("Case %d: Unexpected output. Expected: ('%s', '%s'), Actual: ('%s', '%s').",
+				i, testCase.expectedStdout, testCase.expectedStderr, stdout.String(), stderr.String())
+			continue
+		}
+
+		if (testCase.expectedStdout != liveStdout.String()) || (testCase.expectedStderr != liveStderr.String()) {
+			test.Errorf("Case %d: Unexpected live output. Expected: ('%s', '%s'), Actual: ('%s', '%s').",
+				i, testCase.expectedStdout, testCase.expectedStderr, liveStdout.String(), liveStderr.String())
+			continue
+		}
+
+		if (testCase.expectedTruncated != stdout.Truncated()) || (testCase.expectedTruncated != stderr.Truncated()) {
+			test.Errorf("Case %d: Unexpected truncation. Expected: '%v', Actual: ('%v', '%v').",
+				i, testCase.expectedTruncated, stdout.Truncated(), stderr.Truncated())
+			continue
+		}
+	}
+}
+
+func TestParsePodmanImageInspect(test *testing.T) {
+	text := `[{"Id": "abc", "Created": "2024-01-02T03:04:05.5Z", "Size": 1234}]`
+
+	expected := &ImageSummary{
+		Name:    "image",
+		Created: timestamp.FromMSecs(1704164645500),
+		Size:    1234,
+	}
+
+	actual, err := parsePodmanImageInspect("image", text)
+	if err != nil {
+		test.Fatalf("Failed to parse inspect output: '%v'.", err)
+	}
+
+	if !reflect.DeepEqual(expected, actual) {
+		test.Fatalf("Unexpected summary. Expected: '%s', Actual: '%s'.", util.MustToJSONIndent(expected), util.MustToJSONIndent(actual))
+	}
+
+	actual, err = parsePodmanImageInspect("image", "[]")
+	if err != nil {
+		test.Fatalf("Failed to parse empty inspect output: '%v'.", err)
+	}
+
+	if actual != nil {
+		test.Fatalf("Unexpected summary for empty output: '%s'.", util.MustToJSONIndent(actual))
+	}
+
+	_, err = parsePodmanImageInspect("image", "not json")
+	if err == nil {
+		test.Fatalf("Did not get an error on bad inspect output.")
+	}
+}
@@ -2,22 +2,18 @@ package docker
 
 import (
 	"context"
-	"fmt"
-
-	"github.com/docker/docker/client"
+	"errors"
+	"io"
 )
 
+// Check if the configured container runtime (see GetRuntime()) can be used.
 func CanAccessDocker() bool {
-	docker, err := getDockerClient()
-	if docker != nil {
-		defer docker.Close()
-	}
-
+	runtime, err := GetRuntime()
 	if err != nil {
 		return false
 	}
 
-	_, err = docker.Info(context.Background())
+	err = runtime.Ping(context.Background())
 	if err != nil {
 		return false
 	}
@@ -25,11 +21,23 @@ func CanAccessDocker() bool {
 	return true
 }
 
-func getDockerClient() (*client.Client, error) {
-	docker, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
-	if err != nil {
-		return nil, fmt.Errorf("Cannot create Docker client: '%w'.", err)
+// A reader that closes several resources (in order) when it is closed.
+type closeAllReader struct {
+	io.Reader
+	closers []io.Closer
+}
+
+type closerFunc func() error
+
+func (this *closeAllReader) Close() error {
+	var errs error = nil
+	for _, closer := range this.closers {
+		errs = errors.Join(errs, closer.Close())
 	}
 
-	return docker, nil
+	return errs
+}
+
+func (this closerFunc) Close() error {
+	return this()
 }
@@ -19,7 +19,7 @@ import (
 //   - work -- Should already be created inside the docker image, will only exist within the container.
 //
 // Returns: (result, file contents, stdout, stderr, failure message (soft failure), error (hard failure)).
-func runDockerGrader(ctx context.Context, assignment *model.Assignment, submissionPath string, options GradeOptions, fullSubmissionID string) (*model.GradingInfo, map[string][]byte, string, string, string, error) {
+func runDockerGrader(ctx context.Context, assignment *model.Assignment, submissionPath string, options GradeOptions, fullSubmissionID string, live *docker.LiveOutput) (*model.GradingInfo, map[string][]byte, string, string, string, error) {
 	tempDir, inputDir, outputDir, _, err := common.PrepTempGradingDir("docker")
 	if err != nil {
 		return nil, nil, "", "", "", err
@@ -37,7 +37,9 @@ func runDockerGrader(ctx context.Context, assignment *model.Assignment, submissi
 		return nil, nil, "", "", "", fmt.Errorf("Failed to copy over submission/input contents: '%w'.", err)
 	}
 
-	stdout, stderr, timeout, canceled, err := docker.RunGradingContainer(ctx, assignment, assignment.GetImageName(), inputDir, outputDir, fullSubmissionID, assignment.MaxRuntimeSecs)
+	limits := assignment.GetContainerLimits()
+
+	stdout, stderr, timeout, canceled, exceededLimit, err := docker.RunGradingContainer(ctx, assignment, assignment.GetImageName(), inputDir, outputDir, fullSubmissionID, assignment.MaxRuntimeSecs, limits, assignment.GetContainerPoolOptions(), live)
 	if err != nil {
 		return nil, nil, stdout, stderr, "", err
 	}
@@ -52,6 +54,12 @@ func runDockerGrader(ctx context.Context, assignment *model.Assignment, submissi
 
 	resultPath := filepath.Join(outputDir, common.GRADER_OUTPUT_RESULT_FILENAME)
 	if !util.PathExists(resultPath) {
+		// If a limit was hit, then the grader most likely could not write its result.
+		if exceededLimit != docker.RESOURCE_LIMIT_NONE {
+			log.Debug("Grading container exceeded a resource limit.", assignment, log.NewAttr("limit", exceededLimit))
+			return nil, nil, stdout, stderr, getResourceLimitMessage(exceededLimit, limits), nil
+		}
+
 		log.Warn("Cannot find output file after the grading container was run.",
 			log.NewAttr("path", resultPath), log.NewAttr("image", assignment.GetImageName()))
 
@@ -3,6 +3,7 @@ package grader
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/edulinq/autograder/internal/common"
@@ -47,23 +48,27 @@ func GradeDefault(assignment *model.Assignment, submissionPath string, user stri
 }
 
 // Grade with custom options.
+// For group assignments, the submission is credited to every member of the user's group
+// (the returned result is always the submitting user's copy).
 // Return (result, reject, softGradingError, error).
 // Full success is only when ((reject == nil) && (softGradingError == "") && (error == nil)).
 func Grade(ctx context.Context, assignment *model.Assignment, submissionPath string, user string, message string, options GradeOptions) (
 	*model.GradingResult, RejectReason, string, error) {
-	gradingKey := fmt.Sprintf("%s::%s::%s", assignment.GetCourse().GetID(), assignment.GetID(), user)
-
 	// Get the grading start time right before we acquire the user's lock.
 	startTimestamp := timestamp.Now()
 
-	// Ensure the user can only have one submission (of each assignment) running at a time.
-	lockmanager.Lock(gradingKey)
+	// Ensure the user (or group) can only have one submission (of each assignment) running at a time.
+	gradingKey, members, group, err := lockForGrading(assignment, user)
+	if err != nil {
+		return nil, nil, "", fmt.Errorf("Failed to get group members: '%w'.", err)
+	}
+
 	defer lockmanager.Unlock(gradingKey)
 
 	// Check for rejection inside the lock to prevent TOCTOU race conditions
 	// where concurrent submissions could both pass the limit check before either acquires the lock.
 	if options.CheckRejection {
-		reject, err := checkForRejection(assignment, submissionPath, user, message, options.AllowLate)
+		reject, err := checkForRejection(assignment, submissionPath, user, members, message, options.AllowLate)
 		if err != nil {
 			return nil, nil, "", fmt.Errorf("Failed to check for rejection: '%w'.", err)
 		}
@@ -73,7 +78,7 @@ func Grade(ctx context.Context, assignment *model.Assignment, submissionPath str
 		}
 	}
 
-	submissionID, inputFileContents, err := prepForGrading(assignment, submissionPath, user)
+	submissionID, inputFileContents, err := prepForGrading(assignment, submissionPath, members)
 	if err != nil {
 		return nil, nil, "", fmt.Errorf("Failed to prep for grading: '%w'.", err)
 	}
@@ -83,7 +88,11 @@ func Grade(ctx context.Context, assignment *model.Assignment, submissionPath str
 
 	fullSubmissionID := common.CreateFullSubmissionID(assignment.GetCourse().GetID(), assignment.GetID(), user, submissionID)
 
-	gradingInfo, outputFileContents, stdout, stderr, softGradingError, err := runGrader(ctx, assignment, submissionPath, options, fullSubmissionID)
+	// Let users follow along while the submission is graded.
+	stream := startGradingStream(assignment, members, submissionID)
+	defer stream.finish()
+
+	gradingInfo, outputFileContents, stdout, stderr, softGradingError, err := runGrader(ctx, assignment, submissionPath, options, fullSubmissionID, stream.getLiveOutput())
 
 	endTimestamp := timestamp.Now()
 
@@ -98,6 +107,7 @@ func Grade(ctx context.Context, assignment *model.Assignment, submissionPath str
 
 	// Check for soft grading errors.
 	if softGradingError != "" {
+		stream.setResult(false, softGradingError)
 		return &gradingResult, nil, softGradingError, nil
 	}
 
@@ -110,6 +120,10 @@ func Grade(ctx context.Context, assignment *model.Assignment, submissionPath str
 	gradingInfo.Message = message
 	gradingInfo.ProxyUser = options.ProxyUser
 
+	if group != nil {
+		gradingInfo.GroupID = group.ID
+	}
+
 	if options.ProxyTime == nil {
 		gradingInfo.GradingStartTime = startTimestamp
 		gradingInfo.GradingEndTime = endTimestamp
@@ -121,11 +135,19 @@ func Grade(ctx context.Context, assignment *model.Assignment, submissionPath str
 	}
 
 	gradingInfo.ComputePoints()
+	stream.addQuestions(gradingInfo.Questions)
 
 	gradingResult.Info = gradingInfo
 	gradingResult.OutputFilesGZip = outputFileContents
 
-	err = db.SaveSubmission(assignment, &gradingResult)
+	submissions := []*model.GradingResult{&gradingResult}
+	for _, member := range members {
+		if member != user {
+			submissions = append(submissions, copyGradingResultForUser(&gradingResult, member))
+		}
+	}
+
+	err = db.SaveSubmissions(assignment.GetCourse(), submissions)
 	if err != nil {
 		return &gradingResult, nil, "", fmt.Errorf("Failed to save grading result: '%w'.", err)
 	}
@@ -144,6 +166,8 @@ func Grade(ctx context.Context, assignment *model.Assignment, submissionPath str
 	// Store stats for this grading (when everything is successful).
 	stats.AsyncStoreMetric(&metric)
 
+	stream.setResult(true, "")
+
 	return &gradingResult, nil, "", nil
 }
 
@@ -170,16 +194,56 @@ func ResolveProxyTime(proxyTime *timestamp.Timestamp, assignment *model.Assignme
 	return &minuteBeforeDueDate
 }
 
-func prepForGrading(assignment *model.Assignment, submissionPath string, user string) (string, map[string][]byte, error) {
+// Acquire the grading lock for a user (or the user's group, for group assignments).
+// The user's group is resolved again once the lock is held (since the user may have changed groups while waiting),
+// and the lock is re-acquired if the group changed.
+// On success, the caller must unlock the returned key.
+// Returns: (lock key, members, group (may be nil), error).
+func lockForGrading(assignment *model.Assignment, user string) (string, []string, *model.AssignmentGroup, error) {
+	_, group, err := db.GetAssignmentGroupMembers(assignment, user)
+	if err != nil {
+		return "", nil, nil, err
+	}
+
+	for {
+		gradingKey := getGradingKey(assignment, user, group)
+		lockmanager.Lock(gradingKey)
+
+		members, lockedGroup, err := db.GetAssignmentGroupMembers(assignment, user)
+		if err != nil {
+			lockmanager.Unlock(gradingKey)
+			return "", nil, nil, err
+		}
+
+		if gradingKey == getGradingKey(assignment, user, lockedGroup) {
+			return gradingKey, members, lockedGroup, nil
+		}
+
+		lockmanager.Unlock(gradingKey)
+		group = lockedGroup
+	}
+}
+
+func getGradingKey(assignment *model.Assignment, user string, group *model.AssignmentGroup) string {
+	if group != nil {
+		return fmt.Sprintf("%s::%s::group::%s", assignment.GetCourse().GetID(), assignment.GetID(), group.ID)
+	}
+
+	return fmt.Sprintf("%s::%s::%s", assignment.GetCourse().GetID(), assignment.GetID(), user)
+}
+
+// Prepare for grading a submission that will be saved for each of the given users.
+// The submission ID will be available for all the users.
+func prepForGrading(assignment *model.Assignment, submissionPath string, users []string) (string, map[string][]byte, error) {
 	// Ensure the assignment docker image is built.
 	err := docker.BuildImageFromSourceQuick(assignment)
 	if err != nil {
 		return "", nil, fmt.Errorf("Failed to build assignment '%s' docker image: '%w'.", assignment.FullID(), err)
 	}
 
-	submissionID, err := db.GetNextSubmissionID(assignment, user)
+	submissionID, err := getNextSharedSubmissionID(assignment, users)
 	if err != nil {
-		return "", nil, fmt.Errorf("Unable to get next submission id for assignment '%s', user '%s': '%w'.", assignment.FullID(), user, err)
+		return "", nil, err
 	}
 
 	fileContents, err := util.GzipDirectoryToBytes(submissionPath)
@@ -190,6 +254,45 @@ func prepForGrading(assignment *model.Assignment, submissionPath string, user st
 	return submissionID, fileContents, nil
 }
 
+// Get a submission ID that can be used by all the given users.
+// Submission IDs are increasing integers, so the largest next ID is used.
+func getNextSharedSubmissionID(assignment *model.Assignment, users []string) (string, error) {
+	submissionID := ""
+	maxID := int64(-1)
+
+	for _, user := range users {
+		nextID, err := db.GetNextSubmissionID(assignment, user)
+		if err != nil {
+			return "", fmt.Errorf("Unable to get next submission id for assignment '%s', user '%s': '%w'.", assignment.FullID(), user, err)
+		}
+
+		numericID, err := strconv.ParseInt(nextID, 10, 64)
+		if err != nil {
+			return "", fmt.Errorf("Submission id '%s' for assignment '%s', user '%s' is not an integer: '%w'.", nextID, assignment.FullID(), user, err)
+		}
+
+		if numericID > maxID {
+			maxID = numericID
+			submissionID = nextID
+		}
+	}
+
+	return submissionID, nil
+}
+
+// Make a copy of a grading result that is credited to another user (e.g., another group member).
+// File contents are shared with the original result.
+func copyGradingResultForUser(result *model.GradingResult, user string) *model.GradingResult {
+	info := *result.Info
+	info.User = user
+	info.ID = common.CreateFullSubmissionID(info.CourseID, info.AssignmentID, user, info.ShortID)
+
+	resultCopy := *result
+	resultCopy.Info = &info
+
+	return &resultCopy
+}
+
 func getTimeoutMessage(assignment *model.Assignment) string {
 	return fmt.Sprintf("Submission has ran for too long and was killed. Max assignment runtime is %d seconds (server hard limit is %d seconds). Check for infinite loops/recursion and consult with your instructors/TAs.", assignment.MaxRuntimeSecs, config.GRADING_RUNTIME_MAX_SECS.Get())
 }
@@ -198,10 +301,22 @@ func getCanceledMessage(assignment *model.Assignment) string {
 	return "Grading has been canceled (usually by a broken HTTP connection)."
 }
 
+func getResourceLimitMessage(exceededLimit docker.ResourceLimit, limits *docker.ContainerLimits) string {
+	switch exceededLimit {
+	case docker.RESOURCE_LIMIT_MEMORY:
+		return fmt.Sprintf("Submission ran out of memory and was killed. Max assignment memory is %d MB. Check for memory leaks/large data structures and consult with your instructors/TAs.", limits.MaxMemoryMB)
+	case docker.RESOURCE_LIMIT_PIDS:
+		return fmt.Sprintf("Submission tried to create too many processes/threads and could not finish. Max assignment processes/threads is %d. Check for runaway process/thread creation (e.g., fork bombs) and consult with your instructors/TAs.", limits.MaxPIDs)
+	default:
+		return fmt.Sprintf("Submission exceeded a resource limit (%s) and could not finish. Consult with your instructors/TAs.", exceededLimit)
+	}
+}
+
 // Add an additional level for waiting for timeouts.
 // Timeouts should be handled a level below this (e.g., docker or exec),
 // but this is an additional layer just in case there are issues at that level.
-func runGrader(ctx context.Context, assignment *model.Assignment, submissionPath string, options GradeOptions, fullSubmissionID string) (*model.GradingInfo, map[string][]byte, string, string, string, error) {
+// Output will also be sent to the live output (if not nil) while the grader runs.
+func runGrader(ctx context.Context, assignment *model.Assignment, submissionPath string, options GradeOptions, fullSubmissionID string, live *docker.LiveOutput) (*model.GradingInfo, map[string][]byte, string, string, string, error) {
 	var gradingInfo *model.GradingInfo
 	var outputFileContents map[string][]byte
 	var stdout string
@@ -211,9 +326,9 @@ func runGrader(ctx context.Context, assignment *model.Assignment, submissionPath
 
 	runFunc := func() {
 		if options.NoDocker {
-			gradingInfo, outputFileContents, stdout, stderr, softGradingError, err = runNoDockerGrader(ctx, assignment, submissionPath, options, fullSubmissionID)
+			gradingInfo, outputFileContents, stdout, stderr, softGradingError, err = runNoDockerGrader(ctx, assignment, submissionPath, options, fullSubmissionID, live)
 		} else {
-			gradingInfo, outputFileContents, stdout, stderr, softGradingError, err = runDockerGrader(ctx, assignment, submissionPath, options, fullSubmissionID)
+			gradingInfo, outputFileContents, stdout, stderr, softGradingError, err = runDockerGrader(ctx, assignment, submissionPath, options, fullSubmissionID, live)
 		}
 	}
 
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
 	"time"
@@ -11,6 +12,10 @@ import (
 	"github.com/edulinq/autograder/internal/config"
 	"github.com/edulinq/autograder/internal/db"
 	"github.com/edulinq/autograder/internal/docker"
+	"github.com/edulinq/autograder/internal/lockmanager"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/sandbox"
+	"github.com/edulinq/autograder/internal/timestamp"
 	"github.com/edulinq/autograder/internal/util"
 )
 
@@ -31,6 +36,75 @@ func TestNoDockerSubmissions(test *testing.T) {
 	runSubmissionTests(test, false, false)
 }
 
+func TestNoDockerSandboxSubmissions(test *testing.T) {
+	err := sandbox.CheckSupport()
+	if err != nil {
+		test.Skipf("Sandboxing is not supported: '%v'.", err)
+	}
+
+	db.ResetForTesting()
+	defer db.ResetForTesting()
+
+	oldDockerVal := config.DOCKER_DISABLE.Get()
+	config.DOCKER_DISABLE.Set(true)
+	defer config.DOCKER_DISABLE.Set(oldDockerVal)
+
+	oldSandboxVal := config.DOCKER_NODOCKER_SANDBOX.Get()
+	config.DOCKER_NODOCKER_SANDBOX.Set(true)
+	defer config.DOCKER_NODOCKER_SANDBOX.Set(oldSandboxVal)
+
+	// Only the bash assignment can be graded without installing any grader dependencies.
+	assignment := db.MustGetAssignment("course-languages", "bash")
+
+	testSubmissions, err := GetTestSubmissions(assignment.GetSourceDir(), false)
+	if err != nil {
+		test.Fatalf("Error getting test submissions: '%v'.", err)
+	}
+
+	if len(testSubmissions) == 0 {
+		test.Fatalf("Could not find any test submissions.")
+	}
+
+	gradeOptions := GradeOptions{
+		NoDocker: true,
+	}
+
+	for i, testSubmission := range testSubmissions {
+		user := fmt.Sprintf("%03d_%s", i, BASE_TEST_USER)
+
+		result, reject, softError, err := Grade(context.Background(), testSubmission.Assignment, testSubmission.Dir, user, TEST_MESSAGE, gradeOptions)
+		if err != nil {
+			test.Errorf("Case %d (%s): Failed to grade assignment: '%v'.", i, testSubmission.ID, err)
+			continue
+		}
+
+		if reject != nil {
+			test.Errorf("Case %d (%s): Submission was rejected: '%s'.", i, testSubmission.ID, reject.String())
+			continue
+		}
+
+		if testSubmission.TestSubmission.SoftError {
+			if testSubmission.TestSubmission.GradingInfo.Message != softError {
+				test.Errorf("Case %d (%s): Soft error not as expected. Expected: '%s', Actual: '%s'.",
+					i, testSubmission.ID, testSubmission.TestSubmission.GradingInfo.Message, softError)
+			}
+
+			continue
+		}
+
+		if softError != "" {
+			test.Errorf("Case %d (%s): Submission got an unexpected soft error: '%s'. Stderr: '%s'.", i, testSubmission.ID, softError, result.Stderr)
+			continue
+		}
+
+		if !result.Info.Equals(*testSubmission.TestSubmission.GradingInfo, !testSubmission.TestSubmission.IgnoreMessages) {
+			test.Errorf("Case %d (%s): Actual output:\n---\n%v\n---\ndoes not match expected output:\n---\n%v\n---\n.",
+				i, testSubmission.ID, util.MustToJSONIndent(result.Info), util.MustToJSONIndent(testSubmission.TestSubmission.GradingInfo))
+			continue
+		}
+	}
+}
+
 func runSubmissionTests(test *testing.T, parallel bool, useDocker bool) {
 	db.ResetForTesting()
 	defer db.ResetForTesting()
@@ -274,3 +348,151 @@ func testTruncatedOutput(test *testing.T, sizeKB int, expectedTruncated bool) {
 		}
 	}
 }
+
+func TestGradeGroupSubmission(test *testing.T) {
+	db.ResetForTesting()
+	defer db.ResetForTesting()
+
+	assignment := db.MustGetTestSubmissionAssignment()
+	assignment.GroupOptions = &model.GroupOptions{MaxSize: 2}
+
+	members := []string{BASE_TEST_USER, "course-other@test.edulinq.org"}
+	group := &model.AssignmentGroup{
+		ID:           "test-group",
+		CourseID:     assignment.GetCourse().GetID(),
+		AssignmentID: assignment.GetID(),
+		Members:      members,
+		CreatedBy:    "course-admin@test.edulinq.org",
+		CreationTime: timestamp.Now(),
+	}
+
+	err := db.UpsertAssignmentGroup(assignment, group)
+	if err != nil {
+		test.Fatalf("Failed to create group: '%v'.", err)
+	}
+
+	submissionPath := filepath.Join(assignment.GetSourceDir(), SUBMISSION_RELPATH)
+
+	options := GetDefaultGradeOptions()
+	options.CheckRejection = false
+
+	result, reject, softError, err := Grade(context.Background(), assignment, submissionPath, BASE_TEST_USER, TEST_MESSAGE, options)
+	if err != nil {
+		test.Fatalf("Failed to grade assignment: '%v'.", err)
+	}
+
+	if reject != nil {
+		test.Fatalf("Submission was rejected: '%s'.", reject.String())
+	}
+
+	if softError != "" {
+		test.Fatalf("Submission got a soft error: '%s'.", softError)
+	}
+
+	if result.Info.GroupID != group.ID {
+		test.Fatalf("Unexpected group ID. Expected: '%s', Actual: '%s'.", group.ID, result.Info.GroupID)
+	}
+
+	for _, member := range members {
+		memberResult, err := db.GetSubmissionContents(assignment, member, result.Info.ShortID)
+		if err != nil {
+			test.Fatalf("Failed to get submission for member '%s': '%v'.", member, err)
+		}
+
+		if memberResult == nil {
+			test.Fatalf("Could not find submission for member '%s'.", member)
+		}
+
+		if memberResult.Info.User != member {
+			test.Fatalf("Unexpected user for member '%s': '%s'.", member, memberResult.Info.User)
+		}
+
+		if memberResult.Info.GroupID != group.ID {
+			test.Fatalf("Unexpected group ID for member '%s'. Expected: '%s', Actual: '%s'.", member, group.ID, memberResult.Info.GroupID)
+		}
+
+		if memberResult.Info.Score != result.Info.Score {
+			test.Fatalf("Unexpected score for member '%s'. Expected: '%f', Actual: '%f'.", member, result.Info.Score, memberResult.Info.Score)
+		}
+	}
+}
+
+// A user that changes groups while waiting for the grading lock should be graded with their new group.
+func TestLockForGradingGroupChange(test *testing.T) {
+	db.ResetForTesting()
+	defer db.ResetForTesting()
+
+	assignment := db.MustGetTestSubmissionAssignment()
+	assignment.GroupOptions = &model.GroupOptions{MaxSize: 2}
+
+	oldGroup := &model.AssignmentGroup{
+		ID:           "old-group",
+		CourseID:     assignment.GetCourse().GetID(),
+		AssignmentID: assignment.GetID(),
+		Members:      []string{BASE_TEST_USER},
+		CreatedBy:    "course-admin@test.edulinq.org",
+		CreationTime: timestamp.Now(),
+	}
+
+	err := db.UpsertAssignmentGroup(assignment, oldGroup)
+	if err != nil {
+		test.Fatalf("Failed to create old group: '%v'.", err)
+	}
+
+	// Hold the old group's lock (like a grading in progress).
+	oldKey := getGradingKey(assignment, BASE_TEST_USER, oldGroup)
+	lockmanager.Lock(oldKey)
+
+	type lockResult struct {
+		key     string
+		members []string
+		group   *model.AssignmentGroup
+		err     error
+	}
+
+	results := make(chan lockResult, 1)
+	go func() {
+		key, members, group, err := lockForGrading(assignment, BASE_TEST_USER)
+		results <- lockResult{key, members, group, err}
+	}()
+
+	newMembers := []string{"course-other@test.edulinq.org", BASE_TEST_USER}
+	newGroup := &model.AssignmentGroup{
+		ID:           "new-group",
+		CourseID:     assignment.GetCourse().GetID(),
+		AssignmentID: assignment.GetID(),
+		Members:      newMembers,
+		CreatedBy:    "course-admin@test.edulinq.org",
+		CreationTime: timestamp.Now(),
+	}
+
+	err = db.UpsertAssignmentGroups(assignment, map[string]*model.AssignmentGroup{
+		oldGroup.ID: nil,
+		newGroup.ID: newGroup,
+	})
+	if err != nil {
+		test.Fatalf("Failed to move user to new group: '%v'.", err)
+	}
+
+	lockmanager.Unlock(oldKey)
+
+	result := <-results
+	if result.err != nil {
+		test.Fatalf("Failed to lock for grading: '%v'.", result.err)
+	}
+
+	defer lockmanager.Unlock(result.key)
+
+	expectedKey := getGradingKey(assignment, BASE_TEST_USER, newGroup)
+	if expectedKey != result.key {
+		test.Fatalf("Unexpected lock key. Expected: '%s', Actual: '%s'.", expectedKey, result.key)
+	}
+
+	if (result.group == nil) || (result.group.ID != newGroup.ID) {
+		test.Fatalf("Unexpected group. Expected: '%s', Actual: '%s'.", newGroup.ID, util.MustToJSON(result.group))
+	}
+
+	if !slices.Equal(newMembers, result.members) {
+		test.Fatalf("Unexpected members. Expected: '%v', Actual: '%v'.", newMembers, result.members)
+	}
+}
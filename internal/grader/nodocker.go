@@ -5,26 +5,31 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"os/exec"
 	"path/filepath"
 	"strings"
 	"time"
 
 	"github.com/edulinq/autograder/internal/common"
+	"github.com/edulinq/autograder/internal/config"
+	"github.com/edulinq/autograder/internal/docker"
 	"github.com/edulinq/autograder/internal/log"
 	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/sandbox"
 	"github.com/edulinq/autograder/internal/util"
 )
 
 const PYTHON_AUTOGRADER_INVOCATION = "python3 -m autograder.cli.grading.grade-dir --grader <grader> --dir <basedir> --outpath <outpath>"
 const PYTHON_GRADER_FILENAME = "grader.py"
 const PYTHON_DOCKER_IMAGE_BASENAME = "ghcr.io/edulinq/grader.python"
+const SANDBOX_ROOT_DIRNAME = "sandbox-root"
 
 // A small delay to wait for a process to finish after already timing out.
 var noDockerTimeoutWaitDelayMS int = 10 * 1000
 
 // Returns: (result, file contents, stdout, stderr, failure message (soft failure), error (hard failure)).
-func runNoDockerGrader(ctx context.Context, assignment *model.Assignment, submissionPath string, options GradeOptions, fullSubmissionID string) (
+func runNoDockerGrader(ctx context.Context, assignment *model.Assignment, submissionPath string, options GradeOptions, fullSubmissionID string, live *docker.LiveOutput) (
 	*model.GradingInfo, map[string][]byte, string, string, string, error) {
 	imageInfo := assignment.GetImageInfo()
 	if imageInfo == nil {
@@ -62,7 +67,7 @@ func runNoDockerGrader(ctx context.Context, assignment *model.Assignment, submis
 		return nil, nil, "", "", "", fmt.Errorf("Failed to copy submission assignment files: '%w'.", err)
 	}
 
-	stdout, stderr, timeout, canceled, err := runCMD(ctx, cmd)
+	stdout, stderr, timeout, canceled, err := runCMD(ctx, cmd, live)
 	if err != nil {
 		log.Warn("Failed to run non-docker grader for assignment.",
 			assignment, err, log.NewAttr("cmd", cmd.String()))
@@ -105,13 +110,19 @@ func runNoDockerGrader(ctx context.Context, assignment *model.Assignment, submis
 	return &gradingInfo, fileContents, stdout, stderr, "", nil
 }
 
-func runCMD(ctx context.Context, cmd *exec.Cmd) (string, string, bool, bool, error) {
+// Output will also be sent to the live output (if not nil) while the command runs.
+func runCMD(ctx context.Context, cmd *exec.Cmd, live *docker.LiveOutput) (string, string, bool, bool, error) {
 	var outBuffer bytes.Buffer
 	var errBuffer bytes.Buffer
 
 	cmd.Stdout = &outBuffer
 	cmd.Stderr = &errBuffer
 
+	if live != nil {
+		cmd.Stdout = io.MultiWriter(&outBuffer, live.Stdout)
+		cmd.Stderr = io.MultiWriter(&errBuffer, live.Stderr)
+	}
+
 	timeout := false
 	canceled := false
 
@@ -179,8 +190,22 @@ func getAssignmentInvocation(ctx context.Context, assignment *model.Assignment,
 		ctx, cancelFunc = context.WithTimeout(ctx, time.Duration(assignment.MaxRuntimeSecs)*time.Second)
 	}
 
-	cmd := exec.CommandContext(ctx, cleanCommand[0], cleanCommand[1:]...)
-	cmd.Dir = workDir
+	var cmd *exec.Cmd = nil
+	var err error = nil
+
+	if config.DOCKER_NODOCKER_SANDBOX.Get() {
+		cmd, err = getSandboxedCommand(ctx, assignment, cleanCommand, baseDir, inputDir, outputDir, workDir)
+		if err != nil {
+			if cancelFunc != nil {
+				cancelFunc()
+			}
+
+			return ctx, nil, err
+		}
+	} else {
+		cmd = exec.CommandContext(ctx, cleanCommand[0], cleanCommand[1:]...)
+		cmd.Dir = workDir
+	}
 
 	// Ensure the timeout context is canceled.
 	if cancelFunc != nil {
@@ -195,6 +220,55 @@ func getAssignmentInvocation(ctx context.Context, assignment *model.Assignment,
 	return ctx, cmd, nil
 }
 
+// Get a command that runs the grader inside a sandbox that can only see the grading dirs (and system paths).
+// The sandbox gets the same limits a container for this assignment would get.
+func getSandboxedCommand(ctx context.Context, assignment *model.Assignment, command []string,
+	baseDir string, inputDir string, outputDir string, workDir string) (*exec.Cmd, error) {
+	err := sandbox.CheckSupport()
+	if err != nil {
+		return nil, fmt.Errorf("Cannot run the no-docker grader in a sandbox: '%w'.", err)
+	}
+
+	limits := assignment.GetImageInfo().GetContainerLimits()
+
+	tmpfsSizeMB := limits.TmpfsSizeMB
+	if tmpfsSizeMB == 0 {
+		tmpfsSizeMB = config.DOCKER_MAX_TMPFS_MB.Get()
+	}
+
+	systemPaths := make([]string, 0)
+	for _, path := range strings.Split(config.DOCKER_NODOCKER_SANDBOX_PATHS.Get(), ",") {
+		path = strings.TrimSpace(path)
+		if path != "" {
+			systemPaths = append(systemPaths, path)
+		}
+	}
+
+	options := sandbox.Options{
+		Mounts: []sandbox.Mount{
+			sandbox.Mount{Source: inputDir, Target: inputDir, ReadOnly: true},
+			sandbox.Mount{Source: outputDir, Target: outputDir},
+			sandbox.Mount{Source: workDir, Target: workDir},
+		},
+		SystemPaths:  systemPaths,
+		Dir:          workDir,
+		MaxMemoryMB:  limits.MaxMemoryMB,
+		MaxPIDs:      limits.MaxPIDs,
+		MaxCPUSecs:   assignment.MaxRuntimeSecs,
+		TmpfsSizeMB:  tmpfsSizeMB,
+		AllowNetwork: limits.AllowNetwork,
+	}
+
+	// An empty mount point for the sandbox's root (nothing is written here on the host).
+	rootDir := filepath.Join(baseDir, SANDBOX_ROOT_DIRNAME)
+	err = util.MkDir(rootDir)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create sandbox root dir: '%w'.", err)
+	}
+
+	return sandbox.Command(ctx, options, rootDir, command[0], command[1:]...)
+}
+
 // Set the value and return a function to reset it back to its original state.
 func SetNoDockerTimeoutWaitDelayMSForTesting(newValue int) func() {
 	oldValue := noDockerTimeoutWaitDelayMS
@@ -0,0 +1,341 @@
+package grader
+
+// The grading queue allows submissions to be graded in the background (instead of while the submitter waits).
+// Queued submissions are persisted in the database as grading tickets,
+// so they will still be graded if the server is restarted.
+// Finished tickets are kept (so their results can be checked) until they are removed by the server's grading ticket cleanup task.
+// Only one process should run the queue on a database at a time.
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/edulinq/autograder/internal/config"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/log"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/timestamp"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+// Idle workers will check the database for new tickets at least this often.
+const queuePollIntervalMS = 5 * 1000
+
+var queueLock sync.Mutex
+var queueCancelFunc context.CancelFunc = nil
+var queueWaitGroup sync.WaitGroup
+
+// Wakes up idle workers when a new ticket is queued.
+var queueSignal chan struct{} = nil
+
+// Start the grading queue workers.
+// Any tickets that were left running (e.g., the server stopped during grading) will be graded again.
+// Does nothing if the queue is already running.
+func StartQueue() error {
+	queueLock.Lock()
+	defer queueLock.Unlock()
+
+	return startQueueLocked()
+}
+
+// Stop the grading queue and wait for all workers to exit.
+// Tickets that are currently being graded will be returned to the queue.
+func StopQueue() {
+	queueLock.Lock()
+	if queueCancelFunc == nil {
+		queueLock.Unlock()
+		return
+	}
+
+	queueCancelFunc()
+	queueCancelFunc = nil
+	queueLock.Unlock()
+
+	queueWaitGroup.Wait()
+
+	log.Debug("Grading queue stopped.")
+}
+
+// Place a submission into the grading queue.
+// The submission's files are copied into the returned ticket, so the submission path is free to be removed after this returns.
+// The queue will be started if it is not already running.
+func QueueSubmission(assignment *model.Assignment, submissionPath string, user string, message string, allowLate bool) (*model.GradingTicket, error) {
+	fileContents, err := util.GzipDirectoryToBytes(submissionPath)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to copy submission input '%s': '%w'.", submissionPath, err)
+	}
+
+	ticket := &model.GradingTicket{
+		ID:             util.UUID(),
+		CourseID:       assignment.GetCourse().GetID(),
+		AssignmentID:   assignment.GetID(),
+		User:           user,
+		Message:        message,
+		AllowLate:      allowLate,
+		Status:         model.GradingTicketStatusQueued,
+		QueueTime:      timestamp.Now(),
+		InputFilesGZip: fileContents,
+	}
+
+	queueLock.Lock()
+	defer queueLock.Unlock()
+
+	err = db.UpsertGradingTicket(ticket)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to save grading ticket: '%w'.", err)
+	}
+
+	err = startQueueLocked()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to start the grading queue: '%w'.", err)
+	}
+
+	// Wake up a worker (if one is idle).
+	select {
+	case queueSignal <- struct{}{}:
+	default:
+	}
+
+	log.Debug("Queued submission for grading.", assignment, log.NewUserAttr(user), log.NewAttr("ticket-id", ticket.ID))
+
+	return ticket, nil
+}
+
+// Get a grading ticket and its position in the queue.
+// The position is one-based (one means the ticket will be graded next),
+// and is zero for tickets that are not waiting in the queue (running or done).
+// Returns (nil, 0, nil) if the ticket does not exist.
+func GetQueuedTicket(ticketID string) (*model.GradingTicket, int, error) {
+	ticket, err := db.GetGradingTicket(ticketID)
+	if err != nil {
+		return nil, 0, fmt.Errorf("Failed to get grading ticket: '%w'.", err)
+	}
+
+	if (ticket == nil) || (ticket.Status != model.GradingTicketStatusQueued) {
+		return ticket, 0, nil
+	}
+
+	activeTickets, err := db.GetActiveGradingTickets()
+	if err != nil {
+		return nil, 0, fmt.Errorf("Failed to get active grading tickets: '%w'.", err)
+	}
+
+	position := 0
+	for _, activeTicket := range activeTickets {
+		if activeTicket.Status != model.GradingTicketStatusQueued {
+			continue
+		}
+
+		position++
+
+		if activeTicket.ID == ticket.ID {
+			return ticket, position, nil
+		}
+	}
+
+	// The ticket was picked up by a worker between the two fetches.
+	return ticket, 0, nil
+}
+
+// Start the queue, the caller must hold queueLock.
+func startQueueLocked() error {
+	if queueCancelFunc != nil {
+		return nil
+	}
+
+	numWorkers := config.GRADING_QUEUE_WORKERS.Get()
+	if numWorkers < 1 {
+		return fmt.Errorf("The number of grading queue workers must be positive, found %d.", numWorkers)
+	}
+
+	// Any running tickets were interrupted, requeue them.
+	activeTickets, err := db.GetActiveGradingTickets()
+	if err != nil {
+		return fmt.Errorf("Failed to get active grading tickets: '%w'.", err)
+	}
+
+	requeueTickets := make(map[string]*model.GradingTicket)
+	for _, ticket := range activeTickets {
+		if ticket.Status == model.GradingTicketStatusRunning {
+			ticket.Status = model.GradingTicketStatusQueued
+			ticket.StartTime = nil
+			requeueTickets[ticket.ID] = ticket
+		}
+	}
+
+	if len(requeueTickets) > 0 {
+		err = db.UpsertGradingTickets(requeueTickets)
+		if err != nil {
+			return fmt.Errorf("Failed to requeue interrupted grading tickets: '%w'.", err)
+		}
+
+		log.Info("Requeued interrupted grading tickets.", log.NewAttr("count", len(requeueTickets)))
+	}
+
+	ctx, cancelFunc := context.WithCancel(context.Background())
+	queueCancelFunc = cancelFunc
+	queueSignal = make(chan struct{}, numWorkers)
+
+	for i := 0; i < numWorkers; i++ {
+		queueWaitGroup.Add(1)
+		go runQueueWorker(ctx, queueSignal)
+	}
+
+	log.Debug("Grading queue started.", log.NewAttr("workers", numWorkers), log.NewAttr("active-tickets", len(activeTickets)))
+
+	return nil
+}
+
+func runQueueWorker(ctx context.Context, signal chan struct{}) {
+	defer queueWaitGroup.Done()
+
+	for {
+		ticket, err := claimNextTicket()
+		if err != nil {
+			log.Error("Failed to claim the next grading ticket.", err)
+		}
+
+		if ticket != nil {
+			gradeTicket(ctx, ticket)
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-signal:
+		case <-time.After(time.Duration(queuePollIntervalMS) * time.Millisecond):
+		}
+	}
+}
+
+// Get the next ticket that should be graded and mark it as running.
+// A ticket will not be claimed while another ticket from the same user and assignment is running,
+// since the user's submissions will be graded one at a time anyways.
+// Returns nil if there are no tickets ready to grade.
+func claimNextTicket() (*model.GradingTicket, error) {
+	queueLock.Lock()
+	defer queueLock.Unlock()
+
+	// The queue was stopped.
+	if queueCancelFunc == nil {
+		return nil, nil
+	}
+
+	activeTickets, err := db.GetActiveGradingTickets()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get active grading tickets: '%w'.", err)
+	}
+
+	runningKeys := make(map[string]bool)
+	for _, ticket := range activeTickets {
+		if ticket.Status == model.GradingTicketStatusRunning {
+			runningKeys[getTicketGradingKey(ticket)] = true
+		}
+	}
+
+	for _, ticket := range activeTickets {
+		if ticket.Status != model.GradingTicketStatusQueued {
+			continue
+		}
+
+		if runningKeys[getTicketGradingKey(ticket)] {
+			continue
+		}
+
+		ticket.Status = model.GradingTicketStatusRunning
+		ticket.StartTime = timestamp.NowPointer()
+
+		err = db.UpsertGradingTicket(ticket)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to mark grading ticket '%s' as running: '%w'.", ticket.ID, err)
+		}
+
+		return ticket, nil
+	}
+
+	return nil, nil
+}
+
+func gradeTicket(ctx context.Context, ticket *model.GradingTicket) {
+	result, err := runTicket(ctx, ticket)
+
+	if ctx.Err() != nil {
+		// The queue is stopping, put the ticket back so it will be graded next time.
+		ticket.Status = model.GradingTicketStatusQueued
+		ticket.StartTime = nil
+	} else {
+		if err != nil {
+			log.Warn("Queued submission failed internally.", err, log.NewAttr("ticket-id", ticket.ID),
+				log.NewCourseAttr(ticket.CourseID), log.NewAssignmentAttr(ticket.AssignmentID), log.NewUserAttr(ticket.User))
+			result = &model.GradingTicketResult{}
+		}
+
+		ticket.Status = model.GradingTicketStatusDone
+		ticket.EndTime = timestamp.NowPointer()
+		ticket.InputFilesGZip = nil
+		ticket.Result = result
+	}
+
+	err = db.UpsertGradingTicket(ticket)
+	if err != nil {
+		log.Error("Failed to save grading ticket.", err, log.NewAttr("ticket-id", ticket.ID))
+	}
+}
+
+func runTicket(ctx context.Context, ticket *model.GradingTicket) (*model.GradingTicketResult, error) {
+	assignment, err := db.GetAssignment(ticket.CourseID, ticket.AssignmentID)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get assignment: '%w'.", err)
+	}
+
+	prefix := fmt.Sprintf("queue-%s-%s-%s-", ticket.CourseID, ticket.AssignmentID, ticket.User)
+	tempDir, err := util.MkDirTemp(prefix)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create temp grading dir: '%w'.", err)
+	}
+	defer util.RemoveDirent(tempDir)
+
+	err = util.GzipBytesToDirectory(tempDir, ticket.InputFilesGZip)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to write submission input to a temp dir: '%w'.", err)
+	}
+
+	options := GetDefaultGradeOptions()
+	options.AllowLate = ticket.AllowLate
+
+	gradingResult, reject, failureMessage, err := Grade(ctx, assignment, tempDir, ticket.User, ticket.Message, options)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &model.GradingTicketResult{}
+
+	if reject != nil {
+		result.Rejected = true
+		result.RejectReason = reject.String()
+		return result, nil
+	}
+
+	if failureMessage != "" {
+		result.SoftError = failureMessage
+
+		if (gradingResult != nil) && (gradingResult.HasTextOutput()) {
+			result.Stdout = gradingResult.Stdout
+			result.Stderr = gradingResult.Stderr
+		}
+
+		return result, nil
+	}
+
+	result.GradingSuccess = true
+	result.SubmissionID = gradingResult.Info.ShortID
+
+	return result, nil
+}
+
+func getTicketGradingKey(ticket *model.GradingTicket) string {
+	return fmt.Sprintf("%s::%s::%s", ticket.CourseID, ticket.AssignmentID, ticket.User)
+}
@@ -0,0 +1,185 @@
+package grader
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/timestamp"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestQueueSubmissionBase(test *testing.T) {
+	db.ResetForTesting()
+	defer db.ResetForTesting()
+	defer StopQueue()
+
+	assignment := db.MustGetTestSubmissionAssignment()
+	submissionPath := filepath.Join(assignment.GetSourceDir(), "test-submissions", "solution")
+
+	ticket, err := QueueSubmission(assignment, submissionPath, BASE_TEST_USER, "queued", true)
+	if err != nil {
+		test.Fatalf("Failed to queue submission: '%v'.", err)
+	}
+
+	if ticket.ID == "" {
+		test.Fatalf("Queued ticket does not have an ID.")
+	}
+
+	ticket = waitForTicket(test, ticket.ID)
+
+	if (ticket.Result == nil) || !ticket.Result.GradingSuccess {
+		test.Fatalf("Queued submission was not graded successfully: '%s'.", util.MustToJSONIndent(ticket))
+	}
+
+	if ticket.InputFilesGZip != nil {
+		test.Fatalf("Finished ticket still has input files.")
+	}
+
+	if (ticket.StartTime == nil) || (ticket.EndTime == nil) {
+		test.Fatalf("Finished ticket is missing start/end times: '%s'.", util.MustToJSONIndent(ticket))
+	}
+
+	gradingInfo, err := db.GetSubmissionResult(assignment, BASE_TEST_USER, ticket.Result.SubmissionID)
+	if err != nil {
+		test.Fatalf("Failed to get graded submission: '%v'.", err)
+	}
+
+	if gradingInfo == nil {
+		test.Fatalf("Could not find graded submission '%s'.", ticket.Result.SubmissionID)
+	}
+
+	if gradingInfo.Message != "queued" {
+		test.Fatalf("Unexpected submission message. Expected: 'queued', Actual: '%s'.", gradingInfo.Message)
+	}
+
+	if gradingInfo.Score != gradingInfo.MaxPoints {
+		test.Fatalf("Solution did not get full points. Expected: %f, Actual: %f.", gradingInfo.MaxPoints, gradingInfo.Score)
+	}
+}
+
+// Tickets that were running when the queue stopped should be graded when the queue starts again.
+func TestStartQueueRequeueRunning(test *testing.T) {
+	db.ResetForTesting()
+	defer db.ResetForTesting()
+	defer StopQueue()
+
+	StopQueue()
+
+	assignment := db.MustGetTestSubmissionAssignment()
+	submissionPath := filepath.Join(assignment.GetSourceDir(), "test-submissions", "solution")
+
+	fileContents, err := util.GzipDirectoryToBytes(submissionPath)
+	if err != nil {
+		test.Fatalf("Failed to read submission: '%v'.", err)
+	}
+
+	startTime := timestamp.FromMSecs(200)
+
+	err = db.UpsertGradingTicket(&model.GradingTicket{
+		ID:             "interrupted",
+		CourseID:       assignment.GetCourse().GetID(),
+		AssignmentID:   assignment.GetID(),
+		User:           BASE_TEST_USER,
+		AllowLate:      true,
+		Status:         model.GradingTicketStatusRunning,
+		QueueTime:      timestamp.FromMSecs(100),
+		StartTime:      &startTime,
+		InputFilesGZip: fileContents,
+	})
+	if err != nil {
+		test.Fatalf("Failed to save ticket: '%v'.", err)
+	}
+
+	err = StartQueue()
+	if err != nil {
+		test.Fatalf("Failed to start queue: '%v'.", err)
+	}
+
+	ticket := waitForTicket(test, "interrupted")
+
+	if (ticket.Result == nil) || !ticket.Result.GradingSuccess {
+		test.Fatalf("Requeued submission was not graded successfully: '%s'.", util.MustToJSONIndent(ticket))
+	}
+
+	if *ticket.StartTime == startTime {
+		test.Fatalf("Requeued ticket did not get a new start time.")
+	}
+}
+
+func TestGetQueuedTicketPosition(test *testing.T) {
+	db.ResetForTesting()
+	defer db.ResetForTesting()
+
+	// Keep the queue stopped so the tickets are not graded.
+	StopQueue()
+
+	tickets := map[string]*model.GradingTicket{
+		"a": &model.GradingTicket{ID: "a", Status: model.GradingTicketStatusRunning, QueueTime: timestamp.FromMSecs(100)},
+		"b": &model.GradingTicket{ID: "b", Status: model.GradingTicketStatusQueued, QueueTime: timestamp.FromMSecs(300)},
+		"c": &model.GradingTicket{ID: "c", Status: model.GradingTicketStatusQueued, QueueTime: timestamp.FromMSecs(200)},
+		"d": &model.GradingTicket{ID: "d", Status: model.GradingTicketStatusDone, QueueTime: timestamp.FromMSecs(50)},
+	}
+
+	err := db.UpsertGradingTickets(tickets)
+	if err != nil {
+		test.Fatalf("Failed to save tickets: '%v'.", err)
+	}
+
+	testCases := []struct {
+		ticketID string
+		found    bool
+		position int
+	}{
+		{"a", true, 0},
+		{"b", true, 2},
+		{"c", true, 1},
+		{"d", true, 0},
+		{"zzz", false, 0},
+	}
+
+	for i, testCase := range testCases {
+		ticket, position, err := GetQueuedTicket(testCase.ticketID)
+		if err != nil {
+			test.Errorf("Case %d: Failed to get ticket: '%v'.", i, err)
+			continue
+		}
+
+		if testCase.found != (ticket != nil) {
+			test.Errorf("Case %d: Unexpected found value. Expected: '%v', Actual: '%v'.", i, testCase.found, (ticket != nil))
+			continue
+		}
+
+		if testCase.found && !reflect.DeepEqual(tickets[testCase.ticketID], ticket) {
+			test.Errorf("Case %d: Unexpected ticket. Expected: '%s', Actual: '%s'.",
+				i, util.MustToJSONIndent(tickets[testCase.ticketID]), util.MustToJSONIndent(ticket))
+			continue
+		}
+
+		if testCase.position != position {
+			test.Errorf("Case %d: Unexpected position. Expected: %d, Actual: %d.", i, testCase.position, position)
+			continue
+		}
+	}
+}
+
+func waitForTicket(test *testing.T, ticketID string) *model.GradingTicket {
+	for i := 0; i < 600; i++ {
+		ticket, err := db.GetGradingTicket(ticketID)
+		if err != nil {
+			test.Fatalf("Failed to get ticket '%s': '%v'.", ticketID, err)
+		}
+
+		if (ticket != nil) && ticket.IsDone() {
+			return ticket
+		}
+
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	test.Fatalf("Timed out waiting for ticket '%s'.", ticketID)
+	return nil
+}
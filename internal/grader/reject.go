@@ -61,7 +61,37 @@ func (this *RejectLate) String() string {
 		this.AssignmentName, this.DueDate.SafeMessage(), deltaString)
 }
 
-func checkForRejection(assignment *model.Assignment, submissionPath string, email string, message string, allowLate bool) (RejectReason, error) {
+type RejectNotOpen struct {
+	AssignmentName string
+	OpenDate       timestamp.Timestamp
+}
+
+func (this *RejectNotOpen) String() string {
+	deltaMS := this.OpenDate.ToMSecs() - timestamp.Now().ToMSecs()
+	deltaString := time.Duration(deltaMS * int64(time.Millisecond)).String()
+
+	return fmt.Sprintf("Assignment (%s) is not open for submissions yet."+
+		" It opens on %s (in %s).",
+		this.AssignmentName, this.OpenDate.SafeMessage(), deltaString)
+}
+
+type RejectClosed struct {
+	AssignmentName string
+	CloseDate      timestamp.Timestamp
+}
+
+func (this *RejectClosed) String() string {
+	deltaMS := timestamp.Now().ToMSecs() - this.CloseDate.ToMSecs()
+	deltaString := time.Duration(deltaMS * int64(time.Millisecond)).String()
+
+	return fmt.Sprintf("Assignment (%s) is closed and no longer accepts submissions."+
+		" It closed on %s (which was %s ago).",
+		this.AssignmentName, this.CloseDate.SafeMessage(), deltaString)
+}
+
+// Check if a submission should be rejected.
+// |members| are all the users that the submission will be credited to (the submitter's group, or just the submitter).
+func checkForRejection(assignment *model.Assignment, submissionPath string, email string, members []string, message string, allowLate bool) (RejectReason, error) {
 	user, err := db.GetServerUser(email)
 	if err != nil {
 		return nil, err
@@ -76,29 +106,95 @@ func checkForRejection(assignment *model.Assignment, submissionPath string, emai
 		return nil, nil
 	}
 
-	reason := checkLateSubmission(assignment, allowLate)
+	extension, err := db.GetAssignmentExtension(assignment, email)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get user's extension: '%w'.", err)
+	}
+
+	reason, err := checkSubmissionWindow(assignment, email, extension)
+	if err != nil {
+		return nil, err
+	}
+
 	if reason != nil {
 		return reason, nil
 	}
 
-	return checkSubmissionLimit(assignment, email)
+	reason = checkLateSubmission(assignment, extension, allowLate)
+	if reason != nil {
+		return reason, nil
+	}
+
+	return checkSubmissionLimit(assignment, email, members, extension)
 }
 
-func checkLateSubmission(assignment *model.Assignment, allowLate bool) RejectReason {
-	if assignment.DueDate == nil {
+// Check that the submission falls between the assignment's open and close dates.
+// A due date from the submitting user's extension (which may be nil) that is after the close date will be used as the close date.
+func checkSubmissionWindow(assignment *model.Assignment, email string, extension *model.AssignmentExtension) (RejectReason, error) {
+	openDate := assignment.GetOpenDate()
+	closeDate := assignment.GetCloseDate()
+
+	if (openDate == nil) && (closeDate == nil) {
+		return nil, nil
+	}
+
+	user, err := db.GetCourseUser(assignment.GetCourse(), email)
+	if err != nil {
+		return nil, err
+	}
+
+	if user == nil {
+		return nil, fmt.Errorf("Unable to find user: '%s'.", email)
+	}
+
+	// Users that are >= grader may submit at any time.
+	if user.Role >= model.CourseRoleGrader {
+		return nil, nil
+	}
+
+	now := timestamp.Now()
+
+	if (openDate != nil) && (now < *openDate) {
+		return &RejectNotOpen{assignment.Name, *openDate}, nil
+	}
+
+	if closeDate == nil {
+		return nil, nil
+	}
+
+	if extension != nil {
+		dueDate := extension.GetDueDate(assignment.DueDate)
+		if (dueDate != nil) && (*dueDate > *closeDate) {
+			closeDate = dueDate
+		}
+	}
+
+	if now > *closeDate {
+		return &RejectClosed{assignment.Name, *closeDate}, nil
+	}
+
+	return nil, nil
+}
+
+// The submitting user's extension (which may be nil) will be used in place of the assignment's due date.
+func checkLateSubmission(assignment *model.Assignment, extension *model.AssignmentExtension, allowLate bool) RejectReason {
+	dueDate := extension.GetDueDate(assignment.DueDate)
+	if dueDate == nil {
 		return nil
 	}
 
 	now := timestamp.Now()
 
-	if (now > *assignment.DueDate) && !allowLate {
-		return &RejectLate{assignment.Name, *assignment.DueDate}
+	if (now > *dueDate) && !allowLate {
+		return &RejectLate{assignment.Name, *dueDate}
 	}
 
 	return nil
 }
 
-func checkSubmissionLimit(assignment *model.Assignment, email string) (RejectReason, error) {
+// Submission limits apply to all the members together (so a group shares a single limit).
+// Any extra attempts from the submitting user's extension (which may be nil) are added to the max attempts.
+func checkSubmissionLimit(assignment *model.Assignment, email string, members []string, extension *model.AssignmentExtension) (RejectReason, error) {
 	// Do not check for submission limits in testing mode.
 	if config.UNIT_TESTING_MODE.Get() {
 		return nil, nil
@@ -127,14 +223,15 @@ func checkSubmissionLimit(assignment *model.Assignment, email string) (RejectRea
 
 	now := timestamp.Now()
 
-	history, err := db.GetSubmissionHistory(assignment, email)
+	history, err := getSharedSubmissionHistory(assignment, members)
 	if err != nil {
 		return nil, err
 	}
 
 	if *limit.Max >= 0 {
-		if countStudentSubmissions(history) >= *limit.Max {
-			return &RejectMaxAttempts{*limit.Max}, nil
+		maxAttempts := *limit.Max + extension.GetExtraAttempts()
+		if countStudentSubmissions(history) >= maxAttempts {
+			return &RejectMaxAttempts{maxAttempts}, nil
 		}
 	}
 
@@ -152,6 +249,40 @@ func checkSubmissionLimit(assignment *model.Assignment, email string) (RejectRea
 	return nil, nil
 }
 
+// Get the combined submission history for several users.
+// Group submissions are saved for every member of the group, but will only appear once.
+func getSharedSubmissionHistory(assignment *model.Assignment, users []string) ([]*model.SubmissionHistoryItem, error) {
+	if len(users) == 1 {
+		return db.GetSubmissionHistory(assignment, users[0])
+	}
+
+	history := make([]*model.SubmissionHistoryItem, 0)
+	seen := make(map[string]bool)
+
+	for _, user := range users {
+		userHistory, err := db.GetSubmissionHistory(assignment, user)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, item := range userHistory {
+			key := fmt.Sprintf("user::%s::%s", item.User, item.ShortID)
+			if item.GroupID != "" {
+				key = fmt.Sprintf("group::%s::%s", item.GroupID, item.ShortID)
+			}
+
+			if seen[key] {
+				continue
+			}
+
+			seen[key] = true
+			history = append(history, item)
+		}
+	}
+
+	return history, nil
+}
+
 // Count student-initiated submissions, excluding proxy submissions.
 func countStudentSubmissions(history []*model.SubmissionHistoryItem) int {
 	count := 0
@@ -70,6 +70,39 @@ func TestRejectSubmissionMaxAttemptsInfinite(test *testing.T) {
 	submitForRejection(test, assignment, "course-other@test.edulinq.org", false, nil)
 }
 
+// Group members share a single submission limit.
+func TestRejectSubmissionMaxAttemptsGroup(test *testing.T) {
+	db.ResetForTesting()
+	defer db.ResetForTesting()
+
+	assignment := db.MustGetTestSubmissionAssignment()
+	assignment.DueDate = nil
+	assignment.GroupOptions = &model.GroupOptions{MaxSize: 2}
+
+	maxValue := 1
+	assignment.SubmissionLimit = &model.SubmissionLimitInfo{Max: &maxValue}
+
+	group := &model.AssignmentGroup{
+		ID:           "test-group",
+		CourseID:     assignment.GetCourse().GetID(),
+		AssignmentID: assignment.GetID(),
+		Members:      []string{"course-grader@test.edulinq.org", "course-other@test.edulinq.org"},
+		CreatedBy:    "course-admin@test.edulinq.org",
+		CreationTime: timestamp.Now(),
+	}
+
+	err := db.UpsertAssignmentGroup(assignment, group)
+	if err != nil {
+		test.Fatalf("Failed to create group: '%v'.", err)
+	}
+
+	// The first member's submission goes through.
+	submitForRejection(test, assignment, "course-grader@test.edulinq.org", false, nil)
+
+	// The group's attempts have been used up by the other member.
+	submitForRejection(test, assignment, "course-other@test.edulinq.org", false, &RejectMaxAttempts{1})
+}
+
 func TestRejectSubmissionMaxWindowAttempts(test *testing.T) {
 	testMaxWindowAttempts(test, "course-other@test.edulinq.org", true)
 }
@@ -136,6 +169,182 @@ func TestRejectLateSubmissionWithAllow(test *testing.T) {
 	submitForRejection(test, assignment, "course-other@test.edulinq.org", true, nil)
 }
 
+func TestRejectLateSubmissionWithExtension(test *testing.T) {
+	db.ResetForTesting()
+	defer db.ResetForTesting()
+
+	assignment := db.MustGetTestSubmissionAssignment()
+
+	// Set a dummy submission limit.
+	assignment.SubmissionLimit = &model.SubmissionLimitInfo{}
+
+	// Set the due date to be the Unix epoch.
+	dueDate := timestamp.Zero()
+	assignment.DueDate = &dueDate
+
+	user := "course-other@test.edulinq.org"
+
+	// Extended into the future.
+	extendedDueDate := timestamp.Now() + timestamp.FromMSecs(60*60*1000)
+	extension := &model.AssignmentExtension{
+		CourseID:     TEST_COURSE_ID,
+		AssignmentID: TEST_ASSIGNMENT_ID,
+		Email:        user,
+		DueDate:      &extendedDueDate,
+	}
+
+	err := db.UpsertAssignmentExtension(assignment, extension)
+	if err != nil {
+		test.Fatalf("Failed to save extension: '%v'.", err)
+	}
+
+	submitForRejection(test, assignment, user, false, nil)
+
+	// Extra time that is still in the past.
+	extension.DueDate = nil
+	extension.ExtraTime = &util.DurationSpec{Days: 1}
+
+	err = db.UpsertAssignmentExtension(assignment, extension)
+	if err != nil {
+		test.Fatalf("Failed to save extension: '%v'.", err)
+	}
+
+	submitForRejection(test, assignment, user, false, &RejectLate{assignment.Name, *extension.GetDueDate(assignment.DueDate)})
+}
+
+func TestRejectSubmissionMaxAttemptsWithExtension(test *testing.T) {
+	db.ResetForTesting()
+	defer db.ResetForTesting()
+
+	assignment := db.MustGetTestSubmissionAssignment()
+	assignment.DueDate = nil
+
+	maxValue := 0
+	assignment.SubmissionLimit = &model.SubmissionLimitInfo{Max: &maxValue}
+
+	user := "course-other@test.edulinq.org"
+
+	extension := &model.AssignmentExtension{
+		CourseID:      TEST_COURSE_ID,
+		AssignmentID:  TEST_ASSIGNMENT_ID,
+		Email:         user,
+		ExtraAttempts: 1,
+	}
+
+	err := db.UpsertAssignmentExtension(assignment, extension)
+	if err != nil {
+		test.Fatalf("Failed to save extension: '%v'.", err)
+	}
+
+	// The extra attempt can be used.
+	submitForRejection(test, assignment, user, false, nil)
+
+	// Then the user is out of attempts.
+	submitForRejection(test, assignment, user, false, &RejectMaxAttempts{1})
+}
+
+func TestRejectSubmissionNotOpen(test *testing.T) {
+	db.ResetForTesting()
+	defer db.ResetForTesting()
+
+	assignment := db.MustGetTestSubmissionAssignment()
+	assignment.DueDate = nil
+
+	// Set a dummy submission limit.
+	assignment.SubmissionLimit = &model.SubmissionLimitInfo{}
+
+	openDate := timestamp.Now() + timestamp.FromMSecs(60*60*1000)
+	assignment.OpenDate = &openDate
+
+	submitForRejection(test, assignment, "course-other@test.edulinq.org", false, &RejectNotOpen{assignment.Name, openDate})
+
+	// Graders can submit before the assignment opens.
+	submitForRejection(test, assignment, "course-grader@test.edulinq.org", false, nil)
+
+	// Already open.
+	openDate = timestamp.Zero()
+	submitForRejection(test, assignment, "course-other@test.edulinq.org", false, nil)
+}
+
+func TestRejectSubmissionClosed(test *testing.T) {
+	db.ResetForTesting()
+	defer db.ResetForTesting()
+
+	assignment := db.MustGetTestSubmissionAssignment()
+	assignment.DueDate = nil
+
+	// Set a dummy submission limit.
+	assignment.SubmissionLimit = &model.SubmissionLimitInfo{}
+
+	closeDate := timestamp.Zero()
+	assignment.CloseDate = &closeDate
+
+	// Closed assignments cannot be submitted late.
+	submitForRejection(test, assignment, "course-other@test.edulinq.org", true, &RejectClosed{assignment.Name, closeDate})
+
+	// Graders can submit after the assignment closes.
+	submitForRejection(test, assignment, "course-grader@test.edulinq.org", false, nil)
+}
+
+func TestRejectSubmissionClosedInherited(test *testing.T) {
+	db.ResetForTesting()
+	defer db.ResetForTesting()
+
+	assignment := db.MustGetTestSubmissionAssignment()
+	assignment.DueDate = nil
+
+	// Set a dummy submission limit.
+	assignment.SubmissionLimit = &model.SubmissionLimitInfo{}
+
+	closeDate := timestamp.Zero()
+	assignment.GetCourse().CloseDate = &closeDate
+
+	submitForRejection(test, assignment, "course-other@test.edulinq.org", true, &RejectClosed{assignment.Name, closeDate})
+}
+
+func TestRejectSubmissionClosedWithExtension(test *testing.T) {
+	db.ResetForTesting()
+	defer db.ResetForTesting()
+
+	assignment := db.MustGetTestSubmissionAssignment()
+
+	// Set a dummy submission limit.
+	assignment.SubmissionLimit = &model.SubmissionLimitInfo{}
+
+	dueDate := timestamp.Zero()
+	assignment.DueDate = &dueDate
+	assignment.CloseDate = &dueDate
+
+	user := "course-other@test.edulinq.org"
+
+	// Extended past the close date.
+	extendedDueDate := timestamp.Now() + timestamp.FromMSecs(60*60*1000)
+	extension := &model.AssignmentExtension{
+		CourseID:     TEST_COURSE_ID,
+		AssignmentID: TEST_ASSIGNMENT_ID,
+		Email:        user,
+		DueDate:      &extendedDueDate,
+	}
+
+	err := db.UpsertAssignmentExtension(assignment, extension)
+	if err != nil {
+		test.Fatalf("Failed to save extension: '%v'.", err)
+	}
+
+	submitForRejection(test, assignment, user, false, nil)
+
+	// Extra attempts do not change the close date.
+	extension.DueDate = nil
+	extension.ExtraAttempts = 1
+
+	err = db.UpsertAssignmentExtension(assignment, extension)
+	if err != nil {
+		test.Fatalf("Failed to save extension: '%v'.", err)
+	}
+
+	submitForRejection(test, assignment, user, false, &RejectClosed{assignment.Name, dueDate})
+}
+
 func testMaxWindowAttempts(test *testing.T, user string, expectReject bool) {
 	db.ResetForTesting()
 	defer db.ResetForTesting()
@@ -0,0 +1,272 @@
+package grader
+
+// Grading streams let users follow a submission while it is being graded.
+// A stream is started once a submission has an ID and collects the grader's output (up to the max output size)
+// and the graded questions (once the grader is done).
+// Finished streams are kept for a short time, so listeners that connect late can still get the full stream.
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/edulinq/autograder/internal/common"
+	"github.com/edulinq/autograder/internal/config"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/docker"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/timestamp"
+)
+
+type GradingStreamEventType string
+
+const (
+	GRADING_STREAM_EVENT_START     GradingStreamEventType = "start"
+	GRADING_STREAM_EVENT_STDOUT    GradingStreamEventType = "stdout"
+	GRADING_STREAM_EVENT_STDERR    GradingStreamEventType = "stderr"
+	GRADING_STREAM_EVENT_TRUNCATED GradingStreamEventType = "truncated"
+	GRADING_STREAM_EVENT_QUESTION  GradingStreamEventType = "question"
+	GRADING_STREAM_EVENT_DONE      GradingStreamEventType = "done"
+)
+
+type GradingStreamEvent struct {
+	Type      GradingStreamEventType `json:"type"`
+	Timestamp timestamp.Timestamp    `json:"timestamp"`
+
+	// The output for stdout/stderr events, or a message for truncated/done events.
+	Text string `json:"text,omitempty"`
+
+	// The graded question for question events.
+	Question *model.GradedQuestion `json:"question,omitempty"`
+
+	// For done events, if the submission was successfully graded.
+	Success bool `json:"success,omitempty"`
+}
+
+type GradingStream struct {
+	CourseID     string
+	AssignmentID string
+	SubmissionID string
+
+	// All the users the submission is credited to.
+	Users []string
+
+	lock            sync.Mutex
+	events          []*GradingStreamEvent
+	remainingOutput int
+	truncated       bool
+	success         bool
+	message         string
+	done            bool
+
+	// Closed (and replaced) every time the stream changes.
+	changed chan struct{}
+}
+
+type gradingStreamWriter struct {
+	stream    *GradingStream
+	eventType GradingStreamEventType
+}
+
+// How long finished streams are kept.
+var gradingStreamRetentionMS int64 = 60 * 1000
+
+var gradingStreamsLock sync.Mutex
+
+// {fullSubmissionID: stream}.
+// A stream is available under the full submission ID of every user it is credited to.
+var gradingStreams map[string]*GradingStream = make(map[string]*GradingStream)
+
+// {course::assignment::user: stream}.
+// The stream for the submission that is currently being graded for a user.
+var activeGradingStreams map[string]*GradingStream = make(map[string]*GradingStream)
+
+func init() {
+	db.AddResetForTestingHook(clearGradingStreams)
+}
+
+// Get the grading stream for a user's submission.
+// An empty submission ID will get the submission that is currently being graded for the user.
+// Returns nil if there is no such stream (e.g., the submission was graded a while ago).
+func GetGradingStream(assignment *model.Assignment, user string, submissionID string) *GradingStream {
+	gradingStreamsLock.Lock()
+	defer gradingStreamsLock.Unlock()
+
+	if submissionID == "" {
+		return activeGradingStreams[getActiveGradingStreamKey(assignment.GetCourse().GetID(), assignment.GetID(), user)]
+	}
+
+	submissionID = common.GetShortSubmissionID(submissionID)
+	return gradingStreams[common.CreateFullSubmissionID(assignment.GetCourse().GetID(), assignment.GetID(), user, submissionID)]
+}
+
+// Start a stream for a submission that is about to be graded.
+func startGradingStream(assignment *model.Assignment, users []string, submissionID string) *GradingStream {
+	stream := &GradingStream{
+		CourseID:        assignment.GetCourse().GetID(),
+		AssignmentID:    assignment.GetID(),
+		SubmissionID:    submissionID,
+		Users:           users,
+		events:          make([]*GradingStreamEvent, 0),
+		remainingOutput: config.DOCKER_MAX_OUTPUT_SIZE_KB.Get() * 1024,
+		changed:         make(chan struct{}),
+	}
+
+	stream.addEvent(&GradingStreamEvent{Type: GRADING_STREAM_EVENT_START})
+
+	gradingStreamsLock.Lock()
+	defer gradingStreamsLock.Unlock()
+
+	for _, user := range users {
+		gradingStreams[common.CreateFullSubmissionID(stream.CourseID, stream.AssignmentID, user, submissionID)] = stream
+		activeGradingStreams[getActiveGradingStreamKey(stream.CourseID, stream.AssignmentID, user)] = stream
+	}
+
+	return stream
+}
+
+// Get the events starting at the given index,
+// if the stream is done (no more events will be added),
+// and a channel that will be closed when the stream changes.
+func (this *GradingStream) GetEvents(start int) ([]*GradingStreamEvent, bool, <-chan struct{}) {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	start = max(0, min(start, len(this.events)))
+
+	return this.events[start:], this.done, this.changed
+}
+
+func (this *GradingStream) HasUser(email string) bool {
+	for _, user := range this.Users {
+		if user == email {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Get writers that will add stdout/stderr events to this stream.
+func (this *GradingStream) getLiveOutput() *docker.LiveOutput {
+	return &docker.LiveOutput{
+		Stdout: &gradingStreamWriter{this, GRADING_STREAM_EVENT_STDOUT},
+		Stderr: &gradingStreamWriter{this, GRADING_STREAM_EVENT_STDERR},
+	}
+}
+
+func (this *GradingStream) addQuestions(questions []*model.GradedQuestion) {
+	for _, question := range questions {
+		this.addEvent(&GradingStreamEvent{Type: GRADING_STREAM_EVENT_QUESTION, Question: question})
+	}
+}
+
+// Set the result that will be reported when the stream is finished.
+func (this *GradingStream) setResult(success bool, message string) {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	this.success = success
+	this.message = message
+}
+
+// Add the final (done) event and schedule this stream to be removed.
+// Calling this on a finished stream does nothing.
+func (this *GradingStream) finish() {
+	this.lock.Lock()
+	if this.done {
+		this.lock.Unlock()
+		return
+	}
+
+	this.addEventLocked(&GradingStreamEvent{Type: GRADING_STREAM_EVENT_DONE, Success: this.success, Text: this.message})
+	this.done = true
+	this.lock.Unlock()
+
+	gradingStreamsLock.Lock()
+	for _, user := range this.Users {
+		key := getActiveGradingStreamKey(this.CourseID, this.AssignmentID, user)
+		if activeGradingStreams[key] == this {
+			delete(activeGradingStreams, key)
+		}
+	}
+	gradingStreamsLock.Unlock()
+
+	time.AfterFunc(time.Duration(gradingStreamRetentionMS)*time.Millisecond, this.remove)
+}
+
+func (this *GradingStream) remove() {
+	gradingStreamsLock.Lock()
+	defer gradingStreamsLock.Unlock()
+
+	for _, user := range this.Users {
+		key := common.CreateFullSubmissionID(this.CourseID, this.AssignmentID, user, this.SubmissionID)
+		if gradingStreams[key] == this {
+			delete(gradingStreams, key)
+		}
+	}
+}
+
+// Remove all grading streams (active and finished).
+func clearGradingStreams() {
+	gradingStreamsLock.Lock()
+	defer gradingStreamsLock.Unlock()
+
+	gradingStreams = make(map[string]*GradingStream)
+	activeGradingStreams = make(map[string]*GradingStream)
+}
+
+func (this *GradingStream) addEvent(event *GradingStreamEvent) {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	this.addEventLocked(event)
+}
+
+// Add an event (if the stream is not done) and notify any listeners, the caller must hold the lock.
+func (this *GradingStream) addEventLocked(event *GradingStreamEvent) {
+	if this.done {
+		return
+	}
+
+	event.Timestamp = timestamp.Now()
+	this.events = append(this.events, event)
+
+	close(this.changed)
+	this.changed = make(chan struct{})
+}
+
+// Output shares the same size limit as the output kept for a grading result.
+// Output past the limit is dropped (with a single truncated event), but still reported as written.
+func (this *gradingStreamWriter) Write(data []byte) (int, error) {
+	size := len(data)
+
+	this.stream.lock.Lock()
+	defer this.stream.lock.Unlock()
+
+	if this.stream.truncated {
+		return size, nil
+	}
+
+	if len(data) > this.stream.remainingOutput {
+		data = data[:this.stream.remainingOutput]
+		this.stream.truncated = true
+	}
+
+	this.stream.remainingOutput -= len(data)
+
+	if len(data) > 0 {
+		this.stream.addEventLocked(&GradingStreamEvent{Type: this.eventType, Text: string(data)})
+	}
+
+	if this.stream.truncated {
+		message := fmt.Sprintf("Combined output (stdout + stderr) exceeds maximum size (%d KB), output has been truncated.", config.DOCKER_MAX_OUTPUT_SIZE_KB.Get())
+		this.stream.addEventLocked(&GradingStreamEvent{Type: GRADING_STREAM_EVENT_TRUNCATED, Text: message})
+	}
+
+	return size, nil
+}
+
+func getActiveGradingStreamKey(courseID string, assignmentID string, user string) string {
+	return fmt.Sprintf("%s::%s::%s", courseID, assignmentID, user)
+}
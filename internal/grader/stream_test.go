@@ -0,0 +1,159 @@
+package grader
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/edulinq/autograder/internal/config"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestGradingStreamBase(test *testing.T) {
+	testGradingStream(test, 4*1024, false)
+}
+
+func TestGradingStreamTruncation(test *testing.T) {
+	testGradingStream(test, 1, true)
+}
+
+func testGradingStream(test *testing.T, sizeKB int, expectedTruncated bool) {
+	db.ResetForTesting()
+	defer db.ResetForTesting()
+
+	defer config.DOCKER_DISABLE.Set(config.DOCKER_DISABLE.Get())
+	config.DOCKER_DISABLE.Set(true)
+
+	defer config.DOCKER_MAX_OUTPUT_SIZE_KB.Set(config.DOCKER_MAX_OUTPUT_SIZE_KB.Get())
+	config.DOCKER_MAX_OUTPUT_SIZE_KB.Set(sizeKB)
+
+	submissionDir := filepath.Join(util.ShouldGetThisDir(), "testdata", "bash-outputsize")
+	assignment := db.MustGetAssignment("course-languages", "bash")
+
+	options := GetDefaultGradeOptions()
+	options.CheckRejection = false
+
+	result, reject, softError, err := Grade(context.Background(), assignment, submissionDir, BASE_TEST_USER, TEST_MESSAGE, options)
+	if err != nil {
+		test.Fatalf("Failed to grade assignment: '%v'.", err)
+	}
+
+	if (reject != nil) || (softError != "") {
+		test.Fatalf("Submission was not graded: '%v', '%s'.", reject, softError)
+	}
+
+	// The grading is done, so there is no active stream, but the submission's stream is still available.
+	if GetGradingStream(assignment, BASE_TEST_USER, "") != nil {
+		test.Fatalf("Found an active stream after grading.")
+	}
+
+	stream := GetGradingStream(assignment, BASE_TEST_USER, result.Info.ID)
+	if stream == nil {
+		test.Fatalf("Could not find stream for submission '%s'.", result.Info.ID)
+	}
+
+	if stream != GetGradingStream(assignment, BASE_TEST_USER, result.Info.ShortID) {
+		test.Fatalf("Got a different stream from the short submission ID.")
+	}
+
+	if GetGradingStream(assignment, "course-other@test.edulinq.org", result.Info.ShortID) != nil {
+		test.Fatalf("Found a stream for a user that did not make the submission.")
+	}
+
+	events, done, _ := stream.GetEvents(0)
+	if !done {
+		test.Fatalf("Stream is not done after grading.")
+	}
+
+	counts := make(map[GradingStreamEventType]int)
+	var output strings.Builder
+
+	for _, event := range events {
+		counts[event.Type]++
+
+		if (event.Type == GRADING_STREAM_EVENT_STDOUT) || (event.Type == GRADING_STREAM_EVENT_STDERR) {
+			output.WriteString(event.Text)
+		}
+	}
+
+	if (events[0].Type != GRADING_STREAM_EVENT_START) || (counts[GRADING_STREAM_EVENT_START] != 1) {
+		test.Fatalf("Stream does not have a single start event first: '%s'.", util.MustToJSONIndent(events))
+	}
+
+	lastEvent := events[len(events)-1]
+	if (lastEvent.Type != GRADING_STREAM_EVENT_DONE) || !lastEvent.Success || (counts[GRADING_STREAM_EVENT_DONE] != 1) {
+		test.Fatalf("Stream does not have a single successful done event last: '%s'.", util.MustToJSONIndent(events))
+	}
+
+	if counts[GRADING_STREAM_EVENT_QUESTION] != len(result.Info.Questions) {
+		test.Fatalf("Unexpected number of question events. Expected: %d, Actual: %d.", len(result.Info.Questions), counts[GRADING_STREAM_EVENT_QUESTION])
+	}
+
+	if output.Len() > (sizeKB * 1024) {
+		test.Fatalf("Streamed output is larger than the max size. Expected: <= %d, Actual: %d.", sizeKB*1024, output.Len())
+	}
+
+	if !strings.Contains(output.String(), result.Stderr[:min(len(result.Stderr), 100)]) {
+		test.Fatalf("Streamed output does not contain the grading result's output.")
+	}
+
+	if expectedTruncated != (counts[GRADING_STREAM_EVENT_TRUNCATED] == 1) {
+		test.Fatalf("Unexpected truncation. Expected: %v, Actual: '%s'.", expectedTruncated, util.MustToJSONIndent(counts))
+	}
+
+	// No more events are added to a finished stream.
+	stream.finish()
+	stream.addEvent(&GradingStreamEvent{Type: GRADING_STREAM_EVENT_STDOUT})
+
+	newEvents, _, _ := stream.GetEvents(len(events))
+	if len(newEvents) != 0 {
+		test.Fatalf("Events were added to a finished stream: '%s'.", util.MustToJSONIndent(newEvents))
+	}
+}
+
+func TestGradingStreamRemove(test *testing.T) {
+	assignment := db.MustGetTestAssignment()
+
+	stream := startGradingStream(assignment, []string{BASE_TEST_USER, "course-other@test.edulinq.org"}, "1")
+
+	for _, user := range stream.Users {
+		if GetGradingStream(assignment, user, "") != stream {
+			test.Fatalf("Did not find the active stream for '%s'.", user)
+		}
+
+		if GetGradingStream(assignment, user, "1") != stream {
+			test.Fatalf("Did not find the stream for '%s'.", user)
+		}
+	}
+
+	_, done, changed := stream.GetEvents(0)
+	if done {
+		test.Fatalf("New stream is done.")
+	}
+
+	stream.finish()
+
+	// The listener should be notified of the change.
+	<-changed
+
+	for _, user := range stream.Users {
+		if GetGradingStream(assignment, user, "") != nil {
+			test.Fatalf("Found an active stream for '%s' after it finished.", user)
+		}
+
+		if GetGradingStream(assignment, user, "1") != stream {
+			test.Fatalf("Did not find the finished stream for '%s'.", user)
+		}
+	}
+
+	// Finished streams are removed after the retention time.
+	stream.remove()
+
+	for _, user := range stream.Users {
+		if GetGradingStream(assignment, user, "1") != nil {
+			test.Fatalf("Found a stream for '%s' after it was removed.", user)
+		}
+	}
+}
@@ -12,6 +12,8 @@ import (
 	"github.com/edulinq/autograder/internal/util"
 )
 
+const TEST_SUBMISSION_FILENAME = "test-submission.json"
+
 type TestSubmissionInfo struct {
 	ID             string
 	Dir            string
@@ -21,7 +23,7 @@ type TestSubmissionInfo struct {
 }
 
 func GetTestSubmissions(baseDir string, useDocker bool) ([]*TestSubmissionInfo, error) {
-	testSubmissionPaths, err := util.FindFiles("test-submission.json", baseDir)
+	testSubmissionPaths, err := util.FindFiles(TEST_SUBMISSION_FILENAME, baseDir)
 	if err != nil {
 		return nil, fmt.Errorf("Could not find test results in '%s': '%w'.", baseDir, err)
 	}
@@ -31,12 +33,6 @@ func GetTestSubmissions(baseDir string, useDocker bool) ([]*TestSubmissionInfo,
 	for _, testSubmissionPath := range testSubmissionPaths {
 		testSubmissionPath = util.ShouldAbs(testSubmissionPath)
 
-		var testSubmission model.TestSubmission
-		err := util.JSONFromFile(testSubmissionPath, &testSubmission)
-		if err != nil {
-			return nil, fmt.Errorf("Failed to load test submission: '%s': '%w'.", testSubmissionPath, err)
-		}
-
 		assignment, err := fetchTestSubmissionAssignment(testSubmissionPath)
 		if err != nil {
 			return nil, fmt.Errorf("Could not find assignment for test submission '%s': '%w'.", testSubmissionPath, err)
@@ -47,23 +43,12 @@ func GetTestSubmissions(baseDir string, useDocker bool) ([]*TestSubmissionInfo,
 			continue
 		}
 
-		dir := util.ShouldAbs(filepath.Dir(testSubmissionPath))
-
-		paths, err := util.GetAllDirents(dir, false, false)
+		testSubmission, err := readTestSubmission(testSubmissionPath, strings.TrimPrefix(testSubmissionPath, baseDir), assignment)
 		if err != nil {
-			return nil, fmt.Errorf("Failed to get test submission files: '%w'.", err)
+			return nil, err
 		}
 
-		removeIndex := slices.Index(paths, testSubmissionPath)
-		paths = slices.Delete(paths, removeIndex, removeIndex+1)
-
-		testSubmissions = append(testSubmissions, &TestSubmissionInfo{
-			ID:             strings.TrimPrefix(testSubmissionPath, baseDir),
-			Dir:            dir,
-			Files:          paths,
-			TestSubmission: &testSubmission,
-			Assignment:     assignment,
-		})
+		testSubmissions = append(testSubmissions, testSubmission)
 	}
 
 	if len(testSubmissions) == 0 {
@@ -73,6 +58,63 @@ func GetTestSubmissions(baseDir string, useDocker bool) ([]*TestSubmissionInfo,
 	return testSubmissions, nil
 }
 
+// Get all the test submissions inside an assignment's source dir.
+// Test submission IDs are the relative path (from the assignment's source dir) to the test submission's dir.
+func GetAssignmentTestSubmissions(assignment *model.Assignment) ([]*TestSubmissionInfo, error) {
+	baseDir := util.ShouldAbs(assignment.GetSourceDir())
+
+	testSubmissionPaths, err := util.FindFiles(TEST_SUBMISSION_FILENAME, baseDir)
+	if err != nil {
+		return nil, fmt.Errorf("Could not find test submissions for assignment '%s': '%w'.", assignment.FullID(), err)
+	}
+
+	testSubmissions := make([]*TestSubmissionInfo, 0, len(testSubmissionPaths))
+
+	for _, testSubmissionPath := range testSubmissionPaths {
+		testSubmissionPath = util.ShouldAbs(testSubmissionPath)
+
+		id, err := filepath.Rel(baseDir, filepath.Dir(testSubmissionPath))
+		if err != nil {
+			return nil, fmt.Errorf("Failed to get relative path for test submission '%s': '%w'.", testSubmissionPath, err)
+		}
+
+		testSubmission, err := readTestSubmission(testSubmissionPath, id, assignment)
+		if err != nil {
+			return nil, err
+		}
+
+		testSubmissions = append(testSubmissions, testSubmission)
+	}
+
+	return testSubmissions, nil
+}
+
+func readTestSubmission(testSubmissionPath string, id string, assignment *model.Assignment) (*TestSubmissionInfo, error) {
+	var testSubmission model.TestSubmission
+	err := util.JSONFromFile(testSubmissionPath, &testSubmission)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to load test submission: '%s': '%w'.", testSubmissionPath, err)
+	}
+
+	dir := util.ShouldAbs(filepath.Dir(testSubmissionPath))
+
+	paths, err := util.GetAllDirents(dir, false, false)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get test submission files: '%w'.", err)
+	}
+
+	removeIndex := slices.Index(paths, testSubmissionPath)
+	paths = slices.Delete(paths, removeIndex, removeIndex+1)
+
+	return &TestSubmissionInfo{
+		ID:             id,
+		Dir:            dir,
+		Files:          paths,
+		TestSubmission: &testSubmission,
+		Assignment:     assignment,
+	}, nil
+}
+
 // Test submission are within their assignment's directory,
 // just check the source dirs for existing courses and assignments.
 func fetchTestSubmissionAssignment(testSubmissionPath string) (*model.Assignment, error) {
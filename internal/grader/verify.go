@@ -0,0 +1,253 @@
+package grader
+
+// Verify assignments by grading their test submissions and comparing against the expected results.
+// Verification gradings are not saved (and do not count against any user).
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/edulinq/autograder/internal/common"
+	"github.com/edulinq/autograder/internal/config"
+	"github.com/edulinq/autograder/internal/docker"
+	"github.com/edulinq/autograder/internal/log"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+const VERIFY_USER = "verify"
+
+type VerifyOptions struct {
+	PoolSize   int  `json:"pool-size" help:"The number of test submissions to grade at the same time. Defaults to the grading.queue.workers config option." default:"0"`
+	ForceBuild bool `json:"force-build" help:"Always rebuild assignment images (instead of only rebuilding images that have changed)." default:"false"`
+}
+
+type VerifyResult struct {
+	Passed    bool `json:"passed"`
+	NumPassed int  `json:"num-passed"`
+	NumFailed int  `json:"num-failed"`
+
+	// Results for each test submission, sorted by assignment and test submission ID.
+	Results []*TestSubmissionResult `json:"results"`
+}
+
+type TestSubmissionResult struct {
+	CourseID     string `json:"course-id"`
+	AssignmentID string `json:"assignment-id"`
+
+	// The relative path (from the assignment's source dir) to the test submission.
+	ID string `json:"id"`
+
+	Passed bool `json:"passed"`
+
+	// Why the test submission failed.
+	Message string `json:"message,omitempty"`
+
+	// A unified diff of the expected and actual results (if they were both available).
+	Diff string `json:"diff,omitempty"`
+}
+
+// The parts of a question that are compared when verifying (see model.GradedQuestion.Equals()).
+type verifyQuestion struct {
+	Name      string  `json:"name"`
+	MaxPoints float64 `json:"max_points"`
+	Score     float64 `json:"score"`
+	HardFail  bool    `json:"hard_fail,omitempty"`
+	Skipped   bool    `json:"skipped,omitempty"`
+	Message   string  `json:"message,omitempty"`
+}
+
+// Verify all the assignments in a course that have test submissions.
+func VerifyCourse(ctx context.Context, course *model.Course, options VerifyOptions) (*VerifyResult, error) {
+	return VerifyAssignments(ctx, course.GetSortedAssignments(), options)
+}
+
+// Build the images for the given assignments, and then grade all their test submissions.
+// Assignments without any test submissions are skipped,
+// but it is an error for all the assignments to have no test submissions.
+// A failed image build will fail all the assignment's test submissions (but is not an error).
+func VerifyAssignments(ctx context.Context, assignments []*model.Assignment, options VerifyOptions) (*VerifyResult, error) {
+	poolSize := options.PoolSize
+	if poolSize <= 0 {
+		poolSize = max(1, config.GRADING_QUEUE_WORKERS.Get())
+	}
+
+	gradeOptions := GetDefaultGradeOptions()
+	gradeOptions.CheckRejection = false
+	gradeOptions.AllowLate = true
+
+	result := &VerifyResult{
+		Results: make([]*TestSubmissionResult, 0),
+	}
+
+	testSubmissions := make([]*TestSubmissionInfo, 0)
+
+	for _, assignment := range assignments {
+		assignmentTestSubmissions, err := GetAssignmentTestSubmissions(assignment)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(assignmentTestSubmissions) == 0 {
+			continue
+		}
+
+		err = docker.BuildImageFromSource(assignment, options.ForceBuild, false, docker.NewBuildOptions())
+		if err != nil {
+			log.Warn("Failed to build image for verification.", err, assignment)
+
+			for _, testSubmission := range assignmentTestSubmissions {
+				testResult := newTestSubmissionResult(testSubmission)
+				testResult.Message = fmt.Sprintf("Failed to build assignment image: '%v'.", err)
+				result.Results = append(result.Results, testResult)
+			}
+
+			continue
+		}
+
+		testSubmissions = append(testSubmissions, assignmentTestSubmissions...)
+	}
+
+	if (len(testSubmissions) == 0) && (len(result.Results) == 0) {
+		return nil, fmt.Errorf("Could not find any test submissions.")
+	}
+
+	poolResult, err := util.RunParallelPoolMap(poolSize, testSubmissions, ctx, func(testSubmission *TestSubmissionInfo) (*TestSubmissionResult, error) {
+		return verifyTestSubmission(ctx, testSubmission, gradeOptions), nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to grade test submissions: '%w'.", err)
+	}
+
+	poolResult.IsDone()
+
+	if poolResult.Canceled {
+		return nil, fmt.Errorf("Verification was canceled.")
+	}
+
+	for _, testSubmission := range testSubmissions {
+		result.Results = append(result.Results, poolResult.Results[testSubmission])
+	}
+
+	sort.Slice(result.Results, func(i int, j int) bool {
+		if result.Results[i].AssignmentID != result.Results[j].AssignmentID {
+			return result.Results[i].AssignmentID < result.Results[j].AssignmentID
+		}
+
+		return result.Results[i].ID < result.Results[j].ID
+	})
+
+	for _, testResult := range result.Results {
+		if testResult.Passed {
+			result.NumPassed++
+		} else {
+			result.NumFailed++
+		}
+	}
+
+	result.Passed = (result.NumFailed == 0)
+
+	return result, nil
+}
+
+func newTestSubmissionResult(testSubmission *TestSubmissionInfo) *TestSubmissionResult {
+	return &TestSubmissionResult{
+		CourseID:     testSubmission.Assignment.GetCourse().GetID(),
+		AssignmentID: testSubmission.Assignment.GetID(),
+		ID:           testSubmission.ID,
+	}
+}
+
+// Grade a test submission (without saving it) and compare the result against the expected result.
+func verifyTestSubmission(ctx context.Context, testSubmission *TestSubmissionInfo, options GradeOptions) *TestSubmissionResult {
+	result := newTestSubmissionResult(testSubmission)
+
+	assignment := testSubmission.Assignment
+	expected := testSubmission.TestSubmission
+
+	if expected.GradingInfo == nil {
+		result.Message = "Test submission does not have an expected result."
+		return result
+	}
+
+	fullSubmissionID := common.CreateFullSubmissionID(assignment.GetCourse().GetID(), assignment.GetID(), VERIFY_USER, util.UUID())
+
+	gradingInfo, _, stdout, stderr, softError, err := runGrader(ctx, assignment, testSubmission.Dir, options, fullSubmissionID, nil)
+	if err != nil {
+		result.Message = fmt.Sprintf("Failed to grade test submission: '%v'.", err)
+		log.Debug("Test submission failed to grade.", err, assignment,
+			log.NewAttr("test-submission", testSubmission.ID), log.NewAttr("stdout", stdout), log.NewAttr("stderr", stderr))
+		return result
+	}
+
+	if expected.SoftError {
+		if softError != expected.GradingInfo.Message {
+			result.Message = "Soft error does not match the expected soft error."
+			result.Diff = computeVerifyDiff(expected.GradingInfo.Message+"\n", softError+"\n")
+			return result
+		}
+
+		result.Passed = true
+		return result
+	}
+
+	if softError != "" {
+		result.Message = fmt.Sprintf("Got an unexpected soft error: '%s'.", softError)
+		return result
+	}
+
+	gradingInfo.ComputePoints()
+
+	checkMessages := !expected.IgnoreMessages
+	if !gradingInfo.Equals(*expected.GradingInfo, checkMessages) {
+		result.Message = "Grading result does not match the expected result."
+		result.Diff = computeVerifyDiff(getVerifyJSON(expected.GradingInfo, checkMessages), getVerifyJSON(gradingInfo, checkMessages))
+		return result
+	}
+
+	result.Passed = true
+	return result
+}
+
+// Get the JSON for just the parts of a grading result that are compared.
+func getVerifyJSON(gradingInfo *model.GradingInfo, checkMessages bool) string {
+	questions := make([]*verifyQuestion, 0, len(gradingInfo.Questions))
+	for _, question := range gradingInfo.Questions {
+		if question == nil {
+			questions = append(questions, nil)
+			continue
+		}
+
+		verify := &verifyQuestion{
+			Name:      question.Name,
+			MaxPoints: question.MaxPoints,
+			Score:     question.Score,
+			HardFail:  question.HardFail,
+			Skipped:   question.Skipped,
+		}
+
+		if checkMessages {
+			verify.Message = question.Message
+		}
+
+		questions = append(questions, verify)
+	}
+
+	data := map[string]any{
+		"name":      gradingInfo.Name,
+		"questions": questions,
+	}
+
+	return util.MustToJSONIndent(data) + "\n"
+}
+
+func computeVerifyDiff(expected string, actual string) string {
+	diff, err := util.ComputeUnfiedDiffFull(expected, "expected", actual, "actual")
+	if err != nil {
+		log.Warn("Failed to compute verification diff.", err)
+		return ""
+	}
+
+	return diff
+}
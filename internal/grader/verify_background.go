@@ -0,0 +1,120 @@
+package grader
+
+// Verifications can be run in the background (so that callers like the API do not need to wait for them).
+// Like verification gradings, background verifications are not saved.
+// They are kept in memory while running, and for a while after they finish so that their results can be fetched.
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/log"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/timestamp"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+type VerifyStatus struct {
+	ID       string `json:"id"`
+	CourseID string `json:"course-id"`
+
+	StartTime timestamp.Timestamp  `json:"start-time"`
+	EndTime   *timestamp.Timestamp `json:"end-time,omitempty"`
+
+	Complete bool `json:"complete"`
+
+	// Only set once the verification is complete (and did not fail).
+	Result *VerifyResult `json:"result,omitempty"`
+
+	// Set if the verification failed.
+	Error string `json:"error,omitempty"`
+}
+
+// How long finished background verifications are kept.
+var backgroundVerifyRetentionMS int64 = 60 * 60 * 1000
+
+var backgroundVerifyLock sync.Mutex
+
+// {id: status}.
+var backgroundVerifies map[string]*VerifyStatus = make(map[string]*VerifyStatus)
+
+func init() {
+	db.AddResetForTestingHook(clearBackgroundVerifies)
+}
+
+// Start verifying assignments in the background (see VerifyAssignments()).
+// Returns the status of the new verification, which can be checked again with GetVerifyStatus().
+func StartVerifyAssignments(course *model.Course, assignments []*model.Assignment, options VerifyOptions) *VerifyStatus {
+	status := &VerifyStatus{
+		ID:        util.UUID(),
+		CourseID:  course.GetID(),
+		StartTime: timestamp.Now(),
+	}
+
+	backgroundVerifyLock.Lock()
+	backgroundVerifies[status.ID] = status
+	initialStatus := *status
+	backgroundVerifyLock.Unlock()
+
+	go runBackgroundVerify(status, assignments, options)
+
+	return &initialStatus
+}
+
+// Get (a copy of) the status of a background verification.
+// Returns nil if there is no such verification (e.g., it finished a while ago).
+func GetVerifyStatus(id string) *VerifyStatus {
+	backgroundVerifyLock.Lock()
+	defer backgroundVerifyLock.Unlock()
+
+	status := backgroundVerifies[id]
+	if status == nil {
+		return nil
+	}
+
+	statusCopy := *status
+	return &statusCopy
+}
+
+func runBackgroundVerify(status *VerifyStatus, assignments []*model.Assignment, options VerifyOptions) {
+	// The background context is used so the verification is not canceled when the caller (e.g., an HTTP request) is done.
+	result, err := VerifyAssignments(context.Background(), assignments, options)
+	if err != nil {
+		log.Warn("Background verification failed.", err, log.NewCourseAttr(status.CourseID), log.NewAttr("verify-id", status.ID))
+	}
+
+	backgroundVerifyLock.Lock()
+
+	status.Complete = true
+	status.EndTime = timestamp.NowPointer()
+	status.Result = result
+
+	if err != nil {
+		status.Error = err.Error()
+	}
+
+	backgroundVerifyLock.Unlock()
+
+	time.AfterFunc(time.Duration(backgroundVerifyRetentionMS)*time.Millisecond, func() {
+		removeBackgroundVerify(status)
+	})
+}
+
+func removeBackgroundVerify(status *VerifyStatus) {
+	backgroundVerifyLock.Lock()
+	defer backgroundVerifyLock.Unlock()
+
+	if backgroundVerifies[status.ID] == status {
+		delete(backgroundVerifies, status.ID)
+	}
+}
+
+// Remove all background verifications (running verifications will still finish, but their results will not be kept).
+func clearBackgroundVerifies() {
+	backgroundVerifyLock.Lock()
+	defer backgroundVerifyLock.Unlock()
+
+	backgroundVerifies = make(map[string]*VerifyStatus)
+}
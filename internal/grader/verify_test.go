@@ -0,0 +1,130 @@
+package grader
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/edulinq/autograder/internal/config"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestVerifyAssignmentsBase(test *testing.T) {
+	db.ResetForTesting()
+	defer db.ResetForTesting()
+
+	defer config.DOCKER_DISABLE.Set(config.DOCKER_DISABLE.Get())
+	config.DOCKER_DISABLE.Set(true)
+
+	// Only the bash assignment can be graded without installing any grader dependencies.
+	assignment := db.MustGetAssignment("course-languages", "bash")
+
+	submissions, err := db.GetSubmissionHistory(assignment, BASE_TEST_USER)
+	if err != nil {
+		test.Fatalf("Failed to get submissions: '%v'.", err)
+	}
+
+	result, err := VerifyAssignments(context.Background(), []*model.Assignment{assignment}, VerifyOptions{PoolSize: 2})
+	if err != nil {
+		test.Fatalf("Failed to verify assignment: '%v'.", err)
+	}
+
+	expectedIDs := []string{
+		"test-submissions/crash",
+		"test-submissions/not-implemented",
+		"test-submissions/solution",
+	}
+
+	actualIDs := make([]string, 0, len(result.Results))
+	for _, testResult := range result.Results {
+		actualIDs = append(actualIDs, testResult.ID)
+
+		if !testResult.Passed {
+			test.Errorf("Test submission '%s' did not pass: '%s'.", testResult.ID, util.MustToJSONIndent(testResult))
+		}
+	}
+
+	if util.MustToJSON(expectedIDs) != util.MustToJSON(actualIDs) {
+		test.Fatalf("Unexpected test submissions. Expected: '%s', Actual: '%s'.", util.MustToJSON(expectedIDs), util.MustToJSON(actualIDs))
+	}
+
+	if !result.Passed || (result.NumPassed != len(expectedIDs)) || (result.NumFailed != 0) {
+		test.Fatalf("Unexpected verify counts: '%s'.", util.MustToJSONIndent(result))
+	}
+
+	// Verification should not save any submissions.
+	newSubmissions, err := db.GetSubmissionHistory(assignment, BASE_TEST_USER)
+	if err != nil {
+		test.Fatalf("Failed to get submissions: '%v'.", err)
+	}
+
+	if len(submissions) != len(newSubmissions) {
+		test.Fatalf("Verification changed the number of submissions. Expected: %d, Actual: %d.", len(submissions), len(newSubmissions))
+	}
+}
+
+func TestVerifyTestSubmissionMismatch(test *testing.T) {
+	db.ResetForTesting()
+	defer db.ResetForTesting()
+
+	defer config.DOCKER_DISABLE.Set(config.DOCKER_DISABLE.Get())
+	config.DOCKER_DISABLE.Set(true)
+
+	assignment := db.MustGetAssignment("course-languages", "bash")
+
+	testSubmissions, err := GetAssignmentTestSubmissions(assignment)
+	if err != nil {
+		test.Fatalf("Failed to get test submissions: '%v'.", err)
+	}
+
+	var solution *TestSubmissionInfo = nil
+	var crash *TestSubmissionInfo = nil
+	for _, testSubmission := range testSubmissions {
+		switch testSubmission.ID {
+		case "test-submissions/solution":
+			solution = testSubmission
+		case "test-submissions/crash":
+			crash = testSubmission
+		}
+	}
+
+	if (solution == nil) || (crash == nil) {
+		test.Fatalf("Could not find test submissions: '%s'.", util.MustToJSONIndent(testSubmissions))
+	}
+
+	// Expect a lower score.
+	solution.TestSubmission.GradingInfo.Questions[0].Score = 5
+
+	// Expect a different soft error.
+	crash.TestSubmission.GradingInfo.Message = "ZZZ"
+
+	testCases := []struct {
+		testSubmission  *TestSubmissionInfo
+		expectedMessage string
+		expectedDiff    string
+	}{
+		{solution, "Grading result does not match the expected result.", `"score": 5`},
+		{crash, "Soft error does not match the expected soft error.", "-ZZZ"},
+	}
+
+	for i, testCase := range testCases {
+		result := verifyTestSubmission(context.Background(), testCase.testSubmission, GetDefaultGradeOptions())
+
+		if result.Passed {
+			test.Errorf("Case %d: Test submission passed when it should not.", i)
+			continue
+		}
+
+		if testCase.expectedMessage != result.Message {
+			test.Errorf("Case %d: Unexpected message. Expected: '%s', Actual: '%s'.", i, testCase.expectedMessage, result.Message)
+			continue
+		}
+
+		if !strings.Contains(result.Diff, testCase.expectedDiff) {
+			test.Errorf("Case %d: Diff does not contain '%s': '%s'.", i, testCase.expectedDiff, result.Diff)
+			continue
+		}
+	}
+}
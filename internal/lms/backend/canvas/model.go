@@ -1,6 +1,7 @@
 package canvas
 
 import (
+	"slices"
 	"strings"
 	"time"
 
@@ -47,6 +48,7 @@ type Enrollment struct {
 	Type            string `json:"type"`
 	EnrollmentState string `json:"enrollment_state"`
 	Role            string `json:"role"`
+	CourseSectionID string `json:"course_section_id"`
 }
 
 // Canvas enrollment to autograder role.
@@ -94,6 +96,24 @@ func (this *User) GetRole() model.CourseUserRole {
 	return maxRole
 }
 
+// Get the IDs of the sections this user is enrolled in.
+// Returns nil if no enrollment has a section.
+func (this *User) GetSections() []string {
+	var sections []string = nil
+
+	for _, enrollment := range this.Enrollments {
+		if (enrollment.CourseSectionID == "") || slices.Contains(sections, enrollment.CourseSectionID) {
+			continue
+		}
+
+		sections = append(sections, enrollment.CourseSectionID)
+	}
+
+	slices.Sort(sections)
+
+	return sections
+}
+
 func (this *User) ToLMSType() *lmstypes.User {
 	email := this.LoginID
 	if (email == "") || !strings.Contains(email, "@") {
@@ -105,6 +125,8 @@ func (this *User) ToLMSType() *lmstypes.User {
 		Name:  this.Name,
 		Email: email,
 		Role:  this.GetRole(),
+
+		Sections: this.GetSections(),
 	}
 }
 
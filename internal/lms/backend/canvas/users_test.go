@@ -21,6 +21,8 @@ func TestCanvasUserGetBase(test *testing.T) {
 				Name:  "course-owner",
 				Email: "course-owner@test.edulinq.org",
 				Role:  model.CourseRoleOwner,
+
+				Sections: []string{"153926"},
 			},
 		},
 		{
@@ -30,6 +32,8 @@ func TestCanvasUserGetBase(test *testing.T) {
 				Name:  "course-admin",
 				Email: "course-admin@test.edulinq.org",
 				Role:  model.CourseRoleAdmin,
+
+				Sections: []string{"153833"},
 			},
 		},
 		{
@@ -39,6 +43,8 @@ func TestCanvasUserGetBase(test *testing.T) {
 				Name:  "course-student",
 				Email: "course-student@test.edulinq.org",
 				Role:  model.CourseRoleStudent,
+
+				Sections: []string{"153234", "153833"},
 			},
 		},
 	}
@@ -50,7 +56,7 @@ func TestCanvasUserGetBase(test *testing.T) {
 			continue
 		}
 
-		if *testCase.expected != *user {
+		if !reflect.DeepEqual(testCase.expected, user) {
 			test.Errorf("Case %d: User not as expected. Expected: '%+v', Actual: '%+v'.",
 				i, util.MustToJSONIndent(testCase.expected), util.MustToJSONIndent(user))
 			continue
@@ -65,18 +71,24 @@ func TestCanvasUsersGetBase(test *testing.T) {
 			Name:  "course-student",
 			Email: "course-student@test.edulinq.org",
 			Role:  model.CourseRoleStudent,
+
+			Sections: []string{"153234", "153833"},
 		},
 		&lmstypes.User{
 			ID:    "00020",
 			Name:  "course-admin",
 			Email: "course-admin@test.edulinq.org",
 			Role:  model.CourseRoleAdmin,
+
+			Sections: []string{"153833"},
 		},
 		&lmstypes.User{
 			ID:    "00010",
 			Name:  "course-owner",
 			Email: "course-owner@test.edulinq.org",
 			Role:  model.CourseRoleOwner,
+
+			Sections: []string{"153926"},
 		},
 	}
 
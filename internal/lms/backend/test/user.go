@@ -2,6 +2,7 @@ package test
 
 import (
 	"fmt"
+	"slices"
 
 	"github.com/edulinq/autograder/internal/db"
 	"github.com/edulinq/autograder/internal/lms/lmstypes"
@@ -62,5 +63,7 @@ func UserFromCourseUser(user *model.CourseUser) *lmstypes.User {
 		Name:  user.GetName(false),
 		Email: user.Email,
 		Role:  user.Role,
+
+		Sections: slices.Clone(user.Sections),
 	}
 }
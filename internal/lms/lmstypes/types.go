@@ -10,6 +10,10 @@ type User struct {
 	Name  string
 	Email string
 	Role  model.CourseUserRole
+
+	// The LMS sections the user is in.
+	// Nil if the LMS does not provide section information.
+	Sections []string
 }
 
 type SubmissionScore struct {
@@ -41,6 +45,8 @@ func (this *User) ToRawServerUserData(courseID string) *model.RawServerUserData
 		Course:      courseID,
 		CourseRole:  this.Role.String(),
 		CourseLMSID: this.ID,
+
+		CourseSections: this.Sections,
 	}
 
 	return data
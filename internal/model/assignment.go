@@ -30,6 +30,17 @@ type Assignment struct {
 	// Inheritable
 	LatePolicy      *LateGradingPolicy   `json:"late-policy,omitempty"`
 	SubmissionLimit *SubmissionLimitInfo `json:"submission-limit,omitempty"`
+	OpenDate        *timestamp.Timestamp `json:"open-date,omitempty"`
+	CloseDate       *timestamp.Timestamp `json:"close-date,omitempty"`
+
+	// If set, students submit this assignment as groups.
+	GroupOptions *GroupOptions `json:"group-options,omitempty"`
+
+	// If set, some grading feedback may be held back from students.
+	FeedbackRelease *FeedbackReleaseInfo `json:"feedback-release,omitempty"`
+
+	// If set, graders score submissions against these hand-graded criteria on top of the autograder.
+	Rubric *Rubric `json:"rubric,omitempty"`
 
 	docker.ImageInfo
 
@@ -103,6 +114,37 @@ func (this *Assignment) GetSubmissionLimit() *SubmissionLimitInfo {
 	return this.Course.SubmissionLimit
 }
 
+// Get the time this assignment opens (is released to students) or nil if the assignment is always open.
+// If this assignment has no open date, the course will be checked.
+func (this *Assignment) GetOpenDate() *timestamp.Timestamp {
+	if this.OpenDate != nil {
+		return this.OpenDate
+	}
+
+	return this.Course.OpenDate
+}
+
+// Get the time this assignment stops accepting student submissions or nil if the assignment never closes.
+// If this assignment has no close date, the course will be checked.
+func (this *Assignment) GetCloseDate() *timestamp.Timestamp {
+	if this.CloseDate != nil {
+		return this.CloseDate
+	}
+
+	return this.Course.CloseDate
+}
+
+// Check if this assignment has been released to students at the given time.
+func (this *Assignment) IsOpen(now timestamp.Timestamp) bool {
+	openDate := this.GetOpenDate()
+	return (openDate == nil) || (now >= *openDate)
+}
+
+// Check if this assignment is submitted by groups of students.
+func (this *Assignment) IsGroupAssignment() bool {
+	return this.GroupOptions != nil
+}
+
 func (this *Assignment) GetImageName() string {
 	return strings.ToLower(fmt.Sprintf("autograder.%s.%s", this.Course.GetID(), this.ID))
 }
@@ -152,6 +194,32 @@ func (this *Assignment) Validate() error {
 		}
 	}
 
+	err = validateOpenCloseDates(this.GetOpenDate(), this.GetCloseDate())
+	if err != nil {
+		return err
+	}
+
+	if this.GroupOptions != nil {
+		err = this.GroupOptions.Validate()
+		if err != nil {
+			return fmt.Errorf("Failed to validate group options: '%w'.", err)
+		}
+	}
+
+	if this.FeedbackRelease != nil {
+		err = this.FeedbackRelease.Validate()
+		if err != nil {
+			return fmt.Errorf("Failed to validate feedback release: '%w'.", err)
+		}
+	}
+
+	if this.Rubric != nil {
+		err = this.Rubric.Validate()
+		if err != nil {
+			return fmt.Errorf("Failed to validate rubric: '%w'.", err)
+		}
+	}
+
 	if this.RelSourceDir == "" {
 		return fmt.Errorf("Relative source dir must not be empty.")
 	}
@@ -259,3 +327,15 @@ func CompareAssignments(a *Assignment, b *Assignment) int {
 	// Both assignments have a sort key, use that for comparison.
 	return strings.Compare(aSortID, bSortID)
 }
+
+func validateOpenCloseDates(openDate *timestamp.Timestamp, closeDate *timestamp.Timestamp) error {
+	if (openDate == nil) || (closeDate == nil) {
+		return nil
+	}
+
+	if *openDate >= *closeDate {
+		return fmt.Errorf("Open date (%s) must be before close date (%s).", openDate.SafeString(), closeDate.SafeString())
+	}
+
+	return nil
+}
@@ -26,23 +26,33 @@ func ReadAssignmentConfig(course *Course, path string, relSourceDir string) (*As
 		return nil, fmt.Errorf("Could not load assignment config (%s): '%w'.", path, err)
 	}
 
-	assignment.Course = course
-
 	// Only override the relative source dir if it is empty.
 	// This should only be the first time that we load the course from source (not from the DB).
 	if (assignment.RelSourceDir == "") && (relSourceDir != "") {
 		assignment.RelSourceDir = relSourceDir
 	}
 
-	err = assignment.Validate()
+	err = addLoadedAssignment(course, &assignment)
 	if err != nil {
-		return nil, fmt.Errorf("Failed to validate assignment config (%s): '%w'.", path, err)
+		return nil, fmt.Errorf("Failed to load assignment config (%s): '%w'.", path, err)
 	}
 
-	err = course.AddAssignment(&assignment)
+	return &assignment, nil
+}
+
+// Attach a freshly deserialized assignment to its course.
+func addLoadedAssignment(course *Course, assignment *Assignment) error {
+	assignment.Course = course
+
+	err := assignment.Validate()
 	if err != nil {
-		return nil, fmt.Errorf("Failed to add assignment to course (%s): '%w'.", path, err)
+		return fmt.Errorf("Failed to validate assignment config: '%w'.", err)
 	}
 
-	return &assignment, nil
+	err = course.AddAssignment(assignment)
+	if err != nil {
+		return fmt.Errorf("Failed to add assignment to course: '%w'.", err)
+	}
+
+	return nil
 }
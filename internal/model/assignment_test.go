@@ -0,0 +1,85 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/edulinq/autograder/internal/timestamp"
+)
+
+func TestAssignmentOpenCloseDates(test *testing.T) {
+	early := timestamp.FromMSecs(100)
+	late := timestamp.FromMSecs(200)
+
+	testCases := []struct {
+		courseOpen      *timestamp.Timestamp
+		courseClose     *timestamp.Timestamp
+		assignmentOpen  *timestamp.Timestamp
+		assignmentClose *timestamp.Timestamp
+		expectedOpen    *timestamp.Timestamp
+		expectedClose   *timestamp.Timestamp
+		isOpenAt150     bool
+	}{
+		{nil, nil, nil, nil, nil, nil, true},
+
+		// Only the assignment.
+		{nil, nil, &early, &late, &early, &late, true},
+		{nil, nil, &late, nil, &late, nil, false},
+
+		// Inherited from the course.
+		{&early, &late, nil, nil, &early, &late, true},
+		{&late, nil, nil, nil, &late, nil, false},
+
+		// The assignment overrides the course.
+		{&late, &late, &early, nil, &early, &late, true},
+		{&early, &early, nil, &late, &early, &late, true},
+	}
+
+	for i, testCase := range testCases {
+		course := &Course{OpenDate: testCase.courseOpen, CloseDate: testCase.courseClose}
+		assignment := &Assignment{OpenDate: testCase.assignmentOpen, CloseDate: testCase.assignmentClose, Course: course}
+
+		openDate := assignment.GetOpenDate()
+		if testCase.expectedOpen != openDate {
+			test.Errorf("Case %d: Unexpected open date. Expected: '%s', Actual: '%s'.", i, testCase.expectedOpen.SafeString(), openDate.SafeString())
+			continue
+		}
+
+		closeDate := assignment.GetCloseDate()
+		if testCase.expectedClose != closeDate {
+			test.Errorf("Case %d: Unexpected close date. Expected: '%s', Actual: '%s'.", i, testCase.expectedClose.SafeString(), closeDate.SafeString())
+			continue
+		}
+
+		isOpen := assignment.IsOpen(timestamp.FromMSecs(150))
+		if testCase.isOpenAt150 != isOpen {
+			test.Errorf("Case %d: Unexpected open status. Expected: '%v', Actual: '%v'.", i, testCase.isOpenAt150, isOpen)
+			continue
+		}
+	}
+}
+
+func TestValidateOpenCloseDates(test *testing.T) {
+	early := timestamp.FromMSecs(100)
+	late := timestamp.FromMSecs(200)
+
+	testCases := []struct {
+		openDate  *timestamp.Timestamp
+		closeDate *timestamp.Timestamp
+		hasError  bool
+	}{
+		{nil, nil, false},
+		{&early, nil, false},
+		{nil, &early, false},
+		{&early, &late, false},
+		{&late, &early, true},
+		{&early, &early, true},
+	}
+
+	for i, testCase := range testCases {
+		err := validateOpenCloseDates(testCase.openDate, testCase.closeDate)
+		if (err != nil) != testCase.hasError {
+			test.Errorf("Case %d: Unexpected error result. Expected error: '%v', Actual: '%v'.", i, testCase.hasError, err)
+			continue
+		}
+	}
+}
@@ -0,0 +1,256 @@
+package model
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/edulinq/autograder/internal/timestamp"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+// The kinds of state-changing actions recorded in the audit trail.
+type AuditAction string
+
+const (
+	AuditActionUnknown          AuditAction = ""
+	AuditActionUserUpsert                   = "user-upsert"
+	AuditActionUserRemove                   = "user-remove"
+	AuditActionUserErase                    = "user-erase"
+	AuditActionCourseEnroll                 = "course-enroll"
+	AuditActionCourseDrop                   = "course-drop"
+	AuditActionCourseUpsert                 = "course-upsert"
+	AuditActionProxySubmit                  = "proxy-submit"
+	AuditActionProxyResubmit                = "proxy-resubmit"
+	AuditActionProxyRegrade                 = "proxy-regrade"
+	AuditActionSubmissionRemove             = "submission-remove"
+	AuditActionRubricGrade                  = "rubric-grade"
+	AuditActionRubricRemove                 = "rubric-remove"
+	AuditActionRegradeResolve               = "regrade-resolve"
+	AuditActionTokenCreate                  = "token-create"
+	AuditActionLMSUploadScores              = "lms-upload-scores"
+)
+
+var auditActions = []AuditAction{
+	AuditActionUserUpsert,
+	AuditActionUserRemove,
+	AuditActionUserErase,
+	AuditActionCourseEnroll,
+	AuditActionCourseDrop,
+	AuditActionCourseUpsert,
+	AuditActionProxySubmit,
+	AuditActionProxyResubmit,
+	AuditActionProxyRegrade,
+	AuditActionSubmissionRemove,
+	AuditActionRubricGrade,
+	AuditActionRubricRemove,
+	AuditActionRegradeResolve,
+	AuditActionTokenCreate,
+	AuditActionLMSUploadScores,
+}
+
+// A single entry in the append-only audit trail.
+// Each record contains the hash of the record before it,
+// so any change to (or removal of) an earlier record will break the chain.
+type AuditRecord struct {
+	// The position of this record in the trail (starting at 1).
+	Index int64 `json:"index"`
+
+	Timestamp timestamp.Timestamp `json:"timestamp"`
+	RequestID string              `json:"request-id"`
+	Action    AuditAction         `json:"action"`
+
+	// The email of the user that took this action.
+	Actor string `json:"actor"`
+
+	CourseID     string `json:"course-id,omitempty"`
+	AssignmentID string `json:"assignment-id,omitempty"`
+
+	// What was acted on (e.g., a user's email or a submission ID).
+	Target string `json:"target,omitempty"`
+
+	// A short summary of the target before and after the action.
+	Before map[string]string `json:"before,omitempty"`
+	After  map[string]string `json:"after,omitempty"`
+
+	PreviousHash string `json:"previous-hash"`
+	Hash         string `json:"hash"`
+}
+
+// Filters for audit records.
+// Any empty field will not be used for filtering.
+type AuditQuery struct {
+	CourseID string              `json:"target-course,omitempty"`
+	Actor    string              `json:"actor,omitempty"`
+	Target   string              `json:"target,omitempty"`
+	Action   AuditAction         `json:"action,omitempty"`
+	After    timestamp.Timestamp `json:"after,omitempty"`
+}
+
+func NewAuditRecord(action AuditAction, target string) *AuditRecord {
+	return &AuditRecord{
+		Action: action,
+		Target: target,
+	}
+}
+
+func (this AuditAction) Validate() error {
+	if !slices.Contains(auditActions, this) {
+		return fmt.Errorf("Unknown audit action: '%s'.", string(this))
+	}
+
+	return nil
+}
+
+func (this *AuditRecord) Validate() error {
+	if this == nil {
+		return fmt.Errorf("Audit record is nil.")
+	}
+
+	err := this.Action.Validate()
+	if err != nil {
+		return err
+	}
+
+	if this.Actor == "" {
+		return fmt.Errorf("Audit record ('%s') has no actor.", this.Action)
+	}
+
+	if this.Timestamp.IsZero() {
+		this.Timestamp = timestamp.Now()
+	}
+
+	return nil
+}
+
+// Compute the hash for this record (which covers every field except the hash itself).
+func (this *AuditRecord) ComputeHash() (string, error) {
+	record := *this
+	record.Hash = ""
+
+	data, err := util.ToJSON(record)
+	if err != nil {
+		return "", fmt.Errorf("Failed to serialize audit record %d: '%w'.", this.Index, err)
+	}
+
+	return util.Sha256HexFromString(data), nil
+}
+
+// Set this record's position in the trail (after the given record, which may be nil for the first record) and hash.
+func (this *AuditRecord) Chain(previous *AuditRecord) error {
+	this.Index = 1
+	this.PreviousHash = ""
+
+	if previous != nil {
+		this.Index = previous.Index + 1
+		this.PreviousHash = previous.Hash
+	}
+
+	hash, err := this.ComputeHash()
+	if err != nil {
+		return err
+	}
+
+	this.Hash = hash
+
+	return nil
+}
+
+// Check that the given records (which should be the full trail in order) form an unbroken chain.
+// An error describing the first problem will be returned.
+func VerifyAuditChain(records []*AuditRecord) error {
+	var previous *AuditRecord = nil
+
+	for _, record := range records {
+		expectedIndex := int64(1)
+		expectedPreviousHash := ""
+
+		if previous != nil {
+			expectedIndex = previous.Index + 1
+			expectedPreviousHash = previous.Hash
+		}
+
+		if record.Index != expectedIndex {
+			return fmt.Errorf("Audit record has an unexpected index. Expected: %d, Actual: %d.", expectedIndex, record.Index)
+		}
+
+		if record.PreviousHash != expectedPreviousHash {
+			return fmt.Errorf("Audit record %d does not match the hash of the previous record.", record.Index)
+		}
+
+		hash, err := record.ComputeHash()
+		if err != nil {
+			return err
+		}
+
+		if record.Hash != hash {
+			return fmt.Errorf("Audit record %d does not match its own hash.", record.Index)
+		}
+
+		previous = record
+	}
+
+	return nil
+}
+
+func (this AuditQuery) Match(record *AuditRecord) bool {
+	if record == nil {
+		return false
+	}
+
+	if (this.CourseID != "") && (record.CourseID != this.CourseID) {
+		return false
+	}
+
+	if (this.Actor != "") && (record.Actor != this.Actor) {
+		return false
+	}
+
+	if (this.Target != "") && (record.Target != this.Target) {
+		return false
+	}
+
+	if (this.Action != AuditActionUnknown) && (record.Action != this.Action) {
+		return false
+	}
+
+	if record.Timestamp < this.After {
+		return false
+	}
+
+	return true
+}
+
+// Get a short summary of a user for the audit trail.
+// If a course is given, then only the user's information for that course will be included.
+// Returns nil for a nil user.
+func (this *ServerUser) GetAuditSummary(courseID string) map[string]string {
+	if this == nil {
+		return nil
+	}
+
+	summary := map[string]string{
+		"name":        this.GetName(false),
+		"server-role": this.Role.String(),
+	}
+
+	if courseID != "" {
+		info, ok := this.CourseInfo[courseID]
+		if ok {
+			summary["course-role"] = info.Role.String()
+			summary["lms-id"] = info.GetLMSID()
+		}
+
+		return summary
+	}
+
+	courses := make([]string, 0, len(this.CourseInfo))
+	for id, info := range this.CourseInfo {
+		courses = append(courses, fmt.Sprintf("%s=%s", id, info.Role.String()))
+	}
+
+	slices.Sort(courses)
+	summary["course-roles"] = strings.Join(courses, ",")
+
+	return summary
+}
@@ -0,0 +1,119 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/edulinq/autograder/internal/timestamp"
+)
+
+func TestVerifyAuditChain(test *testing.T) {
+	testCases := []struct {
+		tamper   func(records []*AuditRecord) []*AuditRecord
+		hasError bool
+	}{
+		// No changes.
+		{func(records []*AuditRecord) []*AuditRecord { return records }, false},
+		{func(records []*AuditRecord) []*AuditRecord { return records[:0] }, false},
+		{func(records []*AuditRecord) []*AuditRecord { return records[:2] }, false},
+
+		// Changed fields.
+		{func(records []*AuditRecord) []*AuditRecord { records[1].Actor = "ZZZ"; return records }, true},
+		{func(records []*AuditRecord) []*AuditRecord { records[0].After["server-role"] = "owner"; return records }, true},
+		{func(records []*AuditRecord) []*AuditRecord { records[2].Timestamp++; return records }, true},
+
+		// Rehashing a changed record still breaks the next record.
+		{func(records []*AuditRecord) []*AuditRecord {
+			records[0].Target = "ZZZ"
+			records[0].Hash, _ = records[0].ComputeHash()
+			return records
+		}, true},
+
+		// Removed records.
+		{func(records []*AuditRecord) []*AuditRecord { return records[1:] }, true},
+		{func(records []*AuditRecord) []*AuditRecord { return []*AuditRecord{records[0], records[2]} }, true},
+
+		// Reordered records.
+		{func(records []*AuditRecord) []*AuditRecord { return []*AuditRecord{records[0], records[2], records[1]} }, true},
+	}
+
+	for i, testCase := range testCases {
+		records := makeTestAuditChain(test)
+		records = testCase.tamper(records)
+
+		err := VerifyAuditChain(records)
+		if testCase.hasError && (err == nil) {
+			test.Errorf("Case %d: Did not get an expected error.", i)
+		} else if !testCase.hasError && (err != nil) {
+			test.Errorf("Case %d: Got an unexpected error: '%v'.", i, err)
+		}
+	}
+}
+
+func TestAuditQueryMatch(test *testing.T) {
+	record := &AuditRecord{
+		Timestamp: timestamp.FromMSecs(100),
+		Action:    AuditActionCourseDrop,
+		Actor:     "course-admin@test.edulinq.org",
+		CourseID:  "course101",
+		Target:    "course-student@test.edulinq.org",
+	}
+
+	testCases := []struct {
+		query    AuditQuery
+		expected bool
+	}{
+		{AuditQuery{}, true},
+		{AuditQuery{CourseID: "course101"}, true},
+		{AuditQuery{Actor: "course-admin@test.edulinq.org", Target: "course-student@test.edulinq.org"}, true},
+		{AuditQuery{Action: AuditActionCourseDrop, After: timestamp.FromMSecs(100)}, true},
+
+		{AuditQuery{CourseID: "ZZZ"}, false},
+		{AuditQuery{Actor: "ZZZ"}, false},
+		{AuditQuery{Target: "ZZZ"}, false},
+		{AuditQuery{Action: AuditActionCourseEnroll}, false},
+		{AuditQuery{After: timestamp.FromMSecs(101)}, false},
+	}
+
+	for i, testCase := range testCases {
+		actual := testCase.query.Match(record)
+		if testCase.expected != actual {
+			test.Errorf("Case %d: Unexpected match result. Expected: '%v', Actual: '%v'.", i, testCase.expected, actual)
+		}
+	}
+}
+
+func makeTestAuditChain(test *testing.T) []*AuditRecord {
+	records := []*AuditRecord{
+		&AuditRecord{
+			Timestamp: timestamp.FromMSecs(100),
+			Action:    AuditActionUserUpsert,
+			Actor:     "server-admin@test.edulinq.org",
+			Target:    "course-student@test.edulinq.org",
+			After:     map[string]string{"server-role": "user"},
+		},
+		&AuditRecord{
+			Timestamp: timestamp.FromMSecs(200),
+			Action:    AuditActionTokenCreate,
+			Actor:     "course-student@test.edulinq.org",
+			Target:    "course-student@test.edulinq.org",
+		},
+		&AuditRecord{
+			Timestamp: timestamp.FromMSecs(300),
+			Action:    AuditActionUserRemove,
+			Actor:     "server-admin@test.edulinq.org",
+			Target:    "course-student@test.edulinq.org",
+		},
+	}
+
+	var previous *AuditRecord = nil
+	for _, record := range records {
+		err := record.Chain(previous)
+		if err != nil {
+			test.Fatalf("Failed to chain record: '%v'.", err)
+		}
+
+		previous = record
+	}
+
+	return records
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/edulinq/autograder/internal/config"
 	"github.com/edulinq/autograder/internal/docker"
 	"github.com/edulinq/autograder/internal/log"
+	"github.com/edulinq/autograder/internal/timestamp"
 	"github.com/edulinq/autograder/internal/util"
 )
 
@@ -25,9 +26,15 @@ type Course struct {
 	// Inheritable by assignments.
 	LatePolicy      *LateGradingPolicy   `json:"late-policy,omitempty"`
 	SubmissionLimit *SubmissionLimitInfo `json:"submission-limit,omitempty"`
+	OpenDate        *timestamp.Timestamp `json:"open-date,omitempty"`
+	CloseDate       *timestamp.Timestamp `json:"close-date,omitempty"`
 
 	Tasks []*UserTaskInfo `json:"tasks,omitempty"`
 
+	// The members (emails) of each section ({section: emails}).
+	// Applied to enrolled users when the course is upserted.
+	Sections map[string][]string `json:"sections,omitempty"`
+
 	// Internal fields the autograder will set.
 	Assignments map[string]*Assignment `json:"-"`
 }
@@ -109,6 +116,16 @@ func (this *Course) Validate() error {
 		}
 	}
 
+	err = validateOpenCloseDates(this.OpenDate, this.CloseDate)
+	if err != nil {
+		return err
+	}
+
+	this.Sections, err = normalizeCourseSections(this.Sections)
+	if err != nil {
+		return err
+	}
+
 	if this.Tasks == nil {
 		this.Tasks = make([]*UserTaskInfo, 0)
 	}
@@ -119,6 +136,10 @@ func (this *Course) Validate() error {
 		if err != nil {
 			return fmt.Errorf("Failed to validate task at index %d: '%w'.", i, err)
 		}
+
+		if task.Type.IsServerTask() {
+			return fmt.Errorf("Task at index %d has a server-only type ('%s').", i, task.Type)
+		}
 	}
 
 	return nil
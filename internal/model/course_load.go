@@ -74,3 +74,36 @@ func ReadCourseConfig(path string) (*Course, error) {
 
 	return &course, nil
 }
+
+// Load a course and its assignments from their serialized JSON configs.
+// This is the counterpart to LoadCourseFromPath() for databases that do not store courses as files.
+// The assignments are expected to have already been loaded from source once (and therefore have a relative source dir).
+func LoadCourseFromJSON(courseJSON string, assignmentJSONs []string) (*Course, error) {
+	var course Course
+	err := util.JSONFromString(courseJSON, &course)
+	if err != nil {
+		return nil, fmt.Errorf("Could not load course config: '%w'.", err)
+	}
+
+	course.Assignments = make(map[string]*Assignment)
+
+	err = course.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("Could not validate course config '%s': '%w'.", course.ID, err)
+	}
+
+	for _, assignmentJSON := range assignmentJSONs {
+		var assignment Assignment
+		err = util.JSONFromString(assignmentJSON, &assignment)
+		if err != nil {
+			return nil, fmt.Errorf("Could not load assignment config for course '%s': '%w'.", course.ID, err)
+		}
+
+		err = addLoadedAssignment(&course, &assignment)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to load assignment '%s' for course '%s': '%w'.", assignment.ID, course.ID, err)
+		}
+	}
+
+	return &course, nil
+}
@@ -0,0 +1,57 @@
+package model
+
+import (
+	"strings"
+
+	"github.com/edulinq/autograder/internal/util"
+)
+
+// Endpoints that do not change any state.
+// These are allowed by TOKEN_SCOPE_READ_ONLY and can be used while impersonating (viewing as) another user.
+// Endpoints must be listed in full (without the API prefix),
+// since the same action name (e.g., "scores") may be used by both read and write endpoints.
+// New endpoints are not read-only until they are added here.
+var readOnlyEndpoints = map[string]bool{
+	"audit/query":                                           true,
+	"courses/assignments/extensions/list":                   true,
+	"courses/assignments/get":                               true,
+	"courses/assignments/groups/list":                       true,
+	"courses/assignments/images/fetch":                      true,
+	"courses/assignments/images/info":                       true,
+	"courses/assignments/list":                              true,
+	"courses/assignments/regrades/list":                     true,
+	"courses/assignments/report":                            true,
+	"courses/assignments/rubric/get":                        true,
+	"courses/assignments/submissions/fetch/course/attempts": true,
+	"courses/assignments/submissions/fetch/course/scores":   true,
+	"courses/assignments/submissions/fetch/user/attempt":    true,
+	"courses/assignments/submissions/fetch/user/attempts":   true,
+	"courses/assignments/submissions/fetch/user/history":    true,
+	"courses/assignments/submissions/fetch/user/peek":       true,
+	"courses/assignments/submissions/status":                true,
+	"courses/assignments/submissions/stream":                true,
+	"courses/get":                                           true,
+	"courses/list":                                          true,
+	"courses/stats/query":                                   true,
+	"courses/users/get":                                     true,
+	"courses/users/list":                                    true,
+	"lms/user/get":                                          true,
+	"logs/query":                                            true,
+	"metadata/describe":                                     true,
+	"metadata/heartbeat":                                    true,
+	"stats/query":                                           true,
+	"system/stacks":                                         true,
+	"users/get":                                             true,
+	"users/list":                                            true,
+	"users/tokens/list":                                     true,
+}
+
+// Check if an endpoint (with or without leading/trailing slashes, but without the API prefix) does not change any state.
+func IsReadOnlyEndpoint(endpoint string) bool {
+	return readOnlyEndpoints[strings.Trim(endpoint, "/")]
+}
+
+// Get all the read-only endpoints (sorted).
+func GetReadOnlyEndpoints() []string {
+	return util.GetSortedKeys(readOnlyEndpoints)
+}
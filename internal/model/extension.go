@@ -0,0 +1,84 @@
+package model
+
+import (
+	"fmt"
+
+	"github.com/edulinq/autograder/internal/timestamp"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+// A per-user change to an assignment's deadline and/or submission limit (e.g., an accommodation).
+// At most one of DueDate and ExtraTime may be set.
+type AssignmentExtension struct {
+	CourseID     string `json:"course-id"`
+	AssignmentID string `json:"assignment-id"`
+	Email        string `json:"email"`
+
+	// A new due date for the user.
+	DueDate *timestamp.Timestamp `json:"due-date,omitempty"`
+
+	// Extra time added on to the assignment's due date for the user.
+	ExtraTime *util.DurationSpec `json:"extra-time,omitempty"`
+
+	// Submissions allowed on top of the assignment's max attempts.
+	ExtraAttempts int `json:"extra-attempts,omitempty"`
+
+	Reason     string              `json:"reason,omitempty"`
+	UpdatedBy  string              `json:"updated-by"`
+	UpdateTime timestamp.Timestamp `json:"update-time"`
+}
+
+func (this *AssignmentExtension) Validate() error {
+	if this.Email == "" {
+		return fmt.Errorf("Extension is missing a user email.")
+	}
+
+	if (this.DueDate != nil) && (this.ExtraTime != nil) {
+		return fmt.Errorf("Extension cannot have both a due date and extra time.")
+	}
+
+	if this.ExtraTime != nil {
+		err := this.ExtraTime.Validate()
+		if err != nil {
+			return fmt.Errorf("Extension has invalid extra time: '%w'.", err)
+		}
+	}
+
+	if this.ExtraAttempts < 0 {
+		return fmt.Errorf("Extension cannot have negative extra attempts, found: %d.", this.ExtraAttempts)
+	}
+
+	if (this.DueDate == nil) && ((this.ExtraTime == nil) || this.ExtraTime.IsEmpty()) && (this.ExtraAttempts == 0) {
+		return fmt.Errorf("Extension does not extend anything.")
+	}
+
+	return nil
+}
+
+// Get the due date for the user with this extension applied to the assignment's due date.
+// A nil extension will just return the given due date.
+// Extra time cannot be applied to a missing due date.
+func (this *AssignmentExtension) GetDueDate(dueDate *timestamp.Timestamp) *timestamp.Timestamp {
+	if this == nil {
+		return dueDate
+	}
+
+	if this.DueDate != nil {
+		return this.DueDate
+	}
+
+	if (this.ExtraTime == nil) || (dueDate == nil) {
+		return dueDate
+	}
+
+	extendedDueDate := *dueDate + timestamp.FromMSecs(this.ExtraTime.TotalMSecs())
+	return &extendedDueDate
+}
+
+func (this *AssignmentExtension) GetExtraAttempts() int {
+	if this == nil {
+		return 0
+	}
+
+	return this.ExtraAttempts
+}
@@ -0,0 +1,85 @@
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/edulinq/autograder/internal/timestamp"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestAssignmentExtensionValidate(test *testing.T) {
+	dueDate := timestamp.FromMSecs(1000)
+
+	testCases := []struct {
+		extension      *AssignmentExtension
+		errorSubstring string
+	}{
+		{&AssignmentExtension{Email: "a", DueDate: &dueDate}, ""},
+		{&AssignmentExtension{Email: "a", ExtraTime: &util.DurationSpec{Days: 1}}, ""},
+		{&AssignmentExtension{Email: "a", ExtraAttempts: 1}, ""},
+		{&AssignmentExtension{Email: "a", DueDate: &dueDate, ExtraAttempts: 1}, ""},
+
+		{&AssignmentExtension{DueDate: &dueDate}, "missing a user email"},
+		{&AssignmentExtension{Email: "a", DueDate: &dueDate, ExtraTime: &util.DurationSpec{Days: 1}}, "both a due date and extra time"},
+		{&AssignmentExtension{Email: "a", ExtraTime: &util.DurationSpec{Days: -1}}, "invalid extra time"},
+		{&AssignmentExtension{Email: "a", ExtraAttempts: -1}, "negative extra attempts"},
+		{&AssignmentExtension{Email: "a"}, "does not extend anything"},
+		{&AssignmentExtension{Email: "a", ExtraTime: &util.DurationSpec{}}, "does not extend anything"},
+	}
+
+	for i, testCase := range testCases {
+		err := testCase.extension.Validate()
+		if err != nil {
+			if testCase.errorSubstring == "" {
+				test.Errorf("Case %d: Unexpected error: '%v'.", i, err)
+			} else if !strings.Contains(err.Error(), testCase.errorSubstring) {
+				test.Errorf("Case %d: Error does not contain expected substring. Expected: '%s', Actual: '%v'.", i, testCase.errorSubstring, err)
+			}
+
+			continue
+		}
+
+		if testCase.errorSubstring != "" {
+			test.Errorf("Case %d: Did not get expected error containing '%s'.", i, testCase.errorSubstring)
+			continue
+		}
+	}
+}
+
+func TestAssignmentExtensionGetDueDate(test *testing.T) {
+	dueDate := timestamp.FromMSecs(1000)
+	newDueDate := timestamp.FromMSecs(5000)
+	extendedDueDate := timestamp.FromMSecs(3000)
+
+	testCases := []struct {
+		extension *AssignmentExtension
+		dueDate   *timestamp.Timestamp
+		expected  *timestamp.Timestamp
+	}{
+		{nil, nil, nil},
+		{nil, &dueDate, &dueDate},
+		{&AssignmentExtension{ExtraAttempts: 1}, &dueDate, &dueDate},
+		{&AssignmentExtension{DueDate: &newDueDate}, &dueDate, &newDueDate},
+		{&AssignmentExtension{DueDate: &newDueDate}, nil, &newDueDate},
+		{&AssignmentExtension{ExtraTime: &util.DurationSpec{Seconds: 2}}, &dueDate, &extendedDueDate},
+		{&AssignmentExtension{ExtraTime: &util.DurationSpec{Seconds: 2}}, nil, nil},
+	}
+
+	for i, testCase := range testCases {
+		actual := testCase.extension.GetDueDate(testCase.dueDate)
+
+		if (testCase.expected == nil) || (actual == nil) {
+			if testCase.expected != actual {
+				test.Errorf("Case %d: Unexpected due date. Expected: '%v', Actual: '%v'.", i, testCase.expected, actual)
+			}
+
+			continue
+		}
+
+		if *testCase.expected != *actual {
+			test.Errorf("Case %d: Unexpected due date. Expected: '%d', Actual: '%d'.", i, *testCase.expected, *actual)
+			continue
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package model
+
+import (
+	"fmt"
+
+	"github.com/edulinq/autograder/internal/timestamp"
+)
+
+// When students can see the score and message for a graded question.
+type FeedbackVisibility string
+
+const (
+	FEEDBACK_VISIBILITY_VISIBLE        FeedbackVisibility = "visible"
+	FEEDBACK_VISIBILITY_AFTER_DUE_DATE FeedbackVisibility = "after-due-date"
+	FEEDBACK_VISIBILITY_AFTER_RELEASE  FeedbackVisibility = "after-release"
+)
+
+// Settings for holding back grading feedback from students.
+// Course graders and above always see full feedback.
+type FeedbackReleaseInfo struct {
+	// The visibility for questions that do not set their own.
+	DefaultVisibility FeedbackVisibility `json:"default-visibility,omitempty"`
+
+	// When feedback held until release is shown to students.
+	// If unset, this feedback has not been released.
+	ReleaseDate *timestamp.Timestamp `json:"release-date,omitempty"`
+}
+
+func (this FeedbackVisibility) Validate() error {
+	switch this {
+	case "", FEEDBACK_VISIBILITY_VISIBLE, FEEDBACK_VISIBILITY_AFTER_DUE_DATE, FEEDBACK_VISIBILITY_AFTER_RELEASE:
+		return nil
+	default:
+		return fmt.Errorf("Unknown feedback visibility: '%s'.", this)
+	}
+}
+
+func (this *FeedbackReleaseInfo) Validate() error {
+	return this.DefaultVisibility.Validate()
+}
+
+// Check if students can see the feedback for a question at the given time.
+// Questions without a visibility use the assignment's default (which is visible if not set).
+// Unknown visibilities are treated as hidden.
+func (this *Assignment) IsQuestionVisible(question *GradedQuestion, now timestamp.Timestamp) bool {
+	visibility := question.Visibility
+	if (visibility == "") && (this.FeedbackRelease != nil) {
+		visibility = this.FeedbackRelease.DefaultVisibility
+	}
+
+	switch visibility {
+	case "", FEEDBACK_VISIBILITY_VISIBLE:
+		return true
+	case FEEDBACK_VISIBILITY_AFTER_DUE_DATE:
+		return (this.DueDate == nil) || (now >= *this.DueDate)
+	case FEEDBACK_VISIBILITY_AFTER_RELEASE:
+		return (this.FeedbackRelease != nil) && (this.FeedbackRelease.ReleaseDate != nil) && (now >= *this.FeedbackRelease.ReleaseDate)
+	default:
+		return false
+	}
+}
+
+// Get a copy of this grading info without the score and message of any questions students cannot see yet.
+// The total score will only include visible questions.
+// Also returns if any questions were hidden (if none were, this grading info is returned as-is).
+func (this *GradingInfo) Redact(assignment *Assignment, now timestamp.Timestamp) (*GradingInfo, bool) {
+	if this == nil {
+		return nil, false
+	}
+
+	hasHidden := false
+	for _, question := range this.Questions {
+		if !assignment.IsQuestionVisible(question, now) {
+			hasHidden = true
+			break
+		}
+	}
+
+	if !hasHidden {
+		return this, false
+	}
+
+	info := *this
+	info.Score = 0.0
+	info.Questions = make([]*GradedQuestion, 0, len(this.Questions))
+
+	for _, question := range this.Questions {
+		question, _ := question.Redact(assignment, now)
+
+		info.Score += question.Score
+		info.Questions = append(info.Questions, question)
+	}
+
+	return &info, true
+}
+
+// Get a copy of this question without its score and message if students cannot see it yet.
+// Also returns if the question was hidden (if not, this question is returned as-is).
+func (this *GradedQuestion) Redact(assignment *Assignment, now timestamp.Timestamp) (*GradedQuestion, bool) {
+	if assignment.IsQuestionVisible(this, now) {
+		return this, false
+	}
+
+	question := *this
+	question.Score = 0.0
+	question.HardFail = false
+	question.Skipped = false
+	question.Message = ""
+	question.Hidden = true
+
+	return &question, true
+}
+
+// Get a copy of this grading result without any feedback students cannot see yet.
+// If any questions are hidden, then the grader's output (which may describe the hidden questions) is also removed.
+func (this *GradingResult) Redact(assignment *Assignment, now timestamp.Timestamp) *GradingResult {
+	if this == nil {
+		return nil
+	}
+
+	info, hasHidden := this.Info.Redact(assignment, now)
+	if !hasHidden {
+		return this
+	}
+
+	return &GradingResult{
+		Info:           info,
+		InputFilesGZip: this.InputFilesGZip,
+	}
+}
@@ -0,0 +1,114 @@
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/edulinq/autograder/internal/timestamp"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestIsQuestionVisible(test *testing.T) {
+	past := timestamp.FromMSecs(100)
+	future := timestamp.FromMSecs(300)
+	now := timestamp.FromMSecs(200)
+
+	testCases := []struct {
+		visibility      FeedbackVisibility
+		dueDate         *timestamp.Timestamp
+		feedbackRelease *FeedbackReleaseInfo
+		expected        bool
+	}{
+		// Visible.
+		{"", nil, nil, true},
+		{FEEDBACK_VISIBILITY_VISIBLE, nil, nil, true},
+		{FEEDBACK_VISIBILITY_VISIBLE, &future, &FeedbackReleaseInfo{DefaultVisibility: FEEDBACK_VISIBILITY_AFTER_RELEASE}, true},
+
+		// After due date.
+		{FEEDBACK_VISIBILITY_AFTER_DUE_DATE, nil, nil, true},
+		{FEEDBACK_VISIBILITY_AFTER_DUE_DATE, &past, nil, true},
+		{FEEDBACK_VISIBILITY_AFTER_DUE_DATE, &future, nil, false},
+
+		// After release.
+		{FEEDBACK_VISIBILITY_AFTER_RELEASE, nil, nil, false},
+		{FEEDBACK_VISIBILITY_AFTER_RELEASE, nil, &FeedbackReleaseInfo{}, false},
+		{FEEDBACK_VISIBILITY_AFTER_RELEASE, nil, &FeedbackReleaseInfo{ReleaseDate: &past}, true},
+		{FEEDBACK_VISIBILITY_AFTER_RELEASE, nil, &FeedbackReleaseInfo{ReleaseDate: &future}, false},
+
+		// Assignment default.
+		{"", &future, &FeedbackReleaseInfo{DefaultVisibility: FEEDBACK_VISIBILITY_AFTER_DUE_DATE}, false},
+		{"", &past, &FeedbackReleaseInfo{DefaultVisibility: FEEDBACK_VISIBILITY_AFTER_DUE_DATE}, true},
+		{"", nil, &FeedbackReleaseInfo{DefaultVisibility: FEEDBACK_VISIBILITY_AFTER_RELEASE}, false},
+		{"", nil, &FeedbackReleaseInfo{}, true},
+
+		// Unknown.
+		{"zzz", nil, nil, false},
+	}
+
+	for i, testCase := range testCases {
+		assignment := &Assignment{DueDate: testCase.dueDate, FeedbackRelease: testCase.feedbackRelease}
+		question := &GradedQuestion{Visibility: testCase.visibility}
+
+		actual := assignment.IsQuestionVisible(question, now)
+		if testCase.expected != actual {
+			test.Errorf("Case %d: Unexpected visibility. Expected: '%v', Actual: '%v'.", i, testCase.expected, actual)
+			continue
+		}
+	}
+}
+
+func TestGradingInfoRedact(test *testing.T) {
+	assignment := &Assignment{}
+	info := &GradingInfo{
+		Name:      "HW0",
+		MaxPoints: 3,
+		Score:     3,
+		Questions: []*GradedQuestion{
+			&GradedQuestion{Name: "Q1", MaxPoints: 1, Score: 1, Message: "Visible."},
+			&GradedQuestion{Name: "Q2", MaxPoints: 2, Score: 2, Message: "Hidden.", Visibility: FEEDBACK_VISIBILITY_AFTER_RELEASE},
+		},
+	}
+
+	expected := &GradingInfo{
+		Name:      "HW0",
+		MaxPoints: 3,
+		Score:     1,
+		Questions: []*GradedQuestion{
+			&GradedQuestion{Name: "Q1", MaxPoints: 1, Score: 1, Message: "Visible."},
+			&GradedQuestion{Name: "Q2", MaxPoints: 2, Score: 0, Hidden: true, Visibility: FEEDBACK_VISIBILITY_AFTER_RELEASE},
+		},
+	}
+
+	original := util.MustToJSONIndent(info)
+
+	redacted, hasHidden := info.Redact(assignment, timestamp.Now())
+	if !hasHidden {
+		test.Fatalf("Did not find hidden questions.")
+	}
+
+	if util.MustToJSONIndent(expected) != util.MustToJSONIndent(redacted) {
+		test.Fatalf("Unexpected redacted info. Expected: '%s', Actual: '%s'.", util.MustToJSONIndent(expected), util.MustToJSONIndent(redacted))
+	}
+
+	if original != util.MustToJSONIndent(info) {
+		test.Fatalf("Original info was modified. Expected: '%s', Actual: '%s'.", original, util.MustToJSONIndent(info))
+	}
+
+	report := redacted.Report()
+	if strings.Contains(report, "Hidden.") || !strings.Contains(report, "Q2: ? / 2") {
+		test.Fatalf("Report does not hide question: '%s'.", report)
+	}
+
+	// Once released, nothing is hidden.
+	releaseDate := timestamp.Zero()
+	assignment.FeedbackRelease = &FeedbackReleaseInfo{ReleaseDate: &releaseDate}
+
+	redacted, hasHidden = info.Redact(assignment, timestamp.Now())
+	if hasHidden {
+		test.Fatalf("Found hidden questions after release.")
+	}
+
+	if info != redacted {
+		test.Fatalf("Info without hidden questions was copied.")
+	}
+}
@@ -25,6 +25,7 @@ type GradingInfo struct {
 	AssignmentID   string               `json:"assignment-id"`
 	User           string               `json:"user"`
 	ProxyUser      string               `json:"proxy-user,omitempty"`
+	GroupID        string               `json:"group-id,omitempty"`
 	Message        string               `json:"message"`
 	MaxPoints      float64              `json:"max_points"`
 	Score          float64              `json:"score"`
@@ -40,6 +41,9 @@ type GradingInfo struct {
 	Prologue         string              `json:"prologue,omitempty"`
 	Epilogue         string              `json:"epilogue,omitempty"`
 
+	// Set when the assignment has a rubric that has not been fully scored for this submission.
+	RubricIncomplete bool `json:"rubric-incomplete,omitempty"`
+
 	// Additional pass-through information that the grader can use.
 
 	AdditionalInfo map[string]any `json:"additional-info"`
@@ -54,6 +58,12 @@ type GradedQuestion struct {
 	Message          string              `json:"message"`
 	GradingStartTime timestamp.Timestamp `json:"grading_start_time"`
 	GradingEndTime   timestamp.Timestamp `json:"grading_end_time"`
+
+	// When students can see this question's score and message.
+	Visibility FeedbackVisibility `json:"visibility,omitempty"`
+
+	// Set when this question's score and message were removed because they are not visible yet.
+	Hidden bool `json:"hidden,omitempty"`
 }
 
 func (this *GradingResult) HasTextOutput() bool {
@@ -69,6 +79,8 @@ func (this GradingInfo) ToScoringInfo() *ScoringInfo {
 		ID:                      this.ID,
 		SubmissionTime:          this.GradingStartTime,
 		RawScore:                this.Score,
+		RubricIncomplete:        this.RubricIncomplete,
+		GroupID:                 this.GroupID,
 		AutograderStructVersion: SCORING_INFO_STRUCT_VERSION,
 	}
 }
@@ -111,10 +123,12 @@ func (this GradingInfo) Report() string {
 
 	totalScore := 0.0
 	maxScore := 0.0
+	hasHidden := false
 
 	for _, question := range this.Questions {
 		totalScore += question.Score
 		maxScore += question.MaxPoints
+		hasHidden = hasHidden || question.Hidden
 
 		builder.WriteString(fmt.Sprintf("%s", question.Report()))
 	}
@@ -122,6 +136,10 @@ func (this GradingInfo) Report() string {
 	builder.WriteString("\n")
 	builder.WriteString(fmt.Sprintf("Total: %s / %s", util.FloatToStr(totalScore), util.FloatToStr(maxScore)))
 
+	if hasHidden {
+		builder.WriteString(" (hidden questions are not included)")
+	}
+
 	return builder.String()
 }
 
@@ -141,9 +159,30 @@ func (this *GradingInfo) ComputePoints() {
 	}
 }
 
+// Get the question with the given name (or nil if there is no such question).
+func (this *GradingInfo) GetQuestion(name string) *GradedQuestion {
+	if this == nil {
+		return nil
+	}
+
+	for _, question := range this.Questions {
+		if question.Name == name {
+			return question
+		}
+	}
+
+	return nil
+}
+
 func (this GradedQuestion) Report() string {
 	var builder strings.Builder
 
+	if this.Hidden {
+		builder.WriteString(fmt.Sprintf("%s: ? / %s\n", this.Name, util.FloatToStr(this.MaxPoints)))
+		builder.WriteString("    Feedback for this question has not been released yet.\n")
+		return builder.String()
+	}
+
 	builder.WriteString(fmt.Sprintf("%s: %s / %s\n", this.Name, util.FloatToStr(this.Score), util.FloatToStr(this.MaxPoints)))
 
 	if this.Message != "" {
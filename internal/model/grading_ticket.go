@@ -0,0 +1,80 @@
+package model
+
+import (
+	"github.com/edulinq/autograder/internal/timestamp"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+// The state of a submission that has been placed in the grading queue.
+type GradingTicketStatus string
+
+const (
+	GradingTicketStatusUnknown GradingTicketStatus = ""
+	GradingTicketStatusQueued                      = "queued"
+	GradingTicketStatusRunning                     = "running"
+	GradingTicketStatusDone                        = "done"
+)
+
+var gradingTicketStatusToString = map[GradingTicketStatus]string{
+	GradingTicketStatusUnknown: string(GradingTicketStatusUnknown),
+	GradingTicketStatusQueued:  string(GradingTicketStatusQueued),
+	GradingTicketStatusRunning: string(GradingTicketStatusRunning),
+	GradingTicketStatusDone:    string(GradingTicketStatusDone),
+}
+
+var stringToGradingTicketStatus = map[string]GradingTicketStatus{
+	string(GradingTicketStatusUnknown): GradingTicketStatusUnknown,
+	string(GradingTicketStatusQueued):  GradingTicketStatusQueued,
+	string(GradingTicketStatusRunning): GradingTicketStatusRunning,
+	string(GradingTicketStatusDone):    GradingTicketStatusDone,
+}
+
+// A submission that was queued for grading (instead of being graded while the submitter waits).
+// The submitted files are held in the ticket until grading is complete,
+// so a queued ticket can be graded even after a server restart.
+type GradingTicket struct {
+	ID           string               `json:"id"`
+	CourseID     string               `json:"course-id"`
+	AssignmentID string               `json:"assignment-id"`
+	User         string               `json:"user"`
+	Message      string               `json:"message"`
+	AllowLate    bool                 `json:"allow-late"`
+	Status       GradingTicketStatus  `json:"status"`
+	QueueTime    timestamp.Timestamp  `json:"queue-time"`
+	StartTime    *timestamp.Timestamp `json:"start-time,omitempty"`
+	EndTime      *timestamp.Timestamp `json:"end-time,omitempty"`
+
+	InputFilesGZip map[string][]byte `json:"input-files-gzip,omitempty"`
+
+	// Only set once the ticket is done.
+	Result *GradingTicketResult `json:"result,omitempty"`
+}
+
+// The outcome of grading a ticket.
+// When none of the fields are set, the grading failed internally.
+type GradingTicketResult struct {
+	GradingSuccess bool   `json:"grading-success,omitempty"`
+	SubmissionID   string `json:"submission-id,omitempty"`
+	Rejected       bool   `json:"rejected,omitempty"`
+	RejectReason   string `json:"reject-reason,omitempty"`
+	SoftError      string `json:"soft-error,omitempty"`
+	Stdout         string `json:"stdout,omitempty"`
+	Stderr         string `json:"stderr,omitempty"`
+}
+
+func (this *GradingTicket) IsDone() bool {
+	return this.Status == GradingTicketStatusDone
+}
+
+func (this GradingTicketStatus) MarshalJSON() ([]byte, error) {
+	return util.MarshalEnum(this, gradingTicketStatusToString)
+}
+
+func (this *GradingTicketStatus) UnmarshalJSON(data []byte) error {
+	value, err := util.UnmarshalEnum(data, stringToGradingTicketStatus, true)
+	if err == nil {
+		*this = *value
+	}
+
+	return err
+}
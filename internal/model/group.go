@@ -0,0 +1,63 @@
+package model
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/edulinq/autograder/internal/timestamp"
+)
+
+// Settings for assignments that are submitted by groups (teams) of students.
+type GroupOptions struct {
+	// The maximum number of students in a group.
+	MaxSize int `json:"max-size"`
+
+	// Allow students to create, join, and leave groups themselves.
+	// Students can only join a group after being invited by one of its members.
+	// Otherwise, only graders can manage groups.
+	AllowStudentFormed bool `json:"allow-student-formed,omitempty"`
+}
+
+// A group of students that submit together for an assignment.
+// Every submission from a member is credited to all members.
+type AssignmentGroup struct {
+	ID           string              `json:"id"`
+	CourseID     string              `json:"course-id"`
+	AssignmentID string              `json:"assignment-id"`
+	Members      []string            `json:"members"`
+	CreatedBy    string              `json:"created-by"`
+	CreationTime timestamp.Timestamp `json:"creation-time"`
+
+	// Students that have been invited to join the group by a member (see GroupOptions.AllowStudentFormed).
+	Invites []string `json:"invites,omitempty"`
+}
+
+func (this *GroupOptions) Validate() error {
+	if this.MaxSize < 1 {
+		return fmt.Errorf("Max group size must be positive, found: %d.", this.MaxSize)
+	}
+
+	return nil
+}
+
+func (this *AssignmentGroup) HasMember(email string) bool {
+	return slices.Contains(this.Members, email)
+}
+
+func (this *AssignmentGroup) HasInvite(email string) bool {
+	return slices.Contains(this.Invites, email)
+}
+
+func (this *AssignmentGroup) Equals(other *AssignmentGroup) bool {
+	if (this == nil) || (other == nil) {
+		return (this == nil) && (other == nil)
+	}
+
+	return (this.ID == other.ID) &&
+		(this.CourseID == other.CourseID) &&
+		(this.AssignmentID == other.AssignmentID) &&
+		slices.Equal(this.Members, other.Members) &&
+		slices.Equal(this.Invites, other.Invites) &&
+		(this.CreatedBy == other.CreatedBy) &&
+		(this.CreationTime == other.CreationTime)
+}
@@ -0,0 +1,85 @@
+package model
+
+import (
+	"fmt"
+
+	"github.com/edulinq/autograder/internal/timestamp"
+)
+
+const (
+	LOGIN_THROTTLE_KEY_PREFIX_EMAIL = "email::"
+	LOGIN_THROTTLE_KEY_PREFIX_IP    = "ip::"
+)
+
+// Failed login attempts for a single email or IP address.
+// Once the number of failures reaches a threshold, further logins are blocked until LockedUntil,
+// with the lockout doubling in length for each additional failure (up to a maximum).
+type LoginThrottle struct {
+	Key         string              `json:"key"`
+	Failures    int                 `json:"failures"`
+	LastFailure timestamp.Timestamp `json:"last-failure"`
+	LockedUntil timestamp.Timestamp `json:"locked-until"`
+}
+
+func LoginThrottleKeyForEmail(email string) string {
+	return LOGIN_THROTTLE_KEY_PREFIX_EMAIL + email
+}
+
+func LoginThrottleKeyForIP(ip string) string {
+	return LOGIN_THROTTLE_KEY_PREFIX_IP + ip
+}
+
+func (this *LoginThrottle) Validate() error {
+	if this == nil {
+		return fmt.Errorf("Login throttle is nil.")
+	}
+
+	if this.Key == "" {
+		return fmt.Errorf("Login throttle has an empty key.")
+	}
+
+	if this.Failures < 0 {
+		return fmt.Errorf("Login throttle ('%s') has a negative number of failures.", this.Key)
+	}
+
+	return nil
+}
+
+func (this *LoginThrottle) IsLocked(now timestamp.Timestamp) bool {
+	return (this != nil) && (this.LockedUntil > now)
+}
+
+// Check if the failures in this throttle are old enough to be forgotten.
+// Locked throttles are never stale.
+func (this *LoginThrottle) IsStale(now timestamp.Timestamp, windowSecs int) bool {
+	if this.IsLocked(now) {
+		return false
+	}
+
+	return (this.LastFailure + timestamp.FromMSecs(int64(windowSecs)*1000)) <= now
+}
+
+// Record a failed login.
+// Once the number of failures reaches maxFailures, the throttle will be locked for baseSecs,
+// and each additional failure will double the lockout (up to maxSecs).
+// Returns true if this failure caused a lockout.
+func (this *LoginThrottle) RecordFailure(now timestamp.Timestamp, maxFailures int, baseSecs int, maxSecs int) bool {
+	this.Failures++
+	this.LastFailure = now
+
+	if (maxFailures <= 0) || (this.Failures < maxFailures) {
+		return false
+	}
+
+	// Compute the lockout in a way that cannot overflow.
+	lockoutSecs := int64(baseSecs)
+	for i := maxFailures; (i < this.Failures) && (lockoutSecs < int64(maxSecs)); i++ {
+		lockoutSecs *= 2
+	}
+
+	lockoutSecs = min(lockoutSecs, int64(maxSecs))
+
+	this.LockedUntil = now + timestamp.FromMSecs(lockoutSecs*1000)
+
+	return true
+}
@@ -0,0 +1,84 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/edulinq/autograder/internal/timestamp"
+)
+
+func TestLoginThrottleRecordFailure(test *testing.T) {
+	now := timestamp.FromMSecs(1000 * 1000)
+
+	// Lockout seconds after each failure (zero means no lockout).
+	// Three failures are allowed, the first lockout is 10 seconds, and the max is 60 seconds.
+	expectedLockouts := []int64{0, 0, 10, 20, 40, 60, 60}
+
+	throttle := LoginThrottle{Key: "test"}
+
+	for i, expected := range expectedLockouts {
+		locked := throttle.RecordFailure(now, 3, 10, 60)
+
+		if locked != (expected > 0) {
+			test.Errorf("Case %d: Unexpected lock result. Expected: %v, Actual: %v.", i, (expected > 0), locked)
+			continue
+		}
+
+		if throttle.Failures != (i + 1) {
+			test.Errorf("Case %d: Unexpected failure count. Expected: %d, Actual: %d.", i, (i + 1), throttle.Failures)
+			continue
+		}
+
+		if !locked {
+			continue
+		}
+
+		actual := (throttle.LockedUntil - now).ToMSecs() / 1000
+		if expected != actual {
+			test.Errorf("Case %d: Unexpected lockout. Expected: %d, Actual: %d.", i, expected, actual)
+			continue
+		}
+
+		if !throttle.IsLocked(now) {
+			test.Errorf("Case %d: Throttle is not locked.", i)
+			continue
+		}
+	}
+}
+
+func TestLoginThrottleRecordFailureDisabled(test *testing.T) {
+	throttle := LoginThrottle{Key: "test"}
+
+	for i := 0; i < 10; i++ {
+		if throttle.RecordFailure(timestamp.Now(), 0, 10, 60) {
+			test.Fatalf("Case %d: Throttle was locked when throttling is disabled.", i)
+		}
+	}
+}
+
+func TestLoginThrottleIsStale(test *testing.T) {
+	now := timestamp.FromMSecs(1000 * 1000)
+
+	testCases := []struct {
+		lastFailureSecs int64
+		lockedUntilSecs int64
+		expected        bool
+	}{
+		{990, 0, false},
+		{940, 0, true},
+		{950, 0, true},
+		{900, 1100, false},
+	}
+
+	for i, testCase := range testCases {
+		throttle := LoginThrottle{
+			Key:         "test",
+			LastFailure: timestamp.FromMSecs(testCase.lastFailureSecs * 1000),
+			LockedUntil: timestamp.FromMSecs(testCase.lockedUntilSecs * 1000),
+		}
+
+		actual := throttle.IsStale(now, 50)
+		if testCase.expected != actual {
+			test.Errorf("Case %d: Unexpected result. Expected: %v, Actual: %v.", i, testCase.expected, actual)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package model
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/edulinq/autograder/internal/config"
+	"github.com/edulinq/autograder/internal/email"
+	"github.com/edulinq/autograder/internal/timestamp"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+type RegradeStatus string
+
+const (
+	REGRADE_STATUS_OPEN     RegradeStatus = "open"
+	REGRADE_STATUS_RESOLVED RegradeStatus = "resolved"
+)
+
+var regradeStatuses = []RegradeStatus{
+	REGRADE_STATUS_OPEN,
+	REGRADE_STATUS_RESOLVED,
+}
+
+// A student's dispute of the score for a single question on one of their submissions.
+// Once a grader resolves a request, its score adjustment is applied to that question's score.
+type RegradeRequest struct {
+	ID           string `json:"id"`
+	CourseID     string `json:"course-id"`
+	AssignmentID string `json:"assignment-id"`
+	SubmissionID string `json:"submission-id"`
+	User         string `json:"user"`
+
+	// The name of the disputed question (which may be a rubric criterion).
+	Question      string              `json:"question"`
+	Justification string              `json:"justification"`
+	CreateTime    timestamp.Timestamp `json:"create-time"`
+
+	Status RegradeStatus `json:"status"`
+
+	// The fields below are only set once the request is resolved.
+	// The score adjustment is the points added to (or removed from) the question's score.
+	ScoreAdjustment float64              `json:"score-adjustment,omitempty"`
+	Reply           string               `json:"reply,omitempty"`
+	ResolvedBy      string               `json:"resolved-by,omitempty"`
+	ResolveTime     *timestamp.Timestamp `json:"resolve-time,omitempty"`
+}
+
+func (this RegradeStatus) Validate() error {
+	if !slices.Contains(regradeStatuses, this) {
+		return fmt.Errorf("Unknown regrade status: '%s'.", string(this))
+	}
+
+	return nil
+}
+
+func (this *RegradeRequest) Validate() error {
+	if this.ID == "" {
+		return fmt.Errorf("Regrade request is missing an ID.")
+	}
+
+	if this.SubmissionID == "" {
+		return fmt.Errorf("Regrade request '%s' is missing a submission ID.", this.ID)
+	}
+
+	if this.User == "" {
+		return fmt.Errorf("Regrade request '%s' is missing a user.", this.ID)
+	}
+
+	if this.Question == "" {
+		return fmt.Errorf("Regrade request '%s' is missing a question.", this.ID)
+	}
+
+	if this.Justification == "" {
+		return fmt.Errorf("Regrade request '%s' is missing a justification.", this.ID)
+	}
+
+	err := this.Status.Validate()
+	if err != nil {
+		return fmt.Errorf("Regrade request '%s' has an invalid status: '%w'.", this.ID, err)
+	}
+
+	if this.IsOpen() {
+		if (this.ScoreAdjustment != 0) || (this.ResolvedBy != "") || (this.ResolveTime != nil) {
+			return fmt.Errorf("Open regrade request '%s' cannot have a resolution.", this.ID)
+		}
+	} else {
+		if (this.ResolvedBy == "") || (this.ResolveTime == nil) {
+			return fmt.Errorf("Resolved regrade request '%s' is missing who resolved it and when.", this.ID)
+		}
+	}
+
+	return nil
+}
+
+func (this *RegradeRequest) IsOpen() bool {
+	return this.Status == REGRADE_STATUS_OPEN
+}
+
+// Apply the score adjustments from resolved regrade requests for this submission.
+// Requests for other submissions or for questions that are not in this grading info are ignored.
+func (this *GradingInfo) ApplyRegradeAdjustments(requests []*RegradeRequest) {
+	if this == nil {
+		return
+	}
+
+	for _, request := range requests {
+		if (request == nil) || request.IsOpen() || (request.SubmissionID != this.ID) || (request.ScoreAdjustment == 0) {
+			continue
+		}
+
+		question := this.GetQuestion(request.Question)
+		if question == nil {
+			continue
+		}
+
+		question.Score += request.ScoreAdjustment
+		this.Score += request.ScoreAdjustment
+	}
+}
+
+// Get the email that notifies a student that their regrade request has been resolved
+// (or nil if the request is still open).
+func (this *RegradeRequest) GetResolutionEmail(assignmentName string) *email.Message {
+	if (this == nil) || this.IsOpen() {
+		return nil
+	}
+
+	body := fmt.Sprintf(baseRegradeResolvedBody, this.Question, assignmentName, this.CourseID,
+		util.FloatToStr(this.ScoreAdjustment), this.Reply)
+
+	return &email.Message{
+		MessageRecipients: email.MessageRecipients{
+			To: []string{this.User},
+		},
+		MessageContent: email.MessageContent{
+			Subject: fmt.Sprintf("Autograder %s -- Regrade Request Resolved", config.NAME.Get()),
+			Body:    body,
+			HTML:    false,
+		},
+	}
+}
+
+var baseRegradeResolvedBody string = `Hello,
+
+Your regrade request for question '%s' on assignment '%s' (course '%s') has been resolved.
+
+Score Adjustment: %s
+
+Reply:
+%s
+`
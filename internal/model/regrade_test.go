@@ -0,0 +1,135 @@
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/edulinq/autograder/internal/timestamp"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestRegradeRequestValidate(test *testing.T) {
+	resolveTime := timestamp.FromMSecs(100)
+
+	testCases := []struct {
+		request        *RegradeRequest
+		errorSubstring string
+	}{
+		{&RegradeRequest{ID: "a", SubmissionID: "s", User: "u", Question: "Q1", Justification: "j", Status: REGRADE_STATUS_OPEN}, ""},
+		{&RegradeRequest{ID: "a", SubmissionID: "s", User: "u", Question: "Q1", Justification: "j", Status: REGRADE_STATUS_RESOLVED, ResolvedBy: "g", ResolveTime: &resolveTime}, ""},
+		{&RegradeRequest{ID: "a", SubmissionID: "s", User: "u", Question: "Q1", Justification: "j", Status: REGRADE_STATUS_RESOLVED, ScoreAdjustment: -1, ResolvedBy: "g", ResolveTime: &resolveTime}, ""},
+
+		{&RegradeRequest{SubmissionID: "s", User: "u", Question: "Q1", Justification: "j", Status: REGRADE_STATUS_OPEN}, "missing an ID"},
+		{&RegradeRequest{ID: "a", User: "u", Question: "Q1", Justification: "j", Status: REGRADE_STATUS_OPEN}, "missing a submission ID"},
+		{&RegradeRequest{ID: "a", SubmissionID: "s", Question: "Q1", Justification: "j", Status: REGRADE_STATUS_OPEN}, "missing a user"},
+		{&RegradeRequest{ID: "a", SubmissionID: "s", User: "u", Justification: "j", Status: REGRADE_STATUS_OPEN}, "missing a question"},
+		{&RegradeRequest{ID: "a", SubmissionID: "s", User: "u", Question: "Q1", Status: REGRADE_STATUS_OPEN}, "missing a justification"},
+		{&RegradeRequest{ID: "a", SubmissionID: "s", User: "u", Question: "Q1", Justification: "j"}, "invalid status"},
+		{&RegradeRequest{ID: "a", SubmissionID: "s", User: "u", Question: "Q1", Justification: "j", Status: "zzz"}, "invalid status"},
+		{&RegradeRequest{ID: "a", SubmissionID: "s", User: "u", Question: "Q1", Justification: "j", Status: REGRADE_STATUS_OPEN, ScoreAdjustment: 1}, "cannot have a resolution"},
+		{&RegradeRequest{ID: "a", SubmissionID: "s", User: "u", Question: "Q1", Justification: "j", Status: REGRADE_STATUS_RESOLVED}, "missing who resolved it"},
+	}
+
+	for i, testCase := range testCases {
+		err := testCase.request.Validate()
+		if err != nil {
+			if testCase.errorSubstring == "" {
+				test.Errorf("Case %d: Unexpected error: '%v'.", i, err)
+			} else if !strings.Contains(err.Error(), testCase.errorSubstring) {
+				test.Errorf("Case %d: Error does not contain expected substring. Expected: '%s', Actual: '%v'.", i, testCase.errorSubstring, err)
+			}
+
+			continue
+		}
+
+		if testCase.errorSubstring != "" {
+			test.Errorf("Case %d: Did not get expected error containing '%s'.", i, testCase.errorSubstring)
+			continue
+		}
+	}
+}
+
+func TestGradingInfoApplyRegradeAdjustments(test *testing.T) {
+	testCases := []struct {
+		requests        []*RegradeRequest
+		expectedScore   float64
+		expectedQ1Score float64
+	}{
+		{nil, 1, 1},
+		{[]*RegradeRequest{&RegradeRequest{SubmissionID: "s", Question: "Q1", Status: REGRADE_STATUS_RESOLVED, ScoreAdjustment: -0.5}}, 0.5, 0.5},
+		{[]*RegradeRequest{&RegradeRequest{SubmissionID: "s", Question: "Q2", Status: REGRADE_STATUS_RESOLVED, ScoreAdjustment: 1}}, 2, 1},
+		{
+			[]*RegradeRequest{
+				&RegradeRequest{SubmissionID: "s", Question: "Q1", Status: REGRADE_STATUS_RESOLVED, ScoreAdjustment: -0.5},
+				&RegradeRequest{SubmissionID: "s", Question: "Q2", Status: REGRADE_STATUS_RESOLVED, ScoreAdjustment: 1},
+			},
+			1.5, 0.5,
+		},
+
+		// Ignored requests.
+		{[]*RegradeRequest{&RegradeRequest{SubmissionID: "s", Question: "Q1", Status: REGRADE_STATUS_OPEN}}, 1, 1},
+		{[]*RegradeRequest{&RegradeRequest{SubmissionID: "zzz", Question: "Q1", Status: REGRADE_STATUS_RESOLVED, ScoreAdjustment: -1}}, 1, 1},
+		{[]*RegradeRequest{&RegradeRequest{SubmissionID: "s", Question: "zzz", Status: REGRADE_STATUS_RESOLVED, ScoreAdjustment: -1}}, 1, 1},
+		{[]*RegradeRequest{nil}, 1, 1},
+	}
+
+	for i, testCase := range testCases {
+		gradingInfo := &GradingInfo{
+			ID: "s",
+			Questions: []*GradedQuestion{
+				&GradedQuestion{Name: "Q1", Score: 1, MaxPoints: 1},
+				&GradedQuestion{Name: "Q2", Score: 0, MaxPoints: 1},
+			},
+			Score:     1,
+			MaxPoints: 2,
+		}
+
+		gradingInfo.ApplyRegradeAdjustments(testCase.requests)
+
+		if (testCase.expectedScore != gradingInfo.Score) || (testCase.expectedQ1Score != gradingInfo.GetQuestion("Q1").Score) {
+			test.Errorf("Case %d: Unexpected grading info. Expected: (%v, %v), Actual: '%s'.",
+				i, testCase.expectedScore, testCase.expectedQ1Score, util.MustToJSONIndent(gradingInfo))
+			continue
+		}
+	}
+}
+
+func TestRegradeRequestGetResolutionEmail(test *testing.T) {
+	resolveTime := timestamp.FromMSecs(100)
+
+	request := &RegradeRequest{
+		ID:              "a",
+		CourseID:        "course101",
+		SubmissionID:    "s",
+		User:            "course-student@test.edulinq.org",
+		Question:        "Q1",
+		Justification:   "j",
+		Status:          REGRADE_STATUS_OPEN,
+		ScoreAdjustment: 0.5,
+		Reply:           "You were right.",
+	}
+
+	message := request.GetResolutionEmail("Homework 0")
+	if message != nil {
+		test.Fatalf("Got an email for an open request: '%s'.", util.MustToJSONIndent(message))
+	}
+
+	request.Status = REGRADE_STATUS_RESOLVED
+	request.ResolvedBy = "course-grader@test.edulinq.org"
+	request.ResolveTime = &resolveTime
+
+	message = request.GetResolutionEmail("Homework 0")
+	if message == nil {
+		test.Fatalf("Did not get an email for a resolved request.")
+	}
+
+	if (len(message.To) != 1) || (message.To[0] != request.User) {
+		test.Fatalf("Unexpected recipients: '%v'.", message.To)
+	}
+
+	for _, expected := range []string{"'Q1'", "'Homework 0'", "'course101'", "Score Adjustment: 0.5", "You were right."} {
+		if !strings.Contains(message.Body, expected) {
+			test.Errorf("Email body does not contain '%s': '%s'.", expected, message.Body)
+		}
+	}
+}
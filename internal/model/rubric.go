@@ -0,0 +1,191 @@
+package model
+
+import (
+	"fmt"
+
+	"github.com/edulinq/autograder/internal/timestamp"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+// A set of hand-graded criteria that are scored on top of an assignment's autograder results.
+type Rubric struct {
+	Criteria []*RubricCriterion `json:"criteria"`
+}
+
+type RubricCriterion struct {
+	// Criterion names must be unique within a rubric (and should not match any autograder question names).
+	Name        string  `json:"name"`
+	MaxPoints   float64 `json:"max-points"`
+	Description string  `json:"description,omitempty"`
+
+	// When students can see the score and comment for this criterion (see FeedbackVisibility).
+	Visibility FeedbackVisibility `json:"visibility,omitempty"`
+}
+
+// A grader's scores for a single submission against an assignment's rubric.
+// Criteria that have not been scored yet are missing from the scores.
+type RubricGrade struct {
+	CourseID     string `json:"course-id"`
+	AssignmentID string `json:"assignment-id"`
+	SubmissionID string `json:"submission-id"`
+	User         string `json:"user"`
+
+	// Keyed by criterion name.
+	Scores map[string]*RubricCriterionScore `json:"scores"`
+
+	GradedBy   string              `json:"graded-by"`
+	UpdateTime timestamp.Timestamp `json:"update-time"`
+}
+
+type RubricCriterionScore struct {
+	Score   float64 `json:"score"`
+	Comment string  `json:"comment,omitempty"`
+}
+
+func (this *Rubric) Validate() error {
+	if len(this.Criteria) == 0 {
+		return fmt.Errorf("Rubric has no criteria.")
+	}
+
+	names := make(map[string]bool, len(this.Criteria))
+	for i, criterion := range this.Criteria {
+		if criterion == nil {
+			return fmt.Errorf("Rubric criterion at index %d is nil.", i)
+		}
+
+		if criterion.Name == "" {
+			return fmt.Errorf("Rubric criterion at index %d is missing a name.", i)
+		}
+
+		if names[criterion.Name] {
+			return fmt.Errorf("Rubric has a duplicate criterion: '%s'.", criterion.Name)
+		}
+
+		names[criterion.Name] = true
+
+		if criterion.MaxPoints <= 0 {
+			return fmt.Errorf("Rubric criterion '%s' must have positive max points, found: %s.", criterion.Name, util.FloatToStr(criterion.MaxPoints))
+		}
+
+		err := criterion.Visibility.Validate()
+		if err != nil {
+			return fmt.Errorf("Rubric criterion '%s' has an invalid visibility: '%w'.", criterion.Name, err)
+		}
+	}
+
+	return nil
+}
+
+func (this *Rubric) GetCriterion(name string) *RubricCriterion {
+	if this == nil {
+		return nil
+	}
+
+	for _, criterion := range this.Criteria {
+		if criterion.Name == name {
+			return criterion
+		}
+	}
+
+	return nil
+}
+
+// Check that a grade only scores criteria in the given rubric (and within their point values).
+func (this *RubricGrade) Validate(rubric *Rubric) error {
+	if rubric == nil {
+		return fmt.Errorf("Assignment does not have a rubric.")
+	}
+
+	if this.SubmissionID == "" {
+		return fmt.Errorf("Rubric grade is missing a submission ID.")
+	}
+
+	for name, score := range this.Scores {
+		criterion := rubric.GetCriterion(name)
+		if criterion == nil {
+			return fmt.Errorf("Unknown rubric criterion: '%s'.", name)
+		}
+
+		if score == nil {
+			return fmt.Errorf("Rubric criterion '%s' has a nil score.", name)
+		}
+
+		if (score.Score < 0) || (score.Score > criterion.MaxPoints) {
+			return fmt.Errorf("Score for rubric criterion '%s' must be between 0 and %s, found: %s.",
+				name, util.FloatToStr(criterion.MaxPoints), util.FloatToStr(score.Score))
+		}
+	}
+
+	return nil
+}
+
+// A grade is complete when every criterion in the rubric has been scored.
+// A nil grade is never complete.
+func (this *RubricGrade) IsComplete(rubric *Rubric) bool {
+	if this == nil {
+		return false
+	}
+
+	for _, criterion := range rubric.Criteria {
+		if this.Scores[criterion.Name] == nil {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Merge a rubric grade (which may be nil if the submission has not been graded yet) into this grading info.
+// Each rubric criterion is added as a question (unscored criteria get zero points),
+// and the info's score and max points will include the rubric.
+// If any criterion is not scored, the info will be marked as incomplete.
+// Does nothing if there is no rubric.
+func (this *GradingInfo) ApplyRubricGrade(rubric *Rubric, grade *RubricGrade) {
+	if (this == nil) || (rubric == nil) {
+		return
+	}
+
+	for _, criterion := range rubric.Criteria {
+		question := &GradedQuestion{
+			Name:             criterion.Name,
+			MaxPoints:        criterion.MaxPoints,
+			GradingStartTime: this.GradingStartTime,
+			GradingEndTime:   this.GradingEndTime,
+			Visibility:       criterion.Visibility,
+		}
+
+		if grade != nil {
+			score := grade.Scores[criterion.Name]
+			if score != nil {
+				question.Score = score.Score
+				question.Message = score.Comment
+			}
+		}
+
+		this.Score += question.Score
+		this.MaxPoints += question.MaxPoints
+		this.Questions = append(this.Questions, question)
+	}
+
+	this.RubricIncomplete = !grade.IsComplete(rubric)
+}
+
+// Merge a rubric grade into the score of this history item (see GradingInfo.ApplyRubricGrade()).
+func (this *SubmissionHistoryItem) ApplyRubricGrade(rubric *Rubric, grade *RubricGrade) {
+	if (this == nil) || (rubric == nil) {
+		return
+	}
+
+	for _, criterion := range rubric.Criteria {
+		if grade != nil {
+			score := grade.Scores[criterion.Name]
+			if score != nil {
+				this.Score += score.Score
+			}
+		}
+
+		this.MaxPoints += criterion.MaxPoints
+	}
+
+	this.RubricIncomplete = !grade.IsComplete(rubric)
+}
@@ -0,0 +1,141 @@
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestRubricValidate(test *testing.T) {
+	testCases := []struct {
+		rubric         *Rubric
+		errorSubstring string
+	}{
+		{&Rubric{Criteria: []*RubricCriterion{&RubricCriterion{Name: "A", MaxPoints: 1}}}, ""},
+		{&Rubric{Criteria: []*RubricCriterion{&RubricCriterion{Name: "A", MaxPoints: 1, Visibility: FEEDBACK_VISIBILITY_AFTER_RELEASE}}}, ""},
+		{&Rubric{Criteria: []*RubricCriterion{&RubricCriterion{Name: "A", MaxPoints: 1}, &RubricCriterion{Name: "B", MaxPoints: 0.5}}}, ""},
+
+		{&Rubric{}, "no criteria"},
+		{&Rubric{Criteria: []*RubricCriterion{nil}}, "is nil"},
+		{&Rubric{Criteria: []*RubricCriterion{&RubricCriterion{MaxPoints: 1}}}, "missing a name"},
+		{&Rubric{Criteria: []*RubricCriterion{&RubricCriterion{Name: "A", MaxPoints: 1}, &RubricCriterion{Name: "A", MaxPoints: 1}}}, "duplicate criterion"},
+		{&Rubric{Criteria: []*RubricCriterion{&RubricCriterion{Name: "A"}}}, "positive max points"},
+		{&Rubric{Criteria: []*RubricCriterion{&RubricCriterion{Name: "A", MaxPoints: -1}}}, "positive max points"},
+		{&Rubric{Criteria: []*RubricCriterion{&RubricCriterion{Name: "A", MaxPoints: 1, Visibility: "zzz"}}}, "invalid visibility"},
+	}
+
+	for i, testCase := range testCases {
+		err := testCase.rubric.Validate()
+		if err != nil {
+			if testCase.errorSubstring == "" {
+				test.Errorf("Case %d: Unexpected error: '%v'.", i, err)
+			} else if !strings.Contains(err.Error(), testCase.errorSubstring) {
+				test.Errorf("Case %d: Error does not contain expected substring. Expected: '%s', Actual: '%v'.", i, testCase.errorSubstring, err)
+			}
+
+			continue
+		}
+
+		if testCase.errorSubstring != "" {
+			test.Errorf("Case %d: Did not get expected error containing '%s'.", i, testCase.errorSubstring)
+			continue
+		}
+	}
+}
+
+func TestRubricGradeValidate(test *testing.T) {
+	rubric := &Rubric{Criteria: []*RubricCriterion{&RubricCriterion{Name: "A", MaxPoints: 2}}}
+
+	testCases := []struct {
+		rubric         *Rubric
+		grade          *RubricGrade
+		errorSubstring string
+	}{
+		{rubric, &RubricGrade{SubmissionID: "a"}, ""},
+		{rubric, &RubricGrade{SubmissionID: "a", Scores: map[string]*RubricCriterionScore{"A": &RubricCriterionScore{Score: 0}}}, ""},
+		{rubric, &RubricGrade{SubmissionID: "a", Scores: map[string]*RubricCriterionScore{"A": &RubricCriterionScore{Score: 2}}}, ""},
+
+		{nil, &RubricGrade{SubmissionID: "a"}, "does not have a rubric"},
+		{rubric, &RubricGrade{}, "missing a submission ID"},
+		{rubric, &RubricGrade{SubmissionID: "a", Scores: map[string]*RubricCriterionScore{"B": &RubricCriterionScore{Score: 1}}}, "Unknown rubric criterion"},
+		{rubric, &RubricGrade{SubmissionID: "a", Scores: map[string]*RubricCriterionScore{"A": nil}}, "nil score"},
+		{rubric, &RubricGrade{SubmissionID: "a", Scores: map[string]*RubricCriterionScore{"A": &RubricCriterionScore{Score: -1}}}, "must be between"},
+		{rubric, &RubricGrade{SubmissionID: "a", Scores: map[string]*RubricCriterionScore{"A": &RubricCriterionScore{Score: 2.5}}}, "must be between"},
+	}
+
+	for i, testCase := range testCases {
+		err := testCase.grade.Validate(testCase.rubric)
+		if err != nil {
+			if testCase.errorSubstring == "" {
+				test.Errorf("Case %d: Unexpected error: '%v'.", i, err)
+			} else if !strings.Contains(err.Error(), testCase.errorSubstring) {
+				test.Errorf("Case %d: Error does not contain expected substring. Expected: '%s', Actual: '%v'.", i, testCase.errorSubstring, err)
+			}
+
+			continue
+		}
+
+		if testCase.errorSubstring != "" {
+			test.Errorf("Case %d: Did not get expected error containing '%s'.", i, testCase.errorSubstring)
+			continue
+		}
+	}
+}
+
+func TestGradingInfoApplyRubricGrade(test *testing.T) {
+	rubric := &Rubric{
+		Criteria: []*RubricCriterion{
+			&RubricCriterion{Name: "A", MaxPoints: 2},
+			&RubricCriterion{Name: "B", MaxPoints: 3, Visibility: FEEDBACK_VISIBILITY_AFTER_RELEASE},
+		},
+	}
+
+	testCases := []struct {
+		rubric             *Rubric
+		grade              *RubricGrade
+		expectedScore      float64
+		expectedMaxPoints  float64
+		expectedQuestions  int
+		expectedIncomplete bool
+	}{
+		{nil, nil, 1, 2, 1, false},
+		{rubric, nil, 1, 7, 3, true},
+		{rubric, &RubricGrade{Scores: map[string]*RubricCriterionScore{"A": &RubricCriterionScore{Score: 1.5}}}, 2.5, 7, 3, true},
+		{
+			rubric,
+			&RubricGrade{Scores: map[string]*RubricCriterionScore{
+				"A": &RubricCriterionScore{Score: 1.5},
+				"B": &RubricCriterionScore{Score: 3, Comment: "Nice."},
+			}},
+			5.5, 7, 3, false,
+		},
+	}
+
+	for i, testCase := range testCases {
+		gradingInfo := &GradingInfo{
+			Questions: []*GradedQuestion{&GradedQuestion{Name: "Q1", Score: 1, MaxPoints: 2}},
+			Score:     1,
+			MaxPoints: 2,
+		}
+
+		gradingInfo.ApplyRubricGrade(testCase.rubric, testCase.grade)
+
+		if (testCase.expectedScore != gradingInfo.Score) || (testCase.expectedMaxPoints != gradingInfo.MaxPoints) ||
+			(testCase.expectedQuestions != len(gradingInfo.Questions)) || (testCase.expectedIncomplete != gradingInfo.RubricIncomplete) {
+			test.Errorf("Case %d: Unexpected grading info. Expected: (%v, %v, %d, %v), Actual: '%s'.",
+				i, testCase.expectedScore, testCase.expectedMaxPoints, testCase.expectedQuestions, testCase.expectedIncomplete,
+				util.MustToJSONIndent(gradingInfo))
+			continue
+		}
+
+		if testCase.rubric == nil {
+			continue
+		}
+
+		if gradingInfo.Questions[2].Visibility != FEEDBACK_VISIBILITY_AFTER_RELEASE {
+			test.Errorf("Case %d: Rubric criterion visibility was not carried over. Actual: '%s'.", i, gradingInfo.Questions[2].Visibility)
+			continue
+		}
+	}
+}
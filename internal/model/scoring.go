@@ -17,6 +17,13 @@ type ScoringInfo struct {
 	NumDaysLate    int                 `json:"num-days-late"`
 	Reject         bool                `json:"reject"`
 
+	// The submission's rubric has not been fully scored yet (so the score is missing some points).
+	RubricIncomplete bool `json:"rubric-incomplete,omitempty"`
+
+	// The group that the submission was made by (for group assignments).
+	// Only used internally (to share scores between group members).
+	GroupID string `json:"-"`
+
 	// A distinct key so we can recognize this as an autograder object.
 	AutograderStructVersion string `json:"__autograder__version__"`
 
@@ -34,6 +41,8 @@ type ExternalScoringInfo struct {
 	UploadTime     timestamp.Timestamp `json:"upload-time"`
 	RawScore       float64             `json:"raw-score"`
 	Score          float64             `json:"score"`
+
+	RubricIncomplete bool `json:"rubric-incomplete,omitempty"`
 }
 
 func (this *ScoringInfo) Equal(other *ScoringInfo) bool {
@@ -49,6 +58,7 @@ func (this *ScoringInfo) Equal(other *ScoringInfo) bool {
 		this.LateDayUsage == other.LateDayUsage &&
 		this.NumDaysLate == other.NumDaysLate &&
 		this.Reject == other.Reject &&
+		this.RubricIncomplete == other.RubricIncomplete &&
 		this.AutograderStructVersion == other.AutograderStructVersion)
 }
 
@@ -65,5 +75,7 @@ func (this *ScoringInfo) ToExternal(email string, assignmentID string) *External
 		UploadTime:     this.UploadTime,
 		RawScore:       this.RawScore,
 		Score:          this.Score,
+
+		RubricIncomplete: this.RubricIncomplete,
 	}
 }
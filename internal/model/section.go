@@ -0,0 +1,112 @@
+package model
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// The prefix for course user references that target a section (e.g., "section:lab-01").
+const SECTION_REFERENCE_PREFIX = "section:"
+
+// Clean up a list of section names.
+// Names are trimmed and lowercased, and empty/duplicate names are removed.
+// A nil list will stay nil (since nil sections indicate no change when merging).
+func NormalizeSections(sections []string) []string {
+	if sections == nil {
+		return nil
+	}
+
+	results := make([]string, 0, len(sections))
+	for _, section := range sections {
+		section = strings.ToLower(strings.TrimSpace(section))
+		if section == "" {
+			continue
+		}
+
+		results = append(results, section)
+	}
+
+	slices.Sort(results)
+
+	return slices.Compact(results)
+}
+
+// Clean up the sections declared in a course config.
+// Section names are normalized (see NormalizeSections()), and emails are trimmed and deduplicated.
+func normalizeCourseSections(sections map[string][]string) (map[string][]string, error) {
+	if sections == nil {
+		return nil, nil
+	}
+
+	results := make(map[string][]string, len(sections))
+	for rawName, emails := range sections {
+		names := NormalizeSections([]string{rawName})
+		if len(names) == 0 {
+			return nil, fmt.Errorf("Course section has an empty name.")
+		}
+
+		name := names[0]
+
+		for _, email := range emails {
+			email = strings.TrimSpace(email)
+			if email == "" {
+				return nil, fmt.Errorf("Course section '%s' has an empty email.", name)
+			}
+
+			results[name] = append(results[name], email)
+		}
+
+		slices.Sort(results[name])
+		results[name] = slices.Compact(results[name])
+	}
+
+	return results, nil
+}
+
+// Get the sections declared in the course config for each listed user ({email: sections}).
+// Returns nil if the course does not declare any sections.
+func (this *Course) GetUserSections() map[string][]string {
+	if len(this.Sections) == 0 {
+		return nil
+	}
+
+	userSections := make(map[string][]string)
+	for name, emails := range this.Sections {
+		for _, email := range emails {
+			userSections[email] = append(userSections[email], name)
+		}
+	}
+
+	for email, sections := range userSections {
+		userSections[email] = NormalizeSections(sections)
+	}
+
+	return userSections
+}
+
+// Is this user in any of the given sections.
+func (this *CourseUser) InAnySection(sections []string) bool {
+	if this == nil {
+		return false
+	}
+
+	for _, section := range this.Sections {
+		if slices.Contains(sections, section) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Get the sections this user's view of the course is limited to.
+// Users below an admin that have sections can only see users in those sections,
+// all other users (including graders without a section) are not limited (and will get a nil result).
+func (this *CourseUser) GetSectionScope() []string {
+	if (this == nil) || (this.Role >= CourseRoleAdmin) || (len(this.Sections) == 0) {
+		return nil
+	}
+
+	return this.Sections
+}
@@ -0,0 +1,142 @@
+package model
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeSections(test *testing.T) {
+	testCases := []struct {
+		input    []string
+		expected []string
+	}{
+		{nil, nil},
+		{[]string{}, []string{}},
+		{[]string{"", "  "}, []string{}},
+		{[]string{"lab-a"}, []string{"lab-a"}},
+		{[]string{"Lab-B", " lab-a ", "LAB-A"}, []string{"lab-a", "lab-b"}},
+	}
+
+	for i, testCase := range testCases {
+		actual := NormalizeSections(testCase.input)
+		if !reflect.DeepEqual(testCase.expected, actual) {
+			test.Errorf("Case %d: Unexpected sections. Expected: '%v', Actual: '%v'.", i, testCase.expected, actual)
+		}
+	}
+}
+
+func TestCourseUserGetSectionScope(test *testing.T) {
+	testCases := []struct {
+		user     *CourseUser
+		expected []string
+	}{
+		{nil, nil},
+		{&CourseUser{Role: CourseRoleGrader}, nil},
+		{&CourseUser{Role: CourseRoleGrader, Sections: []string{"lab-a"}}, []string{"lab-a"}},
+		{&CourseUser{Role: CourseRoleStudent, Sections: []string{"lab-a", "lab-b"}}, []string{"lab-a", "lab-b"}},
+		{&CourseUser{Role: CourseRoleAdmin, Sections: []string{"lab-a"}}, nil},
+		{&CourseUser{Role: CourseRoleOwner, Sections: []string{"lab-a"}}, nil},
+	}
+
+	for i, testCase := range testCases {
+		actual := testCase.user.GetSectionScope()
+		if !reflect.DeepEqual(testCase.expected, actual) {
+			test.Errorf("Case %d: Unexpected scope. Expected: '%v', Actual: '%v'.", i, testCase.expected, actual)
+		}
+	}
+}
+
+func TestUserCourseInfoMergeSections(test *testing.T) {
+	testCases := []struct {
+		sections      []string
+		otherSections []string
+		expected      []string
+		changed       bool
+	}{
+		{nil, nil, nil, false},
+		{[]string{"lab-a"}, nil, []string{"lab-a"}, false},
+		{[]string{"lab-a"}, []string{"lab-a"}, []string{"lab-a"}, false},
+		{nil, []string{"lab-a"}, []string{"lab-a"}, true},
+		{[]string{"lab-a"}, []string{"lab-b"}, []string{"lab-b"}, true},
+		{[]string{"lab-a"}, []string{}, []string{}, true},
+	}
+
+	for i, testCase := range testCases {
+		info := &UserCourseInfo{Role: CourseRoleStudent, Sections: testCase.sections}
+		other := &UserCourseInfo{Sections: testCase.otherSections}
+
+		changed := info.Merge(other)
+		if testCase.changed != changed {
+			test.Errorf("Case %d: Unexpected changed value. Expected: '%v', Actual: '%v'.", i, testCase.changed, changed)
+			continue
+		}
+
+		if !reflect.DeepEqual(testCase.expected, info.Sections) {
+			test.Errorf("Case %d: Unexpected sections. Expected: '%v', Actual: '%v'.", i, testCase.expected, info.Sections)
+			continue
+		}
+	}
+}
+
+func TestCourseGetUserSections(test *testing.T) {
+	testCases := []struct {
+		sections          map[string][]string
+		expectedSections  map[string][]string
+		expectedUsers     map[string][]string
+		expectedErrorPart string
+	}{
+		{nil, nil, nil, ""},
+		{
+			map[string][]string{
+				" Lab-A ": []string{"a@test.edulinq.org", " b@test.edulinq.org", "a@test.edulinq.org"},
+				"lab-b":   []string{"b@test.edulinq.org"},
+			},
+			map[string][]string{
+				"lab-a": []string{"a@test.edulinq.org", "b@test.edulinq.org"},
+				"lab-b": []string{"b@test.edulinq.org"},
+			},
+			map[string][]string{
+				"a@test.edulinq.org": []string{"lab-a"},
+				"b@test.edulinq.org": []string{"lab-a", "lab-b"},
+			},
+			"",
+		},
+		{map[string][]string{" ": []string{"a@test.edulinq.org"}}, nil, nil, "empty name"},
+		{map[string][]string{"lab-a": []string{" "}}, nil, nil, "empty email"},
+	}
+
+	for i, testCase := range testCases {
+		course := &Course{
+			ID:       "course101",
+			Sections: testCase.sections,
+		}
+
+		err := course.Validate()
+		if err != nil {
+			if testCase.expectedErrorPart == "" {
+				test.Errorf("Case %d: Failed to validate course: '%v'.", i, err)
+			} else if !strings.Contains(err.Error(), testCase.expectedErrorPart) {
+				test.Errorf("Case %d: Unexpected error. Expected substring: '%s', Actual: '%v'.", i, testCase.expectedErrorPart, err)
+			}
+
+			continue
+		}
+
+		if testCase.expectedErrorPart != "" {
+			test.Errorf("Case %d: Did not get an expected error.", i)
+			continue
+		}
+
+		if !reflect.DeepEqual(testCase.expectedSections, course.Sections) {
+			test.Errorf("Case %d: Unexpected sections. Expected: '%v', Actual: '%v'.", i, testCase.expectedSections, course.Sections)
+			continue
+		}
+
+		userSections := course.GetUserSections()
+		if !reflect.DeepEqual(testCase.expectedUsers, userSections) {
+			test.Errorf("Case %d: Unexpected user sections. Expected: '%v', Actual: '%v'.", i, testCase.expectedUsers, userSections)
+			continue
+		}
+	}
+}
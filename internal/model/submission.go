@@ -21,10 +21,12 @@ type SubmissionHistoryItem struct {
 	User             string               `json:"user"`
 	ProxyUser        string               `json:"proxy-user,omitempty"`
 	ProxyTime        *timestamp.Timestamp `json:"proxy-time,omitempty"`
+	GroupID          string               `json:"group-id,omitempty"`
 	Message          string               `json:"message"`
 	MaxPoints        float64              `json:"max_points"`
 	Score            float64              `json:"score"`
 	GradingStartTime timestamp.Timestamp  `json:"grading_start_time"`
+	RubricIncomplete bool                 `json:"rubric-incomplete,omitempty"`
 }
 
 func (this GradingInfo) ToHistoryItem() *SubmissionHistoryItem {
@@ -36,9 +38,11 @@ func (this GradingInfo) ToHistoryItem() *SubmissionHistoryItem {
 		User:             this.User,
 		ProxyUser:        this.ProxyUser,
 		ProxyTime:        this.ProxyStartTime,
+		GroupID:          this.GroupID,
 		Message:          this.Message,
 		MaxPoints:        this.MaxPoints,
 		Score:            this.Score,
 		GradingStartTime: this.GradingStartTime,
+		RubricIncomplete: this.RubricIncomplete,
 	}
 }
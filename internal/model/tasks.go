@@ -86,6 +86,15 @@ func (this *UserTaskInfo) Validate() error {
 // The created hash will be consistent as long as the user-defined information stays the same.
 // Will return nil if the task is disabled.
 func (this *UserTaskInfo) ToFullCourseTask(courseID string) (*FullScheduledTask, error) {
+	return this.toFullTask(TaskSourceCourse, courseID)
+}
+
+// Like ToFullCourseTask(), but for tasks that belong to the server instead of a course.
+func (this *UserTaskInfo) ToFullServerTask() (*FullScheduledTask, error) {
+	return this.toFullTask(TaskSourceServer, "")
+}
+
+func (this *UserTaskInfo) toFullTask(source TaskSource, courseID string) (*FullScheduledTask, error) {
 	if this.Disabled {
 		return nil, nil
 	}
@@ -105,13 +114,18 @@ func (this *UserTaskInfo) ToFullCourseTask(courseID string) (*FullScheduledTask,
 		baselineTime = timestamp.Now()
 	}
 
+	// Use a combination of the course id (or source for non-course tasks), type, and hashed user config as the hash.
+	owner := courseID
+	if source != TaskSourceCourse {
+		owner = string(source)
+	}
+
 	systemTaskInfo := SystemTaskInfo{
-		Source:      TaskSourceCourse,
+		Source:      source,
 		LastRunTime: timestamp.Zero(),
 		NextRunTime: this.When.ComputeNextTime(baselineTime),
-		// Use a combination of the course id, type, and hashed user config as the hash.
-		Hash:     util.JoinStrings("::", courseID, string(this.Type), configHash),
-		CourseID: courseID,
+		Hash:        util.JoinStrings("::", owner, string(this.Type), configHash),
+		CourseID:    courseID,
 	}
 
 	err = systemTaskInfo.Validate()
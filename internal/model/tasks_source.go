@@ -10,18 +10,21 @@ type TaskSource string
 const (
 	TaskSourceUnknown TaskSource = ""
 	TaskSourceCourse             = "course"
+	TaskSourceServer             = "server"
 	TaskSourceTest               = "test"
 )
 
 var taskSourceToString = map[TaskSource]string{
 	TaskSourceUnknown: string(TaskSourceUnknown),
 	TaskSourceCourse:  string(TaskSourceCourse),
+	TaskSourceServer:  string(TaskSourceServer),
 	TaskSourceTest:    string(TaskSourceTest),
 }
 
 var stringToTaskSource = map[string]TaskSource{
 	string(TaskSourceUnknown): TaskSourceUnknown,
 	string(TaskSourceCourse):  TaskSourceCourse,
+	string(TaskSourceServer):  TaskSourceServer,
 	string(TaskSourceTest):    TaskSourceTest,
 }
 
@@ -19,6 +19,9 @@ const (
 	TaskTypeCourseScoringUpload TaskType = "scoring-upload"
 	TaskTypeCourseUpdate        TaskType = "update"
 
+	TaskTypeServerTokenCleanup         TaskType = "token-cleanup"
+	TaskTypeServerGradingTicketCleanup TaskType = "grading-ticket-cleanup"
+
 	TaskTypeTest TaskType = "test"
 )
 
@@ -31,6 +34,9 @@ var taskTypeToString = map[TaskType]string{
 	TaskTypeCourseScoringUpload: string(TaskTypeCourseScoringUpload),
 	TaskTypeCourseUpdate:        string(TaskTypeCourseUpdate),
 
+	TaskTypeServerTokenCleanup:         string(TaskTypeServerTokenCleanup),
+	TaskTypeServerGradingTicketCleanup: string(TaskTypeServerGradingTicketCleanup),
+
 	TaskTypeTest: string(TaskTypeTest),
 }
 
@@ -43,6 +49,9 @@ var stringToTaskType = map[string]TaskType{
 	string(TaskTypeCourseScoringUpload): TaskTypeCourseScoringUpload,
 	string(TaskTypeCourseUpdate):        TaskTypeCourseUpdate,
 
+	string(TaskTypeServerTokenCleanup):         TaskTypeServerTokenCleanup,
+	string(TaskTypeServerGradingTicketCleanup): TaskTypeServerGradingTicketCleanup,
+
 	string(TaskTypeTest): TaskTypeTest,
 }
 
@@ -71,6 +80,10 @@ func validateTaskTypes(task *UserTaskInfo) error {
 		return nil
 	case TaskTypeCourseEmailLogs:
 		return validateTaskTypeCourseEmailLogs(task)
+	case TaskTypeServerTokenCleanup:
+		return nil
+	case TaskTypeServerGradingTicketCleanup:
+		return nil
 	case TaskTypeTest:
 		return nil
 	default:
@@ -78,6 +91,11 @@ func validateTaskTypes(task *UserTaskInfo) error {
 	}
 }
 
+// Server tasks are created by the server itself and cannot be declared by courses.
+func (this TaskType) IsServerTask() bool {
+	return (this == TaskTypeServerTokenCleanup) || (this == TaskTypeServerGradingTicketCleanup)
+}
+
 func validateTaskTypeCourseEmailLogs(task *UserTaskInfo) error {
 	err := validateEmailList(task)
 	if err != nil {
@@ -4,6 +4,7 @@ import (
 	"crypto/subtle"
 	"encoding/hex"
 	"fmt"
+	"slices"
 	"strings"
 
 	"golang.org/x/crypto/argon2"
@@ -20,6 +21,15 @@ type TokenSource string
 const DEFAULT_SALT string = "c75385ab94f66b3454e93cb0d0546fc1"
 const TOKEN_PASSWORD_NAME = "password"
 
+// Scopes limit the endpoints that a token can be used for.
+// A scope is either an endpoint (e.g., "courses/assignments/submit"),
+// an endpoint prefix ending in a wildcard (e.g., "courses/assignments/submissions/*"),
+// or one of the special scopes below.
+const (
+	TOKEN_SCOPE_ALL       = "*"
+	TOKEN_SCOPE_READ_ONLY = "read-only"
+)
+
 const (
 	TokenSourceUnknown  TokenSource = ""
 	TokenSourceServer               = "server"
@@ -36,6 +46,16 @@ type TokenInfo struct {
 	Name         string              `json:"name"`
 	CreationTime timestamp.Timestamp `json:"creation-time"`
 	AccessTime   timestamp.Timestamp `json:"access-time"`
+
+	// If set, this token cannot be used after this time.
+	ExpirationTime *timestamp.Timestamp `json:"expiration-time,omitempty"`
+
+	// If set, this token can only be used for endpoints that match one of these scopes.
+	Scopes []string `json:"scopes,omitempty"`
+
+	// Set if this token was created for a user that had two-factor auth enabled.
+	// These tokens do not require a two-factor code (so they can be used by scripts).
+	TwoFactor bool `json:"two-factor,omitempty"`
 }
 
 // Tokens refer to any hex string that is used for authentication.
@@ -197,10 +217,103 @@ func (this *Token) Validate() error {
 		return fmt.Errorf("Token ('%s') has unknown source.", this.Name)
 	}
 
+	if (this.Source == TokenSourcePassword) && ((this.ExpirationTime != nil) || (len(this.Scopes) > 0) || this.TwoFactor) {
+		return fmt.Errorf("Password token ('%s') cannot have an expiration time, scopes, or a two-factor mark.", this.Name)
+	}
+
+	this.Scopes, err = ValidateTokenScopes(this.Scopes)
+	if err != nil {
+		return fmt.Errorf("Token ('%s') has invalid scopes: '%w'.", this.Name, err)
+	}
+
 	return nil
 }
 
+// Check if this token has expired at the given time.
+func (this *Token) IsExpired(now timestamp.Timestamp) bool {
+	return (this.ExpirationTime != nil) && (*this.ExpirationTime <= now)
+}
+
+// Check if this token may be used for an endpoint.
+// The endpoint should not include the API prefix, e.g., "courses/assignments/submit".
+// Tokens without any scopes are allowed to access all endpoints.
+func (this *Token) AllowsEndpoint(endpoint string) bool {
+	if len(this.Scopes) == 0 {
+		return true
+	}
+
+	endpoint = strings.Trim(endpoint, "/")
+
+	for _, scope := range this.Scopes {
+		if tokenScopeMatches(scope, endpoint) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Validate and clean a list of token scopes.
+// Scopes will be trimmed, sorted, and deduplicated.
+func ValidateTokenScopes(scopes []string) ([]string, error) {
+	if len(scopes) == 0 {
+		return nil, nil
+	}
+
+	results := make([]string, 0, len(scopes))
+	for i, scope := range scopes {
+		scope = strings.Trim(strings.TrimSpace(scope), "/")
+		if scope == "" {
+			return nil, fmt.Errorf("Scope at index %d is empty.", i)
+		}
+
+		if (scope == TOKEN_SCOPE_ALL) || (scope == TOKEN_SCOPE_READ_ONLY) {
+			results = append(results, scope)
+			continue
+		}
+
+		if strings.ContainsAny(scope, " \t\n") {
+			return nil, fmt.Errorf("Scope '%s' contains whitespace.", scope)
+		}
+
+		wildcardIndex := strings.Index(scope, TOKEN_SCOPE_ALL)
+		if (wildcardIndex != -1) && ((wildcardIndex != (len(scope) - 1)) || !strings.HasSuffix(scope, "/"+TOKEN_SCOPE_ALL)) {
+			return nil, fmt.Errorf("Scope '%s' has a wildcard that is not its last path component.", scope)
+		}
+
+		results = append(results, scope)
+	}
+
+	slices.Sort(results)
+	results = slices.Compact(results)
+
+	return results, nil
+}
+
+func tokenScopeMatches(scope string, endpoint string) bool {
+	if scope == TOKEN_SCOPE_ALL {
+		return true
+	}
+
+	if scope == TOKEN_SCOPE_READ_ONLY {
+		return IsReadOnlyEndpoint(endpoint)
+	}
+
+	prefix, isWildcard := strings.CutSuffix(scope, TOKEN_SCOPE_ALL)
+	if isWildcard {
+		return strings.HasPrefix(endpoint, prefix)
+	}
+
+	return (scope == endpoint)
+}
+
 func (this *Token) Clone() *Token {
+	var expirationTime *timestamp.Timestamp = nil
+	if this.ExpirationTime != nil {
+		value := *this.ExpirationTime
+		expirationTime = &value
+	}
+
 	return &Token{
 		TokenInfo: TokenInfo{
 			ID:           this.ID,
@@ -208,6 +321,10 @@ func (this *Token) Clone() *Token {
 			Name:         this.Name,
 			CreationTime: this.CreationTime,
 			AccessTime:   this.AccessTime,
+
+			ExpirationTime: expirationTime,
+			Scopes:         slices.Clone(this.Scopes),
+			TwoFactor:      this.TwoFactor,
 		},
 		HexDigest: this.HexDigest,
 	}
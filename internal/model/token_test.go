@@ -1,8 +1,10 @@
 package model
 
 import (
+	"reflect"
 	"testing"
 
+	"github.com/edulinq/autograder/internal/timestamp"
 	"github.com/edulinq/autograder/internal/util"
 )
 
@@ -71,3 +73,119 @@ func TestTokenRandom(test *testing.T) {
 		test.Fatalf("Token did match when it should not have.")
 	}
 }
+
+func TestTokenAllowsEndpoint(test *testing.T) {
+	testCases := []struct {
+		scopes   []string
+		endpoint string
+		expected bool
+	}{
+		{nil, "courses/assignments/submit", true},
+		{[]string{}, "users/tokens/create", true},
+
+		{[]string{"*"}, "users/tokens/create", true},
+
+		{[]string{"courses/assignments/submit"}, "courses/assignments/submit", true},
+		{[]string{"courses/assignments/submit"}, "/courses/assignments/submit", true},
+		{[]string{"courses/assignments/submit"}, "courses/assignments/submissions/remove", false},
+
+		{[]string{"courses/assignments/submissions/*"}, "courses/assignments/submissions/fetch/user/peek", true},
+		{[]string{"courses/assignments/submissions/*"}, "courses/assignments/submissions/remove", true},
+		{[]string{"courses/assignments/submissions/*"}, "courses/assignments/submit", false},
+		{[]string{"courses/assignments/submissions/*"}, "courses/assignments/submissions", false},
+
+		{[]string{"read-only"}, "courses/assignments/get", true},
+		{[]string{"read-only"}, "courses/assignments/submissions/fetch/user/peek", true},
+		{[]string{"read-only"}, "users/tokens/list", true},
+		{[]string{"read-only"}, "courses/assignments/submit", false},
+		{[]string{"read-only"}, "users/tokens/create", false},
+		{[]string{"read-only"}, "/courses/users/list/", true},
+		{[]string{"read-only"}, "courses/users/list/remove", false},
+		{[]string{"read-only"}, "lms/upload/scores", false},
+		{[]string{"read-only"}, "courses/assignments/submissions/analysis/individual", false},
+
+		{[]string{"read-only", "courses/assignments/submit"}, "courses/assignments/submit", true},
+		{[]string{"read-only", "courses/assignments/submit"}, "courses/users/list", true},
+		{[]string{"read-only", "courses/assignments/submit"}, "courses/users/drop", false},
+	}
+
+	for i, testCase := range testCases {
+		token := Token{TokenInfo: TokenInfo{Scopes: testCase.scopes}}
+
+		actual := token.AllowsEndpoint(testCase.endpoint)
+		if testCase.expected != actual {
+			test.Errorf("Case %d: Unexpected result for endpoint '%s' with scopes '%v'. Expected: %v, Actual: %v.",
+				i, testCase.endpoint, testCase.scopes, testCase.expected, actual)
+		}
+	}
+}
+
+func TestValidateTokenScopes(test *testing.T) {
+	testCases := []struct {
+		scopes   []string
+		expected []string
+		hasError bool
+	}{
+		{nil, nil, false},
+		{[]string{}, nil, false},
+		{[]string{"*"}, []string{"*"}, false},
+		{[]string{"read-only"}, []string{"read-only"}, false},
+		{
+			[]string{" /users/tokens/list/ ", "courses/assignments/submissions/*", "users/tokens/list"},
+			[]string{"courses/assignments/submissions/*", "users/tokens/list"},
+			false,
+		},
+
+		{[]string{""}, nil, true},
+		{[]string{"  "}, nil, true},
+		{[]string{"courses/assign ments/submit"}, nil, true},
+		{[]string{"courses/*/submit"}, nil, true},
+		{[]string{"courses/assignments/sub*"}, nil, true},
+		{[]string{"courses/assignments/*/*"}, nil, true},
+	}
+
+	for i, testCase := range testCases {
+		actual, err := ValidateTokenScopes(testCase.scopes)
+		if err != nil {
+			if !testCase.hasError {
+				test.Errorf("Case %d: Unexpected error: '%v'.", i, err)
+			}
+
+			continue
+		}
+
+		if testCase.hasError {
+			test.Errorf("Case %d: Did not get expected error.", i)
+			continue
+		}
+
+		if !reflect.DeepEqual(testCase.expected, actual) {
+			test.Errorf("Case %d: Unexpected scopes. Expected: '%v', Actual: '%v'.", i, testCase.expected, actual)
+		}
+	}
+}
+
+func TestTokenIsExpired(test *testing.T) {
+	now := timestamp.Now()
+	past := now - 1000
+	future := now + 1000
+
+	testCases := []struct {
+		expiration *timestamp.Timestamp
+		expected   bool
+	}{
+		{nil, false},
+		{&past, true},
+		{&now, true},
+		{&future, false},
+	}
+
+	for i, testCase := range testCases {
+		token := Token{TokenInfo: TokenInfo{ExpirationTime: testCase.expiration}}
+
+		actual := token.IsExpired(now)
+		if testCase.expected != actual {
+			test.Errorf("Case %d: Unexpected result. Expected: %v, Actual: %v.", i, testCase.expected, actual)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package model
+
+import (
+	"fmt"
+
+	"github.com/edulinq/autograder/internal/timestamp"
+)
+
+// A user's two-factor (TOTP) authentication settings.
+// A secret is generated when a user enrolls,
+// but two-factor auth is not enabled until the user verifies a code generated from that secret.
+// An empty (non-nil) struct indicates that two-factor auth has been disabled
+// (so that the change is kept when merged with an existing user).
+type TwoFactorInfo struct {
+	// A base32-encoded TOTP secret.
+	Secret     string              `json:"secret"`
+	Enabled    bool                `json:"enabled"`
+	EnrollTime timestamp.Timestamp `json:"enroll-time"`
+
+	// The TOTP time step of the last accepted code.
+	// Codes from this time step (or earlier) will not be accepted again.
+	LastTimeStep uint64 `json:"last-time-step,omitempty"`
+}
+
+func (this *TwoFactorInfo) Validate() error {
+	if this.Enabled && (this.Secret == "") {
+		return fmt.Errorf("Two-factor auth is enabled without a secret.")
+	}
+
+	return nil
+}
+
+func (this *TwoFactorInfo) Clone() *TwoFactorInfo {
+	if this == nil {
+		return nil
+	}
+
+	info := *this
+	return &info
+}
+
+// Does this user have two-factor auth enabled.
+func (this *ServerUser) HasTwoFactor() bool {
+	return (this.TwoFactor != nil) && this.TwoFactor.Enabled && (this.TwoFactor.Secret != "")
+}
+
+// Is this user privileged enough that they may be required to use two-factor auth.
+// This is any server admin (or above), or any course admin (or above) in any course.
+func (this *ServerUser) IsTwoFactorPrivileged() bool {
+	if this.Email == RootUserEmail {
+		return false
+	}
+
+	if this.Role >= ServerRoleAdmin {
+		return true
+	}
+
+	for _, info := range this.CourseInfo {
+		if info.Role >= CourseRoleAdmin {
+			return true
+		}
+	}
+
+	return false
+}
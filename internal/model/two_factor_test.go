@@ -0,0 +1,86 @@
+package model
+
+import (
+	"testing"
+)
+
+func TestServerUserTwoFactorMerge(test *testing.T) {
+	user := mustNewTwoFactorTestUser(ServerRoleUser, nil)
+
+	enabled := &TwoFactorInfo{Secret: "GEZDGNBVGY3TQOJQ", Enabled: true}
+
+	changed, err := user.Merge(mustNewTwoFactorTestUser(ServerRoleUnknown, enabled))
+	if err != nil {
+		test.Fatalf("Failed to merge enabled user: '%v'.", err)
+	}
+
+	if !changed || !user.HasTwoFactor() {
+		test.Fatalf("Two-factor auth was not enabled by merge (changed: %v).", changed)
+	}
+
+	// A nil two-factor info does not change anything.
+	changed, err = user.Merge(mustNewTwoFactorTestUser(ServerRoleUnknown, nil))
+	if err != nil {
+		test.Fatalf("Failed to merge nil user: '%v'.", err)
+	}
+
+	if changed || !user.HasTwoFactor() {
+		test.Fatalf("Two-factor auth was changed by a nil merge (changed: %v).", changed)
+	}
+
+	// An empty two-factor info disables two-factor auth.
+	changed, err = user.Merge(mustNewTwoFactorTestUser(ServerRoleUnknown, &TwoFactorInfo{}))
+	if err != nil {
+		test.Fatalf("Failed to merge disabled user: '%v'.", err)
+	}
+
+	if !changed || user.HasTwoFactor() {
+		test.Fatalf("Two-factor auth was not disabled by merge (changed: %v).", changed)
+	}
+
+	// Enabled without a secret is invalid.
+	_, err = user.Merge(mustNewTwoFactorTestUser(ServerRoleUnknown, &TwoFactorInfo{Enabled: true}))
+	if err == nil {
+		test.Fatalf("Did not get an error when merging an invalid two-factor info.")
+	}
+}
+
+func TestServerUserIsTwoFactorPrivileged(test *testing.T) {
+	testCases := []struct {
+		serverRole ServerUserRole
+		courseRole CourseUserRole
+		expected   bool
+	}{
+		{ServerRoleUser, CourseRoleUnknown, false},
+		{ServerRoleCourseCreator, CourseRoleUnknown, false},
+		{ServerRoleAdmin, CourseRoleUnknown, true},
+		{ServerRoleOwner, CourseRoleUnknown, true},
+
+		{ServerRoleUser, CourseRoleStudent, false},
+		{ServerRoleUser, CourseRoleGrader, false},
+		{ServerRoleUser, CourseRoleAdmin, true},
+		{ServerRoleUser, CourseRoleOwner, true},
+	}
+
+	for i, testCase := range testCases {
+		user := mustNewTwoFactorTestUser(testCase.serverRole, nil)
+		if testCase.courseRole != CourseRoleUnknown {
+			user.CourseInfo["course101"] = &UserCourseInfo{Role: testCase.courseRole}
+		}
+
+		actual := user.IsTwoFactorPrivileged()
+		if testCase.expected != actual {
+			test.Errorf("Case %d: Unexpected result. Expected: '%v', Actual: '%v'.", i, testCase.expected, actual)
+		}
+	}
+}
+
+func mustNewTwoFactorTestUser(role ServerUserRole, info *TwoFactorInfo) *ServerUser {
+	return &ServerUser{
+		Email:      "alice@test.edulinq.org",
+		Role:       role,
+		Tokens:     []*Token{},
+		CourseInfo: map[string]*UserCourseInfo{},
+		TwoFactor:  info,
+	}
+}
@@ -2,6 +2,7 @@ package model
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/edulinq/autograder/internal/log"
@@ -18,6 +19,9 @@ type CourseUser struct {
 	Name  *string        `json:"name"`
 	Role  CourseUserRole `json:"role"`
 	LMSID *string        `json:"lms-id"`
+
+	// The sections (e.g., labs) this user belongs to within the course.
+	Sections []string `json:"sections,omitempty"`
 }
 
 func NewCourseUser(email string, name *string, role CourseUserRole, lmsID *string) (*CourseUser, error) {
@@ -51,6 +55,8 @@ func (this *CourseUser) Validate() error {
 		this.LMSID = &lmsID
 	}
 
+	this.Sections = NormalizeSections(this.Sections)
+
 	return nil
 }
 
@@ -92,7 +98,7 @@ func (this *CourseUser) ToServerUser(courseID string) (*ServerUser, error) {
 	serverUser := &ServerUser{
 		Email:      this.Email,
 		Name:       this.Name,
-		CourseInfo: map[string]*UserCourseInfo{courseID: &UserCourseInfo{Role: this.Role, LMSID: this.LMSID}},
+		CourseInfo: map[string]*UserCourseInfo{courseID: &UserCourseInfo{Role: this.Role, LMSID: this.LMSID, Sections: slices.Clone(this.Sections)}},
 	}
 
 	return serverUser, serverUser.validate(false)
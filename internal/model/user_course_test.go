@@ -354,6 +354,12 @@ func setCourseUserLMSID(user *CourseUser, lmsID *string) *CourseUser {
 	return &newUser
 }
 
+func setCourseUserSections(user *CourseUser, sections []string) *CourseUser {
+	newUser := *user
+	newUser.Sections = sections
+	return &newUser
+}
+
 var baseTestCourseUser *CourseUser = &CourseUser{
 	Email: "alice@test.edulinq.org",
 	Name:  util.StringPointer("Alice"),
@@ -1,6 +1,8 @@
 package model
 
 import (
+	"slices"
+
 	"github.com/edulinq/autograder/internal/util"
 )
 
@@ -14,6 +16,8 @@ type RawServerUserData struct {
 	Course      string `json:"course,omitempty" help:"Optional ID of course to enroll user in."`
 	CourseRole  string `json:"course-role,omitempty" help:"Role for the new user in the specified course. Defaults to 'student'." default:"student"`
 	CourseLMSID string `json:"course-lms-id,omitempty" help:"LMS ID for the new user in the specified course."`
+
+	CourseSections []string `json:"course-sections,omitempty" help:"Sections (e.g., labs) for the new user in the specified course."`
 }
 
 // Raw/dirty data for a course user.
@@ -23,6 +27,8 @@ type RawCourseUserData struct {
 	Name        string `json:"name"`
 	CourseRole  string `json:"course-role"`
 	CourseLMSID string `json:"course-lms-id"`
+
+	CourseSections []string `json:"course-sections,omitempty"`
 }
 
 // Convert RawCourseUserData to RawServerUserData for a course.
@@ -35,6 +41,8 @@ func (this *RawCourseUserData) ToRawServerUserData(course *Course) *RawServerUse
 		Course:      course.GetID(),
 		CourseRole:  this.CourseRole,
 		CourseLMSID: this.CourseLMSID,
+
+		CourseSections: this.CourseSections,
 	}
 
 	return rawUserData
@@ -79,6 +87,10 @@ func (this *RawServerUserData) ToServerUser() (*ServerUser, error) {
 		if this.CourseLMSID != "" {
 			user.CourseInfo[this.Course].LMSID = &this.CourseLMSID
 		}
+
+		if this.CourseSections != nil {
+			user.CourseInfo[this.Course].Sections = slices.Clone(this.CourseSections)
+		}
 	}
 
 	return user, user.validate(false)
@@ -91,5 +103,5 @@ func (this *RawServerUserData) HasServerInfo() bool {
 
 // Does this data have course-level user information?
 func (this *RawServerUserData) HasCourseInfo() bool {
-	return (this.Course != "") || (this.CourseRole != "") || (this.CourseLMSID != "")
+	return (this.Course != "") || (this.CourseRole != "") || (this.CourseLMSID != "") || (this.CourseSections != nil)
 }
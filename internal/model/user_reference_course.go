@@ -11,6 +11,7 @@ import (
 // - An email address.
 // - A literal "*" (which includes all users in the course).
 // - A course role (which will include all course users with that role).
+// - A section name preceded by "section:" (which will include all course users in that section).
 // - Any of the above options preceded by a dash ("-") (which indicates that the user or group will NOT be included in the final results).
 type CourseUserReference string
 
@@ -26,6 +27,16 @@ type ParsedCourseUserReference struct {
 
 	// The set of course roles to exclude.
 	ExcludeCourseUserRoles map[CourseUserRole]any
+
+	// The set of sections to include.
+	Sections map[string]any
+
+	// The set of sections to exclude.
+	ExcludeSections map[string]any
+
+	// If not empty, only users in at least one of these sections can be referred to.
+	// This is not set by parsing, and is used to limit a reference to what a user is allowed to see.
+	RestrictSections map[string]any
 }
 
 func (this *ParsedCourseUserReference) ToParsedServerUserReference(courseID string) *ParsedServerUserReference {
@@ -50,6 +61,8 @@ func (this *ParsedCourseUserReference) Merge(other *ParsedCourseUserReference) *
 		ExcludeEmails:          make(map[string]any, 0),
 		CourseUserRoles:        make(map[CourseUserRole]any, 0),
 		ExcludeCourseUserRoles: make(map[CourseUserRole]any, 0),
+		Sections:               make(map[string]any, 0),
+		ExcludeSections:        make(map[string]any, 0),
 	}
 
 	for _, reference := range []*ParsedCourseUserReference{this, other} {
@@ -72,12 +85,40 @@ func (this *ParsedCourseUserReference) Merge(other *ParsedCourseUserReference) *
 		for role, _ := range reference.ExcludeCourseUserRoles {
 			mergedReference.ExcludeCourseUserRoles[role] = nil
 		}
+
+		for section, _ := range reference.Sections {
+			mergedReference.Sections[section] = nil
+		}
+
+		for section, _ := range reference.ExcludeSections {
+			mergedReference.ExcludeSections[section] = nil
+		}
+
+		for section, _ := range reference.RestrictSections {
+			mergedReference.RestrictToSections([]string{section})
+		}
 	}
 
 	return &mergedReference
 }
 
-func (this *ParsedCourseUserReference) Excludes(email string, role CourseUserRole) bool {
+// Limit this reference to only users in at least one of the given sections.
+// Does nothing if no sections are given.
+func (this *ParsedCourseUserReference) RestrictToSections(sections []string) {
+	if (this == nil) || (len(sections) == 0) {
+		return
+	}
+
+	if this.RestrictSections == nil {
+		this.RestrictSections = make(map[string]any, len(sections))
+	}
+
+	for _, section := range sections {
+		this.RestrictSections[section] = nil
+	}
+}
+
+func (this *ParsedCourseUserReference) Excludes(email string, role CourseUserRole, sections []string) bool {
 	if this == nil {
 		return false
 	}
@@ -92,15 +133,23 @@ func (this *ParsedCourseUserReference) Excludes(email string, role CourseUserRol
 		return true
 	}
 
+	if containsAnySection(this.ExcludeSections, sections) {
+		return true
+	}
+
+	if (len(this.RestrictSections) > 0) && !containsAnySection(this.RestrictSections, sections) {
+		return true
+	}
+
 	return false
 }
 
-func (this *ParsedCourseUserReference) RefersTo(email string, role CourseUserRole) bool {
+func (this *ParsedCourseUserReference) RefersTo(email string, role CourseUserRole, sections []string) bool {
 	if this == nil {
 		return false
 	}
 
-	if this.Excludes(email, role) {
+	if this.Excludes(email, role, sections) {
 		return false
 	}
 
@@ -114,6 +163,10 @@ func (this *ParsedCourseUserReference) RefersTo(email string, role CourseUserRol
 		return true
 	}
 
+	if containsAnySection(this.Sections, sections) {
+		return true
+	}
+
 	return false
 }
 
@@ -133,6 +186,8 @@ func ParseCourseUserReferences(rawReferences []CourseUserReference) (*ParsedCour
 		ExcludeEmails:          make(map[string]any, 0),
 		CourseUserRoles:        make(map[CourseUserRole]any, 0),
 		ExcludeCourseUserRoles: make(map[CourseUserRole]any, 0),
+		Sections:               make(map[string]any, 0),
+		ExcludeSections:        make(map[string]any, 0),
 	}
 
 	var errs error = nil
@@ -160,6 +215,18 @@ func ParseCourseUserReferences(rawReferences []CourseUserReference) (*ParsedCour
 			} else {
 				courseUserReference.Emails[reference] = nil
 			}
+		} else if strings.HasPrefix(reference, SECTION_REFERENCE_PREFIX) {
+			section := strings.TrimSpace(strings.TrimPrefix(reference, SECTION_REFERENCE_PREFIX))
+			if section == "" {
+				errs = errors.Join(errs, fmt.Errorf("Course user reference '%s' contains an empty section.", rawReference))
+				continue
+			}
+
+			if exclude {
+				courseUserReference.ExcludeSections[section] = nil
+			} else {
+				courseUserReference.Sections[section] = nil
+			}
 		} else if reference == "*" {
 			allCourseRoles := make(map[CourseUserRole]any, len(commonCourseRoles))
 			for _, role := range commonCourseRoles {
@@ -199,7 +266,7 @@ func ResolveCourseUsers(users map[string]*CourseUser, reference *ParsedCourseUse
 	results := make([]*CourseUser, 0, len(users))
 
 	for email, user := range users {
-		if reference.RefersTo(email, user.Role) {
+		if reference.RefersTo(email, user.Role, user.Sections) {
 			results = append(results, user)
 		}
 	}
@@ -224,16 +291,22 @@ func ResolveCourseUserEmails(users map[string]*CourseUser, reference *ParsedCour
 
 	// Add all emails from the course users.
 	for email, user := range users {
-		if reference.Excludes(email, user.Role) {
+		if reference.Excludes(email, user.Role, user.Sections) {
 			excludeSet[email] = nil
-		} else if reference.RefersTo(email, user.Role) {
+		} else if reference.RefersTo(email, user.Role, user.Sections) {
 			emailSet[email] = nil
 		}
 	}
 
 	// Add all emails based on email alone.
+	// Users outside of the course are never in a section, so a restricted reference cannot include them.
 	for email, _ := range reference.Emails {
-		_, ok := reference.ExcludeEmails[email]
+		_, ok := users[email]
+		if !ok && (len(reference.RestrictSections) > 0) {
+			continue
+		}
+
+		_, ok = reference.ExcludeEmails[email]
 		if ok {
 			continue
 		}
@@ -284,3 +357,15 @@ func courseUserRolesToParsedCourseUserReference(courseRoles map[CourseUserRole]a
 		ExcludeCourseUserRoles: excludeCourseUserRoles,
 	}
 }
+
+// Is any of the given sections in the set.
+func containsAnySection(set map[string]any, sections []string) bool {
+	for _, section := range sections {
+		_, ok := set[section]
+		if ok {
+			return true
+		}
+	}
+
+	return false
+}
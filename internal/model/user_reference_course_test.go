@@ -36,6 +36,34 @@ var defaultCourseUsers = map[string]*CourseUser{
 	},
 }
 
+// Test course users that are split into sections.
+var sectionCourseUsers = map[string]*CourseUser{
+	"course-admin@test.edulinq.org": &CourseUser{
+		Email: "course-admin@test.edulinq.org",
+		Role:  CourseRoleAdmin,
+	},
+	"grader-a@test.edulinq.org": &CourseUser{
+		Email:    "grader-a@test.edulinq.org",
+		Role:     CourseRoleGrader,
+		Sections: []string{"lab-a"},
+	},
+	"student-a@test.edulinq.org": &CourseUser{
+		Email:    "student-a@test.edulinq.org",
+		Role:     CourseRoleStudent,
+		Sections: []string{"lab-a"},
+	},
+	"student-ab@test.edulinq.org": &CourseUser{
+		Email:    "student-ab@test.edulinq.org",
+		Role:     CourseRoleStudent,
+		Sections: []string{"lab-a", "lab-b"},
+	},
+	"student-b@test.edulinq.org": &CourseUser{
+		Email:    "student-b@test.edulinq.org",
+		Role:     CourseRoleStudent,
+		Sections: []string{"lab-b"},
+	},
+}
+
 // The shared test cases between the TestResolveCourseUser*() functions.
 var resolveCourseUserTestCases = []resolveCourseUserTestCase{
 	// Empty Inputs
@@ -247,6 +275,75 @@ var resolveCourseUserTestCases = []resolveCourseUserTestCase{
 			"course-student@test.edulinq.org",
 		},
 	},
+
+	// Sections
+	{
+		&ParsedCourseUserReference{
+			Sections: map[string]any{
+				"lab-a": nil,
+			},
+		},
+		sectionCourseUsers,
+		[]string{"grader-a@test.edulinq.org", "student-a@test.edulinq.org", "student-ab@test.edulinq.org"},
+	},
+	{
+		&ParsedCourseUserReference{
+			Sections: map[string]any{
+				"lab-a": nil,
+			},
+			ExcludeSections: map[string]any{
+				"lab-b": nil,
+			},
+		},
+		sectionCourseUsers,
+		[]string{"grader-a@test.edulinq.org", "student-a@test.edulinq.org"},
+	},
+	{
+		&ParsedCourseUserReference{
+			Sections: map[string]any{
+				"zzz": nil,
+			},
+		},
+		sectionCourseUsers,
+		[]string{},
+	},
+
+	// Restricted Sections
+	{
+		&ParsedCourseUserReference{
+			CourseUserRoles: map[CourseUserRole]any{
+				GetCourseUserRole("student"): nil,
+			},
+			RestrictSections: map[string]any{
+				"lab-b": nil,
+			},
+		},
+		sectionCourseUsers,
+		[]string{"student-ab@test.edulinq.org", "student-b@test.edulinq.org"},
+	},
+	{
+		&ParsedCourseUserReference{
+			CourseUserRoles: allCourseRoles,
+			RestrictSections: map[string]any{
+				"lab-a": nil,
+			},
+		},
+		sectionCourseUsers,
+		[]string{"grader-a@test.edulinq.org", "student-a@test.edulinq.org", "student-ab@test.edulinq.org"},
+	},
+	{
+		&ParsedCourseUserReference{
+			Emails: map[string]any{
+				"course-admin@test.edulinq.org": nil,
+				"student-b@test.edulinq.org":    nil,
+			},
+			RestrictSections: map[string]any{
+				"lab-a": nil,
+			},
+		},
+		sectionCourseUsers,
+		[]string{},
+	},
 }
 
 // The named test case struct allows specific tests to add additional test cases.
@@ -444,8 +541,41 @@ func TestParseCourseUserReferences(test *testing.T) {
 			"",
 		},
 
+		// Sections
+		{
+			[]CourseUserReference{"section:lab-a", "-section:lab-b"},
+			&ParsedCourseUserReference{
+				Sections: map[string]any{
+					"lab-a": nil,
+				},
+				ExcludeSections: map[string]any{
+					"lab-b": nil,
+				},
+			},
+			"",
+		},
+		{
+			[]CourseUserReference{"SECTION:Lab-A", " section: lab-a ", "- section:lab-b"},
+			&ParsedCourseUserReference{
+				Sections: map[string]any{
+					"lab-a": nil,
+				},
+				ExcludeSections: map[string]any{
+					"lab-b": nil,
+				},
+			},
+			"",
+		},
+
 		// Errors
 
+		// Empty Section
+		{
+			[]CourseUserReference{"section:"},
+			nil,
+			"Course user reference 'section:' contains an empty section.",
+		},
+
 		// Unknown Course Role
 		{
 			[]CourseUserReference{"ZZZ"},
@@ -490,6 +620,14 @@ func TestParseCourseUserReferences(test *testing.T) {
 			testCase.output.ExcludeCourseUserRoles = make(map[CourseUserRole]any, 0)
 		}
 
+		if testCase.output.Sections == nil {
+			testCase.output.Sections = make(map[string]any, 0)
+		}
+
+		if testCase.output.ExcludeSections == nil {
+			testCase.output.ExcludeSections = make(map[string]any, 0)
+		}
+
 		if !reflect.DeepEqual(testCase.output, result) {
 			test.Errorf("Case %d: Unexpected result. Expected: '%s', Actual: '%s'.",
 				i, util.MustToJSONIndent(testCase.output), util.MustToJSONIndent(result))
@@ -68,7 +68,7 @@ func (this *ParsedServerUserReference) Excludes(user *ServerUser) bool {
 			continue
 		}
 
-		if courseReference.Excludes(user.Email, courseInfo.Role) {
+		if courseReference.Excludes(user.Email, courseInfo.Role, courseInfo.Sections) {
 			return true
 		}
 	}
@@ -101,7 +101,7 @@ func (this *ParsedServerUserReference) RefersTo(user *ServerUser) bool {
 			continue
 		}
 
-		if courseReference.RefersTo(user.Email, courseInfo.Role) {
+		if courseReference.RefersTo(user.Email, courseInfo.Role, courseInfo.Sections) {
 			return true
 		}
 	}
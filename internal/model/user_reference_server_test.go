@@ -902,6 +902,14 @@ func TestParseServerUserReferences(test *testing.T) {
 			if courseReference.ExcludeCourseUserRoles == nil {
 				courseReference.ExcludeCourseUserRoles = make(map[CourseUserRole]any, 0)
 			}
+
+			if courseReference.Sections == nil {
+				courseReference.Sections = make(map[string]any, 0)
+			}
+
+			if courseReference.ExcludeSections == nil {
+				courseReference.ExcludeSections = make(map[string]any, 0)
+			}
 		}
 
 		if !reflect.DeepEqual(testCase.output, result) {
@@ -9,6 +9,7 @@ import (
 
 	"github.com/edulinq/autograder/internal/common"
 	"github.com/edulinq/autograder/internal/log"
+	"github.com/edulinq/autograder/internal/timestamp"
 	"github.com/edulinq/autograder/internal/util"
 )
 
@@ -39,6 +40,9 @@ type ServerUser struct {
 	// will always be non-nil after validation.
 	// Keyed by the course id.
 	CourseInfo map[string]*UserCourseInfo `json:"course-info"`
+
+	// May be nil if the user has never enrolled in two-factor auth.
+	TwoFactor *TwoFactorInfo `json:"two-factor,omitempty"`
 }
 
 var RootUserEmail = "root"
@@ -46,6 +50,10 @@ var RootUserEmail = "root"
 type UserCourseInfo struct {
 	Role  CourseUserRole `json:"role"`
 	LMSID *string        `json:"lms-id"`
+
+	// The sections (e.g., labs) this user belongs to within the course.
+	// Nil indicates no change when merging, while an empty slice will clear the sections.
+	Sections []string `json:"sections,omitempty"`
 }
 
 func (this *ServerUser) Validate() error {
@@ -114,6 +122,13 @@ func (this *ServerUser) validate(checkAll bool) error {
 
 	this.compactTokens()
 
+	if this.TwoFactor != nil {
+		err := this.TwoFactor.Validate()
+		if err != nil {
+			return fmt.Errorf("User '%s' has invalid two-factor auth: '%w'.", this.Email, err)
+		}
+	}
+
 	if this.CourseInfo == nil {
 		this.CourseInfo = make(map[string]*UserCourseInfo, 0)
 	}
@@ -197,6 +212,7 @@ func (this *ServerUser) ToCourseUser(courseID string, escalateServerAdmin bool)
 	if enrolled {
 		courseUser.Role = info.Role
 		courseUser.LMSID = info.LMSID
+		courseUser.Sections = slices.Clone(info.Sections)
 	}
 
 	if escalate {
@@ -247,6 +263,11 @@ func (this *ServerUser) Merge(other *ServerUser) (bool, error) {
 		this.Password = other.Password.Clone()
 	}
 
+	if (other.TwoFactor != nil) && ((this.TwoFactor == nil) || (*this.TwoFactor != *other.TwoFactor)) {
+		changed = true
+		this.TwoFactor = other.TwoFactor.Clone()
+	}
+
 	if other.Tokens != nil {
 		for _, token := range other.Tokens {
 			this.Tokens = append(this.Tokens, token.Clone())
@@ -341,29 +362,47 @@ func (this *ServerUser) CreateRandomToken(name string, source TokenSource) (*Tok
 // Attempt to authenticate this user with the provided text.
 // True will be returned if any of the tokens match.
 func (this *ServerUser) Auth(input string) (bool, error) {
-	var match bool = false
+	token, err := this.AuthToken(input)
+	if err != nil {
+		return false, err
+	}
+
+	return (token != nil), nil
+}
+
+// Attempt to authenticate this user with the provided text.
+// The matching token (which may be the password) will be returned, or nil if no token matches.
+// Expired tokens will never match.
+func (this *ServerUser) AuthToken(input string) (*Token, error) {
+	var match *Token = nil
 	var errs error = nil
 
 	if this.Salt == nil {
-		return false, fmt.Errorf("User '%s' has no salt. Cannot auth.", this.Email)
+		return nil, fmt.Errorf("User '%s' has no salt. Cannot auth.", this.Email)
 	}
 
+	now := timestamp.Now()
+
 	// Make sure that the password and all tokens are checked so we are not vulnerable to timing attacks.
 
 	if this.Password != nil {
 		tokenMatch, err := this.Password.Check(input, *this.Salt)
 		errs = errors.Join(errs, err)
-		match = match || tokenMatch
+		if tokenMatch && (match == nil) {
+			match = this.Password
+		}
 	}
 
 	for _, token := range this.Tokens {
 		tokenMatch, err := token.Check(input, *this.Salt)
 		errs = errors.Join(errs, err)
-		match = match || tokenMatch
+		if tokenMatch && (match == nil) && !token.IsExpired(now) {
+			match = token
+		}
 	}
 
 	if errs != nil {
-		return false, errs
+		return nil, errs
 	}
 
 	return match, nil
@@ -394,6 +433,7 @@ func (this *ServerUser) Clone() *ServerUser {
 		Password:   password,
 		Tokens:     tokens,
 		CourseInfo: courseInfo,
+		TwoFactor:  this.TwoFactor.Clone(),
 	}
 }
 
@@ -453,6 +493,8 @@ func (this *UserCourseInfo) Validate() error {
 		}
 	}
 
+	this.Sections = NormalizeSections(this.Sections)
+
 	return nil
 }
 
@@ -476,13 +518,19 @@ func (this *UserCourseInfo) Merge(other *UserCourseInfo) bool {
 		changed = true
 	}
 
+	if (other.Sections != nil) && !slices.Equal(this.Sections, other.Sections) {
+		this.Sections = slices.Clone(other.Sections)
+		changed = true
+	}
+
 	return changed
 }
 
 func (this *UserCourseInfo) Clone() *UserCourseInfo {
 	return &UserCourseInfo{
-		Role:  this.Role,
-		LMSID: this.LMSID,
+		Role:     this.Role,
+		LMSID:    this.LMSID,
+		Sections: slices.Clone(this.Sections),
 	}
 }
 
@@ -5,6 +5,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/edulinq/autograder/internal/timestamp"
 	"github.com/edulinq/autograder/internal/util"
 )
 
@@ -566,6 +567,14 @@ func TestUserServerUserToCourseUser(test *testing.T) {
 			false,
 		},
 
+		// Sections
+		{
+			setServerUserCourseInfo(baseTestServerUser, map[string]*UserCourseInfo{"course101": &UserCourseInfo{Role: CourseRoleStudent, Sections: []string{"lab-a"}}}),
+			setCourseUserSections(setCourseUserLMSID(baseTestCourseUser, nil), []string{"lab-a"}),
+			"course101",
+			false,
+		},
+
 		// Validation Error
 		{
 			setServerUserCourseInfo(baseTestServerUser, map[string]*UserCourseInfo{"course101": &UserCourseInfo{Role: CourseRoleUnknown}}),
@@ -1162,6 +1171,38 @@ func TestUserServerCreateRandomToken(test *testing.T) {
 	}
 }
 
+func TestUserServerAuthTokenExpired(test *testing.T) {
+	user := baseTestServerUser.Clone()
+
+	token, cleartext, err := user.CreateRandomToken("", TokenSourceUser)
+	if err != nil {
+		test.Fatalf("Failed to create random token: '%v'.", err)
+	}
+
+	input := util.Sha256HexFromString(cleartext)
+
+	match, err := user.AuthToken(input)
+	if err != nil {
+		test.Fatalf("Failed to perform authentication: '%v'.", err)
+	}
+
+	if match != token {
+		test.Fatalf("Did not get the matching token.")
+	}
+
+	expiration := timestamp.Now() - 1
+	token.ExpirationTime = &expiration
+
+	match, err = user.AuthToken(input)
+	if err != nil {
+		test.Fatalf("Failed to perform authentication with an expired token: '%v'.", err)
+	}
+
+	if match != nil {
+		test.Fatalf("Expired token was accepted.")
+	}
+}
+
 func setServerUserEmail(user *ServerUser, email string) *ServerUser {
 	newUser := *user
 	newUser.Email = email
@@ -0,0 +1,14 @@
+package oidc
+
+import (
+	"os"
+	"testing"
+
+	"github.com/edulinq/autograder/internal/config"
+)
+
+func TestMain(suite *testing.M) {
+	config.MustEnableUnitTestingMode()
+
+	os.Exit(suite.Run())
+}
@@ -0,0 +1,144 @@
+package oidc
+
+// A minimal OpenID Connect client that supports the authorization code flow.
+// See: https://openid.net/specs/openid-connect-core-1_0.html#CodeFlowAuth
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/edulinq/autograder/internal/config"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+// A user that has logged in through the provider.
+type Identity struct {
+	Email string
+	Name  string
+}
+
+type tokenResponse struct {
+	IDToken string `json:"id_token"`
+	Error   string `json:"error"`
+}
+
+func IsEnabled() bool {
+	return config.OIDC_ISSUER.Get() != ""
+}
+
+// Start a new login.
+// Returns the provider's URL that the user should be sent to and the login's state.
+// The state should be bound to the user's browser (e.g., with a cookie) and checked when the login is finished,
+// so that a login cannot be finished in a different browser.
+func StartLogin() (string, string, error) {
+	if !IsEnabled() {
+		return "", "", fmt.Errorf("OIDC login is not enabled.")
+	}
+
+	provider, err := getProvider()
+	if err != nil {
+		return "", "", err
+	}
+
+	state, nonce := addPendingLogin()
+
+	authURL, err := url.Parse(provider.AuthorizationEndpoint)
+	if err != nil {
+		return "", "", fmt.Errorf("Failed to parse OIDC authorization endpoint '%s': '%w'.", provider.AuthorizationEndpoint, err)
+	}
+
+	query := authURL.Query()
+	query.Set("response_type", "code")
+	query.Set("scope", "openid email profile")
+	query.Set("client_id", config.OIDC_CLIENT_ID.Get())
+	query.Set("redirect_uri", config.OIDC_REDIRECT_URI.Get())
+	query.Set("state", state)
+	query.Set("nonce", nonce)
+	authURL.RawQuery = query.Encode()
+
+	return authURL.String(), state, nil
+}
+
+// Finish a login using the state and code that the provider sent to the callback.
+// The returned identity will always have an email.
+func FinishLogin(state string, code string) (*Identity, error) {
+	if !IsEnabled() {
+		return nil, fmt.Errorf("OIDC login is not enabled.")
+	}
+
+	nonce, ok := removePendingLogin(state)
+	if !ok {
+		return nil, fmt.Errorf("Unknown or expired OIDC login state.")
+	}
+
+	if code == "" {
+		return nil, fmt.Errorf("No OIDC authorization code provided.")
+	}
+
+	provider, err := getProvider()
+	if err != nil {
+		return nil, err
+	}
+
+	rawIDToken, err := exchangeCode(provider, code)
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := fetchKeys(provider)
+	if err != nil {
+		return nil, err
+	}
+
+	claims, err := verifyIDToken(rawIDToken, keys)
+	if err != nil {
+		return nil, err
+	}
+
+	err = claims.validate(provider.Issuer, config.OIDC_CLIENT_ID.Get(), nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Identity{
+		Email: claims.Email,
+		Name:  claims.Name,
+	}, nil
+}
+
+// Exchange an authorization code for a (still unverified) ID token.
+func exchangeCode(provider *providerMetadata, code string) (string, error) {
+	form := map[string]string{
+		"grant_type":   "authorization_code",
+		"code":         code,
+		"redirect_uri": config.OIDC_REDIRECT_URI.Get(),
+	}
+
+	credentials := url.QueryEscape(config.OIDC_CLIENT_ID.Get()) + ":" + url.QueryEscape(config.OIDC_CLIENT_SECRET.Get())
+	headers := map[string][]string{
+		"Accept":        []string{"application/json"},
+		"Authorization": []string{"Basic " + util.Base64Encode([]byte(credentials))},
+	}
+
+	body, _, err := util.PostWithHeaders(provider.TokenEndpoint, form, headers)
+	if err != nil {
+		return "", fmt.Errorf("Failed to exchange OIDC authorization code: '%w'.", err)
+	}
+
+	var response tokenResponse
+	err = util.JSONFromString(body, &response)
+	if err != nil {
+		return "", fmt.Errorf("Failed to parse OIDC token response: '%w'.", err)
+	}
+
+	if response.Error != "" {
+		return "", fmt.Errorf("OIDC provider returned an error for the token request: '%s'.", response.Error)
+	}
+
+	if strings.TrimSpace(response.IDToken) == "" {
+		return "", fmt.Errorf("OIDC token response does not contain an ID token.")
+	}
+
+	return response.IDToken, nil
+}
@@ -0,0 +1,163 @@
+package oidc
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/edulinq/autograder/internal/timestamp"
+)
+
+func TestLoginBase(test *testing.T) {
+	issuer := NewTestIssuer()
+	defer issuer.Close()
+
+	authURL, startState, err := StartLogin()
+	if err != nil {
+		test.Fatalf("Failed to start login: '%v'.", err)
+	}
+
+	if !strings.HasPrefix(authURL, issuer.Server.URL+"/authorize?") {
+		test.Fatalf("Unexpected authorization URL: '%s'.", authURL)
+	}
+
+	state, code, err := issuer.Login(authURL, "course-student@test.edulinq.org", "course-student")
+	if err != nil {
+		test.Fatalf("Failed to log in with the issuer: '%v'.", err)
+	}
+
+	if startState != state {
+		test.Fatalf("Unexpected state. Expected: '%s', Actual: '%s'.", startState, state)
+	}
+
+	identity, err := FinishLogin(state, code)
+	if err != nil {
+		test.Fatalf("Failed to finish login: '%v'.", err)
+	}
+
+	expected := Identity{"course-student@test.edulinq.org", "course-student"}
+	if expected != *identity {
+		test.Fatalf("Unexpected identity. Expected: '%+v', Actual: '%+v'.", expected, *identity)
+	}
+
+	// A state cannot be used twice.
+	_, err = FinishLogin(state, code)
+	if err == nil {
+		test.Fatalf("Did not get an error when reusing a login state.")
+	}
+}
+
+func TestLoginFailures(test *testing.T) {
+	issuer := NewTestIssuer()
+	defer issuer.Close()
+
+	now := timestamp.Now().ToMSecs() / 1000
+
+	testCases := []struct {
+		claims         map[string]any
+		badState       bool
+		badCode        bool
+		errorSubstring string
+	}{
+		{map[string]any{"email": "course-student@test.edulinq.org"}, true, false, "Unknown or expired OIDC login state"},
+		{map[string]any{"email": "course-student@test.edulinq.org"}, false, true, "Failed to exchange OIDC authorization code"},
+
+		{map[string]any{"email": "course-student@test.edulinq.org", "iss": "http://zzz"}, false, false, "wrong issuer"},
+		{map[string]any{"email": "course-student@test.edulinq.org", "aud": "zzz"}, false, false, "not issued for this client"},
+		{map[string]any{"email": "course-student@test.edulinq.org", "aud": []string{"zzz", TEST_CLIENT_ID}}, false, false, ""},
+		{map[string]any{"email": "course-student@test.edulinq.org", "exp": now - 3600}, false, false, "expired"},
+		{map[string]any{"email": "course-student@test.edulinq.org", "iat": now + 3600}, false, false, "issued in the future"},
+		{map[string]any{"email": "course-student@test.edulinq.org", "nonce": "zzz"}, false, false, "wrong nonce"},
+		{map[string]any{"email": "course-student@test.edulinq.org", "email_verified": false}, false, false, "has not been verified"},
+		{map[string]any{"name": "course-student"}, false, false, "does not have an email"},
+	}
+
+	for i, testCase := range testCases {
+		authURL, _, err := StartLogin()
+		if err != nil {
+			test.Errorf("Case %d: Failed to start login: '%v'.", i, err)
+			continue
+		}
+
+		state, code, err := issuer.LoginWithClaims(authURL, testCase.claims)
+		if err != nil {
+			test.Errorf("Case %d: Failed to log in with the issuer: '%v'.", i, err)
+			continue
+		}
+
+		if testCase.badState {
+			state = "zzz"
+		}
+
+		if testCase.badCode {
+			code = "zzz"
+		}
+
+		_, err = FinishLogin(state, code)
+		if err == nil {
+			if testCase.errorSubstring != "" {
+				test.Errorf("Case %d: Did not get an expected error.", i)
+			}
+
+			continue
+		}
+
+		if testCase.errorSubstring == "" {
+			test.Errorf("Case %d: Got an unexpected error: '%v'.", i, err)
+			continue
+		}
+
+		if !strings.Contains(err.Error(), testCase.errorSubstring) {
+			test.Errorf("Case %d: Unexpected error. Expected substring: '%s', Actual: '%v'.", i, testCase.errorSubstring, err)
+			continue
+		}
+	}
+}
+
+func TestVerifyIDTokenSignature(test *testing.T) {
+	issuer := NewTestIssuer()
+	defer issuer.Close()
+
+	token, err := issuer.sign(map[string]any{"email": "course-student@test.edulinq.org"})
+	if err != nil {
+		test.Fatalf("Failed to sign token: '%v'.", err)
+	}
+
+	otherToken, err := issuer.sign(map[string]any{"email": "course-admin@test.edulinq.org"})
+	if err != nil {
+		test.Fatalf("Failed to sign other token: '%v'.", err)
+	}
+
+	provider, err := getProvider()
+	if err != nil {
+		test.Fatalf("Failed to get provider: '%v'.", err)
+	}
+
+	keys, err := fetchKeys(provider)
+	if err != nil {
+		test.Fatalf("Failed to fetch keys: '%v'.", err)
+	}
+
+	claims, err := verifyIDToken(token, keys)
+	if err != nil {
+		test.Fatalf("Failed to verify token: '%v'.", err)
+	}
+
+	if claims.Email != "course-student@test.edulinq.org" {
+		test.Fatalf("Unexpected email: '%s'.", claims.Email)
+	}
+
+	// Swap in the claims from another token.
+	parts := strings.Split(token, ".")
+	otherParts := strings.Split(otherToken, ".")
+	tamperedToken := strings.Join([]string{parts[0], otherParts[1], parts[2]}, ".")
+
+	_, err = verifyIDToken(tamperedToken, keys)
+	if err == nil {
+		test.Fatalf("Did not get an error on a tampered token.")
+	}
+
+	_, err = verifyIDToken("a.b", keys)
+	if err == nil {
+		test.Fatalf("Did not get an error on a malformed token.")
+	}
+}
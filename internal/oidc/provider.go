@@ -0,0 +1,78 @@
+package oidc
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/edulinq/autograder/internal/config"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+const DISCOVERY_PATH = "/.well-known/openid-configuration"
+
+// The parts of a provider's discovery document that we use.
+// See: https://openid.net/specs/openid-connect-discovery-1_0.html#ProviderMetadata
+type providerMetadata struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+var (
+	providerLock sync.Mutex
+	providers    map[string]*providerMetadata = make(map[string]*providerMetadata)
+)
+
+// Get the metadata for the configured issuer.
+// Metadata is cached after it is successfully fetched.
+func getProvider() (*providerMetadata, error) {
+	issuer := strings.TrimSuffix(config.OIDC_ISSUER.Get(), "/")
+
+	providerLock.Lock()
+	defer providerLock.Unlock()
+
+	provider, ok := providers[issuer]
+	if ok {
+		return provider, nil
+	}
+
+	body, err := util.Get(issuer + DISCOVERY_PATH)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to fetch OIDC discovery document for issuer '%s': '%w'.", issuer, err)
+	}
+
+	provider = &providerMetadata{}
+	err = util.JSONFromString(body, provider)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse OIDC discovery document for issuer '%s': '%w'.", issuer, err)
+	}
+
+	if strings.TrimSuffix(provider.Issuer, "/") != issuer {
+		return nil, fmt.Errorf("OIDC discovery document has a different issuer. Expected: '%s', Actual: '%s'.", issuer, provider.Issuer)
+	}
+
+	if (provider.AuthorizationEndpoint == "") || (provider.TokenEndpoint == "") || (provider.JWKSURI == "") {
+		return nil, fmt.Errorf("OIDC discovery document for issuer '%s' is missing a required endpoint.", issuer)
+	}
+
+	providers[issuer] = provider
+
+	return provider, nil
+}
+
+func fetchKeys(provider *providerMetadata) (*jsonWebKeySet, error) {
+	body, err := util.Get(provider.JWKSURI)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to fetch OIDC signing keys: '%w'.", err)
+	}
+
+	var keys jsonWebKeySet
+	err = util.JSONFromString(body, &keys)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse OIDC signing keys: '%w'.", err)
+	}
+
+	return &keys, nil
+}
@@ -0,0 +1,70 @@
+package oidc
+
+// Logins that have been started, but not yet finished.
+// Each login gets a random state (to match the callback to the login)
+// and a random nonce (to match the ID token to the login).
+
+import (
+	"sync"
+
+	"github.com/edulinq/autograder/internal/timestamp"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+// The time (in MS) a user has to finish logging in with the provider.
+const LOGIN_TIMEOUT_MS = 10 * 60 * 1000
+
+type pendingLogin struct {
+	Nonce      string
+	Expiration timestamp.Timestamp
+}
+
+var (
+	pendingLoginsLock sync.Mutex
+	pendingLogins     map[string]*pendingLogin = make(map[string]*pendingLogin)
+)
+
+// Returns the state and nonce for the new login.
+func addPendingLogin() (string, string) {
+	pendingLoginsLock.Lock()
+	defer pendingLoginsLock.Unlock()
+
+	now := timestamp.Now()
+
+	// Clear out any expired logins.
+	for state, login := range pendingLogins {
+		if login.Expiration < now {
+			delete(pendingLogins, state)
+		}
+	}
+
+	state := util.UUID()
+	nonce := util.UUID()
+
+	pendingLogins[state] = &pendingLogin{
+		Nonce:      nonce,
+		Expiration: now + timestamp.FromMSecs(LOGIN_TIMEOUT_MS),
+	}
+
+	return state, nonce
+}
+
+// Remove a pending login (a state can only be used once).
+// Returns the login's nonce and true if the login exists and has not expired.
+func removePendingLogin(state string) (string, bool) {
+	pendingLoginsLock.Lock()
+	defer pendingLoginsLock.Unlock()
+
+	login, ok := pendingLogins[state]
+	if !ok {
+		return "", false
+	}
+
+	delete(pendingLogins, state)
+
+	if login.Expiration < timestamp.Now() {
+		return "", false
+	}
+
+	return login.Nonce, true
+}
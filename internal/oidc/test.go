@@ -0,0 +1,231 @@
+package oidc
+
+// A local OIDC provider that can be used for testing.
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+
+	"github.com/edulinq/autograder/internal/config"
+	"github.com/edulinq/autograder/internal/timestamp"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+const (
+	TEST_CLIENT_ID     = "autograder-test-client"
+	TEST_CLIENT_SECRET = "autograder-test-secret"
+	TEST_REDIRECT_URI  = "http://localhost/oidc/callback"
+	TEST_KEY_ID        = "test-key"
+)
+
+type TestIssuer struct {
+	Server *httptest.Server
+
+	key *rsa.PrivateKey
+
+	// Claims for each authorization code that has not been exchanged.
+	codes     map[string]map[string]any
+	codesLock sync.Mutex
+}
+
+// Start a new test issuer and configure the server to use it.
+// Callers should Close() the issuer when done.
+func NewTestIssuer() *TestIssuer {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		panic(fmt.Sprintf("Failed to generate test OIDC key: '%v'.", err))
+	}
+
+	issuer := &TestIssuer{
+		key:   key,
+		codes: make(map[string]map[string]any),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET "+DISCOVERY_PATH, issuer.handleDiscovery)
+	mux.HandleFunc("GET /keys", issuer.handleKeys)
+	mux.HandleFunc("POST /token", issuer.handleToken)
+
+	issuer.Server = httptest.NewServer(mux)
+
+	config.OIDC_ISSUER.Set(issuer.Server.URL)
+	config.OIDC_CLIENT_ID.Set(TEST_CLIENT_ID)
+	config.OIDC_CLIENT_SECRET.Set(TEST_CLIENT_SECRET)
+	config.OIDC_REDIRECT_URI.Set(TEST_REDIRECT_URI)
+
+	return issuer
+}
+
+// Stop the issuer and disable OIDC login.
+func (this *TestIssuer) Close() {
+	this.Server.Close()
+
+	config.OIDC_ISSUER.Set("")
+	config.OIDC_CLIENT_ID.Set("")
+	config.OIDC_CLIENT_SECRET.Set("")
+	config.OIDC_REDIRECT_URI.Set("")
+	config.OIDC_CREATE_USERS.Set(false)
+}
+
+// Log in a user at the given authorization URL (from StartLogin()).
+// Returns the state and code that the provider would send to the callback.
+func (this *TestIssuer) Login(authURL string, email string, name string) (string, string, error) {
+	return this.LoginWithClaims(authURL, map[string]any{
+		"email":          email,
+		"email_verified": true,
+		"name":           name,
+	})
+}
+
+// Like Login(), but with specific claims.
+// Standard claims (e.g., "iss", "aud", "nonce") will be filled in if not present.
+func (this *TestIssuer) LoginWithClaims(authURL string, claims map[string]any) (string, string, error) {
+	parsedURL, err := url.Parse(authURL)
+	if err != nil {
+		return "", "", fmt.Errorf("Failed to parse authorization URL: '%w'.", err)
+	}
+
+	query := parsedURL.Query()
+
+	now := timestamp.Now().ToMSecs() / 1000
+	defaults := map[string]any{
+		"iss":   this.Server.URL,
+		"sub":   util.UUID(),
+		"aud":   query.Get("client_id"),
+		"exp":   now + 60,
+		"iat":   now,
+		"nonce": query.Get("nonce"),
+	}
+
+	for key, value := range defaults {
+		_, ok := claims[key]
+		if !ok {
+			claims[key] = value
+		}
+	}
+
+	code := util.UUID()
+
+	this.codesLock.Lock()
+	defer this.codesLock.Unlock()
+
+	this.codes[code] = claims
+
+	return query.Get("state"), code, nil
+}
+
+func (this *TestIssuer) handleDiscovery(response http.ResponseWriter, request *http.Request) {
+	metadata := providerMetadata{
+		Issuer:                this.Server.URL,
+		AuthorizationEndpoint: this.Server.URL + "/authorize",
+		TokenEndpoint:         this.Server.URL + "/token",
+		JWKSURI:               this.Server.URL + "/keys",
+	}
+
+	writeJSON(response, http.StatusOK, metadata)
+}
+
+func (this *TestIssuer) handleKeys(response http.ResponseWriter, request *http.Request) {
+	keys := jsonWebKeySet{
+		Keys: []*jsonWebKey{
+			&jsonWebKey{
+				KeyType:   "RSA",
+				KeyID:     TEST_KEY_ID,
+				Use:       "sig",
+				Algorithm: SIGNING_ALGORITHM,
+				Modulus:   base64.RawURLEncoding.EncodeToString(this.key.PublicKey.N.Bytes()),
+				Exponent:  base64.RawURLEncoding.EncodeToString(big.NewInt(int64(this.key.PublicKey.E)).Bytes()),
+			},
+		},
+	}
+
+	writeJSON(response, http.StatusOK, keys)
+}
+
+func (this *TestIssuer) handleToken(response http.ResponseWriter, request *http.Request) {
+	clientID, clientSecret, ok := request.BasicAuth()
+	if !ok {
+		writeJSON(response, http.StatusUnauthorized, tokenResponse{Error: "invalid_client"})
+		return
+	}
+
+	clientID, _ = url.QueryUnescape(clientID)
+	clientSecret, _ = url.QueryUnescape(clientSecret)
+
+	if (clientID != TEST_CLIENT_ID) || (clientSecret != TEST_CLIENT_SECRET) {
+		writeJSON(response, http.StatusUnauthorized, tokenResponse{Error: "invalid_client"})
+		return
+	}
+
+	if (request.PostFormValue("grant_type") != "authorization_code") || (request.PostFormValue("redirect_uri") != TEST_REDIRECT_URI) {
+		writeJSON(response, http.StatusBadRequest, tokenResponse{Error: "invalid_request"})
+		return
+	}
+
+	code := request.PostFormValue("code")
+
+	this.codesLock.Lock()
+	claims, ok := this.codes[code]
+	delete(this.codes, code)
+	this.codesLock.Unlock()
+
+	if !ok {
+		writeJSON(response, http.StatusBadRequest, tokenResponse{Error: "invalid_grant"})
+		return
+	}
+
+	idToken, err := this.sign(claims)
+	if err != nil {
+		writeJSON(response, http.StatusInternalServerError, tokenResponse{Error: err.Error()})
+		return
+	}
+
+	writeJSON(response, http.StatusOK, map[string]any{
+		"access_token": util.UUID(),
+		"token_type":   "Bearer",
+		"id_token":     idToken,
+	})
+}
+
+func (this *TestIssuer) sign(claims map[string]any) (string, error) {
+	header := tokenHeader{
+		Algorithm: SIGNING_ALGORITHM,
+		KeyID:     TEST_KEY_ID,
+	}
+
+	rawHeader, err := json.Marshal(header)
+	if err != nil {
+		return "", err
+	}
+
+	rawClaims, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	payload := base64.RawURLEncoding.EncodeToString(rawHeader) + "." + base64.RawURLEncoding.EncodeToString(rawClaims)
+	digest := sha256.Sum256([]byte(payload))
+
+	signature, err := rsa.SignPKCS1v15(rand.Reader, this.key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", err
+	}
+
+	return payload + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+func writeJSON(response http.ResponseWriter, status int, content any) {
+	response.Header().Set("Content-Type", "application/json")
+	response.WriteHeader(status)
+	json.NewEncoder(response).Encode(content)
+}
@@ -0,0 +1,226 @@
+package oidc
+
+// Verification for ID tokens (JSON Web Tokens).
+// Only RS256 signatures are supported, which all OIDC providers must support.
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"slices"
+	"strings"
+
+	"github.com/edulinq/autograder/internal/timestamp"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+const SIGNING_ALGORITHM = "RS256"
+
+// Allowed difference (in seconds) between our clock and the provider's.
+const CLOCK_SKEW_SECS = 60
+
+type jsonWebKey struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid,omitempty"`
+	Use       string `json:"use,omitempty"`
+	Algorithm string `json:"alg,omitempty"`
+	Modulus   string `json:"n"`
+	Exponent  string `json:"e"`
+}
+
+type jsonWebKeySet struct {
+	Keys []*jsonWebKey `json:"keys"`
+}
+
+type tokenHeader struct {
+	Algorithm string `json:"alg"`
+	KeyID     string `json:"kid"`
+}
+
+type idTokenClaims struct {
+	Issuer        string   `json:"iss"`
+	Subject       string   `json:"sub"`
+	Audience      audience `json:"aud"`
+	Expiration    int64    `json:"exp"`
+	IssuedAt      int64    `json:"iat"`
+	Nonce         string   `json:"nonce"`
+	Email         string   `json:"email"`
+	EmailVerified *bool    `json:"email_verified"`
+	Name          string   `json:"name"`
+}
+
+// The audience of a token can either be a single string or a list of strings.
+type audience []string
+
+func (this *audience) UnmarshalJSON(data []byte) error {
+	var single string
+	err := json.Unmarshal(data, &single)
+	if err == nil {
+		*this = audience{single}
+		return nil
+	}
+
+	var multiple []string
+	err = json.Unmarshal(data, &multiple)
+	if err != nil {
+		return fmt.Errorf("Audience is not a string or list of strings: '%w'.", err)
+	}
+
+	*this = audience(multiple)
+	return nil
+}
+
+func (this *jsonWebKey) toPublicKey() (*rsa.PublicKey, error) {
+	if this.KeyType != "RSA" {
+		return nil, fmt.Errorf("Unsupported key type: '%s'.", this.KeyType)
+	}
+
+	rawModulus, err := base64.RawURLEncoding.DecodeString(this.Modulus)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to decode key modulus: '%w'.", err)
+	}
+
+	rawExponent, err := base64.RawURLEncoding.DecodeString(this.Exponent)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to decode key exponent: '%w'.", err)
+	}
+
+	exponent := new(big.Int).SetBytes(rawExponent)
+	if !exponent.IsInt64() || (exponent.Int64() > (1<<31 - 1)) {
+		return nil, fmt.Errorf("Key exponent is too large.")
+	}
+
+	return &rsa.PublicKey{
+		N: new(big.Int).SetBytes(rawModulus),
+		E: int(exponent.Int64()),
+	}, nil
+}
+
+// Find the key to verify a token with.
+// If the token does not specify a key, then the set must contain a single usable key.
+func (this *jsonWebKeySet) getKey(keyID string) (*jsonWebKey, error) {
+	candidates := make([]*jsonWebKey, 0, len(this.Keys))
+	for _, key := range this.Keys {
+		if (key.Use != "") && (key.Use != "sig") {
+			continue
+		}
+
+		if (key.Algorithm != "") && (key.Algorithm != SIGNING_ALGORITHM) {
+			continue
+		}
+
+		if (keyID != "") && (key.KeyID != keyID) {
+			continue
+		}
+
+		candidates = append(candidates, key)
+	}
+
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("Could not find a signing key with ID '%s'.", keyID)
+	}
+
+	if len(candidates) > 1 {
+		return nil, fmt.Errorf("Found %d possible signing keys for ID '%s'.", len(candidates), keyID)
+	}
+
+	return candidates[0], nil
+}
+
+// Check the signature of an ID token and parse its claims.
+// The claims themselves are not validated.
+func verifyIDToken(rawToken string, keys *jsonWebKeySet) (*idTokenClaims, error) {
+	parts := strings.Split(rawToken, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("ID token is malformed, expected 3 parts, found %d.", len(parts))
+	}
+
+	var header tokenHeader
+	err := decodeTokenPart(parts[0], &header)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to decode ID token header: '%w'.", err)
+	}
+
+	if header.Algorithm != SIGNING_ALGORITHM {
+		return nil, fmt.Errorf("Unsupported ID token signing algorithm: '%s'.", header.Algorithm)
+	}
+
+	key, err := keys.getKey(header.KeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	publicKey, err := key.toPublicKey()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to load signing key '%s': '%w'.", key.KeyID, err)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("Failed to decode ID token signature: '%w'.", err)
+	}
+
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+
+	err = rsa.VerifyPKCS1v15(publicKey, crypto.SHA256, digest[:], signature)
+	if err != nil {
+		return nil, fmt.Errorf("ID token has an invalid signature: '%w'.", err)
+	}
+
+	var claims idTokenClaims
+	err = decodeTokenPart(parts[1], &claims)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to decode ID token claims: '%w'.", err)
+	}
+
+	return &claims, nil
+}
+
+// Check that these claims are meant for us and still current.
+// See: https://openid.net/specs/openid-connect-core-1_0.html#IDTokenValidation
+func (this *idTokenClaims) validate(issuer string, clientID string, nonce string) error {
+	if strings.TrimSuffix(this.Issuer, "/") != strings.TrimSuffix(issuer, "/") {
+		return fmt.Errorf("ID token has the wrong issuer. Expected: '%s', Actual: '%s'.", issuer, this.Issuer)
+	}
+
+	if !slices.Contains(this.Audience, clientID) {
+		return fmt.Errorf("ID token was not issued for this client.")
+	}
+
+	now := timestamp.Now().ToMSecs() / 1000
+
+	if (this.Expiration + CLOCK_SKEW_SECS) < now {
+		return fmt.Errorf("ID token has expired.")
+	}
+
+	if (this.IssuedAt - CLOCK_SKEW_SECS) > now {
+		return fmt.Errorf("ID token was issued in the future.")
+	}
+
+	if this.Nonce != nonce {
+		return fmt.Errorf("ID token has the wrong nonce.")
+	}
+
+	if strings.TrimSpace(this.Email) == "" {
+		return fmt.Errorf("ID token does not have an email. Make sure the 'email' scope is allowed.")
+	}
+
+	if (this.EmailVerified != nil) && !*this.EmailVerified {
+		return fmt.Errorf("ID token email '%s' has not been verified by the provider.", this.Email)
+	}
+
+	return nil
+}
+
+func decodeTokenPart(part string, target any) error {
+	data, err := base64.RawURLEncoding.DecodeString(part)
+	if err != nil {
+		return err
+	}
+
+	return util.JSONFromString(string(data), target)
+}
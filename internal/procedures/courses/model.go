@@ -35,6 +35,9 @@ type CourseUpsertResult struct {
 	Created bool `json:"created"`
 	Updated bool `json:"updated"`
 
+	// Users whose sections were set from the course config.
+	SectionUsers []string `json:"section-users,omitempty"`
+
 	LMSSyncResult           *model.LMSSyncResult `json:"lms-sync-result"`
 	BuiltAssignmentImages   []string             `json:"built-assignment-images"`
 	AssignmentTemplateFiles map[string][]string  `json:"assignment-template-files,omitempty"`
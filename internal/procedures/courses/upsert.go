@@ -3,11 +3,13 @@ package courses
 import (
 	"fmt"
 	"path/filepath"
+	"slices"
 
 	"github.com/edulinq/autograder/internal/common"
 	"github.com/edulinq/autograder/internal/db"
 	"github.com/edulinq/autograder/internal/lms/lmssync"
 	"github.com/edulinq/autograder/internal/lockmanager"
+	"github.com/edulinq/autograder/internal/log"
 	"github.com/edulinq/autograder/internal/model"
 	"github.com/edulinq/autograder/internal/util"
 )
@@ -73,6 +75,13 @@ func upsertFromConfigPath(path string, options CourseUpsertOptions) (*CourseUpse
 		}
 	}
 
+	// Sync Sections
+	// This is done before the LMS sync so that sections from the LMS take precedence.
+	result.SectionUsers, err = syncConfigSections(course, options, result)
+	if err != nil {
+		return nil, result.CourseID, fmt.Errorf("Failed to sync course sections: '%w'.", err)
+	}
+
 	// Sync LMS
 	if !options.SkipLMSSync {
 		err = syncLMS(course, options, result)
@@ -133,6 +142,55 @@ func syncLMS(course *model.Course, options CourseUpsertOptions, result *CourseUp
 	return nil
 }
 
+// Set the sections of enrolled users from the course config.
+// Only users listed in the config are changed (so sections from other sources are kept for everyone else),
+// and listed users that are not enrolled in the course are skipped.
+// Returns the emails of the users whose sections were changed (or would be changed in a dry run).
+func syncConfigSections(course *model.Course, options CourseUpsertOptions, result *CourseUpsertResult) ([]string, error) {
+	userSections := course.GetUserSections()
+	if len(userSections) == 0 {
+		return nil, nil
+	}
+
+	// Dry runs use a modified ID, but users are enrolled in the original course.
+	courseID := result.CourseID
+
+	users, err := db.GetServerUsers()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get users: '%w'.", err)
+	}
+
+	updatedUsers := make(map[string]*model.ServerUser)
+	for email, sections := range userSections {
+		user := users[email]
+		if (user == nil) || !user.IsEnrolled(courseID) {
+			log.Warn("User listed in course sections is not enrolled in the course.", log.NewCourseAttr(courseID), log.NewUserAttr(email))
+			continue
+		}
+
+		info := user.CourseInfo[courseID]
+		if slices.Equal(info.Sections, sections) {
+			continue
+		}
+
+		info.Sections = sections
+		updatedUsers[email] = user
+	}
+
+	emails := util.GetSortedKeys(updatedUsers)
+
+	if options.DryRun || (len(updatedUsers) == 0) {
+		return emails, nil
+	}
+
+	err = db.UpsertUsers(updatedUsers)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to save user sections: '%w'.", err)
+	}
+
+	return emails, nil
+}
+
 // Sync the courses source to the canonical source directory for the source.
 // If the source was updated, return the new course represented by that source.
 func syncSource(course *model.Course, options CourseUpsertOptions) (*model.Course, error) {
@@ -365,3 +365,90 @@ var standardTemplateFiles map[string][]string = map[string][]string{
 var standardDryRunBuildImages []string = []string{
 	"autograder.__autograder_dryrun__course101.hw0",
 }
+
+func TestSyncConfigSections(test *testing.T) {
+	defer db.ResetForTesting()
+
+	course := db.MustGetTestCourse()
+	course.Sections = map[string][]string{
+		"lab-a": []string{"course-student@test.edulinq.org", "course-grader@test.edulinq.org"},
+		"lab-b": []string{"course-student@test.edulinq.org", "server-user@test.edulinq.org"},
+	}
+
+	err := course.Validate()
+	if err != nil {
+		test.Fatalf("Failed to validate course: '%v'.", err)
+	}
+
+	testCases := []struct {
+		dryRun           bool
+		expectedEmails   []string
+		expectedSections map[string][]string
+	}{
+		// A dry run does not change any users.
+		{
+			true,
+			[]string{"course-grader@test.edulinq.org", "course-student@test.edulinq.org"},
+			map[string][]string{
+				"course-grader@test.edulinq.org":  nil,
+				"course-student@test.edulinq.org": nil,
+			},
+		},
+		// Users that are not enrolled (server-user) are skipped.
+		{
+			false,
+			[]string{"course-grader@test.edulinq.org", "course-student@test.edulinq.org"},
+			map[string][]string{
+				"course-grader@test.edulinq.org":  []string{"lab-a"},
+				"course-student@test.edulinq.org": []string{"lab-a", "lab-b"},
+				"course-other@test.edulinq.org":   nil,
+			},
+		},
+		// Users that already have their sections are not changed again.
+		{
+			false,
+			[]string{},
+			map[string][]string{
+				"course-grader@test.edulinq.org":  []string{"lab-a"},
+				"course-student@test.edulinq.org": []string{"lab-a", "lab-b"},
+			},
+		},
+	}
+
+	for i, testCase := range testCases {
+		options := CourseUpsertOptions{
+			CourseUpsertPublicOptions: CourseUpsertPublicOptions{
+				DryRun: testCase.dryRun,
+			},
+		}
+
+		result := &CourseUpsertResult{
+			CourseID: course.ID,
+		}
+
+		emails, err := syncConfigSections(course, options, result)
+		if err != nil {
+			test.Errorf("Case %d: Failed to sync sections: '%v'.", i, err)
+			continue
+		}
+
+		if !reflect.DeepEqual(testCase.expectedEmails, emails) {
+			test.Errorf("Case %d: Unexpected updated users. Expected: '%v', Actual: '%v'.", i, testCase.expectedEmails, emails)
+			continue
+		}
+
+		for email, expectedSections := range testCase.expectedSections {
+			user := db.MustGetServerUser(email)
+
+			actualSections := user.CourseInfo[course.ID].Sections
+			if !reflect.DeepEqual(expectedSections, actualSections) {
+				test.Errorf("Case %d: Unexpected sections for '%s'. Expected: '%v', Actual: '%v'.", i, email, expectedSections, actualSections)
+			}
+		}
+	}
+
+	user := db.MustGetServerUser("server-user@test.edulinq.org")
+	if user.IsEnrolled(course.ID) {
+		test.Fatalf("Unenrolled user was enrolled by syncing sections.")
+	}
+}
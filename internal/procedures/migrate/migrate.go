@@ -0,0 +1,311 @@
+package migrate
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/log"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/stats"
+)
+
+type MigrateOptions struct {
+	// Clear the target database before migrating.
+	// Without this, the target must be empty (except for the root user).
+	Overwrite bool
+}
+
+type MigrateResult struct {
+	Source DatabaseSummary `json:"source"`
+	Target DatabaseSummary `json:"target"`
+}
+
+// Copy all data from one database into another, and then verify the copy.
+// Every item is counted and checksummed as it is read from the source,
+// and then the target is re-read and summarized the same way.
+// A non-nil result will be returned if the migration ran (even if verification failed).
+func Migrate(source db.Backend, target db.Backend, options MigrateOptions) (*MigrateResult, error) {
+	err := target.EnsureTables()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to ensure target tables: '%w'.", err)
+	}
+
+	if options.Overwrite {
+		err = target.Clear()
+		if err != nil {
+			return nil, fmt.Errorf("Failed to clear target database: '%w'.", err)
+		}
+	} else {
+		err = checkEmpty(target)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	copier := &copier{
+		target:     target,
+		summarizer: newSummarizer(),
+	}
+
+	err = walkBackend(source, copier)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to migrate data: '%w'.", err)
+	}
+
+	targetSummarizer := newSummarizer()
+	err = walkBackend(target, targetSummarizer)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read back migrated data: '%w'.", err)
+	}
+
+	result := &MigrateResult{
+		Source: copier.summarizer.summary(),
+		Target: targetSummarizer.summary(),
+	}
+
+	return result, errors.Join(result.Verify(), verifyAuditChain(target))
+}
+
+// Check that the source and target summaries match.
+func (this *MigrateResult) Verify() error {
+	var errs error = nil
+
+	for _, dataType := range DATA_TYPES {
+		source := this.Source[dataType]
+		target := this.Target[dataType]
+
+		if source.Count != target.Count {
+			errs = errors.Join(errs, fmt.Errorf("Count mismatch for %s. Source: %d, Target: %d.", dataType, source.Count, target.Count))
+		} else if source.Checksum != target.Checksum {
+			errs = errors.Join(errs, fmt.Errorf("Checksum mismatch for %s. Source: '%s', Target: '%s'.", dataType, source.Checksum, target.Checksum))
+		}
+	}
+
+	return errs
+}
+
+// Check that the audit trail in the target is still an unbroken chain
+// (the checksums only check that the same records were copied).
+func verifyAuditChain(target db.Backend) error {
+	records, err := target.GetAuditRecords(model.AuditQuery{})
+	if err != nil {
+		return fmt.Errorf("Failed to get target audit records: '%w'.", err)
+	}
+
+	err = model.VerifyAuditChain(records)
+	if err != nil {
+		return fmt.Errorf("Migrated audit trail is not a valid chain: '%w'.", err)
+	}
+
+	return nil
+}
+
+// A target database is considered empty if it has no courses, no users (other than root), and no audit records.
+func checkEmpty(target db.Backend) error {
+	courses, err := target.GetCourses()
+	if err != nil {
+		return fmt.Errorf("Failed to get target courses: '%w'.", err)
+	}
+
+	if len(courses) > 0 {
+		return fmt.Errorf("Target database is not empty, it has %d courses.", len(courses))
+	}
+
+	users, err := target.GetServerUsers()
+	if err != nil {
+		return fmt.Errorf("Failed to get target users: '%w'.", err)
+	}
+
+	delete(users, model.RootUserEmail)
+	if len(users) > 0 {
+		return fmt.Errorf("Target database is not empty, it has %d users.", len(users))
+	}
+
+	// Migrated audit records cannot be chained after existing ones.
+	lastAuditRecord, err := target.GetLastAuditRecord()
+	if err != nil {
+		return fmt.Errorf("Failed to get target audit records: '%w'.", err)
+	}
+
+	if lastAuditRecord != nil {
+		return fmt.Errorf("Target database is not empty, it has %d audit records.", lastAuditRecord.Index)
+	}
+
+	return nil
+}
+
+// Writes all visited data into the target (while summarizing it).
+type copier struct {
+	target     db.Backend
+	summarizer *summarizer
+}
+
+func (this *copier) visitUsers(users map[string]*model.ServerUser) error {
+	err := this.summarizer.visitUsers(users)
+	if err != nil {
+		return err
+	}
+
+	return this.target.UpsertUsers(users)
+}
+
+func (this *copier) visitCourse(course *model.Course) error {
+	err := this.summarizer.visitCourse(course)
+	if err != nil {
+		return err
+	}
+
+	return this.target.SaveCourse(course)
+}
+
+func (this *copier) visitSubmissions(course *model.Course, submissions []*model.GradingResult) error {
+	err := this.summarizer.visitSubmissions(course, submissions)
+	if err != nil {
+		return err
+	}
+
+	return this.target.SaveSubmissions(course, submissions)
+}
+
+func (this *copier) visitAssignmentGroups(assignment *model.Assignment, groups map[string]*model.AssignmentGroup) error {
+	err := this.summarizer.visitAssignmentGroups(assignment, groups)
+	if err != nil {
+		return err
+	}
+
+	if len(groups) == 0 {
+		return nil
+	}
+
+	return this.target.UpsertAssignmentGroups(assignment, groups)
+}
+
+func (this *copier) visitAssignmentExtensions(assignment *model.Assignment, extensions map[string]*model.AssignmentExtension) error {
+	err := this.summarizer.visitAssignmentExtensions(assignment, extensions)
+	if err != nil {
+		return err
+	}
+
+	if len(extensions) == 0 {
+		return nil
+	}
+
+	return this.target.UpsertAssignmentExtensions(assignment, extensions)
+}
+
+func (this *copier) visitRubricGrades(assignment *model.Assignment, grades map[string]*model.RubricGrade) error {
+	err := this.summarizer.visitRubricGrades(assignment, grades)
+	if err != nil {
+		return err
+	}
+
+	if len(grades) == 0 {
+		return nil
+	}
+
+	return this.target.UpsertRubricGrades(assignment, grades)
+}
+
+func (this *copier) visitRegradeRequests(assignment *model.Assignment, requests map[string]*model.RegradeRequest) error {
+	err := this.summarizer.visitRegradeRequests(assignment, requests)
+	if err != nil {
+		return err
+	}
+
+	if len(requests) == 0 {
+		return nil
+	}
+
+	return this.target.UpsertRegradeRequests(assignment, requests)
+}
+
+func (this *copier) visitTasks(tasks map[string]*model.FullScheduledTask) error {
+	err := this.summarizer.visitTasks(tasks)
+	if err != nil {
+		return err
+	}
+
+	return this.target.UpsertActiveTasks(tasks)
+}
+
+func (this *copier) visitGradingTickets(tickets []*model.GradingTicket) error {
+	err := this.summarizer.visitGradingTickets(tickets)
+	if err != nil {
+		return err
+	}
+
+	upsertTickets := make(map[string]*model.GradingTicket, len(tickets))
+	for _, ticket := range tickets {
+		upsertTickets[ticket.ID] = ticket
+	}
+
+	return this.target.UpsertGradingTickets(upsertTickets)
+}
+
+func (this *copier) visitLogs(records []*log.Record) error {
+	err := this.summarizer.visitLogs(records)
+	if err != nil {
+		return err
+	}
+
+	for _, record := range records {
+		err = this.target.LogDirect(record)
+		if err != nil {
+			return fmt.Errorf("Failed to migrate log record: '%w'.", err)
+		}
+	}
+
+	return nil
+}
+
+func (this *copier) visitMetrics(metrics []*stats.Metric) error {
+	err := this.summarizer.visitMetrics(metrics)
+	if err != nil {
+		return err
+	}
+
+	for _, metric := range metrics {
+		err = this.target.StoreMetric(metric)
+		if err != nil {
+			return fmt.Errorf("Failed to migrate metric: '%w'.", err)
+		}
+	}
+
+	return nil
+}
+
+func (this *copier) visitIndividualAnalysis(records []*model.IndividualAnalysis) error {
+	err := this.summarizer.visitIndividualAnalysis(records)
+	if err != nil {
+		return err
+	}
+
+	return this.target.StoreIndividualAnalysis(records)
+}
+
+func (this *copier) visitPairwiseAnalysis(records []*model.PairwiseAnalysis) error {
+	err := this.summarizer.visitPairwiseAnalysis(records)
+	if err != nil {
+		return err
+	}
+
+	return this.target.StorePairwiseAnalysis(records)
+}
+
+// Records are appended as-is (they are already chained).
+func (this *copier) visitAuditRecords(records []*model.AuditRecord) error {
+	err := this.summarizer.visitAuditRecords(records)
+	if err != nil {
+		return err
+	}
+
+	for _, record := range records {
+		err = this.target.AppendAuditRecord(record)
+		if err != nil {
+			return fmt.Errorf("Failed to migrate audit record %d: '%w'.", record.Index, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,318 @@
+package migrate
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/log"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/stats"
+	"github.com/edulinq/autograder/internal/timestamp"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestMigrateBase(test *testing.T) {
+	db.ResetForTesting()
+	defer db.ResetForTesting()
+
+	source := mustOpenBackend(test, db.DB_TYPE_DISK)
+	defer source.Close()
+
+	addExtraData(test, source)
+
+	target := mustOpenBackend(test, db.DB_TYPE_SQLITE)
+	defer target.Close()
+	defer target.Clear()
+
+	result, err := Migrate(source, target, MigrateOptions{Overwrite: true})
+	if err != nil {
+		test.Fatalf("Failed to migrate: '%v'.", err)
+	}
+
+	for _, dataType := range DATA_TYPES {
+		if dataType == DATA_TYPE_TASKS {
+			// The test data does not have any persisted tasks.
+			continue
+		}
+
+		if result.Source[dataType].Count == 0 {
+			test.Errorf("No data of type '%s' was migrated.", dataType)
+		}
+	}
+
+	// Spot check some data.
+	course, err := target.GetCourse(db.TEST_COURSE_ID)
+	if err != nil {
+		test.Fatalf("Failed to get migrated course: '%v'.", err)
+	}
+
+	if course == nil {
+		test.Fatalf("Could not find migrated course.")
+	}
+
+	expected, err := source.GetSubmissionAttempts(db.MustGetTestAssignment(), "course-student@test.edulinq.org")
+	if err != nil {
+		test.Fatalf("Failed to get source submissions: '%v'.", err)
+	}
+
+	actual, err := target.GetSubmissionAttempts(course.Assignments[db.TEST_ASSIGNMENT_ID], "course-student@test.edulinq.org")
+	if err != nil {
+		test.Fatalf("Failed to get target submissions: '%v'.", err)
+	}
+
+	if util.MustToJSON(expected) != util.MustToJSON(actual) {
+		test.Fatalf("Migrated submissions do not match. Expected: '%s', Actual: '%s'.", util.MustToJSONIndent(expected), util.MustToJSONIndent(actual))
+	}
+
+	// Migrating again without overwriting should fail.
+	_, err = Migrate(source, target, MigrateOptions{})
+	if err == nil {
+		test.Fatalf("Did not get an error when migrating into a non-empty database.")
+	}
+
+	expectedSubstring := "Target database is not empty"
+	if !strings.Contains(err.Error(), expectedSubstring) {
+		test.Fatalf("Did not get expected error substring. Expected: '%s', Actual: '%s'.", expectedSubstring, err.Error())
+	}
+}
+
+func TestMigrateResultVerify(test *testing.T) {
+	testCases := []struct {
+		targetCount    int
+		targetChecksum string
+		errorSubstring string
+	}{
+		{1, "abc", ""},
+		{2, "abc", "Count mismatch for users"},
+		{1, "def", "Checksum mismatch for users"},
+	}
+
+	for i, testCase := range testCases {
+		result := &MigrateResult{
+			Source: make(DatabaseSummary),
+			Target: make(DatabaseSummary),
+		}
+
+		for _, dataType := range DATA_TYPES {
+			result.Source[dataType] = &DataSummary{Count: 1, Checksum: "abc"}
+			result.Target[dataType] = &DataSummary{Count: 1, Checksum: "abc"}
+		}
+
+		result.Target[DATA_TYPE_USERS] = &DataSummary{Count: testCase.targetCount, Checksum: testCase.targetChecksum}
+
+		err := result.Verify()
+		if testCase.errorSubstring == "" {
+			if err != nil {
+				test.Errorf("Case %d: Unexpected error: '%v'.", i, err)
+			}
+
+			continue
+		}
+
+		if err == nil {
+			test.Errorf("Case %d: Did not get expected error.", i)
+			continue
+		}
+
+		if !strings.Contains(err.Error(), testCase.errorSubstring) {
+			test.Errorf("Case %d: Did not get expected error substring. Expected: '%s', Actual: '%s'.", i, testCase.errorSubstring, err.Error())
+		}
+	}
+}
+
+// A broken audit chain in the source will be copied as-is, but fail verification.
+func TestMigrateBrokenAuditChain(test *testing.T) {
+	db.ResetForTesting()
+	defer db.ResetForTesting()
+
+	source := mustOpenBackend(test, db.DB_TYPE_DISK)
+	defer source.Close()
+
+	record := model.NewAuditRecord(model.AuditActionCourseEnroll, "course-student@test.edulinq.org")
+	record.Actor = "course-admin@test.edulinq.org"
+	record.Timestamp = timestamp.FromMSecs(500)
+	record.Index = 1
+	record.Hash = "zzz"
+
+	err := source.AppendAuditRecord(record)
+	if err != nil {
+		test.Fatalf("Failed to add audit record: '%v'.", err)
+	}
+
+	target := mustOpenBackend(test, db.DB_TYPE_SQLITE)
+	defer target.Close()
+	defer target.Clear()
+
+	result, err := Migrate(source, target, MigrateOptions{Overwrite: true})
+	if err == nil {
+		test.Fatalf("Did not get an error when migrating a broken audit chain.")
+	}
+
+	if result == nil {
+		test.Fatalf("Did not get a result when the migration ran.")
+	}
+
+	expectedSubstring := "Migrated audit trail is not a valid chain"
+	if !strings.Contains(err.Error(), expectedSubstring) {
+		test.Fatalf("Did not get expected error substring. Expected: '%s', Actual: '%s'.", expectedSubstring, err.Error())
+	}
+}
+
+func mustOpenBackend(test *testing.T, dbType string) db.Backend {
+	backend, err := db.OpenBackend(dbType)
+	if err != nil {
+		test.Fatalf("Failed to open '%s' backend: '%v'.", dbType, err)
+	}
+
+	return backend
+}
+
+// Add data that is not part of the standard test data.
+func addExtraData(test *testing.T, backend db.Backend) {
+	err := backend.LogDirect(&log.Record{
+		Level:     log.LevelInfo,
+		Message:   "Migration test.",
+		Timestamp: timestamp.FromMSecs(100),
+		Course:    db.TEST_COURSE_ID,
+	})
+	if err != nil {
+		test.Fatalf("Failed to add log record: '%v'.", err)
+	}
+
+	err = backend.StoreMetric(&stats.Metric{
+		Timestamp: timestamp.FromMSecs(200),
+		Type:      stats.MetricTypeGradingTime,
+		Value:     10,
+		Attributes: map[stats.MetricAttribute]any{
+			stats.MetricAttributeCourseID: db.TEST_COURSE_ID,
+		},
+	})
+	if err != nil {
+		test.Fatalf("Failed to add metric: '%v'.", err)
+	}
+
+	err = backend.UpsertGradingTickets(map[string]*model.GradingTicket{
+		"migrate-ticket": &model.GradingTicket{
+			ID:             "migrate-ticket",
+			CourseID:       db.TEST_COURSE_ID,
+			AssignmentID:   db.TEST_ASSIGNMENT_ID,
+			User:           "course-student@test.edulinq.org",
+			Status:         model.GradingTicketStatusQueued,
+			QueueTime:      timestamp.FromMSecs(250),
+			InputFilesGZip: map[string][]byte{"submission.py": []byte{1, 2, 3}},
+		},
+	})
+	if err != nil {
+		test.Fatalf("Failed to add grading ticket: '%v'.", err)
+	}
+
+	err = backend.UpsertAssignmentGroups(db.MustGetTestAssignment(), map[string]*model.AssignmentGroup{
+		"migrate-group": &model.AssignmentGroup{
+			ID:           "migrate-group",
+			CourseID:     db.TEST_COURSE_ID,
+			AssignmentID: db.TEST_ASSIGNMENT_ID,
+			Members:      []string{"course-student@test.edulinq.org"},
+			CreatedBy:    "course-grader@test.edulinq.org",
+			CreationTime: timestamp.FromMSecs(275),
+		},
+	})
+	if err != nil {
+		test.Fatalf("Failed to add assignment group: '%v'.", err)
+	}
+
+	dueDate := timestamp.FromMSecs(300)
+	err = backend.UpsertAssignmentExtensions(db.MustGetTestAssignment(), map[string]*model.AssignmentExtension{
+		"course-student@test.edulinq.org": &model.AssignmentExtension{
+			CourseID:     db.TEST_COURSE_ID,
+			AssignmentID: db.TEST_ASSIGNMENT_ID,
+			Email:        "course-student@test.edulinq.org",
+			DueDate:      &dueDate,
+			UpdatedBy:    "course-admin@test.edulinq.org",
+			UpdateTime:   timestamp.FromMSecs(300),
+		},
+	})
+	if err != nil {
+		test.Fatalf("Failed to add assignment extension: '%v'.", err)
+	}
+
+	err = backend.UpsertRubricGrades(db.MustGetTestAssignment(), map[string]*model.RubricGrade{
+		"course101::hw0::course-student@test.edulinq.org::1697406256": &model.RubricGrade{
+			CourseID:     db.TEST_COURSE_ID,
+			AssignmentID: db.TEST_ASSIGNMENT_ID,
+			SubmissionID: "course101::hw0::course-student@test.edulinq.org::1697406256",
+			User:         "course-student@test.edulinq.org",
+			Scores: map[string]*model.RubricCriterionScore{
+				"Style": &model.RubricCriterionScore{Score: 1, Comment: "Good."},
+			},
+			GradedBy:   "course-grader@test.edulinq.org",
+			UpdateTime: timestamp.FromMSecs(325),
+		},
+	})
+	if err != nil {
+		test.Fatalf("Failed to add rubric grade: '%v'.", err)
+	}
+
+	err = backend.UpsertRegradeRequests(db.MustGetTestAssignment(), map[string]*model.RegradeRequest{
+		"aaa": &model.RegradeRequest{
+			ID:            "aaa",
+			CourseID:      db.TEST_COURSE_ID,
+			AssignmentID:  db.TEST_ASSIGNMENT_ID,
+			SubmissionID:  "course101::hw0::course-student@test.edulinq.org::1697406256",
+			User:          "course-student@test.edulinq.org",
+			Question:      "Q1",
+			Justification: "Please check again.",
+			CreateTime:    timestamp.FromMSecs(330),
+			Status:        model.REGRADE_STATUS_OPEN,
+		},
+	})
+	if err != nil {
+		test.Fatalf("Failed to add regrade request: '%v'.", err)
+	}
+
+	fullIDs := []string{
+		"course101::hw0::course-student@test.edulinq.org::1697406256",
+		"course101::hw0::course-student@test.edulinq.org::1697406265",
+	}
+
+	err = backend.StoreIndividualAnalysis([]*model.IndividualAnalysis{
+		&model.IndividualAnalysis{
+			AnalysisTimestamp: timestamp.FromMSecs(300),
+			FullID:            fullIDs[0],
+			CourseID:          db.TEST_COURSE_ID,
+		},
+	})
+	if err != nil {
+		test.Fatalf("Failed to add individual analysis: '%v'.", err)
+	}
+
+	err = backend.StorePairwiseAnalysis([]*model.PairwiseAnalysis{
+		&model.PairwiseAnalysis{
+			AnalysisTimestamp: timestamp.FromMSecs(400),
+			SubmissionIDs:     model.NewPairwiseKey(fullIDs[0], fullIDs[1]),
+		},
+	})
+	if err != nil {
+		test.Fatalf("Failed to add pairwise analysis: '%v'.", err)
+	}
+
+	var previous *model.AuditRecord = nil
+	for i, target := range []string{"course-student@test.edulinq.org", "course-other@test.edulinq.org"} {
+		record := model.NewAuditRecord(model.AuditActionCourseEnroll, target)
+		record.Actor = "course-admin@test.edulinq.org"
+		record.CourseID = db.TEST_COURSE_ID
+		record.Timestamp = timestamp.FromMSecs(int64(500 + i))
+
+		err = record.Chain(previous)
+		if err != nil {
+			test.Fatalf("Failed to chain audit record: '%v'.", err)
+		}
+
+		err = backend.AppendAuditRecord(record)
+		if err != nil {
+			test.Fatalf("Failed to add audit record: '%v'.", err)
+		}
+
+		previous = record
+	}
+}
@@ -0,0 +1,189 @@
+package migrate
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+
+	"github.com/edulinq/autograder/internal/log"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/stats"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+const (
+	DATA_TYPE_USERS                 = "users"
+	DATA_TYPE_COURSES               = "courses"
+	DATA_TYPE_ASSIGNMENTS           = "assignments"
+	DATA_TYPE_SUBMISSIONS           = "submissions"
+	DATA_TYPE_ASSIGNMENT_GROUPS     = "assignment-groups"
+	DATA_TYPE_ASSIGNMENT_EXTENSIONS = "assignment-extensions"
+	DATA_TYPE_RUBRIC_GRADES         = "rubric-grades"
+	DATA_TYPE_REGRADE_REQUESTS      = "regrade-requests"
+	DATA_TYPE_TASKS                 = "tasks"
+	DATA_TYPE_GRADING_QUEUE         = "grading-queue"
+	DATA_TYPE_LOGS                  = "logs"
+	DATA_TYPE_METRICS               = "metrics"
+	DATA_TYPE_INDIVIDUAL_ANALYSIS   = "individual-analysis"
+	DATA_TYPE_PAIRWISE_ANALYSIS     = "pairwise-analysis"
+	DATA_TYPE_AUDIT_RECORDS         = "audit-records"
+)
+
+// All the types of data that get migrated (in the order they are migrated).
+var DATA_TYPES []string = []string{
+	DATA_TYPE_USERS,
+	DATA_TYPE_COURSES,
+	DATA_TYPE_ASSIGNMENTS,
+	DATA_TYPE_SUBMISSIONS,
+	DATA_TYPE_ASSIGNMENT_GROUPS,
+	DATA_TYPE_ASSIGNMENT_EXTENSIONS,
+	DATA_TYPE_RUBRIC_GRADES,
+	DATA_TYPE_REGRADE_REQUESTS,
+	DATA_TYPE_TASKS,
+	DATA_TYPE_GRADING_QUEUE,
+	DATA_TYPE_LOGS,
+	DATA_TYPE_METRICS,
+	DATA_TYPE_INDIVIDUAL_ANALYSIS,
+	DATA_TYPE_PAIRWISE_ANALYSIS,
+	DATA_TYPE_AUDIT_RECORDS,
+}
+
+// A summary of a single type of data in a database.
+// The checksum is a SHA-256 over the JSON of each item (in a consistent order).
+type DataSummary struct {
+	Count    int    `json:"count"`
+	Checksum string `json:"checksum"`
+}
+
+// A summary of all the migrated data in a database, keyed by data type.
+type DatabaseSummary map[string]*DataSummary
+
+// Computes a DatabaseSummary as it visits data.
+type summarizer struct {
+	counts map[string]int
+	hashes map[string]hash.Hash
+}
+
+func newSummarizer() *summarizer {
+	summarizer := &summarizer{
+		counts: make(map[string]int, len(DATA_TYPES)),
+		hashes: make(map[string]hash.Hash, len(DATA_TYPES)),
+	}
+
+	for _, dataType := range DATA_TYPES {
+		summarizer.hashes[dataType] = sha256.New()
+	}
+
+	return summarizer
+}
+
+func (this *summarizer) summary() DatabaseSummary {
+	summary := make(DatabaseSummary, len(DATA_TYPES))
+	for _, dataType := range DATA_TYPES {
+		summary[dataType] = &DataSummary{
+			Count:    this.counts[dataType],
+			Checksum: hex.EncodeToString(this.hashes[dataType].Sum(nil)),
+		}
+	}
+
+	return summary
+}
+
+func (this *summarizer) add(dataType string, item any) error {
+	json, err := util.ToJSON(item)
+	if err != nil {
+		return fmt.Errorf("Failed to serialize %s item for checksum: '%w'.", dataType, err)
+	}
+
+	hash := this.hashes[dataType]
+	hash.Write([]byte(json))
+	hash.Write([]byte("\n"))
+
+	this.counts[dataType]++
+
+	return nil
+}
+
+func addAll[T any](this *summarizer, dataType string, items []T) error {
+	for _, item := range items {
+		err := this.add(dataType, item)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func addMap[T any](this *summarizer, dataType string, items map[string]T) error {
+	for _, key := range util.GetSortedKeys(items) {
+		err := this.add(dataType, items[key])
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (this *summarizer) visitUsers(users map[string]*model.ServerUser) error {
+	return addMap(this, DATA_TYPE_USERS, users)
+}
+
+func (this *summarizer) visitCourse(course *model.Course) error {
+	err := this.add(DATA_TYPE_COURSES, course)
+	if err != nil {
+		return err
+	}
+
+	return addMap(this, DATA_TYPE_ASSIGNMENTS, course.Assignments)
+}
+
+func (this *summarizer) visitSubmissions(course *model.Course, submissions []*model.GradingResult) error {
+	return addAll(this, DATA_TYPE_SUBMISSIONS, submissions)
+}
+
+func (this *summarizer) visitAssignmentGroups(assignment *model.Assignment, groups map[string]*model.AssignmentGroup) error {
+	return addMap(this, DATA_TYPE_ASSIGNMENT_GROUPS, groups)
+}
+
+func (this *summarizer) visitAssignmentExtensions(assignment *model.Assignment, extensions map[string]*model.AssignmentExtension) error {
+	return addMap(this, DATA_TYPE_ASSIGNMENT_EXTENSIONS, extensions)
+}
+
+func (this *summarizer) visitRubricGrades(assignment *model.Assignment, grades map[string]*model.RubricGrade) error {
+	return addMap(this, DATA_TYPE_RUBRIC_GRADES, grades)
+}
+
+func (this *summarizer) visitRegradeRequests(assignment *model.Assignment, requests map[string]*model.RegradeRequest) error {
+	return addMap(this, DATA_TYPE_REGRADE_REQUESTS, requests)
+}
+
+func (this *summarizer) visitTasks(tasks map[string]*model.FullScheduledTask) error {
+	return addMap(this, DATA_TYPE_TASKS, tasks)
+}
+
+func (this *summarizer) visitGradingTickets(tickets []*model.GradingTicket) error {
+	return addAll(this, DATA_TYPE_GRADING_QUEUE, tickets)
+}
+
+func (this *summarizer) visitLogs(records []*log.Record) error {
+	return addAll(this, DATA_TYPE_LOGS, records)
+}
+
+func (this *summarizer) visitMetrics(metrics []*stats.Metric) error {
+	return addAll(this, DATA_TYPE_METRICS, metrics)
+}
+
+func (this *summarizer) visitIndividualAnalysis(records []*model.IndividualAnalysis) error {
+	return addAll(this, DATA_TYPE_INDIVIDUAL_ANALYSIS, records)
+}
+
+func (this *summarizer) visitPairwiseAnalysis(records []*model.PairwiseAnalysis) error {
+	return addAll(this, DATA_TYPE_PAIRWISE_ANALYSIS, records)
+}
+
+func (this *summarizer) visitAuditRecords(records []*model.AuditRecord) error {
+	return addAll(this, DATA_TYPE_AUDIT_RECORDS, records)
+}
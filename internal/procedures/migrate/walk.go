@@ -0,0 +1,197 @@
+package migrate
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/log"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/stats"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+// Receives batches of data while walking a database.
+// Each batch is only a single kind of data.
+type dataVisitor interface {
+	visitUsers(users map[string]*model.ServerUser) error
+	visitCourse(course *model.Course) error
+	visitSubmissions(course *model.Course, submissions []*model.GradingResult) error
+	visitAssignmentGroups(assignment *model.Assignment, groups map[string]*model.AssignmentGroup) error
+	visitAssignmentExtensions(assignment *model.Assignment, extensions map[string]*model.AssignmentExtension) error
+	visitRubricGrades(assignment *model.Assignment, grades map[string]*model.RubricGrade) error
+	visitRegradeRequests(assignment *model.Assignment, requests map[string]*model.RegradeRequest) error
+	visitTasks(tasks map[string]*model.FullScheduledTask) error
+	visitGradingTickets(tickets []*model.GradingTicket) error
+	visitLogs(records []*log.Record) error
+	visitMetrics(metrics []*stats.Metric) error
+	visitIndividualAnalysis(records []*model.IndividualAnalysis) error
+	visitPairwiseAnalysis(records []*model.PairwiseAnalysis) error
+	visitAuditRecords(records []*model.AuditRecord) error
+}
+
+// Walk all the data in a database in a consistent order.
+// Submissions are fetched (and visited) one user/assignment at a time so that all submissions are never in memory at once.
+func walkBackend(backend db.Backend, visitor dataVisitor) error {
+	users, err := backend.GetServerUsers()
+	if err != nil {
+		return fmt.Errorf("Failed to get users: '%w'.", err)
+	}
+
+	err = visitor.visitUsers(users)
+	if err != nil {
+		return err
+	}
+
+	courses, err := backend.GetCourses()
+	if err != nil {
+		return fmt.Errorf("Failed to get courses: '%w'.", err)
+	}
+
+	for _, courseID := range util.GetSortedKeys(courses) {
+		err = walkCourse(backend, courses[courseID], visitor)
+		if err != nil {
+			return fmt.Errorf("Failed to walk course '%s': '%w'.", courseID, err)
+		}
+	}
+
+	tasks, err := backend.GetActiveTasks()
+	if err != nil {
+		return fmt.Errorf("Failed to get tasks: '%w'.", err)
+	}
+
+	err = visitor.visitTasks(tasks)
+	if err != nil {
+		return err
+	}
+
+	// Only tickets that still need grading are migrated.
+	tickets, err := backend.GetActiveGradingTickets()
+	if err != nil {
+		return fmt.Errorf("Failed to get grading tickets: '%w'.", err)
+	}
+
+	err = visitor.visitGradingTickets(tickets)
+	if err != nil {
+		return err
+	}
+
+	records, err := backend.GetLogRecords(log.ParsedLogQuery{Level: log.LevelTrace})
+	if err != nil {
+		return fmt.Errorf("Failed to get log records: '%w'.", err)
+	}
+
+	err = visitor.visitLogs(records)
+	if err != nil {
+		return err
+	}
+
+	// Only known metric types can be fetched.
+	for _, metricType := range stats.GetMetricTypes() {
+		metrics, err := backend.GetMetrics(stats.Query{Type: metricType})
+		if err != nil {
+			return fmt.Errorf("Failed to get metrics of type '%s': '%w'.", metricType, err)
+		}
+
+		err = visitor.visitMetrics(metrics)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Audit records are visited in order (by index), so the hash chain is kept intact.
+	auditRecords, err := backend.GetAuditRecords(model.AuditQuery{})
+	if err != nil {
+		return fmt.Errorf("Failed to get audit records: '%w'.", err)
+	}
+
+	return visitor.visitAuditRecords(auditRecords)
+}
+
+func walkCourse(backend db.Backend, course *model.Course, visitor dataVisitor) error {
+	err := visitor.visitCourse(course)
+	if err != nil {
+		return err
+	}
+
+	for _, assignmentID := range util.GetSortedKeys(course.Assignments) {
+		assignment := course.Assignments[assignmentID]
+
+		emails, err := backend.GetSubmissionUsers(assignment)
+		if err != nil {
+			return fmt.Errorf("Failed to get submission users for assignment '%s': '%w'.", assignmentID, err)
+		}
+
+		// Backends are supposed to sort, but a consistent order is required for checksums.
+		slices.Sort(emails)
+
+		for _, email := range emails {
+			submissions, err := backend.GetSubmissionAttempts(assignment, email)
+			if err != nil {
+				return fmt.Errorf("Failed to get submissions for assignment '%s' and user '%s': '%w'.", assignmentID, email, err)
+			}
+
+			err = visitor.visitSubmissions(course, submissions)
+			if err != nil {
+				return err
+			}
+		}
+
+		groups, err := backend.GetAssignmentGroups(assignment)
+		if err != nil {
+			return fmt.Errorf("Failed to get groups for assignment '%s': '%w'.", assignmentID, err)
+		}
+
+		err = visitor.visitAssignmentGroups(assignment, groups)
+		if err != nil {
+			return err
+		}
+
+		extensions, err := backend.GetAssignmentExtensions(assignment)
+		if err != nil {
+			return fmt.Errorf("Failed to get extensions for assignment '%s': '%w'.", assignmentID, err)
+		}
+
+		err = visitor.visitAssignmentExtensions(assignment, extensions)
+		if err != nil {
+			return err
+		}
+
+		grades, err := backend.GetRubricGrades(assignment)
+		if err != nil {
+			return fmt.Errorf("Failed to get rubric grades for assignment '%s': '%w'.", assignmentID, err)
+		}
+
+		err = visitor.visitRubricGrades(assignment, grades)
+		if err != nil {
+			return err
+		}
+
+		requests, err := backend.GetRegradeRequests(assignment)
+		if err != nil {
+			return fmt.Errorf("Failed to get regrade requests for assignment '%s': '%w'.", assignmentID, err)
+		}
+
+		err = visitor.visitRegradeRequests(assignment, requests)
+		if err != nil {
+			return err
+		}
+	}
+
+	individualRecords, err := backend.GetCourseIndividualAnalysis(course.GetID())
+	if err != nil {
+		return fmt.Errorf("Failed to get individual analysis: '%w'.", err)
+	}
+
+	err = visitor.visitIndividualAnalysis(individualRecords)
+	if err != nil {
+		return err
+	}
+
+	pairwiseRecords, err := backend.GetCoursePairwiseAnalysis(course.GetID())
+	if err != nil {
+		return fmt.Errorf("Failed to get pairwise analysis: '%w'.", err)
+	}
+
+	return visitor.visitPairwiseAnalysis(pairwiseRecords)
+}
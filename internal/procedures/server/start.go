@@ -7,6 +7,8 @@ import (
 	"github.com/edulinq/autograder/internal/api/server"
 	"github.com/edulinq/autograder/internal/config"
 	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/docker"
+	"github.com/edulinq/autograder/internal/grader"
 	"github.com/edulinq/autograder/internal/lockmanager"
 	"github.com/edulinq/autograder/internal/log"
 	"github.com/edulinq/autograder/internal/stats"
@@ -47,7 +49,18 @@ func setup(initiator systemserver.ServerInitiator) error {
 	// Only perfrom some tasks if we are running a primary server.
 	if initiator == systemserver.PRIMARY_SERVER {
 		// Initialize the task engine.
+		err = tasks.UpsertServerTasks()
+		if err != nil {
+			return fmt.Errorf("Failed to save server tasks: '%w'.", err)
+		}
+
 		tasks.Start()
+
+		// Resume grading any submissions left in the grading queue.
+		err = grader.StartQueue()
+		if err != nil {
+			return fmt.Errorf("Failed to start the grading queue: '%w'.", err)
+		}
 	}
 
 	return nil
@@ -63,6 +76,10 @@ func CleanupAndStop() (err error) {
 
 	tasks.Stop()
 
+	grader.StopQueue()
+
+	docker.StopContainerPools()
+
 	stats.StopCollection()
 
 	apiServer.Stop()
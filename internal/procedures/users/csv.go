@@ -0,0 +1,278 @@
+package users
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+// Multiple sections within a single CSV cell are separated by this.
+const CSV_SECTION_SEPARATOR = ";"
+
+// The CSV column (header) that holds each field of RawServerUserData.
+// Empty values will use the default column name (the field's JSON key).
+// Columns that are not present in the CSV are ignored (except for email, which is required).
+type CSVColumnMapping struct {
+	Email          string `json:"email" name:"email" help:"Column containing the user's email." default:"email"`
+	Name           string `json:"name" name:"name" help:"Column containing the user's name." default:"name"`
+	Role           string `json:"role" name:"role" help:"Column containing the user's server role." default:"role"`
+	Course         string `json:"course" name:"course" help:"Column containing the course to enroll the user in." default:"course"`
+	CourseRole     string `json:"course-role" name:"course-role" help:"Column containing the user's course role." default:"course-role"`
+	CourseLMSID    string `json:"course-lms-id" name:"course-lms-id" help:"Column containing the user's LMS ID." default:"course-lms-id"`
+	CourseSections string `json:"course-sections" name:"course-sections" help:"Column containing the user's sections (separated by ';')." default:"course-sections"`
+}
+
+// Options for reading users from a CSV file.
+// This struct can be directly embedded for Kong arguments.
+type CSVImportOptions struct {
+	// Which columns (by header name) hold each user field.
+	Columns CSVColumnMapping `json:"columns" embed:"" prefix:"column-"`
+
+	// Values to use when a row does not have a value for these fields.
+	DefaultCourse     string `json:"default-course" help:"Course to enroll users in when a row does not have a course."`
+	DefaultCourseRole string `json:"default-course-role" help:"Course role for users that do not have one (only used when a course is present)."`
+}
+
+func GetDefaultCSVColumnMapping() CSVColumnMapping {
+	return CSVColumnMapping{
+		Email:          "email",
+		Name:           "name",
+		Role:           "role",
+		Course:         "course",
+		CourseRole:     "course-role",
+		CourseLMSID:    "course-lms-id",
+		CourseSections: "course-sections",
+	}
+}
+
+// Get a copy of this mapping with all empty columns filled with their defaults.
+func (this CSVColumnMapping) withDefaults() CSVColumnMapping {
+	defaults := GetDefaultCSVColumnMapping()
+
+	this.Email = util.GetStringWithDefault(strings.TrimSpace(this.Email), defaults.Email)
+	this.Name = util.GetStringWithDefault(strings.TrimSpace(this.Name), defaults.Name)
+	this.Role = util.GetStringWithDefault(strings.TrimSpace(this.Role), defaults.Role)
+	this.Course = util.GetStringWithDefault(strings.TrimSpace(this.Course), defaults.Course)
+	this.CourseRole = util.GetStringWithDefault(strings.TrimSpace(this.CourseRole), defaults.CourseRole)
+	this.CourseLMSID = util.GetStringWithDefault(strings.TrimSpace(this.CourseLMSID), defaults.CourseLMSID)
+	this.CourseSections = util.GetStringWithDefault(strings.TrimSpace(this.CourseSections), defaults.CourseSections)
+
+	return this
+}
+
+// Read raw users from a CSV.
+// The first row must be a header, which is used to locate columns according to the column mapping.
+// Every non-header row will produce exactly one user (in order),
+// validation of the users is left to the upsert process (so that errors are reported per-user).
+func ReadUsersCSV(reader io.Reader, options CSVImportOptions) ([]*model.RawServerUserData, error) {
+	columns := options.Columns.withDefaults()
+
+	csvReader := csv.NewReader(reader)
+	csvReader.TrimLeadingSpace = true
+
+	// Allow rows with missing trailing columns (missing values are treated as empty).
+	csvReader.FieldsPerRecord = -1
+
+	header, err := csvReader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("CSV is empty, a header row is required.")
+		}
+
+		return nil, fmt.Errorf("Failed to read CSV header: '%w'.", err)
+	}
+
+	indexes := make(map[string]int, len(header))
+	for i, name := range header {
+		indexes[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+
+	emailIndex := getCSVColumnIndex(indexes, columns.Email)
+	if emailIndex < 0 {
+		return nil, fmt.Errorf("CSV header does not contain the email column ('%s').", columns.Email)
+	}
+
+	nameIndex := getCSVColumnIndex(indexes, columns.Name)
+	roleIndex := getCSVColumnIndex(indexes, columns.Role)
+	courseIndex := getCSVColumnIndex(indexes, columns.Course)
+	courseRoleIndex := getCSVColumnIndex(indexes, columns.CourseRole)
+	courseLMSIDIndex := getCSVColumnIndex(indexes, columns.CourseLMSID)
+	courseSectionsIndex := getCSVColumnIndex(indexes, columns.CourseSections)
+
+	rawUsers := make([]*model.RawServerUserData, 0)
+
+	for {
+		row, err := csvReader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("Failed to read CSV row %d: '%w'.", len(rawUsers)+1, err)
+		}
+
+		rawUser := &model.RawServerUserData{
+			Email:       getCSVValue(row, emailIndex),
+			Name:        getCSVValue(row, nameIndex),
+			Role:        getCSVValue(row, roleIndex),
+			Course:      getCSVValue(row, courseIndex),
+			CourseRole:  getCSVValue(row, courseRoleIndex),
+			CourseLMSID: getCSVValue(row, courseLMSIDIndex),
+		}
+
+		if courseSectionsIndex >= 0 {
+			rawUser.CourseSections = splitCSVSections(getCSVValue(row, courseSectionsIndex))
+		}
+
+		if rawUser.Course == "" {
+			rawUser.Course = options.DefaultCourse
+		}
+
+		if (rawUser.Course != "") && (rawUser.CourseRole == "") {
+			rawUser.CourseRole = options.DefaultCourseRole
+		}
+
+		rawUsers = append(rawUsers, rawUser)
+	}
+
+	return rawUsers, nil
+}
+
+// The header used when writing server users to a CSV.
+func GetServerUsersCSVHeader() []string {
+	columns := GetDefaultCSVColumnMapping()
+	return []string{columns.Email, columns.Name, columns.Role, columns.Course, columns.CourseRole, columns.CourseLMSID, columns.CourseSections}
+}
+
+// The header used when writing course users to a CSV.
+func GetCourseUsersCSVHeader() []string {
+	columns := GetDefaultCSVColumnMapping()
+	return []string{columns.Email, columns.Name, columns.Course, columns.CourseRole, columns.CourseLMSID, columns.CourseSections}
+}
+
+// Write server users as a CSV that can be read back in with ReadUsersCSV().
+// Each user will get one row per course they are enrolled in (or a single row without course information if they are not enrolled anywhere).
+// Rows are sorted by email and then course.
+func WriteServerUsersCSV(writer io.Writer, users []*model.ServerUser) error {
+	users = slices.Clone(users)
+	slices.SortFunc(users, model.CompareServerUserPointer)
+
+	csvWriter := csv.NewWriter(writer)
+
+	err := csvWriter.Write(GetServerUsersCSVHeader())
+	if err != nil {
+		return fmt.Errorf("Failed to write CSV header: '%w'.", err)
+	}
+
+	for _, user := range users {
+		if user == nil {
+			continue
+		}
+
+		baseRow := []string{user.Email, util.PointerToString(user.Name), user.Role.String()}
+
+		courses := user.GetCourses()
+		slices.Sort(courses)
+
+		if len(courses) == 0 {
+			courses = []string{""}
+		}
+
+		for _, courseID := range courses {
+			row := slices.Clone(baseRow)
+
+			info := user.CourseInfo[courseID]
+			if info == nil {
+				row = append(row, "", "", "", "")
+			} else {
+				row = append(row, courseID, info.Role.String(), info.GetLMSID(), strings.Join(info.Sections, CSV_SECTION_SEPARATOR))
+			}
+
+			err = csvWriter.Write(row)
+			if err != nil {
+				return fmt.Errorf("Failed to write CSV row for user '%s': '%w'.", user.Email, err)
+			}
+		}
+	}
+
+	csvWriter.Flush()
+
+	return csvWriter.Error()
+}
+
+// Write the users of a course as a CSV that can be read back in with ReadUsersCSV().
+// Rows are sorted by email.
+func WriteCourseUsersCSV(writer io.Writer, courseID string, users []*model.CourseUser) error {
+	users = slices.Clone(users)
+	slices.SortFunc(users, model.CompareCourseUserPointer)
+
+	csvWriter := csv.NewWriter(writer)
+
+	err := csvWriter.Write(GetCourseUsersCSVHeader())
+	if err != nil {
+		return fmt.Errorf("Failed to write CSV header: '%w'.", err)
+	}
+
+	for _, user := range users {
+		if user == nil {
+			continue
+		}
+
+		row := []string{
+			user.Email,
+			util.PointerToString(user.Name),
+			courseID,
+			user.Role.String(),
+			user.GetLMSID(),
+			strings.Join(user.Sections, CSV_SECTION_SEPARATOR),
+		}
+
+		err = csvWriter.Write(row)
+		if err != nil {
+			return fmt.Errorf("Failed to write CSV row for user '%s': '%w'.", user.Email, err)
+		}
+	}
+
+	csvWriter.Flush()
+
+	return csvWriter.Error()
+}
+
+// Get the index of a column (or -1 if it does not exist).
+func getCSVColumnIndex(indexes map[string]int, column string) int {
+	index, ok := indexes[strings.ToLower(column)]
+	if !ok {
+		return -1
+	}
+
+	return index
+}
+
+func getCSVValue(row []string, index int) string {
+	if (index < 0) || (index >= len(row)) {
+		return ""
+	}
+
+	return strings.TrimSpace(row[index])
+}
+
+// Split a CSV cell into sections.
+// An empty cell results in an empty (non-nil) slice, which will clear any existing sections.
+func splitCSVSections(value string) []string {
+	sections := make([]string, 0)
+
+	for _, section := range strings.Split(value, CSV_SECTION_SEPARATOR) {
+		section = strings.TrimSpace(section)
+		if section != "" {
+			sections = append(sections, section)
+		}
+	}
+
+	return sections
+}
@@ -0,0 +1,233 @@
+package users
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestReadUsersCSV(test *testing.T) {
+	testCases := []struct {
+		input         string
+		options       CSVImportOptions
+		expected      []*model.RawServerUserData
+		expectedError string
+	}{
+		// Default columns.
+		{
+			input: "email,name,role,course,course-role,course-lms-id,course-sections\n" +
+				"a@test.edulinq.org,A,user,course101,student,lms-a,lab-01;lab-02\n",
+			expected: []*model.RawServerUserData{
+				&model.RawServerUserData{
+					Email:          "a@test.edulinq.org",
+					Name:           "A",
+					Role:           "user",
+					Course:         "course101",
+					CourseRole:     "student",
+					CourseLMSID:    "lms-a",
+					CourseSections: []string{"lab-01", "lab-02"},
+				},
+			},
+		},
+
+		// Only email, extra columns and whitespace.
+		{
+			input: " Email , Student ID\n" +
+				" a@test.edulinq.org , 123\n" +
+				"b@test.edulinq.org\n",
+			expected: []*model.RawServerUserData{
+				&model.RawServerUserData{Email: "a@test.edulinq.org"},
+				&model.RawServerUserData{Email: "b@test.edulinq.org"},
+			},
+		},
+
+		// Custom columns.
+		{
+			input: "Student Email,Student Name,Registrar ID,Lab\n" +
+				"a@test.edulinq.org,A,111,lab-01\n" +
+				"b@test.edulinq.org,B,222,\n",
+			options: CSVImportOptions{
+				Columns: CSVColumnMapping{
+					Email:          "student email",
+					Name:           "Student Name",
+					CourseLMSID:    "Registrar ID",
+					CourseSections: "Lab",
+				},
+			},
+			expected: []*model.RawServerUserData{
+				&model.RawServerUserData{
+					Email:          "a@test.edulinq.org",
+					Name:           "A",
+					CourseLMSID:    "111",
+					CourseSections: []string{"lab-01"},
+				},
+				&model.RawServerUserData{
+					Email:          "b@test.edulinq.org",
+					Name:           "B",
+					CourseLMSID:    "222",
+					CourseSections: []string{},
+				},
+			},
+		},
+
+		// Defaults.
+		{
+			input: "email,course,course-role\n" +
+				"a@test.edulinq.org,,\n" +
+				"b@test.edulinq.org,course-languages,\n" +
+				"c@test.edulinq.org,,grader\n",
+			options: CSVImportOptions{
+				DefaultCourse:     "course101",
+				DefaultCourseRole: "student",
+			},
+			expected: []*model.RawServerUserData{
+				&model.RawServerUserData{Email: "a@test.edulinq.org", Course: "course101", CourseRole: "student"},
+				&model.RawServerUserData{Email: "b@test.edulinq.org", Course: "course-languages", CourseRole: "student"},
+				&model.RawServerUserData{Email: "c@test.edulinq.org", Course: "course101", CourseRole: "grader"},
+			},
+		},
+
+		// No course, no default role.
+		{
+			input: "email,course-role\n" +
+				"a@test.edulinq.org,\n",
+			options: CSVImportOptions{
+				DefaultCourseRole: "student",
+			},
+			expected: []*model.RawServerUserData{
+				&model.RawServerUserData{Email: "a@test.edulinq.org"},
+			},
+		},
+
+		// Header only.
+		{
+			input:    "email\n",
+			expected: []*model.RawServerUserData{},
+		},
+
+		// Errors.
+		{
+			input:         "",
+			expectedError: "CSV is empty, a header row is required.",
+		},
+		{
+			input:         "name\nA\n",
+			expectedError: "CSV header does not contain the email column ('email').",
+		},
+		{
+			input: "email\n\"a@test.edulinq.org\n",
+			options: CSVImportOptions{
+				Columns: CSVColumnMapping{Email: "EMAIL"},
+			},
+			expectedError: "Failed to read CSV row 1",
+		},
+	}
+
+	for i, testCase := range testCases {
+		actual, err := ReadUsersCSV(strings.NewReader(testCase.input), testCase.options)
+		if err != nil {
+			if testCase.expectedError == "" {
+				test.Errorf("Case %d: Unexpected error: '%v'.", i, err)
+			} else if !strings.HasPrefix(err.Error(), testCase.expectedError) {
+				test.Errorf("Case %d: Unexpected error. Expected '%s', found '%v'.", i, testCase.expectedError, err)
+			}
+
+			continue
+		}
+
+		if testCase.expectedError != "" {
+			test.Errorf("Case %d: Did not get expected error '%s'.", i, testCase.expectedError)
+			continue
+		}
+
+		if !reflect.DeepEqual(testCase.expected, actual) {
+			test.Errorf("Case %d: Unexpected users. Expected: '%s', Actual: '%s'.",
+				i, util.MustToJSONIndent(testCase.expected), util.MustToJSONIndent(actual))
+		}
+	}
+}
+
+func TestWriteServerUsersCSV(test *testing.T) {
+	usersMap := db.MustGetServerUsers()
+
+	users := []*model.ServerUser{
+		usersMap["server-owner@test.edulinq.org"],
+		usersMap["course-admin@test.edulinq.org"],
+	}
+
+	users[1] = users[1].Clone()
+	users[1].CourseInfo["course101"].Sections = []string{"lab-01", "lab-02"}
+
+	var builder strings.Builder
+
+	err := WriteServerUsersCSV(&builder, users)
+	if err != nil {
+		test.Fatalf("Failed to write users: '%v'.", err)
+	}
+
+	expected := "email,name,role,course,course-role,course-lms-id,course-sections\n" +
+		"course-admin@test.edulinq.org,course-admin,user,course-languages,admin,lms-course-admin@test.edulinq.org,\n" +
+		"course-admin@test.edulinq.org,course-admin,user,course101,admin,lms-course-admin@test.edulinq.org,lab-01;lab-02\n" +
+		"server-owner@test.edulinq.org,server-owner,owner,,,,\n"
+
+	if expected != builder.String() {
+		test.Fatalf("Unexpected CSV. Expected: '%s', Actual: '%s'.", expected, builder.String())
+	}
+
+	// The output should be readable as input.
+	rawUsers, err := ReadUsersCSV(strings.NewReader(builder.String()), CSVImportOptions{})
+	if err != nil {
+		test.Fatalf("Failed to read written users: '%v'.", err)
+	}
+
+	if len(rawUsers) != 3 {
+		test.Fatalf("Unexpected number of read users. Expected: 3, Actual: %d.", len(rawUsers))
+	}
+
+	expectedRawUser := &model.RawServerUserData{
+		Email:          "course-admin@test.edulinq.org",
+		Name:           "course-admin",
+		Role:           "user",
+		Course:         "course101",
+		CourseRole:     "admin",
+		CourseLMSID:    "lms-course-admin@test.edulinq.org",
+		CourseSections: []string{"lab-01", "lab-02"},
+	}
+
+	if !reflect.DeepEqual(expectedRawUser, rawUsers[1]) {
+		test.Fatalf("Unexpected read user. Expected: '%s', Actual: '%s'.",
+			util.MustToJSONIndent(expectedRawUser), util.MustToJSONIndent(rawUsers[1]))
+	}
+}
+
+func TestWriteCourseUsersCSV(test *testing.T) {
+	course := db.MustGetTestCourse()
+	usersMap, err := db.GetCourseUsers(course)
+	if err != nil {
+		test.Fatalf("Failed to get course users: '%v'.", err)
+	}
+
+	users := []*model.CourseUser{
+		usersMap["course-student@test.edulinq.org"],
+		usersMap["course-grader@test.edulinq.org"],
+	}
+
+	var builder strings.Builder
+
+	err = WriteCourseUsersCSV(&builder, course.GetID(), users)
+	if err != nil {
+		test.Fatalf("Failed to write users: '%v'.", err)
+	}
+
+	expected := "email,name,course,course-role,course-lms-id,course-sections\n" +
+		"course-grader@test.edulinq.org,course-grader,course101,grader,lms-course-grader@test.edulinq.org,\n" +
+		"course-student@test.edulinq.org,course-student,course101,student,lms-course-student@test.edulinq.org,\n"
+
+	if expected != builder.String() {
+		test.Fatalf("Unexpected CSV. Expected: '%s', Actual: '%s'.", expected, builder.String())
+	}
+}
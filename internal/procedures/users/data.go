@@ -0,0 +1,220 @@
+package users
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+
+	"github.com/edulinq/autograder/internal/common"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/log"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/stats"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+// All the data stored on the server that references a single user (a "data subject").
+// Data is gathered regardless of whether the user still exists on the server
+// (e.g., submissions are left behind when a user is removed).
+type userData struct {
+	Email string
+
+	// May be nil if the user no longer exists.
+	User *model.ServerUser
+
+	Submissions    []*model.GradingResult
+	Groups         []*model.AssignmentGroup
+	Extensions     []*model.AssignmentExtension
+	GradingTickets []*model.GradingTicket
+
+	// Rubric grades and regrade requests that reference the user,
+	// either as the student (the submission's user) or as the grader (GradedBy/ResolvedBy).
+	RubricGrades    []*model.RubricGrade
+	RegradeRequests []*model.RegradeRequest
+
+	IndividualAnalysis []*model.IndividualAnalysis
+	PairwiseAnalysis   []*model.PairwiseAnalysis
+	Logs               []*log.Record
+	Metrics            []*stats.Metric
+	AuditRecords       []*model.AuditRecord
+
+	// May be nil if the user has no login throttle.
+	LoginThrottle *model.LoginThrottle
+}
+
+func gatherUserData(email string) (*userData, error) {
+	data := &userData{
+		Email: email,
+	}
+
+	var err error
+
+	data.User, err = db.GetServerUser(email)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get user: '%w'.", err)
+	}
+
+	courses, err := db.GetCourses()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get courses: '%w'.", err)
+	}
+
+	courseIDs := make([]string, 0, len(courses))
+	for courseID, _ := range courses {
+		courseIDs = append(courseIDs, courseID)
+	}
+
+	slices.Sort(courseIDs)
+
+	for _, courseID := range courseIDs {
+		err = data.gatherCourseData(courses[courseID])
+		if err != nil {
+			return nil, fmt.Errorf("Failed to gather data for course '%s': '%w'.", courseID, err)
+		}
+	}
+
+	data.GradingTickets, err = db.GetUserGradingTickets(email)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get grading tickets: '%w'.", err)
+	}
+
+	data.Logs, err = db.GetLogRecords(log.ParsedLogQuery{Level: log.LevelTrace, UserEmail: email})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get log records: '%w'.", err)
+	}
+
+	data.Metrics = make([]*stats.Metric, 0)
+	for _, metricType := range stats.GetMetricTypes() {
+		query := stats.Query{
+			Type:  metricType,
+			Where: map[stats.MetricAttribute]any{stats.MetricAttributeUserEmail: email},
+		}
+
+		metrics, err := db.GetMetrics(query)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to get '%s' metrics: '%w'.", metricType, err)
+		}
+
+		data.Metrics = append(data.Metrics, metrics...)
+	}
+
+	data.AuditRecords, err = getUserAuditRecords(email)
+	if err != nil {
+		return nil, err
+	}
+
+	data.LoginThrottle, err = db.GetLoginThrottle(model.LoginThrottleKeyForEmail(email))
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get login throttle: '%w'.", err)
+	}
+
+	return data, nil
+}
+
+func (this *userData) gatherCourseData(course *model.Course) error {
+	for _, assignment := range course.GetSortedAssignments() {
+		// Raw submissions are used since they may be saved again (see erase()),
+		// and manual grades are gathered separately.
+		submissions, err := db.GetRawSubmissionAttempts(assignment, this.Email)
+		if err != nil {
+			return fmt.Errorf("Failed to get submissions for assignment '%s': '%w'.", assignment.GetID(), err)
+		}
+
+		this.Submissions = append(this.Submissions, submissions...)
+
+		group, err := db.GetUserAssignmentGroup(assignment, this.Email)
+		if err != nil {
+			return fmt.Errorf("Failed to get group for assignment '%s': '%w'.", assignment.GetID(), err)
+		}
+
+		if group != nil {
+			this.Groups = append(this.Groups, group)
+		}
+
+		extension, err := db.GetAssignmentExtension(assignment, this.Email)
+		if err != nil {
+			return fmt.Errorf("Failed to get extension for assignment '%s': '%w'.", assignment.GetID(), err)
+		}
+
+		if extension != nil {
+			this.Extensions = append(this.Extensions, extension)
+		}
+
+		rubricGrades, err := db.GetRubricGrades(assignment)
+		if err != nil {
+			return fmt.Errorf("Failed to get rubric grades for assignment '%s': '%w'.", assignment.GetID(), err)
+		}
+
+		for _, submissionID := range util.GetSortedKeys(rubricGrades) {
+			grade := rubricGrades[submissionID]
+			if (grade.User == this.Email) || (grade.GradedBy == this.Email) {
+				this.RubricGrades = append(this.RubricGrades, grade)
+			}
+		}
+
+		regradeRequests, err := db.GetSortedRegradeRequests(assignment, "")
+		if err != nil {
+			return fmt.Errorf("Failed to get regrade requests for assignment '%s': '%w'.", assignment.GetID(), err)
+		}
+
+		for _, request := range regradeRequests {
+			if (request.User == this.Email) || (request.ResolvedBy == this.Email) {
+				this.RegradeRequests = append(this.RegradeRequests, request)
+			}
+		}
+	}
+
+	individualAnalysis, err := db.GetCourseIndividualAnalysis(course.GetID())
+	if err != nil {
+		return fmt.Errorf("Failed to get individual analysis: '%w'.", err)
+	}
+
+	for _, analysis := range individualAnalysis {
+		if analysis.UserEmail == this.Email {
+			this.IndividualAnalysis = append(this.IndividualAnalysis, analysis)
+		}
+	}
+
+	pairwiseAnalysis, err := db.GetCoursePairwiseAnalysis(course.GetID())
+	if err != nil {
+		return fmt.Errorf("Failed to get pairwise analysis: '%w'.", err)
+	}
+
+	for _, analysis := range pairwiseAnalysis {
+		if isSubmissionUser(analysis.SubmissionIDs[0], this.Email) || isSubmissionUser(analysis.SubmissionIDs[1], this.Email) {
+			this.PairwiseAnalysis = append(this.PairwiseAnalysis, analysis)
+		}
+	}
+
+	return nil
+}
+
+// Get all the audit records where the user is either the actor or the target (sorted by index).
+func getUserAuditRecords(email string) ([]*model.AuditRecord, error) {
+	actorRecords, err := db.GetAuditRecords(model.AuditQuery{Actor: email})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get audit records (as actor): '%w'.", err)
+	}
+
+	targetRecords, err := db.GetAuditRecords(model.AuditQuery{Target: email})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get audit records (as target): '%w'.", err)
+	}
+
+	records := append(actorRecords, targetRecords...)
+
+	slices.SortFunc(records, func(a *model.AuditRecord, b *model.AuditRecord) int {
+		return cmp.Compare(a.Index, b.Index)
+	})
+
+	records = slices.CompactFunc(records, func(a *model.AuditRecord, b *model.AuditRecord) bool {
+		return a.Index == b.Index
+	})
+
+	return records, nil
+}
+
+func isSubmissionUser(fullSubmissionID string, email string) bool {
+	_, _, user, _, err := common.SplitFullSubmissionID(fullSubmissionID)
+	return (err == nil) && (user == email)
+}
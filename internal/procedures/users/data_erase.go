@@ -0,0 +1,327 @@
+package users
+
+import (
+	"fmt"
+
+	"github.com/edulinq/autograder/internal/common"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/log"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+const ERASED_USER_EMAIL_DOMAIN = "erased.invalid"
+
+// The outcome of erasing a user's data.
+// Counts are for the records that were pseudonymized or removed,
+// except for RetainedAuditRecords (which were left untouched).
+type EraseUserDataResult struct {
+	Email     string `json:"email"`
+	Pseudonym string `json:"pseudonym"`
+
+	UserRemoved bool `json:"user-removed"`
+
+	Submissions        int `json:"submissions"`
+	Groups             int `json:"groups"`
+	Extensions         int `json:"extensions"`
+	GradingTickets     int `json:"grading-tickets"`
+	RubricGrades       int `json:"rubric-grades"`
+	RegradeRequests    int `json:"regrade-requests"`
+	IndividualAnalysis int `json:"individual-analysis"`
+	PairwiseAnalysis   int `json:"pairwise-analysis"`
+	Logs               int `json:"logs"`
+	Metrics            int `json:"metrics"`
+
+	// Audit records that reference the user (as the actor or target) and still contain their email.
+	RetainedAuditRecords int `json:"retained-audit-records"`
+}
+
+// Erase all the data stored on the server that references a user.
+// Data that feeds into course aggregates (submissions, groups, rubric grades, regrade requests, analysis, and metrics)
+// is kept but pseudonymized (the user's email is replaced with a random pseudonym),
+// so that course-level statistics remain consistent.
+// All other personal data (the account itself, extensions, grading tickets, login throttles, and logs) is removed.
+// The audit trail is left untouched, since it is hash-chained and cannot be modified without breaking verification.
+// The number of retained audit records is reported in the result (so they can be handled separately if needed).
+func EraseUserData(email string) (*EraseUserDataResult, error) {
+	data, err := gatherUserData(email)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to gather data for user '%s': '%w'.", email, err)
+	}
+
+	result := &EraseUserDataResult{
+		Email:     email,
+		Pseudonym: fmt.Sprintf("erased-%s@%s", util.UUID(), ERASED_USER_EMAIL_DOMAIN),
+	}
+
+	err = data.erase(result)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to erase data for user '%s': '%w'.", email, err)
+	}
+
+	log.Info("Erased user data.", log.NewAttr("pseudonym", result.Pseudonym))
+
+	return result, nil
+}
+
+func (this *userData) erase(result *EraseUserDataResult) error {
+	pseudonym := result.Pseudonym
+
+	// Submissions.
+	for _, submission := range this.Submissions {
+		assignment, err := db.GetAssignment(submission.Info.CourseID, submission.Info.AssignmentID)
+		if err != nil {
+			return fmt.Errorf("Failed to get assignment for submission '%s': '%w'.", submission.Info.ID, err)
+		}
+
+		oldID := submission.Info.ID
+
+		submission.Info.User = pseudonym
+		submission.Info.ID = common.CreateFullSubmissionID(submission.Info.CourseID, submission.Info.AssignmentID, pseudonym, submission.Info.ShortID)
+
+		if submission.Info.ProxyUser == this.Email {
+			submission.Info.ProxyUser = pseudonym
+		}
+
+		err = db.SaveSubmission(assignment, submission)
+		if err != nil {
+			return fmt.Errorf("Failed to save pseudonymized submission '%s': '%w'.", oldID, err)
+		}
+
+		_, err = db.RemoveSubmission(assignment, this.Email, oldID)
+		if err != nil {
+			return fmt.Errorf("Failed to remove submission '%s': '%w'.", oldID, err)
+		}
+
+		result.Submissions++
+	}
+
+	// Rubric grades and regrade requests.
+	err := this.eraseGrading(result)
+	if err != nil {
+		return err
+	}
+
+	// Groups.
+	for _, group := range this.Groups {
+		assignment, err := db.GetAssignment(group.CourseID, group.AssignmentID)
+		if err != nil {
+			return fmt.Errorf("Failed to get assignment for group '%s': '%w'.", group.ID, err)
+		}
+
+		for i, member := range group.Members {
+			if member == this.Email {
+				group.Members[i] = pseudonym
+			}
+		}
+
+		if group.CreatedBy == this.Email {
+			group.CreatedBy = pseudonym
+		}
+
+		err = db.UpsertAssignmentGroup(assignment, group)
+		if err != nil {
+			return fmt.Errorf("Failed to save pseudonymized group '%s': '%w'.", group.ID, err)
+		}
+
+		result.Groups++
+	}
+
+	// Extensions.
+	for _, extension := range this.Extensions {
+		assignment, err := db.GetAssignment(extension.CourseID, extension.AssignmentID)
+		if err != nil {
+			return fmt.Errorf("Failed to get assignment for extension: '%w'.", err)
+		}
+
+		_, err = db.RemoveAssignmentExtension(assignment, this.Email)
+		if err != nil {
+			return fmt.Errorf("Failed to remove extension for assignment '%s': '%w'.", extension.AssignmentID, err)
+		}
+
+		result.Extensions++
+	}
+
+	// Grading tickets.
+	if len(this.GradingTickets) > 0 {
+		tickets := make(map[string]*model.GradingTicket, len(this.GradingTickets))
+		for _, ticket := range this.GradingTickets {
+			tickets[ticket.ID] = nil
+		}
+
+		err := db.UpsertGradingTickets(tickets)
+		if err != nil {
+			return fmt.Errorf("Failed to remove grading tickets: '%w'.", err)
+		}
+
+		result.GradingTickets = len(this.GradingTickets)
+	}
+
+	// Analysis.
+	err = this.eraseAnalysis(result)
+	if err != nil {
+		return err
+	}
+
+	// Logs.
+	err = db.RemoveUserLogRecords(this.Email)
+	if err != nil {
+		return fmt.Errorf("Failed to remove log records: '%w'.", err)
+	}
+
+	result.Logs = len(this.Logs)
+
+	// Metrics.
+	err = db.PseudonymizeUserMetrics(this.Email, pseudonym)
+	if err != nil {
+		return fmt.Errorf("Failed to pseudonymize metrics: '%w'.", err)
+	}
+
+	result.Metrics = len(this.Metrics)
+
+	// Login throttle.
+	if this.LoginThrottle != nil {
+		err = db.RemoveLoginThrottles([]string{this.LoginThrottle.Key})
+		if err != nil {
+			return fmt.Errorf("Failed to remove login throttle: '%w'.", err)
+		}
+	}
+
+	// Audit records are retained (see EraseUserData()).
+	result.RetainedAuditRecords = len(this.AuditRecords)
+
+	// The account itself.
+	result.UserRemoved, err = db.DeleteUser(this.Email)
+	if err != nil {
+		return fmt.Errorf("Failed to remove user: '%w'.", err)
+	}
+
+	return nil
+}
+
+func (this *userData) eraseAnalysis(result *EraseUserDataResult) error {
+	pseudonym := result.Pseudonym
+
+	if len(this.IndividualAnalysis) > 0 {
+		oldIDs := make([]string, 0, len(this.IndividualAnalysis))
+
+		for _, analysis := range this.IndividualAnalysis {
+			oldIDs = append(oldIDs, analysis.FullID)
+
+			analysis.UserEmail = pseudonym
+			analysis.FullID = pseudonymizeSubmissionID(analysis.FullID, this.Email, pseudonym)
+		}
+
+		err := db.StoreIndividualAnalysis(this.IndividualAnalysis)
+		if err != nil {
+			return fmt.Errorf("Failed to store pseudonymized individual analysis: '%w'.", err)
+		}
+
+		err = db.RemoveIndividualAnalysis(oldIDs)
+		if err != nil {
+			return fmt.Errorf("Failed to remove individual analysis: '%w'.", err)
+		}
+
+		result.IndividualAnalysis = len(this.IndividualAnalysis)
+	}
+
+	if len(this.PairwiseAnalysis) > 0 {
+		oldKeys := make([]model.PairwiseKey, 0, len(this.PairwiseAnalysis))
+
+		for _, analysis := range this.PairwiseAnalysis {
+			oldKeys = append(oldKeys, analysis.SubmissionIDs)
+
+			analysis.SubmissionIDs = model.NewPairwiseKey(
+				pseudonymizeSubmissionID(analysis.SubmissionIDs[0], this.Email, pseudonym),
+				pseudonymizeSubmissionID(analysis.SubmissionIDs[1], this.Email, pseudonym))
+		}
+
+		err := db.StorePairwiseAnalysis(this.PairwiseAnalysis)
+		if err != nil {
+			return fmt.Errorf("Failed to store pseudonymized pairwise analysis: '%w'.", err)
+		}
+
+		err = db.RemovePairwiseAnalysis(oldKeys)
+		if err != nil {
+			return fmt.Errorf("Failed to remove pairwise analysis: '%w'.", err)
+		}
+
+		result.PairwiseAnalysis = len(this.PairwiseAnalysis)
+	}
+
+	return nil
+}
+
+// Replace the user in a full submission ID (if the submission belongs to the user).
+func pseudonymizeSubmissionID(fullSubmissionID string, email string, pseudonym string) string {
+	courseID, assignmentID, user, shortID, err := common.SplitFullSubmissionID(fullSubmissionID)
+	if (err != nil) || (user != email) {
+		return fullSubmissionID
+	}
+
+	return common.CreateFullSubmissionID(courseID, assignmentID, pseudonym, shortID)
+}
+
+// Pseudonymize the rubric grades and regrade requests that reference the user.
+// Records for the user's submissions are re-keyed to the submission's new (pseudonymized) ID,
+// and the user is also replaced wherever they were the grader.
+func (this *userData) eraseGrading(result *EraseUserDataResult) error {
+	pseudonym := result.Pseudonym
+
+	for _, grade := range this.RubricGrades {
+		assignment, err := db.GetAssignment(grade.CourseID, grade.AssignmentID)
+		if err != nil {
+			return fmt.Errorf("Failed to get assignment for rubric grade '%s': '%w'.", grade.SubmissionID, err)
+		}
+
+		oldID := grade.SubmissionID
+		grade.SubmissionID = pseudonymizeSubmissionID(oldID, this.Email, pseudonym)
+
+		if grade.User == this.Email {
+			grade.User = pseudonym
+		}
+
+		if grade.GradedBy == this.Email {
+			grade.GradedBy = pseudonym
+		}
+
+		grades := map[string]*model.RubricGrade{
+			oldID: nil,
+		}
+
+		grades[grade.SubmissionID] = grade
+
+		err = db.UpsertRubricGrades(assignment, grades)
+		if err != nil {
+			return fmt.Errorf("Failed to save pseudonymized rubric grade '%s': '%w'.", oldID, err)
+		}
+
+		result.RubricGrades++
+	}
+
+	for _, request := range this.RegradeRequests {
+		assignment, err := db.GetAssignment(request.CourseID, request.AssignmentID)
+		if err != nil {
+			return fmt.Errorf("Failed to get assignment for regrade request '%s': '%w'.", request.ID, err)
+		}
+
+		request.SubmissionID = pseudonymizeSubmissionID(request.SubmissionID, this.Email, pseudonym)
+
+		if request.User == this.Email {
+			request.User = pseudonym
+		}
+
+		if request.ResolvedBy == this.Email {
+			request.ResolvedBy = pseudonym
+		}
+
+		err = db.UpsertRegradeRequest(assignment, request)
+		if err != nil {
+			return fmt.Errorf("Failed to save pseudonymized regrade request '%s': '%w'.", request.ID, err)
+		}
+
+		result.RegradeRequests++
+	}
+
+	return nil
+}
@@ -0,0 +1,147 @@
+package users
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+const (
+	DATA_EXPORT_DIRNAME                      = "user-data"
+	DATA_EXPORT_USER_FILENAME                = "user.json"
+	DATA_EXPORT_SUBMISSIONS_DIRNAME          = "submissions"
+	DATA_EXPORT_GROUPS_FILENAME              = "groups.json"
+	DATA_EXPORT_EXTENSIONS_FILENAME          = "extensions.json"
+	DATA_EXPORT_GRADING_TICKETS_FILENAME     = "grading-tickets.json"
+	DATA_EXPORT_RUBRIC_GRADES_FILENAME       = "rubric-grades.json"
+	DATA_EXPORT_REGRADE_REQUESTS_FILENAME    = "regrade-requests.json"
+	DATA_EXPORT_INDIVIDUAL_ANALYSIS_FILENAME = "analysis-individual.json"
+	DATA_EXPORT_PAIRWISE_ANALYSIS_FILENAME   = "analysis-pairwise.json"
+	DATA_EXPORT_LOGS_FILENAME                = "logs.json"
+	DATA_EXPORT_METRICS_FILENAME             = "metrics.json"
+	DATA_EXPORT_AUDIT_FILENAME               = "audit.json"
+	DATA_EXPORT_LOGIN_THROTTLE_FILENAME      = "login-throttle.json"
+)
+
+// The account information included in a data export.
+// Secrets (password hashes, token hashes, salts, and two-factor secrets) are never exported.
+type exportedUser struct {
+	Email            string                           `json:"email"`
+	Name             *string                          `json:"name"`
+	Role             model.ServerUserRole             `json:"role"`
+	CourseInfo       map[string]*model.UserCourseInfo `json:"course-info"`
+	Tokens           []model.TokenInfo                `json:"tokens"`
+	TwoFactorEnabled bool                             `json:"two-factor-enabled"`
+}
+
+// Export all the data stored on the server that references a user as a zip file (returned as bytes).
+// The export works even if the user's account has already been removed,
+// in which case the account information (user.json) will not be present.
+func ExportUserData(email string) ([]byte, error) {
+	data, err := gatherUserData(email)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to gather data for user '%s': '%w'.", email, err)
+	}
+
+	tempDir, err := util.MkDirTemp("user-data-export-")
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create temp dir: '%w'.", err)
+	}
+	defer util.RemoveDirent(tempDir)
+
+	// All the data is placed in a single dir inside the zip file.
+	baseDir := filepath.Join(tempDir, DATA_EXPORT_DIRNAME)
+
+	err = data.writeDir(baseDir)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to write data for user '%s': '%w'.", email, err)
+	}
+
+	return util.ZipToBytes(baseDir, "", true)
+}
+
+func (this *userData) writeDir(baseDir string) error {
+	err := util.MkDir(baseDir)
+	if err != nil {
+		return fmt.Errorf("Failed to make export dir '%s': '%w'.", baseDir, err)
+	}
+
+	if this.User != nil {
+		user := exportedUser{
+			Email:            this.User.Email,
+			Name:             this.User.Name,
+			Role:             this.User.Role,
+			CourseInfo:       this.User.CourseInfo,
+			Tokens:           make([]model.TokenInfo, 0, len(this.User.Tokens)),
+			TwoFactorEnabled: ((this.User.TwoFactor != nil) && this.User.TwoFactor.Enabled),
+		}
+
+		for _, token := range this.User.Tokens {
+			user.Tokens = append(user.Tokens, token.TokenInfo)
+		}
+
+		err = util.ToJSONFileIndent(user, filepath.Join(baseDir, DATA_EXPORT_USER_FILENAME))
+		if err != nil {
+			return fmt.Errorf("Failed to write user: '%w'.", err)
+		}
+	}
+
+	for _, submission := range this.Submissions {
+		path := filepath.Join(baseDir, DATA_EXPORT_SUBMISSIONS_DIRNAME,
+			submission.Info.CourseID, submission.Info.AssignmentID, submission.Info.ShortID)
+
+		err = model.WriteGradingResult(submission, path)
+		if err != nil {
+			return fmt.Errorf("Failed to write submission '%s': '%w'.", submission.Info.ID, err)
+		}
+	}
+
+	// Only the grades and requests for the user's own submissions are exported
+	// (records that only reference the user as a grader are about other users).
+	rubricGrades := make([]*model.RubricGrade, 0, len(this.RubricGrades))
+	for _, grade := range this.RubricGrades {
+		if grade.User == this.Email {
+			rubricGrades = append(rubricGrades, grade)
+		}
+	}
+
+	regradeRequests := make([]*model.RegradeRequest, 0, len(this.RegradeRequests))
+	for _, request := range this.RegradeRequests {
+		if request.User == this.Email {
+			regradeRequests = append(regradeRequests, request)
+		}
+	}
+
+	jsonFiles := []struct {
+		filename string
+		data     any
+		empty    bool
+	}{
+		{DATA_EXPORT_GROUPS_FILENAME, this.Groups, (len(this.Groups) == 0)},
+		{DATA_EXPORT_EXTENSIONS_FILENAME, this.Extensions, (len(this.Extensions) == 0)},
+		{DATA_EXPORT_GRADING_TICKETS_FILENAME, this.GradingTickets, (len(this.GradingTickets) == 0)},
+		{DATA_EXPORT_RUBRIC_GRADES_FILENAME, rubricGrades, (len(rubricGrades) == 0)},
+		{DATA_EXPORT_REGRADE_REQUESTS_FILENAME, regradeRequests, (len(regradeRequests) == 0)},
+		{DATA_EXPORT_INDIVIDUAL_ANALYSIS_FILENAME, this.IndividualAnalysis, (len(this.IndividualAnalysis) == 0)},
+		{DATA_EXPORT_PAIRWISE_ANALYSIS_FILENAME, this.PairwiseAnalysis, (len(this.PairwiseAnalysis) == 0)},
+		{DATA_EXPORT_LOGS_FILENAME, this.Logs, (len(this.Logs) == 0)},
+		{DATA_EXPORT_METRICS_FILENAME, this.Metrics, (len(this.Metrics) == 0)},
+		{DATA_EXPORT_AUDIT_FILENAME, this.AuditRecords, (len(this.AuditRecords) == 0)},
+		{DATA_EXPORT_LOGIN_THROTTLE_FILENAME, this.LoginThrottle, (this.LoginThrottle == nil)},
+	}
+
+	for _, jsonFile := range jsonFiles {
+		if jsonFile.empty {
+			continue
+		}
+
+		err = util.ToJSONFileIndent(jsonFile.data, filepath.Join(baseDir, jsonFile.filename))
+		if err != nil {
+			return fmt.Errorf("Failed to write '%s': '%w'.", jsonFile.filename, err)
+		}
+	}
+
+	return nil
+}
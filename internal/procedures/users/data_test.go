@@ -0,0 +1,327 @@
+package users
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/stats"
+	"github.com/edulinq/autograder/internal/timestamp"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestExportUserData(test *testing.T) {
+	defer db.ResetForTesting()
+
+	addGradingDataForTesting(test)
+
+	testCases := []struct {
+		email               string
+		expectedUser        bool
+		expectedSubmissions int
+		expectedGrading     bool
+	}{
+		{"course-student@test.edulinq.org", true, 5, true},
+		{"course-admin@test.edulinq.org", true, 0, false},
+		{"zzz@test.edulinq.org", false, 0, false},
+
+		// Graders do not get the grades/requests of other users.
+		{"course-grader@test.edulinq.org", true, 0, false},
+	}
+
+	for i, testCase := range testCases {
+		data, err := ExportUserData(testCase.email)
+		if err != nil {
+			test.Errorf("Case %d: Failed to export user data: '%v'.", i, err)
+			continue
+		}
+
+		tempDir := util.MustMkDirTemp("test-user-data-export-")
+		defer util.RemoveDirent(tempDir)
+
+		err = util.UnzipFromBytes(data, tempDir)
+		if err != nil {
+			test.Errorf("Case %d: Failed to unzip user data: '%v'.", i, err)
+			continue
+		}
+
+		baseDir := filepath.Join(tempDir, DATA_EXPORT_DIRNAME)
+
+		relpaths, err := util.GetAllDirents(baseDir, true, true)
+		if err != nil {
+			test.Errorf("Case %d: Failed to list exported files: '%v'.", i, err)
+			continue
+		}
+
+		hasUser := slices.Contains(relpaths, DATA_EXPORT_USER_FILENAME)
+		if testCase.expectedUser != hasUser {
+			test.Errorf("Case %d: Unexpected user file presence. Expected: %v, Actual: %v.", i, testCase.expectedUser, hasUser)
+			continue
+		}
+
+		if hasUser {
+			text := util.MustReadFile(filepath.Join(baseDir, DATA_EXPORT_USER_FILENAME))
+			for _, secret := range []string{`"salt"`, `"password"`, `"hash"`, `"secret"`} {
+				if strings.Contains(text, secret) {
+					test.Errorf("Case %d: Exported user contains a secret field (%s): '%s'.", i, secret, text)
+				}
+			}
+		}
+
+		submissionCount := 0
+		for _, relpath := range relpaths {
+			if strings.HasPrefix(relpath, DATA_EXPORT_SUBMISSIONS_DIRNAME) && strings.HasSuffix(relpath, "submission-result.json") {
+				submissionCount++
+			}
+		}
+
+		if testCase.expectedSubmissions != submissionCount {
+			test.Errorf("Case %d: Unexpected number of exported submissions. Expected: %d, Actual: %d.",
+				i, testCase.expectedSubmissions, submissionCount)
+		}
+
+		for _, filename := range []string{DATA_EXPORT_RUBRIC_GRADES_FILENAME, DATA_EXPORT_REGRADE_REQUESTS_FILENAME} {
+			hasFile := slices.Contains(relpaths, filename)
+			if testCase.expectedGrading != hasFile {
+				test.Errorf("Case %d: Unexpected presence of '%s'. Expected: %v, Actual: %v.", i, filename, testCase.expectedGrading, hasFile)
+			}
+		}
+
+		if testCase.expectedGrading {
+			text := util.MustReadFile(filepath.Join(baseDir, DATA_EXPORT_REGRADE_REQUESTS_FILENAME))
+			if !strings.Contains(text, TEST_JUSTIFICATION) {
+				test.Errorf("Case %d: Exported regrade requests do not contain the justification: '%s'.", i, text)
+			}
+		}
+	}
+}
+
+func TestEraseUserData(test *testing.T) {
+	defer db.ResetForTesting()
+
+	email := "course-student@test.edulinq.org"
+
+	metric := &stats.Metric{
+		Timestamp: timestamp.Now(),
+		Type:      stats.MetricTypeGradingTime,
+		Value:     100,
+		Attributes: map[stats.MetricAttribute]any{
+			stats.MetricAttributeUserEmail: email,
+			stats.MetricAttributeCourseID:  "course101",
+		},
+	}
+
+	err := db.StoreMetric(metric)
+	if err != nil {
+		test.Fatalf("Failed to store metric: '%v'.", err)
+	}
+
+	record := &model.AuditRecord{
+		Action: model.AuditActionUserUpsert,
+		Actor:  "server-admin@test.edulinq.org",
+		Target: email,
+	}
+
+	err = db.AppendAuditRecord(record)
+	if err != nil {
+		test.Fatalf("Failed to append audit record: '%v'.", err)
+	}
+
+	assignment := addGradingDataForTesting(test)
+
+	result, err := EraseUserData(email)
+	if err != nil {
+		test.Fatalf("Failed to erase user data: '%v'.", err)
+	}
+
+	if !result.UserRemoved {
+		test.Fatalf("User was not removed.")
+	}
+
+	if !strings.HasSuffix(result.Pseudonym, "@"+ERASED_USER_EMAIL_DOMAIN) {
+		test.Fatalf("Unexpected pseudonym: '%s'.", result.Pseudonym)
+	}
+
+	if result.Submissions != 5 {
+		test.Fatalf("Unexpected number of erased submissions. Expected: 5, Actual: %d.", result.Submissions)
+	}
+
+	if result.Metrics != 1 {
+		test.Fatalf("Unexpected number of erased metrics. Expected: 1, Actual: %d.", result.Metrics)
+	}
+
+	if (result.RubricGrades != 1) || (result.RegradeRequests != 1) {
+		test.Fatalf("Unexpected number of erased rubric grades/regrade requests. Expected: 1/1, Actual: %d/%d.", result.RubricGrades, result.RegradeRequests)
+	}
+
+	// The audit trail is not modified.
+	if result.RetainedAuditRecords != 1 {
+		test.Fatalf("Unexpected number of retained audit records. Expected: 1, Actual: %d.", result.RetainedAuditRecords)
+	}
+
+	// Nothing (except the audit trail) should reference the user anymore.
+	data, err := gatherUserData(email)
+	if err != nil {
+		test.Fatalf("Failed to gather user data: '%v'.", err)
+	}
+
+	if data.User != nil {
+		test.Fatalf("User still exists.")
+	}
+
+	counts := []int{len(data.Submissions), len(data.Groups), len(data.Extensions), len(data.GradingTickets),
+		len(data.RubricGrades), len(data.RegradeRequests),
+		len(data.IndividualAnalysis), len(data.PairwiseAnalysis), len(data.Logs), len(data.Metrics)}
+	for i, count := range counts {
+		if count != 0 {
+			test.Fatalf("Found remaining user data (index %d, count %d).", i, count)
+		}
+	}
+
+	// Course aggregates should be kept under the pseudonym.
+	pseudonymData, err := gatherUserData(result.Pseudonym)
+	if err != nil {
+		test.Fatalf("Failed to gather pseudonym data: '%v'.", err)
+	}
+
+	if len(pseudonymData.Submissions) != 5 {
+		test.Fatalf("Unexpected number of pseudonymized submissions. Expected: 5, Actual: %d.", len(pseudonymData.Submissions))
+	}
+
+	for _, submission := range pseudonymData.Submissions {
+		if (submission.Info.User != result.Pseudonym) || !strings.Contains(submission.Info.ID, result.Pseudonym) {
+			test.Fatalf("Submission was not pseudonymized: '%s'.", util.MustToJSONIndent(submission.Info))
+		}
+	}
+
+	if len(pseudonymData.Metrics) != 1 {
+		test.Fatalf("Unexpected number of pseudonymized metrics. Expected: 1, Actual: %d.", len(pseudonymData.Metrics))
+	}
+
+	if (len(pseudonymData.RubricGrades) != 1) || (len(pseudonymData.RegradeRequests) != 1) {
+		test.Fatalf("Unexpected number of pseudonymized rubric grades/regrade requests. Expected: 1/1, Actual: %d/%d.",
+			len(pseudonymData.RubricGrades), len(pseudonymData.RegradeRequests))
+	}
+
+	grade := pseudonymData.RubricGrades[0]
+	if (grade.User != result.Pseudonym) || !strings.Contains(grade.SubmissionID, result.Pseudonym) {
+		test.Fatalf("Rubric grade was not pseudonymized: '%s'.", util.MustToJSONIndent(grade))
+	}
+
+	request := pseudonymData.RegradeRequests[0]
+	if (request.User != result.Pseudonym) || !strings.Contains(request.SubmissionID, result.Pseudonym) {
+		test.Fatalf("Regrade request was not pseudonymized: '%s'.", util.MustToJSONIndent(request))
+	}
+
+	// The grade should still apply to the pseudonymized submission.
+	gradingInfo, err := db.GetSubmissionResult(assignment, result.Pseudonym, grade.SubmissionID)
+	if err != nil {
+		test.Fatalf("Failed to get pseudonymized submission: '%v'.", err)
+	}
+
+	if (gradingInfo == nil) || (gradingInfo.GetQuestion("Style") == nil) {
+		test.Fatalf("Rubric grade is not applied to the pseudonymized submission: '%s'.", util.MustToJSONIndent(gradingInfo))
+	}
+}
+
+func TestEraseUserDataGrader(test *testing.T) {
+	defer db.ResetForTesting()
+
+	email := "course-grader@test.edulinq.org"
+	assignment := addGradingDataForTesting(test)
+
+	result, err := EraseUserData(email)
+	if err != nil {
+		test.Fatalf("Failed to erase user data: '%v'.", err)
+	}
+
+	if (result.RubricGrades != 1) || (result.RegradeRequests != 1) {
+		test.Fatalf("Unexpected number of erased rubric grades/regrade requests. Expected: 1/1, Actual: %d/%d.", result.RubricGrades, result.RegradeRequests)
+	}
+
+	grades, err := db.GetRubricGrades(assignment)
+	if err != nil {
+		test.Fatalf("Failed to get rubric grades: '%v'.", err)
+	}
+
+	for _, grade := range grades {
+		if (grade.GradedBy != result.Pseudonym) || (grade.User != "course-student@test.edulinq.org") {
+			test.Fatalf("Rubric grade was not pseudonymized as expected: '%s'.", util.MustToJSONIndent(grade))
+		}
+	}
+
+	requests, err := db.GetRegradeRequests(assignment)
+	if err != nil {
+		test.Fatalf("Failed to get regrade requests: '%v'.", err)
+	}
+
+	for _, request := range requests {
+		if (request.ResolvedBy != result.Pseudonym) || (request.User != "course-student@test.edulinq.org") {
+			test.Fatalf("Regrade request was not pseudonymized as expected: '%s'.", util.MustToJSONIndent(request))
+		}
+	}
+}
+
+const TEST_JUSTIFICATION = "I deserve more points."
+
+// Grade (with a rubric) the latest course101/hw0 submission for course-student (as course-grader),
+// and add a regrade request for it (resolved by course-grader).
+func addGradingDataForTesting(test *testing.T) *model.Assignment {
+	assignment := db.MustSetTestRubric("course101", "hw0")
+	student := "course-student@test.edulinq.org"
+	grader := "course-grader@test.edulinq.org"
+
+	history, err := db.GetSubmissionHistory(assignment, student)
+	if err != nil {
+		test.Fatalf("Failed to get submission history: '%v'.", err)
+	}
+
+	if len(history) == 0 {
+		test.Fatalf("Student has no submissions.")
+	}
+
+	submissionID := history[len(history)-1].ID
+
+	grade := &model.RubricGrade{
+		CourseID:     "course101",
+		AssignmentID: "hw0",
+		SubmissionID: submissionID,
+		User:         student,
+		Scores: map[string]*model.RubricCriterionScore{
+			"Style": &model.RubricCriterionScore{Score: 1, Comment: "Some comment."},
+		},
+		GradedBy:   grader,
+		UpdateTime: timestamp.Now(),
+	}
+
+	err = db.UpsertRubricGrade(assignment, grade)
+	if err != nil {
+		test.Fatalf("Failed to save rubric grade: '%v'.", err)
+	}
+
+	resolveTime := timestamp.Now()
+	request := &model.RegradeRequest{
+		ID:              "test-regrade",
+		CourseID:        "course101",
+		AssignmentID:    "hw0",
+		SubmissionID:    submissionID,
+		User:            student,
+		Question:        "Style",
+		Justification:   TEST_JUSTIFICATION,
+		CreateTime:      timestamp.Now(),
+		Status:          model.REGRADE_STATUS_RESOLVED,
+		ScoreAdjustment: 1,
+		ResolvedBy:      grader,
+		ResolveTime:     &resolveTime,
+	}
+
+	err = db.UpsertRegradeRequest(assignment, request)
+	if err != nil {
+		test.Fatalf("Failed to save regrade request: '%v'.", err)
+	}
+
+	return assignment
+}
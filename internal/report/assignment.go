@@ -41,7 +41,13 @@ type ScoringReportQuestionStats struct {
 const DEFAULT_VALUE float64 = -1.0
 
 func GetAssignmentScoringReport(assignment *model.Assignment) (*AssignmentScoringReport, error) {
-	questionNames, scores, lastSubmissionTime, err := fetchScores(assignment)
+	return GetSectionAssignmentScoringReport(assignment, nil)
+}
+
+// Get a scoring report that only includes students in the given sections.
+// If no sections are given, then all students will be included.
+func GetSectionAssignmentScoringReport(assignment *model.Assignment, sections []string) (*AssignmentScoringReport, error) {
+	questionNames, scores, lastSubmissionTime, err := fetchScores(assignment, sections)
 	if err != nil {
 		return nil, err
 	}
@@ -83,8 +89,9 @@ func GetAssignmentScoringReport(assignment *model.Assignment) (*AssignmentScorin
 	return &report, nil
 }
 
-func fetchScores(assignment *model.Assignment) ([]string, map[string][]float64, timestamp.Timestamp, error) {
+func fetchScores(assignment *model.Assignment, sections []string) ([]string, map[string][]float64, timestamp.Timestamp, error) {
 	reference := model.CourseUserRoleToParsedCourseUserReference(model.CourseRoleStudent)
+	reference.RestrictToSections(sections)
 
 	results, err := db.GetRecentSubmissions(assignment, reference)
 	if err != nil {
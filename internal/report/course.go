@@ -10,10 +10,16 @@ type CourseScoringReport struct {
 }
 
 func GetCourseScoringReport(course *model.Course) (*CourseScoringReport, error) {
+	return GetSectionCourseScoringReport(course, nil)
+}
+
+// Get a scoring report that only includes students in the given sections.
+// If no sections are given, then all students will be included.
+func GetSectionCourseScoringReport(course *model.Course, sections []string) (*CourseScoringReport, error) {
 	assignmentReports := make([]*AssignmentScoringReport, 0)
 
 	for _, assignment := range course.GetSortedAssignments() {
-		assignmentReport, err := GetAssignmentScoringReport(assignment)
+		assignmentReport, err := GetSectionAssignmentScoringReport(assignment, sections)
 		if err != nil {
 			return nil, err
 		}
@@ -0,0 +1,210 @@
+package sandbox
+
+// A lightweight sandbox for running untrusted commands (e.g., graders) without a container engine.
+// On Linux, the sandbox uses user/mount/PID/IPC/UTS/network namespaces, seccomp, and rlimits.
+// The sandboxed command is started by re-executing the current binary (see init()),
+// which sets up the sandbox from inside the new namespaces and then execs the real command.
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+
+	"github.com/edulinq/autograder/internal/util"
+)
+
+const SANDBOX_INIT_ENV = "AUTOGRADER_SANDBOX_INIT"
+
+// The exit code used when the sandbox could not be set up (mirroring container runtimes).
+const SANDBOX_ERROR_EXIT_CODE = 125
+
+const SANDBOX_HOSTNAME = "sandbox"
+
+// The default PATH for sandboxed commands (if the host does not have one).
+const DEFAULT_PATH = "/usr/local/sbin:/usr/local/bin:/usr/sbin:/usr/bin:/sbin:/bin"
+
+// The only host environment variables that are passed into the sandbox.
+// Everything else (e.g., server config and secrets) is dropped.
+var passedEnvVars = []string{"PATH", "LANG"}
+
+// Host paths that are always exposed (read-only) inside the sandbox (if they exist).
+// These are the minimum needed to run most dynamically linked programs and interpreters.
+var DEFAULT_SYSTEM_PATHS = []string{
+	"/bin",
+	"/sbin",
+	"/lib",
+	"/lib32",
+	"/lib64",
+	"/usr",
+	"/etc/alternatives",
+	"/etc/ld.so.cache",
+	"/etc/ld.so.conf",
+	"/etc/ld.so.conf.d",
+	"/etc/localtime",
+	"/etc/ssl",
+}
+
+type Mount struct {
+	Source   string `json:"source"`
+	Target   string `json:"target"`
+	ReadOnly bool   `json:"read-only"`
+}
+
+// The options for a sandboxed command.
+// A zero (numeric) limit means that there is no limit.
+type Options struct {
+	// Host paths (files or dirs) to mount at the same path inside the sandbox.
+	Mounts []Mount `json:"mounts"`
+
+	// Additional host paths to expose (read-only) inside the sandbox (see DEFAULT_SYSTEM_PATHS).
+	SystemPaths []string `json:"system-paths"`
+
+	// The directory (inside the sandbox) to run the command in.
+	Dir string `json:"dir"`
+
+	MaxMemoryMB  int  `json:"max-memory-mb"`
+	MaxPIDs      int  `json:"max-pids"`
+	MaxCPUSecs   int  `json:"max-cpu-secs"`
+	TmpfsSizeMB  int  `json:"tmpfs-size-mb"`
+	AllowNetwork bool `json:"allow-network"`
+}
+
+// Everything the sandbox init process needs, passed via the environment.
+type initSpec struct {
+	Options
+
+	// An empty host directory that the sandbox's root will be mounted on.
+	RootDir string `json:"root-dir"`
+
+	Command []string `json:"command"`
+
+	// The environment the command is run with (see getEnv()).
+	Env []string `json:"env"`
+}
+
+var (
+	supportedOnce sync.Once
+	supportedErr  error
+)
+
+// If this process was started as a sandbox init process, set up the sandbox and exec the real command.
+// This never returns in a sandbox init process.
+func init() {
+	rawSpec, ok := os.LookupEnv(SANDBOX_INIT_ENV)
+	if !ok {
+		return
+	}
+
+	// Log and config have not been set up (and should not be), so just report to stderr.
+	err := runInit(rawSpec)
+	fmt.Fprintf(os.Stderr, "Failed to set up grading sandbox: '%v'.\n", err)
+	os.Exit(SANDBOX_ERROR_EXIT_CODE)
+}
+
+// Get a command that will run the given command inside a sandbox.
+// The caller should treat the command like any other (e.g., set stdout/stderr and run it).
+// The returned command will already have its directory set.
+// The rootDir must be an empty directory (that the caller will clean up),
+// it is only used as a mount point (nothing is written to it on the host).
+func Command(ctx context.Context, options Options, rootDir string, name string, args ...string) (*exec.Cmd, error) {
+	err := checkOptions(options)
+	if err != nil {
+		return nil, err
+	}
+
+	spec := initSpec{
+		Options: options,
+		RootDir: rootDir,
+		Command: append([]string{name}, args...),
+		Env:     getEnv(),
+	}
+
+	rawSpec, err := json.Marshal(spec)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to serialize sandbox spec: '%w'.", err)
+	}
+
+	cmd := exec.CommandContext(ctx, "/proc/self/exe")
+	cmd.Args = []string{"autograder-sandbox", name}
+	cmd.Env = append(spec.Env, fmt.Sprintf("%s=%s", SANDBOX_INIT_ENV, string(rawSpec)))
+	cmd.Dir = options.Dir
+
+	err = setupCommand(cmd, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return cmd, nil
+}
+
+// Get the minimal environment that sandboxed commands are run with.
+func getEnv() []string {
+	env := []string{"HOME=/tmp"}
+
+	for _, name := range passedEnvVars {
+		value, ok := os.LookupEnv(name)
+		if !ok && (name == "PATH") {
+			value, ok = DEFAULT_PATH, true
+		}
+
+		if ok {
+			env = append(env, fmt.Sprintf("%s=%s", name, value))
+		}
+	}
+
+	return env
+}
+
+// Check if commands can be sandboxed on this system (the result is cached).
+// A nil return means sandboxing is supported.
+func CheckSupport() error {
+	supportedOnce.Do(func() {
+		supportedErr = checkSupport()
+	})
+
+	return supportedErr
+}
+
+func checkSupport() error {
+	rootDir, err := util.MkDirTemp("autograder-sandbox-check-")
+	if err != nil {
+		return fmt.Errorf("Failed to create sandbox check dir: '%w'.", err)
+	}
+	defer util.RemoveDirent(rootDir)
+
+	cmd, err := Command(context.Background(), Options{Dir: "/"}, rootDir, "true")
+	if err != nil {
+		return err
+	}
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("Failed to run a sandboxed command: '%w'. Output: '%s'.", err, string(output))
+	}
+
+	return nil
+}
+
+func checkOptions(options Options) error {
+	if !filepath.IsAbs(options.Dir) {
+		return fmt.Errorf("Sandbox directory must be an absolute path, found: '%s'.", options.Dir)
+	}
+
+	for _, path := range options.SystemPaths {
+		if !filepath.IsAbs(path) {
+			return fmt.Errorf("Sandbox system paths must be absolute, found: '%s'.", path)
+		}
+	}
+
+	for _, mount := range options.Mounts {
+		if !filepath.IsAbs(mount.Source) || !filepath.IsAbs(mount.Target) {
+			return fmt.Errorf("Sandbox mounts must use absolute paths, found: '%s' -> '%s'.", mount.Source, mount.Target)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,413 @@
+//go:build linux
+
+package sandbox
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// The name (inside the new root) of the directory the old root is moved to while pivoting.
+const oldRootDirname = ".old-root"
+
+// Device files that are exposed inside the sandbox.
+var devicePaths = []string{
+	"/dev/full",
+	"/dev/null",
+	"/dev/random",
+	"/dev/tty",
+	"/dev/urandom",
+	"/dev/zero",
+}
+
+func setupCommand(cmd *exec.Cmd, options Options) error {
+	flags := uintptr(syscall.CLONE_NEWUSER | syscall.CLONE_NEWNS | syscall.CLONE_NEWPID | syscall.CLONE_NEWIPC | syscall.CLONE_NEWUTS)
+	if !options.AllowNetwork {
+		flags |= syscall.CLONE_NEWNET
+	}
+
+	// Only the current user is mapped (to root) inside the sandbox,
+	// so sandboxed processes cannot act as (or create files owned by) any other host user.
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		Cloneflags: flags,
+		UidMappings: []syscall.SysProcIDMap{
+			syscall.SysProcIDMap{ContainerID: 0, HostID: os.Getuid(), Size: 1},
+		},
+		GidMappings: []syscall.SysProcIDMap{
+			syscall.SysProcIDMap{ContainerID: 0, HostID: os.Getgid(), Size: 1},
+		},
+		GidMappingsEnableSetgroups: false,
+		Pdeathsig:                  syscall.SIGKILL,
+	}
+
+	return nil
+}
+
+// Set up the sandbox from inside the new namespaces, and then exec the command.
+// Only returns on an error.
+func runInit(rawSpec string) error {
+	// Many of the operations below (e.g., seccomp and capabilities) only apply to the current thread,
+	// which will become the only thread after exec.
+	runtime.LockOSThread()
+
+	var spec initSpec
+	err := json.Unmarshal([]byte(rawSpec), &spec)
+	if err != nil {
+		return fmt.Errorf("Failed to parse sandbox spec: '%w'.", err)
+	}
+
+	if len(spec.Command) == 0 {
+		return fmt.Errorf("No command given.")
+	}
+
+	err = setupFilesystem(&spec)
+	if err != nil {
+		return err
+	}
+
+	err = unix.Sethostname([]byte(SANDBOX_HOSTNAME))
+	if err != nil {
+		return fmt.Errorf("Failed to set hostname: '%w'.", err)
+	}
+
+	err = setRlimits(&spec.Options)
+	if err != nil {
+		return err
+	}
+
+	// Find the command (inside the sandbox) before privileges are dropped.
+	os.Unsetenv(SANDBOX_INIT_ENV)
+
+	path, err := exec.LookPath(spec.Command[0])
+	if err != nil {
+		return fmt.Errorf("Failed to find command '%s': '%w'.", spec.Command[0], err)
+	}
+
+	err = dropPrivileges()
+	if err != nil {
+		return err
+	}
+
+	err = unix.Exec(path, spec.Command, spec.Env)
+	return fmt.Errorf("Failed to exec command '%s': '%w'.", path, err)
+}
+
+// Build a new root with only the system and requested paths, and pivot into it.
+// Mounts are done in order of depth (tmpfs mounts first), so later mounts are not hidden by earlier ones.
+func setupFilesystem(spec *initSpec) error {
+	// Don't let any of our mounts propagate back to the host.
+	err := unix.Mount("", "/", "", unix.MS_REC|unix.MS_PRIVATE, "")
+	if err != nil {
+		return fmt.Errorf("Failed to make mounts private: '%w'.", err)
+	}
+
+	root := spec.RootDir
+
+	err = unix.Mount("tmpfs", root, "tmpfs", unix.MS_NOSUID|unix.MS_NODEV, "mode=0755,size=16m")
+	if err != nil {
+		return fmt.Errorf("Failed to mount sandbox root: '%w'.", err)
+	}
+
+	tmpOptions := "mode=1777"
+	if spec.TmpfsSizeMB > 0 {
+		tmpOptions = fmt.Sprintf("%s,size=%dm", tmpOptions, spec.TmpfsSizeMB)
+	}
+
+	err = mountTmpfs(filepath.Join(root, "tmp"), tmpOptions)
+	if err != nil {
+		return err
+	}
+
+	err = setupDev(root)
+	if err != nil {
+		return err
+	}
+
+	// A new /proc shows only the sandbox's processes.
+	procPath := filepath.Join(root, "proc")
+	err = os.MkdirAll(procPath, 0755)
+	if err != nil {
+		return fmt.Errorf("Failed to create /proc: '%w'.", err)
+	}
+
+	err = unix.Mount("proc", procPath, "proc", unix.MS_NOSUID|unix.MS_NODEV|unix.MS_NOEXEC, "")
+	if err != nil {
+		return fmt.Errorf("Failed to mount /proc: '%w'.", err)
+	}
+
+	for _, path := range slices.Concat(DEFAULT_SYSTEM_PATHS, spec.SystemPaths) {
+		err = bindMount(root, Mount{Source: path, Target: path, ReadOnly: true}, true)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, mount := range spec.Mounts {
+		err = bindMount(root, mount, false)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = pivotRoot(root)
+	if err != nil {
+		return err
+	}
+
+	// The root itself is read-only, only /tmp and the requested writable mounts can be written to.
+	err = unix.Mount("", "/", "", unix.MS_REMOUNT|unix.MS_RDONLY|unix.MS_NOSUID|unix.MS_NODEV, "")
+	if err != nil {
+		return fmt.Errorf("Failed to make sandbox root read-only: '%w'.", err)
+	}
+
+	err = os.Chdir(spec.Dir)
+	if err != nil {
+		return fmt.Errorf("Failed to change to sandbox dir '%s': '%w'.", spec.Dir, err)
+	}
+
+	return nil
+}
+
+func setupDev(root string) error {
+	devDir := filepath.Join(root, "dev")
+
+	err := mountTmpfs(devDir, "mode=0755,size=1m")
+	if err != nil {
+		return err
+	}
+
+	for _, path := range devicePaths {
+		err = bindMount(root, Mount{Source: path, Target: path}, true)
+		if err != nil {
+			return err
+		}
+	}
+
+	links := map[string]string{
+		"fd":     "/proc/self/fd",
+		"stdin":  "/proc/self/fd/0",
+		"stdout": "/proc/self/fd/1",
+		"stderr": "/proc/self/fd/2",
+	}
+
+	for name, target := range links {
+		err = os.Symlink(target, filepath.Join(devDir, name))
+		if err != nil {
+			return fmt.Errorf("Failed to create /dev/%s link: '%w'.", name, err)
+		}
+	}
+
+	return nil
+}
+
+func mountTmpfs(path string, options string) error {
+	err := os.MkdirAll(path, 0755)
+	if err != nil {
+		return fmt.Errorf("Failed to create tmpfs dir '%s': '%w'.", path, err)
+	}
+
+	err = unix.Mount("tmpfs", path, "tmpfs", unix.MS_NOSUID|unix.MS_NODEV, options)
+	if err != nil {
+		return fmt.Errorf("Failed to mount tmpfs at '%s': '%w'.", path, err)
+	}
+
+	return nil
+}
+
+// Bind mount a host path into the new root.
+// Symlinks are recreated (instead of mounted), which keeps merged-/usr layouts (e.g., /bin -> usr/bin) working.
+func bindMount(root string, mount Mount, skipMissing bool) error {
+	info, err := os.Lstat(mount.Source)
+	if err != nil {
+		if skipMissing && errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+
+		return fmt.Errorf("Failed to stat mount source '%s': '%w'.", mount.Source, err)
+	}
+
+	target := filepath.Join(root, mount.Target)
+
+	err = os.MkdirAll(filepath.Dir(target), 0755)
+	if err != nil {
+		return fmt.Errorf("Failed to create parent of mount target '%s': '%w'.", mount.Target, err)
+	}
+
+	if info.Mode()&os.ModeSymlink != 0 {
+		link, err := os.Readlink(mount.Source)
+		if err != nil {
+			return fmt.Errorf("Failed to read link '%s': '%w'.", mount.Source, err)
+		}
+
+		err = os.Symlink(link, target)
+		if err != nil {
+			return fmt.Errorf("Failed to create link '%s': '%w'.", mount.Target, err)
+		}
+
+		return nil
+	}
+
+	if info.IsDir() {
+		err = os.MkdirAll(target, 0755)
+	} else {
+		err = os.WriteFile(target, []byte{}, 0644)
+	}
+
+	if err != nil {
+		return fmt.Errorf("Failed to create mount target '%s': '%w'.", mount.Target, err)
+	}
+
+	err = unix.Mount(mount.Source, target, "", unix.MS_BIND|unix.MS_REC, "")
+	if err != nil {
+		return fmt.Errorf("Failed to bind mount '%s': '%w'.", mount.Source, err)
+	}
+
+	// A bind mount's flags can only be changed with a remount,
+	// which must keep any flags that were locked on the original (host) mount.
+	var stat unix.Statfs_t
+	err = unix.Statfs(target, &stat)
+	if err != nil {
+		return fmt.Errorf("Failed to stat mount '%s': '%w'.", mount.Target, err)
+	}
+
+	flags := uintptr(unix.MS_BIND|unix.MS_REMOUNT|unix.MS_NOSUID) | getLockedMountFlags(stat.Flags)
+	if mount.ReadOnly {
+		flags |= unix.MS_RDONLY
+	}
+
+	err = unix.Mount("", target, "", flags, "")
+	if err != nil {
+		return fmt.Errorf("Failed to remount '%s': '%w'.", mount.Target, err)
+	}
+
+	return nil
+}
+
+func getLockedMountFlags(statFlags int64) uintptr {
+	mapping := map[int64]uintptr{
+		unix.ST_NODEV:       unix.MS_NODEV,
+		unix.ST_NOEXEC:      unix.MS_NOEXEC,
+		unix.ST_NOATIME:     unix.MS_NOATIME,
+		unix.ST_NODIRATIME:  unix.MS_NODIRATIME,
+		unix.ST_RELATIME:    unix.MS_RELATIME,
+		unix.ST_RDONLY:      unix.MS_RDONLY,
+		unix.ST_SYNCHRONOUS: unix.MS_SYNCHRONOUS,
+	}
+
+	var flags uintptr = 0
+	for statFlag, mountFlag := range mapping {
+		if (statFlags & statFlag) != 0 {
+			flags |= mountFlag
+		}
+	}
+
+	return flags
+}
+
+func pivotRoot(root string) error {
+	oldRoot := filepath.Join(root, oldRootDirname)
+
+	err := os.MkdirAll(oldRoot, 0700)
+	if err != nil {
+		return fmt.Errorf("Failed to create old root dir: '%w'.", err)
+	}
+
+	err = unix.PivotRoot(root, oldRoot)
+	if err != nil {
+		return fmt.Errorf("Failed to pivot root: '%w'.", err)
+	}
+
+	err = os.Chdir("/")
+	if err != nil {
+		return fmt.Errorf("Failed to change to new root: '%w'.", err)
+	}
+
+	oldRoot = "/" + oldRootDirname
+
+	err = unix.Unmount(oldRoot, unix.MNT_DETACH)
+	if err != nil {
+		return fmt.Errorf("Failed to unmount old root: '%w'.", err)
+	}
+
+	err = os.Remove(oldRoot)
+	if err != nil {
+		return fmt.Errorf("Failed to remove old root dir: '%w'.", err)
+	}
+
+	return nil
+}
+
+func setRlimits(options *Options) error {
+	limits := map[int]uint64{
+		// No core dumps.
+		unix.RLIMIT_CORE: 0,
+	}
+
+	if options.MaxMemoryMB > 0 {
+		limits[unix.RLIMIT_AS] = uint64(options.MaxMemoryMB) * 1024 * 1024
+	}
+
+	// Note that this is per-user, but only the sandbox's processes are counted in its user namespace.
+	// The kernel does not enforce this limit when the server is run as (host) root.
+	if options.MaxPIDs > 0 {
+		limits[unix.RLIMIT_NPROC] = uint64(options.MaxPIDs)
+	}
+
+	if options.MaxCPUSecs > 0 {
+		limits[unix.RLIMIT_CPU] = uint64(options.MaxCPUSecs)
+	}
+
+	for resource, value := range limits {
+		err := unix.Setrlimit(resource, &unix.Rlimit{Cur: value, Max: value})
+		if err != nil {
+			return fmt.Errorf("Failed to set rlimit %d to %d: '%w'.", resource, value, err)
+		}
+	}
+
+	return nil
+}
+
+// Drop all capabilities (even the ones root inside the sandbox would get on exec),
+// and install the seccomp filter.
+func dropPrivileges() error {
+	for capability := 0; capability <= unix.CAP_LAST_CAP; capability++ {
+		err := unix.Prctl(unix.PR_CAPBSET_DROP, uintptr(capability), 0, 0, 0)
+		if (err != nil) && !errors.Is(err, unix.EINVAL) {
+			return fmt.Errorf("Failed to drop capability %d: '%w'.", capability, err)
+		}
+	}
+
+	err := unix.Prctl(unix.PR_CAP_AMBIENT, unix.PR_CAP_AMBIENT_CLEAR_ALL, 0, 0, 0)
+	if (err != nil) && !errors.Is(err, unix.EINVAL) {
+		return fmt.Errorf("Failed to clear ambient capabilities: '%w'.", err)
+	}
+
+	err = unix.Prctl(unix.PR_SET_NO_NEW_PRIVS, 1, 0, 0, 0)
+	if err != nil {
+		return fmt.Errorf("Failed to set no new privileges: '%w'.", err)
+	}
+
+	err = installSeccompFilter()
+	if err != nil {
+		return err
+	}
+
+	header := unix.CapUserHeader{Version: unix.LINUX_CAPABILITY_VERSION_3}
+	data := [2]unix.CapUserData{}
+
+	err = unix.Capset(&header, &data[0])
+	if err != nil {
+		return fmt.Errorf("Failed to clear capabilities: '%w'.", err)
+	}
+
+	return nil
+}
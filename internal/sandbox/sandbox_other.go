@@ -0,0 +1,17 @@
+//go:build !linux
+
+package sandbox
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+)
+
+func setupCommand(cmd *exec.Cmd, options Options) error {
+	return fmt.Errorf("Sandboxing is not supported on '%s'.", runtime.GOOS)
+}
+
+func runInit(rawSpec string) error {
+	return fmt.Errorf("Sandboxing is not supported on '%s'.", runtime.GOOS)
+}
@@ -0,0 +1,193 @@
+package sandbox
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/edulinq/autograder/internal/util"
+)
+
+func TestSandboxCommand(test *testing.T) {
+	skipIfNotSupported(test)
+
+	tempDir := util.MustMkDirTemp("sandbox-test-")
+	defer util.RemoveDirent(tempDir)
+
+	inputDir := filepath.Join(tempDir, "input")
+	outputDir := filepath.Join(tempDir, "output")
+	util.MustMkDir(inputDir)
+	util.MustMkDir(outputDir)
+
+	err := util.WriteFile("", filepath.Join(inputDir, "in.txt"))
+	if err != nil {
+		test.Fatalf("Failed to write input file: '%v'.", err)
+	}
+
+	hostDir, err := os.Getwd()
+	if err != nil {
+		test.Fatalf("Failed to get working dir: '%v'.", err)
+	}
+
+	options := Options{
+		Mounts: []Mount{
+			Mount{Source: inputDir, Target: inputDir, ReadOnly: true},
+			Mount{Source: outputDir, Target: outputDir},
+		},
+		Dir: outputDir,
+	}
+
+	test.Setenv("AUTOGRADER__SANDBOX_TEST_SECRET", "secret")
+
+	testCases := []struct {
+		script   string
+		expected string
+	}{
+		{`echo "$$ $(hostname)"`, "1 sandbox"},
+		{`pwd`, outputDir},
+		{`ls ` + inputDir, "in.txt"},
+
+		// Only the mounted dirs are visible.
+		{`test -e '` + hostDir + `' || echo missing`, "missing"},
+		{`test -e /root || echo missing`, "missing"},
+
+		// Read-only mounts and the root cannot be written to (but writable mounts and /tmp can).
+		{`touch '` + inputDir + `/a' 2>/dev/null || echo denied`, "denied"},
+		{`touch /a 2>/dev/null || echo denied`, "denied"},
+		{`touch out.txt /tmp/a && ls`, "out.txt"},
+
+		// Only a minimal environment is passed in.
+		{`echo "${AUTOGRADER__SANDBOX_TEST_SECRET:-unset} $HOME"`, "unset /tmp"},
+
+		// Denied syscalls.
+		{`mount -t tmpfs none /tmp 2>/dev/null || echo denied`, "denied"},
+		{`unshare --user true 2>/dev/null || echo denied`, "denied"},
+	}
+
+	for i, testCase := range testCases {
+		cmd, err := Command(context.Background(), options, getRootDir(test, tempDir, i), "sh", "-c", testCase.script)
+		if err != nil {
+			test.Errorf("Case %d: Failed to get command: '%v'.", i, err)
+			continue
+		}
+
+		output, err := cmd.CombinedOutput()
+		if err != nil {
+			test.Errorf("Case %d: Failed to run command: '%v'. Output: '%s'.", i, err, string(output))
+			continue
+		}
+
+		if testCase.expected != strings.TrimSpace(string(output)) {
+			test.Errorf("Case %d: Unexpected output. Expected: '%s', Actual: '%s'.", i, testCase.expected, string(output))
+			continue
+		}
+	}
+}
+
+func TestSandboxCommandLimits(test *testing.T) {
+	skipIfNotSupported(test)
+
+	tempDir := util.MustMkDirTemp("sandbox-test-")
+	defer util.RemoveDirent(tempDir)
+
+	testCases := []struct {
+		options  Options
+		script   string
+		expected string
+		skipRoot bool
+	}{
+		{Options{Dir: "/"}, `(sleep 0 & sleep 0 & sleep 0 & wait) && echo ok`, "ok", false},
+
+		// The kernel does not enforce process limits on (host) root.
+		{Options{Dir: "/", MaxPIDs: 2}, `(sleep 0 & sleep 0 & sleep 0 & wait) 2>/dev/null || echo failed`, "failed", true},
+
+		{Options{Dir: "/"}, `ulimit -v`, "unlimited", false},
+		{Options{Dir: "/", MaxMemoryMB: 64}, `ulimit -v`, "65536", false},
+		{Options{Dir: "/", MaxCPUSecs: 5}, `ulimit -t`, "5", false},
+	}
+
+	for i, testCase := range testCases {
+		if testCase.skipRoot && (os.Getuid() == 0) {
+			continue
+		}
+
+		cmd, err := Command(context.Background(), testCase.options, getRootDir(test, tempDir, i), "sh", "-c", testCase.script)
+		if err != nil {
+			test.Errorf("Case %d: Failed to get command: '%v'.", i, err)
+			continue
+		}
+
+		output, err := cmd.CombinedOutput()
+		if err != nil {
+			test.Errorf("Case %d: Failed to run command: '%v'. Output: '%s'.", i, err, string(output))
+			continue
+		}
+
+		if testCase.expected != strings.TrimSpace(string(output)) {
+			test.Errorf("Case %d: Unexpected output. Expected: '%s', Actual: '%s'.", i, testCase.expected, string(output))
+			continue
+		}
+	}
+}
+
+func TestSandboxCommandTimeout(test *testing.T) {
+	skipIfNotSupported(test)
+
+	tempDir := util.MustMkDirTemp("sandbox-test-")
+	defer util.RemoveDirent(tempDir)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	cmd, err := Command(ctx, Options{Dir: "/"}, getRootDir(test, tempDir, 0), "sleep", "10")
+	if err != nil {
+		test.Fatalf("Failed to get command: '%v'.", err)
+	}
+
+	startTime := time.Now()
+	err = cmd.Run()
+	if err == nil {
+		test.Fatalf("Did not get an error from a timed out command.")
+	}
+
+	if time.Since(startTime) > (5 * time.Second) {
+		test.Fatalf("Command was not killed on timeout.")
+	}
+}
+
+func TestSandboxCommandBadOptions(test *testing.T) {
+	testCases := []Options{
+		Options{Dir: "relative"},
+		Options{Dir: "/", SystemPaths: []string{"relative"}},
+		Options{Dir: "/", Mounts: []Mount{Mount{Source: "relative", Target: "/a"}}},
+		Options{Dir: "/", Mounts: []Mount{Mount{Source: "/a", Target: "relative"}}},
+	}
+
+	for i, options := range testCases {
+		_, err := Command(context.Background(), options, "/", "true")
+		if err == nil {
+			test.Errorf("Case %d: Did not get an expected error.", i)
+		}
+	}
+}
+
+func skipIfNotSupported(test *testing.T) {
+	err := CheckSupport()
+	if err != nil {
+		test.Skipf("Sandboxing is not supported: '%v'.", err)
+	}
+}
+
+func getRootDir(test *testing.T, tempDir string, index int) string {
+	path := filepath.Join(tempDir, "root", util.UUID())
+
+	err := util.MkDir(path)
+	if err != nil {
+		test.Fatalf("Case %d: Failed to make root dir: '%v'.", index, err)
+	}
+
+	return path
+}
@@ -0,0 +1,146 @@
+//go:build linux
+
+package sandbox
+
+import (
+	"fmt"
+	"runtime"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// Offsets into the kernel's seccomp_data struct.
+const (
+	seccompDataNROffset   = 0
+	seccompDataArchOffset = 4
+	seccompDataArg0Offset = 16
+)
+
+// Syscalls on x86_64 with this bit set use the x32 ABI (which has its own numbers).
+const x32SyscallBit = 0x40000000
+
+// The namespace flags that sandboxed processes may not pass to clone().
+const cloneNamespaceFlags = unix.CLONE_NEWCGROUP | unix.CLONE_NEWIPC | unix.CLONE_NEWNET | unix.CLONE_NEWNS |
+	unix.CLONE_NEWPID | unix.CLONE_NEWUSER | unix.CLONE_NEWUTS | unix.CLONE_NEWTIME
+
+var auditArches = map[string]uint32{
+	"amd64": unix.AUDIT_ARCH_X86_64,
+	"arm64": unix.AUDIT_ARCH_AARCH64,
+}
+
+// Syscalls that sandboxed processes may not make (they will fail with EPERM).
+// These are mostly for changing the system, escaping the sandbox, or inspecting other processes.
+var deniedSyscalls = []uint32{
+	unix.SYS_ACCT,
+	unix.SYS_ADD_KEY,
+	unix.SYS_BPF,
+	unix.SYS_CHROOT,
+	unix.SYS_CLOCK_ADJTIME,
+	unix.SYS_CLOCK_SETTIME,
+	unix.SYS_DELETE_MODULE,
+	unix.SYS_FINIT_MODULE,
+	unix.SYS_FSCONFIG,
+	unix.SYS_FSMOUNT,
+	unix.SYS_FSOPEN,
+	unix.SYS_FSPICK,
+	unix.SYS_INIT_MODULE,
+	unix.SYS_KEXEC_FILE_LOAD,
+	unix.SYS_KEXEC_LOAD,
+	unix.SYS_KEYCTL,
+	unix.SYS_MOUNT,
+	unix.SYS_MOUNT_SETATTR,
+	unix.SYS_MOVE_MOUNT,
+	unix.SYS_NAME_TO_HANDLE_AT,
+	unix.SYS_OPEN_BY_HANDLE_AT,
+	unix.SYS_OPEN_TREE,
+	unix.SYS_PERF_EVENT_OPEN,
+	unix.SYS_PIVOT_ROOT,
+	unix.SYS_PROCESS_VM_READV,
+	unix.SYS_PROCESS_VM_WRITEV,
+	unix.SYS_PTRACE,
+	unix.SYS_QUOTACTL,
+	unix.SYS_REBOOT,
+	unix.SYS_REQUEST_KEY,
+	unix.SYS_SETNS,
+	unix.SYS_SETTIMEOFDAY,
+	unix.SYS_SWAPOFF,
+	unix.SYS_SWAPON,
+	unix.SYS_SYSLOG,
+	unix.SYS_UMOUNT2,
+	unix.SYS_UNSHARE,
+	unix.SYS_USERFAULTFD,
+}
+
+func installSeccompFilter() error {
+	filter, err := getSeccompFilter(runtime.GOARCH)
+	if err != nil {
+		return err
+	}
+
+	program := unix.SockFprog{
+		Len:    uint16(len(filter)),
+		Filter: &filter[0],
+	}
+
+	err = unix.Prctl(unix.PR_SET_SECCOMP, unix.SECCOMP_MODE_FILTER, uintptr(unsafe.Pointer(&program)), 0, 0)
+	if err != nil {
+		return fmt.Errorf("Failed to install seccomp filter: '%w'.", err)
+	}
+
+	return nil
+}
+
+// Get a (BPF) seccomp filter that denies the deniedSyscalls, namespace creation via clone(), and clone3()
+// (which libc will fall back from to clone() on ENOSYS).
+// Syscalls from any other architecture (or ABI) are denied.
+func getSeccompFilter(arch string) ([]unix.SockFilter, error) {
+	auditArch, ok := auditArches[arch]
+	if !ok {
+		return nil, fmt.Errorf("Seccomp filtering is not supported on architecture '%s'.", arch)
+	}
+
+	deny := bpfReturn(unix.SECCOMP_RET_ERRNO | uint32(unix.EPERM))
+
+	filter := []unix.SockFilter{
+		bpfLoad(seccompDataArchOffset),
+		bpfJump(unix.BPF_JEQ, auditArch, 1, 0),
+		bpfReturn(unix.SECCOMP_RET_KILL_PROCESS),
+		bpfLoad(seccompDataNROffset),
+	}
+
+	if arch == "amd64" {
+		filter = append(filter, bpfJump(unix.BPF_JGE, x32SyscallBit, 0, 1), deny)
+	}
+
+	for _, syscall := range deniedSyscalls {
+		filter = append(filter, bpfJump(unix.BPF_JEQ, syscall, 0, 1), deny)
+	}
+
+	filter = append(filter,
+		bpfJump(unix.BPF_JEQ, unix.SYS_CLONE3, 0, 1),
+		bpfReturn(unix.SECCOMP_RET_ERRNO|uint32(unix.ENOSYS)),
+
+		// On both supported architectures, the flags are the first argument to clone().
+		bpfJump(unix.BPF_JEQ, unix.SYS_CLONE, 0, 3),
+		bpfLoad(seccompDataArg0Offset),
+		bpfJump(unix.BPF_JSET, cloneNamespaceFlags, 0, 1),
+		deny,
+
+		bpfReturn(unix.SECCOMP_RET_ALLOW),
+	)
+
+	return filter, nil
+}
+
+func bpfLoad(offset uint32) unix.SockFilter {
+	return unix.SockFilter{Code: unix.BPF_LD | unix.BPF_W | unix.BPF_ABS, K: offset}
+}
+
+func bpfJump(operation uint16, value uint32, jumpTrue uint8, jumpFalse uint8) unix.SockFilter {
+	return unix.SockFilter{Code: unix.BPF_JMP | operation | unix.BPF_K, Jt: jumpTrue, Jf: jumpFalse, K: value}
+}
+
+func bpfReturn(value uint32) unix.SockFilter {
+	return unix.SockFilter{Code: unix.BPF_RET | unix.BPF_K, K: value}
+}
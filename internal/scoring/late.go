@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/edulinq/autograder/internal/common"
+	"github.com/edulinq/autograder/internal/db"
 	"github.com/edulinq/autograder/internal/lms"
 	"github.com/edulinq/autograder/internal/lms/lmstypes"
 	"github.com/edulinq/autograder/internal/log"
@@ -50,7 +51,12 @@ func ApplyLatePolicy(assignment *model.Assignment, users map[string]*model.Cours
 		return fmt.Errorf("Assignment does not have a due date.")
 	}
 
-	applyBaselinePolicy(assignment, policy, users, scores, *lmsAssignment.DueDate)
+	extensions, err := db.GetAssignmentExtensions(assignment)
+	if err != nil {
+		return fmt.Errorf("Failed to get assignment extensions: '%w'.", err)
+	}
+
+	applyBaselinePolicy(assignment, policy, users, scores, *lmsAssignment.DueDate, extensions)
 
 	// Baseline policy is complete.
 	if policy.Type == model.BaselinePolicy {
@@ -81,9 +87,12 @@ func ApplyLatePolicy(assignment *model.Assignment, users map[string]*model.Cours
 }
 
 // Apply a common policy.
-func applyBaselinePolicy(assignment *model.Assignment, policy *model.LateGradingPolicy, users map[string]*model.CourseUser, scores map[string]*model.ScoringInfo, dueDate timestamp.Timestamp) {
+// Users with an extension will have their late days computed from their extended due date.
+func applyBaselinePolicy(assignment *model.Assignment, policy *model.LateGradingPolicy, users map[string]*model.CourseUser, scores map[string]*model.ScoringInfo,
+	dueDate timestamp.Timestamp, extensions map[string]*model.AssignmentExtension) {
 	for email, score := range scores {
-		score.NumDaysLate = computeLateDays(dueDate, score.SubmissionTime, policy.GraceMinutes)
+		userDueDate := extensions[email].GetDueDate(&dueDate)
+		score.NumDaysLate = computeLateDays(*userDueDate, score.SubmissionTime, policy.GraceMinutes)
 
 		_, ok := users[email]
 		if !ok {
@@ -120,6 +129,11 @@ func applyLateDaysPolicy(policy *model.LateGradingPolicy, assignment *model.Assi
 		return err
 	}
 
+	groupLateDayLimits, err := getGroupLateDayLimits(assignment, users, allLateDays)
+	if err != nil {
+		return err
+	}
+
 	lateDaysToUpdate := make(map[string]*LateDaysInfo)
 
 	for email, scoringInfo := range scores {
@@ -164,7 +178,13 @@ func applyLateDaysPolicy(policy *model.LateGradingPolicy, assignment *model.Assi
 		// - The number of late days the user has to use.
 		// - The maximum number of late days that can be used on this assignment.
 		// - The number of days late the submission actually is.
+		// - The number of late days every member of the user's group has to use (if in a group).
 		lateDaysToUse := min(lateDaysAvailable, policy.MaxLateDays, scoringInfo.NumDaysLate)
+
+		groupLimit, inGroup := groupLateDayLimits[email]
+		if inGroup {
+			lateDaysToUse = min(lateDaysToUse, groupLimit)
+		}
 		scoringInfo.LateDayUsage = lateDaysToUse
 
 		// Enforce a penalty for any remaining late days.
@@ -190,6 +210,51 @@ func applyLateDaysPolicy(policy *model.LateGradingPolicy, assignment *model.Assi
 	return nil
 }
 
+// Group members share a submission (and therefore a submission time),
+// so each member must use the same number of late days.
+// Get the number of late days that is available to every member of each group (keyed by member email).
+// Late days already allocated to this assignment are counted as available.
+func getGroupLateDayLimits(assignment *model.Assignment, users map[string]*model.CourseUser, allLateDays map[string]*LateDaysInfo) (map[string]int, error) {
+	limits := make(map[string]int)
+
+	if !assignment.IsGroupAssignment() {
+		return limits, nil
+	}
+
+	groups, err := db.GetAssignmentGroups(assignment)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get assignment groups: '%w'.", err)
+	}
+
+	for _, group := range groups {
+		groupLimit := math.MaxInt
+
+		for _, email := range group.Members {
+			user := users[email]
+			if user == nil {
+				continue
+			}
+
+			lateDays := allLateDays[user.GetLMSID()]
+			if lateDays == nil {
+				continue
+			}
+
+			groupLimit = min(groupLimit, lateDays.AvailableDays+lateDays.AllocatedDays[assignment.GetID()])
+		}
+
+		if groupLimit == math.MaxInt {
+			continue
+		}
+
+		for _, email := range group.Members {
+			limits[email] = groupLimit
+		}
+	}
+
+	return limits, nil
+}
+
 func updateLateDays(policy *model.LateGradingPolicy, assignment *model.Assignment, lateDaysToUpdate map[string]*LateDaysInfo, dryRun bool) error {
 	// Update late days.
 	// Info that does NOT have a LMSCommentID will get the autograder comment added in.
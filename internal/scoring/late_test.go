@@ -1,10 +1,13 @@
 package scoring
 
 import (
+	"reflect"
 	"strings"
 	"testing"
 
 	"github.com/edulinq/autograder/internal/common"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
 	"github.com/edulinq/autograder/internal/timestamp"
 	"github.com/edulinq/autograder/internal/util"
 )
@@ -102,3 +105,154 @@ func TestComputeLateDaysWithGraceTime(test *testing.T) {
 		}
 	}
 }
+
+func TestGetGroupLateDayLimits(test *testing.T) {
+	db.ResetForTesting()
+	defer db.ResetForTesting()
+
+	assignment := db.MustGetTestAssignment()
+
+	group := &model.AssignmentGroup{
+		ID:           "test-group",
+		CourseID:     db.TEST_COURSE_ID,
+		AssignmentID: db.TEST_ASSIGNMENT_ID,
+		Members:      []string{"course-student@test.edulinq.org", "course-other@test.edulinq.org"},
+		CreatedBy:    "course-admin@test.edulinq.org",
+		CreationTime: timestamp.Now(),
+	}
+
+	err := db.UpsertAssignmentGroup(assignment, group)
+	if err != nil {
+		test.Fatalf("Failed to create group: '%v'.", err)
+	}
+
+	studentLMSID := "student"
+	otherLMSID := "other"
+
+	users := map[string]*model.CourseUser{
+		"course-student@test.edulinq.org": &model.CourseUser{Email: "course-student@test.edulinq.org", LMSID: &studentLMSID},
+		"course-other@test.edulinq.org":   &model.CourseUser{Email: "course-other@test.edulinq.org", LMSID: &otherLMSID},
+	}
+
+	testCases := []struct {
+		isGroupAssignment bool
+		lateDays          map[string]*LateDaysInfo
+		expected          map[string]int
+	}{
+		// Not a group assignment.
+		{
+			false,
+			map[string]*LateDaysInfo{
+				studentLMSID: &LateDaysInfo{AvailableDays: 3},
+				otherLMSID:   &LateDaysInfo{AvailableDays: 1},
+			},
+			map[string]int{},
+		},
+
+		// The member with the fewest days limits the group.
+		{
+			true,
+			map[string]*LateDaysInfo{
+				studentLMSID: &LateDaysInfo{AvailableDays: 3},
+				otherLMSID:   &LateDaysInfo{AvailableDays: 1},
+			},
+			map[string]int{
+				"course-student@test.edulinq.org": 1,
+				"course-other@test.edulinq.org":   1,
+			},
+		},
+
+		// Days already allocated to this assignment are available.
+		{
+			true,
+			map[string]*LateDaysInfo{
+				studentLMSID: &LateDaysInfo{AvailableDays: 3},
+				otherLMSID:   &LateDaysInfo{AvailableDays: 1, AllocatedDays: map[string]int{db.TEST_ASSIGNMENT_ID: 1, "other": 5}},
+			},
+			map[string]int{
+				"course-student@test.edulinq.org": 2,
+				"course-other@test.edulinq.org":   2,
+			},
+		},
+
+		// Members without late day info do not limit the group.
+		{
+			true,
+			map[string]*LateDaysInfo{
+				studentLMSID: &LateDaysInfo{AvailableDays: 3},
+			},
+			map[string]int{
+				"course-student@test.edulinq.org": 3,
+				"course-other@test.edulinq.org":   3,
+			},
+		},
+		{
+			true,
+			map[string]*LateDaysInfo{},
+			map[string]int{},
+		},
+	}
+
+	for i, testCase := range testCases {
+		assignment.GroupOptions = nil
+		if testCase.isGroupAssignment {
+			assignment.GroupOptions = &model.GroupOptions{MaxSize: 2}
+		}
+
+		actual, err := getGroupLateDayLimits(assignment, users, testCase.lateDays)
+		if err != nil {
+			test.Errorf("Case %d: Failed to get group late day limits: '%v'.", i, err)
+			continue
+		}
+
+		if !reflect.DeepEqual(testCase.expected, actual) {
+			test.Errorf("Case %d: Unexpected limits. Expected: '%v', Actual: '%v'.", i, testCase.expected, actual)
+			continue
+		}
+	}
+}
+
+func TestApplyBaselinePolicyExtensions(test *testing.T) {
+	assignment := db.MustGetTestAssignment()
+	policy := &model.LateGradingPolicy{Type: model.BaselinePolicy}
+
+	dayMSecs := int64(24 * 60 * 60 * 1000)
+	dueDate := timestamp.FromMSecs(10 * dayMSecs)
+	newDueDate := timestamp.FromMSecs(20 * dayMSecs)
+
+	users := map[string]*model.CourseUser{
+		"a@test.edulinq.org": &model.CourseUser{Email: "a@test.edulinq.org"},
+		"b@test.edulinq.org": &model.CourseUser{Email: "b@test.edulinq.org"},
+		"c@test.edulinq.org": &model.CourseUser{Email: "c@test.edulinq.org"},
+		"d@test.edulinq.org": &model.CourseUser{Email: "d@test.edulinq.org"},
+	}
+
+	extensions := map[string]*model.AssignmentExtension{
+		"b@test.edulinq.org": &model.AssignmentExtension{DueDate: &newDueDate},
+		"c@test.edulinq.org": &model.AssignmentExtension{ExtraTime: &util.DurationSpec{Days: 2}},
+		"d@test.edulinq.org": &model.AssignmentExtension{ExtraAttempts: 2},
+	}
+
+	// Every user submits three days late.
+	submissionTime := timestamp.FromMSecs(13 * dayMSecs)
+
+	scores := make(map[string]*model.ScoringInfo)
+	for email := range users {
+		scores[email] = &model.ScoringInfo{SubmissionTime: submissionTime}
+	}
+
+	expected := map[string]int{
+		"a@test.edulinq.org": 3,
+		"b@test.edulinq.org": 0,
+		"c@test.edulinq.org": 1,
+		"d@test.edulinq.org": 3,
+	}
+
+	applyBaselinePolicy(assignment, policy, users, scores, dueDate, extensions)
+
+	for email, expectedDays := range expected {
+		if expectedDays != scores[email].NumDaysLate {
+			test.Errorf("User '%s': Unexpected number of late days. Expected: %d, Actual: %d.", email, expectedDays, scores[email].NumDaysLate)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package stats
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/edulinq/autograder/internal/config"
@@ -146,6 +147,26 @@ func validateType(metricType MetricType) error {
 	return nil
 }
 
+// Get all the known metric types (sorted).
+func GetMetricTypes() []MetricType {
+	metricTypes := make([]MetricType, 0, len(knownMetricTypes))
+	for metricType, known := range knownMetricTypes {
+		if known {
+			metricTypes = append(metricTypes, metricType)
+		}
+	}
+
+	slices.Sort(metricTypes)
+
+	return metricTypes
+}
+
+// Is this metric attributed to the given user.
+func (this *Metric) IsUser(email string) bool {
+	value, ok := this.Attributes[MetricAttributeUserEmail].(string)
+	return ok && (value == email)
+}
+
 func AsyncStoreMetric(metric *Metric) {
 	err := metric.Validate()
 	if err != nil {
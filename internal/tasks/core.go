@@ -96,11 +96,11 @@ func runNextTask() {
 		Value:     float64((timestamp.Now() - startTimestamp).ToMSecs()),
 		Attributes: map[stats.MetricAttribute]any{
 			stats.MetricAttributeTaskType: task.Type,
-			stats.MetricAttributeCourseID: task.CourseID,
 		},
 	}
 
 	// Add optional fields if non-empty.
+	metric.SetCourseID(task.CourseID)
 	metric.SetUserEmail(task.UserEmail)
 	metric.SetAssignmentID(task.AssignmentID)
 
@@ -142,6 +142,10 @@ func runTask(task *model.FullScheduledTask) {
 		err = RunCourseScoringUploadTask(task)
 	case model.TaskTypeCourseUpdate:
 		err = RunCourseUpdateTask(task)
+	case model.TaskTypeServerTokenCleanup:
+		err = RunServerTokenCleanupTask(task)
+	case model.TaskTypeServerGradingTicketCleanup:
+		err = RunServerGradingTicketCleanupTask(task)
 	case model.TaskTypeTest:
 		err = RunTestTask(task)
 	default:
@@ -0,0 +1,45 @@
+package tasks
+
+import (
+	"github.com/edulinq/autograder/internal/config"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+// Save the tasks that the server runs for itself (as opposed to tasks declared by courses).
+// Server tasks that are no longer configured will be removed.
+func UpsertServerTasks() error {
+	return db.UpsertActiveServerTasks(getServerTasks())
+}
+
+func getServerTasks() []*model.UserTaskInfo {
+	tasks := make([]*model.UserTaskInfo, 0)
+
+	tokenCleanupMinutes := config.TASK_TOKEN_CLEANUP_MINS.Get()
+	if tokenCleanupMinutes > 0 {
+		tasks = append(tasks, &model.UserTaskInfo{
+			Type: model.TaskTypeServerTokenCleanup,
+			When: &util.ScheduledTime{
+				Every: util.DurationSpec{
+					Minutes: int64(tokenCleanupMinutes),
+				},
+			},
+		})
+	}
+
+	// Finished grading tickets are only removed if they have a retention period.
+	gradingTicketCleanupMinutes := config.TASK_GRADING_TICKET_CLEANUP_MINS.Get()
+	if (gradingTicketCleanupMinutes > 0) && (config.GRADING_QUEUE_RETENTION_HOURS.Get() > 0) {
+		tasks = append(tasks, &model.UserTaskInfo{
+			Type: model.TaskTypeServerGradingTicketCleanup,
+			When: &util.ScheduledTime{
+				Every: util.DurationSpec{
+					Minutes: int64(gradingTicketCleanupMinutes),
+				},
+			},
+		})
+	}
+
+	return tasks
+}
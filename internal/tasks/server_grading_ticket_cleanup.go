@@ -0,0 +1,33 @@
+package tasks
+
+import (
+	"fmt"
+
+	"github.com/edulinq/autograder/internal/config"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/log"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/timestamp"
+)
+
+// Remove all finished grading tickets that are past their retention period.
+// Queued and running tickets are never removed.
+func RunServerGradingTicketCleanupTask(task *model.FullScheduledTask) error {
+	retentionHours := config.GRADING_QUEUE_RETENTION_HOURS.Get()
+	if retentionHours <= 0 {
+		return nil
+	}
+
+	cutoff := timestamp.Now() - timestamp.FromMSecs(int64(retentionHours)*60*60*1000)
+
+	count, err := db.RemoveDoneGradingTickets(cutoff)
+	if err != nil {
+		return fmt.Errorf("Failed to remove finished grading tickets: '%w'.", err)
+	}
+
+	if count > 0 {
+		log.Info("Removed finished grading tickets.", log.NewAttr("count", count))
+	}
+
+	return nil
+}
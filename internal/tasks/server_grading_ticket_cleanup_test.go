@@ -0,0 +1,75 @@
+package tasks
+
+import (
+	"testing"
+
+	"github.com/edulinq/autograder/internal/config"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/timestamp"
+)
+
+func TestRunServerGradingTicketCleanupTaskBase(test *testing.T) {
+	defer db.ResetForTesting()
+	defer config.GRADING_QUEUE_RETENTION_HOURS.Set(config.GRADING_QUEUE_RETENTION_HOURS.Get())
+
+	config.GRADING_QUEUE_RETENTION_HOURS.Set(1)
+
+	old := timestamp.Now() - timestamp.FromMSecs(2*60*60*1000)
+	recent := timestamp.Now()
+
+	tickets := map[string]*model.GradingTicket{
+		"old": &model.GradingTicket{
+			ID:        "old",
+			User:      "course-student@test.edulinq.org",
+			Status:    model.GradingTicketStatusDone,
+			QueueTime: old,
+			EndTime:   &old,
+		},
+		"recent": &model.GradingTicket{
+			ID:        "recent",
+			User:      "course-student@test.edulinq.org",
+			Status:    model.GradingTicketStatusDone,
+			QueueTime: old,
+			EndTime:   &recent,
+		},
+		"queued": &model.GradingTicket{
+			ID:        "queued",
+			User:      "course-student@test.edulinq.org",
+			Status:    model.GradingTicketStatusQueued,
+			QueueTime: old,
+		},
+	}
+
+	err := db.UpsertGradingTickets(tickets)
+	if err != nil {
+		test.Fatalf("Failed to upsert tickets: '%v'.", err)
+	}
+
+	err = RunServerGradingTicketCleanupTask(&model.FullScheduledTask{})
+	if err != nil {
+		test.Fatalf("Got an unexpected error running task: '%v'.", err)
+	}
+
+	testCases := []struct {
+		ticketID string
+		exists   bool
+	}{
+		{"old", false},
+		{"recent", true},
+		{"queued", true},
+	}
+
+	for i, testCase := range testCases {
+		ticket, err := db.GetGradingTicket(testCase.ticketID)
+		if err != nil {
+			test.Errorf("Case %d: Failed to get ticket: '%v'.", i, err)
+			continue
+		}
+
+		if testCase.exists != (ticket != nil) {
+			test.Errorf("Case %d: Unexpected ticket existence. Expected: %v, Actual: %v.", i, testCase.exists, (ticket != nil))
+			continue
+		}
+	}
+}
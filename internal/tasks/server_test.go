@@ -0,0 +1,65 @@
+package tasks
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/edulinq/autograder/internal/config"
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+)
+
+func TestUpsertServerTasks(test *testing.T) {
+	defer db.ResetForTesting()
+
+	err := UpsertServerTasks()
+	if err != nil {
+		test.Fatalf("Failed to upsert server tasks: '%v'.", err)
+	}
+
+	serverTasks := getActiveServerTasks(test)
+	if len(serverTasks) != 2 {
+		test.Fatalf("Unexpected number of server tasks. Expected: 2, Actual: %d.", len(serverTasks))
+	}
+
+	expectedTypes := []model.TaskType{model.TaskTypeServerTokenCleanup, model.TaskTypeServerGradingTicketCleanup}
+	for _, expectedType := range expectedTypes {
+		if !slices.ContainsFunc(serverTasks, func(task *model.FullScheduledTask) bool { return task.Type == expectedType }) {
+			test.Fatalf("Could not find server task '%s'.", expectedType)
+		}
+	}
+
+	// Disabling the cleanups should remove the tasks.
+	defer config.TASK_TOKEN_CLEANUP_MINS.Set(config.TASK_TOKEN_CLEANUP_MINS.Get())
+	config.TASK_TOKEN_CLEANUP_MINS.Set(0)
+
+	// Grading tickets without a retention period are never cleaned up.
+	defer config.GRADING_QUEUE_RETENTION_HOURS.Set(config.GRADING_QUEUE_RETENTION_HOURS.Get())
+	config.GRADING_QUEUE_RETENTION_HOURS.Set(0)
+
+	err = UpsertServerTasks()
+	if err != nil {
+		test.Fatalf("Failed to upsert server tasks after disabling: '%v'.", err)
+	}
+
+	serverTasks = getActiveServerTasks(test)
+	if len(serverTasks) != 0 {
+		test.Fatalf("Unexpected number of server tasks after disabling. Expected: 0, Actual: %d.", len(serverTasks))
+	}
+}
+
+func getActiveServerTasks(test *testing.T) []*model.FullScheduledTask {
+	tasks, err := db.GetActiveTasks()
+	if err != nil {
+		test.Fatalf("Failed to get active tasks: '%v'.", err)
+	}
+
+	serverTasks := make([]*model.FullScheduledTask, 0)
+	for _, task := range tasks {
+		if task.Source == model.TaskSourceServer {
+			serverTasks = append(serverTasks, task)
+		}
+	}
+
+	return serverTasks
+}
@@ -0,0 +1,45 @@
+package tasks
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/log"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/timestamp"
+)
+
+// Remove all expired tokens from all users.
+func RunServerTokenCleanupTask(task *model.FullScheduledTask) error {
+	users, err := db.GetServerUsers()
+	if err != nil {
+		return fmt.Errorf("Failed to get users: '%w'.", err)
+	}
+
+	now := timestamp.Now()
+	count := 0
+
+	var errs error = nil
+	for _, user := range users {
+		for _, token := range user.Tokens {
+			if !token.IsExpired(now) {
+				continue
+			}
+
+			_, err = db.DeleteUserToken(user.Email, token.ID)
+			if err != nil {
+				errs = errors.Join(errs, fmt.Errorf("Failed to delete expired token '%s' for user '%s': '%w'.", token.ID, user.Email, err))
+				continue
+			}
+
+			count++
+		}
+	}
+
+	if count > 0 {
+		log.Info("Removed expired tokens.", log.NewAttr("count", count))
+	}
+
+	return errs
+}
@@ -0,0 +1,53 @@
+package tasks
+
+import (
+	"testing"
+
+	"github.com/edulinq/autograder/internal/db"
+	"github.com/edulinq/autograder/internal/model"
+	"github.com/edulinq/autograder/internal/timestamp"
+)
+
+func TestRunServerTokenCleanupTaskBase(test *testing.T) {
+	defer db.ResetForTesting()
+
+	email := "course-student@test.edulinq.org"
+	user := db.MustGetServerUser(email)
+	initialTokenCount := len(user.Tokens)
+
+	past := timestamp.Now() - 1
+	future := timestamp.Now() + timestamp.FromMSecs(60*60*1000)
+
+	expiredToken, _, err := user.CreateRandomToken("expired", model.TokenSourceUser)
+	if err != nil {
+		test.Fatalf("Failed to create expired token: '%v'.", err)
+	}
+
+	expiredToken.ExpirationTime = &past
+
+	validToken, _, err := user.CreateRandomToken("valid", model.TokenSourceUser)
+	if err != nil {
+		test.Fatalf("Failed to create valid token: '%v'.", err)
+	}
+
+	validToken.ExpirationTime = &future
+
+	db.MustUpsertUser(user)
+
+	err = RunServerTokenCleanupTask(&model.FullScheduledTask{})
+	if err != nil {
+		test.Fatalf("Got an unexpected error running task: '%v'.", err)
+	}
+
+	user = db.MustGetServerUser(email)
+
+	if len(user.Tokens) != (initialTokenCount + 1) {
+		test.Fatalf("Unexpected token count. Expected: %d, Actual: %d.", (initialTokenCount + 1), len(user.Tokens))
+	}
+
+	for _, token := range user.Tokens {
+		if token.ID == expiredToken.ID {
+			test.Fatalf("Expired token was not removed.")
+		}
+	}
+}
@@ -55,6 +55,11 @@ func Now() Timestamp {
 	return FromGoTime(time.Now())
 }
 
+func NowPointer() *Timestamp {
+	value := Now()
+	return &value
+}
+
 func Zero() Timestamp {
 	return Timestamp(0)
 }
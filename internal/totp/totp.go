@@ -0,0 +1,143 @@
+package totp
+
+// Time-based one-time passwords (TOTP), as used by most authenticator apps.
+// Only the defaults that all authenticator apps support are used (SHA-1, 6 digits, 30 second periods).
+// See: https://datatracker.ietf.org/doc/html/rfc6238
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/edulinq/autograder/internal/timestamp"
+	"github.com/edulinq/autograder/internal/util"
+)
+
+const (
+	DIGITS      = 6
+	PERIOD_SECS = 30
+
+	SECRET_LEN_BYTES = 20
+
+	// The number of periods before/after the current one that a code is still accepted for
+	// (to allow for clock drift and slow typists).
+	ALLOWED_SKEW_PERIODS = 1
+)
+
+var secretEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// Generate a new random (base32-encoded) secret.
+func NewSecret() (string, error) {
+	bytes, err := util.RandBytes(SECRET_LEN_BYTES)
+	if err != nil {
+		return "", fmt.Errorf("Failed to generate random bytes for TOTP secret: '%w'.", err)
+	}
+
+	return secretEncoding.EncodeToString(bytes), nil
+}
+
+// Get the code for a secret at the given time.
+func GenerateCode(secret string, now timestamp.Timestamp) (string, error) {
+	key, err := decodeSecret(secret)
+	if err != nil {
+		return "", err
+	}
+
+	return generateCode(key, getPeriod(now), DIGITS), nil
+}
+
+// Check if a code is valid for a secret at the given time.
+func ValidateCode(secret string, code string, now timestamp.Timestamp) (bool, error) {
+	valid, _, err := ValidateCodeTimeStep(secret, code, now)
+	return valid, err
+}
+
+// Check if a code is valid for a secret at the given time,
+// and return the time step (period) that the code was generated for.
+// Callers can remember the time step of the last accepted code to reject codes that are reused.
+func ValidateCodeTimeStep(secret string, code string, now timestamp.Timestamp) (bool, uint64, error) {
+	key, err := decodeSecret(secret)
+	if err != nil {
+		return false, 0, err
+	}
+
+	code = strings.ReplaceAll(strings.TrimSpace(code), " ", "")
+	if len(code) != DIGITS {
+		return false, 0, nil
+	}
+
+	period := getPeriod(now)
+	match := false
+	var matchPeriod uint64 = 0
+
+	// Check all the periods so we are not vulnerable to timing attacks.
+	for offset := -ALLOWED_SKEW_PERIODS; offset <= ALLOWED_SKEW_PERIODS; offset++ {
+		currentPeriod := uint64(int64(period) + int64(offset))
+		expected := generateCode(key, currentPeriod, DIGITS)
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1 {
+			match = true
+			matchPeriod = currentPeriod
+		}
+	}
+
+	return match, matchPeriod, nil
+}
+
+// Get a URI that authenticator apps can use to add the secret (usually through a QR code).
+// See: https://github.com/google/google-authenticator/wiki/Key-Uri-Format
+func GetURI(secret string, issuer string, account string) string {
+	label := url.PathEscape(issuer + ":" + account)
+
+	query := url.Values{}
+	query.Set("secret", secret)
+	query.Set("issuer", issuer)
+	query.Set("algorithm", "SHA1")
+	query.Set("digits", fmt.Sprintf("%d", DIGITS))
+	query.Set("period", fmt.Sprintf("%d", PERIOD_SECS))
+
+	return fmt.Sprintf("otpauth://totp/%s?%s", label, query.Encode())
+}
+
+func decodeSecret(secret string) ([]byte, error) {
+	secret = strings.ToUpper(strings.TrimRight(strings.TrimSpace(secret), "="))
+
+	key, err := secretEncoding.DecodeString(secret)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to decode TOTP secret: '%w'.", err)
+	}
+
+	if len(key) == 0 {
+		return nil, fmt.Errorf("TOTP secret is empty.")
+	}
+
+	return key, nil
+}
+
+func getPeriod(now timestamp.Timestamp) uint64 {
+	return uint64(now.ToMSecs() / 1000 / PERIOD_SECS)
+}
+
+// See: https://datatracker.ietf.org/doc/html/rfc4226#section-5.3
+func generateCode(key []byte, period uint64, digits int) string {
+	message := make([]byte, 8)
+	binary.BigEndian.PutUint64(message, period)
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(message)
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	modulus := uint32(1)
+	for i := 0; i < digits; i++ {
+		modulus *= 10
+	}
+
+	return fmt.Sprintf("%0*d", digits, value%modulus)
+}
@@ -0,0 +1,173 @@
+package totp
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/edulinq/autograder/internal/timestamp"
+)
+
+// The SHA-1 secret from RFC 6238 ("12345678901234567890").
+const RFC_SECRET = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+// See: https://datatracker.ietf.org/doc/html/rfc6238#appendix-B
+func TestGenerateCodeRFC(test *testing.T) {
+	testCases := []struct {
+		secs     int64
+		expected string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+	}
+
+	for i, testCase := range testCases {
+		code, err := GenerateCode(RFC_SECRET, timestamp.FromMSecs(testCase.secs*1000))
+		if err != nil {
+			test.Errorf("Case %d: Failed to generate code: '%v'.", i, err)
+			continue
+		}
+
+		if testCase.expected != code {
+			test.Errorf("Case %d: Unexpected code. Expected: '%s', Actual: '%s'.", i, testCase.expected, code)
+		}
+	}
+}
+
+func TestValidateCode(test *testing.T) {
+	now := timestamp.FromMSecs(1111111111 * 1000)
+
+	testCases := []struct {
+		code     string
+		expected bool
+	}{
+		{"050471", true},
+		{" 050 471 ", true},
+
+		// Previous and next periods.
+		{"081804", true},
+		{mustGenerateCode(test, now+timestamp.FromMSecs(PERIOD_SECS*1000)), true},
+
+		// Too far away.
+		{mustGenerateCode(test, now-timestamp.FromMSecs(2*PERIOD_SECS*1000)), false},
+		{mustGenerateCode(test, now+timestamp.FromMSecs(2*PERIOD_SECS*1000)), false},
+
+		{"", false},
+		{"000000", false},
+		{"05047", false},
+		{"0504711", false},
+	}
+
+	for i, testCase := range testCases {
+		valid, err := ValidateCode(RFC_SECRET, testCase.code, now)
+		if err != nil {
+			test.Errorf("Case %d: Failed to validate code: '%v'.", i, err)
+			continue
+		}
+
+		if testCase.expected != valid {
+			test.Errorf("Case %d: Unexpected result for code '%s'. Expected: '%v', Actual: '%v'.", i, testCase.code, testCase.expected, valid)
+		}
+	}
+}
+
+func TestValidateCodeTimeStep(test *testing.T) {
+	now := timestamp.FromMSecs(1111111111 * 1000)
+	period := getPeriod(now)
+
+	testCases := []struct {
+		code             string
+		expectedValid    bool
+		expectedTimeStep uint64
+	}{
+		{"050471", true, period},
+		{"081804", true, period - 1},
+		{mustGenerateCode(test, now+timestamp.FromMSecs(PERIOD_SECS*1000)), true, period + 1},
+		{"000000", false, 0},
+	}
+
+	for i, testCase := range testCases {
+		valid, timeStep, err := ValidateCodeTimeStep(RFC_SECRET, testCase.code, now)
+		if err != nil {
+			test.Errorf("Case %d: Failed to validate code: '%v'.", i, err)
+			continue
+		}
+
+		if testCase.expectedValid != valid {
+			test.Errorf("Case %d: Unexpected result for code '%s'. Expected: '%v', Actual: '%v'.", i, testCase.code, testCase.expectedValid, valid)
+		}
+
+		if testCase.expectedTimeStep != timeStep {
+			test.Errorf("Case %d: Unexpected time step for code '%s'. Expected: '%d', Actual: '%d'.", i, testCase.code, testCase.expectedTimeStep, timeStep)
+		}
+	}
+}
+
+func TestNewSecret(test *testing.T) {
+	secret, err := NewSecret()
+	if err != nil {
+		test.Fatalf("Failed to generate secret: '%v'.", err)
+	}
+
+	key, err := decodeSecret(secret)
+	if err != nil {
+		test.Fatalf("Failed to decode new secret: '%v'.", err)
+	}
+
+	if len(key) != SECRET_LEN_BYTES {
+		test.Fatalf("Unexpected secret length. Expected: %d, Actual: %d.", SECRET_LEN_BYTES, len(key))
+	}
+
+	now := timestamp.Now()
+
+	code, err := GenerateCode(secret, now)
+	if err != nil {
+		test.Fatalf("Failed to generate code: '%v'.", err)
+	}
+
+	valid, err := ValidateCode(secret, code, now)
+	if err != nil {
+		test.Fatalf("Failed to validate code: '%v'.", err)
+	}
+
+	if !valid {
+		test.Fatalf("Code for new secret is not valid.")
+	}
+}
+
+func TestBadSecret(test *testing.T) {
+	secrets := []string{"", "!!!", "ABC1"}
+
+	for i, secret := range secrets {
+		_, err := ValidateCode(secret, "123456", timestamp.Now())
+		if err == nil {
+			test.Errorf("Case %d: Did not get an error on bad secret '%s'.", i, secret)
+		}
+	}
+}
+
+func TestGetURI(test *testing.T) {
+	uri := GetURI(RFC_SECRET, "autograder", "course-admin@test.edulinq.org")
+
+	expectedPrefix := "otpauth://totp/autograder:course-admin@test.edulinq.org?"
+	if !strings.HasPrefix(uri, expectedPrefix) {
+		test.Fatalf("Unexpected URI prefix. Expected: '%s', Actual: '%s'.", expectedPrefix, uri)
+	}
+
+	for _, part := range []string{"secret=" + RFC_SECRET, "issuer=autograder", "digits=6", "period=30"} {
+		if !strings.Contains(uri, part) {
+			test.Errorf("URI '%s' does not contain '%s'.", uri, part)
+		}
+	}
+}
+
+func mustGenerateCode(test *testing.T, now timestamp.Timestamp) string {
+	code, err := GenerateCode(RFC_SECRET, now)
+	if err != nil {
+		test.Fatalf("Failed to generate code: '%v'.", err)
+	}
+
+	return code
+}
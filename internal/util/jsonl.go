@@ -104,6 +104,63 @@ func FilterJSONLFile[T any](path string, emptyRecord T, matchFunc func(record *T
 	return records, nil
 }
 
+// Get the last record in a JSONL file, without reading the whole file (the file is read backwards from the end).
+// Returns (nil, nil) if the file does not exist or has no records.
+func ReadLastJSONLRecord[T any](path string, emptyRecord T) (*T, error) {
+	if !PathExists(path) {
+		return nil, nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to open JSONL file '%s': '%w'.", path, err)
+	}
+	defer file.Close()
+
+	stat, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to stat JSONL file '%s': '%w'.", path, err)
+	}
+
+	const chunkSize int64 = 4096
+
+	// The bytes read so far (from offset to the end of the file).
+	var data []byte
+	offset := stat.Size()
+
+	for {
+		// Look for the start of the last non-empty line in what has been read.
+		trimmed := strings.TrimRight(string(data), " \t\r\n")
+		start := strings.LastIndexByte(trimmed, '\n')
+
+		if (start >= 0) || (offset == 0) {
+			line := trimmed[start+1:]
+			if line == "" {
+				return nil, nil
+			}
+
+			var record *T = reflect.New(reflect.TypeOf(emptyRecord)).Interface().(*T)
+			err = JSONFromString(line, record)
+			if err != nil {
+				return nil, fmt.Errorf("Failed to convert last line from JSONL file '%s' to JSON: '%w'.", path, err)
+			}
+
+			return record, nil
+		}
+
+		readSize := min(chunkSize, offset)
+		offset -= readSize
+
+		chunk := make([]byte, readSize)
+		_, err = file.ReadAt(chunk, offset)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to read JSONL file '%s': '%w'.", path, err)
+		}
+
+		data = append(chunk, data...)
+	}
+}
+
 // Will only return a nil content on error or EOF.
 func readline(reader *bufio.Reader) ([]byte, error) {
 	var isPrefix bool = true
@@ -136,11 +193,37 @@ func readline(reader *bufio.Reader) ([]byte, error) {
 // On most errors, the old file will remain the same.
 // The exception is if there is an error on the file move operation.
 func RemoveEntriesJSONLFile[T any](path string, emptyRecord T, shouldRemoveFunc func(record *T) bool) error {
+	return rewriteJSONLFile(path, emptyRecord, func(record *T, line string) (string, error) {
+		if shouldRemoveFunc(record) {
+			return "", nil
+		}
+
+		return line, nil
+	})
+}
+
+// Update entries in the file in place.
+// The update function may modify the passed in record, and should return true if it did so.
+// This works the same way as RemoveEntriesJSONLFile() (streaming through a temp file),
+// and has the same requirements and error semantics.
+func UpdateEntriesJSONLFile[T any](path string, emptyRecord T, updateFunc func(record *T) bool) error {
+	return rewriteJSONLFile(path, emptyRecord, func(record *T, line string) (string, error) {
+		if !updateFunc(record) {
+			return line, nil
+		}
+
+		return ToJSON(record)
+	})
+}
+
+// Stream through a JSONL file, writing each line returned by the rewrite function to a temp file
+// (empty lines are skipped) that will then be moved over the original file.
+func rewriteJSONLFile[T any](path string, emptyRecord T, rewriteFunc func(record *T, line string) (string, error)) error {
 	if !PathExists(path) {
 		return nil
 	}
 
-	tempDir, err := MkDirTemp("jsonl-remove-entries-")
+	tempDir, err := MkDirTemp("jsonl-rewrite-entries-")
 	if err != nil {
 		return fmt.Errorf("Failed to create temp dir: '%w'.", err)
 	}
@@ -160,11 +243,17 @@ func RemoveEntriesJSONLFile[T any](path string, emptyRecord T, shouldRemoveFunc
 			return
 		}
 
-		if shouldRemoveFunc(record) {
+		line, err := rewriteFunc(record, line)
+		if err != nil {
+			writeError = fmt.Errorf("Failed to rewrite record %d: '%w'.", index, err)
+			return
+		}
+
+		if line == "" {
 			return
 		}
 
-		_, err := file.WriteString(line + "\n")
+		_, err = file.WriteString(line + "\n")
 		if err != nil {
 			writeError = fmt.Errorf("Failed to write to temp file: '%w'.", err)
 		}
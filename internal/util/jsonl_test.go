@@ -0,0 +1,65 @@
+package util
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type jsonlTestRecord struct {
+	Index int    `json:"index"`
+	Text  string `json:"text"`
+}
+
+func TestReadLastJSONLRecord(test *testing.T) {
+	tempDir := MustMkDirTemp("jsonl-test-")
+	defer RemoveDirent(tempDir)
+
+	longText := strings.Repeat("a", 10000)
+
+	testCases := []struct {
+		contents string
+		expected *jsonlTestRecord
+	}{
+		{"", nil},
+		{"\n\n", nil},
+		{`{"index": 1}`, &jsonlTestRecord{Index: 1}},
+		{`{"index": 1}` + "\n", &jsonlTestRecord{Index: 1}},
+		{`{"index": 1}` + "\n" + `{"index": 2}` + "\n", &jsonlTestRecord{Index: 2}},
+		{`{"index": 1}` + "\n" + `{"index": 2}` + "\n\n  \n", &jsonlTestRecord{Index: 2}},
+
+		// Lines longer than a single read.
+		{`{"index": 1}` + "\n" + `{"index": 2, "text": "` + longText + `"}` + "\n", &jsonlTestRecord{Index: 2, Text: longText}},
+		{`{"index": 1, "text": "` + longText + `"}` + "\n" + `{"index": 2}` + "\n", &jsonlTestRecord{Index: 2}},
+	}
+
+	for i, testCase := range testCases {
+		path := filepath.Join(tempDir, "test.jsonl")
+
+		err := WriteFile(testCase.contents, path)
+		if err != nil {
+			test.Fatalf("Case %d: Failed to write file: '%v'.", i, err)
+		}
+
+		actual, err := ReadLastJSONLRecord(path, jsonlTestRecord{})
+		if err != nil {
+			test.Errorf("Case %d: Failed to read last record: '%v'.", i, err)
+			continue
+		}
+
+		if MustToJSON(testCase.expected) != MustToJSON(actual) {
+			test.Errorf("Case %d: Unexpected record. Expected: '%s', Actual: '%s'.", i, MustToJSON(testCase.expected), MustToJSON(actual))
+			continue
+		}
+	}
+
+	// Missing files have no records.
+	actual, err := ReadLastJSONLRecord(filepath.Join(tempDir, "missing.jsonl"), jsonlTestRecord{})
+	if err != nil {
+		test.Fatalf("Failed to read missing file: '%v'.", err)
+	}
+
+	if actual != nil {
+		test.Fatalf("Got a record from a missing file: '%s'.", MustToJSON(actual))
+	}
+}
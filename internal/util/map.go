@@ -0,0 +1,18 @@
+package util
+
+import (
+	"cmp"
+	"slices"
+)
+
+// Get the keys of a map in sorted order.
+func GetSortedKeys[K cmp.Ordered, V any](data map[K]V) []K {
+	keys := make([]K, 0, len(data))
+	for key := range data {
+		keys = append(keys, key)
+	}
+
+	slices.Sort(keys)
+
+	return keys
+}
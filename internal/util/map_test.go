@@ -0,0 +1,25 @@
+package util
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGetSortedKeys(test *testing.T) {
+	testCases := []struct {
+		input    map[string]int
+		expected []string
+	}{
+		{nil, []string{}},
+		{map[string]int{}, []string{}},
+		{map[string]int{"a": 1}, []string{"a"}},
+		{map[string]int{"c": 1, "a": 2, "b": 3}, []string{"a", "b", "c"}},
+	}
+
+	for i, testCase := range testCases {
+		actual := GetSortedKeys(testCase.input)
+		if !reflect.DeepEqual(testCase.expected, actual) {
+			test.Errorf("Case %d: Unexpected keys. Expected: '%v', Actual: '%v'.", i, testCase.expected, actual)
+		}
+	}
+}